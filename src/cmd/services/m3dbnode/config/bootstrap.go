@@ -56,6 +56,9 @@ type BootstrapConfiguration struct {
 	// Commitlog bootstrapper configuration.
 	Commitlog *BootstrapCommitlogConfiguration `yaml:"commitlog"`
 
+	// Peers bootstrapper configuration.
+	Peers *BootstrapPeersConfiguration `yaml:"peers"`
+
 	// CacheSeriesMetadata determines whether individual bootstrappers cache
 	// series metadata across all calls (namespaces / shards / blocks).
 	CacheSeriesMetadata *bool `yaml:"cacheSeriesMetadata"`
@@ -86,6 +89,21 @@ type BootstrapCommitlogConfiguration struct {
 	ReturnUnfulfilledForCorruptCommitlogFiles bool `yaml:"returnUnfulfilledForCorruptCommitlogFiles"`
 }
 
+// BootstrapPeersConfiguration specifies config for the peers bootstrapper.
+type BootstrapPeersConfiguration struct {
+	// StreamThroughputLimitMbps limits the throughput of blocks streamed from
+	// peers by this node, zero disables the limit.
+	StreamThroughputLimitMbps float64 `yaml:"streamThroughputLimitMbps" validate:"min=0.0"`
+
+	// ServeThroughputLimitMbps limits the throughput of blocks served by this
+	// node to peers that are bootstrapping, zero disables the limit.
+	ServeThroughputLimitMbps float64 `yaml:"serveThroughputLimitMbps" validate:"min=0.0"`
+
+	// StreamConcurrency is the number of concurrent block batch requests made
+	// to peers when streaming blocks, zero uses the client default.
+	StreamConcurrency int `yaml:"streamConcurrency" validate:"min=0"`
+}
+
 // New creates a bootstrap process based on the bootstrap configuration.
 func (bsc BootstrapConfiguration) New(
 	opts storage.Options,
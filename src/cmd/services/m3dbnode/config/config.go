@@ -32,6 +32,7 @@ import (
 	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3x/config/hostid"
 	"github.com/m3db/m3x/instrument"
 	xlog "github.com/m3db/m3x/log"
@@ -120,6 +121,10 @@ type DBConfiguration struct {
 	// The repair policy for repairing in-memory data.
 	Repair RepairPolicy `yaml:"repair"`
 
+	// The downsample configuration for rolling up flushed blocks into
+	// lower resolution namespaces.
+	Downsample *DownsampleConfiguration `yaml:"downsample"`
+
 	// The pooling policy.
 	PoolingPolicy PoolingPolicy `yaml:"pooling"`
 
@@ -140,6 +145,11 @@ type IndexConfiguration struct {
 	// important to prevent index queries from overloading the database entirely
 	// as they are very CPU-intensive (regex and FST matching.)
 	MaxQueryIDsConcurrency int `yaml:"maxQueryIDsConcurrency" validate:"min=0"`
+
+	// PostingsListCacheSizeBytes is the maximum size of the cache of postings
+	// lists matched by term and regexp queries against FST segments, a value
+	// of zero disables the cache.
+	PostingsListCacheSizeBytes int64 `yaml:"postingsListCacheSizeBytes" validate:"min=0"`
 }
 
 // TickConfiguration is the tick configuration for background processing of
@@ -195,6 +205,14 @@ type CommitLogPolicy struct {
 
 	// The commit log block size.
 	BlockSize time.Duration `yaml:"blockSize" validate:"nonzero"`
+
+	// The compression applied to commit log chunks, commit logs written with
+	// compression cannot be read by versions without support for it.
+	Compression *compression.Type `yaml:"compression"`
+
+	// The group commit policy, if set writes are only acknowledged once they
+	// have been fsync'd to disk together with any concurrent writes.
+	GroupCommit *CommitLogGroupCommitPolicy `yaml:"groupCommit"`
 }
 
 // CalculationType is a type of configuration parameter.
@@ -216,6 +234,12 @@ type CommitLogQueuePolicy struct {
 	Size int `yaml:"size" validate:"nonzero"`
 }
 
+// CommitLogGroupCommitPolicy is the commit log group commit policy.
+type CommitLogGroupCommitPolicy struct {
+	// The maximum time a write waits for concurrent writes to be fsync'd with.
+	MaxDelay time.Duration `yaml:"maxDelay" validate:"nonzero"`
+}
+
 // RepairPolicy is the repair policy.
 type RepairPolicy struct {
 	// Enabled or disabled.
@@ -308,6 +308,7 @@ db:
 	expected := `db:
   index:
     maxQueryIDsConcurrency: 0
+    postingsListCacheSizeBytes: 0
   logging:
     file: /var/log/m3dbnode.log
     level: info
@@ -360,10 +361,12 @@ db:
       maxRetries: 3
       forever: null
       jitter: true
+    fetchHedgePercentile: null
     backgroundHealthCheckFailLimit: 4
     backgroundHealthCheckFailThrottleFactor: 0.5
     hashing:
       seed: 42
+    hintedHandoff: null
   gcPercentage: 100
   writeNewSeriesLimitPerSecond: 1048576
   writeNewSeriesBackoffDuration: 2ms
@@ -376,6 +379,7 @@ db:
     fs:
       numProcessorsPerCPU: 0.125
     commitlog: null
+    peers: null
     cacheSeriesMetadata: null
   blockRetrieve: null
   cache:
@@ -391,6 +395,7 @@ db:
     newFileMode: null
     newDirectoryMode: null
     mmap: null
+    tiering: null
   commitlog:
     flushMaxBytes: 524288
     flushEvery: 1s
@@ -399,6 +404,8 @@ db:
       size: 2097152
     queueChannel: null
     blockSize: 10m0s
+    compression: null
+    groupCommit: null
   repair:
     enabled: false
     interval: 2h0m0s
@@ -406,6 +413,7 @@ db:
     jitter: 1h0m0s
     throttle: 2m0s
     checkInterval: 1m0s
+  downsample: null
   pooling:
     blockAllocSize: 16
     type: simple
@@ -0,0 +1,65 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/downsample"
+	"github.com/m3db/m3x/ident"
+)
+
+// DownsampleConfiguration is the configuration for rolling up flushed
+// blocks of namespaces into lower resolution namespaces.
+type DownsampleConfiguration struct {
+	// Rules are the rules describing which namespaces to roll up.
+	Rules []DownsampleRuleConfiguration `yaml:"rules"`
+}
+
+// DownsampleRuleConfiguration is the configuration for rolling up the
+// flushed blocks of a single namespace into another namespace.
+type DownsampleRuleConfiguration struct {
+	// SourceNamespace is the namespace flushed blocks are read from.
+	SourceNamespace string `yaml:"sourceNamespace" validate:"nonzero"`
+
+	// TargetNamespace is the namespace rolled up blocks are written to.
+	TargetNamespace string `yaml:"targetNamespace" validate:"nonzero"`
+
+	// Resolution is the resolution of the rolled up datapoints.
+	Resolution time.Duration `yaml:"resolution" validate:"nonzero"`
+
+	// Aggregation is the aggregation applied to each resolution bucket.
+	Aggregation downsample.AggregationType `yaml:"aggregation" validate:"nonzero"`
+}
+
+// DownsampleRules returns the downsample rules described by the configuration.
+func (c DownsampleConfiguration) DownsampleRules() []downsample.Rule {
+	rules := make([]downsample.Rule, 0, len(c.Rules))
+	for _, rule := range c.Rules {
+		rules = append(rules, downsample.Rule{
+			SourceNamespace: ident.StringID(rule.SourceNamespace),
+			TargetNamespace: ident.StringID(rule.TargetNamespace),
+			Resolution:      rule.Resolution,
+			Aggregation:     rule.Aggregation,
+		})
+	}
+	return rules
+}
@@ -23,6 +23,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 const (
@@ -30,6 +31,9 @@ const (
 	DefaultNewFileMode = os.FileMode(0666)
 	// DefaultNewDirectoryMode is the default new directory mode.
 	DefaultNewDirectoryMode = os.FileMode(0755)
+	// DefaultTieringCacheMaxBytes is the default max size of the cache of
+	// files downloaded from the object store.
+	DefaultTieringCacheMaxBytes = 16 << 30 // 16gb
 )
 
 // DefaultMmapConfiguration is the default mmap configuration.
@@ -75,6 +79,39 @@ type FilesystemConfiguration struct {
 
 	// Mmap is the mmap options which features are primarily platform dependent
 	Mmap *MmapConfiguration `yaml:"mmap"`
+
+	// Tiering is the configuration for moving older data filesets to an
+	// object store, if not set data filesets are kept on local disk.
+	Tiering *FilesystemTieringConfiguration `yaml:"tiering"`
+}
+
+// FilesystemTieringConfiguration is the filesystem tiering configuration.
+type FilesystemTieringConfiguration struct {
+	// After is how long after a block has ended that its data fileset
+	// is moved to the object store
+	After time.Duration `yaml:"after" validate:"nonzero"`
+
+	// Directory is the root directory of the object store, typically a mount
+	// of cheaper storage
+	Directory string `yaml:"directory" validate:"nonzero"`
+
+	// CacheDirectory is the directory that files downloaded from the object
+	// store to be read are cached in, defaults to a directory beneath the
+	// file path prefix
+	CacheDirectory string `yaml:"cacheDirectory"`
+
+	// CacheMaxBytes is the size of the cached files above which the least
+	// recently read files are evicted
+	CacheMaxBytes int64 `yaml:"cacheMaxBytes"`
+}
+
+// CacheMaxBytesOrDefault returns the configured cache max bytes or the
+// default if not set.
+func (c FilesystemTieringConfiguration) CacheMaxBytesOrDefault() int64 {
+	if c.CacheMaxBytes > 0 {
+		return c.CacheMaxBytes
+	}
+	return DefaultTieringCacheMaxBytes
 }
 
 // MmapConfiguration is the mmap configuration.
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	nchannel "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node/channel"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+	xlog "github.com/m3db/m3x/log"
+	xretry "github.com/m3db/m3x/retry"
+
+	"github.com/pborman/uuid"
+	tchannel "github.com/uber/tchannel-go"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+var (
+	optPathPrefix       = flag.String("path-prefix", "/var/lib/m3db", "Path prefix")
+	optNamespace        = flag.String("namespace", "metrics", "Namespace")
+	optArchiveDir       = flag.String("archive-dir", "", "Archive directory, must be empty or not exist")
+	optObjectStoreDir   = flag.String("object-store-dir", "", "Object store directory of tiered filesets [optional]")
+	optNodeTChannelAddr = flag.String("node-tchannel-addr", "127.0.0.1:9000", "TChannel address of the node to snapshot")
+	optSnapshotTimeout  = flag.Duration("snapshot-timeout", 10*time.Minute, "Timeout of each attempt to snapshot the node")
+	optSnapshotRetries  = flag.Int("snapshot-retries", 5, "Number of times to retry snapshotting the node")
+)
+
+func main() {
+	flag.Parse()
+	if *optPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optArchiveDir == "" ||
+		*optNodeTChannelAddr == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	log := xlog.NewLogger(os.Stderr)
+	opts := fs.NewOptions().SetFilePathPrefix(*optPathPrefix)
+	if *optObjectStoreDir != "" {
+		opts = opts.SetObjectStore(fs.NewDirectoryObjectStore(*optObjectStoreDir))
+	}
+
+	channel, err := tchannel.NewChannel("Client", nil)
+	if err != nil {
+		log.Fatalf("could not create new tchannel channel: %v", err)
+	}
+	endpoint := &thrift.ClientOptions{HostPort: *optNodeTChannelAddr}
+	thriftClient := thrift.NewClient(channel, nchannel.ChannelName, endpoint)
+	client := rpc.NewTChanNodeClient(thriftClient)
+
+	// The snapshot fails while the node is flushing or snapshotting so retry
+	// until the node is idle.
+	var (
+		snapshotID uuid.UUID
+		retrier    = xretry.NewRetrier(xretry.NewOptions().
+				SetBackoffFactor(2).
+				SetMaxRetries(*optSnapshotRetries).
+				SetInitialBackoff(10 * time.Second).
+				SetJitter(true))
+	)
+	err = retrier.Attempt(func() error {
+		tctx, _ := thrift.NewContext(*optSnapshotTimeout)
+		result, err := client.Snapshot(tctx, rpc.NewNodeSnapshotRequest())
+		if err != nil {
+			log.Warnf("unable to snapshot node %s: %v", *optNodeTChannelAddr, err)
+			return err
+		}
+		snapshotID = uuid.Parse(result.SnapshotID)
+		if snapshotID == nil {
+			return xerrors.NewNonRetryableError(fmt.Errorf(
+				"invalid snapshot ID: %s", result.SnapshotID))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("unable to snapshot node: %v", err)
+	}
+
+	log.Infof("backing up snapshot %s of namespace %s from %s to %s",
+		snapshotID.String(), *optNamespace, *optPathPrefix, *optArchiveDir)
+
+	manifest, err := backup.NewBackuper(opts).
+		Backup(ident.StringID(*optNamespace), snapshotID, *optArchiveDir)
+	if err != nil {
+		log.Fatalf("unable to backup: %v", err)
+	}
+
+	log.Infof("successfully backed up %d shards", len(manifest.Shards))
+}
@@ -101,6 +101,10 @@ func main() {
 		log.Fatalf("unable to open reader: %v", err)
 	}
 
+	// Compressed volumes are decompressed transparently by the reader.
+	log.Infof("reading volume with data compression: %s",
+		reader.Status().Compression.String())
+
 	for {
 		id, _, data, _, err := reader.Read()
 		if err == io.EOF {
@@ -129,4 +133,10 @@ func main() {
 		data.DecRef()
 		data.Finalize()
 	}
+
+	// NB: All data has been read so the digest of the data file can be
+	// validated, this also covers the compressed frames of the volume.
+	if err := reader.Validate(); err != nil {
+		log.Fatalf("unable to validate volume: %v", err)
+	}
 }
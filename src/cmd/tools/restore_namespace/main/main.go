@@ -0,0 +1,62 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3x/ident"
+	xlog "github.com/m3db/m3x/log"
+)
+
+var (
+	optArchiveDir = flag.String("archive-dir", "", "Archive directory")
+	optPathPrefix = flag.String("path-prefix", "/var/lib/m3db", "Path prefix")
+	optNamespace  = flag.String("namespace", "", "Namespace to restore into, defaults to the backed up namespace [optional]")
+)
+
+func main() {
+	flag.Parse()
+	if *optArchiveDir == "" ||
+		*optPathPrefix == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	log := xlog.NewLogger(os.Stderr)
+	opts := fs.NewOptions().SetFilePathPrefix(*optPathPrefix)
+
+	var namespace ident.ID
+	if *optNamespace != "" {
+		namespace = ident.StringID(*optNamespace)
+	}
+
+	manifest, err := backup.NewRestorer(opts).Restore(*optArchiveDir, namespace)
+	if err != nil {
+		log.Fatalf("unable to restore: %v", err)
+	}
+
+	log.Infof("successfully restored %d shards of namespace %s to %s",
+		len(manifest.Shards), manifest.Namespace, *optPathPrefix)
+}
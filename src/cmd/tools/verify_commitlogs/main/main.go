@@ -228,6 +228,31 @@ func main() {
 		SetReadConcurrency(*readConcurrency).
 		SetBytesPool(bytesPool)
 
+	// NB: both chunk format versions are read by the bootstrapper, the format
+	// of each commit log is logged to help verify mixed version directories.
+	files, corruptFiles, err := commitlog.Files(commitLogOpts)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	for _, corruptFile := range corruptFiles {
+		log.WithFields(
+			xlog.NewField("filePath", corruptFile.Path()),
+			xlog.NewField("error", corruptFile.Error()),
+		).Errorf("corrupt commit log")
+	}
+	for _, file := range files {
+		format, err := commitlog.ReadFileFormat(file.FilePath)
+		if err != nil {
+			log.Fatalf("could not read format of commit log '%s': %v", file.FilePath, err)
+		}
+		log.WithFields(
+			xlog.NewField("filePath", file.FilePath),
+			xlog.NewField("start", file.Start),
+			xlog.NewField("chunkFormatVersion", int(format.Version)),
+			xlog.NewField("compression", format.Compression.String()),
+		).Infof("commit log")
+	}
+
 	opts := commitlogsrc.NewOptions().
 		SetResultOptions(resultOpts).
 		SetCommitLogOptions(commitLogOpts).
@@ -0,0 +1,361 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/index"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3x/ident"
+)
+
+// validateIndexFileSets validates the checksums of every index fileset volume
+// for the namespace on a host, optionally restricted to a set of block starts.
+// If repair is set then corrupt volumes are quarantined and the index block is
+// rebuilt from the IDs and tags stored in the data filesets of the host.
+func validateIndexFileSets(
+	namespaceStr string,
+	fsOpts fs.Options,
+	hostName string,
+	blocks []int64,
+	repair bool,
+) {
+	nsID := ident.StringID(namespaceStr)
+
+	blocksFilter := make(map[int64]struct{}, len(blocks))
+	for _, block := range blocks {
+		blocksFilter[time.Unix(block, 0).UnixNano()] = struct{}{}
+	}
+
+	// NB: Volumes are listed from their files rather than their info files
+	// so that volumes with a corrupt info file are validated too.
+	volumes, err := fs.IndexFiles(fsOpts.FilePathPrefix(), nsID)
+	if err != nil {
+		log.Fatalf("err listing index filesets: %v\n", err)
+	}
+
+	var (
+		infos     = make(map[indexVolume]index.IndexInfo)
+		blockSize time.Duration
+	)
+	infoFiles := fs.ReadIndexInfoFiles(fsOpts.FilePathPrefix(), nsID,
+		fsOpts.InfoReaderBufferSize())
+	for _, infoFile := range infoFiles {
+		if err := infoFile.Err.Error(); err != nil {
+			// The volume is reported as invalid once it is validated.
+			continue
+		}
+		infos[newIndexVolume(infoFile.ID)] = infoFile.Info
+		blockSize = time.Duration(infoFile.Info.BlockSize)
+	}
+
+	for _, volume := range volumes {
+		id := volume.ID
+		if _, ok := blocksFilter[id.BlockStart.UnixNano()]; len(blocksFilter) > 0 && !ok {
+			continue
+		}
+
+		segments, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+			ReaderOptions: fs.IndexReaderOpenOptions{
+				Identifier:  id,
+				FileSetType: persist.FileSetFlushType,
+			},
+			FilesystemOptions: fsOpts,
+			Validate:          true,
+		})
+		if err == nil {
+			for _, seg := range segments {
+				seg.Close()
+			}
+			log.Printf("host %s index fileset for block: %d, volume: %d is valid\n",
+				hostName, id.BlockStart.Unix(), id.VolumeIndex)
+			continue
+		}
+		if err == fs.ErrCheckpointFileNotFound {
+			log.Printf("host %s index fileset for block: %d, volume: %d is incomplete\n",
+				hostName, id.BlockStart.Unix(), id.VolumeIndex)
+			continue
+		}
+
+		log.Printf("host %s index fileset for block: %d, volume: %d is invalid: %v\n",
+			hostName, id.BlockStart.Unix(), id.VolumeIndex, err)
+		if !repair {
+			continue
+		}
+		if !fs.IsCorruptIndexFileSetError(err) {
+			log.Printf("host %s skipping repair of index fileset for block: %d, volume: %d\n",
+				hostName, id.BlockStart.Unix(), id.VolumeIndex)
+			continue
+		}
+
+		info, ok := infos[newIndexVolume(id)]
+		if !ok {
+			// The info file is corrupt so the block is rebuilt from every
+			// shard of the namespace on the host.
+			if blockSize == 0 {
+				log.Printf("host %s skipping repair of index fileset for block: %d, volume: %d, "+
+					"no valid info file to determine the block size from\n",
+					hostName, id.BlockStart.Unix(), id.VolumeIndex)
+				continue
+			}
+			shards, err := namespaceShards(fsOpts, nsID)
+			if err != nil {
+				log.Fatalf("err listing shards: %v\n", err)
+			}
+			info = index.IndexInfo{
+				BlockStart: id.BlockStart.UnixNano(),
+				BlockSize:  int64(blockSize),
+				Shards:     shards,
+			}
+		}
+
+		// NB: Index only namespaces have no data filesets to rebuild the
+		// block from so their corrupt volumes are never quarantined.
+		hasData, err := hasDataFileSets(fsOpts, nsID, info.Shards)
+		if err != nil {
+			log.Fatalf("err listing data filesets: %v\n", err)
+		}
+		if !hasData {
+			log.Printf("host %s refusing to repair index fileset for block: %d, volume: %d, "+
+				"no data filesets to rebuild it from, namespace may be index only\n",
+				hostName, id.BlockStart.Unix(), id.VolumeIndex)
+			continue
+		}
+
+		err = fs.QuarantineIndexFileSet(fsOpts.FilePathPrefix(), id,
+			fsOpts.NewDirectoryMode())
+		if err != nil {
+			log.Fatalf("err quarantining index fileset: %v\n", err)
+		}
+		log.Printf("host %s quarantined index fileset for block: %d, volume: %d to %s\n",
+			hostName, id.BlockStart.Unix(), id.VolumeIndex,
+			fs.NamespaceIndexQuarantineDirPath(fsOpts.FilePathPrefix(), nsID))
+
+		numDocs, err := rebuildIndexFileSet(fsOpts, nsID, info)
+		if err != nil {
+			log.Fatalf("err rebuilding index fileset: %v\n", err)
+		}
+		log.Printf("host %s rebuilt index fileset for block: %d with %d series\n",
+			hostName, id.BlockStart.Unix(), numDocs)
+	}
+}
+
+type indexVolume struct {
+	blockStart  int64
+	volumeIndex int
+}
+
+func newIndexVolume(id fs.FileSetFileIdentifier) indexVolume {
+	return indexVolume{
+		blockStart:  id.BlockStart.UnixNano(),
+		volumeIndex: id.VolumeIndex,
+	}
+}
+
+// namespaceShards returns the shards of the namespace with a data directory
+// on the host.
+func namespaceShards(fsOpts fs.Options, nsID ident.ID) ([]uint32, error) {
+	dirs, err := ioutil.ReadDir(fs.NamespaceDataDirPath(fsOpts.FilePathPrefix(), nsID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var shards []uint32
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		shard, err := strconv.ParseUint(dir.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		shards = append(shards, uint32(shard))
+	}
+	return shards, nil
+}
+
+func hasDataFileSets(fsOpts fs.Options, nsID ident.ID, shards []uint32) (bool, error) {
+	for _, shard := range shards {
+		files, err := fs.DataFiles(fsOpts.FilePathPrefix(), nsID, shard)
+		if err != nil {
+			return false, err
+		}
+		if len(files) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rebuildIndexFileSet rebuilds an index block from the data filesets of the
+// shards the block covered and persists it as a new index fileset volume.
+func rebuildIndexFileSet(
+	fsOpts fs.Options,
+	nsID ident.ID,
+	info index.IndexInfo,
+) (int64, error) {
+	var (
+		blockStart = time.Unix(0, info.BlockStart)
+		blockSize  = time.Duration(info.BlockSize)
+		blockEnd   = blockStart.Add(blockSize)
+		shards     = make(map[uint32]struct{}, len(info.Shards))
+	)
+
+	seg, err := mem.NewSegment(0, mem.NewOptions())
+	if err != nil {
+		return 0, err
+	}
+	defer seg.Close()
+
+	for _, shard := range info.Shards {
+		shards[shard] = struct{}{}
+
+		dataInfoFiles := fs.ReadInfoFiles(fsOpts.FilePathPrefix(), nsID, shard,
+			fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions())
+		for _, dataInfoFile := range dataInfoFiles {
+			if err := dataInfoFile.Err.Error(); err != nil {
+				return 0, fmt.Errorf("unable to read data info file %s: %v",
+					dataInfoFile.Err.Filepath(), err)
+			}
+
+			start := time.Unix(0, dataInfoFile.Info.BlockStart)
+			if start.Before(blockStart) || !start.Before(blockEnd) {
+				continue
+			}
+
+			if err := indexDataFileSet(fsOpts, dataInfoFile.ID, seg); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if _, err := seg.Seal(); err != nil {
+		return 0, err
+	}
+
+	nsOpts := namespace.NewOptions().
+		SetIndexOptions(namespace.NewIndexOptions().
+			SetEnabled(true).
+			SetBlockSize(blockSize))
+	nsMetadata, err := namespace.NewMetadata(nsID, nsOpts)
+	if err != nil {
+		return 0, err
+	}
+
+	pm, err := fs.NewPersistManager(fsOpts)
+	if err != nil {
+		return 0, err
+	}
+	flush, err := pm.StartIndexPersist()
+	if err != nil {
+		return 0, err
+	}
+
+	prepared, err := flush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: nsMetadata,
+		BlockStart:        blockStart,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            shards,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := prepared.Persist(seg); err != nil {
+		return 0, err
+	}
+
+	persisted, err := prepared.Close()
+	if err != nil {
+		return 0, err
+	}
+	for _, persistedSeg := range persisted {
+		persistedSeg.Close()
+	}
+
+	if err := flush.DoneIndex(); err != nil {
+		return 0, err
+	}
+
+	return seg.Size(), nil
+}
+
+// indexDataFileSet indexes the IDs and tags of the data fileset volume, reading
+// the files of tiered volumes through the tiered file cache of the options.
+func indexDataFileSet(
+	fsOpts fs.Options,
+	id fs.FileSetFileIdentifier,
+	seg segment.MutableSegment,
+) error {
+	reader, err := fs.NewReader(bytesPool, fsOpts)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	err = reader.Open(fs.DataReaderOpenOptions{
+		Identifier:  id,
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		id, tagsIter, _, _, err := reader.ReadMetadata()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		exists, err := seg.ContainsID(id.Bytes())
+		if err == nil && !exists {
+			var d doc.Document
+			d, err = convert.FromMetricIter(id, tagsIter)
+			if err == nil {
+				_, err = seg.Insert(d)
+			}
+		}
+		id.Finalize()
+		tagsIter.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
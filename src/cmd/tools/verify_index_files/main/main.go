@@ -23,7 +23,9 @@
 // the name of any series that are missing, or have mismatched checksums. The tool
 // expects a directory where each subdirectory is the name of the host, and within
 // each of those subdirectories is the "data" directory for that host, exactly as
-// generated by M3DB itself. See the README for more details.
+// generated by M3DB itself. It can also validate the checksums of the index
+// filesets of each host and repair corrupt ones by rebuilding them from the data
+// filesets. See the README for more details.
 
 package main
 
@@ -39,6 +41,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/tools"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3x/ident"
@@ -69,6 +72,10 @@ var (
 	shardsArg           = flagParser.String("shards", "", "Shards - set comma separated list of shards")
 	blocksArgs          = flagParser.String("blocks", "", "Start unix timestamp (Seconds) - set comma separated list of unix timestamps")
 	compareChecksumsArg = flagParser.Bool("compare-checksums", true, "Compare checksums")
+	validateIndexArg    = flagParser.Bool("validate-index-filesets", false, "Validate the checksums of index filesets instead of comparing data filesets")
+	repairArg           = flagParser.Bool("repair", false, "Quarantine corrupt index filesets and rebuild them from data filesets, requires validate-index-filesets")
+	tieringPrefixArg    = flagParser.String("tiering-path-prefix", "", "Path prefix of the directories data filesets are tiered to - must contain a folder per host if set")
+	tieringCacheArg     = flagParser.Int64("tiering-cache-max-bytes", config.DefaultTieringCacheMaxBytes, "Max size of the cache of tiered files downloaded for each host")
 )
 
 var bytesPool pool.CheckedBytesPool
@@ -86,14 +93,42 @@ func main() {
 		shardsVal        = *shardsArg
 		blocksVal        = *blocksArgs
 		compareChecksums = *compareChecksumsArg
+		validateIndex    = *validateIndexArg
+		repair           = *repairArg
+		tieringPrefix    = *tieringPrefixArg
+		tieringCache     = *tieringCacheArg
 	)
 
-	blocks := parseBlockArgs(blocksVal)
+	if repair && !validateIndex {
+		log.Fatalf("repair requires validate-index-filesets to be set")
+	}
+
 	hosts, err := ioutil.ReadDir(pathPrefix)
 	if err != nil {
 		log.Fatalf("err reading dir: %s, err: %s\n", pathPrefix, err)
 	}
 
+	hostsFsOpts := make(map[string]fs.Options, len(hosts))
+	for _, host := range hosts {
+		hostsFsOpts[host.Name()] = newFilesystemOptions(pathPrefix, tieringPrefix,
+			tieringCache, host.Name())
+	}
+
+	if validateIndex {
+		// Validate all blocks unless a set of blocks is specified.
+		var blocks []int64
+		if strings.TrimSpace(blocksVal) != "" {
+			blocks = parseBlockArgs(blocksVal)
+		}
+		for _, host := range hosts {
+			log.Printf("validating index filesets for host: %s\n", host.Name())
+			validateIndexFileSets(namespaceStr, hostsFsOpts[host.Name()], host.Name(),
+				blocks, repair)
+		}
+		return
+	}
+
+	blocks := parseBlockArgs(blocksVal)
 	shards := parseShards(shardsVal)
 	for _, block := range blocks {
 		for _, shard := range shards {
@@ -101,7 +136,8 @@ func main() {
 			allHostSeriesChecksumsForShard := []seriesChecksums{}
 			// Accumulate all the series checksums for each host for this shard
 			for _, host := range hosts {
-				hostShardReader, err := newReader(namespaceStr, pathPrefix, host.Name(), shard, time.Unix(block, 0))
+				hostShardReader, err := newReader(namespaceStr, hostsFsOpts[host.Name()],
+					shard, time.Unix(block, 0))
 				if err != nil {
 					// Ignore folders for hosts that don't have this data
 					if err == fs.ErrCheckpointFileNotFound {
@@ -199,8 +235,31 @@ func mergeMaps(seriesMaps ...seriesMap) seriesMap {
 	return merged
 }
 
-func newReader(namespace, pathPrefix, hostName string, shard uint32, start time.Time) (fs.DataFileSetReader, error) {
+// newFilesystemOptions returns the filesystem options for the data directory of
+// a host, reading data filesets tiered to the object store through a cache in
+// the data directory if a tiering path prefix is set.
+func newFilesystemOptions(pathPrefix, tieringPrefix string, cacheMaxBytes int64, hostName string) fs.Options {
 	fsOpts := fs.NewOptions().SetFilePathPrefix(path.Join(pathPrefix, hostName))
+	if tieringPrefix == "" {
+		return fsOpts
+	}
+
+	objectStore := fs.NewDirectoryObjectStore(path.Join(tieringPrefix, hostName))
+	cache, err := fs.NewTieredFileCache(objectStore, fs.TieredFileCacheOptions{
+		Directory:        fs.TieredFileCacheDirPath(fsOpts.FilePathPrefix()),
+		MaxBytes:         cacheMaxBytes,
+		NewFileMode:      fsOpts.NewFileMode(),
+		NewDirectoryMode: fsOpts.NewDirectoryMode(),
+	})
+	if err != nil {
+		log.Fatalf("err creating tiered file cache for host %s: %v\n", hostName, err)
+	}
+	return fsOpts.
+		SetObjectStore(objectStore).
+		SetTieredFileCache(cache)
+}
+
+func newReader(namespace string, fsOpts fs.Options, shard uint32, start time.Time) (fs.DataFileSetReader, error) {
 	reader, err := fs.NewReader(bytesPool, fsOpts)
 	if err != nil {
 		return nil, err
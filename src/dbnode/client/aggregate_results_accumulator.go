@@ -0,0 +1,210 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/topology"
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+type aggregateResultAccumulatorOpts struct {
+	host     topology.Host
+	response *rpc.AggregateQueryResult_
+}
+
+type aggregateResultAccumulator struct {
+	// NB: an aggregate request fans out to each host in the topology, the
+	// response consistency is tracked per shard the same as fetchTagged.
+	shardConsistencyResults []fetchTaggedShardConsistencyResult
+	numHostsPending         int32
+	numShardsPending        int32
+
+	errors     xerrors.Errors
+	numSuccess int
+	exhaustive bool
+	// NB: merged is unbounded, the limit is applied once the counts from each
+	// of the replicas have been combined.
+	merged index.AggregateResults
+
+	majority         int
+	consistencyLevel topology.ReadConsistencyLevel
+	topoMap          topology.Map
+}
+
+func newAggregateResultAccumulator(
+	topoMap topology.Map,
+	majority int,
+	consistencyLevel topology.ReadConsistencyLevel,
+) *aggregateResultAccumulator {
+	accum := &aggregateResultAccumulator{
+		shardConsistencyResults: make([]fetchTaggedShardConsistencyResult,
+			1+int(topoMap.ShardSet().Max())),
+		numHostsPending:  int32(topoMap.HostsLen()),
+		numShardsPending: int32(len(topoMap.ShardSet().All())),
+		exhaustive:       true,
+		merged:           index.NewAggregateResults(0),
+		majority:         majority,
+		consistencyLevel: consistencyLevel,
+		topoMap:          topoMap,
+	}
+	for _, hss := range topoMap.HostShardSets() {
+		for _, hShard := range hss.ShardSet().All() {
+			accum.shardConsistencyResults[hShard.ID()].enqueued++
+		}
+	}
+	return accum
+}
+
+func (accum *aggregateResultAccumulator) Add(
+	opts aggregateResultAccumulatorOpts,
+	resultErr error,
+) (bool, error) {
+	host := opts.host
+	if host == nil {
+		// should never happen, guarding against incompatible changes to the `client` package.
+		err := fmt.Errorf("[invariant violated] nil host in aggregate completionFn")
+		return true, xerrors.NewNonRetryableError(err)
+	}
+
+	hostShardSet, ok := accum.topoMap.LookupHostShardSet(host.ID())
+	if !ok {
+		// should never happen, as we've taken a reference to the
+		// topology when beginning the request, and the var is immutable.
+		err := fmt.Errorf(
+			"[invariant violated] missing host shard in aggregate completionFn: %s", host.ID())
+		return true, xerrors.NewNonRetryableError(err)
+	}
+
+	accum.numHostsPending--
+	if resultErr != nil {
+		accum.errors = append(accum.errors, xerrors.NewRenamedError(resultErr,
+			fmt.Errorf("error aggregating from host %s: %v", host.ID(), resultErr)))
+	} else {
+		accum.numSuccess++
+		accum.exhaustive = accum.exhaustive && opts.response.Exhaustive
+		for _, elem := range opts.response.Results {
+			if len(elem.TagValues) == 0 {
+				accum.merged.AddField(elem.TagName, elem.Count)
+				continue
+			}
+			for _, value := range elem.TagValues {
+				accum.merged.AddFieldValue(elem.TagName, value.TagValue, value.Count)
+			}
+		}
+	}
+
+	for _, hs := range hostShardSet.ShardSet().All() {
+		shardID := int(hs.ID())
+		shardResult := accum.shardConsistencyResults[shardID]
+		if shardResult.done {
+			continue
+		}
+
+		if hs.State() != shard.Available {
+			// Only responses from available shards are accepted, the same
+			// as fetchTagged.
+			shardResult.errors++
+		} else if resultErr == nil {
+			shardResult.success++
+		} else {
+			shardResult.errors++
+		}
+
+		if topology.ReadConsistencyTermination(accum.consistencyLevel, int32(accum.majority),
+			shardResult.pending(), int32(shardResult.success)) {
+			shardResult.done = true
+			if topology.ReadConsistencyAchieved(accum.consistencyLevel, accum.majority,
+				int(shardResult.enqueued), int(shardResult.success)) {
+				accum.numShardsPending--
+			}
+		}
+
+		accum.shardConsistencyResults[shardID] = shardResult
+	}
+
+	// success case, sufficient responses for each shard
+	if accum.numShardsPending == 0 {
+		return true, nil
+	}
+
+	// failure case - we've received all responses but still weren't able to
+	// satisfy all shards, so we need to fail
+	if accum.numHostsPending == 0 {
+		return true, fmt.Errorf(
+			"unable to satisfy consistency requirements for %d shards [ err = %s ]",
+			accum.numShardsPending, accum.errors.Error())
+	}
+
+	return false, nil
+}
+
+// Results returns the aggregated results of the hosts that responded, at most
+// limit distinct entries are returned.
+func (accum *aggregateResultAccumulator) Results(limit int) index.AggregateQueryResults {
+	results := index.NewAggregateResults(limit)
+	exhaustive := accum.exhaustive && accum.addPerReplicaCounts(results)
+	return index.AggregateQueryResults{
+		Results:    results,
+		Exhaustive: exhaustive,
+	}
+}
+
+// addPerReplicaCounts adds the merged results to the given results, it returns
+// false if the results reached their limit.
+func (accum *aggregateResultAccumulator) addPerReplicaCounts(
+	results index.AggregateResults,
+) bool {
+	for _, field := range accum.merged.Fields() {
+		if len(field.Values) == 0 {
+			if !results.AddField(field.Name, accum.perReplicaCount(field.Count)) {
+				return false
+			}
+			continue
+		}
+		for _, value := range field.Values {
+			if !results.AddFieldValue(field.Name, value.Value, accum.perReplicaCount(value.Count)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// perReplicaCount scales a count summed across the hosts that responded back
+// down to approximate the number of distinct series. Each series is counted
+// once by every responding host that owns its shard, which on average is the
+// replication factor scaled by the share of hosts that responded.
+func (accum *aggregateResultAccumulator) perReplicaCount(count int64) int64 {
+	var (
+		hosts     = int64(accum.topoMap.HostsLen())
+		responded = int64(accum.topoMap.Replicas()) * int64(accum.numSuccess)
+	)
+	if responded <= hosts {
+		return count
+	}
+	// Round up so that a series seen by any replica is always counted.
+	return (count*hosts + responded - 1) / responded
+}
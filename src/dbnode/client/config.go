@@ -69,6 +69,11 @@ type Configuration struct {
 	// FetchRetry is the fetch retry config.
 	FetchRetry retry.Configuration `yaml:"fetchRetry"`
 
+	// FetchHedgePercentile is the percentile, between zero and one, of a host's
+	// recent fetch latencies after which a fetch tagged request is hedged to
+	// additional replicas, fetches are not hedged if not set.
+	FetchHedgePercentile *float64 `yaml:"fetchHedgePercentile"`
+
 	// BackgroundHealthCheckFailLimit is the amount of times a background check
 	// must fail before a connection is taken out of consideration.
 	BackgroundHealthCheckFailLimit int `yaml:"backgroundHealthCheckFailLimit" validate:"min=1,max=10"`
@@ -79,6 +84,29 @@ type Configuration struct {
 
 	// HashingConfiguration is the configuration for hashing of IDs to shards.
 	HashingConfiguration HashingConfiguration `yaml:"hashing"`
+
+	// HintedHandoff is the configuration for persisting writes that failed on
+	// a replica to replay once the replica's host is reachable again.
+	HintedHandoff *HintedHandoffConfiguration `yaml:"hintedHandoff"`
+}
+
+// HintedHandoffConfiguration is the configuration for hinted handoff.
+type HintedHandoffConfiguration struct {
+	// Directory is the directory hints are persisted to.
+	Directory string `yaml:"directory" validate:"nonzero"`
+
+	// MaxAge is the maximum age of a hint before it is discarded, it should
+	// not exceed the buffer past of the namespaces written to.
+	MaxAge time.Duration `yaml:"maxAge" validate:"min=0"`
+
+	// MaxBytes is the maximum size of the hints persisted for a single host.
+	MaxBytes int64 `yaml:"maxBytes" validate:"min=0"`
+
+	// ReplayBatchSize is the number of hints replayed to a host at a time.
+	ReplayBatchSize int `yaml:"replayBatchSize" validate:"min=0"`
+
+	// ReplayRate is the maximum number of hints replayed to a host per second.
+	ReplayRate int `yaml:"replayRate" validate:"min=0"`
 }
 
 // HashingConfiguration is the configuration for hashing
@@ -190,6 +218,26 @@ func (c Configuration) NewAdminClient(
 		SetChannelOptions(xtchannel.NewDefaultChannelOptions()).
 		SetInstrumentOptions(iopts)
 
+	if c.FetchHedgePercentile != nil {
+		v = v.SetFetchHedgePercentile(*c.FetchHedgePercentile)
+	}
+
+	if hh := c.HintedHandoff; hh != nil {
+		v = v.SetHintedHandoffDirectory(hh.Directory)
+		if hh.MaxAge > 0 {
+			v = v.SetHintedHandoffMaxAge(hh.MaxAge)
+		}
+		if hh.MaxBytes > 0 {
+			v = v.SetHintedHandoffMaxBytes(hh.MaxBytes)
+		}
+		if hh.ReplayBatchSize > 0 {
+			v = v.SetHintedHandoffReplayBatchSize(hh.ReplayBatchSize)
+		}
+		if hh.ReplayRate > 0 {
+			v = v.SetHintedHandoffReplayRate(hh.ReplayRate)
+		}
+	}
+
 	encodingOpts := params.EncodingOptions
 	if encodingOpts == nil {
 		encodingOpts = encoding.NewOptions()
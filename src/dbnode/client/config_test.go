@@ -52,10 +52,17 @@ fetchRetry:
     backoffFactor: 2
     maxRetries: 3
     jitter: true
+fetchHedgePercentile: 0.95
 backgroundHealthCheckFailLimit: 4
 backgroundHealthCheckFailThrottleFactor: 0.5
 hashing:
   seed: 42
+hintedHandoff:
+  directory: /var/lib/m3db/hints
+  maxAge: 1h
+  maxBytes: 1048576
+  replayBatchSize: 512
+  replayRate: 5000
 `
 
 	fd, err := ioutil.TempFile("", "config.yaml")
@@ -73,6 +80,7 @@ hashing:
 	require.NoError(t, err)
 
 	boolTrue := true
+	hedgePercentile := 0.95
 	expected := Configuration{
 		WriteConsistencyLevel:   topology.ConsistencyLevelMajority,
 		ReadConsistencyLevel:    topology.ReadConsistencyLevelUnstrictMajority,
@@ -92,11 +100,19 @@ hashing:
 			MaxRetries:     3,
 			Jitter:         &boolTrue,
 		},
+		FetchHedgePercentile:                    &hedgePercentile,
 		BackgroundHealthCheckFailLimit:          4,
 		BackgroundHealthCheckFailThrottleFactor: 0.5,
 		HashingConfiguration: HashingConfiguration{
 			Seed: 42,
 		},
+		HintedHandoff: &HintedHandoffConfiguration{
+			Directory:       "/var/lib/m3db/hints",
+			MaxAge:          time.Hour,
+			MaxBytes:        1048576,
+			ReplayBatchSize: 512,
+			ReplayRate:      5000,
+		},
 	}
 
 	assert.Equal(t, expected, cfg)
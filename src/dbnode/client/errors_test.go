@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
 	"github.com/m3db/m3/src/dbnode/topology"
 	xerrors "github.com/m3db/m3x/errors"
 
@@ -54,3 +55,16 @@ func TestConsistencyResultError(t *testing.T) {
 	assert.Equal(t, 1, NumSuccess(err))
 	assert.Equal(t, 2, NumError(err))
 }
+
+func TestIsResourceExhaustedError(t *testing.T) {
+	err := tterrors.NewResourceExhaustedError(fmt.Errorf("quota exceeded"))
+	assert.True(t, IsResourceExhaustedError(err))
+	assert.False(t, IsBadRequestError(err))
+	assert.False(t, IsInternalServerError(err))
+
+	wrapped := xerrors.NewRenamedError(err, fmt.Errorf("wrapped"))
+	assert.True(t, IsResourceExhaustedError(wrapped))
+
+	assert.False(t, IsResourceExhaustedError(tterrors.NewInternalError(fmt.Errorf("internal"))))
+	assert.False(t, IsResourceExhaustedError(nil))
+}
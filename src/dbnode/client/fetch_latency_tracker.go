@@ -0,0 +1,119 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+)
+
+const (
+	// fetchLatencyTrackerSamples is the number of recent fetch latencies
+	// tracked for a host.
+	fetchLatencyTrackerSamples = 128
+
+	// fetchLatencyTrackerMinSamples is the minimum number of recent fetch
+	// latencies required to estimate a percentile.
+	fetchLatencyTrackerMinSamples = 16
+
+	// fetchLatencyTrackerWindow is how long a fetch latency is considered
+	// recent, this ensures estimates for hosts that have not been fetched from
+	// recently are not used to decide where to send fetches.
+	fetchLatencyTrackerWindow = time.Minute
+)
+
+type fetchLatencySample struct {
+	completedAt time.Time
+	latency     time.Duration
+}
+
+// fetchLatencyTracker tracks the latencies of the most recent fetches to a
+// host to estimate percentiles of the host's fetch latency.
+type fetchLatencyTracker struct {
+	sync.Mutex
+
+	nowFn   clock.NowFn
+	samples []fetchLatencySample
+	next    int
+	sorted  []time.Duration
+}
+
+func newFetchLatencyTracker(nowFn clock.NowFn) *fetchLatencyTracker {
+	return &fetchLatencyTracker{
+		nowFn:   nowFn,
+		samples: make([]fetchLatencySample, 0, fetchLatencyTrackerSamples),
+		sorted:  make([]time.Duration, 0, fetchLatencyTrackerSamples),
+	}
+}
+
+// Record records the latency of a fetch that just completed.
+func (t *fetchLatencyTracker) Record(latency time.Duration) {
+	sample := fetchLatencySample{completedAt: t.nowFn(), latency: latency}
+
+	t.Lock()
+	if len(t.samples) < cap(t.samples) {
+		t.samples = append(t.samples, sample)
+	} else {
+		t.samples[t.next] = sample
+	}
+	t.next = (t.next + 1) % cap(t.samples)
+	t.Unlock()
+}
+
+// Percentile returns the given percentile of the recent fetch latencies, or
+// false if too few fetches have completed recently to estimate it.
+func (t *fetchLatencyTracker) Percentile(percentile float64) (time.Duration, bool) {
+	since := t.nowFn().Add(-fetchLatencyTrackerWindow)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.sorted = t.sorted[:0]
+	for _, sample := range t.samples {
+		if sample.completedAt.Before(since) {
+			continue
+		}
+		t.sorted = append(t.sorted, sample.latency)
+	}
+	if len(t.sorted) < fetchLatencyTrackerMinSamples {
+		return 0, false
+	}
+
+	sort.Sort(durationsAsc(t.sorted))
+	idx := int(math.Ceil(percentile*float64(len(t.sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(t.sorted) {
+		idx = len(t.sorted) - 1
+	}
+	return t.sorted[idx], true
+}
+
+type durationsAsc []time.Duration
+
+func (d durationsAsc) Len() int           { return len(d) }
+func (d durationsAsc) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d durationsAsc) Less(i, j int) bool { return d[i] < d[j] }
@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchLatencyTrackerPercentile(t *testing.T) {
+	now := time.Now()
+	tracker := newFetchLatencyTracker(func() time.Time { return now })
+
+	for i := 1; i < fetchLatencyTrackerMinSamples; i++ {
+		tracker.Record(time.Duration(i) * time.Millisecond)
+	}
+	_, ok := tracker.Percentile(0.5)
+	require.False(t, ok)
+
+	for i := fetchLatencyTrackerMinSamples; i <= 100; i++ {
+		tracker.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	latency, ok := tracker.Percentile(0.95)
+	require.True(t, ok)
+	assert.Equal(t, 95*time.Millisecond, latency)
+
+	latency, ok = tracker.Percentile(1)
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, latency)
+
+	latency, ok = tracker.Percentile(0)
+	require.True(t, ok)
+	assert.Equal(t, time.Millisecond, latency)
+}
+
+func TestFetchLatencyTrackerKeepsMostRecentSamples(t *testing.T) {
+	now := time.Now()
+	tracker := newFetchLatencyTracker(func() time.Time { return now })
+
+	for i := 0; i < fetchLatencyTrackerSamples; i++ {
+		tracker.Record(time.Second)
+	}
+	for i := 0; i < fetchLatencyTrackerSamples; i++ {
+		tracker.Record(time.Millisecond)
+	}
+
+	latency, ok := tracker.Percentile(1)
+	require.True(t, ok)
+	assert.Equal(t, time.Millisecond, latency)
+}
+
+func TestFetchLatencyTrackerIgnoresOldSamples(t *testing.T) {
+	now := time.Now()
+	tracker := newFetchLatencyTracker(func() time.Time { return now })
+
+	for i := 0; i < fetchLatencyTrackerMinSamples; i++ {
+		tracker.Record(time.Millisecond)
+	}
+	_, ok := tracker.Percentile(0.5)
+	require.True(t, ok)
+
+	now = now.Add(fetchLatencyTrackerWindow + time.Second)
+	_, ok = tracker.Percentile(0.5)
+	require.False(t, ok)
+}
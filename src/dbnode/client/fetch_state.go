@@ -31,6 +31,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/x/serialize"
 	"github.com/m3db/m3x/ident"
+
+	"github.com/uber-go/tally"
 )
 
 const (
@@ -53,9 +55,29 @@ type fetchState struct {
 	err                  error
 	done                 bool
 
+	// hedgeQueues are the queues of the spare hosts the fetch is hedged to if
+	// it has not completed once hedgeTimer fires, they are only set when the
+	// fetch is hedged.
+	hedgeQueues  []hostQueue
+	hedgeTimer   *time.Timer
+	hedgedHosts  []string
+	hedgeMetrics fetchHedgeMetrics
+
 	pool fetchStatePool
 }
 
+type fetchHedgeMetrics struct {
+	sent tally.Counter
+	won  tally.Counter
+}
+
+func newFetchHedgeMetrics(scope tally.Scope) fetchHedgeMetrics {
+	return fetchHedgeMetrics{
+		sent: scope.Counter("fetch.hedges-sent"),
+		won:  scope.Counter("fetch.hedges-won"),
+	}
+}
+
 func newFetchState(pool fetchStatePool) *fetchState {
 	f := &fetchState{
 		tagResultAccumulator: newFetchTaggedResultAccumulator(),
@@ -77,6 +99,13 @@ func (f *fetchState) close() {
 	}
 	f.err = nil
 	f.done = false
+	f.hedgeQueues = nil
+	f.hedgeTimer = nil
+	for i := range f.hedgedHosts {
+		f.hedgedHosts[i] = ""
+	}
+	f.hedgedHosts = f.hedgedHosts[:0]
+	f.hedgeMetrics = fetchHedgeMetrics{}
 	f.tagResultAccumulator.Clear()
 
 	if f.pool == nil {
@@ -95,6 +124,87 @@ func (f *fetchState) Reset(
 	op.incRef() // take a reference to the provided op
 	f.op = op
 	f.tagResultAccumulator.Reset(startTime, endTime, topoMap, majority, consistencyLevel)
+	if op.paginated() {
+		f.tagResultAccumulator.ResetPaginated()
+	}
+}
+
+// ResetHedge holds back the fetch from the given queues and hedges the fetch
+// to them if it has not completed after the delay, it must be called after
+// Reset with the lock held and before the fetch is enqueued to any host.
+func (f *fetchState) ResetHedge(
+	queues []hostQueue,
+	delay time.Duration,
+	metrics fetchHedgeMetrics,
+) {
+	f.hedgeQueues = queues
+	f.hedgeMetrics = metrics
+	for _, q := range queues {
+		f.tagResultAccumulator.SpareHost(q.Host())
+	}
+
+	f.incRef() // the hedge timer holds a reference until it fires or is stopped
+	f.hedgeTimer = time.AfterFunc(delay, f.hedgeTimerFn)
+}
+
+func (f *fetchState) hedgeTimerFn() {
+	f.Lock()
+	if !f.done {
+		f.hedgeWithLock()
+	}
+	f.Unlock()
+	f.decRef() // release ref held onto by the hedge timer
+}
+
+// hedgeWithLock sends the fetch to all the spare hosts it has been held back
+// from, if any.
+func (f *fetchState) hedgeWithLock() {
+	queues := f.hedgeQueues
+	f.stopHedgeWithLock()
+
+	for _, q := range queues {
+		host := q.Host()
+		f.tagResultAccumulator.HedgeHost(host)
+
+		// inc to indicate the hostQueue has a reference to `op` which has a ref to the fetchState
+		f.incRef()
+		if err := q.Enqueue(f.op); err != nil {
+			f.decRef() // release the ref for the hostQueue
+
+			// The queue has been closed by a topology change, treat the host
+			// as having failed to respond.
+			done, err := f.tagResultAccumulator.Add(
+				fetchTaggedResultAccumulatorOpts{host: host}, err)
+			if done {
+				f.markDoneWithLock(err)
+				return
+			}
+			continue
+		}
+
+		f.hedgedHosts = append(f.hedgedHosts, host.ID())
+		f.hedgeMetrics.sent.Inc(1)
+	}
+}
+
+// stopHedgeWithLock stops the fetch from being hedged.
+func (f *fetchState) stopHedgeWithLock() {
+	if f.hedgeTimer != nil && f.hedgeTimer.Stop() {
+		// NB: the caller always holds a reference to the fetch state as well
+		// so this never releases it while the lock is held.
+		f.decRef() // release ref held onto by the hedge timer
+	}
+	f.hedgeTimer = nil
+	f.hedgeQueues = nil
+}
+
+func (f *fetchState) hedgedHost(hostID string) bool {
+	for _, hedgedHostID := range f.hedgedHosts {
+		if hedgedHostID == hostID {
+			return true
+		}
+	}
+	return false
 }
 
 func (f *fetchState) completionFn(
@@ -123,13 +233,28 @@ func (f *fetchState) completionFn(
 
 	done, err := f.tagResultAccumulator.Add(opts, resultErr)
 	if done {
+		if err == nil && opts.host != nil && f.hedgedHost(opts.host.ID()) {
+			f.hedgeMetrics.won.Inc(1)
+		}
 		f.markDoneWithLock(err)
+		return
+	}
+
+	if len(f.hedgeQueues) > 0 &&
+		(resultErr != nil || f.tagResultAccumulator.NumHostsPending() == 0) {
+		// Don't wait to hedge the fetch if a host failed or all the hosts
+		// have responded without meeting the read consistency level.
+		f.hedgeWithLock()
+		if f.done {
+			return
+		}
 	}
 }
 
 func (f *fetchState) markDoneWithLock(err error) {
 	f.done = true
 	f.err = err
+	f.stopHedgeWithLock()
 	f.Signal()
 }
 
@@ -165,6 +290,21 @@ func (f *fetchState) asEncodingSeriesIterators(pools fetchTaggedPools) (encoding
 	return f.tagResultAccumulator.AsEncodingSeriesIterators(limit, pools)
 }
 
+func (f *fetchState) nextPageToken() ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.done {
+		return nil, errFetchStateStillProcessing
+	}
+
+	if err := f.err; err != nil {
+		return nil, err
+	}
+
+	return f.tagResultAccumulator.NextPageToken(f.op.pageTokens)
+}
+
 // NB(prateek): this is backed by the sessionPools struct, but we're restricting it to a narrow
 // interface to force the fetchTagged code-paths to be explicit about the pools they need access
 // to. The alternative is to either expose the sessionPools struct (which is a worse abstraction),
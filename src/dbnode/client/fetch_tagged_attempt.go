@@ -42,12 +42,16 @@ type fetchTaggedAttempt struct {
 	dataResultIters      encoding.SeriesIterators
 	idsResultExhaustive  bool
 	dataResultExhaustive bool
+	resultNextPageToken  []byte
 }
 
 type fetchTaggedAttemptArgs struct {
-	ns    ident.ID
-	query index.Query
-	opts  index.QueryOptions
+	ns         ident.ID
+	query      index.Query
+	opts       index.QueryOptions
+	pageTokens map[string]fetchTaggedHostPageToken
+	// shards restricts the fetch to the series of the shards, if set.
+	shards []uint32
 }
 
 func (f *fetchTaggedAttempt) reset() {
@@ -57,19 +61,20 @@ func (f *fetchTaggedAttempt) reset() {
 	f.idsResultExhaustive = false
 	f.dataResultIters = nil
 	f.dataResultExhaustive = false
+	f.resultNextPageToken = nil
 }
 
 func (f *fetchTaggedAttempt) performIDsAttempt() error {
 	var err error
-	f.idsResultIter, f.idsResultExhaustive, err = f.session.fetchTaggedIDsAttempt(
-		f.args.ns, f.args.query, f.args.opts)
+	f.idsResultIter, f.idsResultExhaustive, f.resultNextPageToken, err = f.session.fetchTaggedIDsAttempt(
+		f.args.ns, f.args.query, f.args.opts, f.args.pageTokens, f.args.shards)
 	return err
 }
 
 func (f *fetchTaggedAttempt) performDataAttempt() error {
 	var err error
-	f.dataResultIters, f.dataResultExhaustive, err = f.session.fetchTaggedAttempt(
-		f.args.ns, f.args.query, f.args.opts)
+	f.dataResultIters, f.dataResultExhaustive, f.resultNextPageToken, err = f.session.fetchTaggedAttempt(
+		f.args.ns, f.args.query, f.args.opts, f.args.pageTokens, f.args.shards)
 	return err
 }
 
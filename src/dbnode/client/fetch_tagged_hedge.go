@@ -0,0 +1,119 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/topology"
+)
+
+type fetchTaggedHedge struct {
+	queues []hostQueue
+	spare  []hostQueue
+	delay  time.Duration
+}
+
+// newFetchTaggedHedge splits the host queues into the queues a fetch tagged
+// request is sent to first, the fastest hosts that together can satisfy the
+// read consistency level for every shard, and the spare queues the request
+// is hedged to once the slowest of the first hosts has taken longer than the
+// given percentile of its recent fetch latencies. It returns false if the
+// request should be sent to every host instead.
+func newFetchTaggedHedge(
+	queues []hostQueue,
+	topoMap topology.Map,
+	level topology.ReadConsistencyLevel,
+	majority int,
+	percentile float64,
+) (fetchTaggedHedge, bool) {
+	var required int
+	switch level {
+	case topology.ReadConsistencyLevelNone, topology.ReadConsistencyLevelOne:
+		required = 1
+	case topology.ReadConsistencyLevelMajority, topology.ReadConsistencyLevelUnstrictMajority:
+		required = majority
+	default:
+		// Every host needs to respond, there is nothing to hedge.
+		return fetchTaggedHedge{}, false
+	}
+
+	byLatency := make(hostQueuesByLatency, 0, len(queues))
+	for _, q := range queues {
+		latency, ok := q.FetchLatency(percentile)
+		if !ok {
+			// Send to every host until enough recent latencies are known
+			// for each host to choose the fastest.
+			return fetchTaggedHedge{}, false
+		}
+		byLatency = append(byLatency, hostQueueLatency{queue: q, latency: latency})
+	}
+	sort.Stable(byLatency)
+
+	shardSet := topoMap.ShardSet()
+	needed := make([]int, 1+int(shardSet.Max()))
+	for _, shardID := range shardSet.AllIDs() {
+		needed[shardID] = required
+	}
+
+	var hedge fetchTaggedHedge
+	for _, elem := range byLatency {
+		hostShardSet, ok := topoMap.LookupHostShardSet(elem.queue.Host().ID())
+		if !ok {
+			return fetchTaggedHedge{}, false
+		}
+
+		useful := false
+		for _, s := range hostShardSet.ShardSet().All() {
+			if s.State() == shard.Available && needed[s.ID()] > 0 {
+				needed[s.ID()]--
+				useful = true
+			}
+		}
+		if !useful {
+			hedge.spare = append(hedge.spare, elem.queue)
+			continue
+		}
+
+		hedge.queues = append(hedge.queues, elem.queue)
+		if elem.latency > hedge.delay {
+			hedge.delay = elem.latency
+		}
+	}
+
+	if len(hedge.spare) == 0 {
+		return fetchTaggedHedge{}, false
+	}
+	return hedge, true
+}
+
+type hostQueueLatency struct {
+	queue   hostQueue
+	latency time.Duration
+}
+
+type hostQueuesByLatency []hostQueueLatency
+
+func (q hostQueuesByLatency) Len() int           { return len(q) }
+func (q hostQueuesByLatency) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q hostQueuesByLatency) Less(i, j int) bool { return q[i].latency < q[j].latency }
@@ -0,0 +1,205 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/topology"
+	tu "github.com/m3db/m3/src/dbnode/topology/testutil"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newTestFetchTaggedHedgeTopology() topology.Map {
+	// rf=3, 30 shards total; three identical hosts
+	return tu.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": tu.ShardsRange(0, 29, shard.Available),
+		"testhost1": tu.ShardsRange(0, 29, shard.Available),
+		"testhost2": tu.ShardsRange(0, 29, shard.Available),
+	})
+}
+
+func newTestFetchTaggedHedgeQueues(
+	ctrl *gomock.Controller,
+	topoMap topology.Map,
+	latencies ...time.Duration,
+) []hostQueue {
+	queues := make([]hostQueue, 0, len(latencies))
+	for i, latency := range latencies {
+		hss, _ := topoMap.LookupHostShardSet(fmt.Sprintf("testhost%d", i))
+		q := NewMockhostQueue(ctrl)
+		q.EXPECT().Host().Return(hss.Host()).AnyTimes()
+		q.EXPECT().FetchLatency(0.95).Return(latency, latency > 0).AnyTimes()
+		queues = append(queues, q)
+	}
+	return queues
+}
+
+func TestNewFetchTaggedHedgeSelectsFastestHosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	topoMap := newTestFetchTaggedHedgeTopology()
+	queues := newTestFetchTaggedHedgeQueues(ctrl, topoMap,
+		5*time.Millisecond, time.Millisecond, 3*time.Millisecond)
+
+	hedge, ok := newFetchTaggedHedge(queues, topoMap,
+		topology.ReadConsistencyLevelMajority, topoMap.MajorityReplicas(), 0.95)
+	require.True(t, ok)
+	assert.Equal(t, []hostQueue{queues[1], queues[2]}, hedge.queues)
+	assert.Equal(t, []hostQueue{queues[0]}, hedge.spare)
+	assert.Equal(t, 3*time.Millisecond, hedge.delay)
+
+	hedge, ok = newFetchTaggedHedge(queues, topoMap,
+		topology.ReadConsistencyLevelOne, topoMap.MajorityReplicas(), 0.95)
+	require.True(t, ok)
+	assert.Equal(t, []hostQueue{queues[1]}, hedge.queues)
+	assert.Equal(t, []hostQueue{queues[2], queues[0]}, hedge.spare)
+	assert.Equal(t, time.Millisecond, hedge.delay)
+}
+
+func TestNewFetchTaggedHedgeNotHedged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	topoMap := newTestFetchTaggedHedgeTopology()
+	majority := topoMap.MajorityReplicas()
+
+	// Every host must respond.
+	queues := newTestFetchTaggedHedgeQueues(ctrl, topoMap,
+		time.Millisecond, time.Millisecond, time.Millisecond)
+	_, ok := newFetchTaggedHedge(queues, topoMap,
+		topology.ReadConsistencyLevelAll, majority, 0.95)
+	require.False(t, ok)
+
+	// Latency of a host is not known yet.
+	queues = newTestFetchTaggedHedgeQueues(ctrl, topoMap,
+		time.Millisecond, 0, time.Millisecond)
+	_, ok = newFetchTaggedHedge(queues, topoMap,
+		topology.ReadConsistencyLevelMajority, majority, 0.95)
+	require.False(t, ok)
+}
+
+func newTestHedgedFetchState(
+	topoMap topology.Map,
+	spare hostQueue,
+	delay time.Duration,
+	scope tally.Scope,
+) *fetchState {
+	op := newFetchTaggedOp(nil)
+	state := newFetchState(nil)
+	state.incRef() // the caller's reference
+	state.Reset(testStartTime, testEndTime, op, topoMap,
+		topoMap.MajorityReplicas(), topology.ReadConsistencyLevelMajority)
+
+	state.Lock()
+	state.incRef() // testhost1's reference
+	state.incRef() // testhost2's reference
+	state.ResetHedge([]hostQueue{spare}, delay, newFetchHedgeMetrics(scope))
+	state.Unlock()
+	return state
+}
+
+func TestFetchStateHedgesAfterHostError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		topoMap = newTestFetchTaggedHedgeTopology()
+		scope   = tally.NewTestScope("", nil)
+		spare   = newTestFetchTaggedHedgeQueues(ctrl, topoMap, time.Millisecond)[0]
+		state   = newTestHedgedFetchState(topoMap, spare, time.Hour, scope)
+	)
+	spare.EXPECT().Enqueue(state.op).Return(nil)
+
+	// An error from a host hedges the fetch immediately, instead of failing
+	// the fetch once the other host responds.
+	state.completionFn(fetchTaggedResultAccumulatorOpts{
+		host: host(t, topoMap, "testhost1"),
+	}, errors.New("an error"))
+	state.completionFn(fetchTaggedResultAccumulatorOpts{
+		host:     host(t, topoMap, "testhost2"),
+		response: &rpc.FetchTaggedResult_{Exhaustive: true},
+	}, nil)
+	require.False(t, state.done)
+
+	state.completionFn(fetchTaggedResultAccumulatorOpts{
+		host:     host(t, topoMap, "testhost0"),
+		response: &rpc.FetchTaggedResult_{Exhaustive: true},
+	}, nil)
+	require.True(t, state.done)
+	require.NoError(t, state.err)
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["fetch.hedges-sent+"].Value())
+	assert.Equal(t, int64(1), counters["fetch.hedges-won+"].Value())
+
+	state.decRef()
+	require.Nil(t, state.op)
+}
+
+func TestFetchStateHedgesAfterDelay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		topoMap  = newTestFetchTaggedHedgeTopology()
+		scope    = tally.NewTestScope("", nil)
+		spare    = newTestFetchTaggedHedgeQueues(ctrl, topoMap, time.Millisecond)[0]
+		enqueued = make(chan struct{})
+		state    = newTestHedgedFetchState(topoMap, spare, time.Millisecond, scope)
+	)
+	spare.EXPECT().Enqueue(state.op).DoAndReturn(func(op op) error {
+		close(enqueued)
+		return nil
+	})
+	<-enqueued
+
+	for _, hostID := range []string{"testhost1", "testhost2"} {
+		state.completionFn(fetchTaggedResultAccumulatorOpts{
+			host:     host(t, topoMap, hostID),
+			response: &rpc.FetchTaggedResult_{Exhaustive: true},
+		}, nil)
+	}
+	require.True(t, state.done)
+	require.NoError(t, state.err)
+
+	// The hedged host responding after the fetch is done is ignored.
+	state.completionFn(fetchTaggedResultAccumulatorOpts{
+		host:     host(t, topoMap, "testhost0"),
+		response: &rpc.FetchTaggedResult_{Exhaustive: true},
+	}, nil)
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["fetch.hedges-sent+"].Value())
+	assert.Equal(t, int64(0), counters["fetch.hedges-won+"].Value())
+
+	state.decRef()
+}
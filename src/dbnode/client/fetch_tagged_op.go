@@ -34,6 +34,11 @@ type fetchTaggedOp struct {
 	request      rpc.FetchTaggedRequest
 	completionFn completionFn
 
+	// pageTokens is the position of each host when requesting a page after
+	// the first page of a paginated request, hosts without a page token
+	// request their first page.
+	pageTokens map[string]fetchTaggedHostPageToken
+
 	pool fetchTaggedOpPool
 }
 
@@ -45,7 +50,43 @@ func (f *fetchTaggedOp) update(req rpc.FetchTaggedRequest, fn completionFn) {
 	f.completionFn = fn
 }
 
+func (f *fetchTaggedOp) updatePageTokens(pageTokens map[string]fetchTaggedHostPageToken) {
+	f.pageTokens = pageTokens
+}
+
+func (f *fetchTaggedOp) paginated() bool {
+	return f.request.PageToken != nil
+}
+
+// requestForHost returns the request to send to the host, or false if the
+// host has already returned all results for a paginated request.
+func (f *fetchTaggedOp) requestForHost(hostID string) (*rpc.FetchTaggedRequest, bool) {
+	if f.pageTokens == nil {
+		return &f.request, true
+	}
+
+	// NB: a host without a page token either failed to return its first
+	// page or was not part of the topology when the previous page was
+	// requested, so request its first page.
+	hostToken := f.pageTokens[hostID]
+	if hostToken.exhausted {
+		return nil, false
+	}
+
+	req := f.request
+	req.PageToken = hostToken.pageToken
+	if req.PageToken == nil {
+		req.PageToken = []byte{}
+	}
+	return &req, true
+}
+
 func (f *fetchTaggedOp) requestLimit(defaultValue int) int {
+	if f.paginated() {
+		// NB: results are not truncated for paginated requests since hosts
+		// resume the next page after the last result they returned.
+		return defaultValue
+	}
 	if f.request.Limit == nil {
 		return defaultValue
 	}
@@ -55,6 +96,7 @@ func (f *fetchTaggedOp) requestLimit(defaultValue int) int {
 func (f *fetchTaggedOp) close() {
 	f.completionFn = nil
 	f.request = fetchTaggedOpRequestZeroed
+	f.pageTokens = nil
 	// return to pool
 	if f.pool == nil {
 		return
@@ -0,0 +1,100 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"sort"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/pagetoken"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// fetchTaggedHostPageToken is the position of a host in a paginated fetch
+// tagged request.
+type fetchTaggedHostPageToken struct {
+	// pageToken requests the next page from the host, an empty page token
+	// requests the first page.
+	pageToken []byte
+	// exhausted is set once the host has returned all of its results.
+	exhausted bool
+}
+
+// decodeFetchTaggedPageToken decodes a fetch tagged page token into the page
+// token to send to each host. A nil map is returned for an empty page token,
+// which requests the first page from every host.
+func decodeFetchTaggedPageToken(
+	token []byte,
+) (map[string]fetchTaggedHostPageToken, error) {
+	if len(token) == 0 {
+		return nil, nil
+	}
+
+	var decoded pagetoken.FetchTaggedPageToken
+	if err := proto.Unmarshal(token, &decoded); err != nil {
+		return nil, err
+	}
+
+	hostTokens := make(map[string]fetchTaggedHostPageToken, len(decoded.Hosts))
+	for _, host := range decoded.Hosts {
+		hostTokens[host.HostID] = fetchTaggedHostPageToken{
+			pageToken: host.PageToken,
+			exhausted: host.Exhausted,
+		}
+	}
+	return hostTokens, nil
+}
+
+// encodeFetchTaggedPageToken encodes the position of each host into a fetch
+// tagged page token, a nil page token is returned once all hosts have returned
+// all of their results.
+func encodeFetchTaggedPageToken(
+	hostTokens map[string]fetchTaggedHostPageToken,
+) ([]byte, error) {
+	exhausted := true
+	for _, hostToken := range hostTokens {
+		if !hostToken.exhausted {
+			exhausted = false
+			break
+		}
+	}
+	if exhausted {
+		return nil, nil
+	}
+
+	token := pagetoken.FetchTaggedPageToken{
+		Hosts: make([]*pagetoken.FetchTaggedPageToken_HostPageToken, 0, len(hostTokens)),
+	}
+	for hostID, hostToken := range hostTokens {
+		token.Hosts = append(token.Hosts, &pagetoken.FetchTaggedPageToken_HostPageToken{
+			HostID:    hostID,
+			PageToken: hostToken.pageToken,
+			Exhausted: hostToken.exhausted,
+		})
+	}
+
+	// Sort by host so the same page token is always produced for the same hosts.
+	sort.Slice(token.Hosts, func(i, j int) bool {
+		return token.Hosts[i].HostID < token.Hosts[j].HostID
+	})
+
+	return proto.Marshal(&token)
+}
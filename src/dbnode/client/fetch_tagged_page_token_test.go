@@ -0,0 +1,171 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/topology"
+	tu "github.com/m3db/m3/src/dbnode/topology/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTaggedPageTokenRoundTrip(t *testing.T) {
+	hostTokens := map[string]fetchTaggedHostPageToken{
+		"c": {exhausted: true},
+		"b": {pageToken: []byte("token-b")},
+		"a": {pageToken: []byte("token-a")},
+	}
+
+	token, err := encodeFetchTaggedPageToken(hostTokens)
+	require.NoError(t, err)
+	require.NotNil(t, token)
+
+	// Encoding is deterministic regardless of map iteration order.
+	again, err := encodeFetchTaggedPageToken(hostTokens)
+	require.NoError(t, err)
+	require.Equal(t, token, again)
+
+	decoded, err := decodeFetchTaggedPageToken(token)
+	require.NoError(t, err)
+	require.Equal(t, hostTokens, decoded)
+}
+
+func TestFetchTaggedPageTokenEmpty(t *testing.T) {
+	token, err := encodeFetchTaggedPageToken(nil)
+	require.NoError(t, err)
+	require.Nil(t, token)
+
+	decoded, err := decodeFetchTaggedPageToken(nil)
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+
+	// No page token is returned once every host is exhausted.
+	token, err = encodeFetchTaggedPageToken(map[string]fetchTaggedHostPageToken{
+		"a": {exhausted: true},
+		"b": {exhausted: true},
+	})
+	require.NoError(t, err)
+	require.Nil(t, token)
+}
+
+func TestFetchTaggedOpRequestForHost(t *testing.T) {
+	op := newFetchTaggedOp(nil)
+	op.update(rpc.FetchTaggedRequest{PageToken: []byte{}}, nil)
+
+	// First page requests every host.
+	req, ok := op.requestForHost("a")
+	require.True(t, ok)
+	require.Equal(t, []byte{}, req.PageToken)
+
+	op.updatePageTokens(map[string]fetchTaggedHostPageToken{
+		"a": {pageToken: []byte("token-a")},
+		"b": {exhausted: true},
+		"c": {},
+	})
+	req, ok = op.requestForHost("a")
+	require.True(t, ok)
+	require.Equal(t, []byte("token-a"), req.PageToken)
+
+	// Hosts marked exhausted have returned all of their results.
+	_, ok = op.requestForHost("b")
+	require.False(t, ok)
+
+	// Hosts without a page token request their first page again.
+	for _, hostID := range []string{"c", "d"} {
+		req, ok = op.requestForHost(hostID)
+		require.True(t, ok)
+		require.Equal(t, []byte{}, req.PageToken)
+	}
+}
+
+func TestFetchTaggedResultsAccumulatorNextPageToken(t *testing.T) {
+	// rf=3, 30 shards total; three identical hosts
+	topoMap := tu.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": tu.ShardsRange(0, 29, shard.Available),
+		"testhost1": tu.ShardsRange(0, 29, shard.Available),
+		"testhost2": tu.ShardsRange(0, 29, shard.Available),
+	})
+
+	accum := newFetchTaggedResultAccumulator()
+	accum.Reset(testStartTime, testEndTime, topoMap, topoMap.MajorityReplicas(),
+		topology.ReadConsistencyLevelOne)
+	accum.ResetPaginated()
+
+	pageTokens := map[string]fetchTaggedHostPageToken{
+		"testhost0": {pageToken: []byte("token-0")},
+		"testhost1": {pageToken: []byte("token-1")},
+		"testhost2": {pageToken: []byte("token-2")},
+	}
+
+	// The first host returns the next page, the second host fails and the
+	// third host is not waited for once the consistency level is met.
+	_, err := accum.Add(fetchTaggedResultAccumulatorOpts{
+		host: host(t, topoMap, "testhost1"),
+	}, errTestFetchTagged)
+	require.NoError(t, err)
+	done, err := accum.Add(fetchTaggedResultAccumulatorOpts{
+		host:     host(t, topoMap, "testhost0"),
+		response: &rpc.FetchTaggedResult_{NextPageToken: []byte("token-0-next")},
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, done)
+
+	token, err := accum.NextPageToken(pageTokens)
+	require.NoError(t, err)
+	decoded, err := decodeFetchTaggedPageToken(token)
+	require.NoError(t, err)
+	require.Equal(t, map[string]fetchTaggedHostPageToken{
+		"testhost0": {pageToken: []byte("token-0-next")},
+		"testhost1": {pageToken: []byte("token-1")},
+		"testhost2": {pageToken: []byte("token-2")},
+	}, decoded)
+
+	// Hosts without a next page token are marked exhausted.
+	accum.Clear()
+	accum.Reset(testStartTime, testEndTime, topoMap, topoMap.MajorityReplicas(),
+		topology.ReadConsistencyLevelAll)
+	accum.ResetPaginated()
+	for _, hostID := range []string{"testhost0", "testhost1", "testhost2"} {
+		response := &rpc.FetchTaggedResult_{Exhaustive: true}
+		if hostID == "testhost2" {
+			response.NextPageToken = []byte("token-2-next")
+		}
+		_, err := accum.Add(fetchTaggedResultAccumulatorOpts{
+			host:     host(t, topoMap, hostID),
+			response: response,
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	token, err = accum.NextPageToken(pageTokens)
+	require.NoError(t, err)
+	decoded, err = decodeFetchTaggedPageToken(token)
+	require.NoError(t, err)
+	require.Equal(t, map[string]fetchTaggedHostPageToken{
+		"testhost0": {exhausted: true},
+		"testhost1": {exhausted: true},
+		"testhost2": {pageToken: []byte("token-2-next")},
+	}, decoded)
+}
@@ -50,12 +50,18 @@ type fetchTaggedResultAccumulator struct {
 	// Length of this slice == 1 + max shard id in topology
 	shardConsistencyResults []fetchTaggedShardConsistencyResult
 	numHostsPending         int32
+	numHostsSpare           int32
 	numShardsPending        int32
 
 	errors     xerrors.Errors
 	responses  fetchTaggedIDResults
 	exhaustive bool
 
+	// paginated requests record the position each host returned its page
+	// at, to request the next page from.
+	paginated  bool
+	pageTokens map[string]fetchTaggedHostPageToken
+
 	startTime        time.Time
 	endTime          time.Time
 	majority         int
@@ -65,6 +71,7 @@ type fetchTaggedResultAccumulator struct {
 
 type fetchTaggedShardConsistencyResult struct {
 	enqueued int8
+	spare    int8
 	success  int8
 	errors   int8
 	done     bool
@@ -107,6 +114,17 @@ func (accum *fetchTaggedResultAccumulator) Add(
 		for _, elem := range response.Elements {
 			accum.responses = append(accum.responses, elem)
 		}
+		if accum.paginated {
+			if accum.pageTokens == nil {
+				accum.pageTokens = make(map[string]fetchTaggedHostPageToken)
+			}
+			// NB: hosts return no next page token once they have returned
+			// all of their results.
+			accum.pageTokens[host.ID()] = fetchTaggedHostPageToken{
+				pageToken: response.NextPageToken,
+				exhausted: response.NextPageToken == nil,
+			}
+		}
 	}
 
 	// FOLLOWUP(prateek): once we transmit the shards successfully satisfied by a response, the
@@ -131,7 +149,9 @@ func (accum *fetchTaggedResultAccumulator) Add(
 			shardResult.errors++
 		}
 
-		pending := shardResult.pending()
+		// NB: the shard is not done while it has spare replicas a hedged fetch
+		// may still be sent to.
+		pending := shardResult.pending() + int32(shardResult.spare)
 		if topology.ReadConsistencyTermination(accum.consistencyLevel, int32(accum.majority), pending, int32(shardResult.success)) {
 			shardResult.done = true
 			if topology.ReadConsistencyAchieved(accum.consistencyLevel, accum.majority, int(shardResult.enqueued), int(shardResult.success)) {
@@ -155,7 +175,7 @@ func (accum *fetchTaggedResultAccumulator) Add(
 
 	// failure case - we've received all responses but still weren't able to satisfy
 	// all shards, so we need to fail
-	if accum.numHostsPending == 0 && accum.numShardsPending != 0 {
+	if accum.numHostsPending == 0 && accum.numHostsSpare == 0 && accum.numShardsPending != 0 {
 		doneAccumulating := true
 		return doneAccumulating, fmt.Errorf(
 			"unable to satisfy consistency requirements for %d shards [ err = %s ]",
@@ -166,6 +186,44 @@ func (accum *fetchTaggedResultAccumulator) Add(
 	return doneAccumulating, nil
 }
 
+// SpareHost excludes a host that the request has not been sent to from the
+// responses expected until it is hedged to, shards owned by the host are not
+// considered done while the host is spare.
+func (accum *fetchTaggedResultAccumulator) SpareHost(host topology.Host) {
+	accum.updateHostSpare(host, true)
+}
+
+// HedgeHost indicates the request has been sent to a spare host and its
+// response is expected.
+func (accum *fetchTaggedResultAccumulator) HedgeHost(host topology.Host) {
+	accum.updateHostSpare(host, false)
+}
+
+// NumHostsPending returns the number of hosts the request has been sent to
+// that have not yet responded.
+func (accum *fetchTaggedResultAccumulator) NumHostsPending() int {
+	return int(accum.numHostsPending)
+}
+
+func (accum *fetchTaggedResultAccumulator) updateHostSpare(host topology.Host, spare bool) {
+	hostShardSet, ok := accum.topoMap.LookupHostShardSet(host.ID())
+	if !ok {
+		return // should never happen, hosts are taken from the topology
+	}
+
+	delta := int8(1)
+	if !spare {
+		delta = -1
+	}
+	accum.numHostsSpare += int32(delta)
+	accum.numHostsPending -= int32(delta)
+	for _, hs := range hostShardSet.ShardSet().All() {
+		shardResult := &accum.shardConsistencyResults[hs.ID()]
+		shardResult.spare += delta
+		shardResult.enqueued -= delta
+	}
+}
+
 func (accum *fetchTaggedResultAccumulator) Clear() {
 	for i := range accum.responses {
 		accum.responses[i] = nil
@@ -175,9 +233,14 @@ func (accum *fetchTaggedResultAccumulator) Clear() {
 		accum.errors[i] = nil
 	}
 	accum.errors = accum.errors[:0]
+	for hostID := range accum.pageTokens {
+		delete(accum.pageTokens, hostID)
+	}
 	accum.shardConsistencyResults = accum.shardConsistencyResults[:0]
+	accum.paginated = false
 	accum.consistencyLevel = topology.ReadConsistencyLevelNone
 	accum.majority, accum.numHostsPending, accum.numShardsPending = 0, 0, 0
+	accum.numHostsSpare = 0
 	accum.startTime, accum.endTime = time.Time{}, time.Time{}
 	accum.topoMap = nil
 	accum.exhaustive = true
@@ -212,6 +275,12 @@ func (accum *fetchTaggedResultAccumulator) Reset(
 	}
 }
 
+// ResetPaginated records the position of each host for a paginated request,
+// it must be called after Reset.
+func (accum *fetchTaggedResultAccumulator) ResetPaginated() {
+	accum.paginated = true
+}
+
 func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 	pools fetchTaggedPools,
 	elems fetchTaggedIDResults,
@@ -302,6 +371,26 @@ func (accum *fetchTaggedResultAccumulator) AsTaggedIDsIterator(
 	return iter, exhaustive, nil
 }
 
+// NextPageToken returns the page token to request the next page of a paginated
+// request given the page token of each host the current page was requested
+// with. Hosts which returned an error, or whose response was not waited for
+// once the read consistency level was met, request the same page again.
+func (accum *fetchTaggedResultAccumulator) NextPageToken(
+	pageTokens map[string]fetchTaggedHostPageToken,
+) ([]byte, error) {
+	hosts := accum.topoMap.Hosts()
+	nextPageTokens := make(map[string]fetchTaggedHostPageToken, len(hosts))
+	for _, host := range hosts {
+		hostID := host.ID()
+		if hostToken, ok := accum.pageTokens[hostID]; ok {
+			nextPageTokens[hostID] = hostToken
+			continue
+		}
+		nextPageTokens[hostID] = pageTokens[hostID]
+	}
+	return encodeFetchTaggedPageToken(nextPageTokens)
+}
+
 type fetchTaggedShardConsistencyResults []fetchTaggedShardConsistencyResult
 
 func (res fetchTaggedShardConsistencyResults) initialize(length int) fetchTaggedShardConsistencyResults {
@@ -29,6 +29,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/topology"
 	tu "github.com/m3db/m3/src/dbnode/topology/testutil"
+
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -453,3 +455,43 @@ func TestFetchTaggedResultsAccumulatorReadConsitencyLevelMajority(t *testing.T)
 		},
 	}.run()
 }
+
+func TestFetchTaggedResultsAccumulatorSpareHostWaitsForHedge(t *testing.T) {
+	// rf=3, 30 shards total; three identical hosts
+	topoMap := tu.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": tu.ShardsRange(0, 29, shard.Available),
+		"testhost1": tu.ShardsRange(0, 29, shard.Available),
+		"testhost2": tu.ShardsRange(0, 29, shard.Available),
+	})
+
+	accum := newFetchTaggedResultAccumulator()
+	accum.Reset(testStartTime, testEndTime, topoMap, topoMap.MajorityReplicas(),
+		topology.ReadConsistencyLevelMajority)
+	accum.SpareHost(host(t, topoMap, "testhost2"))
+	require.Equal(t, 2, accum.NumHostsPending())
+
+	done, err := accum.Add(fetchTaggedResultAccumulatorOpts{
+		host: host(t, topoMap, "testhost0"),
+	}, errTestFetchTagged)
+	require.NoError(t, err)
+	require.False(t, done)
+
+	// The spare host may still satisfy the consistency level once hedged to.
+	done, err = accum.Add(fetchTaggedResultAccumulatorOpts{
+		host:     host(t, topoMap, "testhost1"),
+		response: &testFetchTaggedSuccessResponse,
+	}, nil)
+	require.NoError(t, err)
+	require.False(t, done)
+	require.Equal(t, 0, accum.NumHostsPending())
+
+	accum.HedgeHost(host(t, topoMap, "testhost2"))
+	require.Equal(t, 1, accum.NumHostsPending())
+
+	done, err = accum.Add(fetchTaggedResultAccumulatorOpts{
+		host:     host(t, topoMap, "testhost2"),
+		response: &testFetchTaggedSuccessResponse,
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, done)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"github.com/m3db/m3/src/dbnode/encoding"
+)
+
+type fetchTaggedShardsFn func(
+	shards []uint32,
+) (encoding.SeriesIterators, bool, error)
+
+// seriesIteratorStream returns series to the caller a batch of shards at a
+// time, only the series of the current batch are held at any time. Each batch
+// is only returned once all of its shards have met the read consistency level
+// and the replicas of each series have been merged.
+type seriesIteratorStream struct {
+	fetchShardsFn  fetchTaggedShardsFn
+	shards         []uint32
+	shardBatchSize int
+	limit          int
+	count          int
+
+	batch    encoding.SeriesIterators
+	batchIdx int
+	current  encoding.SeriesIterator
+
+	// batchesExhaustive is whether every batch fetched so far returned all of
+	// the series of its shards.
+	batchesExhaustive bool
+	complete          bool
+	done              bool
+	err               error
+}
+
+// make the compiler ensure the concrete type `&seriesIteratorStream{}` implements
+// the `SeriesIteratorStream` interface.
+var _ SeriesIteratorStream = &seriesIteratorStream{}
+
+func newSeriesIteratorStream(
+	fetchShardsFn fetchTaggedShardsFn,
+	shards []uint32,
+	shardBatchSize int,
+	limit int,
+) *seriesIteratorStream {
+	return &seriesIteratorStream{
+		fetchShardsFn:     fetchShardsFn,
+		shards:            shards,
+		shardBatchSize:    shardBatchSize,
+		limit:             limit,
+		batchesExhaustive: true,
+	}
+}
+
+func (s *seriesIteratorStream) Next() bool {
+	s.current = nil
+	if s.done {
+		return false
+	}
+
+	if s.count >= s.limit {
+		// The stream is truncated at the limit so it is not exhaustive.
+		s.closeBatch()
+		s.done = true
+		return false
+	}
+
+	for s.batch == nil || s.batchIdx >= s.batch.Len() {
+		s.closeBatch()
+		if len(s.shards) == 0 {
+			s.complete = true
+			s.done = true
+			return false
+		}
+
+		n := s.shardBatchSize
+		if n > len(s.shards) {
+			n = len(s.shards)
+		}
+		batch, exhaustive, err := s.fetchShardsFn(s.shards[:n])
+		if err != nil {
+			s.err = err
+			s.done = true
+			return false
+		}
+		s.shards = s.shards[n:]
+		s.batch, s.batchIdx = batch, 0
+		s.batchesExhaustive = s.batchesExhaustive && exhaustive
+	}
+
+	// NB: ownership of the series iterator is transferred to the caller so it
+	// must not be closed with the rest of the batch.
+	iters := s.batch.Iters()
+	s.current = iters[s.batchIdx]
+	iters[s.batchIdx] = nil
+	s.batchIdx++
+	s.count++
+	return true
+}
+
+func (s *seriesIteratorStream) Current() encoding.SeriesIterator {
+	return s.current
+}
+
+func (s *seriesIteratorStream) Exhaustive() bool {
+	return s.complete && s.batchesExhaustive
+}
+
+func (s *seriesIteratorStream) Err() error {
+	return s.err
+}
+
+func (s *seriesIteratorStream) Close() {
+	s.current = nil
+	s.closeBatch()
+	s.done = true
+}
+
+// closeBatch closes the series in the current batch not yet returned.
+func (s *seriesIteratorStream) closeBatch() {
+	if s.batch == nil {
+		return
+	}
+	s.batch.Close()
+	s.batch = nil
+}
@@ -0,0 +1,784 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/topology"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	hintFileSuffix       = ".hints"
+	hintReplayFileSuffix = ".replay"
+	hintFilePerm         = 0666
+	hintDirPerm          = 0755
+
+	// hintRecordHeaderLen is the length of the record size and checksum
+	// preceding each hint persisted.
+	hintRecordHeaderLen = 8
+
+	// hintedHandoffReplayInterval is how often hosts with hints are checked
+	// for connectivity so that their hints can be replayed.
+	hintedHandoffReplayInterval = 10 * time.Second
+
+	// hintedHandoffFlushInterval is how often hints buffered in memory are
+	// appended to the hint files.
+	hintedHandoffFlushInterval = time.Second
+)
+
+var (
+	errHintChecksumMismatch = errors.New("hint checksum mismatch")
+	errHintTruncated        = errors.New("hint truncated")
+	errHintedHandoffClosed  = errors.New("hinted handoff closed")
+)
+
+// hint is a write that failed on a replica to be replayed once the replica's
+// host is reachable again.
+type hint struct {
+	createdAt   int64
+	tagged      bool
+	namespace   []byte
+	id          []byte
+	encodedTags []byte
+	timestamp   int64
+	timeType    rpc.TimeType
+	value       float64
+	annotation  []byte
+}
+
+// datapointNanos returns the timestamp of the hint's datapoint in nanoseconds,
+// or zero if the hint's time type is invalid.
+func (value hint) datapointNanos() int64 {
+	t, err := convert.ToTime(value.timestamp, value.timeType)
+	if err != nil {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// hintReplayFn replays hints, returning the hints that failed to replay.
+type hintReplayFn func(hints []hint) []hint
+
+// hintOwnedFn returns whether the host a hint is replayed to still owns the
+// shard of the hint's series.
+type hintOwnedFn func(value hint) bool
+
+type hintedHandoffMetrics struct {
+	stored          tally.Counter
+	droppedSize     tally.Counter
+	droppedError    tally.Counter
+	droppedNotOwned tally.Counter
+	droppedRejected tally.Counter
+	expired         tally.Counter
+	corrupt         tally.Counter
+	replayed        tally.Counter
+	replayErrors    tally.Counter
+}
+
+func newHintedHandoffMetrics(scope tally.Scope) hintedHandoffMetrics {
+	return hintedHandoffMetrics{
+		stored: scope.Counter("stored"),
+		droppedSize: scope.Tagged(map[string]string{
+			"reason": "size",
+		}).Counter("dropped"),
+		droppedError: scope.Tagged(map[string]string{
+			"reason": "error",
+		}).Counter("dropped"),
+		droppedNotOwned: scope.Tagged(map[string]string{
+			"reason": "not-owned",
+		}).Counter("dropped"),
+		droppedRejected: scope.Tagged(map[string]string{
+			"reason": "rejected",
+		}).Counter("dropped"),
+		expired:      scope.Counter("expired"),
+		corrupt:      scope.Counter("corrupt"),
+		replayed:     scope.Counter("replayed"),
+		replayErrors: scope.Counter("replay-errors"),
+	}
+}
+
+type hintFile struct {
+	fd   *os.File
+	size int64
+}
+
+// hintedHandoff persists hints for writes that failed on a replica to local
+// disk, one file per host, bounded by age and size.
+type hintedHandoff struct {
+	sync.Mutex
+
+	dir         string
+	maxAge      time.Duration
+	maxBytes    int64
+	batchSize   int
+	replayRate  int
+	nowFn       clock.NowFn
+	sleepFn     func(time.Duration)
+	hosts       map[string]*hintFile
+	buf         []byte
+	metrics     hintedHandoffMetrics
+	closed      bool
+	flushDoneCh chan struct{}
+	flushWg     sync.WaitGroup
+
+	// pending holds the encoded hints of each host yet to be appended to the
+	// host's hint file, it has its own lock so that adding a hint from a
+	// write's completion never waits on disk.
+	pendingLock   sync.Mutex
+	pending       map[string][]byte
+	pendingClosed bool
+}
+
+func newHintedHandoff(opts Options) (*hintedHandoff, error) {
+	dir := opts.HintedHandoffDirectory()
+	if err := os.MkdirAll(dir, hintDirPerm); err != nil {
+		return nil, err
+	}
+
+	scope := opts.InstrumentOptions().MetricsScope().SubScope("hinted-handoff")
+	h := &hintedHandoff{
+		dir:         dir,
+		maxAge:      opts.HintedHandoffMaxAge(),
+		maxBytes:    opts.HintedHandoffMaxBytes(),
+		batchSize:   opts.HintedHandoffReplayBatchSize(),
+		replayRate:  opts.HintedHandoffReplayRate(),
+		nowFn:       opts.ClockOptions().NowFn(),
+		sleepFn:     time.Sleep,
+		hosts:       make(map[string]*hintFile),
+		metrics:     newHintedHandoffMetrics(scope),
+		flushDoneCh: make(chan struct{}),
+		pending:     make(map[string][]byte),
+	}
+
+	// Pick up any hints persisted before a restart.
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, hintFileSuffix) {
+			continue
+		}
+		hostID, err := url.PathUnescape(strings.TrimSuffix(name, hintFileSuffix))
+		if err != nil {
+			continue
+		}
+		h.hosts[hostID] = &hintFile{size: info.Size()}
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, hintReplayFileSuffix) {
+			continue
+		}
+		hostID, err := url.PathUnescape(strings.TrimSuffix(name, hintReplayFileSuffix))
+		if err != nil {
+			continue
+		}
+		if _, ok := h.hosts[hostID]; !ok {
+			// NB: a replay was interrupted, register the host so that the
+			// remaining hints are replayed.
+			h.hosts[hostID] = &hintFile{}
+		}
+	}
+
+	h.flushWg.Add(1)
+	go h.flushLoop()
+
+	return h, nil
+}
+
+func (h *hintedHandoff) hintFilePath(hostID string) string {
+	return filepath.Join(h.dir, url.PathEscape(hostID)+hintFileSuffix)
+}
+
+func (h *hintedHandoff) hintReplayFilePath(hostID string) string {
+	return filepath.Join(h.dir, url.PathEscape(hostID)+hintReplayFileSuffix)
+}
+
+// Add buffers a hint for a write that failed on the given host, buffered
+// hints are appended to the host's hint file in the background.
+func (h *hintedHandoff) Add(hostID string, value hint) {
+	if value.createdAt == 0 {
+		value.createdAt = h.nowFn().UnixNano()
+	}
+
+	h.pendingLock.Lock()
+	defer h.pendingLock.Unlock()
+
+	if h.pendingClosed {
+		h.metrics.droppedError.Inc(1)
+		return
+	}
+
+	buf := h.pending[hostID]
+	n := len(buf)
+	buf = encodeHintRecord(buf, value)
+	if int64(len(buf)) > h.maxBytes {
+		// NB: bound the hints buffered for a host, the size of the host's
+		// hint file is checked as they are appended to it.
+		h.metrics.droppedSize.Inc(1)
+		buf = buf[:n]
+	}
+	h.pending[hostID] = buf
+}
+
+func (h *hintedHandoff) flushLoop() {
+	defer h.flushWg.Done()
+
+	ticker := time.NewTicker(hintedHandoffFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.flushDoneCh:
+			return
+		case <-ticker.C:
+		}
+
+		h.Lock()
+		h.flushWithLock()
+		h.Unlock()
+	}
+}
+
+// flushWithLock appends the hints buffered for each host to its hint file.
+func (h *hintedHandoff) flushWithLock() {
+	h.pendingLock.Lock()
+	pending := h.pending
+	h.pending = make(map[string][]byte, len(pending))
+	h.pendingLock.Unlock()
+
+	for hostID, records := range pending {
+		if err := h.appendWithLock(hostID, records); err != nil {
+			h.metrics.droppedError.Inc(int64(numHintRecords(records)))
+		}
+	}
+}
+
+// appendWithLock appends encoded hints to the hint file of a host, hints that
+// would grow the file beyond the max bytes are discarded.
+func (h *hintedHandoff) appendWithLock(hostID string, records []byte) error {
+	if h.closed {
+		return errHintedHandoffClosed
+	}
+
+	file, ok := h.hosts[hostID]
+	if !ok {
+		file = &hintFile{}
+		h.hosts[hostID] = file
+	}
+
+	var n, stored int
+	for n < len(records) {
+		size := hintRecordLen(records[n:])
+		if file.size+int64(n+size) > h.maxBytes {
+			break
+		}
+		n += size
+		stored++
+	}
+	if dropped := numHintRecords(records[n:]); dropped > 0 {
+		h.metrics.droppedSize.Inc(int64(dropped))
+	}
+	if n == 0 {
+		return nil
+	}
+
+	if file.fd == nil {
+		fd, err := os.OpenFile(h.hintFilePath(hostID),
+			os.O_CREATE|os.O_WRONLY|os.O_APPEND, hintFilePerm)
+		if err != nil {
+			return err
+		}
+		file.fd = fd
+	}
+
+	written, err := file.fd.Write(records[:n])
+	file.size += int64(written)
+	if err != nil {
+		return err
+	}
+
+	h.metrics.stored.Inc(int64(stored))
+	return nil
+}
+
+// HostIDs returns the hosts that have hints pending replay.
+func (h *hintedHandoff) HostIDs() []string {
+	h.Lock()
+	defer h.Unlock()
+
+	h.flushWithLock()
+	hostIDs := make([]string, 0, len(h.hosts))
+	for hostID := range h.hosts {
+		hostIDs = append(hostIDs, hostID)
+	}
+	return hostIDs
+}
+
+// Replay replays the hints for a host in batches at a bounded rate, hints for
+// shards the host no longer owns are dropped and any hints that fail to replay
+// are persisted again to be replayed later.
+func (h *hintedHandoff) Replay(hostID string, ownedFn hintOwnedFn, fn hintReplayFn) error {
+	replayPath := h.hintReplayFilePath(hostID)
+
+	h.Lock()
+	h.flushWithLock()
+	file, ok := h.hosts[hostID]
+	if !ok {
+		h.Unlock()
+		return nil
+	}
+	delete(h.hosts, hostID)
+
+	var err error
+	if file.fd != nil {
+		err = file.fd.Close()
+	}
+	if _, statErr := os.Stat(replayPath); os.IsNotExist(statErr) && err == nil {
+		// NB: only take the current hints if there are no hints left over
+		// from an interrupted replay, they will be taken by the next replay.
+		err = os.Rename(h.hintFilePath(hostID), replayPath)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	} else if err == nil {
+		// Keep track of the current hints for the next replay.
+		h.hosts[hostID] = &hintFile{size: file.size}
+	}
+	h.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.Open(replayPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var (
+		reader    = bufio.NewReader(fd)
+		minAge    = h.nowFn().Add(-h.maxAge).UnixNano()
+		batch     = make([]hint, 0, h.batchSize)
+		records   []byte
+		abandoned bool
+	)
+	replayBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		failed := batch
+		if !abandoned {
+			start := h.nowFn()
+			failed = fn(batch)
+			h.metrics.replayed.Inc(int64(len(batch) - len(failed)))
+			h.metrics.replayErrors.Inc(int64(len(failed)))
+			// NB: stop replaying once a whole batch fails, the host is likely
+			// unreachable again and the remaining hints are kept as is.
+			abandoned = len(failed) == len(batch)
+
+			// Bound the rate hints are replayed at.
+			expected := time.Duration(len(batch)) * time.Second / time.Duration(h.replayRate)
+			if wait := expected - h.nowFn().Sub(start); wait > 0 {
+				h.sleepFn(wait)
+			}
+		}
+
+		if len(failed) > 0 {
+			records = records[:0]
+			for _, value := range failed {
+				records = encodeHintRecord(records, value)
+			}
+			h.Lock()
+			if err := h.appendWithLock(hostID, records); err != nil {
+				h.metrics.droppedError.Inc(int64(len(failed)))
+			}
+			h.Unlock()
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		value, err := readHintRecord(reader, h.maxBytes)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// The remainder of the file cannot be trusted.
+			h.metrics.corrupt.Inc(1)
+			break
+		}
+		if value.createdAt < minAge || value.datapointNanos() < minAge {
+			// NB: replicas reject datapoints older than the namespace's buffer
+			// past, which the max age should not exceed.
+			h.metrics.expired.Inc(1)
+			continue
+		}
+		if !abandoned && !ownedFn(value) {
+			h.metrics.droppedNotOwned.Inc(1)
+			continue
+		}
+
+		batch = append(batch, value)
+		if len(batch) >= h.batchSize {
+			replayBatch()
+		}
+	}
+	replayBatch()
+
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	return os.Remove(replayPath)
+}
+
+// Close closes any open hint files, hints remain on disk to be replayed once
+// a session is opened again.
+func (h *hintedHandoff) Close() error {
+	h.pendingLock.Lock()
+	if h.pendingClosed {
+		h.pendingLock.Unlock()
+		return errHintedHandoffClosed
+	}
+	h.pendingClosed = true
+	h.pendingLock.Unlock()
+
+	close(h.flushDoneCh)
+	h.flushWg.Wait()
+
+	h.Lock()
+	defer h.Unlock()
+
+	// Persist the hints still buffered before closing the hint files.
+	h.flushWithLock()
+
+	h.closed = true
+	var firstErr error
+	for _, file := range h.hosts {
+		if file.fd == nil {
+			continue
+		}
+		if err := file.fd.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		file.fd = nil
+	}
+	return firstErr
+}
+
+// hintRecordLen returns the length of the encoded hint at the start of the
+// given bytes, which must hold complete hints.
+func hintRecordLen(records []byte) int {
+	return hintRecordHeaderLen + int(binary.BigEndian.Uint32(records[0:4]))
+}
+
+// numHintRecords returns the number of encoded hints in the given bytes,
+// which must hold complete hints.
+func numHintRecords(records []byte) int {
+	n := 0
+	for len(records) > 0 {
+		records = records[hintRecordLen(records):]
+		n++
+	}
+	return n
+}
+
+// readHintRecord reads the next hint from the reader, it returns io.EOF once
+// there are no more hints. The returned hint references newly allocated bytes.
+func readHintRecord(r *bufio.Reader, maxSize int64) (hint, error) {
+	var header [hintRecordHeaderLen]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return hint{}, errHintTruncated
+		}
+		return hint{}, err
+	}
+
+	size := int64(binary.BigEndian.Uint32(header[0:4]))
+	if hintRecordHeaderLen+size > maxSize {
+		// No hint this large could have been persisted.
+		return hint{}, errHintTruncated
+	}
+
+	record := make([]byte, hintRecordHeaderLen+size)
+	copy(record, header[:])
+	if _, err := io.ReadFull(r, record[hintRecordHeaderLen:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return hint{}, errHintTruncated
+		}
+		return hint{}, err
+	}
+
+	value, _, err := decodeHintRecord(record)
+	return value, err
+}
+
+func encodeHintRecord(buf []byte, value hint) []byte {
+	buf = append(buf, make([]byte, hintRecordHeaderLen)...)
+
+	var scratch [binary.MaxVarintLen64]byte
+	appendUvarint := func(v uint64) {
+		n := binary.PutUvarint(scratch[:], v)
+		buf = append(buf, scratch[:n]...)
+	}
+	appendVarint := func(v int64) {
+		n := binary.PutVarint(scratch[:], v)
+		buf = append(buf, scratch[:n]...)
+	}
+	appendBytes := func(b []byte) {
+		appendUvarint(uint64(len(b)))
+		buf = append(buf, b...)
+	}
+
+	appendVarint(value.createdAt)
+	if value.tagged {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	appendBytes(value.namespace)
+	appendBytes(value.id)
+	appendBytes(value.encodedTags)
+	appendVarint(value.timestamp)
+	appendVarint(int64(value.timeType))
+	appendUvarint(math.Float64bits(value.value))
+	appendBytes(value.annotation)
+
+	payload := buf[hintRecordHeaderLen:]
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], digest.Checksum(payload))
+	return buf
+}
+
+// decodeHintRecord decodes a hint, the returned hint references the provided
+// bytes, and returns the number of bytes consumed.
+func decodeHintRecord(data []byte) (hint, int, error) {
+	if len(data) < hintRecordHeaderLen {
+		return hint{}, 0, errHintTruncated
+	}
+
+	size := int(binary.BigEndian.Uint32(data[0:4]))
+	checksum := binary.BigEndian.Uint32(data[4:8])
+	if len(data)-hintRecordHeaderLen < size {
+		return hint{}, 0, errHintTruncated
+	}
+
+	payload := data[hintRecordHeaderLen : hintRecordHeaderLen+size]
+	if digest.Checksum(payload) != checksum {
+		return hint{}, 0, errHintChecksumMismatch
+	}
+
+	var (
+		r     = bytes.NewReader(payload)
+		value hint
+		err   error
+	)
+	readBytes := func() []byte {
+		if err != nil {
+			return nil
+		}
+		var n uint64
+		n, err = binary.ReadUvarint(r)
+		if err != nil {
+			return nil
+		}
+		if n > uint64(r.Len()) {
+			err = errHintTruncated
+			return nil
+		}
+		start := len(payload) - r.Len()
+		b := payload[start : start+int(n)]
+		_, err = r.Seek(int64(n), io.SeekCurrent)
+		return b
+	}
+	readVarint := func() int64 {
+		if err != nil {
+			return 0
+		}
+		var v int64
+		v, err = binary.ReadVarint(r)
+		return v
+	}
+
+	value.createdAt = readVarint()
+	if err == nil {
+		var tagged byte
+		tagged, err = r.ReadByte()
+		value.tagged = tagged == 1
+	}
+	value.namespace = readBytes()
+	value.id = readBytes()
+	value.encodedTags = readBytes()
+	value.timestamp = readVarint()
+	value.timeType = rpc.TimeType(readVarint())
+	if err == nil {
+		var bits uint64
+		bits, err = binary.ReadUvarint(r)
+		value.value = math.Float64frombits(bits)
+	}
+	value.annotation = readBytes()
+	if err != nil {
+		return hint{}, 0, fmt.Errorf("could not decode hint: %v", err)
+	}
+
+	return value, hintRecordHeaderLen + size, nil
+}
+
+func (s *session) replayHintsLoop(hints *hintedHandoff, doneCh chan struct{}) {
+	ticker := time.NewTicker(hintedHandoffReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+		}
+		s.replayHints(hints)
+	}
+}
+
+// replayHints replays the hints for each host that has reconnected.
+func (s *session) replayHints(hints *hintedHandoff) {
+	for _, hostID := range hints.HostIDs() {
+		s.state.RLock()
+		queue, ok := s.state.queuesByHostID[hostID]
+		topoMap := s.state.topoMap
+		s.state.RUnlock()
+		if !ok || queue.ConnectionCount() == 0 {
+			continue
+		}
+
+		err := hints.Replay(hostID, func(value hint) bool {
+			return hostOwnsShard(topoMap, hostID, value.id)
+		}, func(values []hint) []hint {
+			return s.replayHintsToQueue(hints, queue, values)
+		})
+		if err != nil {
+			s.log.Errorf("could not replay hints for host %s: %v", hostID, err)
+		}
+	}
+}
+
+// hostOwnsShard returns whether a host owns the shard of a series, hints for
+// shards that have moved off the host are dropped rather than replayed.
+func hostOwnsShard(topoMap topology.Map, hostID string, id []byte) bool {
+	hostShardSet, ok := topoMap.LookupHostShardSet(hostID)
+	if !ok {
+		return false
+	}
+	shardID := topoMap.ShardSet().Lookup(ident.BytesID(id))
+	_, err := hostShardSet.ShardSet().LookupStateByID(shardID)
+	return err == nil
+}
+
+// replayHintsToQueue replays hints to a host queue and returns the hints that
+// failed to replay, hints that the replica rejects are dropped since replaying
+// them again would be rejected too.
+func (s *session) replayHintsToQueue(
+	hints *hintedHandoff,
+	queue hostQueue,
+	values []hint,
+) []hint {
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		failed []hint
+	)
+	onFailure := func(value hint) {
+		lock.Lock()
+		failed = append(failed, value)
+		lock.Unlock()
+	}
+
+	for i := range values {
+		value := values[i]
+
+		var op writeOp
+		if value.tagged {
+			wop := s.pools.writeTaggedOperation.Get()
+			wop.namespace = ident.BytesID(value.namespace)
+			wop.request.ID = value.id
+			wop.request.EncodedTags = value.encodedTags
+			wop.request.Datapoint.Value = value.value
+			wop.request.Datapoint.Timestamp = value.timestamp
+			wop.request.Datapoint.TimestampTimeType = value.timeType
+			wop.request.Datapoint.Annotation = value.annotation
+			op = wop
+		} else {
+			wop := s.pools.writeOperation.Get()
+			wop.namespace = ident.BytesID(value.namespace)
+			wop.request.ID = value.id
+			wop.request.Datapoint.Value = value.value
+			wop.request.Datapoint.Timestamp = value.timestamp
+			wop.request.Datapoint.TimestampTimeType = value.timeType
+			wop.request.Datapoint.Annotation = value.annotation
+			op = wop
+		}
+
+		wg.Add(1)
+		op.SetCompletionFn(func(_ interface{}, err error) {
+			if IsBadRequestError(err) || xerrors.IsNonRetryableError(err) {
+				hints.metrics.droppedRejected.Inc(1)
+			} else if err != nil {
+				onFailure(value)
+			}
+			op.Close()
+			wg.Done()
+		})
+		if err := queue.Enqueue(op); err != nil {
+			op.Close()
+			onFailure(value)
+			wg.Done()
+		}
+	}
+
+	wg.Wait()
+	return failed
+}
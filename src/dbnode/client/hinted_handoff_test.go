@@ -0,0 +1,383 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/pool"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testHintTime = time.Now().Truncate(time.Second)
+
+func newTestHint(i int) hint {
+	return hint{
+		tagged:      i%2 == 0,
+		namespace:   []byte("testNs"),
+		id:          []byte(fmt.Sprintf("id%d", i)),
+		encodedTags: []byte(fmt.Sprintf("tags%d", i)),
+		timestamp:   testHintTime.Unix() + int64(i),
+		timeType:    rpc.TimeType_UNIX_SECONDS,
+		value:       float64(i) + 0.5,
+		annotation:  []byte(fmt.Sprintf("annotation%d", i)),
+	}
+}
+
+func ownsAllHints(hint) bool {
+	return true
+}
+
+func newTestHintedHandoff(
+	t *testing.T,
+	dir string,
+	nowFn clock.NowFn,
+	maxBytes int64,
+) *hintedHandoff {
+	opts := NewOptions().
+		SetHintedHandoffDirectory(dir).
+		SetHintedHandoffMaxAge(time.Hour).
+		SetHintedHandoffMaxBytes(maxBytes).
+		SetClockOptions(clock.NewOptions().SetNowFn(nowFn))
+	hints, err := newHintedHandoff(opts)
+	require.NoError(t, err)
+	return hints
+}
+
+func TestHintRecordEncodeDecode(t *testing.T) {
+	var buf []byte
+	for i := 0; i < 3; i++ {
+		value := newTestHint(i)
+		value.createdAt = int64(i + 1)
+		buf = encodeHintRecord(buf, value)
+	}
+
+	for i := 0; i < 3; i++ {
+		value, n, err := decodeHintRecord(buf)
+		require.NoError(t, err)
+
+		expected := newTestHint(i)
+		expected.createdAt = int64(i + 1)
+		assert.Equal(t, expected, value)
+		buf = buf[n:]
+	}
+	assert.Equal(t, 0, len(buf))
+}
+
+func TestHintRecordDecodeCorrupt(t *testing.T) {
+	buf := encodeHintRecord(nil, newTestHint(1))
+
+	_, _, err := decodeHintRecord(buf[:len(buf)-1])
+	assert.Equal(t, errHintTruncated, err)
+
+	buf[len(buf)-1]++
+	_, _, err = decodeHintRecord(buf)
+	assert.Equal(t, errHintChecksumMismatch, err)
+}
+
+func TestHintedHandoffReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	hints := newTestHintedHandoff(t, dir, func() time.Time { return now }, 1<<20)
+	for i := 0; i < 4; i++ {
+		hints.Add("testhost0", newTestHint(i))
+	}
+	assert.Equal(t, []string{"testhost0"}, hints.HostIDs())
+
+	// Fail to replay the first hint, it should be replayed again later.
+	var replayed []hint
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return values[:1]
+	}))
+	require.Equal(t, 4, len(replayed))
+	for i, value := range replayed {
+		assert.Equal(t, newTestHint(i).id, value.id)
+	}
+	assert.Equal(t, []string{"testhost0"}, hints.HostIDs())
+
+	replayed = nil
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 1, len(replayed))
+	assert.Equal(t, newTestHint(0).id, replayed[0].id)
+	assert.Equal(t, 0, len(hints.HostIDs()))
+
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffReplayAfterRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hints := newTestHintedHandoff(t, dir, time.Now, 1<<20)
+	hints.Add("test/host:9000", newTestHint(1))
+	require.NoError(t, hints.Close())
+
+	hints = newTestHintedHandoff(t, dir, time.Now, 1<<20)
+	assert.Equal(t, []string{"test/host:9000"}, hints.HostIDs())
+
+	var replayed []hint
+	require.NoError(t, hints.Replay("test/host:9000", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 1, len(replayed))
+	assert.Equal(t, newTestHint(1).id, replayed[0].id)
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffDiscardsExpiredHints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	hints := newTestHintedHandoff(t, dir, func() time.Time { return now }, 1<<20)
+	hints.Add("testhost0", newTestHint(1))
+	now = now.Add(2 * time.Hour)
+	value := newTestHint(2)
+	value.timestamp = now.Unix()
+	hints.Add("testhost0", value)
+
+	var replayed []hint
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 1, len(replayed))
+	assert.Equal(t, newTestHint(2).id, replayed[0].id)
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffDiscardsHintsForExpiredDatapoints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	hints := newTestHintedHandoff(t, dir, func() time.Time { return now }, 1<<20)
+
+	// Replicas reject datapoints older than the buffer past, a recent hint
+	// for an old datapoint is discarded too.
+	value := newTestHint(1)
+	value.timestamp = now.Add(-2 * time.Hour).Unix()
+	hints.Add("testhost0", value)
+	hints.Add("testhost0", newTestHint(2))
+
+	var replayed []hint
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 1, len(replayed))
+	assert.Equal(t, newTestHint(2).id, replayed[0].id)
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffDiscardsHintsOverMaxBytes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	value := newTestHint(1)
+	value.createdAt = now.UnixNano()
+	maxBytes := int64(len(encodeHintRecord(nil, value)))
+	hints := newTestHintedHandoff(t, dir, func() time.Time { return now }, maxBytes)
+	hints.Add("testhost0", newTestHint(1))
+	hints.Add("testhost0", newTestHint(2))
+
+	var replayed []hint
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 1, len(replayed))
+	assert.Equal(t, newTestHint(1).id, replayed[0].id)
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffAddBuffersHints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hints := newTestHintedHandoff(t, dir, time.Now, 1<<20)
+	hints.Add("testhost0", newTestHint(1))
+
+	// Adding a hint only buffers it, it is appended to the hint file when
+	// the buffered hints are flushed.
+	_, err = os.Stat(hints.hintFilePath("testhost0"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Equal(t, []string{"testhost0"}, hints.HostIDs())
+	_, err = os.Stat(hints.hintFilePath("testhost0"))
+	assert.NoError(t, err)
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffReplayBatchesAtRate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	hints := newTestHintedHandoff(t, dir, func() time.Time { return now }, 1<<20)
+	hints.batchSize = 2
+	hints.replayRate = 2
+	var slept []time.Duration
+	hints.sleepFn = func(d time.Duration) {
+		slept = append(slept, d)
+	}
+	for i := 0; i < 5; i++ {
+		hints.Add("testhost0", newTestHint(i))
+	}
+
+	var batches [][]hint
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		batches = append(batches, append([]hint(nil), values...))
+		return nil
+	}))
+	require.Equal(t, 3, len(batches))
+	assert.Equal(t, 2, len(batches[0]))
+	assert.Equal(t, 2, len(batches[1]))
+	assert.Equal(t, 1, len(batches[2]))
+	assert.Equal(t, newTestHint(4).id, batches[2][0].id)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, 500 * time.Millisecond}, slept)
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffReplayDropsHintsNotOwned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hints := newTestHintedHandoff(t, dir, time.Now, 1<<20)
+	for i := 0; i < 4; i++ {
+		hints.Add("testhost0", newTestHint(i))
+	}
+
+	var replayed []hint
+	require.NoError(t, hints.Replay("testhost0", func(value hint) bool {
+		return value.tagged
+	}, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 2, len(replayed))
+	assert.Equal(t, newTestHint(0).id, replayed[0].id)
+	assert.Equal(t, newTestHint(2).id, replayed[1].id)
+	assert.Equal(t, 0, len(hints.HostIDs()))
+	require.NoError(t, hints.Close())
+}
+
+func TestHintedHandoffReplayStopsAfterFailedBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hints := newTestHintedHandoff(t, dir, time.Now, 1<<20)
+	hints.batchSize = 2
+	for i := 0; i < 5; i++ {
+		hints.Add("testhost0", newTestHint(i))
+	}
+
+	// Once a whole batch fails the remaining hints are kept without being
+	// replayed.
+	attempts := 0
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		attempts++
+		return values
+	}))
+	assert.Equal(t, 1, attempts)
+
+	var replayed []hint
+	require.NoError(t, hints.Replay("testhost0", ownsAllHints, func(values []hint) []hint {
+		replayed = append(replayed, values...)
+		return nil
+	}))
+	require.Equal(t, 5, len(replayed))
+	for i, value := range replayed {
+		assert.Equal(t, newTestHint(i).id, value.id)
+	}
+	require.NoError(t, hints.Close())
+}
+
+func TestReplayHintsToQueueDropsRejectedHints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "hints")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hints := newTestHintedHandoff(t, dir, time.Now, 1<<20)
+	defer hints.Close()
+
+	s := newDefaultTestSession(t).(*session)
+	s.pools.writeOperation = newWriteOperationPool(pool.NewObjectPoolOptions())
+	s.pools.writeOperation.Init()
+	s.pools.writeTaggedOperation = newWriteTaggedOpPool(pool.NewObjectPoolOptions())
+	s.pools.writeTaggedOperation.Init()
+
+	errs := []error{
+		tterrors.NewBadRequestError(errors.New("bad request")),
+		xerrors.NewNonRetryableError(errors.New("non retryable")),
+		tterrors.NewInternalError(errors.New("internal")),
+		nil,
+	}
+	queue := NewMockhostQueue(ctrl)
+	enqueued := 0
+	queue.EXPECT().Enqueue(gomock.Any()).Do(func(op op) error {
+		op.CompletionFn()(nil, errs[enqueued])
+		enqueued++
+		return nil
+	}).Return(nil).Times(len(errs))
+
+	values := make([]hint, 0, len(errs))
+	for i := range errs {
+		values = append(values, newTestHint(i))
+	}
+
+	// Only the hint that failed with a retryable error is replayed again.
+	failed := s.replayHintsToQueue(hints, queue, values)
+	require.Equal(t, 1, len(failed))
+	assert.Equal(t, newTestHint(2).id, failed[0].id)
+}
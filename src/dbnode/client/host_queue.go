@@ -46,6 +46,7 @@ type queue struct {
 	nowFn                                      clock.NowFn
 	host                                       topology.Host
 	connPool                                   connectionPool
+	fetchLatencies                             *fetchLatencyTracker
 	writeBatchRawRequestPool                   writeBatchRawRequestPool
 	writeBatchRawRequestElementArrayPool       writeBatchRawRequestElementArrayPool
 	writeTaggedBatchRawRequestPool             writeTaggedBatchRawRequestPool
@@ -107,6 +108,7 @@ func newHostQueue(
 		nowFn:                                      opts.ClockOptions().NowFn(),
 		host:                                       host,
 		connPool:                                   newConnectionPool(host, opts),
+		fetchLatencies:                             newFetchLatencyTracker(opts.ClockOptions().NowFn()),
 		writeBatchRawRequestPool:                   hostQueueOpts.writeBatchRawRequestPool,
 		writeBatchRawRequestElementArrayPool:       hostQueueOpts.writeBatchRawRequestElementArrayPool,
 		writeTaggedBatchRawRequestPool:             hostQueueOpts.writeTaggedBatchRawRequestPool,
@@ -259,6 +261,8 @@ func (q *queue) drain() {
 				q.asyncFetchTagged(v)
 			case *truncateOp:
 				q.asyncTruncate(v)
+			case *aggregateOp:
+				q.asyncAggregate(v)
 			default:
 				completionFn := ops[i].CompletionFn()
 				completionFn(nil, errQueueUnknownOperation(q.host.ID()))
@@ -481,6 +485,18 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 			q.Done()
 		}
 
+		req, ok := op.requestForHost(q.host.ID())
+		if !ok {
+			// Host is marked as having returned all of its results for the
+			// paginated request already, it has no more results to return.
+			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{
+				host:     q.host,
+				response: &rpc.FetchTaggedResult_{Exhaustive: true},
+			}, nil)
+			cleanup()
+			return
+		}
+
 		client, err := q.connPool.NextClient()
 		if err != nil {
 			// No client available
@@ -489,8 +505,10 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 			return
 		}
 
+		start := q.nowFn()
 		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
-		result, err := client.FetchTagged(ctx, &op.request)
+		result, err := client.FetchTagged(ctx, req)
+		q.fetchLatencies.Record(q.nowFn().Sub(start))
 		if err != nil {
 			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host}, err)
 			cleanup()
@@ -505,6 +523,31 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 	})
 }
 
+func (q *queue) asyncAggregate(op *aggregateOp) {
+	q.Add(1)
+
+	q.workerPool.Go(func() {
+		cleanup := q.Done
+
+		client, err := q.connPool.NextClient()
+		if err != nil {
+			// No client available
+			op.completionFn(aggregateResultAccumulatorOpts{host: q.host}, err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		res, err := client.Aggregate(ctx, &op.request)
+		op.completionFn(aggregateResultAccumulatorOpts{
+			host:     q.host,
+			response: res,
+		}, err)
+
+		cleanup()
+	})
+}
+
 func (q *queue) asyncTruncate(op *truncateOp) {
 	q.Add(1)
 
@@ -600,6 +643,10 @@ func (q *queue) BorrowConnection(fn withConnectionFn) error {
 	return nil
 }
 
+func (q *queue) FetchLatency(percentile float64) (time.Duration, bool) {
+	return q.fetchLatencies.Percentile(percentile)
+}
+
 func (q *queue) Close() {
 	q.Lock()
 	if q.status != statusOpen {
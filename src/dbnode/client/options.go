@@ -88,12 +88,42 @@ const (
 	// defaultWriteTaggedOpPoolSize is the default write tagged op pool size
 	defaultWriteTaggedOpPoolSize = 65536
 
+	// defaultAsyncWriteMaxPending is the default maximum number of pending
+	// asynchronous writes, it matches the write op pool sizes so that pending
+	// asynchronous writes do not exhaust the pools
+	defaultAsyncWriteMaxPending = 65536
+
+	// defaultHintedHandoffMaxAge is the default maximum age of a hint, it
+	// matches the default namespace buffer past since replicas reject writes
+	// for datapoints older than the buffer past
+	defaultHintedHandoffMaxAge = 10 * time.Minute
+
+	// defaultHintedHandoffMaxBytes is the default maximum size of the hints
+	// persisted for a single host
+	defaultHintedHandoffMaxBytes = 256 * 1024 * 1024
+
+	// defaultHintedHandoffReplayBatchSize is the default number of hints
+	// replayed to a host at a time
+	defaultHintedHandoffReplayBatchSize = 1024
+
+	// defaultHintedHandoffReplayRate is the default maximum number of hints
+	// replayed to a host per second
+	defaultHintedHandoffReplayRate = 10000
+
 	// defaultFetchBatchOpPoolSize is the default fetch op pool size
 	defaultFetchBatchOpPoolSize = 8192
 
 	// defaultFetchBatchSize is the default fetch batch size
 	defaultFetchBatchSize = 128
 
+	// defaultFetchTaggedStreamShardBatchSize is the default number of shards
+	// whose series are fetched at a time by a streaming fetch tagged
+	defaultFetchTaggedStreamShardBatchSize = 16
+
+	// defaultFetchHedgePercentile is the default fetch hedge percentile,
+	// hedged fetches are disabled by default
+	defaultFetchHedgePercentile = 0
+
 	// defaultCheckedBytesWrapperPoolSize is the default checkedBytesWrapperPoolSize
 	defaultCheckedBytesWrapperPoolSize = 65536
 
@@ -178,8 +208,15 @@ var (
 			SetJitter(true),
 	)
 
-	errNoTopologyInitializerSet    = errors.New("no topology initializer set")
-	errNoReaderIteratorAllocateSet = errors.New("no reader iterator allocator set, encoding not set")
+	errNoTopologyInitializerSet        = errors.New("no topology initializer set")
+	errNoReaderIteratorAllocateSet     = errors.New("no reader iterator allocator set, encoding not set")
+	errAsyncWriteMaxPendingInvalid     = errors.New("async write max pending must be positive")
+	errFetchTaggedStreamShardBatchSize = errors.New("fetch tagged stream shard batch size must be positive")
+	errHintedHandoffMaxAgeInvalid      = errors.New("hinted handoff max age must be positive")
+	errHintedHandoffMaxBytes           = errors.New("hinted handoff max bytes must be positive")
+	errHintedHandoffReplayBatch        = errors.New("hinted handoff replay batch size must be positive")
+	errHintedHandoffReplayRate         = errors.New("hinted handoff replay rate must be positive")
+	errFetchHedgePercentileInvalid     = errors.New("fetch hedge percentile must be between zero and one")
 )
 
 type options struct {
@@ -212,12 +249,21 @@ type options struct {
 	writeRetrier                            xretry.Retrier
 	fetchRetrier                            xretry.Retrier
 	streamBlocksRetrier                     xretry.Retrier
+	streamBlocksProgress                    StreamBlocksProgress
 	readerIteratorAllocate                  encoding.ReaderIteratorAllocate
 	writeOperationPoolSize                  int
 	writeTaggedOperationPoolSize            int
+	asyncWriteMaxPending                    int
+	hintedHandoffDirectory                  string
+	hintedHandoffMaxAge                     time.Duration
+	hintedHandoffMaxBytes                   int64
+	hintedHandoffReplayBatchSize            int
+	hintedHandoffReplayRate                 int
 	fetchBatchOpPoolSize                    int
 	writeBatchSize                          int
 	fetchBatchSize                          int
+	fetchTaggedStreamShardBatchSize         int
+	fetchHedgePercentile                    float64
 	identifierPool                          ident.Pool
 	hostQueueOpsFlushSize                   int
 	hostQueueOpsFlushInterval               time.Duration
@@ -292,11 +338,19 @@ func newOptions() *options {
 		tagDecoderPoolSize:                      defaultTagDecoderPoolSize,
 		tagDecoderOpts:                          serialize.NewTagDecoderOptions(),
 		streamBlocksRetrier:                     defaultStreamBlocksRetrier,
+		streamBlocksProgress:                    NewStreamBlocksProgress(time.Now),
 		writeOperationPoolSize:                  defaultWriteOpPoolSize,
 		writeTaggedOperationPoolSize:            defaultWriteTaggedOpPoolSize,
+		asyncWriteMaxPending:                    defaultAsyncWriteMaxPending,
+		hintedHandoffMaxAge:                     defaultHintedHandoffMaxAge,
+		hintedHandoffMaxBytes:                   defaultHintedHandoffMaxBytes,
+		hintedHandoffReplayBatchSize:            defaultHintedHandoffReplayBatchSize,
+		hintedHandoffReplayRate:                 defaultHintedHandoffReplayRate,
 		fetchBatchOpPoolSize:                    defaultFetchBatchOpPoolSize,
 		writeBatchSize:                          DefaultWriteBatchSize,
 		fetchBatchSize:                          defaultFetchBatchSize,
+		fetchTaggedStreamShardBatchSize:         defaultFetchTaggedStreamShardBatchSize,
+		fetchHedgePercentile:                    defaultFetchHedgePercentile,
 		identifierPool:                          idPool,
 		hostQueueOpsFlushSize:                   defaultHostQueueOpsFlushSize,
 		hostQueueOpsFlushInterval:               defaultHostQueueOpsFlushInterval,
@@ -321,6 +375,27 @@ func (o *options) Validate() error {
 	if o.readerIteratorAllocate == nil {
 		return errNoReaderIteratorAllocateSet
 	}
+	if o.asyncWriteMaxPending <= 0 {
+		return errAsyncWriteMaxPendingInvalid
+	}
+	if o.fetchTaggedStreamShardBatchSize <= 0 {
+		return errFetchTaggedStreamShardBatchSize
+	}
+	if o.fetchHedgePercentile < 0 || o.fetchHedgePercentile > 1 {
+		return errFetchHedgePercentileInvalid
+	}
+	if o.hintedHandoffMaxAge <= 0 {
+		return errHintedHandoffMaxAgeInvalid
+	}
+	if o.hintedHandoffMaxBytes <= 0 {
+		return errHintedHandoffMaxBytes
+	}
+	if o.hintedHandoffReplayBatchSize <= 0 {
+		return errHintedHandoffReplayBatch
+	}
+	if o.hintedHandoffReplayRate <= 0 {
+		return errHintedHandoffReplayRate
+	}
 	if err := topology.ValidateConsistencyLevel(
 		o.writeConsistencyLevel,
 	); err != nil {
@@ -639,6 +714,16 @@ func (o *options) StreamBlocksRetrier() xretry.Retrier {
 	return o.streamBlocksRetrier
 }
 
+func (o *options) SetStreamBlocksProgress(value StreamBlocksProgress) AdminOptions {
+	opts := *o
+	opts.streamBlocksProgress = value
+	return &opts
+}
+
+func (o *options) StreamBlocksProgress() StreamBlocksProgress {
+	return o.streamBlocksProgress
+}
+
 func (o *options) SetWriteOpPoolSize(value int) Options {
 	opts := *o
 	opts.writeOperationPoolSize = value
@@ -659,6 +744,66 @@ func (o *options) WriteTaggedOpPoolSize() int {
 	return o.writeTaggedOperationPoolSize
 }
 
+func (o *options) SetAsyncWriteMaxPending(value int) Options {
+	opts := *o
+	opts.asyncWriteMaxPending = value
+	return &opts
+}
+
+func (o *options) AsyncWriteMaxPending() int {
+	return o.asyncWriteMaxPending
+}
+
+func (o *options) SetHintedHandoffDirectory(value string) Options {
+	opts := *o
+	opts.hintedHandoffDirectory = value
+	return &opts
+}
+
+func (o *options) HintedHandoffDirectory() string {
+	return o.hintedHandoffDirectory
+}
+
+func (o *options) SetHintedHandoffMaxAge(value time.Duration) Options {
+	opts := *o
+	opts.hintedHandoffMaxAge = value
+	return &opts
+}
+
+func (o *options) HintedHandoffMaxAge() time.Duration {
+	return o.hintedHandoffMaxAge
+}
+
+func (o *options) SetHintedHandoffMaxBytes(value int64) Options {
+	opts := *o
+	opts.hintedHandoffMaxBytes = value
+	return &opts
+}
+
+func (o *options) HintedHandoffMaxBytes() int64 {
+	return o.hintedHandoffMaxBytes
+}
+
+func (o *options) SetHintedHandoffReplayBatchSize(value int) Options {
+	opts := *o
+	opts.hintedHandoffReplayBatchSize = value
+	return &opts
+}
+
+func (o *options) HintedHandoffReplayBatchSize() int {
+	return o.hintedHandoffReplayBatchSize
+}
+
+func (o *options) SetHintedHandoffReplayRate(value int) Options {
+	opts := *o
+	opts.hintedHandoffReplayRate = value
+	return &opts
+}
+
+func (o *options) HintedHandoffReplayRate() int {
+	return o.hintedHandoffReplayRate
+}
+
 func (o *options) SetFetchBatchOpPoolSize(value int) Options {
 	opts := *o
 	opts.fetchBatchOpPoolSize = value
@@ -699,6 +844,26 @@ func (o *options) FetchBatchSize() int {
 	return o.fetchBatchSize
 }
 
+func (o *options) SetFetchTaggedStreamShardBatchSize(value int) Options {
+	opts := *o
+	opts.fetchTaggedStreamShardBatchSize = value
+	return &opts
+}
+
+func (o *options) FetchTaggedStreamShardBatchSize() int {
+	return o.fetchTaggedStreamShardBatchSize
+}
+
+func (o *options) SetFetchHedgePercentile(value float64) Options {
+	opts := *o
+	opts.fetchHedgePercentile = value
+	return &opts
+}
+
+func (o *options) FetchHedgePercentile() float64 {
+	return o.fetchHedgePercentile
+}
+
 func (o *options) SetIdentifierPool(value ident.Pool) Options {
 	opts := *o
 	opts.identifierPool = value
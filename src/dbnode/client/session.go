@@ -37,6 +37,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
@@ -118,6 +119,8 @@ type sessionState struct {
 	readLevel      topology.ReadConsistencyLevel
 	bootstrapLevel topology.ReadConsistencyLevel
 
+	streamBlocksRateLimitOpts ratelimit.Options
+
 	queues         []hostQueue
 	queuesByHostID map[string]hostQueue
 	topo           topology.Topology
@@ -136,6 +139,10 @@ type session struct {
 	log                              xlog.Logger
 	newHostQueueFn                   newHostQueueFn
 	writeRetrier                     xretry.Retrier
+	asyncWritePending                chan struct{}
+	hints                            *hintedHandoff
+	hintsDoneCh                      chan struct{}
+	hintsWg                          sync.WaitGroup
 	fetchRetrier                     xretry.Retrier
 	streamBlocksRetrier              xretry.Retrier
 	pools                            sessionPools
@@ -149,6 +156,8 @@ type session struct {
 	streamBlocksBatchSize            int
 	streamBlocksMetadataBatchTimeout time.Duration
 	streamBlocksBatchTimeout         time.Duration
+	streamBlocksThrottler            *ratelimit.Throttler
+	streamBlocksProgress             StreamBlocksProgress
 	metrics                          sessionMetrics
 }
 
@@ -167,6 +176,7 @@ type sessionMetrics struct {
 	fetchErrors                          tally.Counter
 	fetchNodesRespondingErrors           []tally.Counter
 	fetchNodesRespondingBadRequestErrors []tally.Counter
+	fetchHedge                           fetchHedgeMetrics
 	topologyUpdatedSuccess               tally.Counter
 	topologyUpdatedError                 tally.Counter
 	streamFromPeersMetrics               map[shardMetricsKey]streamFromPeersMetrics
@@ -178,6 +188,7 @@ func newSessionMetrics(scope tally.Scope) sessionMetrics {
 		writeErrors:            scope.Counter("write.errors"),
 		fetchSuccess:           scope.Counter("fetch.success"),
 		fetchErrors:            scope.Counter("fetch.errors"),
+		fetchHedge:             newFetchHedgeMetrics(scope),
 		topologyUpdatedSuccess: scope.Counter("topology.updated-success"),
 		topologyUpdatedError:   scope.Counter("topology.updated-error"),
 		streamFromPeersMetrics: make(map[shardMetricsKey]streamFromPeersMetrics),
@@ -201,6 +212,7 @@ type streamFromPeersMetrics struct {
 	fetchBlockRetriesRespError                        tally.Counter
 	fetchBlockRetriesConsistencyLevelNotAchievedError tally.Counter
 	blocksEnqueueChannel                              tally.Gauge
+	fetchBlockThrottle                                tally.Timer
 }
 
 type hostQueueOpts struct {
@@ -226,10 +238,11 @@ func newSession(opts Options) (clientSession, error) {
 
 	s := &session{
 		state: sessionState{
-			writeLevel:     opts.WriteConsistencyLevel(),
-			readLevel:      opts.ReadConsistencyLevel(),
-			queuesByHostID: make(map[string]hostQueue),
-			topo:           topo,
+			writeLevel:                opts.WriteConsistencyLevel(),
+			readLevel:                 opts.ReadConsistencyLevel(),
+			streamBlocksRateLimitOpts: ratelimit.NewOptions(),
+			queuesByHostID:            make(map[string]hostQueue),
+			topo:                      topo,
 		},
 		opts:                 opts,
 		scope:                scope,
@@ -239,6 +252,7 @@ func newSession(opts Options) (clientSession, error) {
 		fetchBatchSize:       opts.FetchBatchSize(),
 		newPeerBlocksQueueFn: newPeerBlocksQueue,
 		writeRetrier:         opts.WriteRetrier(),
+		asyncWritePending:    make(chan struct{}, opts.AsyncWriteMaxPending()),
 		fetchRetrier:         opts.FetchRetrier(),
 		pools: sessionPools{
 			context: opts.ContextPool(),
@@ -305,6 +319,8 @@ func newSession(opts Options) (clientSession, error) {
 		s.streamBlocksMetadataBatchTimeout = opts.FetchSeriesBlocksMetadataBatchTimeout()
 		s.streamBlocksBatchTimeout = opts.FetchSeriesBlocksBatchTimeout()
 		s.streamBlocksRetrier = opts.StreamBlocksRetrier()
+		s.streamBlocksThrottler = ratelimit.NewThrottler(s.nowFn, time.Sleep)
+		s.streamBlocksProgress = opts.StreamBlocksProgress()
 	}
 
 	if runtimeOptsMgr := opts.RuntimeOptionsManager(); runtimeOptsMgr != nil {
@@ -319,6 +335,7 @@ func (s *session) SetRuntimeOptions(value runtime.Options) {
 	s.state.bootstrapLevel = value.ClientBootstrapConsistencyLevel()
 	s.state.readLevel = value.ClientReadConsistencyLevel()
 	s.state.writeLevel = value.ClientWriteConsistencyLevel()
+	s.state.streamBlocksRateLimitOpts = value.ClientStreamBlocksRateLimitOptions()
 	s.state.Unlock()
 }
 
@@ -384,6 +401,7 @@ func (s *session) newPeerMetadataStreamingProgressMetrics(
 			"reason": "consistency-level-not-achieved-error",
 		}).Counter("fetch-block-retries"),
 		blocksEnqueueChannel: scope.Gauge("fetch-blocks-enqueue-channel-length"),
+		fetchBlockThrottle:   scope.Timer("fetch-block-throttle"),
 	}
 	s.metrics.streamFromPeersMetrics[mKey] = m
 	s.metrics.Unlock()
@@ -521,6 +539,22 @@ func (s *session) Open() error {
 	s.pools.seriesIterator.Init()
 	s.pools.seriesIterators = encoding.NewMutableSeriesIteratorsPool(s.opts.SeriesIteratorArrayPoolBuckets())
 	s.pools.seriesIterators.Init()
+
+	if s.opts.HintedHandoffDirectory() != "" {
+		hints, err := newHintedHandoff(s.opts)
+		if err != nil {
+			s.state.Unlock()
+			return err
+		}
+		s.hints = hints
+		s.hintsDoneCh = make(chan struct{})
+		s.hintsWg.Add(1)
+		go func() {
+			s.replayHintsLoop(hints, s.hintsDoneCh)
+			s.hintsWg.Done()
+		}()
+	}
+
 	s.state.status = statusOpen
 	s.state.Unlock()
 
@@ -900,6 +934,101 @@ func (s *session) WriteTagged(
 	return err
 }
 
+func (s *session) WriteTaggedAsync(
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	fn WriteCompletionFn,
+) {
+	s.writeAsync(taggedWriteAttemptType, namespace, id, tags,
+		t, value, unit, annotation, fn)
+}
+
+func (s *session) WriteBatch(
+	writes []WriteBatchElement,
+	fn WriteBatchCompletionFn,
+) {
+	for i := range writes {
+		var (
+			idx   = i
+			write = writes[i]
+			wType = untaggedWriteAttemptType
+			tags  = ident.EmptyTagIterator
+		)
+		if write.Tags != nil {
+			wType, tags = taggedWriteAttemptType, write.Tags
+		}
+		s.writeAsync(wType, write.Namespace, write.ID, tags, write.Timestamp,
+			write.Value, write.Unit, write.Annotation, func(err error) {
+				fn(idx, err)
+			})
+	}
+}
+
+func (s *session) writeAsync(
+	wType writeAttemptType,
+	namespace, id ident.ID,
+	inputTags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	fn WriteCompletionFn,
+) {
+	timeType, timeTypeErr := convert.ToTimeType(unit)
+	if timeTypeErr != nil {
+		fn(timeTypeErr)
+		return
+	}
+
+	timestamp, timestampErr := convert.ToValue(t, timeType)
+	if timestampErr != nil {
+		fn(timestampErr)
+		return
+	}
+
+	// NB: block until there is room for another pending write to apply
+	// back pressure to callers writing faster than the host queues can
+	// drain, this must happen before acquiring the state lock so that
+	// topology changes are not blocked.
+	s.asyncWritePending <- struct{}{}
+	done := func(err error) {
+		<-s.asyncWritePending
+		fn(err)
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		done(errSessionStatusNotOpen)
+		return
+	}
+
+	state, majority, enqueued, err := s.writeAttemptWithRLock(
+		wType, namespace, id, inputTags, timestamp, value, timeType, annotation)
+	s.state.RUnlock()
+
+	if err != nil {
+		done(err)
+		return
+	}
+
+	// NB: completions cannot run until the state is unlocked so the async fn
+	// is always set before the write can complete.
+	state.asyncFn = func(state *writeState) {
+		err := s.writeConsistencyResult(state.consistencyLevel, majority, enqueued,
+			enqueued-state.pending, int32(len(state.errors)), state.errors)
+
+		s.incWriteMetrics(err, int32(len(state.errors)))
+
+		done(err)
+	}
+	state.Unlock()
+}
+
 func (s *session) writeAttempt(
 	wType writeAttemptType,
 	namespace, id ident.ID,
@@ -1023,6 +1152,7 @@ func (s *session) writeAttemptWithRLock(
 	// todo@bl: Can we combine the writeOpPool and the writeStatePool?
 	state.op, state.majority = op, majority
 	state.nsID, state.tsID, state.tagEncoder = nsID, tsID, tagEncoder
+	state.hints = s.hints
 	op.SetCompletionFn(state.completionFn)
 
 	if err := s.state.topoMap.RouteForEach(tsID, func(idx int, host topology.Host) {
@@ -1101,21 +1231,43 @@ func (s *session) FetchTagged(
 	return iters, exhaustive, err
 }
 
+func (s *session) FetchTaggedPage(
+	ns ident.ID, q index.Query, opts index.QueryOptions, pageToken []byte,
+) (encoding.SeriesIterators, []byte, error) {
+	pageTokens, err := decodeFetchTaggedPageToken(pageToken)
+	if err != nil {
+		return nil, nil, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid page token: %v", err))
+	}
+
+	opts.Paginate = true
+	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	f.args.pageTokens = pageTokens
+	err = s.fetchRetrier.Attempt(f.dataAttemptFn)
+	iters, nextPageToken := f.dataResultIters, f.resultNextPageToken
+	s.pools.fetchTaggedAttempt.Put(f)
+	return iters, nextPageToken, err
+}
+
 func (s *session) fetchTaggedAttempt(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
-) (encoding.SeriesIterators, bool, error) {
+	pageTokens map[string]fetchTaggedHostPageToken, shards []uint32,
+) (encoding.SeriesIterators, bool, []byte, error) {
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
-		return nil, false, errSessionStatusNotOpen
+		return nil, false, nil, errSessionStatusNotOpen
 	}
 
 	const fetchData = true
-	fetchState, err := s.fetchTaggedAttemptWithRLock(ns, q, opts, fetchData)
+	fetchState, err := s.fetchTaggedAttemptWithRLock(ns, q, opts, pageTokens, shards, fetchData)
 	s.state.RUnlock()
 
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 
 	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
@@ -1126,11 +1278,62 @@ func (s *session) fetchTaggedAttempt(
 	// the fetchState Lock
 	fetchState.Unlock()
 	iters, exhaustive, err := fetchState.asEncodingSeriesIterators(s.pools)
+	var nextPageToken []byte
+	if err == nil && opts.Paginate {
+		nextPageToken, err = fetchState.nextPageToken()
+	}
 
 	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
 	// pool if ref count == 0.
 	fetchState.decRef()
 
+	return iters, exhaustive, nextPageToken, err
+}
+
+func (s *session) FetchTaggedStream(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (SeriesIteratorStream, error) {
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return nil, errSessionStatusNotOpen
+	}
+	shards := s.state.topoMap.ShardSet().AllIDs()
+	s.state.RUnlock()
+
+	// NB: the stream fetches the series of a batch of shards at a time, the
+	// replicas of each series are merged once every shard in the batch meets
+	// the read consistency level. Only the series of a single batch are held
+	// at any time and each batch is retried by itself.
+	sorted := make([]uint32, len(shards))
+	copy(sorted, shards)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = maxInt
+	}
+	opts.Paginate = false
+	fetchShardsFn := func(shards []uint32) (encoding.SeriesIterators, bool, error) {
+		return s.fetchTaggedShards(ns, q, opts, shards)
+	}
+	return newSeriesIteratorStream(fetchShardsFn, sorted,
+		s.opts.FetchTaggedStreamShardBatchSize(), limit), nil
+}
+
+// fetchTaggedShards fetches the series of the provided shards that match the
+// query, the series of all other shards are excluded by the hosts.
+func (s *session) fetchTaggedShards(
+	ns ident.ID, q index.Query, opts index.QueryOptions, shards []uint32,
+) (encoding.SeriesIterators, bool, error) {
+	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	f.args.shards = shards
+	err := s.fetchRetrier.Attempt(f.dataAttemptFn)
+	iters, exhaustive := f.dataResultIters, f.dataResultExhaustive
+	s.pools.fetchTaggedAttempt.Put(f)
 	return iters, exhaustive, err
 }
 
@@ -1147,21 +1350,43 @@ func (s *session) FetchTaggedIDs(
 	return iter, exhaustive, err
 }
 
+func (s *session) FetchTaggedIDsPage(
+	ns ident.ID, q index.Query, opts index.QueryOptions, pageToken []byte,
+) (TaggedIDsIterator, []byte, error) {
+	pageTokens, err := decodeFetchTaggedPageToken(pageToken)
+	if err != nil {
+		return nil, nil, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid page token: %v", err))
+	}
+
+	opts.Paginate = true
+	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	f.args.pageTokens = pageTokens
+	err = s.fetchRetrier.Attempt(f.idsAttemptFn)
+	iter, nextPageToken := f.idsResultIter, f.resultNextPageToken
+	s.pools.fetchTaggedAttempt.Put(f)
+	return iter, nextPageToken, err
+}
+
 func (s *session) fetchTaggedIDsAttempt(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
-) (TaggedIDsIterator, bool, error) {
+	pageTokens map[string]fetchTaggedHostPageToken, shards []uint32,
+) (TaggedIDsIterator, bool, []byte, error) {
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
-		return nil, false, errSessionStatusNotOpen
+		return nil, false, nil, errSessionStatusNotOpen
 	}
 
 	const fetchData = false
-	fetchState, err := s.fetchTaggedAttemptWithRLock(ns, q, opts, fetchData)
+	fetchState, err := s.fetchTaggedAttemptWithRLock(ns, q, opts, pageTokens, shards, fetchData)
 	s.state.RUnlock()
 
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 
 	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
@@ -1172,12 +1397,91 @@ func (s *session) fetchTaggedIDsAttempt(
 	// the fetchState Lock
 	fetchState.Unlock()
 	iter, exhaustive, err := fetchState.asTaggedIDsIterator(s.pools)
+	var nextPageToken []byte
+	if err == nil && opts.Paginate {
+		nextPageToken, err = fetchState.nextPageToken()
+	}
 
 	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
 	// pool if ref count == 0.
 	fetchState.decRef()
 
-	return iter, exhaustive, err
+	return iter, exhaustive, nextPageToken, err
+}
+
+func (s *session) Aggregate(
+	ns ident.ID, q index.Query, opts index.AggregateQueryOptions,
+) (index.AggregateQueryResults, error) {
+	var result index.AggregateQueryResults
+	err := s.fetchRetrier.Attempt(func() error {
+		var err error
+		result, err = s.aggregateAttempt(ns, q, opts)
+		return err
+	})
+	return result, err
+}
+
+func (s *session) aggregateAttempt(
+	ns ident.ID, q index.Query, opts index.AggregateQueryOptions,
+) (index.AggregateQueryResults, error) {
+	req, err := convert.ToRPCAggregateQueryRequest(ns, q, opts)
+	if err != nil {
+		return index.AggregateQueryResults{}, xerrors.NewNonRetryableError(err)
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return index.AggregateQueryResults{}, errSessionStatusNotOpen
+	}
+
+	var (
+		accum = newAggregateResultAccumulator(s.state.topoMap,
+			s.state.majority, s.state.readLevel)
+		resultLock sync.Mutex
+		done       bool
+		doneErr    error
+		doneCh     = make(chan struct{})
+	)
+
+	op := &aggregateOp{request: req}
+	op.completionFn = func(result interface{}, err error) {
+		resultLock.Lock()
+		defer resultLock.Unlock()
+
+		if done {
+			// NB: the read consistency level has already been met, or can no
+			// longer be met, the response of any remaining host is ignored.
+			return
+		}
+
+		accumDone, accumErr := accum.Add(result.(aggregateResultAccumulatorOpts), err)
+		if accumDone {
+			done, doneErr = true, accumErr
+			close(doneCh)
+		}
+	}
+
+	for _, queue := range s.state.queues {
+		if err := queue.Enqueue(op); err != nil {
+			// NB: should never happen as queues are never closed while the
+			// session state read lock is held, treat the host as having failed
+			// to respond.
+			s.log.Errorf("failed to enqueue request: %v", err)
+			op.completionFn(aggregateResultAccumulatorOpts{host: queue.Host()}, err)
+		}
+	}
+	s.state.RUnlock()
+
+	// Wait until the read consistency level is met for each shard
+	<-doneCh
+
+	resultLock.Lock()
+	defer resultLock.Unlock()
+	if doneErr != nil {
+		return index.AggregateQueryResults{}, doneErr
+	}
+	return accum.Results(opts.Limit), nil
 }
 
 // NB(prateek): the returned fetchState, if valid, still holds the lock. Its ownership
@@ -1187,6 +1491,8 @@ func (s *session) fetchTaggedAttemptWithRLock(
 	ns ident.ID,
 	q index.Query,
 	opts index.QueryOptions,
+	pageTokens map[string]fetchTaggedHostPageToken,
+	shards []uint32,
 	fetchData bool,
 ) (*fetchState, error) {
 	// NB(prateek): we have to clone the namespace, as we cannot guarantee the lifecycle
@@ -1202,6 +1508,12 @@ func (s *session) fetchTaggedAttemptWithRLock(
 		nsClone.Finalize()
 		return nil, xerrors.NewNonRetryableError(err)
 	}
+	if len(shards) > 0 {
+		req.Shards = make([]int32, 0, len(shards))
+		for _, shard := range shards {
+			req.Shards = append(req.Shards, int32(shard))
+		}
+	}
 
 	var (
 		topoMap    = s.state.topoMap
@@ -1213,13 +1525,26 @@ func (s *session) fetchTaggedAttemptWithRLock(
 	fetchState.incRef()       // indicate current go-routine has a reference to the fetchState
 	op.incRef()               // indicate current go-routine has a reference to the op
 	op.update(req, fetchState.completionFn)
+	op.updatePageTokens(pageTokens)
 
 	fetchState.Reset(opts.StartInclusive, opts.EndExclusive, op, topoMap, s.state.majority, s.state.readLevel)
 	fetchState.Lock()
-	for _, hq := range s.state.queues {
+	queues := s.state.queues
+	if percentile := s.opts.FetchHedgePercentile(); percentile > 0 && !op.paginated() {
+		// NB: paginated requests are not hedged as every host needs to return
+		// its page for the next page to resume from.
+		hedge, ok := newFetchTaggedHedge(queues, topoMap, s.state.readLevel,
+			s.state.majority, percentile)
+		if ok {
+			queues = hedge.queues
+			fetchState.ResetHedge(hedge.spare, hedge.delay, s.metrics.fetchHedge)
+		}
+	}
+	for _, hq := range queues {
 		// inc to indicate the hostQueue has a reference to `op` which has a ref to the fetchState
 		fetchState.incRef()
 		if err := hq.Enqueue(op); err != nil {
+			fetchState.stopHedgeWithLock()
 			fetchState.Unlock()
 			op.decRef()         // release the ref for the current go-routine
 			fetchState.decRef() // release the ref for the hostQueue
@@ -1561,10 +1886,22 @@ func (s *session) Close() error {
 	topo := s.state.topo
 	s.state.Unlock()
 
+	if s.hints != nil {
+		// Stop replaying hints before closing the queues they are replayed to.
+		close(s.hintsDoneCh)
+		s.hintsWg.Wait()
+	}
+
 	for _, q := range queues {
 		q.Close()
 	}
 
+	if s.hints != nil {
+		if err := s.hints.Close(); err != nil {
+			s.log.Errorf("could not close hinted handoff: %v", err)
+		}
+	}
+
 	topoWatch.Close()
 	topo.Close()
 
@@ -1779,6 +2116,14 @@ func (s *session) FetchBootstrapBlocksFromPeers(
 		return nil, err
 	}
 
+	// Track the progress of the shard to report on it while streaming
+	var shardProgress *shardStreamBlocksProgress
+	if s.streamBlocksProgress != nil {
+		shardProgress = s.streamBlocksProgress.start(nsMetadata.ID(), shard)
+		result.progress = shardProgress
+		defer shardProgress.setDone()
+	}
+
 	// Emit a gauge indicating whether we're done or not
 	go func() {
 		for {
@@ -1812,7 +2157,8 @@ func (s *session) FetchBootstrapBlocksFromPeers(
 	// the caller, but metrics and logs are emitted internally. Also note that the
 	// streamAndGroupCollectedBlocksMetadata function is injected.
 	s.streamBlocksFromPeers(nsMetadata, shard, peers, metadataCh, opts,
-		level, result, progress, s.streamAndGroupCollectedBlocksMetadata)
+		level, result, progress, shardProgress,
+		s.streamAndGroupCollectedBlocksMetadata)
 
 	// Check if an error occurred during the metadata streaming
 	if err = <-errCh; err != nil {
@@ -1894,7 +2240,7 @@ func (s *session) FetchBlocksFromPeers(
 	// Begin consuming metadata and making requests
 	go func() {
 		s.streamBlocksFromPeers(nsMetadata, shard, peers, metadataCh,
-			opts, level, result, progress, s.passThroughBlocksMetadata)
+			opts, level, result, progress, nil, s.passThroughBlocksMetadata)
 		close(outputCh)
 		onDone(nil)
 	}()
@@ -2182,6 +2528,7 @@ func (s *session) streamBlocksFromPeers(
 	consistencyLevel runtimeReadConsistencyLevel,
 	result blocksResult,
 	progress *streamFromPeersMetrics,
+	shardProgress *shardStreamBlocksProgress,
 	streamMetadataFn streamBlocksMetadataFn,
 ) {
 	var (
@@ -2190,11 +2537,14 @@ func (s *session) streamBlocksFromPeers(
 		numPeers            = len(peers.peers)
 		uncheckedBytesPool  = opts.DatabaseBlockOptions().BytesPool().BytesPool()
 	)
+	shardProgress.setEnqueueChannel(enqueueCh)
 
 	// Consume the incoming metadata and enqueue to the ready channel
 	// Spin up background goroutine to consume
 	go func() {
 		streamMetadataFn(numPeers, metadataCh, enqueueCh, uncheckedBytesPool)
+		// All blocks to stream have now been enqueued
+		shardProgress.setMetadataDone()
 		// Begin assessing the queue and how much is processed, once queue
 		// is entirely processed then we can close the enqueue channel
 		enqueueCh.closeOnAllProcessed()
@@ -2697,6 +3047,9 @@ func (s *session) streamBlocksBatchFromPeer(
 		return
 	}
 
+	// Throttle streaming to avoid saturating the network of peers
+	s.throttleStreamBlocks(result, m)
+
 	// Parse and act on result
 	tooManyIDsLogged := false
 	for i := range result.Elements {
@@ -2797,6 +3150,30 @@ func (s *session) streamBlocksBatchFromPeer(
 	}
 }
 
+func (s *session) throttleStreamBlocks(
+	result *rpc.FetchBlocksRawResult_,
+	m *streamFromPeersMetrics,
+) {
+	if s.streamBlocksThrottler == nil {
+		return
+	}
+
+	var bytes int64
+	for _, elem := range result.Elements {
+		for _, block := range elem.Blocks {
+			bytes += convert.SegmentsLen(block.Segments)
+		}
+	}
+
+	s.state.RLock()
+	rateLimitOpts := s.state.streamBlocksRateLimitOpts
+	s.state.RUnlock()
+
+	if slept := s.streamBlocksThrottler.Throttle(rateLimitOpts, bytes); slept > 0 {
+		m.fetchBlockThrottle.Record(slept)
+	}
+}
+
 func (s *session) verifyFetchedBlock(block *rpc.Block) error {
 	if block.Err != nil {
 		return fmt.Errorf("block error from peer: %s %s", block.Err.Type.String(), block.Err.Message)
@@ -3177,6 +3554,7 @@ type bulkBlocksResult struct {
 	result         result.ShardResult
 	tagDecoderPool serialize.TagDecoderPool
 	idPool         ident.Pool
+	progress       *shardStreamBlocksProgress
 }
 
 func newBulkBlocksResult(
@@ -3214,8 +3592,10 @@ func (r *bulkBlocksResult) addBlockFromPeer(
 		currBlock, exists := r.result.BlockAt(id, start)
 		if !exists {
 			if encodedTags == nil || attemptedDecodeTags {
+				_, seriesExists := r.result.AllSeries().Get(id)
 				r.result.AddBlock(id, tags, result)
 				r.Unlock()
+				r.progress.addBlock(convert.SegmentsLen(block.Segments), !seriesExists)
 				break
 			}
 			r.Unlock()
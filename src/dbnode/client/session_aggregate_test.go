@@ -0,0 +1,234 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/ident"
+	xretry "github.com/m3db/m3x/retry"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAggregateResult() *rpc.AggregateQueryResult_ {
+	return &rpc.AggregateQueryResult_{
+		Results: []*rpc.AggregateQueryResultTagNameElement{
+			{
+				TagName: []byte("foo"),
+				Count:   4,
+				TagValues: []*rpc.AggregateQueryResultTagValueElement{
+					{TagValue: []byte("bar"), Count: 3},
+					{TagValue: []byte("baz"), Count: 1},
+				},
+			},
+		},
+		Exhaustive: true,
+	}
+}
+
+func testAggregateHosts(t *testing.T, opts Options) []topology.Host {
+	topoWatch, err := opts.TopologyInitializer().Init()
+	require.NoError(t, err)
+	return topoWatch.Get().Hosts()
+}
+
+func TestSessionAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+	hosts := testAggregateHosts(t, opts)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(hostIdx int, op op) {
+			aggregate, ok := op.(*aggregateOp)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("metrics"), aggregate.request.NameSpace)
+			assert.False(t, aggregate.request.TagNamesOnly)
+			aggregate.completionFn(aggregateResultAccumulatorOpts{
+				host:     hosts[hostIdx],
+				response: testAggregateResult(),
+			}, nil)
+		},
+	})
+
+	assert.NoError(t, session.Open())
+
+	result, err := s.Aggregate(ident.StringID("metrics"),
+		index.Query{Query: idx.NewTermQuery([]byte("foo"), []byte("bar"))},
+		index.AggregateQueryOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Exhaustive)
+
+	// Counts are summed across the replicas that responded before the read
+	// consistency level was met then scaled back down.
+	assert.Equal(t, []index.AggregateField{
+		{
+			Name:  []byte("foo"),
+			Count: 4,
+			Values: []index.AggregateValue{
+				{Value: []byte("bar"), Count: 3},
+				{Value: []byte("baz"), Count: 1},
+			},
+		},
+	}, result.Results.Fields())
+
+	assert.NoError(t, session.Close())
+}
+
+func TestSessionAggregateHostError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+	hosts := testAggregateHosts(t, opts)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(hostIdx int, op op) {
+			aggregate, ok := op.(*aggregateOp)
+			assert.True(t, ok)
+			if hostIdx == 0 {
+				aggregate.completionFn(aggregateResultAccumulatorOpts{
+					host: hosts[hostIdx],
+				}, fmt.Errorf("an error"))
+				return
+			}
+			aggregate.completionFn(aggregateResultAccumulatorOpts{
+				host:     hosts[hostIdx],
+				response: testAggregateResult(),
+			}, nil)
+		},
+	})
+
+	assert.NoError(t, session.Open())
+
+	result, err := s.Aggregate(ident.StringID("metrics"),
+		index.Query{Query: idx.NewAllQuery()},
+		index.AggregateQueryOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Exhaustive)
+
+	// Only two of the replicas responded, counts are divided by the number
+	// of replicas that responded.
+	fields := result.Results.Fields()
+	require.Len(t, fields, 1)
+	assert.Equal(t, int64(4), fields[0].Count)
+	assert.Equal(t, []index.AggregateValue{
+		{Value: []byte("bar"), Count: 3},
+		{Value: []byte("baz"), Count: 1},
+	}, fields[0].Values)
+
+	assert.NoError(t, session.Close())
+}
+
+func TestSessionAggregateReadConsistencyOne(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions().
+		SetReadConsistencyLevel(topology.ReadConsistencyLevelOne)
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+	hosts := testAggregateHosts(t, opts)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(hostIdx int, op op) {
+			aggregate, ok := op.(*aggregateOp)
+			assert.True(t, ok)
+			if hostIdx > 0 {
+				// Hosts that have not responded once the read consistency
+				// level is met are not waited on.
+				return
+			}
+			aggregate.completionFn(aggregateResultAccumulatorOpts{
+				host:     hosts[hostIdx],
+				response: testAggregateResult(),
+			}, nil)
+		},
+	})
+
+	assert.NoError(t, session.Open())
+
+	result, err := s.Aggregate(ident.StringID("metrics"),
+		index.Query{Query: idx.NewAllQuery()},
+		index.AggregateQueryOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Exhaustive)
+
+	fields := result.Results.Fields()
+	require.Len(t, fields, 1)
+	assert.Equal(t, int64(4), fields[0].Count)
+
+	assert.NoError(t, session.Close())
+}
+
+func TestSessionAggregateReadConsistencyNotMet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions().
+		SetFetchRetrier(xretry.NewRetrier(xretry.NewOptions().SetMaxRetries(0)))
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+	hosts := testAggregateHosts(t, opts)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(hostIdx int, op op) {
+			aggregate, ok := op.(*aggregateOp)
+			assert.True(t, ok)
+			if hostIdx < 2 {
+				aggregate.completionFn(aggregateResultAccumulatorOpts{
+					host: hosts[hostIdx],
+				}, fmt.Errorf("an error"))
+				return
+			}
+			aggregate.completionFn(aggregateResultAccumulatorOpts{
+				host:     hosts[hostIdx],
+				response: testAggregateResult(),
+			}, nil)
+		},
+	})
+
+	assert.NoError(t, session.Open())
+
+	_, err = s.Aggregate(ident.StringID("metrics"),
+		index.Query{Query: idx.NewAllQuery()},
+		index.AggregateQueryOptions{})
+	require.Error(t, err)
+
+	assert.NoError(t, session.Close())
+}
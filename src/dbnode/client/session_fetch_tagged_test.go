@@ -28,6 +28,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
@@ -403,6 +404,137 @@ func TestSessionFetchTaggedMergeTest(t *testing.T) {
 	require.Equal(t, 1, numOpAllocs)
 }
 
+func TestSessionFetchTaggedStreamNotOpenError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newDefaultTestSession(t)
+	start := time.Now().Truncate(time.Hour)
+	stream, err := s.FetchTaggedStream(ident.StringID("namespace"),
+		testSessionFetchTaggedQuery, testSessionFetchTaggedQueryOpts(start, start.Add(time.Hour)))
+	assert.Error(t, err)
+	assert.Equal(t, errSessionStatusNotOpen, err)
+	assert.Nil(t, stream)
+}
+
+func TestSessionFetchTaggedStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions().
+		SetReadConsistencyLevel(topology.ReadConsistencyLevelAll).
+		SetFetchTaggedStreamShardBatchSize(2)
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+
+	start := time.Now().Truncate(time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	var (
+		numPoints = 100
+		sg0       = newTestSerieses(1, 5)
+		sg1       = newTestSerieses(6, 10)
+		th        = newTestFetchTaggedHelper(t)
+	)
+	sg0.addDatapoints(numPoints, start, end)
+	sg1.addDatapoints(numPoints, start, end)
+
+	topoInit := opts.TopologyInitializer()
+	topoWatch, err := topoInit.Init()
+	require.NoError(t, err)
+	topoMap := topoWatch.Get()
+	require.Equal(t, 3, topoMap.HostsLen()) // the code below assumes this
+	require.Equal(t, 3, len(topoMap.ShardSet().AllIDs()))
+
+	// Every series belongs to shard 0 which is in the first batch of shards,
+	// the second batch of shards has no series.
+	enqueueBatch := func(
+		expectedShards []int32,
+		response *rpc.FetchTaggedResult_,
+	) testEnqueue {
+		return testEnqueue{
+			enqueueFn: func(idx int, op op) {
+				host := topoMap.Hosts()[idx]
+				req, ok := op.(*fetchTaggedOp).requestForHost(host.ID())
+				assert.True(t, ok)
+				assert.Equal(t, expectedShards, req.Shards)
+				assert.Nil(t, req.PageToken)
+				go func() {
+					op.CompletionFn()(fetchTaggedResultAccumulatorOpts{
+						host:     host,
+						response: response,
+					}, nil)
+				}()
+			},
+		}
+	}
+	// The replicas of the first batch each return a different subset of the
+	// series, each series is returned once with the replicas merged.
+	firstBatches := []*rpc.FetchTaggedResult_{
+		append(sg0, sg1...).toRPCResult(th, start, true),
+		sg0.toRPCResult(th, start, true),
+		sg1.toRPCResult(th, start, true),
+	}
+	hostQueueOps := make(testHostQueueOpsByHost)
+	for i := 0; i < topoMap.HostsLen(); i++ {
+		hostQueueOps[testHostName(i)] = &testHostQueueOps{
+			enqueues: []testEnqueue{
+				enqueueBatch([]int32{0, 1}, firstBatches[i]),
+				enqueueBatch([]int32{2}, testSerieses{}.toRPCResult(th, start, true)),
+			},
+		}
+	}
+	mockExtendedHostQueues(t, ctrl, session, sessionTestReplicas, hostQueueOps)
+
+	assert.NoError(t, session.Open())
+
+	// NB: stubbing needs to be done after session.Open
+	leakStatePool := injectLeakcheckFetchStatePool(session)
+	leakOpPool := injectLeakcheckFetchTaggedOpPool(session)
+
+	stream, err := session.FetchTaggedStream(ident.StringID("namespace"),
+		testSessionFetchTaggedQuery, testSessionFetchTaggedQueryOpts(start, end))
+	require.NoError(t, err)
+
+	streamed := make(map[string]encoding.SeriesIterator)
+	for stream.Next() {
+		iter := stream.Current()
+		_, ok := streamed[iter.ID().String()]
+		require.False(t, ok, "series returned more than once: %s", iter.ID().String())
+		streamed[iter.ID().String()] = iter
+	}
+	require.NoError(t, stream.Err())
+	assert.True(t, stream.Exhaustive())
+	stream.Close()
+
+	expected := append(sg0, sg1...)
+	require.Equal(t, len(expected), len(streamed))
+	for _, ts := range expected {
+		iter, ok := streamed[ts.id.String()]
+		require.True(t, ok)
+		ts.assertMatchesEncodingIter(t, iter)
+		iter.Close()
+	}
+
+	assert.NoError(t, session.Close())
+
+	// A fetch state and op is used per batch of shards.
+	numStateAllocs := 0
+	leakStatePool.CheckExtended(t, func(e leakcheckFetchState) {
+		require.Equal(t, int32(0), atomic.LoadInt32(&e.Value.refCounter.n), string(e.GetStacktrace))
+		numStateAllocs++
+	})
+	require.Equal(t, 2, numStateAllocs)
+
+	numOpAllocs := 0
+	leakOpPool.CheckExtended(t, func(e leakcheckFetchTaggedOp) {
+		require.Equal(t, int32(0), atomic.LoadInt32(&e.Value.refCounter.n), string(e.GetStacktrace))
+		numOpAllocs++
+	})
+	require.Equal(t, 2, numOpAllocs)
+}
+
 func TestSessionFetchTaggedMergeWithRetriesTest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
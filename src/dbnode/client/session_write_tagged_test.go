@@ -618,3 +618,83 @@ func (e *erroredTagIter) Close()                       {}
 func (e *erroredTagIter) Len() int                     { return 0 }
 func (e *erroredTagIter) Remaining() int               { return 0 }
 func (e *erroredTagIter) Duplicate() ident.TagIterator { return e }
+
+func TestSessionWriteTaggedAsyncNotOpenError(t *testing.T) {
+	s := newDefaultTestSession(t)
+
+	var (
+		wg        sync.WaitGroup
+		resultErr error
+	)
+	wg.Add(1)
+	s.WriteTaggedAsync(ident.StringID("namespace"), ident.StringID("foo"),
+		ident.EmptyTagIterator, time.Now(), 1.337, xtime.Second, nil,
+		func(err error) {
+			resultErr = err
+			wg.Done()
+		})
+	wg.Wait()
+	assert.Equal(t, errSessionStatusNotOpen, resultErr)
+}
+
+func TestSessionWriteBatch(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	s := newDefaultTestSession(t).(*session)
+	var hosts []topology.Host
+
+	completeAsync := func(idx int, op op) {
+		go func() {
+			op.CompletionFn()(hosts[idx], nil)
+		}()
+	}
+	mockHostQueues(ctrl, s, sessionTestReplicas,
+		[]testEnqueueFn{completeAsync, completeAsync})
+	assert.NoError(t, s.Open())
+
+	s.state.RLock()
+	hosts = s.state.topoMap.Hosts()
+	s.state.RUnlock()
+
+	w := newWriteTaggedStub()
+	writes := []WriteBatchElement{
+		{
+			Namespace: w.ns,
+			ID:        ident.StringID("untagged"),
+			Timestamp: w.t,
+			Value:     w.value,
+			Unit:      w.unit,
+		},
+		{
+			Namespace: w.ns,
+			ID:        w.id,
+			Tags:      ident.NewTagsIterator(w.tags),
+			Timestamp: w.t,
+			Value:     w.value,
+			Unit:      w.unit,
+		},
+	}
+
+	var (
+		wg      sync.WaitGroup
+		lock    sync.Mutex
+		results = make(map[int]error)
+	)
+	wg.Add(len(writes))
+	s.WriteBatch(writes, func(idx int, err error) {
+		lock.Lock()
+		results[idx] = err
+		lock.Unlock()
+		wg.Done()
+	})
+	wg.Wait()
+
+	require.Equal(t, len(writes), len(results))
+	for idx := range writes {
+		assert.NoError(t, results[idx])
+	}
+	assert.Equal(t, 0, len(s.asyncWritePending))
+
+	require.NoError(t, s.Close())
+}
@@ -234,6 +234,46 @@ func TestSessionWriteBadRequestErrorIsNonRetryable(t *testing.T) {
 	assert.NoError(t, session.Close())
 }
 
+func TestSessionWriteResourceExhaustedErrorIsRetriedWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := newDefaultRetryEnabledTestSession(t).(*session)
+
+	var hosts []topology.Host
+
+	rejectFn := func(idx int, op op) {
+		go func() {
+			op.CompletionFn()(hosts[idx], &rpc.Error{
+				Type:    rpc.ErrorType_RESOURCE_EXHAUSTED,
+				Message: "expected quota exceeded error",
+			})
+		}()
+	}
+	enqueueWg := mockHostQueues(ctrl, session, sessionTestReplicas,
+		[]testEnqueueFn{rejectFn, rejectFn})
+
+	assert.NoError(t, session.Open())
+
+	session.state.RLock()
+	hosts = session.state.topoMap.Hosts()
+	session.state.RUnlock()
+
+	start := time.Now()
+	err := session.Write(ident.StringID("testNs"), ident.StringID("foo"),
+		time.Now(), 1.0, xtime.Second, nil)
+	assert.Error(t, err)
+	assert.False(t, xerrors.IsNonRetryableError(err))
+	assert.True(t, IsResourceExhaustedError(err))
+
+	// The retry must have backed off after the first rejection
+	assert.True(t, time.Since(start) >= resourceExhaustedInitialBackoff)
+
+	enqueueWg.Wait()
+
+	assert.NoError(t, session.Close())
+}
+
 func TestSessionWriteRetry(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,214 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3x/ident"
+)
+
+// UnknownStreamBlocksETA is the ETA reported for a shard when it cannot
+// yet be estimated.
+const UnknownStreamBlocksETA = time.Duration(-1)
+
+// StreamBlocksProgress tracks the progress of streaming blocks from
+// peers for each shard when bootstrapping from peers.
+type StreamBlocksProgress interface {
+	// Shards returns the progress of each shard that is being or has
+	// been streamed from peers, ordered by namespace and shard.
+	Shards() []ShardStreamBlocksProgress
+
+	start(namespace ident.ID, shard uint32) *shardStreamBlocksProgress
+}
+
+// ShardStreamBlocksProgress is the progress of streaming blocks of a
+// shard from peers.
+type ShardStreamBlocksProgress struct {
+	Namespace      string        `json:"namespace"`
+	Shard          uint32        `json:"shard"`
+	Started        time.Time     `json:"started"`
+	Done           bool          `json:"done"`
+	MetadataDone   bool          `json:"metadataDone"`
+	BlocksStreamed int64         `json:"blocksStreamed"`
+	BlocksPending  int64         `json:"blocksPending"`
+	SeriesStreamed int64         `json:"seriesStreamed"`
+	BytesStreamed  int64         `json:"bytesStreamed"`
+	ETA            time.Duration `json:"eta"`
+}
+
+type streamBlocksProgressKey struct {
+	namespace string
+	shard     uint32
+}
+
+type streamBlocksProgress struct {
+	sync.RWMutex
+
+	nowFn  clock.NowFn
+	shards map[streamBlocksProgressKey]*shardStreamBlocksProgress
+}
+
+// NewStreamBlocksProgress returns a new tracker of the progress of
+// streaming blocks from peers.
+func NewStreamBlocksProgress(nowFn clock.NowFn) StreamBlocksProgress {
+	return &streamBlocksProgress{
+		nowFn:  nowFn,
+		shards: make(map[streamBlocksProgressKey]*shardStreamBlocksProgress),
+	}
+}
+
+// start returns the progress of a shard that is beginning to stream, the
+// progress accumulates across all the time ranges streamed for the shard.
+func (p *streamBlocksProgress) start(
+	namespace ident.ID,
+	shard uint32,
+) *shardStreamBlocksProgress {
+	key := streamBlocksProgressKey{namespace: namespace.String(), shard: shard}
+
+	p.Lock()
+	defer p.Unlock()
+
+	progress, ok := p.shards[key]
+	if !ok {
+		progress = &shardStreamBlocksProgress{
+			namespace: key.namespace,
+			shard:     shard,
+			started:   p.nowFn(),
+		}
+		p.shards[key] = progress
+	}
+	progress.Lock()
+	progress.done = false
+	progress.metadataDone = false
+	progress.Unlock()
+	return progress
+}
+
+func (p *streamBlocksProgress) Shards() []ShardStreamBlocksProgress {
+	now := p.nowFn()
+
+	p.RLock()
+	result := make([]ShardStreamBlocksProgress, 0, len(p.shards))
+	for _, progress := range p.shards {
+		result = append(result, progress.snapshot(now))
+	}
+	p.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Shard < result[j].Shard
+	})
+	return result
+}
+
+// shardStreamBlocksProgress tracks the progress of a single shard, all
+// methods are safe to call on a nil value to make tracking optional.
+type shardStreamBlocksProgress struct {
+	sync.RWMutex
+
+	namespace    string
+	shard        uint32
+	started      time.Time
+	done         bool
+	metadataDone bool
+	enqueueCh    enqueueChannel
+
+	blocksStreamed int64
+	seriesStreamed int64
+	bytesStreamed  int64
+}
+
+func (p *shardStreamBlocksProgress) setEnqueueChannel(enqueueCh enqueueChannel) {
+	if p == nil {
+		return
+	}
+	p.Lock()
+	p.enqueueCh = enqueueCh
+	p.Unlock()
+}
+
+func (p *shardStreamBlocksProgress) setMetadataDone() {
+	if p == nil {
+		return
+	}
+	p.Lock()
+	p.metadataDone = true
+	p.Unlock()
+}
+
+func (p *shardStreamBlocksProgress) setDone() {
+	if p == nil {
+		return
+	}
+	p.Lock()
+	p.done = true
+	p.metadataDone = true
+	p.enqueueCh = nil
+	p.Unlock()
+}
+
+func (p *shardStreamBlocksProgress) addBlock(bytes int64, newSeries bool) {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.blocksStreamed, 1)
+	atomic.AddInt64(&p.bytesStreamed, bytes)
+	if newSeries {
+		atomic.AddInt64(&p.seriesStreamed, 1)
+	}
+}
+
+func (p *shardStreamBlocksProgress) snapshot(now time.Time) ShardStreamBlocksProgress {
+	p.RLock()
+	result := ShardStreamBlocksProgress{
+		Namespace:      p.namespace,
+		Shard:          p.shard,
+		Started:        p.started,
+		Done:           p.done,
+		MetadataDone:   p.metadataDone,
+		BlocksStreamed: atomic.LoadInt64(&p.blocksStreamed),
+		SeriesStreamed: atomic.LoadInt64(&p.seriesStreamed),
+		BytesStreamed:  atomic.LoadInt64(&p.bytesStreamed),
+		ETA:            UnknownStreamBlocksETA,
+	}
+	if p.enqueueCh != nil {
+		result.BlocksPending = int64(p.enqueueCh.unprocessedLen())
+	}
+	p.RUnlock()
+
+	switch {
+	case result.Done:
+		result.ETA = 0
+	case result.MetadataDone && result.BlocksStreamed > 0:
+		// Only estimate once all blocks to stream are known
+		elapsed := now.Sub(result.Started)
+		result.ETA = time.Duration(float64(elapsed) *
+			float64(result.BlocksPending) / float64(result.BlocksStreamed))
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamBlocksProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	progress := NewStreamBlocksProgress(func() time.Time { return now })
+
+	started := now
+	shard := progress.start(ident.StringID("testns"), 1)
+	progress.start(ident.StringID("testns"), 0)
+
+	enqueueCh := NewMockenqueueChannel(ctrl)
+	enqueueCh.EXPECT().unprocessedLen().Return(30).AnyTimes()
+	shard.setEnqueueChannel(enqueueCh)
+
+	shard.addBlock(100, true)
+	shard.addBlock(50, false)
+	now = now.Add(time.Minute)
+
+	shards := progress.Shards()
+	require.Equal(t, 2, len(shards))
+	require.Equal(t, uint32(0), shards[0].Shard)
+	require.Equal(t, ShardStreamBlocksProgress{
+		Namespace:      "testns",
+		Shard:          1,
+		Started:        started,
+		BlocksStreamed: 2,
+		BlocksPending:  30,
+		SeriesStreamed: 1,
+		BytesStreamed:  150,
+		ETA:            UnknownStreamBlocksETA,
+	}, shards[1])
+
+	// Once all metadata is received the ETA can be estimated
+	shard.setMetadataDone()
+	shards = progress.Shards()
+	require.True(t, shards[1].MetadataDone)
+	require.Equal(t, 15*time.Minute, shards[1].ETA)
+
+	shard.setDone()
+	shards = progress.Shards()
+	require.True(t, shards[1].Done)
+	require.Equal(t, int64(0), shards[1].BlocksPending)
+	require.Equal(t, time.Duration(0), shards[1].ETA)
+
+	// Restarting the shard accumulates progress
+	shard = progress.start(ident.StringID("testns"), 1)
+	shard.addBlock(10, true)
+	shards = progress.Shards()
+	require.False(t, shards[1].Done)
+	require.Equal(t, int64(3), shards[1].BlocksStreamed)
+	require.Equal(t, int64(160), shards[1].BytesStreamed)
+}
+
+func TestShardStreamBlocksProgressNil(t *testing.T) {
+	var shard *shardStreamBlocksProgress
+	shard.setEnqueueChannel(nil)
+	shard.addBlock(100, true)
+	shard.setMetadataDone()
+	shard.setDone()
+}
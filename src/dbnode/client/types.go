@@ -66,6 +66,19 @@ type Session interface {
 	// WriteTagged value to the database for an ID and given tags.
 	WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time.Time, value float64, unit xtime.Unit, annotation []byte) error
 
+	// WriteTaggedAsync writes a value to the database for an ID and given tags
+	// without waiting for the result, the completion fn is called with the
+	// result once the write consistency level is reached or the write fails.
+	// The annotation must remain valid until the completion fn is called.
+	// Blocks while the maximum number of asynchronous writes are pending,
+	// asynchronous writes are not retried.
+	WriteTaggedAsync(namespace, id ident.ID, tags ident.TagIterator, t time.Time, value float64, unit xtime.Unit, annotation []byte, fn WriteCompletionFn)
+
+	// WriteBatch writes values to the database for a batch of IDs without
+	// waiting for the results, the completion fn is called with the index and
+	// result of each write in the batch and behaves as for WriteTaggedAsync.
+	WriteBatch(writes []WriteBatchElement, fn WriteBatchCompletionFn)
+
 	// Fetch values from the database for an ID
 	Fetch(namespace, id ident.ID, startInclusive, endExclusive time.Time) (encoding.SeriesIterator, error)
 
@@ -78,6 +91,27 @@ type Session interface {
 	// FetchTaggedIDs resolves the provided query to known IDs.
 	FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error)
 
+	// FetchTaggedPage resolves the provided query to known IDs, and fetches the
+	// data for them, a page at a time. The returned next page token requests the
+	// following page and is nil once all results have been returned, a nil page
+	// token requests the first page. The query options limit sets the page size.
+	FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions, pageToken []byte) (results encoding.SeriesIterators, nextPageToken []byte, err error)
+
+	// FetchTaggedIDsPage resolves the provided query to known IDs a page at a time,
+	// pagination behaves the same as FetchTaggedPage.
+	FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions, pageToken []byte) (iter TaggedIDsIterator, nextPageToken []byte, err error)
+
+	// FetchTaggedStream resolves the provided query to known IDs, and streams
+	// the data for them a batch of shards at a time rather than buffering every
+	// result. Each series is returned once with the replicas of every host that
+	// returned it merged, a batch is returned once all of its shards meet the
+	// read consistency level and is retried by itself.
+	FetchTaggedStream(namespace ident.ID, q index.Query, opts index.QueryOptions) (SeriesIteratorStream, error)
+
+	// Aggregate resolves the provided query to the distinct tag names, and
+	// optionally tag values, of the series that match it.
+	Aggregate(namespace ident.ID, q index.Query, opts index.AggregateQueryOptions) (index.AggregateQueryResults, error)
+
 	// ShardID returns the given shard for an ID for callers
 	// to easily discern what shard is failing when operations
 	// for given IDs begin failing
@@ -90,6 +124,26 @@ type Session interface {
 	Close() error
 }
 
+// WriteBatchElement is a value to write for an ID with WriteBatch, the value
+// is written with tags when tags are set.
+type WriteBatchElement struct {
+	Namespace  ident.ID
+	ID         ident.ID
+	Tags       ident.TagIterator
+	Timestamp  time.Time
+	Value      float64
+	Unit       xtime.Unit
+	Annotation []byte
+}
+
+// WriteCompletionFn is called with the result of an asynchronous write, it
+// is called from the goroutines performing requests and must not block.
+type WriteCompletionFn func(err error)
+
+// WriteBatchCompletionFn is called with the index in the batch and the result
+// of each write of an asynchronous batch write.
+type WriteBatchCompletionFn func(idx int, err error)
+
 // TaggedIDsIterator iterates over a collection of IDs with associated tags and namespace.
 type TaggedIDsIterator interface {
 	// Next returns whether there are more items in the collection.
@@ -106,6 +160,28 @@ type TaggedIDsIterator interface {
 	Finalize()
 }
 
+// SeriesIteratorStream iterates over series iterators as they are received
+// from the hosts responding to a fetch.
+type SeriesIteratorStream interface {
+	// Next returns whether there are more series, blocking until the next
+	// series is received.
+	Next() bool
+
+	// Current returns the current series iterator, ownership of the series
+	// iterator is transferred to the caller which must close it.
+	Current() encoding.SeriesIterator
+
+	// Exhaustive returns whether all matching series were returned, it is
+	// only valid once Next() has returned false.
+	Exhaustive() bool
+
+	// Err returns any error encountered.
+	Err() error
+
+	// Close stops the stream and releases any held resources.
+	Close()
+}
+
 // AdminClient can create administration sessions
 type AdminClient interface {
 	Client
@@ -402,6 +478,24 @@ type Options interface {
 	// FetchBatchSize returns the fetchBatchSize
 	FetchBatchSize() int
 
+	// SetFetchTaggedStreamShardBatchSize sets the number of shards whose
+	// series are fetched at a time by a streaming fetch tagged
+	SetFetchTaggedStreamShardBatchSize(value int) Options
+
+	// FetchTaggedStreamShardBatchSize returns the number of shards whose
+	// series are fetched at a time by a streaming fetch tagged
+	FetchTaggedStreamShardBatchSize() int
+
+	// SetFetchHedgePercentile sets the percentile, between zero and one, of a
+	// host's recent fetch latencies after which a fetch tagged request is
+	// hedged to additional replicas, zero disables hedged fetches
+	SetFetchHedgePercentile(value float64) Options
+
+	// FetchHedgePercentile returns the percentile, between zero and one, of a
+	// host's recent fetch latencies after which a fetch tagged request is
+	// hedged to additional replicas, zero disables hedged fetches
+	FetchHedgePercentile() float64
+
 	// SetWriteOpPoolSize sets the writeOperationPoolSize
 	SetWriteOpPoolSize(value int) Options
 
@@ -414,6 +508,58 @@ type Options interface {
 	// WriteTaggedOpPoolSize returns the writeTaggedOperationPoolSize
 	WriteTaggedOpPoolSize() int
 
+	// SetAsyncWriteMaxPending sets the maximum number of asynchronous writes
+	// that can be pending before further asynchronous writes block
+	SetAsyncWriteMaxPending(value int) Options
+
+	// AsyncWriteMaxPending returns the maximum number of asynchronous writes
+	// that can be pending before further asynchronous writes block
+	AsyncWriteMaxPending() int
+
+	// SetHintedHandoffDirectory sets the directory hints for writes that failed
+	// on a replica are persisted to until the host is reachable again, hinted
+	// handoff is disabled when empty
+	SetHintedHandoffDirectory(value string) Options
+
+	// HintedHandoffDirectory returns the directory hints for writes that failed
+	// on a replica are persisted to until the host is reachable again, hinted
+	// handoff is disabled when empty
+	HintedHandoffDirectory() string
+
+	// SetHintedHandoffMaxAge sets the maximum age of a hint, hints created
+	// or for datapoints older than the max age are discarded rather than
+	// replayed, it should not exceed the buffer past of the namespaces written
+	SetHintedHandoffMaxAge(value time.Duration) Options
+
+	// HintedHandoffMaxAge returns the maximum age of a hint, hints created
+	// or for datapoints older than the max age are discarded rather than
+	// replayed, it should not exceed the buffer past of the namespaces written
+	HintedHandoffMaxAge() time.Duration
+
+	// SetHintedHandoffMaxBytes sets the maximum size of the hints persisted for
+	// a single host, further hints for the host are discarded
+	SetHintedHandoffMaxBytes(value int64) Options
+
+	// HintedHandoffMaxBytes returns the maximum size of the hints persisted for
+	// a single host, further hints for the host are discarded
+	HintedHandoffMaxBytes() int64
+
+	// SetHintedHandoffReplayBatchSize sets the number of hints replayed to a
+	// host at a time, each batch completes before the next is replayed
+	SetHintedHandoffReplayBatchSize(value int) Options
+
+	// HintedHandoffReplayBatchSize returns the number of hints replayed to a
+	// host at a time, each batch completes before the next is replayed
+	HintedHandoffReplayBatchSize() int
+
+	// SetHintedHandoffReplayRate sets the maximum number of hints replayed to
+	// a host per second
+	SetHintedHandoffReplayRate(value int) Options
+
+	// HintedHandoffReplayRate returns the maximum number of hints replayed to
+	// a host per second
+	HintedHandoffReplayRate() int
+
 	// SetFetchBatchOpPoolSize sets the fetchBatchOpPoolSize
 	SetFetchBatchOpPoolSize(value int) Options
 
@@ -526,6 +672,14 @@ type AdminOptions interface {
 
 	// StreamBlocksRetrier returns the retrier for streaming blocks
 	StreamBlocksRetrier() xretry.Retrier
+
+	// SetStreamBlocksProgress sets the tracker of the progress of streaming
+	// blocks from peers when bootstrapping
+	SetStreamBlocksProgress(value StreamBlocksProgress) AdminOptions
+
+	// StreamBlocksProgress returns the tracker of the progress of streaming
+	// blocks from peers when bootstrapping
+	StreamBlocksProgress() StreamBlocksProgress
 }
 
 // The rest of these types are internal types that mocks are generated for
@@ -562,6 +716,10 @@ type hostQueue interface {
 	// BorrowConnection will borrow a connection and execute a user function
 	BorrowConnection(fn withConnectionFn) error
 
+	// FetchLatency returns the given percentile of the host's recent fetch
+	// tagged latencies, or false if too few have completed recently
+	FetchLatency(percentile float64) (time.Duration, bool)
+
 	// Close the host queue, will flush any operations still pending
 	Close()
 }
@@ -39,12 +39,26 @@ const (
 	taggedWriteAttemptType
 )
 
+const (
+	// resourceExhaustedInitialBackoff is the backoff before retrying a write
+	// that was rejected by a quota, it doubles with every rejection.
+	resourceExhaustedInitialBackoff = 100 * time.Millisecond
+	// resourceExhaustedMaxBackoff is the max backoff before retrying a write
+	// that was rejected by a quota.
+	resourceExhaustedMaxBackoff = 5 * time.Second
+)
+
 var writeAttemptArgsZeroed writeAttemptArgs
 
 type writeAttempt struct {
 	args writeAttemptArgs
 
+	// resourceExhaustedBackoff is the backoff to wait before the next
+	// attempt after the previous attempt was rejected by a quota.
+	resourceExhaustedBackoff time.Duration
+
 	session *session
+	sleepFn func(time.Duration)
 
 	attemptFn xretry.Fn
 }
@@ -62,9 +76,16 @@ type writeAttemptArgs struct {
 
 func (w *writeAttempt) reset() {
 	w.args = writeAttemptArgsZeroed
+	w.resourceExhaustedBackoff = 0
 }
 
 func (w *writeAttempt) perform() error {
+	if w.resourceExhaustedBackoff > 0 {
+		// The previous attempt was rejected by a quota, back off so that
+		// retries do not add load to nodes that are already over quota
+		w.sleepFn(w.resourceExhaustedBackoff)
+	}
+
 	err := w.session.writeAttempt(w.args.attemptType,
 		w.args.namespace, w.args.id, w.args.tags, w.args.t,
 		w.args.value, w.args.unit, w.args.annotation)
@@ -74,6 +95,18 @@ func (w *writeAttempt) perform() error {
 		err = xerrors.NewNonRetryableError(err)
 	}
 
+	if IsResourceExhaustedError(err) {
+		w.resourceExhaustedBackoff *= 2
+		if w.resourceExhaustedBackoff == 0 {
+			w.resourceExhaustedBackoff = resourceExhaustedInitialBackoff
+		}
+		if w.resourceExhaustedBackoff > resourceExhaustedMaxBackoff {
+			w.resourceExhaustedBackoff = resourceExhaustedMaxBackoff
+		}
+	} else {
+		w.resourceExhaustedBackoff = 0
+	}
+
 	return err
 }
 
@@ -92,7 +125,7 @@ func newWriteAttemptPool(
 
 func (p *writeAttemptPool) Init() {
 	p.pool.Init(func() interface{} {
-		w := &writeAttempt{session: p.session}
+		w := &writeAttempt{session: p.session, sleepFn: time.Sleep}
 		// NB(r): Bind attemptFn once to avoid creating receiver
 		// and function method pointer over and over again
 		w.attemptFn = w.perform
@@ -58,6 +58,15 @@ type writeState struct {
 	success           int32
 	errors            []error
 
+	// asyncFn is only set for asynchronous writes, it is called once with the
+	// lock held when the write would otherwise signal the waiting caller.
+	asyncFn   func(w *writeState)
+	asyncDone bool
+
+	// hints is only set when hinted handoff is enabled, writes that fail on a
+	// host are persisted to it to be replayed once the host is reachable.
+	hints *hintedHandoff
+
 	queues         []hostQueue
 	tagEncoderPool serialize.TagEncoderPool
 	pool           *writeStatePool
@@ -88,6 +97,8 @@ func (w *writeState) close() {
 
 	w.op, w.majority, w.pending, w.success = nil, 0, 0, 0
 	w.nsID, w.tsID, w.tagEncoder = nil, nil, nil
+	w.asyncFn, w.asyncDone = nil, false
+	w.hints = nil
 
 	for i := range w.errors {
 		w.errors[i] = nil
@@ -112,10 +123,17 @@ func (w *writeState) completionFn(result interface{}, err error) {
 	w.Lock()
 	w.pending--
 
-	var wErr error
+	var (
+		wErr   error
+		hinted bool
+		value  hint
+	)
 
 	if err != nil {
 		wErr = xerrors.NewRenamedError(err, fmt.Errorf("error writing to host %s: %v", hostID, err))
+		if w.hints != nil && !IsBadRequestError(err) && !IsResourceExhaustedError(err) {
+			value, hinted = w.hint()
+		}
 	} else if hostShardSet, ok := w.topoMap.LookupHostShardSet(hostID); !ok {
 		errStr := "missing host shard in writeState completionFn: %s"
 		wErr = xerrors.NewRetryableError(fmt.Errorf(errStr, hostID))
@@ -142,23 +160,69 @@ func (w *writeState) completionFn(result interface{}, err error) {
 		w.errors = append(w.errors, wErr)
 	}
 
+	var done bool
 	switch w.consistencyLevel {
 	case topology.ConsistencyLevelOne:
-		if w.success > 0 || w.pending == 0 {
-			w.Signal()
-		}
+		done = w.success > 0 || w.pending == 0
 	case topology.ConsistencyLevelMajority:
-		if w.success >= w.majority || w.pending == 0 {
-			w.Signal()
-		}
+		done = w.success >= w.majority || w.pending == 0
 	case topology.ConsistencyLevelAll:
-		if w.pending == 0 {
+		done = w.pending == 0
+	}
+
+	var asyncDone bool
+	if done {
+		if w.asyncFn == nil {
 			w.Signal()
+		} else if !w.asyncDone {
+			w.asyncDone, asyncDone = true, true
+			w.asyncFn(w)
 		}
 	}
 
 	w.Unlock()
+
+	if hinted {
+		// NB: add the hint before releasing the reference held on behalf of
+		// the host queue as the hint references the op's request until it is
+		// encoded, adding it only buffers it in memory.
+		w.hints.Add(hostID, value)
+	}
+
 	w.decRef()
+
+	if asyncDone {
+		// Release the reference held on behalf of the asynchronous caller.
+		w.decRef()
+	}
+}
+
+func (w *writeState) hint() (hint, bool) {
+	switch op := w.op.(type) {
+	case *writeOperation:
+		return hint{
+			namespace:  op.namespace.Bytes(),
+			id:         op.request.ID,
+			timestamp:  op.datapoint.Timestamp,
+			timeType:   op.datapoint.TimestampTimeType,
+			value:      op.datapoint.Value,
+			annotation: op.datapoint.Annotation,
+		}, true
+	case *writeTaggedOperation:
+		return hint{
+			tagged:      true,
+			namespace:   op.namespace.Bytes(),
+			id:          op.request.ID,
+			encodedTags: op.request.EncodedTags,
+			timestamp:   op.datapoint.Timestamp,
+			timeType:    op.datapoint.TimestampTimeType,
+			value:       op.datapoint.Value,
+			annotation:  op.datapoint.Annotation,
+		}, true
+	default:
+		// should never happen
+		return hint{}, false
+	}
 }
 
 type writeStatePool struct {
@@ -141,6 +141,8 @@ type NamespaceOptions struct {
 	RetentionOptions  *RetentionOptions `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
 	SnapshotEnabled   bool              `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
 	IndexOptions      *IndexOptions     `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
+	DataCompression   string            `protobuf:"bytes,9,opt,name=dataCompression,proto3" json:"dataCompression,omitempty"`
+	IndexOnly         bool              `protobuf:"varint,10,opt,name=indexOnly,proto3" json:"indexOnly,omitempty"`
 }
 
 func (m *NamespaceOptions) Reset()                    { *m = NamespaceOptions{} }
@@ -204,6 +206,20 @@ func (m *NamespaceOptions) GetIndexOptions() *IndexOptions {
 	return nil
 }
 
+func (m *NamespaceOptions) GetDataCompression() string {
+	if m != nil {
+		return m.DataCompression
+	}
+	return ""
+}
+
+func (m *NamespaceOptions) GetIndexOnly() bool {
+	if m != nil {
+		return m.IndexOnly
+	}
+	return false
+}
+
 type Registry struct {
 	Namespaces map[string]*NamespaceOptions `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
 }
@@ -407,6 +423,22 @@ func (m *NamespaceOptions) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n2
 	}
+	if len(m.DataCompression) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.DataCompression)))
+		i += copy(dAtA[i:], m.DataCompression)
+	}
+	if m.IndexOnly {
+		dAtA[i] = 0x50
+		i++
+		if m.IndexOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -530,6 +562,13 @@ func (m *NamespaceOptions) Size() (n int) {
 		l = m.IndexOptions.Size()
 		n += 1 + l + sovNamespace(uint64(l))
 	}
+	l = len(m.DataCompression)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.IndexOnly {
+		n += 2
+	}
 	return n
 }
 
@@ -1034,6 +1073,55 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataCompression", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataCompression = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IndexOnly = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
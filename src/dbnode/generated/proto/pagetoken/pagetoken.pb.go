@@ -29,6 +29,8 @@
 
 	It has these top-level messages:
 		PageToken
+		IndexQueryPageToken
+		FetchTaggedPageToken
 */
 package pagetoken
 
@@ -117,10 +119,95 @@ func (m *PageToken_FlushedSeriesPhase) GetCurrBlockEntryIdx() int64 {
 	return 0
 }
 
+type IndexQueryPageToken struct {
+	BlockStartUnixNanos int64  `protobuf:"varint,1,opt,name=blockStartUnixNanos,proto3" json:"blockStartUnixNanos,omitempty"`
+	Segment             int64  `protobuf:"varint,2,opt,name=segment,proto3" json:"segment,omitempty"`
+	DocID               []byte `protobuf:"bytes,3,opt,name=docID,proto3" json:"docID,omitempty"`
+}
+
+func (m *IndexQueryPageToken) Reset()                    { *m = IndexQueryPageToken{} }
+func (m *IndexQueryPageToken) String() string            { return proto.CompactTextString(m) }
+func (*IndexQueryPageToken) ProtoMessage()               {}
+func (*IndexQueryPageToken) Descriptor() ([]byte, []int) { return fileDescriptorPagetoken, []int{1} }
+
+func (m *IndexQueryPageToken) GetBlockStartUnixNanos() int64 {
+	if m != nil {
+		return m.BlockStartUnixNanos
+	}
+	return 0
+}
+
+func (m *IndexQueryPageToken) GetSegment() int64 {
+	if m != nil {
+		return m.Segment
+	}
+	return 0
+}
+
+func (m *IndexQueryPageToken) GetDocID() []byte {
+	if m != nil {
+		return m.DocID
+	}
+	return nil
+}
+
+type FetchTaggedPageToken struct {
+	Hosts []*FetchTaggedPageToken_HostPageToken `protobuf:"bytes,1,rep,name=hosts" json:"hosts,omitempty"`
+}
+
+func (m *FetchTaggedPageToken) Reset()                    { *m = FetchTaggedPageToken{} }
+func (m *FetchTaggedPageToken) String() string            { return proto.CompactTextString(m) }
+func (*FetchTaggedPageToken) ProtoMessage()               {}
+func (*FetchTaggedPageToken) Descriptor() ([]byte, []int) { return fileDescriptorPagetoken, []int{2} }
+
+func (m *FetchTaggedPageToken) GetHosts() []*FetchTaggedPageToken_HostPageToken {
+	if m != nil {
+		return m.Hosts
+	}
+	return nil
+}
+
+type FetchTaggedPageToken_HostPageToken struct {
+	HostID    string `protobuf:"bytes,1,opt,name=hostID,proto3" json:"hostID,omitempty"`
+	PageToken []byte `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	Exhausted bool   `protobuf:"varint,3,opt,name=exhausted,proto3" json:"exhausted,omitempty"`
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) Reset()         { *m = FetchTaggedPageToken_HostPageToken{} }
+func (m *FetchTaggedPageToken_HostPageToken) String() string { return proto.CompactTextString(m) }
+func (*FetchTaggedPageToken_HostPageToken) ProtoMessage()    {}
+func (*FetchTaggedPageToken_HostPageToken) Descriptor() ([]byte, []int) {
+	return fileDescriptorPagetoken, []int{2, 0}
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) GetHostID() string {
+	if m != nil {
+		return m.HostID
+	}
+	return ""
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) GetPageToken() []byte {
+	if m != nil {
+		return m.PageToken
+	}
+	return nil
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) GetExhausted() bool {
+	if m != nil {
+		return m.Exhausted
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*PageToken)(nil), "pagetoken.PageToken")
 	proto.RegisterType((*PageToken_ActiveSeriesPhase)(nil), "pagetoken.PageToken.ActiveSeriesPhase")
 	proto.RegisterType((*PageToken_FlushedSeriesPhase)(nil), "pagetoken.PageToken.FlushedSeriesPhase")
+	proto.RegisterType((*IndexQueryPageToken)(nil), "pagetoken.IndexQueryPageToken")
+	proto.RegisterType((*FetchTaggedPageToken)(nil), "pagetoken.FetchTaggedPageToken")
+	proto.RegisterType((*FetchTaggedPageToken_HostPageToken)(nil), "pagetoken.FetchTaggedPageToken.HostPageToken")
 }
 func (m *PageToken) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -211,6 +298,110 @@ func (m *PageToken_FlushedSeriesPhase) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *IndexQueryPageToken) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *IndexQueryPageToken) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.BlockStartUnixNanos != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintPagetoken(dAtA, i, uint64(m.BlockStartUnixNanos))
+	}
+	if m.Segment != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintPagetoken(dAtA, i, uint64(m.Segment))
+	}
+	if len(m.DocID) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintPagetoken(dAtA, i, uint64(len(m.DocID)))
+		i += copy(dAtA[i:], m.DocID)
+	}
+	return i, nil
+}
+
+func (m *FetchTaggedPageToken) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchTaggedPageToken) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hosts) > 0 {
+		for _, msg := range m.Hosts {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintPagetoken(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.HostID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintPagetoken(dAtA, i, uint64(len(m.HostID)))
+		i += copy(dAtA[i:], m.HostID)
+	}
+	if len(m.PageToken) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintPagetoken(dAtA, i, uint64(len(m.PageToken)))
+		i += copy(dAtA[i:], m.PageToken)
+	}
+	if m.Exhausted {
+		dAtA[i] = 0x18
+		i++
+		if m.Exhausted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
 func encodeVarintPagetoken(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -255,6 +446,51 @@ func (m *PageToken_FlushedSeriesPhase) Size() (n int) {
 	return n
 }
 
+func (m *IndexQueryPageToken) Size() (n int) {
+	var l int
+	_ = l
+	if m.BlockStartUnixNanos != 0 {
+		n += 1 + sovPagetoken(uint64(m.BlockStartUnixNanos))
+	}
+	if m.Segment != 0 {
+		n += 1 + sovPagetoken(uint64(m.Segment))
+	}
+	l = len(m.DocID)
+	if l > 0 {
+		n += 1 + l + sovPagetoken(uint64(l))
+	}
+	return n
+}
+
+func (m *FetchTaggedPageToken) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Hosts) > 0 {
+		for _, e := range m.Hosts {
+			l = e.Size()
+			n += 1 + l + sovPagetoken(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FetchTaggedPageToken_HostPageToken) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.HostID)
+	if l > 0 {
+		n += 1 + l + sovPagetoken(uint64(l))
+	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovPagetoken(uint64(l))
+	}
+	if m.Exhausted {
+		n += 2
+	}
+	return n
+}
+
 func sovPagetoken(x uint64) (n int) {
 	for {
 		n++
@@ -541,6 +777,336 @@ func (m *PageToken_FlushedSeriesPhase) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *IndexQueryPageToken) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPagetoken
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IndexQueryPageToken: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IndexQueryPageToken: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockStartUnixNanos", wireType)
+			}
+			m.BlockStartUnixNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockStartUnixNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segment", wireType)
+			}
+			m.Segment = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Segment |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DocID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DocID = append(m.DocID[:0], dAtA[iNdEx:postIndex]...)
+			if m.DocID == nil {
+				m.DocID = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPagetoken(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchTaggedPageToken) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPagetoken
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FetchTaggedPageToken: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FetchTaggedPageToken: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hosts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hosts = append(m.Hosts, &FetchTaggedPageToken_HostPageToken{})
+			if err := m.Hosts[len(m.Hosts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPagetoken(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchTaggedPageToken_HostPageToken) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPagetoken
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HostPageToken: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HostPageToken: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = append(m.PageToken[:0], dAtA[iNdEx:postIndex]...)
+			if m.PageToken == nil {
+				m.PageToken = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exhausted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPagetoken
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exhausted = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPagetoken(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPagetoken
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPagetoken(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -651,24 +1217,31 @@ func init() {
 }
 
 var fileDescriptorPagetoken = []byte{
-	// 291 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x72, 0x4f, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0xd2, 0xcf, 0x35, 0xd6, 0x2f,
-	0x2e, 0x4a, 0xd6, 0x4f, 0x49, 0xca, 0xcb, 0x4f, 0x49, 0xd5, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d, 0x4a,
-	0x2c, 0x49, 0x4d, 0xd1, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0xd7, 0x2f, 0x48, 0x4c, 0x4f, 0x2d, 0xc9,
-	0xcf, 0x4e, 0xcd, 0x43, 0xb0, 0xf4, 0xc0, 0x32, 0x42, 0x9c, 0x70, 0x01, 0xa5, 0xcf, 0x4c, 0x5c,
-	0x9c, 0x01, 0x89, 0xe9, 0xa9, 0x21, 0x20, 0x9e, 0x50, 0x18, 0x97, 0x70, 0x62, 0x72, 0x49, 0x66,
-	0x59, 0x6a, 0x7c, 0x71, 0x6a, 0x51, 0x66, 0x6a, 0x71, 0x7c, 0x41, 0x46, 0x62, 0x71, 0xaa, 0x04,
-	0xa3, 0x02, 0xa3, 0x06, 0xb7, 0x91, 0x9a, 0x1e, 0xc2, 0x1c, 0xb8, 0x16, 0x3d, 0x47, 0xb0, 0xfa,
-	0x60, 0xb0, 0xf2, 0x00, 0x90, 0xea, 0x20, 0xc1, 0x44, 0x74, 0x21, 0xa1, 0x48, 0x2e, 0x91, 0xb4,
-	0x9c, 0xd2, 0xe2, 0x8c, 0xd4, 0x14, 0x54, 0x83, 0x99, 0xc0, 0x06, 0xab, 0x63, 0x35, 0xd8, 0x0d,
-	0xa2, 0x01, 0xd9, 0x64, 0xa1, 0x34, 0x0c, 0x31, 0x29, 0x53, 0x2e, 0x41, 0x0c, 0x27, 0x08, 0x29,
-	0x70, 0x71, 0x67, 0xe6, 0xa5, 0xa4, 0x56, 0x38, 0x97, 0x16, 0x15, 0xe7, 0x17, 0x81, 0xdd, 0xcf,
-	0x1c, 0x84, 0x2c, 0x24, 0x55, 0xc3, 0x25, 0x84, 0x69, 0x81, 0x90, 0x05, 0x97, 0x78, 0x72, 0x69,
-	0x51, 0x91, 0x53, 0x4e, 0x7e, 0x72, 0x76, 0x70, 0x49, 0x62, 0x51, 0x49, 0x68, 0x5e, 0x66, 0x85,
-	0x5f, 0x62, 0x5e, 0x7e, 0x31, 0xd4, 0x0c, 0x5c, 0xd2, 0x42, 0x3a, 0x5c, 0x82, 0x70, 0x29, 0xd7,
-	0xbc, 0x92, 0xa2, 0x4a, 0xcf, 0x94, 0x0a, 0xb0, 0xf7, 0x98, 0x83, 0x30, 0x25, 0x9c, 0x04, 0x4e,
-	0x3c, 0x92, 0x63, 0xbc, 0xf0, 0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x09, 0x8f, 0xe5, 0x18,
-	0x92, 0xd8, 0xc0, 0x31, 0x63, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0xc9, 0xdd, 0x02, 0xeb, 0xe4,
-	0x01, 0x00, 0x00,
+	// 415 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x75, 0x52, 0x4b, 0x4e, 0xc3, 0x30,
+	0x10, 0xa5, 0x54, 0x05, 0x3a, 0x05, 0x89, 0xba, 0x15, 0x54, 0x15, 0xaa, 0x50, 0x17, 0xc0, 0x02,
+	0x12, 0x04, 0x42, 0x62, 0xcb, 0xaf, 0xd0, 0x0d, 0x82, 0xf0, 0x91, 0x58, 0x21, 0x27, 0x1e, 0x92,
+	0x08, 0x1a, 0x57, 0xb6, 0x03, 0x45, 0xe2, 0x10, 0x1c, 0x89, 0x25, 0x4b, 0x8e, 0x80, 0xe0, 0x08,
+	0x5c, 0x00, 0xc7, 0x2d, 0x4d, 0xa1, 0x65, 0x61, 0x2b, 0xef, 0x33, 0x6f, 0xc6, 0xb1, 0xe1, 0xd0,
+	0x0f, 0x55, 0x10, 0xbb, 0x96, 0xc7, 0x5b, 0x76, 0x6b, 0x93, 0xb9, 0x7a, 0xb3, 0xa5, 0xf0, 0x6c,
+	0xe6, 0x46, 0x9c, 0xa1, 0xed, 0x63, 0x84, 0x82, 0x2a, 0x64, 0x76, 0x5b, 0x70, 0xc5, 0xed, 0x36,
+	0xf5, 0x51, 0xf1, 0x5b, 0x8c, 0xd2, 0x2f, 0xcb, 0x28, 0x24, 0xdf, 0x27, 0xea, 0x5f, 0xe3, 0x90,
+	0x3f, 0xd1, 0xe8, 0x3c, 0x41, 0xe4, 0x12, 0x4a, 0xd4, 0x53, 0xe1, 0x3d, 0x5e, 0x4b, 0x14, 0x21,
+	0xca, 0xeb, 0x76, 0x40, 0x25, 0x56, 0x32, 0x8b, 0x99, 0x95, 0xc2, 0xc6, 0x92, 0x95, 0xe6, 0xf4,
+	0x4b, 0xac, 0x1d, 0xe3, 0x3f, 0x33, 0xf6, 0x93, 0xc4, 0xed, 0x14, 0xe9, 0x5f, 0x8a, 0x5c, 0x41,
+	0xf9, 0xe6, 0x2e, 0x96, 0x01, 0xb2, 0xdf, 0xc1, 0xe3, 0x26, 0x78, 0x79, 0x64, 0x70, 0xa3, 0x5b,
+	0x30, 0x98, 0x4c, 0x6e, 0x86, 0xb8, 0xea, 0x16, 0x14, 0x87, 0x46, 0x20, 0x8b, 0x50, 0x08, 0x23,
+	0x86, 0x9d, 0xbd, 0x58, 0x48, 0x2e, 0xcc, 0xfc, 0x59, 0x67, 0x90, 0xaa, 0x3e, 0x01, 0x19, 0x6e,
+	0x40, 0xb6, 0x61, 0xde, 0x8b, 0x85, 0xd8, 0xbd, 0xe3, 0xde, 0xed, 0x99, 0xa2, 0x42, 0x5d, 0x44,
+	0x61, 0xe7, 0x98, 0x46, 0x5c, 0xf6, 0x32, 0xfe, 0x93, 0xc9, 0x2a, 0x14, 0xfb, 0xd2, 0x41, 0xa4,
+	0xc4, 0x63, 0x93, 0x75, 0xcc, 0xf1, 0xb2, 0xce, 0xb0, 0x50, 0x7f, 0x80, 0x52, 0x33, 0x19, 0xe6,
+	0x34, 0x46, 0xf1, 0x98, 0xfe, 0xfe, 0x75, 0x28, 0xb9, 0xff, 0xb6, 0x1e, 0x25, 0x91, 0x0a, 0x4c,
+	0x4a, 0xf4, 0x5b, 0x18, 0xa9, 0x5e, 0xb3, 0x1f, 0x48, 0xca, 0x90, 0x63, 0xdc, 0x6b, 0xee, 0x57,
+	0xb2, 0x9a, 0x9f, 0x76, 0xba, 0xa0, 0xfe, 0x92, 0x81, 0x72, 0x03, 0x95, 0x17, 0x9c, 0x53, 0xdf,
+	0x47, 0x96, 0xb6, 0xde, 0x83, 0x5c, 0xc0, 0xa5, 0x4a, 0x9a, 0x65, 0xf5, 0x95, 0xac, 0x0d, 0x5c,
+	0xc9, 0x28, 0xbf, 0x75, 0xa4, 0xcd, 0x7d, 0xe4, 0x74, 0x6b, 0xab, 0x1e, 0xcc, 0xfc, 0xe2, 0xc9,
+	0x1c, 0x4c, 0x24, 0x8a, 0x9e, 0x22, 0x39, 0x43, 0xde, 0xe9, 0x21, 0xb2, 0x00, 0xe6, 0x09, 0x1a,
+	0x93, 0x19, 0x7c, 0xda, 0x49, 0x89, 0x44, 0xc5, 0x4e, 0x40, 0x63, 0xa9, 0xdf, 0xb2, 0x19, 0x7f,
+	0xca, 0x49, 0x89, 0xdd, 0xd9, 0xd7, 0x8f, 0x5a, 0xe6, 0x4d, 0xaf, 0x77, 0xbd, 0x9e, 0x3f, 0x6b,
+	0x63, 0xee, 0x84, 0x79, 0xd5, 0x9b, 0xdf, 0xab, 0xcd, 0xfd, 0x4a, 0x20, 0x03, 0x00, 0x00,
 }
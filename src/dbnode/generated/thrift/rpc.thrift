@@ -29,7 +29,8 @@ enum TimeType {
 
 enum ErrorType {
 	INTERNAL_ERROR,
-	BAD_REQUEST
+	BAD_REQUEST,
+	RESOURCE_EXHAUSTED
 }
 
 exception Error {
@@ -46,6 +47,7 @@ service Node {
 	QueryResult query(1: QueryRequest req) throws (1: Error err)
 	FetchResult fetch(1: FetchRequest req) throws (1: Error err)
 	FetchTaggedResult fetchTagged(1: FetchTaggedRequest req) throws (1: Error err)
+	AggregateQueryResult aggregate(1: AggregateQueryRequest req) throws (1: Error err)
 	void write(1: WriteRequest req) throws (1: Error err)
 	void writeTagged(1: WriteTaggedRequest req) throws (1: Error err)
 
@@ -70,6 +72,7 @@ service Node {
 	NodeWriteNewSeriesBackoffDurationResult setWriteNewSeriesBackoffDuration(1: NodeSetWriteNewSeriesBackoffDurationRequest req) throws (1: Error err)
 	NodeWriteNewSeriesLimitPerShardPerSecondResult getWriteNewSeriesLimitPerShardPerSecond() throws (1: Error err)
 	NodeWriteNewSeriesLimitPerShardPerSecondResult setWriteNewSeriesLimitPerShardPerSecond(1: NodeSetWriteNewSeriesLimitPerShardPerSecondRequest req) throws (1: Error err)
+	NodeSnapshotResult snapshot(1: NodeSnapshotRequest req) throws (1: Error err)
 }
 
 struct FetchRequest {
@@ -142,11 +145,14 @@ struct FetchTaggedRequest {
 	5: required bool fetchData
 	6: optional i64 limit
 	7: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	8: optional binary pageToken
+	9: optional list<i32> shards
 }
 
 struct FetchTaggedResult {
 	1: required list<FetchTaggedIDResult> elements
 	2: required bool exhaustive
+	3: optional binary nextPageToken
 }
 
 struct FetchTaggedIDResult {
@@ -157,6 +163,33 @@ struct FetchTaggedIDResult {
 	5: optional Error err
 }
 
+struct AggregateQueryRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+	5: optional list<binary> tagNameFilter
+	6: optional bool tagNamesOnly = false
+	7: optional i64 limit
+	8: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+}
+
+struct AggregateQueryResult {
+	1: required list<AggregateQueryResultTagNameElement> results
+	2: required bool exhaustive
+}
+
+struct AggregateQueryResultTagNameElement {
+	1: required binary tagName
+	2: required i64 count
+	3: optional list<AggregateQueryResultTagValueElement> tagValues
+}
+
+struct AggregateQueryResultTagValueElement {
+	1: required binary tagValue
+	2: required i64 count
+}
+
 struct FetchBlocksRawRequest {
 	1: required binary nameSpace
 	2: required i32 shard
@@ -297,6 +330,14 @@ struct NodeSetWriteNewSeriesLimitPerShardPerSecondRequest {
 	1: required i64 writeNewSeriesLimitPerShardPerSecond
 }
 
+struct NodeSnapshotRequest {}
+
+struct NodeSnapshotResult {
+	1: required string snapshotID
+	2: required i64 snapshotTime
+	3: required TimeType snapshotTimeType
+}
+
 service Cluster {
 	HealthResult health() throws (1: Error err)
 	void write(1: WriteRequest req) throws (1: Error err)
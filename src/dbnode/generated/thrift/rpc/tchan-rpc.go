@@ -45,6 +45,7 @@ type TChanCluster interface {
 
 // TChanNode is the interface that defines the server handler and client interface.
 type TChanNode interface {
+	Aggregate(ctx thrift.Context, req *AggregateQueryRequest) (*AggregateQueryResult_, error)
 	Bootstrapped(ctx thrift.Context) (*NodeBootstrappedResult_, error)
 	Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error)
 	FetchBatchRaw(ctx thrift.Context, req *FetchBatchRawRequest) (*FetchBatchRawResult_, error)
@@ -62,6 +63,7 @@ type TChanNode interface {
 	SetWriteNewSeriesAsync(ctx thrift.Context, req *NodeSetWriteNewSeriesAsyncRequest) (*NodeWriteNewSeriesAsyncResult_, error)
 	SetWriteNewSeriesBackoffDuration(ctx thrift.Context, req *NodeSetWriteNewSeriesBackoffDurationRequest) (*NodeWriteNewSeriesBackoffDurationResult_, error)
 	SetWriteNewSeriesLimitPerShardPerSecond(ctx thrift.Context, req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (*NodeWriteNewSeriesLimitPerShardPerSecondResult_, error)
+	Snapshot(ctx thrift.Context, req *NodeSnapshotRequest) (*NodeSnapshotResult_, error)
 	Truncate(ctx thrift.Context, req *TruncateRequest) (*TruncateResult_, error)
 	Write(ctx thrift.Context, req *WriteRequest) error
 	WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) error
@@ -473,6 +475,24 @@ func NewTChanNodeClient(client thrift.TChanClient) TChanNode {
 	return NewTChanNodeInheritedClient("Node", client)
 }
 
+func (c *tchanNodeClient) Aggregate(ctx thrift.Context, req *AggregateQueryRequest) (*AggregateQueryResult_, error) {
+	var resp NodeAggregateResult
+	args := NodeAggregateArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "aggregate", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for aggregate")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) Bootstrapped(ctx thrift.Context) (*NodeBootstrappedResult_, error) {
 	var resp NodeBootstrappedResult
 	args := NodeBootstrappedArgs{}
@@ -765,6 +785,24 @@ func (c *tchanNodeClient) SetWriteNewSeriesLimitPerShardPerSecond(ctx thrift.Con
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) Snapshot(ctx thrift.Context, req *NodeSnapshotRequest) (*NodeSnapshotResult_, error) {
+	var resp NodeSnapshotResult
+	args := NodeSnapshotArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "snapshot", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for snapshot")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) Truncate(ctx thrift.Context, req *TruncateRequest) (*TruncateResult_, error) {
 	var resp NodeTruncateResult
 	args := NodeTruncateArgs{
@@ -873,6 +911,7 @@ func (s *tchanNodeServer) Service() string {
 
 func (s *tchanNodeServer) Methods() []string {
 	return []string{
+		"aggregate",
 		"bootstrapped",
 		"fetch",
 		"fetchBatchRaw",
@@ -890,6 +929,7 @@ func (s *tchanNodeServer) Methods() []string {
 		"setWriteNewSeriesAsync",
 		"setWriteNewSeriesBackoffDuration",
 		"setWriteNewSeriesLimitPerShardPerSecond",
+		"snapshot",
 		"truncate",
 		"write",
 		"writeBatchRaw",
@@ -900,6 +940,8 @@ func (s *tchanNodeServer) Methods() []string {
 
 func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	switch methodName {
+	case "aggregate":
+		return s.handleAggregate(ctx, protocol)
 	case "bootstrapped":
 		return s.handleBootstrapped(ctx, protocol)
 	case "fetch":
@@ -934,6 +976,8 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleSetWriteNewSeriesBackoffDuration(ctx, protocol)
 	case "setWriteNewSeriesLimitPerShardPerSecond":
 		return s.handleSetWriteNewSeriesLimitPerShardPerSecond(ctx, protocol)
+	case "snapshot":
+		return s.handleSnapshot(ctx, protocol)
 	case "truncate":
 		return s.handleTruncate(ctx, protocol)
 	case "write":
@@ -950,6 +994,34 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 	}
 }
 
+func (s *tchanNodeServer) handleAggregate(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeAggregateArgs
+	var res NodeAggregateResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.Aggregate(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleBootstrapped(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeBootstrappedArgs
 	var res NodeBootstrappedResult
@@ -1425,6 +1497,34 @@ func (s *tchanNodeServer) handleSetWriteNewSeriesLimitPerShardPerSecond(ctx thri
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleSnapshot(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeSnapshotArgs
+	var res NodeSnapshotResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.Snapshot(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleTruncate(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeTruncateArgs
 	var res NodeTruncateResult
@@ -44,4 +44,8 @@ const (
 	// ClientWriteConsistencyLevel is the KV config key for the runtime
 	// configuration specifying the client write consistency level
 	ClientWriteConsistencyLevel = "m3db.client.write-consistency-level"
+
+	// QuotaLimitsKey is the KV config key for the runtime configuration
+	// specifying the per-namespace and per-tenant quota limits as JSON
+	QuotaLimitsKey = "m3db.node.quota-limits"
 )
@@ -209,9 +209,45 @@ func RegisterHandlers(mux *http.ServeMux, service interface{}, opts ServerOption
 			w.Write(buff.Bytes())
 		})
 	}
+	for path, handler := range opts.Handlers() {
+		mux.Handle(path, handler)
+	}
 	return nil
 }
 
+// GetHandlerFn returns the result to respond with to a GET request
+type GetHandlerFn func(r *http.Request) (interface{}, error)
+
+// NewGetHandler returns a HTTP handler that responds to GET requests with
+// the JSON encoded result of the given fn
+func NewGetHandler(fn GetHandlerFn) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		// Always close the request body
+		defer r.Body.Close()
+
+		if strings.ToUpper(r.Method) != "GET" {
+			writeError(w, errRequestMustBeGet)
+			return
+		}
+
+		result, err := fn(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		buff := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buff).Encode(result); err != nil {
+			writeError(w, fmt.Errorf("failed to encode response body: %v", err))
+			return
+		}
+
+		w.Write(buff.Bytes())
+	})
+}
+
 func writeError(w http.ResponseWriter, errValue interface{}) {
 	result := respErrorResult{respError{}}
 	if value, ok := errValue.(error); ok {
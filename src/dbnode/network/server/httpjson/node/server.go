@@ -21,6 +21,7 @@
 package node
 
 import (
+	"errors"
 	"net"
 	"net/http"
 
@@ -29,7 +30,23 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
 	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3x/context"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+)
+
+const (
+	// CardinalityPath is the path of the endpoint reporting the tag names
+	// and metric names with the highest estimated cardinality.
+	CardinalityPath = "/cardinality"
+
+	cardinalityNamespaceParam = "namespace"
+)
+
+var (
+	errCardinalityNamespaceRequired = xerrors.NewInvalidParamsError(
+		errors.New("namespace is required"))
 )
 
 type server struct {
@@ -69,6 +86,7 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 	if err := httpjson.RegisterHandlers(mux, ttnode.NewService(s.db, s.ttopts), s.opts); err != nil {
 		return nil, err
 	}
+	mux.Handle(CardinalityPath, httpjson.NewGetHandler(s.cardinality))
 
 	listener, err := net.Listen("tcp", s.address)
 	if err != nil {
@@ -89,3 +107,18 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 		listener.Close()
 	}, nil
 }
+
+func (s *server) cardinality(r *http.Request) (interface{}, error) {
+	values := r.URL.Query()
+	namespace := values.Get(cardinalityNamespaceParam)
+	if namespace == "" {
+		return nil, errCardinalityNamespaceRequired
+	}
+
+	opts, err := cardinality.ParseReportOptions(values)
+	if err != nil {
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+
+	return s.db.Cardinality(ident.StringID(namespace), opts)
+}
@@ -21,6 +21,7 @@
 package httpjson
 
 import (
+	"net/http"
 	"time"
 
 	apachethrift "github.com/apache/thrift/lib/go/thrift"
@@ -72,6 +73,13 @@ type ServerOptions interface {
 
 	// PostResponseFn returns the post response fn
 	PostResponseFn() PostResponseFn
+
+	// SetHandlers sets additional handlers keyed by path to register
+	// alongside the service methods and returns a new ServerOptions
+	SetHandlers(value map[string]http.Handler) ServerOptions
+
+	// Handlers returns the additional handlers keyed by path
+	Handlers() map[string]http.Handler
 }
 
 type serverOptions struct {
@@ -80,6 +88,7 @@ type serverOptions struct {
 	requestTimeout time.Duration
 	contextFn      ContextFn
 	postResponseFn PostResponseFn
+	handlers       map[string]http.Handler
 }
 
 // NewServerOptions creates a new set of server options with defaults
@@ -140,3 +149,13 @@ func (o *serverOptions) SetPostResponseFn(value PostResponseFn) ServerOptions {
 func (o *serverOptions) PostResponseFn() PostResponseFn {
 	return o.postResponseFn
 }
+
+func (o *serverOptions) SetHandlers(value map[string]http.Handler) ServerOptions {
+	opts := *o
+	opts.handlers = value
+	return &opts
+}
+
+func (o *serverOptions) Handlers() map[string]http.Handler {
+	return o.handlers
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
@@ -175,6 +176,21 @@ func ToSegments(blocks []xio.BlockReader) (ToSegmentsResult, error) {
 	return ToSegmentsResult{Segments: s}, nil
 }
 
+// SegmentsLen returns the number of bytes of data held by segments.
+func SegmentsLen(segments *rpc.Segments) int64 {
+	if segments == nil {
+		return 0
+	}
+	var n int64
+	if seg := segments.Merged; seg != nil {
+		n += int64(len(seg.Head) + len(seg.Tail))
+	}
+	for _, seg := range segments.Unmerged {
+		n += int64(len(seg.Head) + len(seg.Tail))
+	}
+	return n
+}
+
 func bytesRef(data checked.Bytes) []byte {
 	if data != nil {
 		return data.Bytes()
@@ -190,6 +206,9 @@ func ToRPCError(err error) *rpc.Error {
 	if xerrors.IsInvalidParams(err) {
 		return tterrors.NewBadRequestError(err)
 	}
+	if quota.IsExceededError(err) {
+		return tterrors.NewResourceExhaustedError(err)
+	}
 	return tterrors.NewInternalError(err)
 }
 
@@ -223,6 +242,11 @@ func FromRPCFetchTaggedRequest(
 	if l := req.Limit; l != nil {
 		opts.Limit = int(*l)
 	}
+	if req.PageToken != nil {
+		// NB: an empty but set page token requests the first page.
+		opts.Paginate = true
+		opts.PageToken = index.PageToken(req.PageToken)
+	}
 
 	q, err := idx.Unmarshal(req.Query)
 	if err != nil {
@@ -274,9 +298,128 @@ func ToRPCFetchTaggedRequest(
 		request.Limit = &l
 	}
 
+	if opts.Paginate {
+		request.PageToken = []byte(opts.PageToken)
+		if request.PageToken == nil {
+			request.PageToken = []byte{}
+		}
+	}
+
 	return request, nil
 }
 
+// FromRPCAggregateQueryRequest converts the rpc request type for AggregateQueryRequest into corresponding Go API types.
+func FromRPCAggregateQueryRequest(
+	req *rpc.AggregateQueryRequest, pools FetchTaggedConversionPools,
+) (ident.ID, index.Query, index.AggregateQueryOptions, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, req.RangeTimeType)
+	if rangeStartErr != nil {
+		return nil, index.Query{}, index.AggregateQueryOptions{}, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, req.RangeTimeType)
+	if rangeEndErr != nil {
+		return nil, index.Query{}, index.AggregateQueryOptions{}, rangeEndErr
+	}
+
+	opts := index.AggregateQueryOptions{
+		QueryOptions: index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+		},
+		FieldFilter: req.TagNameFilter,
+		Type:        index.AggregateTagNamesAndValues,
+	}
+	if l := req.Limit; l != nil {
+		opts.Limit = int(*l)
+	}
+	if req.TagNamesOnly {
+		opts.Type = index.AggregateTagNames
+	}
+
+	q, err := idx.Unmarshal(req.Query)
+	if err != nil {
+		return nil, index.Query{}, index.AggregateQueryOptions{}, err
+	}
+
+	var ns ident.ID
+	if pools != nil {
+		nsBytes := pools.CheckedBytesWrapper().Get(req.NameSpace)
+		ns = pools.ID().BinaryID(nsBytes)
+	} else {
+		ns = ident.StringID(string(req.NameSpace))
+	}
+	return ns, index.Query{Query: q}, opts, nil
+}
+
+// ToRPCAggregateQueryRequest converts the Go `client/` types into rpc request type for AggregateQueryRequest.
+func ToRPCAggregateQueryRequest(
+	ns ident.ID,
+	q index.Query,
+	opts index.AggregateQueryOptions,
+) (rpc.AggregateQueryRequest, error) {
+	rangeStart, tsErr := ToValue(opts.StartInclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.AggregateQueryRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(opts.EndExclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.AggregateQueryRequest{}, tsErr
+	}
+
+	query, queryErr := idx.Marshal(q.Query)
+	if queryErr != nil {
+		return rpc.AggregateQueryRequest{}, queryErr
+	}
+
+	request := rpc.AggregateQueryRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         query,
+		RangeStart:    rangeStart,
+		RangeEnd:      rangeEnd,
+		RangeTimeType: fetchTaggedTimeType,
+		TagNameFilter: opts.FieldFilter,
+		TagNamesOnly:  opts.Type == index.AggregateTagNames,
+	}
+
+	if opts.Limit > 0 {
+		l := int64(opts.Limit)
+		request.Limit = &l
+	}
+
+	return request, nil
+}
+
+// ToRPCAggregateQueryResult converts aggregated tag names and values into
+// the rpc result type for AggregateQueryResult.
+func ToRPCAggregateQueryResult(
+	results index.AggregateQueryResults,
+) *rpc.AggregateQueryResult_ {
+	fields := results.Results.Fields()
+	response := &rpc.AggregateQueryResult_{
+		Results:    make([]*rpc.AggregateQueryResultTagNameElement, 0, len(fields)),
+		Exhaustive: results.Exhaustive,
+	}
+	for _, field := range fields {
+		elem := &rpc.AggregateQueryResultTagNameElement{
+			TagName: field.Name,
+			Count:   field.Count,
+		}
+		if len(field.Values) > 0 {
+			elem.TagValues = make([]*rpc.AggregateQueryResultTagValueElement, 0, len(field.Values))
+			for _, value := range field.Values {
+				elem.TagValues = append(elem.TagValues, &rpc.AggregateQueryResultTagValueElement{
+					TagValue: value.Value,
+					Count:    value.Count,
+				})
+			}
+		}
+		response.Results = append(response.Results, elem)
+	}
+	return response
+}
+
 // ToTagsIter returns a tag iterator over the given request.
 func ToTagsIter(r *rpc.WriteTaggedRequest) (ident.TagIterator, error) {
 	if r == nil {
@@ -150,6 +150,48 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertFetchTaggedRequestPageToken(t *testing.T) {
+	ns := ident.StringID("abc")
+	q, _ := termQueryTestCase(t)
+	opts := index.QueryOptions{
+		StartInclusive: time.Now().Add(-900 * time.Hour),
+		EndExclusive:   time.Now(),
+		Limit:          10,
+	}
+
+	// Not paginated, no page token is sent.
+	req, err := convert.ToRPCFetchTaggedRequest(ns, index.Query{Query: q}, opts, true)
+	require.NoError(t, err)
+	require.False(t, req.IsSetPageToken())
+
+	_, _, observedOpts, _, err := convert.FromRPCFetchTaggedRequest(&req, nil)
+	require.NoError(t, err)
+	require.False(t, observedOpts.Paginate)
+
+	// Paginated first page sends an empty page token.
+	opts.Paginate = true
+	req, err = convert.ToRPCFetchTaggedRequest(ns, index.Query{Query: q}, opts, true)
+	require.NoError(t, err)
+	require.True(t, req.IsSetPageToken())
+	require.Equal(t, 0, len(req.PageToken))
+
+	_, _, observedOpts, _, err = convert.FromRPCFetchTaggedRequest(&req, nil)
+	require.NoError(t, err)
+	require.True(t, observedOpts.Paginate)
+	require.Equal(t, 0, len(observedOpts.PageToken))
+
+	// Subsequent pages send the page token.
+	opts.PageToken = index.PageToken("token")
+	req, err = convert.ToRPCFetchTaggedRequest(ns, index.Query{Query: q}, opts, true)
+	require.NoError(t, err)
+	require.Equal(t, []byte("token"), req.PageToken)
+
+	_, _, observedOpts, _, err = convert.FromRPCFetchTaggedRequest(&req, nil)
+	require.NoError(t, err)
+	require.True(t, observedOpts.Paginate)
+	require.Equal(t, index.PageToken("token"), observedOpts.PageToken)
+}
+
 type testPools struct {
 	id      ident.Pool
 	wrapper xpool.CheckedBytesWrapperPool
@@ -171,3 +213,84 @@ var _ convert.FetchTaggedConversionPools = &testPools{}
 
 func (t *testPools) ID() ident.Pool                                     { return t.id }
 func (t *testPools) CheckedBytesWrapper() xpool.CheckedBytesWrapperPool { return t.wrapper }
+
+func TestConvertAggregateQueryRequest(t *testing.T) {
+	ns := ident.StringID("abc")
+	q, rpcQ := termQueryTestCase(t)
+	opts := index.AggregateQueryOptions{
+		QueryOptions: index.QueryOptions{
+			StartInclusive: time.Now().Add(-900 * time.Hour),
+			EndExclusive:   time.Now(),
+			Limit:          10,
+		},
+		FieldFilter: [][]byte{[]byte("foo")},
+		Type:        index.AggregateTagNames,
+	}
+	var limit int64 = 10
+	expectedReq := &rpc.AggregateQueryRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         rpcQ,
+		RangeStart:    mustToRpcTime(t, opts.StartInclusive),
+		RangeEnd:      mustToRpcTime(t, opts.EndExclusive),
+		RangeTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+		TagNameFilter: [][]byte{[]byte("foo")},
+		TagNamesOnly:  true,
+		Limit:         &limit,
+	}
+
+	observedReq, err := convert.ToRPCAggregateQueryRequest(ns, index.Query{Query: q}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "", cmp.Diff(expectedReq, &observedReq))
+
+	for _, pools := range []convert.FetchTaggedConversionPools{nil, newTestPools()} {
+		id, observedQuery, observedOpts, err := convert.FromRPCAggregateQueryRequest(expectedReq, pools)
+		require.NoError(t, err)
+		require.Equal(t, ns.String(), id.String())
+		require.True(t, index.NewQueryMatcher(index.Query{Query: q}).Matches(observedQuery))
+		assert.Equal(t, "", cmp.Diff(opts, observedOpts))
+	}
+}
+
+func TestConvertAggregateQueryRequestSecondsTimeType(t *testing.T) {
+	_, rpcQ := termQueryTestCase(t)
+	req := &rpc.AggregateQueryRequest{
+		NameSpace:  []byte("abc"),
+		Query:      rpcQ,
+		RangeStart: 10,
+		RangeEnd:   20,
+	}
+
+	_, _, opts, err := convert.FromRPCAggregateQueryRequest(req, nil)
+	require.NoError(t, err)
+	require.True(t, time.Unix(10, 0).Equal(opts.StartInclusive))
+	require.True(t, time.Unix(20, 0).Equal(opts.EndExclusive))
+	require.Equal(t, index.AggregateTagNamesAndValues, opts.Type)
+	require.Equal(t, 0, opts.Limit)
+}
+
+func TestConvertToRPCAggregateQueryResult(t *testing.T) {
+	results := index.NewAggregateResults(0)
+	results.AddFieldValue([]byte("foo"), []byte("bar"), 2)
+	results.AddField([]byte("baz"), 1)
+
+	response := convert.ToRPCAggregateQueryResult(index.AggregateQueryResults{
+		Results:    results,
+		Exhaustive: true,
+	})
+	assert.Equal(t, "", cmp.Diff(&rpc.AggregateQueryResult_{
+		Results: []*rpc.AggregateQueryResultTagNameElement{
+			{
+				TagName: []byte("baz"),
+				Count:   1,
+			},
+			{
+				TagName: []byte("foo"),
+				Count:   2,
+				TagValues: []*rpc.AggregateQueryResultTagValueElement{
+					{TagValue: []byte("bar"), Count: 2},
+				},
+			},
+		},
+		Exhaustive: true,
+	}, response))
+}
@@ -43,6 +43,11 @@ func IsBadRequestError(err *rpc.Error) bool {
 	return err != nil && err.Type == rpc.ErrorType_BAD_REQUEST
 }
 
+// IsResourceExhaustedError returns whether the error is a resource exhausted error
+func IsResourceExhaustedError(err *rpc.Error) bool {
+	return err != nil && err.Type == rpc.ErrorType_RESOURCE_EXHAUSTED
+}
+
 // NewInternalError creates a new internal error
 func NewInternalError(err error) *rpc.Error {
 	return newError(rpc.ErrorType_INTERNAL_ERROR, err)
@@ -53,6 +58,12 @@ func NewBadRequestError(err error) *rpc.Error {
 	return newError(rpc.ErrorType_BAD_REQUEST, err)
 }
 
+// NewResourceExhaustedError creates a new resource exhausted error, returned
+// when a request is rejected because a quota was exceeded
+func NewResourceExhaustedError(err error) *rpc.Error {
+	return newError(rpc.ErrorType_RESOURCE_EXHAUSTED, err)
+}
+
 // NewWriteBatchRawError creates a new write batch error
 func NewWriteBatchRawError(index int, err error) *rpc.WriteBatchRawError {
 	batchErr := rpc.NewWriteBatchRawError()
@@ -68,3 +79,11 @@ func NewBadRequestWriteBatchRawError(index int, err error) *rpc.WriteBatchRawErr
 	batchErr.Err = NewBadRequestError(err)
 	return batchErr
 }
+
+// NewResourceExhaustedWriteBatchRawError creates a new resource exhausted write batch error
+func NewResourceExhaustedWriteBatchRawError(index int, err error) *rpc.WriteBatchRawError {
+	batchErr := rpc.NewWriteBatchRawError()
+	batchErr.Index = int64(index)
+	batchErr.Err = NewResourceExhaustedError(err)
+	return batchErr
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/quota"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -85,32 +87,40 @@ var (
 type serviceMetrics struct {
 	fetch               instrument.MethodMetrics
 	fetchTagged         instrument.MethodMetrics
+	aggregate           instrument.MethodMetrics
 	write               instrument.MethodMetrics
 	writeTagged         instrument.MethodMetrics
 	fetchBlocks         instrument.MethodMetrics
 	fetchBlocksMetadata instrument.MethodMetrics
 	repair              instrument.MethodMetrics
 	truncate            instrument.MethodMetrics
+	snapshot            instrument.MethodMetrics
 	fetchBatchRaw       instrument.BatchMethodMetrics
 	writeBatchRaw       instrument.BatchMethodMetrics
 	writeTaggedBatchRaw instrument.BatchMethodMetrics
 	overloadRejected    tally.Counter
+	quotaRejected       tally.Counter
+	fetchBlocksThrottle tally.Timer
 }
 
 func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
 	return serviceMetrics{
 		fetch:               instrument.NewMethodMetrics(scope, "fetch", samplingRate),
 		fetchTagged:         instrument.NewMethodMetrics(scope, "fetchTagged", samplingRate),
+		aggregate:           instrument.NewMethodMetrics(scope, "aggregate", samplingRate),
 		write:               instrument.NewMethodMetrics(scope, "write", samplingRate),
 		writeTagged:         instrument.NewMethodMetrics(scope, "writeTagged", samplingRate),
 		fetchBlocks:         instrument.NewMethodMetrics(scope, "fetchBlocks", samplingRate),
 		fetchBlocksMetadata: instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
 		repair:              instrument.NewMethodMetrics(scope, "repair", samplingRate),
 		truncate:            instrument.NewMethodMetrics(scope, "truncate", samplingRate),
+		snapshot:            instrument.NewMethodMetrics(scope, "snapshot", samplingRate),
 		fetchBatchRaw:       instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", samplingRate),
 		writeBatchRaw:       instrument.NewBatchMethodMetrics(scope, "writeBatchRaw", samplingRate),
 		writeTaggedBatchRaw: instrument.NewBatchMethodMetrics(scope, "writeTaggedBatchRaw", samplingRate),
 		overloadRejected:    scope.Counter("overload-rejected"),
+		quotaRejected:       scope.Counter("quota-rejected"),
+		fetchBlocksThrottle: scope.Timer("fetchBlocks-throttle"),
 	}
 }
 
@@ -125,6 +135,10 @@ type service struct {
 	pools   pools
 	metrics serviceMetrics
 	health  *rpc.NodeHealthResult_
+
+	// serveBlocksThrottler throttles the throughput of blocks
+	// served to peers that are bootstrapping from this node
+	serveBlocksThrottler *ratelimit.Throttler
 }
 
 type pools struct {
@@ -202,6 +216,8 @@ func NewService(db storage.Database, opts tchannelthrift.Options) rpc.TChanNode
 			Status:       "up",
 			Bootstrapped: false,
 		},
+		serveBlocksThrottler: ratelimit.NewThrottler(
+			db.Options().ClockOptions().NowFn(), time.Sleep),
 	}
 
 	return s
@@ -397,17 +413,33 @@ func (s *service) FetchTagged(tctx thrift.Context, req *rpc.FetchTaggedRequest)
 		s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
 		return nil, tterrors.NewBadRequestError(err)
 	}
+	if req.IsSetShards() {
+		opts.FilterID = s.shardsFilter(ns, req.Shards)
+	}
 
 	queryResult, err := s.db.QueryIDs(ctx, ns, query, opts)
 	if err != nil {
 		s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
+		if xerrors.IsInvalidParams(err) {
+			// E.g. a malformed page token.
+			return nil, tterrors.NewBadRequestError(err)
+		}
 		return nil, tterrors.NewInternalError(err)
 	}
 
+	results := queryResult.Results
+	if err := s.allowFetchSeries(results); err != nil {
+		s.metrics.quotaRejected.Inc(1)
+		s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewResourceExhaustedError(err)
+	}
+
 	response := &rpc.FetchTaggedResult_{
 		Exhaustive: queryResult.Exhaustive,
 	}
-	results := queryResult.Results
+	if opts.Paginate && queryResult.NextPageToken != nil {
+		response.NextPageToken = queryResult.NextPageToken
+	}
 	nsID := results.Namespace()
 	tagsIter := ident.NewTagsIterator(ident.Tags{})
 	for _, entry := range results.Map().Iter() {
@@ -443,6 +475,80 @@ func (s *service) FetchTagged(tctx thrift.Context, req *rpc.FetchTaggedRequest)
 	return response, nil
 }
 
+func (s *service) Aggregate(tctx thrift.Context, req *rpc.AggregateQueryRequest) (*rpc.AggregateQueryResult_, error) {
+	if s.isOverloaded() {
+		s.metrics.overloadRejected.Inc(1)
+		return nil, tterrors.NewInternalError(errServerIsOverloaded)
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	ns, query, opts, err := convert.FromRPCAggregateQueryRequest(req, s.pools)
+	if err != nil {
+		s.metrics.aggregate.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	queryResult, err := s.db.AggregateQuery(ctx, ns, query, opts)
+	if err != nil {
+		s.metrics.aggregate.ReportError(s.nowFn().Sub(callStart))
+		if xerrors.IsInvalidParams(err) {
+			return nil, tterrors.NewBadRequestError(err)
+		}
+		return nil, tterrors.NewInternalError(err)
+	}
+
+	response := convert.ToRPCAggregateQueryResult(queryResult)
+	s.metrics.aggregate.ReportSuccess(s.nowFn().Sub(callStart))
+	return response, nil
+}
+
+// shardsFilter returns a filter for the series IDs that belong to the provided
+// shards of the namespace.
+func (s *service) shardsFilter(nsID ident.ID, shards []int32) func(id []byte) bool {
+	ns, ok := s.db.Namespace(nsID)
+	if !ok {
+		// NB: the query itself fails for an unknown namespace.
+		return nil
+	}
+
+	var (
+		shardSet = ns.ShardSet()
+		include  = make(map[uint32]struct{}, len(shards))
+	)
+	for _, shard := range shards {
+		include[uint32(shard)] = struct{}{}
+	}
+	return func(id []byte) bool {
+		_, ok := include[shardSet.Lookup(ident.BytesID(id))]
+		return ok
+	}
+}
+
+// allowFetchSeries enforces the fetch series per query quota of each
+// tenant that has series in the results.
+func (s *service) allowFetchSeries(results index.Results) error {
+	enforcer := s.db.Options().QuotaEnforcer()
+	limits := enforcer.Limits()
+	if len(limits.Tenants) == 0 && limits.DefaultTenant.FetchSeriesPerQuery == 0 {
+		// No fetch quotas configured
+		return nil
+	}
+
+	seriesByTenant := make(map[string]int64)
+	tagsIter := ident.NewTagsIterator(ident.Tags{})
+	for _, entry := range results.Map().Iter() {
+		tagsIter.Reset(entry.Value())
+		seriesByTenant[enforcer.Tenant(tagsIter)]++
+	}
+	for tenant, n := range seriesByTenant {
+		if err := enforcer.AllowFetchSeries(tenant, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *service) encodeTags(
 	enc serialize.TagEncoder,
 	tags ident.TagIterator,
@@ -542,6 +648,8 @@ func (s *service) FetchBlocksRaw(tctx thrift.Context, req *rpc.FetchBlocksRawReq
 	res := rpc.NewFetchBlocksRawResult_()
 	res.Elements = make([]*rpc.Blocks, len(req.Elements))
 
+	var bytesFetched int64
+
 	// Preallocate starts to maximum size since at least one element will likely
 	// be fetching most blocks for peer bootstrapping
 	ropts := nsMetadata.Options().RetentionOptions()
@@ -583,6 +691,7 @@ func (s *service) FetchBlocksRaw(tctx thrift.Context, req *rpc.FetchBlocksRawReq
 				}
 				block.Segments = converted.Segments
 				block.Checksum = converted.Checksum
+				bytesFetched += convert.SegmentsLen(converted.Segments)
 			}
 
 			blocks.Blocks = append(blocks.Blocks, block)
@@ -591,6 +700,14 @@ func (s *service) FetchBlocksRaw(tctx thrift.Context, req *rpc.FetchBlocksRawReq
 		res.Elements[i] = blocks
 	}
 
+	// Throttle the response to avoid saturating the network
+	// when peers are bootstrapping from this node
+	rateLimitOpts := s.db.Options().RuntimeOptionsManager().Get().
+		ServeBlocksRateLimitOptions()
+	if slept := s.serveBlocksThrottler.Throttle(rateLimitOpts, bytesFetched); slept > 0 {
+		s.metrics.fetchBlocksThrottle.Record(slept)
+	}
+
 	s.metrics.fetchBlocks.ReportSuccess(s.nowFn().Sub(callStart))
 
 	return res, nil
@@ -754,6 +871,13 @@ func (s *service) Write(tctx thrift.Context, req *rpc.WriteRequest) error {
 		return tterrors.NewBadRequestError(err)
 	}
 
+	// Untagged writes have no tenant and are subject to the default tenant quota.
+	if err := s.db.Options().QuotaEnforcer().AllowWrites("", 1); err != nil {
+		s.metrics.quotaRejected.Inc(1)
+		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
+		return tterrors.NewResourceExhaustedError(err)
+	}
+
 	if err = s.db.Write(
 		ctx, s.pools.id.GetStringID(ctx, req.NameSpace), s.pools.id.GetStringID(ctx, req.ID),
 		xtime.FromNormalizedTime(dp.Timestamp, d), dp.Value, unit, dp.Annotation,
@@ -801,6 +925,14 @@ func (s *service) WriteTagged(tctx thrift.Context, req *rpc.WriteTaggedRequest)
 		return tterrors.NewBadRequestError(err)
 	}
 
+	if enforcer := s.db.Options().QuotaEnforcer(); enforcer.HasWriteLimits() {
+		if err := enforcer.AllowWrites(enforcer.Tenant(iter), 1); err != nil {
+			s.metrics.quotaRejected.Inc(1)
+			s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
+			return tterrors.NewResourceExhaustedError(err)
+		}
+	}
+
 	if err = s.db.WriteTagged(ctx,
 		s.pools.id.GetStringID(ctx, req.NameSpace),
 		s.pools.id.GetStringID(ctx, req.ID),
@@ -838,6 +970,7 @@ func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawReque
 		return err
 	}
 
+	enforcer := s.db.Options().QuotaEnforcer()
 	for i, elem := range req.Elements {
 		unit, unitErr := convert.ToUnit(elem.Datapoint.TimestampTimeType)
 		if unitErr != nil {
@@ -853,6 +986,14 @@ func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawReque
 			continue
 		}
 
+		// Untagged writes have no tenant and are subject to the default tenant quota.
+		if err := enforcer.AllowWrites("", 1); err != nil {
+			s.metrics.quotaRejected.Inc(1)
+			retryableErrors++
+			pooledReq.addError(tterrors.NewResourceExhaustedWriteBatchRawError(i, err))
+			continue
+		}
+
 		seriesID := s.newPooledID(ctx, elem.ID, pooledReq)
 		batchWriter.Add(
 			i,
@@ -911,6 +1052,10 @@ func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedB
 		return err
 	}
 
+	var (
+		enforcer       = s.db.Options().QuotaEnforcer()
+		hasWriteLimits = enforcer.HasWriteLimits()
+	)
 	for i, elem := range req.Elements {
 		unit, unitErr := convert.ToUnit(elem.Datapoint.TimestampTimeType)
 		if unitErr != nil {
@@ -933,6 +1078,15 @@ func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedB
 			continue
 		}
 
+		if hasWriteLimits {
+			if err := enforcer.AllowWrites(enforcer.Tenant(dec), 1); err != nil {
+				s.metrics.quotaRejected.Inc(1)
+				retryableErrors++
+				pooledReq.addError(tterrors.NewResourceExhaustedWriteBatchRawError(i, err))
+				continue
+			}
+		}
+
 		seriesID := s.newPooledID(ctx, elem.ID, pooledReq)
 		batchWriter.AddTagged(
 			i,
@@ -999,6 +1153,27 @@ func (s *service) Truncate(tctx thrift.Context, req *rpc.TruncateRequest) (r *rp
 	return res, nil
 }
 
+func (s *service) Snapshot(
+	tctx thrift.Context,
+	req *rpc.NodeSnapshotRequest,
+) (*rpc.NodeSnapshotResult_, error) {
+	callStart := s.nowFn()
+	result, err := s.db.Snapshot()
+	if err != nil {
+		s.metrics.snapshot.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewNodeSnapshotResult_()
+	res.SnapshotID = result.ID.String()
+	res.SnapshotTime = result.Time.UnixNano()
+	res.SnapshotTimeType = rpc.TimeType_UNIX_NANOSECONDS
+
+	s.metrics.snapshot.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
 func (s *service) GetPersistRateLimit(
 	ctx thrift.Context,
 ) (*rpc.NodePersistRateLimitResult_, error) {
@@ -1320,6 +1495,14 @@ func (r *writeBatchPooledReq) HandleError(index int, err error) {
 		return
 	}
 
+	if quota.IsExceededError(err) {
+		r.retryableErrors++
+		r.errs = append(
+			r.errs,
+			tterrors.NewResourceExhaustedWriteBatchRawError(index, err))
+		return
+	}
+
 	r.retryableErrors++
 	r.errs = append(
 		r.errs,
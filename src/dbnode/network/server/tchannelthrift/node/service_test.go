@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -46,6 +47,7 @@ import (
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber/tchannel-go/thrift"
@@ -1117,6 +1119,73 @@ func TestServiceFetchTaggedNoData(t *testing.T) {
 	}
 }
 
+func TestServiceFetchTaggedQuotaExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	enforcer := quota.NewEnforcer(time.Now)
+	limits := quota.NewLimits()
+	limits.Tenants = map[string]quota.TenantLimits{
+		"foo": {FetchSeriesPerQuery: 1},
+	}
+	enforcer.SetLimits(limits)
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts.SetQuotaEnforcer(enforcer)).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	tenantTags := ident.NewTags(ident.StringTag(quota.DefaultTenantTag, "foo"))
+	resMap := index.NewResults(index.NewOptions())
+	resMap.Reset(ident.StringID(nsID))
+	resMap.Map().Set(ident.StringID("foo"), tenantTags)
+	resMap.Map().Set(ident.StringID("bar"), tenantTags)
+	mockDB.EXPECT().QueryIDs(
+		ctx,
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			Limit:          10,
+		}).Return(index.QueryResults{Results: resMap, Exhaustive: true}, nil)
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	var limit int64 = 10
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	_, err = service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: startNanos,
+		RangeEnd:   endNanos,
+		FetchData:  false,
+		Limit:      &limit,
+	})
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsResourceExhaustedError(rpcErr))
+}
+
 func TestServiceFetchTaggedErrs(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1169,6 +1238,109 @@ func TestServiceFetchTaggedErrs(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestServiceAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	var limit int64 = 10
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	results := index.NewAggregateResults(0)
+	results.AddFieldValue([]byte("foo"), []byte("bar"), 2)
+	results.AddFieldValue([]byte("foo"), []byte("baz"), 1)
+
+	mockDB.EXPECT().AggregateQuery(
+		ctx,
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		index.AggregateQueryOptions{
+			QueryOptions: index.QueryOptions{
+				StartInclusive: start,
+				EndExclusive:   end,
+				Limit:          10,
+			},
+			FieldFilter: [][]byte{[]byte("foo")},
+			Type:        index.AggregateTagNamesAndValues,
+		}).Return(index.AggregateQueryResults{
+		Results:    results,
+		Exhaustive: true,
+	}, nil)
+
+	r, err := service.Aggregate(tctx, &rpc.AggregateQueryRequest{
+		NameSpace:     []byte(nsID),
+		Query:         data,
+		RangeStart:    startNanos,
+		RangeEnd:      endNanos,
+		RangeTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+		TagNameFilter: [][]byte{[]byte("foo")},
+		Limit:         &limit,
+	})
+	require.NoError(t, err)
+	require.True(t, r.Exhaustive)
+	require.Len(t, r.Results, 1)
+	require.Equal(t, []byte("foo"), r.Results[0].TagName)
+	require.Equal(t, int64(3), r.Results[0].Count)
+	require.Equal(t, []*rpc.AggregateQueryResultTagValueElement{
+		{TagValue: []byte("bar"), Count: 2},
+		{TagValue: []byte("baz"), Count: 1},
+	}, r.Results[0].TagValues)
+}
+
+func TestServiceAggregateErrs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+
+	mockDB.EXPECT().AggregateQuery(ctx, ident.NewIDMatcher("metrics"), gomock.Any(), gomock.Any()).
+		Return(index.AggregateQueryResults{}, fmt.Errorf("random err"))
+	_, err = service.Aggregate(tctx, &rpc.AggregateQueryRequest{
+		NameSpace:  []byte("metrics"),
+		Query:      data,
+		RangeStart: time.Now().Add(-time.Hour).Unix(),
+		RangeEnd:   time.Now().Unix(),
+	})
+	require.Error(t, err)
+}
+
 func TestServiceWrite(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1255,6 +1427,114 @@ func TestServiceWriteTagged(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestServiceWriteTaggedQuotaExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	enforcer := quota.NewEnforcer(time.Now)
+	limits := quota.NewLimits()
+	limits.Tenants = map[string]quota.TenantLimits{
+		"foo": {WritesPerSecond: 1},
+	}
+	enforcer.SetLimits(limits)
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts.SetQuotaEnforcer(enforcer)).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		id    = "foo"
+		at    = time.Now().Truncate(time.Second)
+		value = 42.42
+	)
+
+	// Only the first write should make it to the database
+	mockDB.EXPECT().WriteTagged(ctx,
+		ident.NewIDMatcher(nsID),
+		ident.NewIDMatcher(id),
+		gomock.Any(),
+		at, value, xtime.Second, nil,
+	).Return(nil)
+
+	request := &rpc.WriteTaggedRequest{
+		NameSpace: nsID,
+		ID:        id,
+		Datapoint: &rpc.Datapoint{
+			Timestamp:         at.Unix(),
+			TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			Value:             value,
+		},
+		Tags: []*rpc.Tag{
+			{Name: quota.DefaultTenantTag, Value: "foo"},
+		},
+	}
+
+	require.NoError(t, service.WriteTagged(tctx, request))
+
+	err := service.WriteTagged(tctx, request)
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsResourceExhaustedError(rpcErr))
+}
+
+func TestServiceWriteDefaultTenantQuotaExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	enforcer := quota.NewEnforcer(time.Now)
+	limits := quota.NewLimits()
+	limits.DefaultTenant = quota.TenantLimits{WritesPerSecond: 1}
+	enforcer.SetLimits(limits)
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts.SetQuotaEnforcer(enforcer)).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		id    = "foo"
+		at    = time.Now().Truncate(time.Second)
+		value = 42.42
+	)
+
+	// Only the first untagged write should make it to the database
+	mockDB.EXPECT().Write(ctx,
+		ident.NewIDMatcher(nsID),
+		ident.NewIDMatcher(id),
+		at, value, xtime.Second, nil,
+	).Return(nil)
+
+	request := &rpc.WriteRequest{
+		NameSpace: nsID,
+		ID:        id,
+		Datapoint: &rpc.Datapoint{
+			Timestamp:         at.Unix(),
+			TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			Value:             value,
+		},
+	}
+
+	require.NoError(t, service.Write(tctx, request))
+
+	err := service.Write(tctx, request)
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsResourceExhaustedError(rpcErr))
+}
+
 func TestServiceWriteBatchRaw(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1415,6 +1695,37 @@ func TestServiceTruncate(t *testing.T) {
 	assert.Equal(t, truncated, r.NumSeries)
 }
 
+func TestServiceSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	snapshot := storage.SnapshotResult{
+		ID:   uuid.NewRandom(),
+		Time: time.Now(),
+	}
+	mockDB.EXPECT().Snapshot().Return(snapshot, nil)
+
+	r, err := service.Snapshot(tctx, &rpc.NodeSnapshotRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.ID.String(), r.SnapshotID)
+	assert.Equal(t, snapshot.Time.UnixNano(), r.SnapshotTime)
+	assert.Equal(t, rpc.TimeType_UNIX_NANOSECONDS, r.SnapshotTimeType)
+
+	mockDB.EXPECT().Snapshot().Return(storage.SnapshotResult{}, fmt.Errorf("an error"))
+
+	_, err = service.Snapshot(tctx, &rpc.NodeSnapshotRequest{})
+	require.Error(t, err)
+}
+
 func TestServiceSetPersistRateLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
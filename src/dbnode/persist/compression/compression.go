@@ -0,0 +1,239 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package compression provides the block compression codecs that can be
+// applied to persisted files.
+package compression
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Type is a compression type.
+type Type int
+
+const (
+	// None is no compression.
+	None Type = iota
+
+	// Snappy is snappy block compression.
+	Snappy
+
+	// Zstd is zstandard block compression.
+	Zstd
+)
+
+var validTypes = []Type{
+	None,
+	Snappy,
+	Zstd,
+}
+
+var (
+	errTypeUnspecified = errors.New("compression type not specified")
+)
+
+// String returns the compression type as a string.
+func (t Type) String() string {
+	switch t {
+	case None:
+		return "none"
+	case Snappy:
+		return "snappy"
+	case Zstd:
+		return "zstd"
+	}
+	return "unknown"
+}
+
+// ValidTypes returns a copy of the valid compression types.
+func ValidTypes() []Type {
+	result := make([]Type, len(validTypes))
+	copy(result, validTypes)
+	return result
+}
+
+// Validate returns nil when the compression type is valid, otherwise
+// it returns an error.
+func (t Type) Validate() error {
+	for _, valid := range validTypes {
+		if valid == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid compression type: %d", int(t))
+}
+
+// ParseType parses a compression type from a string, an empty string is
+// parsed as no compression.
+func ParseType(str string) (Type, error) {
+	if str == "" {
+		return None, nil
+	}
+	strs := make([]string, 0, len(validTypes))
+	for _, valid := range validTypes {
+		if str == valid.String() {
+			return valid, nil
+		}
+		strs = append(strs, "'"+valid.String()+"'")
+	}
+	return None, fmt.Errorf("invalid compression type '%s' valid types are: %s",
+		str, strings.Join(strs, ", "))
+}
+
+// UnmarshalYAML unmarshals a compression type from a string.
+func (t *Type) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		return errTypeUnspecified
+	}
+	v, err := ParseType(str)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Codec compresses and decompresses blocks of bytes, a codec is safe
+// for concurrent use.
+type Codec interface {
+	// Type returns the compression type of the codec.
+	Type() Type
+
+	// Encode appends the compressed form of src to dst and returns the
+	// resulting slice.
+	Encode(dst, src []byte) ([]byte, error)
+
+	// Decode appends the decompressed form of src to dst and returns the
+	// resulting slice.
+	Decode(dst, src []byte) ([]byte, error)
+}
+
+// NewCodec returns a new codec for the compression type.
+func NewCodec(t Type) (Codec, error) {
+	switch t {
+	case None:
+		return noneCodec{}, nil
+	case Snappy:
+		return snappyCodec{}, nil
+	case Zstd:
+		return newZstdCodec()
+	}
+	return nil, t.Validate()
+}
+
+type noneCodec struct{}
+
+func (noneCodec) Type() Type { return None }
+
+func (noneCodec) Encode(dst, src []byte) ([]byte, error) {
+	return append(dst, src...), nil
+}
+
+func (noneCodec) Decode(dst, src []byte) ([]byte, error) {
+	return append(dst, src...), nil
+}
+
+type snappyCodec struct{}
+
+func (snappyCodec) Type() Type { return Snappy }
+
+func (snappyCodec) Encode(dst, src []byte) ([]byte, error) {
+	n := snappy.MaxEncodedLen(len(src))
+	start := len(dst)
+	dst = grow(dst, n)
+	encoded := snappy.Encode(dst[start:start+n], src)
+	return dst[:start+len(encoded)], nil
+}
+
+func (snappyCodec) Decode(dst, src []byte) ([]byte, error) {
+	n, err := snappy.DecodedLen(src)
+	if err != nil {
+		return nil, err
+	}
+	start := len(dst)
+	dst = grow(dst, n)
+	decoded, err := snappy.Decode(dst[start:start+n], src)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:start+len(decoded)], nil
+}
+
+// NB: The zstd encoder and decoder are safe for concurrent use when only
+// using EncodeAll and DecodeAll and are expensive to create, so they are
+// shared by all zstd codecs.
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+type zstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newZstdCodec() (Codec, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	if zstdErr != nil {
+		return nil, zstdErr
+	}
+	return &zstdCodec{encoder: zstdEncoder, decoder: zstdDecoder}, nil
+}
+
+func (c *zstdCodec) Type() Type { return Zstd }
+
+func (c *zstdCodec) Encode(dst, src []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(src, dst), nil
+}
+
+func (c *zstdCodec) Decode(dst, src []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(src, dst)
+}
+
+// grow returns a slice with at least n bytes of spare capacity after
+// the current length of b, the length of the returned slice is that
+// of b plus n.
+func grow(b []byte, n int) []byte {
+	if cap(b)-len(b) >= n {
+		return b[:len(b)+n]
+	}
+	grown := make([]byte, len(b)+n)
+	copy(grown, b)
+	return grown
+}
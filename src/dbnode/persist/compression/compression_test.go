@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	src := bytes.Repeat([]byte("some-compressible-bytes"), 1024)
+	for _, typ := range ValidTypes() {
+		codec, err := NewCodec(typ)
+		require.NoError(t, err)
+		require.Equal(t, typ, codec.Type())
+
+		prefix := []byte("prefix")
+		encoded, err := codec.Encode(append([]byte(nil), prefix...), src)
+		require.NoError(t, err)
+		require.Equal(t, prefix, encoded[:len(prefix)])
+		if typ != None {
+			require.True(t, len(encoded) < len(src), typ.String())
+		}
+
+		decoded, err := codec.Decode(nil, encoded[len(prefix):])
+		require.NoError(t, err)
+		require.Equal(t, src, decoded)
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for _, typ := range ValidTypes() {
+		parsed, err := ParseType(typ.String())
+		require.NoError(t, err)
+		require.Equal(t, typ, parsed)
+	}
+
+	parsed, err := ParseType("")
+	require.NoError(t, err)
+	require.Equal(t, None, parsed)
+
+	_, err = ParseType("lz4")
+	require.Error(t, err)
+}
+
+func TestNewCodecInvalidType(t *testing.T) {
+	_, err := NewCodec(Type(100))
+	require.Error(t, err)
+}
@@ -0,0 +1,475 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	manifestFileName = "manifest.json"
+	manifestVersion  = 2
+
+	shardsDirName           = "shards"
+	dataDirName             = "data"
+	snapshotsDirName        = "snapshots"
+	indexDirName            = "index"
+	snapshotMetadataDirName = "snapshot-metadata"
+
+	checkpointFileSuffix = "-checkpoint.db"
+)
+
+var (
+	errArchiveDirNotEmpty = errors.New("archive directory is not empty")
+	errFileSetRemoved     = errors.New("fileset was removed during backup")
+	errSnapshotSuperseded = errors.New(
+		"snapshot was superseded by a newer snapshot, take a new snapshot and retry")
+)
+
+// openFileFn opens a file of a fileset for reading.
+type openFileFn func(filePath string) (io.ReadCloser, error)
+
+type backuper struct {
+	opts fs.Options
+}
+
+// NewBackuper creates a new namespace backuper which reads filesets from
+// the file path prefix of the filesystem options.
+func NewBackuper(opts fs.Options) Backuper {
+	return &backuper{
+		opts: opts,
+	}
+}
+
+func (b *backuper) Backup(
+	namespace ident.ID,
+	snapshotID uuid.UUID,
+	archiveDir string,
+) (Manifest, error) {
+	if err := checkArchiveDirEmpty(archiveDir); err != nil {
+		return Manifest{}, err
+	}
+
+	var (
+		filePathPrefix = b.opts.FilePathPrefix()
+		manifest       = Manifest{
+			Version:    manifestVersion,
+			Namespace:  namespace.String(),
+			SnapshotID: snapshotID.String(),
+			CreatedAt:  b.opts.ClockOptions().NowFn()(),
+		}
+	)
+
+	// NB: The snapshot metadata and snapshot filesets are backed up before the
+	// data filesets. A snapshot fileset is only removed once its block has been
+	// flushed, in which case the data fileset listed afterwards is backed up
+	// instead, or once the snapshot is superseded, which is checked for once
+	// the snapshot filesets have been opened.
+	metadata, err := b.snapshotMetadata(snapshotID)
+	if err != nil {
+		return Manifest{}, err
+	}
+	manifest.SnapshotMetadataFiles, err = b.backupFileSet(
+		[]string{metadata.MetadataFilePath, metadata.CheckpointFilePath},
+		filepath.Join(archiveDir, snapshotMetadataDirName), openFile)
+	if err == errFileSetRemoved {
+		return Manifest{}, errSnapshotSuperseded
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("unable to backup snapshot metadata: %v", err)
+	}
+
+	shards, err := namespaceShards(filePathPrefix, namespace)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	for _, shard := range shards {
+		snapshotFileSets, err := b.backupSnapshotFileSets(namespace, shard, snapshotID,
+			filepath.Join(shardArchiveDirPath(archiveDir, shard), snapshotsDirName))
+		if err != nil {
+			return Manifest{}, fmt.Errorf(
+				"unable to backup snapshot filesets of shard %d: %v", shard, err)
+		}
+
+		manifest.Shards = append(manifest.Shards, ShardManifest{
+			Shard:            shard,
+			SnapshotFileSets: snapshotFileSets,
+		})
+	}
+
+	// Snapshot filesets are only removed for being superseded after the
+	// metadata of the newer snapshot is written.
+	if _, err := b.snapshotMetadata(snapshotID); err != nil {
+		return Manifest{}, err
+	}
+
+	for i, shard := range shards {
+		dataFileSets, err := b.backupDataFileSets(namespace, shard,
+			filepath.Join(shardArchiveDirPath(archiveDir, shard), dataDirName))
+		if err != nil {
+			return Manifest{}, fmt.Errorf(
+				"unable to backup data filesets of shard %d: %v", shard, err)
+		}
+		manifest.Shards[i].DataFileSets = dataFileSets
+	}
+
+	indexFiles, err := fs.IndexFiles(filePathPrefix, namespace)
+	if err != nil {
+		return Manifest{}, err
+	}
+	manifest.IndexFileSets, err = b.backupFileSets(completeFileSets(indexFiles),
+		filepath.Join(archiveDir, indexDirName, dataDirName), fileSetFilePaths, openFile)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("unable to backup index filesets: %v", err)
+	}
+
+	// NB: The manifest is written last so that its presence marks
+	// the archive as complete.
+	if err := b.writeManifest(archiveDir, manifest); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// snapshotMetadata returns the metadata of the snapshot with the given ID,
+// which must be the latest snapshot as only the metadata of the latest
+// snapshot is retained.
+func (b *backuper) snapshotMetadata(snapshotID uuid.UUID) (fs.SnapshotMetadata, error) {
+	metadatas, _, err := fs.SortedSnapshotMetadataFiles(b.opts)
+	if err != nil {
+		return fs.SnapshotMetadata{}, err
+	}
+
+	for i, metadata := range metadatas {
+		if !uuid.Equal(metadata.ID.UUID, snapshotID) {
+			continue
+		}
+		if i != len(metadatas)-1 {
+			return fs.SnapshotMetadata{}, errSnapshotSuperseded
+		}
+		return metadata, nil
+	}
+	return fs.SnapshotMetadata{}, fmt.Errorf(
+		"no snapshot metadata for snapshot %s, it may have been superseded", snapshotID)
+}
+
+// backupSnapshotFileSets backs up the complete snapshot filesets of a shard
+// that were written by the snapshot with the given ID.
+func (b *backuper) backupSnapshotFileSets(
+	namespace ident.ID,
+	shard uint32,
+	snapshotID uuid.UUID,
+	destDir string,
+) ([]FileSetManifest, error) {
+	snapshotFiles, err := fs.SnapshotFiles(b.opts.FilePathPrefix(), namespace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	filesets := make(fs.FileSetFilesSlice, 0, len(snapshotFiles))
+	for _, fileset := range completeFileSets(snapshotFiles) {
+		_, id, err := fileset.SnapshotTimeAndID()
+		if err != nil {
+			removed, removedErr := fileSetRemoved(fileset)
+			if removedErr != nil {
+				return nil, removedErr
+			}
+			if removed {
+				// Superseded or flushed since it was listed.
+				continue
+			}
+			return nil, err
+		}
+		if uuid.Equal(uuid.UUID(id), snapshotID) {
+			filesets = append(filesets, fileset)
+		}
+	}
+
+	return b.backupFileSets(filesets, destDir, fileSetFilePaths, openFile)
+}
+
+// backupDataFileSets backs up the complete flushed data filesets of a shard,
+// streaming files that have been tiered to the object store directly into
+// the archive rather than downloading them to local disk.
+func (b *backuper) backupDataFileSets(
+	namespace ident.ID,
+	shard uint32,
+	destDir string,
+) ([]FileSetManifest, error) {
+	filePathPrefix := b.opts.FilePathPrefix()
+	dataFiles, err := fs.DataFiles(filePathPrefix, namespace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	filePathsFn := func(fileset fs.FileSetFile) []string {
+		filePaths := fileSetFilePaths(fileset)
+		tieredFilePaths := fs.TieredDataFileSetFilePaths(filePathPrefix,
+			namespace, shard, fileset.ID.BlockStart)
+		for _, tieredFilePath := range tieredFilePaths {
+			if !containsString(filePaths, tieredFilePath) {
+				filePaths = append(filePaths, tieredFilePath)
+			}
+		}
+		return filePaths
+	}
+	openFn := func(filePath string) (io.ReadCloser, error) {
+		return fs.OpenDataFileSetFile(b.opts, filePath)
+	}
+	return b.backupFileSets(completeFileSets(dataFiles), destDir, filePathsFn, openFn)
+}
+
+// backupFileSets copies filesets into the destination directory, skipping
+// filesets that are removed during the backup.
+func (b *backuper) backupFileSets(
+	filesets fs.FileSetFilesSlice,
+	destDir string,
+	filePathsFn func(fileset fs.FileSetFile) []string,
+	openFn openFileFn,
+) ([]FileSetManifest, error) {
+	manifests := make([]FileSetManifest, 0, len(filesets))
+	for _, fileset := range filesets {
+		files, err := b.backupFileSet(filePathsFn(fileset), destDir, openFn)
+		if err == errFileSetRemoved {
+			// Fileset expired, or was flushed, during the backup
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, FileSetManifest{
+			BlockStart:  fileset.ID.BlockStart,
+			VolumeIndex: fileset.ID.VolumeIndex,
+			Files:       files,
+		})
+	}
+	return manifests, nil
+}
+
+func (b *backuper) backupFileSet(
+	filePaths []string,
+	destDir string,
+	openFn openFileFn,
+) ([]string, error) {
+	// NB: Every file of the fileset, including any tiered to the object store,
+	// is opened before any is copied. A complete fileset is never modified and
+	// an open file remains readable after it is removed, so the copy is
+	// consistent even if cleanup removes the fileset.
+	readers := make([]io.ReadCloser, 0, len(filePaths))
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+	for _, filePath := range filePaths {
+		r, err := openFn(filePath)
+		if os.IsNotExist(err) {
+			return nil, errFileSetRemoved
+		}
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, r)
+	}
+
+	if err := os.MkdirAll(destDir, b.opts.NewDirectoryMode()); err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(readers))
+	for i, r := range readers {
+		fileName := filepath.Base(filePaths[i])
+		err := copyFile(r, filepath.Join(destDir, fileName), b.opts.NewFileMode())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, fileName)
+	}
+	return files, nil
+}
+
+func (b *backuper) writeManifest(archiveDir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(archiveDir, b.opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	var (
+		manifestPath    = filepath.Join(archiveDir, manifestFileName)
+		tmpManifestPath = manifestPath + ".tmp"
+	)
+	if err := ioutil.WriteFile(tmpManifestPath, data, b.opts.NewFileMode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpManifestPath, manifestPath)
+}
+
+// ReadManifest reads the manifest of a backup archive.
+func ReadManifest(archiveDir string) (Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(archiveDir, manifestFileName))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, err
+	}
+	if manifest.Version != manifestVersion {
+		return Manifest{}, fmt.Errorf("unsupported manifest version: %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+func checkArchiveDirEmpty(archiveDir string) error {
+	entries, err := ioutil.ReadDir(archiveDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return errArchiveDirNotEmpty
+	}
+	return nil
+}
+
+// namespaceShards returns the shards that have data or snapshot filesets.
+func namespaceShards(filePathPrefix string, namespace ident.ID) ([]uint32, error) {
+	var (
+		shards = make(map[uint32]struct{})
+		dirs   = []string{
+			fs.NamespaceDataDirPath(filePathPrefix, namespace),
+			fs.NamespaceSnapshotsDirPath(filePathPrefix, namespace),
+		}
+	)
+	for _, dir := range dirs {
+		entries, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			shard, err := strconv.ParseUint(entry.Name(), 10, 32)
+			if err != nil {
+				continue
+			}
+			shards[uint32(shard)] = struct{}{}
+		}
+	}
+
+	result := make([]uint32, 0, len(shards))
+	for shard := range shards {
+		result = append(result, shard)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+func completeFileSets(filesets fs.FileSetFilesSlice) fs.FileSetFilesSlice {
+	result := make(fs.FileSetFilesSlice, 0, len(filesets))
+	for _, fileset := range filesets {
+		if fileset.HasCheckpointFile() {
+			result = append(result, fileset)
+		}
+	}
+	return result
+}
+
+// fileSetRemoved returns whether any of the files of a fileset
+// have been removed since it was listed.
+func fileSetRemoved(fileset fs.FileSetFile) (bool, error) {
+	for _, filePath := range fileset.AbsoluteFilepaths {
+		exists, err := fs.FileExists(filePath)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func fileSetFilePaths(fileset fs.FileSetFile) []string {
+	return append([]string(nil), fileset.AbsoluteFilepaths...)
+}
+
+func openFile(filePath string) (io.ReadCloser, error) {
+	return os.Open(filePath)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func shardArchiveDirPath(archiveDir string, shard uint32) string {
+	return filepath.Join(archiveDir, shardsDirName, strconv.Itoa(int(shard)))
+}
+
+func copyFile(r io.Reader, filePath string, newFileMode os.FileMode) error {
+	fd, err := fs.OpenWritable(filePath, newFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fd, r); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	numTestSeries = 10
+	testBlockSize = 2 * time.Hour
+)
+
+var (
+	testNamespace = ident.StringID("testns")
+	testBytes     = checked.NewBytes([]byte("somelongstringofdata"), nil)
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		store   = fs.NewDirectoryObjectStore(filepath.Join(dir, "store"))
+		srcOpts = fs.NewOptions().
+			SetFilePathPrefix(filepath.Join(dir, "src")).
+			SetObjectStore(store)
+		destOpts       = fs.NewOptions().SetFilePathPrefix(filepath.Join(dir, "dest"))
+		archiveDir     = filepath.Join(dir, "archive")
+		blockStart     = time.Now().Truncate(testBlockSize).Add(-2 * testBlockSize)
+		snapshotID     = uuid.NewRandom()
+		prevSnapshotID = uuid.NewRandom()
+	)
+	testBytes.IncRef()
+	defer testBytes.DecRef()
+
+	writeTestFileSet(t, srcOpts, 1, blockStart, persist.FileSetFlushType, 0, nil)
+	writeTestFileSet(t, srcOpts, 2, blockStart, persist.FileSetFlushType, 0, nil)
+	writeTestFileSet(t, srcOpts, 1, blockStart.Add(testBlockSize), persist.FileSetSnapshotType, 0, prevSnapshotID)
+	writeTestFileSet(t, srcOpts, 1, blockStart.Add(testBlockSize), persist.FileSetSnapshotType, 1, snapshotID)
+	writeTestIndexFileSet(t, srcOpts, blockStart, 0, true)
+	writeTestIndexFileSet(t, srcOpts, blockStart, 1, false)
+	writeTestSnapshotMetadata(t, srcOpts, 0, prevSnapshotID)
+	writeTestSnapshotMetadata(t, srcOpts, 1, snapshotID)
+
+	// Tiered files are streamed from the object store into the archive
+	require.NoError(t, fs.TierDataFileSet(srcOpts, testNamespace, 2, blockStart))
+	tieredFilePaths := fs.TieredDataFileSetFilePaths(srcOpts.FilePathPrefix(),
+		testNamespace, 2, blockStart)
+
+	_, err = NewBackuper(srcOpts).Backup(testNamespace, prevSnapshotID, archiveDir)
+	require.Equal(t, errSnapshotSuperseded, err)
+
+	manifest, err := NewBackuper(srcOpts).Backup(testNamespace, snapshotID, archiveDir)
+	require.NoError(t, err)
+	require.Equal(t, testNamespace.String(), manifest.Namespace)
+	require.Equal(t, snapshotID.String(), manifest.SnapshotID)
+	require.Equal(t, 2, len(manifest.SnapshotMetadataFiles))
+	require.Equal(t, 2, len(manifest.Shards))
+	require.Equal(t, uint32(1), manifest.Shards[0].Shard)
+	require.Equal(t, 1, len(manifest.Shards[0].DataFileSets))
+	// Only the snapshot fileset of the snapshot is backed up
+	require.Equal(t, 1, len(manifest.Shards[0].SnapshotFileSets))
+	require.Equal(t, 1, manifest.Shards[0].SnapshotFileSets[0].VolumeIndex)
+	require.Equal(t, uint32(2), manifest.Shards[1].Shard)
+	require.Equal(t, 1, len(manifest.Shards[1].DataFileSets))
+	require.Equal(t, 0, len(manifest.Shards[1].SnapshotFileSets))
+	// Only the complete index volume is backed up
+	require.Equal(t, 1, len(manifest.IndexFileSets))
+	require.Equal(t, 0, manifest.IndexFileSets[0].VolumeIndex)
+
+	// Tiered files are not downloaded into the data directory
+	for _, filePath := range tieredFilePaths {
+		exists, err := fs.FileExists(filePath)
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+
+	readManifest, err := ReadManifest(archiveDir)
+	require.NoError(t, err)
+	require.Equal(t, len(manifest.Shards), len(readManifest.Shards))
+
+	_, err = NewBackuper(srcOpts).Backup(testNamespace, snapshotID, archiveDir)
+	require.Equal(t, errArchiveDirNotEmpty, err)
+
+	restoreNamespace := ident.StringID("restoredns")
+	_, err = NewRestorer(destOpts).Restore(archiveDir, restoreNamespace)
+	require.NoError(t, err)
+
+	for _, shard := range []uint32{1, 2} {
+		requireTestFileSet(t, destOpts, restoreNamespace, shard, blockStart)
+	}
+	snapshots, err := fs.SnapshotFiles(destOpts.FilePathPrefix(), restoreNamespace, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(snapshots))
+	require.Equal(t, 1, snapshots[0].ID.VolumeIndex)
+	// The restored snapshot is bootstrappable without its snapshot metadata
+	latestSnapshot, ok := snapshots.LatestVolumeForBlock(blockStart.Add(testBlockSize))
+	require.True(t, ok)
+	_, restoredSnapshotID, err := latestSnapshot.SnapshotTimeAndID()
+	require.NoError(t, err)
+	require.True(t, uuid.Equal(snapshotID, restoredSnapshotID))
+	indexFileSets, err := fs.IndexFileSetsAt(destOpts.FilePathPrefix(), restoreNamespace, blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(indexFileSets))
+	// The snapshot metadata refers to the commit log of the source node so
+	// it is never restored
+	metadatas, _, err := fs.SortedSnapshotMetadataFiles(destOpts)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(metadatas))
+
+	// Restoring over existing filesets fails
+	_, err = NewRestorer(destOpts).Restore(archiveDir, restoreNamespace)
+	require.Error(t, err)
+}
+
+func writeTestFileSet(
+	t *testing.T,
+	opts fs.Options,
+	shard uint32,
+	blockStart time.Time,
+	fileSetType persist.FileSetType,
+	volumeIndex int,
+	snapshotID uuid.UUID,
+) {
+	w, err := fs.NewWriter(opts)
+	require.NoError(t, err)
+	writerOpts := fs.DataWriterOpenOptions{
+		BlockSize:   testBlockSize,
+		FileSetType: fileSetType,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   testNamespace,
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: volumeIndex,
+		},
+	}
+	if fileSetType == persist.FileSetSnapshotType {
+		writerOpts.Snapshot.SnapshotTime = blockStart
+		writerOpts.Snapshot.SnapshotID = snapshotID
+	}
+	require.NoError(t, w.Open(writerOpts))
+	for i := 0; i < numTestSeries; i++ {
+		id := ident.StringID(fmt.Sprintf("test-series.%d", i))
+		require.NoError(t, w.Write(id, ident.Tags{}, testBytes, 1234))
+	}
+	require.NoError(t, w.Close())
+}
+
+func writeTestSnapshotMetadata(
+	t *testing.T,
+	opts fs.Options,
+	index int64,
+	snapshotID uuid.UUID,
+) {
+	w := fs.NewSnapshotMetadataWriter(opts)
+	require.NoError(t, w.Write(fs.SnapshotMetadataWriteArgs{
+		ID: fs.SnapshotMetadataIdentifier{
+			Index: index,
+			UUID:  snapshotID,
+		},
+		CommitlogIdentifier: []byte("commitlog"),
+	}))
+}
+
+// writeTestIndexFileSet writes placeholder index fileset files, the
+// backup copies files without reading them.
+func writeTestIndexFileSet(
+	t *testing.T,
+	opts fs.Options,
+	blockStart time.Time,
+	volumeIndex int,
+	complete bool,
+) {
+	dir := fs.NamespaceIndexDataDirPath(opts.FilePathPrefix(), testNamespace)
+	require.NoError(t, os.MkdirAll(dir, opts.NewDirectoryMode()))
+
+	suffixes := []string{"info", "digest", "segment-0-fst"}
+	if complete {
+		suffixes = append(suffixes, "checkpoint")
+	}
+	for _, suffix := range suffixes {
+		fileName := fmt.Sprintf("fileset-%d-%d-%s.db",
+			blockStart.UnixNano(), volumeIndex, suffix)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, fileName),
+			[]byte(suffix), opts.NewFileMode()))
+	}
+}
+
+func requireTestFileSet(
+	t *testing.T,
+	opts fs.Options,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+) {
+	r, err := fs.NewReader(nil, opts)
+	require.NoError(t, err)
+	require.NoError(t, r.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  namespace,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+	}))
+	require.Equal(t, numTestSeries, r.Entries())
+
+	for i := 0; ; i++ {
+		id, _, data, _, err := r.Read()
+		if err == io.EOF {
+			require.Equal(t, numTestSeries, i)
+			break
+		}
+		require.NoError(t, err)
+
+		data.IncRef()
+		require.Equal(t, fmt.Sprintf("test-series.%d", i), id.String())
+		require.Equal(t, testBytes.Bytes(), data.Bytes())
+		data.DecRef()
+	}
+	require.NoError(t, r.Close())
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3x/ident"
+)
+
+var errInvalidManifestFileName = errors.New("invalid file name in manifest")
+
+type restorer struct {
+	opts fs.Options
+}
+
+// restoreFileSet is a fileset to copy from a backup archive
+// into a directory beneath the file path prefix.
+type restoreFileSet struct {
+	srcDir  string
+	destDir string
+	files   []string
+}
+
+// NewRestorer creates a new namespace restorer which lays out filesets
+// beneath the file path prefix of the filesystem options.
+func NewRestorer(opts fs.Options) Restorer {
+	return &restorer{
+		opts: opts,
+	}
+}
+
+func (r *restorer) Restore(archiveDir string, namespace ident.ID) (Manifest, error) {
+	manifest, err := ReadManifest(archiveDir)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if namespace == nil {
+		namespace = ident.StringID(manifest.Namespace)
+	}
+
+	var (
+		filePathPrefix = r.opts.FilePathPrefix()
+		filesets       []restoreFileSet
+	)
+	for _, shard := range manifest.Shards {
+		shardDir := shardArchiveDirPath(archiveDir, shard.Shard)
+		filesets = appendRestoreFileSets(filesets, shard.DataFileSets,
+			filepath.Join(shardDir, dataDirName),
+			fs.ShardDataDirPath(filePathPrefix, namespace, shard.Shard))
+		filesets = appendRestoreFileSets(filesets, shard.SnapshotFileSets,
+			filepath.Join(shardDir, snapshotsDirName),
+			fs.ShardSnapshotsDirPath(filePathPrefix, namespace, shard.Shard))
+	}
+	filesets = appendRestoreFileSets(filesets, manifest.IndexFileSets,
+		filepath.Join(archiveDir, indexDirName, dataDirName),
+		fs.NamespaceIndexDataDirPath(filePathPrefix, namespace))
+	// NB: The snapshot metadata of the archive is never restored. It records
+	// the commit log of the node the backup was taken on, which would let the
+	// node being restored to skip, or clean up, its own commit logs. The
+	// commit log bootstrapper loads the latest complete snapshot volume of
+	// each shard and block without it.
+
+	// Check every file up front so that a restore never overwrites
+	// or mixes with filesets that already exist.
+	for _, fileset := range filesets {
+		for _, fileName := range fileset.files {
+			if err := validateFileName(fileName); err != nil {
+				return Manifest{}, err
+			}
+			filePath := filepath.Join(fileset.destDir, fileName)
+			exists, err := fs.FileExists(filePath)
+			if err != nil {
+				return Manifest{}, err
+			}
+			if exists {
+				return Manifest{}, fmt.Errorf("file already exists: %s", filePath)
+			}
+		}
+	}
+
+	for _, fileset := range filesets {
+		if err := r.restoreFileSet(fileset); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	manifest.Namespace = namespace.String()
+	return manifest, nil
+}
+
+func (r *restorer) restoreFileSet(fileset restoreFileSet) error {
+	if err := os.MkdirAll(fileset.destDir, r.opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	// NB: The checkpoint file is copied last so that the fileset is
+	// only considered complete once every other file is in place.
+	var checkpointFile string
+	for _, fileName := range fileset.files {
+		if strings.HasSuffix(fileName, checkpointFileSuffix) {
+			checkpointFile = fileName
+			continue
+		}
+		if err := r.restoreFile(fileset, fileName); err != nil {
+			return err
+		}
+	}
+	if checkpointFile == "" {
+		return nil
+	}
+	return r.restoreFile(fileset, checkpointFile)
+}
+
+func (r *restorer) restoreFile(fileset restoreFileSet, fileName string) error {
+	fd, err := os.Open(filepath.Join(fileset.srcDir, fileName))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	return copyFile(fd, filepath.Join(fileset.destDir, fileName), r.opts.NewFileMode())
+}
+
+func appendRestoreFileSets(
+	filesets []restoreFileSet,
+	manifests []FileSetManifest,
+	srcDir string,
+	destDir string,
+) []restoreFileSet {
+	for _, manifest := range manifests {
+		filesets = append(filesets, restoreFileSet{
+			srcDir:  srcDir,
+			destDir: destDir,
+			files:   manifest.Files,
+		})
+	}
+	return filesets
+}
+
+// validateFileName ensures that a file name from a manifest cannot
+// refer to a file outside of the directory it is restored to.
+func validateFileName(fileName string) error {
+	if fileName == "." || fileName == ".." ||
+		filepath.Base(fileName) != fileName ||
+		strings.ContainsRune(fileName, filepath.Separator) {
+		return errInvalidManifestFileName
+	}
+	return nil
+}
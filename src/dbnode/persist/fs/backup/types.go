@@ -0,0 +1,72 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"time"
+
+	"github.com/m3db/m3x/ident"
+
+	"github.com/pborman/uuid"
+)
+
+// Manifest describes the contents of a namespace backup archive.
+type Manifest struct {
+	Version               int               `json:"version"`
+	Namespace             string            `json:"namespace"`
+	SnapshotID            string            `json:"snapshotID"`
+	CreatedAt             time.Time         `json:"createdAt"`
+	Shards                []ShardManifest   `json:"shards"`
+	IndexFileSets         []FileSetManifest `json:"indexFileSets"`
+	SnapshotMetadataFiles []string          `json:"snapshotMetadataFiles"`
+}
+
+// ShardManifest describes the filesets of a shard in a backup archive.
+type ShardManifest struct {
+	Shard            uint32            `json:"shard"`
+	DataFileSets     []FileSetManifest `json:"dataFileSets"`
+	SnapshotFileSets []FileSetManifest `json:"snapshotFileSets"`
+}
+
+// FileSetManifest describes a single fileset volume in a backup archive.
+type FileSetManifest struct {
+	BlockStart  time.Time `json:"blockStart"`
+	VolumeIndex int       `json:"volumeIndex"`
+	Files       []string  `json:"files"`
+}
+
+// Backuper takes backups of namespaces
+type Backuper interface {
+	// Backup copies the snapshot filesets and snapshot metadata of a snapshot
+	// that has just been taken on the node, along with the complete flushed
+	// data and index filesets of a namespace, into an empty archive directory
+	// along with a manifest
+	Backup(namespace ident.ID, snapshotID uuid.UUID, archiveDir string) (Manifest, error)
+}
+
+// Restorer restores namespaces from backups
+type Restorer interface {
+	// Restore lays out the filesets of a backup archive so that they are
+	// bootstrapped, into the given namespace or into the namespace the backup
+	// was taken of if the namespace is nil. The snapshot metadata of the
+	// archive is not restored
+	Restore(archiveDir string, namespace ident.ID) (Manifest, error)
+}
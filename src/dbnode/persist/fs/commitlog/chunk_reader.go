@@ -22,9 +22,11 @@ package commitlog
 
 import (
 	"bufio"
+	"io"
 	"os"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 )
 
 const (
@@ -34,11 +36,22 @@ const (
 	checksumSizeEnd   = checksumSizeStart + chunkHeaderSizeLen
 	checksumDataStart = checksumSizeEnd
 	checksumDataEnd   = checksumDataStart + chunkHeaderChecksumDataLen
+
+	decodedSizeStart    = sizeEnd
+	decodedSizeEnd      = decodedSizeStart + chunkHeaderDecodedSizeLen
+	checksumHeaderStart = decodedSizeEnd
+	checksumHeaderEnd   = checksumHeaderStart + chunkHeaderChecksumHeaderLen
+	checksumDataV2Start = checksumHeaderEnd
+	checksumDataV2End   = checksumDataV2Start + chunkHeaderChecksumDataLen
 )
 
 type chunkReader struct {
 	fd        *os.File
 	buffer    *bufio.Reader
+	format    FileFormat
+	codec     compression.Codec
+	encoded   []byte
+	decoded   []byte
 	remaining int
 	charBuff  []byte
 }
@@ -53,10 +66,34 @@ func newChunkReader(bufferLen int) *chunkReader {
 func (r *chunkReader) reset(fd *os.File) {
 	r.fd = fd
 	r.buffer.Reset(fd)
+	r.format = FileFormat{}
+	r.codec = nil
 	r.remaining = 0
 }
 
+// readFileHeader detects the chunk format of the file from its file header
+// and must be called before reading the first chunk.
+func (r *chunkReader) readFileHeader() error {
+	format, err := readFileFormat(r.buffer)
+	if err != nil {
+		return err
+	}
+
+	codec, err := compression.NewCodec(format.Compression)
+	if err != nil {
+		return err
+	}
+
+	r.format = format
+	r.codec = codec
+	return nil
+}
+
 func (r *chunkReader) readHeader() error {
+	if r.format.Version == ChunkFormatV2 {
+		return r.readHeaderV2()
+	}
+
 	header, err := r.buffer.Peek(chunkHeaderLen)
 	if err != nil {
 		return err
@@ -96,6 +133,69 @@ func (r *chunkReader) readHeader() error {
 	return nil
 }
 
+func (r *chunkReader) readHeaderV2() error {
+	header, err := r.buffer.Peek(chunkHeaderV2Len)
+	if err != nil {
+		return err
+	}
+
+	size := endianness.Uint32(header[sizeStart:sizeEnd])
+	decodedSize := endianness.Uint32(header[decodedSizeStart:decodedSizeEnd])
+	checksumHeader := digest.
+		Buffer(header[checksumHeaderStart:checksumHeaderEnd]).
+		ReadDigest()
+	checksumData := digest.
+		Buffer(header[checksumDataV2Start:checksumDataV2End]).
+		ReadDigest()
+
+	// Verify sizes checksum
+	if digest.Checksum(header[sizeStart:decodedSizeEnd]) != checksumHeader {
+		return errCommitLogReaderChunkSizeChecksumMismatch
+	}
+
+	// Discard the peeked header
+	if _, err := r.buffer.Discard(chunkHeaderV2Len); err != nil {
+		return err
+	}
+
+	// NB: the encoded data is read rather than peeked as the encoded size of
+	// a chunk can exceed the buffer size when the data does not compress.
+	if cap(r.encoded) < int(size) {
+		r.encoded = make([]byte, size)
+	}
+	r.encoded = r.encoded[:size]
+	if _, err := io.ReadFull(r.buffer, r.encoded); err != nil {
+		return err
+	}
+
+	// Verify data checksum
+	if digest.Checksum(r.encoded) != checksumData {
+		return errCommitLogReaderChunkSizeChecksumMismatch
+	}
+
+	r.decoded, err = r.codec.Decode(r.decoded[:0], r.encoded)
+	if err != nil {
+		return err
+	}
+	if len(r.decoded) != int(decodedSize) {
+		return errCommitLogReaderChunkDecodedSizeMismatch
+	}
+
+	// Set remaining data to be consumed
+	r.remaining = len(r.decoded)
+
+	return nil
+}
+
+// readData reads from the current chunk which must have at least len(p)
+// bytes remaining.
+func (r *chunkReader) readData(p []byte) (int, error) {
+	if r.format.Version == ChunkFormatV2 {
+		return copy(p, r.decoded[len(r.decoded)-r.remaining:]), nil
+	}
+	return r.buffer.Read(p)
+}
+
 func (r *chunkReader) Read(p []byte) (int, error) {
 	size := len(p)
 	read := 0
@@ -103,7 +203,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 	if r.remaining < size {
 		// Copy any remaining
 		if r.remaining > 0 {
-			n, err := r.buffer.Read(p[:r.remaining])
+			n, err := r.readData(p[:r.remaining])
 			r.remaining -= n
 			read += n
 			if err != nil {
@@ -125,7 +225,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 		return read, err
 	}
 
-	n, err := r.buffer.Read(p)
+	n, err := r.readData(p)
 	r.remaining -= n
 	read += n
 	return read, err
@@ -44,6 +44,9 @@ var (
 	errCommitLogClosed = errors.New("commit log is closed")
 
 	timeZero = time.Time{}
+
+	ackLatencyBuckets      = tally.MustMakeExponentialDurationBuckets(100*time.Microsecond, 2, 16)
+	groupCommitSizeBuckets = tally.MustMakeExponentialValueBuckets(1, 2, 16)
 )
 
 type newCommitLogWriterFn func(
@@ -82,6 +85,9 @@ type commitLog struct {
 
 	writerState writerState
 
+	// Only accessed by the write loop, or before it begins on "Open()".
+	groupCommit groupCommitState
+
 	// Associated with the closedState, but stored separately since
 	// it does not require the closedState lock to be acquired before
 	// being accessed.
@@ -135,6 +141,17 @@ type closedState struct {
 	closed bool
 }
 
+// groupCommitState tracks the batch of writes waiting to be fsync'd
+// together when using the group commit strategy.
+type groupCommitState struct {
+	// timer is only set when using the group commit strategy.
+	timer *time.Timer
+	// timerC is only set while the timer is armed for a pending batch.
+	timerC    <-chan time.Time
+	pending   bool
+	startedAt time.Time
+}
+
 type commitLogMetrics struct {
 	numWritesInQueue tally.Gauge
 	queueLength      tally.Gauge
@@ -145,6 +162,10 @@ type commitLogMetrics struct {
 	closeErrors      tally.Counter
 	flushErrors      tally.Counter
 	flushDone        tally.Counter
+
+	ackLatency         tally.Histogram
+	groupCommitSize    tally.Histogram
+	groupCommitLatency tally.Histogram
 }
 
 type eventType int
@@ -235,12 +256,20 @@ func NewCommitLog(opts Options) (CommitLog, error) {
 			closeErrors:      scope.Counter("writes.close-errors"),
 			flushErrors:      scope.Counter("writes.flush-errors"),
 			flushDone:        scope.Counter("writes.flush-done"),
+
+			ackLatency:         scope.Histogram("writes.ack-latency", ackLatencyBuckets),
+			groupCommitSize:    scope.Histogram("writes.group-commit-size", groupCommitSizeBuckets),
+			groupCommitLatency: scope.Histogram("writes.group-commit-latency", ackLatencyBuckets),
 		},
 	}
 
 	switch opts.Strategy() {
 	case StrategyWriteWait:
 		commitLog.writeFn = commitLog.writeWait
+	case StrategyGroupCommit:
+		commitLog.writeFn = commitLog.writeWait
+		commitLog.groupCommit.timer = time.NewTimer(opts.GroupCommitMaxDelay())
+		commitLog.groupCommit.timer.Stop()
 	default:
 		commitLog.writeFn = commitLog.writeBehind
 	}
@@ -405,7 +434,12 @@ func (l *commitLog) write() {
 	var singleBatch = make([]ts.BatchWrite, 1)
 	var batch []ts.BatchWrite
 
-	for write := range l.writes {
+	for {
+		write, ok := l.nextWrite()
+		if !ok {
+			break
+		}
+
 		if write.eventType == flushEventType {
 			l.writerState.writer.Flush(false)
 			continue
@@ -422,11 +456,6 @@ func (l *commitLog) write() {
 			continue
 		}
 
-		// For writes requiring acks add to pending acks
-		if write.eventType == writeEventType && write.callbackFn != nil {
-			l.pendingFlushFns = append(l.pendingFlushFns, write.callbackFn)
-		}
-
 		var (
 			now                         = l.nowFn()
 			isWriteForNextCommitLogFile = !now.Before(l.writerState.writerExpireAt)
@@ -456,6 +485,15 @@ func (l *commitLog) write() {
 				})
 			}
 
+			if err != nil && write.callbackFn != nil {
+				// The write was never buffered so fail its ack rather than
+				// acknowledging it with the next flush.
+				write.callbackFn(callbackResult{
+					eventType: flushEventType,
+					err:       err,
+				})
+			}
+
 			if err != nil || isRotateLogsEvent {
 				continue
 			}
@@ -464,6 +502,7 @@ func (l *commitLog) write() {
 		var (
 			numWritesSuccess int64
 			numDequeued      int
+			writeErr         error
 		)
 
 		if write.write.writeBatch == nil {
@@ -490,11 +529,28 @@ func (l *commitLog) write() {
 				write.Datapoint, write.Unit, write.Annotation)
 			if err != nil {
 				l.handleWriteErr(err)
+				writeErr = err
 				continue
 			}
 			numWritesSuccess++
 		}
 
+		// For writes requiring acks add to pending acks. NB: this must happen
+		// only once the write is fully buffered, otherwise a chunk flushed
+		// midway through encoding the write would ack it while its tail is
+		// still unsynced.
+		if write.callbackFn != nil {
+			if writeErr != nil {
+				write.callbackFn(callbackResult{
+					eventType: flushEventType,
+					err:       writeErr,
+				})
+			} else {
+				l.pendingFlushFns = append(l.pendingFlushFns, write.callbackFn)
+				l.startGroupCommit()
+			}
+		}
+
 		// Return the write batch to the pool.
 		if write.write.writeBatch != nil {
 			write.write.writeBatch.Finalize()
@@ -510,6 +566,64 @@ func (l *commitLog) write() {
 	l.closeErr <- writer.Close()
 }
 
+// nextWrite returns the next write to process, committing the pending batch
+// of writes whenever the group commit max delay expires while waiting for it.
+func (l *commitLog) nextWrite() (commitLogWrite, bool) {
+	for {
+		select {
+		case write, ok := <-l.writes:
+			return write, ok
+		case <-l.groupCommit.timerC:
+			l.groupCommit.timerC = nil
+			l.commitGroup()
+		}
+	}
+}
+
+// startGroupCommit arms the group commit timer if there is not already a
+// batch of writes pending.
+func (l *commitLog) startGroupCommit() {
+	if l.groupCommit.timer == nil || l.groupCommit.pending {
+		return
+	}
+
+	l.groupCommit.pending = true
+	l.groupCommit.startedAt = l.nowFn()
+	l.groupCommit.timer.Reset(l.opts.GroupCommitMaxDelay())
+	l.groupCommit.timerC = l.groupCommit.timer.C
+}
+
+// commitGroup flushes the pending batch of writes, the writer fsyncs each
+// chunk it writes when using the group commit strategy so the writes are
+// acknowledged by onFlush once they are durable.
+func (l *commitLog) commitGroup() {
+	err := l.writerState.writer.Flush(false)
+	if len(l.pendingFlushFns) > 0 {
+		// Nothing was buffered so the pending writes were either already
+		// flushed or not written at all.
+		l.onFlush(err)
+	}
+}
+
+// finishGroupCommit disarms the group commit timer once the pending batch
+// of writes has been acknowledged.
+func (l *commitLog) finishGroupCommit(numWrites int) {
+	if !l.groupCommit.pending {
+		return
+	}
+
+	if l.groupCommit.timerC != nil {
+		if !l.groupCommit.timer.Stop() {
+			<-l.groupCommit.timerC
+		}
+		l.groupCommit.timerC = nil
+	}
+
+	l.groupCommit.pending = false
+	l.metrics.groupCommitSize.RecordValue(float64(numWrites))
+	l.metrics.groupCommitLatency.RecordDuration(l.nowFn().Sub(l.groupCommit.startedAt))
+}
+
 func (l *commitLog) onFlush(err error) {
 	l.flushState.setLastFlushAt(l.nowFn())
 
@@ -539,6 +653,7 @@ func (l *commitLog) onFlush(err error) {
 		})
 		l.pendingFlushFns[i] = nil
 	}
+	l.finishGroupCommit(len(l.pendingFlushFns))
 	l.pendingFlushFns = l.pendingFlushFns[:0]
 	l.metrics.flushDone.Inc(1)
 }
@@ -612,6 +727,7 @@ func (l *commitLog) writeWait(
 	var (
 		wg     sync.WaitGroup
 		result error
+		start  = l.nowFn()
 	)
 
 	wg.Add(1)
@@ -657,6 +773,7 @@ func (l *commitLog) writeWait(
 	l.closedState.RUnlock()
 
 	wg.Wait()
+	l.metrics.ackLatency.RecordDuration(l.nowFn().Sub(start))
 
 	return result
 }
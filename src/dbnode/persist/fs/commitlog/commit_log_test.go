@@ -33,6 +33,7 @@ import (
 
 	"github.com/m3db/bitset"
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3x/context"
@@ -369,6 +370,51 @@ func TestCommitLogWrite(t *testing.T) {
 	assertCommitLogWritesByIterating(t, commitLog, writes)
 }
 
+func TestCommitLogWriteCompressed(t *testing.T) {
+	tests := []struct {
+		compression compression.Type
+		version     ChunkFormatVersion
+	}{
+		{compression: compression.None, version: ChunkFormatV1},
+		{compression: compression.Snappy, version: ChunkFormatV2},
+		{compression: compression.Zstd, version: ChunkFormatV2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.compression.String(), func(t *testing.T) {
+			opts, scope := newTestOptions(t, overrides{
+				strategy: StrategyWriteWait,
+			})
+			opts = opts.SetCompression(test.compression)
+			defer cleanup(t, opts)
+
+			commitLog := newTestCommitLog(t, opts)
+
+			// Write enough to span several chunks.
+			var writes []testWrite
+			for i := 0; i < 500; i++ {
+				series := testSeries(uint64(i%50), fmt.Sprintf("foo.%d", i%50),
+					ident.NewTags(ident.StringTag("name", "val")), uint32(i%50))
+				writes = append(writes, testWrite{series, time.Now(), float64(i), xtime.Second, []byte{1, 2, 3}, nil})
+			}
+			writeCommitLogs(t, scope, commitLog, writes).Wait()
+			require.NoError(t, commitLog.Close())
+
+			files, corruptFiles, err := Files(opts)
+			require.NoError(t, err)
+			require.Equal(t, 0, len(corruptFiles))
+			require.Equal(t, 1, len(files))
+
+			format, err := ReadFileFormat(files[0].FilePath)
+			require.NoError(t, err)
+			require.Equal(t, test.version, format.Version)
+			require.Equal(t, test.compression, format.Compression)
+
+			assertCommitLogWritesByIterating(t, commitLog, writes)
+		})
+	}
+}
+
 func TestReadCommitLogMissingMetadata(t *testing.T) {
 	readConc := 4
 	// Make sure we're not leaking goroutines
@@ -561,6 +607,151 @@ func TestCommitLogWriteBehind(t *testing.T) {
 	assertCommitLogWritesByIterating(t, commitLog, writes)
 }
 
+func TestCommitLogWriteGroupCommit(t *testing.T) {
+	// Disable the periodic flush so that writes are only acknowledged by
+	// group commits.
+	flushInterval := time.Duration(0)
+	opts, scope := newTestOptions(t, overrides{
+		strategy:      StrategyGroupCommit,
+		flushInterval: &flushInterval,
+	})
+	// NB: use a max delay well above the time taken to enqueue the writes so
+	// that they are all committed by the same group commit.
+	opts = opts.SetGroupCommitMaxDelay(250 * time.Millisecond)
+	defer cleanup(t, opts)
+
+	commitLog, writer := newGroupCommitTestLog(t, opts)
+
+	writes := []testWrite{
+		{testSeries(0, "foo.bar", testTags1, 127), time.Now(), 123.456, xtime.Millisecond, nil, nil},
+		{testSeries(1, "foo.baz", testTags2, 150), time.Now(), 456.789, xtime.Millisecond, nil, nil},
+		{testSeries(2, "foo.qux", testTags3, 291), time.Now(), 789.123, xtime.Millisecond, nil, nil},
+	}
+
+	// Call write and wait for the writes to be committed
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+
+	histograms := scope.Snapshot().Histograms()
+	for _, name := range []string{
+		"commitlog.writes.ack-latency",
+		"commitlog.writes.group-commit-size",
+		"commitlog.writes.group-commit-latency",
+	} {
+		h, ok := histograms[tally.KeyForPrefixedStringMap(name, nil)]
+		require.True(t, ok, name)
+		var count int64
+		for _, v := range h.Values() {
+			count += v
+		}
+		for _, v := range h.Durations() {
+			count += v
+		}
+		require.True(t, count > 0, name)
+	}
+
+	require.NoError(t, commitLog.Close())
+
+	// Assert the concurrent writers shared a single fsync
+	require.Equal(t, []int{len(writes)}, writer.acked)
+
+	// Assert writes occurred by reading the commit log
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
+func TestCommitLogWriteGroupCommitAcksOnlyBufferedWrites(t *testing.T) {
+	flushInterval := time.Duration(0)
+	opts, scope := newTestOptions(t, overrides{
+		strategy:      StrategyGroupCommit,
+		flushInterval: &flushInterval,
+	})
+	// Use a flush size much smaller than the write so that the write is
+	// flushed in several chunks.
+	opts = opts.
+		SetFlushSize(128).
+		SetGroupCommitMaxDelay(10 * time.Millisecond)
+	defer cleanup(t, opts)
+
+	commitLog, writer := newGroupCommitTestLog(t, opts)
+
+	annotation := make([]byte, 1024)
+	rand.Read(annotation)
+
+	writes := []testWrite{
+		{testSeries(0, "foo.bar", testTags1, 127), time.Now(), 123.456, xtime.Millisecond, annotation, nil},
+	}
+
+	// Call write and wait for the write to be committed
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+
+	require.NoError(t, commitLog.Close())
+
+	// Assert chunks were fsync'd while the write was being encoded but that
+	// it was not acknowledged until its last chunk was fsync'd
+	require.True(t, writer.encodingFlushes > 0)
+	require.Equal(t, 0, writer.ackedWhileEncoding)
+
+	// Assert write occurred by reading the commit log
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
+// groupCommitTestWriter wraps a commit log writer to record the writes
+// acknowledged by each of its flushes and whether a write was still being
+// encoded at the time of the flush.
+type groupCommitTestWriter struct {
+	commitLogWriter
+
+	encoding           bool
+	encodingFlushes    int
+	ackedWhileEncoding int
+	acked              []int
+}
+
+func newGroupCommitTestLog(
+	t *testing.T,
+	opts Options,
+) (*commitLog, *groupCommitTestWriter) {
+	commitLogI, err := NewCommitLog(opts)
+	require.NoError(t, err)
+	commitLog := commitLogI.(*commitLog)
+
+	writer := &groupCommitTestWriter{}
+	commitLog.newCommitLogWriterFn = func(
+		flushFn flushFn,
+		opts Options,
+	) commitLogWriter {
+		writer.commitLogWriter = newCommitLogWriter(func(err error) {
+			// NB: flushes happen on the write goroutine which is the only
+			// accessor of the pending acks.
+			if writer.encoding {
+				writer.encodingFlushes++
+			}
+			if n := len(commitLog.pendingFlushFns); n > 0 {
+				writer.acked = append(writer.acked, n)
+				if writer.encoding {
+					writer.ackedWhileEncoding++
+				}
+			}
+			flushFn(err)
+		}, opts)
+		return writer
+	}
+
+	require.NoError(t, commitLog.Open())
+	return commitLog, writer
+}
+
+func (w *groupCommitTestWriter) Write(
+	series ts.Series,
+	datapoint ts.Datapoint,
+	unit xtime.Unit,
+	annotation ts.Annotation,
+) error {
+	w.encoding = true
+	err := w.commitLogWriter.Write(series, datapoint, unit, annotation)
+	w.encoding = false
+	return err
+}
+
 func TestCommitLogWriteErrorOnClosed(t *testing.T) {
 	opts, _ := newTestOptions(t, overrides{})
 	defer cleanup(t, opts)
@@ -0,0 +1,159 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+)
+
+// ChunkFormatVersion is the version of the format of the chunks in a
+// commit log file.
+type ChunkFormatVersion int
+
+const (
+	// ChunkFormatV1 is the original chunk format, the file has no file header
+	// and chunks are written uncompressed.
+	ChunkFormatV1 ChunkFormatVersion = 1
+
+	// ChunkFormatV2 is the chunk format written when compression is enabled,
+	// the file starts with a file header recording the compression type and
+	// each chunk records both its encoded and decoded sizes.
+	ChunkFormatV2 ChunkFormatVersion = 2
+)
+
+const (
+	// The lengths of the file header written by chunk format versions after v1:
+	// - magic [4]byte
+	// - version uint8
+	// - compression uint8
+	// - checksum uint32
+	fileHeaderMagicLen       = 4
+	fileHeaderVersionLen     = 1
+	fileHeaderCompressionLen = 1
+	fileHeaderChecksumLen    = 4
+	fileHeaderLen            = fileHeaderMagicLen +
+		fileHeaderVersionLen +
+		fileHeaderCompressionLen +
+		fileHeaderChecksumLen
+
+	fileHeaderVersionStart     = fileHeaderMagicLen
+	fileHeaderCompressionStart = fileHeaderVersionStart + fileHeaderVersionLen
+	fileHeaderChecksumStart    = fileHeaderCompressionStart + fileHeaderCompressionLen
+)
+
+var (
+	// NB: v1 files start with a chunk header whose first four bytes are the
+	// size of the chunk followed by the checksum of the size, the magic is a
+	// size of more than a gigabyte and the header checksum is verified so a v1
+	// file is never mistaken for a file with a file header.
+	fileHeaderMagic = []byte("m3cl")
+
+	errCommitLogFileHeaderChecksumMismatch = errors.New("commit log file header checksum mismatch")
+)
+
+// FileFormat describes the format of the chunks in a commit log file.
+type FileFormat struct {
+	Version     ChunkFormatVersion
+	Compression compression.Type
+}
+
+// ReadFileFormat reads the format of the chunks in a commit log file from
+// its file header, files without a file header are v1 files.
+func ReadFileFormat(filePath string) (FileFormat, error) {
+	fd, err := os.Open(filePath)
+	if err != nil {
+		return FileFormat{}, err
+	}
+	defer fd.Close()
+
+	return readFileFormat(bufio.NewReaderSize(fd, fileHeaderLen))
+}
+
+// fileFormatFromCompression returns the file format written for the given
+// compression type, files are only written as v2 files when compressed so
+// that uncompressed files can still be read by older versions.
+func fileFormatFromCompression(t compression.Type) FileFormat {
+	if t == compression.None {
+		return FileFormat{Version: ChunkFormatV1, Compression: compression.None}
+	}
+	return FileFormat{Version: ChunkFormatV2, Compression: t}
+}
+
+// readFileFormat peeks at the start of the buffer for a file header, if found
+// the file header is discarded from the buffer.
+func readFileFormat(buffer *bufio.Reader) (FileFormat, error) {
+	header, err := buffer.Peek(fileHeaderLen)
+	if err != nil && err != io.EOF {
+		return FileFormat{}, err
+	}
+	if len(header) < fileHeaderLen || !bytes.Equal(header[:fileHeaderMagicLen], fileHeaderMagic) {
+		return fileFormatFromCompression(compression.None), nil
+	}
+
+	format, err := decodeFileHeader(header)
+	if err != nil {
+		return FileFormat{}, err
+	}
+
+	if _, err := buffer.Discard(fileHeaderLen); err != nil {
+		return FileFormat{}, err
+	}
+	return format, nil
+}
+
+func encodeFileHeader(buf []byte, format FileFormat) []byte {
+	buf = append(buf[:0], fileHeaderMagic...)
+	buf = append(buf, byte(format.Version), byte(format.Compression))
+	checksum := digest.Checksum(buf[:fileHeaderChecksumStart])
+	buf = append(buf, make([]byte, fileHeaderChecksumLen)...)
+	digest.Buffer(buf[fileHeaderChecksumStart:fileHeaderLen]).WriteDigest(checksum)
+	return buf
+}
+
+func decodeFileHeader(header []byte) (FileFormat, error) {
+	checksum := digest.
+		Buffer(header[fileHeaderChecksumStart:fileHeaderLen]).
+		ReadDigest()
+	if digest.Checksum(header[:fileHeaderChecksumStart]) != checksum {
+		return FileFormat{}, errCommitLogFileHeaderChecksumMismatch
+	}
+
+	format := FileFormat{
+		Version:     ChunkFormatVersion(header[fileHeaderVersionStart]),
+		Compression: compression.Type(header[fileHeaderCompressionStart]),
+	}
+	if format.Version != ChunkFormatV2 {
+		return FileFormat{}, fmt.Errorf(
+			"commit log file has unsupported chunk format version: %d", format.Version)
+	}
+	if err := format.Compression.Validate(); err != nil {
+		return FileFormat{}, err
+	}
+	return format, nil
+}
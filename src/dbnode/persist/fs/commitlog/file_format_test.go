@@ -0,0 +1,86 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileFormat(t *testing.T) {
+	format := FileFormat{Version: ChunkFormatV2, Compression: compression.Snappy}
+	header := encodeFileHeader(nil, format)
+	require.Equal(t, fileHeaderLen, len(header))
+
+	buffer := bufio.NewReader(bytes.NewReader(append(header, 1, 2, 3)))
+	read, err := readFileFormat(buffer)
+	require.NoError(t, err)
+	require.Equal(t, format, read)
+
+	// The file header is discarded.
+	rest := make([]byte, 3)
+	_, err = buffer.Read(rest)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, rest)
+}
+
+func TestReadFileFormatWithoutFileHeader(t *testing.T) {
+	v1 := FileFormat{Version: ChunkFormatV1, Compression: compression.None}
+	for _, data := range [][]byte{
+		nil,
+		[]byte("m3"),
+		bytes.Repeat([]byte{1}, 2*fileHeaderLen),
+	} {
+		buffer := bufio.NewReader(bytes.NewReader(data))
+		read, err := readFileFormat(buffer)
+		require.NoError(t, err)
+		require.Equal(t, v1, read)
+
+		// Nothing is discarded.
+		require.Equal(t, len(data), buffer.Buffered())
+	}
+}
+
+func TestReadFileFormatChecksumMismatch(t *testing.T) {
+	header := encodeFileHeader(nil, FileFormat{
+		Version:     ChunkFormatV2,
+		Compression: compression.Zstd,
+	})
+	header[fileHeaderCompressionStart] = byte(compression.Snappy)
+
+	_, err := readFileFormat(bufio.NewReader(bytes.NewReader(header)))
+	require.Equal(t, errCommitLogFileHeaderChecksumMismatch, err)
+}
+
+func TestReadFileFormatUnsupportedVersion(t *testing.T) {
+	header := encodeFileHeader(nil, FileFormat{
+		Version:     ChunkFormatV2 + 1,
+		Compression: compression.Snappy,
+	})
+
+	_, err := readFileFormat(bufio.NewReader(bytes.NewReader(header)))
+	require.Error(t, err)
+}
@@ -56,6 +56,10 @@ func ReadLogInfo(filePath string, opts Options) (time.Time, time.Duration, int64
 
 	chunkReader := newChunkReader(opts.FlushSize())
 	chunkReader.reset(fd)
+	if err := chunkReader.readFileHeader(); err != nil {
+		return time.Time{}, 0, 0, err
+	}
+
 	size, err := binary.ReadUvarint(chunkReader)
 	if err != nil {
 		return time.Time{}, 0, 0, err
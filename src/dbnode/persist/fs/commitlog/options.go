@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -40,9 +41,15 @@ const (
 	// defaultFlushInterval is the default commit log flush interval
 	defaultFlushInterval = time.Second
 
+	// defaultGroupCommitMaxDelay is the default commit log group commit max delay
+	defaultGroupCommitMaxDelay = 5 * time.Millisecond
+
 	// defaultFlushSize is the default commit log flush size
 	defaultFlushSize = 65536
 
+	// defaultCompression is the default commit log chunk compression
+	defaultCompression = compression.None
+
 	// defaultBlockSize is the default commit log block size
 	defaultBlockSize = 15 * time.Minute
 
@@ -64,6 +71,7 @@ var (
 
 var (
 	errFlushIntervalNonNegative = errors.New("flush interval must be non-negative")
+	errGroupCommitMaxDelay      = errors.New("group commit max delay must be a positive duration")
 	errBlockSizePositive        = errors.New("block size must be a positive duration")
 	errReadConcurrencyPositive  = errors.New("read concurrency must be a positive integer")
 	errBacklogQueueChannelSize  = errors.New("read concurrency must be a positive integer")
@@ -76,7 +84,9 @@ type options struct {
 	fsOpts                  fs.Options
 	strategy                Strategy
 	flushSize               int
+	compression             compression.Type
 	flushInterval           time.Duration
+	groupCommitMaxDelay     time.Duration
 	backlogQueueSize        int
 	backlogQueueChannelSize int
 	bytesPool               pool.CheckedBytesPool
@@ -93,7 +103,9 @@ func NewOptions() Options {
 		fsOpts:                  fs.NewOptions(),
 		strategy:                defaultStrategy,
 		flushSize:               defaultFlushSize,
+		compression:             defaultCompression,
 		flushInterval:           defaultFlushInterval,
+		groupCommitMaxDelay:     defaultGroupCommitMaxDelay,
 		backlogQueueSize:        defaultBacklogQueueSize,
 		backlogQueueChannelSize: defaultBacklogQueueChannelSize,
 		bytesPool: pool.NewCheckedBytesPool(nil, nil, func(s []pool.Bucket) pool.BytesPool {
@@ -111,6 +123,10 @@ func (o *options) Validate() error {
 		return errFlushIntervalNonNegative
 	}
 
+	if o.GroupCommitMaxDelay() <= 0 {
+		return errGroupCommitMaxDelay
+	}
+
 	if o.BlockSize() <= 0 {
 		return errBlockSizePositive
 	}
@@ -119,6 +135,11 @@ func (o *options) Validate() error {
 		return errReadConcurrencyPositive
 	}
 
+	// NB: creating the codec also validates the compression type.
+	if _, err := compression.NewCodec(o.Compression()); err != nil {
+		return err
+	}
+
 	if float64(o.BacklogQueueSize())/float64(o.BacklogQueueChannelSize()) > MaximumQueueSizeQueueChannelSizeRatio {
 		return fmt.Errorf(
 			"BacklogQueueSize / BacklogQueueChannelSize ratio must be at least: %f, but was: %f",
@@ -188,6 +209,26 @@ func (o *options) FlushSize() int {
 	return o.flushSize
 }
 
+func (o *options) SetCompression(value compression.Type) Options {
+	opts := *o
+	opts.compression = value
+	return &opts
+}
+
+func (o *options) Compression() compression.Type {
+	return o.compression
+}
+
+func (o *options) SetGroupCommitMaxDelay(value time.Duration) Options {
+	opts := *o
+	opts.groupCommitMaxDelay = value
+	return &opts
+}
+
+func (o *options) GroupCommitMaxDelay() time.Duration {
+	return o.groupCommitMaxDelay
+}
+
 func (o *options) SetFlushInterval(value time.Duration) Options {
 	opts := *o
 	opts.flushInterval = value
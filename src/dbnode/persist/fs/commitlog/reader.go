@@ -51,6 +51,7 @@ var (
 	emptyLogInfo schema.LogInfo
 
 	errCommitLogReaderChunkSizeChecksumMismatch = errors.New("commit log reader encountered chunk size checksum mismatch")
+	errCommitLogReaderChunkDecodedSizeMismatch  = errors.New("commit log reader encountered chunk decoded size mismatch")
 	errCommitLogReaderIsNotReusable             = errors.New("commit log reader is not reusable")
 	errCommitLogReaderMultipleReadloops         = errors.New("commit log reader tried to open multiple readLoops, do not call Read() concurrently")
 	errCommitLogReaderMissingMetadata           = errors.New("commit log reader encountered a datapoint without corresponding metadata")
@@ -172,6 +173,11 @@ func (r *reader) Open(filePath string) (time.Time, time.Duration, int64, error)
 	}
 
 	r.chunkReader.reset(fd)
+	if err := r.chunkReader.readFileHeader(); err != nil {
+		r.Close()
+		return timeZero, 0, 0, err
+	}
+
 	info, err := r.readInfo()
 	if err != nil {
 		r.Close()
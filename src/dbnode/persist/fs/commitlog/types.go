@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3x/context"
@@ -46,6 +47,11 @@ const (
 	// for the buffered commit log chunk that contains a write to flush
 	// before acknowledging a write
 	StrategyWriteBehind
+
+	// StrategyGroupCommit describes the strategy that batches concurrent
+	// writes and fsyncs them together no later than the group commit max
+	// delay after the first write of the batch before acknowledging them
+	StrategyGroupCommit
 )
 
 // CommitLog provides a synchronized commit log
@@ -136,12 +142,28 @@ type Options interface {
 	// FlushSize returns the flush size.
 	FlushSize() int
 
+	// SetCompression sets the compression type of the chunks written, files
+	// with compressed chunks cannot be read by versions which only support
+	// the v1 chunk format.
+	SetCompression(value compression.Type) Options
+
+	// Compression returns the compression type of the chunks written.
+	Compression() compression.Type
+
 	// SetStrategy sets the strategy.
 	SetStrategy(value Strategy) Options
 
 	// Strategy returns the strategy.
 	Strategy() Strategy
 
+	// SetGroupCommitMaxDelay sets the maximum time the first write of a batch
+	// waits to be fsync'd when using the group commit strategy.
+	SetGroupCommitMaxDelay(value time.Duration) Options
+
+	// GroupCommitMaxDelay returns the maximum time the first write of a batch
+	// waits to be fsync'd when using the group commit strategy.
+	GroupCommitMaxDelay() time.Duration
+
 	// SetFlushInterval sets the flush interval.
 	SetFlushInterval(value time.Duration) Options
 
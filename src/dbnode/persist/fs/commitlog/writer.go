@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/bitset"
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
@@ -53,6 +54,18 @@ const (
 		chunkHeaderChecksumSizeLen +
 		chunkHeaderChecksumDataLen
 
+	// The lengths to reserve for a v2 chunk header:
+	// - size uint32
+	// - decodedSize uint32
+	// - checksumHeader uint32
+	// - checksumData uint32
+	chunkHeaderDecodedSizeLen    = 4
+	chunkHeaderChecksumHeaderLen = 4
+	chunkHeaderV2Len             = chunkHeaderSizeLen +
+		chunkHeaderDecodedSizeLen +
+		chunkHeaderChecksumHeaderLen +
+		chunkHeaderChecksumDataLen
+
 	defaultBitSetLength = 65536
 
 	defaultEncoderBuffSize = 16384
@@ -103,6 +116,8 @@ type writer struct {
 	nowFn               clock.NowFn
 	start               time.Time
 	duration            time.Duration
+	format              FileFormat
+	fileHeader          []byte
 	chunkWriter         chunkWriter
 	chunkReserveHeader  []byte
 	buffer              *bufio.Writer
@@ -119,14 +134,22 @@ func newCommitLogWriter(
 	flushFn flushFn,
 	opts Options,
 ) commitLogWriter {
-	shouldFsync := opts.Strategy() == StrategyWriteWait
+	shouldFsync := opts.Strategy() == StrategyWriteWait ||
+		opts.Strategy() == StrategyGroupCommit
+
+	// NB: the codec is created when the options are validated so creating
+	// it again cannot fail.
+	format := fileFormatFromCompression(opts.Compression())
+	codec, _ := compression.NewCodec(format.Compression)
 
 	return &writer{
 		filePathPrefix:      opts.FilesystemOptions().FilePathPrefix(),
 		newFileMode:         opts.FilesystemOptions().NewFileMode(),
 		newDirectoryMode:    opts.FilesystemOptions().NewDirectoryMode(),
 		nowFn:               opts.ClockOptions().NowFn(),
-		chunkWriter:         newChunkWriter(flushFn, shouldFsync),
+		format:              format,
+		fileHeader:          make([]byte, 0, fileHeaderLen),
+		chunkWriter:         newChunkWriter(flushFn, shouldFsync, format, codec),
 		chunkReserveHeader:  make([]byte, chunkHeaderLen),
 		buffer:              bufio.NewWriterSize(nil, opts.FlushSize()),
 		sizeBuffer:          make([]byte, binary.MaxVarintLen64),
@@ -176,6 +199,14 @@ func (w *writer) Open(start time.Time, duration time.Duration) (File, error) {
 		return File{}, err
 	}
 
+	if w.format.Version == ChunkFormatV2 {
+		w.fileHeader = encodeFileHeader(w.fileHeader, w.format)
+		if _, err := fd.Write(w.fileHeader); err != nil {
+			fd.Close()
+			return File{}, err
+		}
+	}
+
 	w.chunkWriter.reset(fd)
 	w.buffer.Reset(w.chunkWriter)
 	if err := w.write(w.logEncoder.Bytes()); err != nil {
@@ -333,13 +364,26 @@ type fsChunkWriter struct {
 	flushFn flushFn
 	buff    []byte
 	fsync   bool
+	format  FileFormat
+	codec   compression.Codec
 }
 
-func newChunkWriter(flushFn flushFn, fsync bool) chunkWriter {
+func newChunkWriter(
+	flushFn flushFn,
+	fsync bool,
+	format FileFormat,
+	codec compression.Codec,
+) chunkWriter {
+	headerLen := chunkHeaderLen
+	if format.Version == ChunkFormatV2 {
+		headerLen = chunkHeaderV2Len
+	}
 	return &fsChunkWriter{
 		flushFn: flushFn,
-		buff:    make([]byte, chunkHeaderLen),
+		buff:    make([]byte, headerLen),
 		fsync:   fsync,
+		format:  format,
+		codec:   codec,
 	}
 }
 
@@ -362,6 +406,10 @@ func (w *fsChunkWriter) sync() error {
 }
 
 func (w *fsChunkWriter) Write(p []byte) (int, error) {
+	if w.format.Version == ChunkFormatV2 {
+		return w.writeV2(p)
+	}
+
 	size := len(p)
 
 	sizeStart, sizeEnd :=
@@ -389,6 +437,54 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 	// Combine buffers to reduce to a single syscall
 	w.buff = append(w.buff[:chunkHeaderLen], p...)
 
+	return w.writeBuff()
+}
+
+func (w *fsChunkWriter) writeV2(p []byte) (int, error) {
+	sizeStart, sizeEnd :=
+		0, chunkHeaderSizeLen
+	decodedSizeStart, decodedSizeEnd :=
+		sizeEnd, sizeEnd+chunkHeaderDecodedSizeLen
+	checksumHeaderStart, checksumHeaderEnd :=
+		decodedSizeEnd, decodedSizeEnd+chunkHeaderChecksumHeaderLen
+	checksumDataStart, checksumDataEnd :=
+		checksumHeaderEnd, checksumHeaderEnd+chunkHeaderChecksumDataLen
+
+	// Encode the data directly after the header to reduce to a single syscall
+	buff, err := w.codec.Encode(w.buff[:chunkHeaderV2Len], p)
+	if err != nil {
+		w.flushFn(err)
+		return 0, err
+	}
+	w.buff = buff
+	encoded := w.buff[chunkHeaderV2Len:]
+
+	// Write sizes
+	endianness.PutUint32(w.buff[sizeStart:sizeEnd], uint32(len(encoded)))
+	endianness.PutUint32(w.buff[decodedSizeStart:decodedSizeEnd], uint32(len(p)))
+
+	// Calculate checksums
+	checksumHeader := digest.Checksum(w.buff[sizeStart:decodedSizeEnd])
+	checksumData := digest.Checksum(encoded)
+
+	// Write checksums
+	digest.
+		Buffer(w.buff[checksumHeaderStart:checksumHeaderEnd]).
+		WriteDigest(checksumHeader)
+	digest.
+		Buffer(w.buff[checksumDataStart:checksumDataEnd]).
+		WriteDigest(checksumData)
+
+	if _, err := w.writeBuff(); err != nil {
+		return 0, err
+	}
+
+	// NB: callers expect the number of bytes of p written, not the number
+	// of encoded bytes.
+	return len(p), nil
+}
+
+func (w *fsChunkWriter) writeBuff() (int, error) {
 	// Write contents to file descriptor
 	n, err := w.fd.Write(w.buff)
 	if err != nil {
@@ -0,0 +1,152 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/schema"
+)
+
+const (
+	// defaultDataCompressionFrameSize is the target size of uncompressed data
+	// in a single compressed frame of a data file, a frame only ever holds
+	// whole series so frames can be larger than this target.
+	defaultDataCompressionFrameSize = 64 * 1024
+)
+
+var (
+	// errCompressedFrameNotFound returned when no compressed frame holds the requested data
+	errCompressedFrameNotFound = errors.New("compressed frame not found for data file offset")
+
+	// errCompressedFrameSizeMismatch returned when a decompressed frame is not the expected size
+	errCompressedFrameSizeMismatch = errors.New("decompressed frame size does not match expected size")
+)
+
+// dataCompressionFrames is the set of compressed frames of a data file sorted
+// by their uncompressed offset ascending.
+type dataCompressionFrames []schema.IndexDataCompressionFrame
+
+// frameFor returns the index of the frame holding the uncompressed range
+// [offset, offset+size) of the data file.
+func (f dataCompressionFrames) frameFor(offset int64, size int64) (int, error) {
+	idx := sort.Search(len(f), func(i int) bool {
+		return f[i].Offset+f[i].Size > offset
+	})
+	if idx >= len(f) {
+		return 0, errCompressedFrameNotFound
+	}
+	frame := f[idx]
+	if offset < frame.Offset || offset+size > frame.Offset+frame.Size {
+		// Series are never split across frames.
+		return 0, errCompressedFrameNotFound
+	}
+	return idx, nil
+}
+
+// decodeFrame decompresses a single frame and appends it to dst.
+func decodeFrame(
+	codec compression.Codec,
+	dst []byte,
+	frame schema.IndexDataCompressionFrame,
+	compressed []byte,
+) ([]byte, error) {
+	start := len(dst)
+	result, err := codec.Decode(dst, compressed)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(result)-start) != frame.Size {
+		return nil, errCompressedFrameSizeMismatch
+	}
+	return result, nil
+}
+
+// compressedDataReader reads the uncompressed contents of a compressed data
+// file sequentially, one frame at a time.
+type compressedDataReader struct {
+	reader     io.Reader
+	codec      compression.Codec
+	frames     dataCompressionFrames
+	nextFrame  int
+	compressed []byte
+	frameBuf   []byte
+	unread     []byte
+}
+
+func newCompressedDataReader() *compressedDataReader {
+	return &compressedDataReader{}
+}
+
+func (r *compressedDataReader) Reset(
+	reader io.Reader,
+	codec compression.Codec,
+	frames dataCompressionFrames,
+) {
+	r.reader = reader
+	r.codec = codec
+	r.frames = frames
+	r.nextFrame = 0
+	r.unread = nil
+}
+
+func (r *compressedDataReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.unread) == 0 {
+			if err := r.readFrame(); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(p[n:], r.unread)
+		r.unread = r.unread[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+func (r *compressedDataReader) readFrame() error {
+	if r.nextFrame >= len(r.frames) {
+		return io.EOF
+	}
+
+	frame := r.frames[r.nextFrame]
+	if int64(cap(r.compressed)) < frame.CompressedSize {
+		r.compressed = make([]byte, frame.CompressedSize)
+	}
+	r.compressed = r.compressed[:frame.CompressedSize]
+	if _, err := io.ReadFull(r.reader, r.compressed); err != nil {
+		return err
+	}
+
+	frameBuf, err := decodeFrame(r.codec, r.frameBuf[:0], frame, r.compressed)
+	if err != nil {
+		return err
+	}
+
+	r.frameBuf = frameBuf
+	r.unread = frameBuf
+	r.nextFrame++
+	return nil
+}
@@ -48,10 +48,14 @@ import (
 var timeZero time.Time
 
 const (
-	dataDirName       = "data"
-	indexDirName      = "index"
-	snapshotDirName   = "snapshots"
-	commitLogsDirName = "commitlogs"
+	dataDirName        = "data"
+	indexDirName       = "index"
+	snapshotDirName    = "snapshots"
+	commitLogsDirName  = "commitlogs"
+	downsampleDirName  = "downsample"
+	stagingDirName     = "staging"
+	tieredCacheDirName = "tiered-cache"
+	quarantineDirName  = "quarantine"
 
 	commitLogComponentPosition    = 2
 	indexFileSetComponentPosition = 2
@@ -68,6 +72,10 @@ var (
 
 type fileOpener func(filePath string) (*os.File, error)
 
+// filePinner opens a file and keeps it from being removed by the tiered file
+// cache until the returned unpin function is called.
+type filePinner func(filePath string) (*os.File, func(), error)
+
 // FileSetFile represents a set of FileSet files for a given block start
 type FileSetFile struct {
 	ID                FileSetFileIdentifier
@@ -563,6 +571,7 @@ func forEachInfoFile(
 
 // ReadInfoFileResult is the result of reading an info file
 type ReadInfoFileResult struct {
+	ID   FileSetFileIdentifier
 	Info schema.IndexInfo
 	Err  ReadInfoFileResultError
 }
@@ -613,6 +622,7 @@ func ReadInfoFiles(
 			decoder.Reset(msgpack.NewDecoderStream(data))
 			info, err := decoder.DecodeIndexInfo()
 			infoFileResults = append(infoFileResults, ReadInfoFileResult{
+				ID:   id,
 				Info: info,
 				Err: readInfoFileResultError{
 					err:      err,
@@ -735,6 +745,19 @@ func SortedSnapshotMetadataFiles(opts Options) (
 	return metadatas, errorsWithPaths, nil
 }
 
+// DataFiles returns a slice of all the names for all the flushed data fileset
+// files for a given namespace and shard combination.
+func DataFiles(filePathPrefix string, namespace ident.ID, shard uint32) (FileSetFilesSlice, error) {
+	return filesetFiles(filesetFilesSelector{
+		fileSetType:    persist.FileSetFlushType,
+		contentType:    persist.FileSetDataContentType,
+		filePathPrefix: filePathPrefix,
+		namespace:      namespace,
+		shard:          shard,
+		pattern:        filesetFilePattern,
+	})
+}
+
 // SnapshotFiles returns a slice of all the names for all the fileset files
 // for a given namespace and shard combination.
 func SnapshotFiles(filePathPrefix string, namespace ident.ID, shard uint32) (FileSetFilesSlice, error) {
@@ -748,6 +771,18 @@ func SnapshotFiles(filePathPrefix string, namespace ident.ID, shard uint32) (Fil
 	})
 }
 
+// IndexFiles returns a slice of all the names for all the flushed index
+// fileset files for a given namespace.
+func IndexFiles(filePathPrefix string, namespace ident.ID) (FileSetFilesSlice, error) {
+	return filesetFiles(filesetFilesSelector{
+		fileSetType:    persist.FileSetFlushType,
+		contentType:    persist.FileSetIndexContentType,
+		filePathPrefix: filePathPrefix,
+		namespace:      namespace,
+		pattern:        filesetFilePattern,
+	})
+}
+
 // IndexSnapshotFiles returns a slice of all the names for all the index fileset files
 // for a given namespace.
 func IndexSnapshotFiles(filePathPrefix string, namespace ident.ID) (FileSetFilesSlice, error) {
@@ -838,6 +873,57 @@ func DeleteFileSetAt(filePathPrefix string, namespace ident.ID, shard uint32, t
 	return DeleteFiles(fileset.AbsoluteFilepaths)
 }
 
+// QuarantineIndexFileSet moves all the files of an index fileset volume into
+// the quarantine directory for the namespace so that the volume is no longer
+// read when bootstrapping or querying. The checkpoint file is moved first so
+// that a partially quarantined volume is treated as incomplete.
+func QuarantineIndexFileSet(
+	filePathPrefix string,
+	id FileSetFileIdentifier,
+	newDirectoryMode os.FileMode,
+) error {
+	volumePattern := fmt.Sprintf("%d%s%s", id.VolumeIndex, separator, anyLowerCaseCharsNumbersPattern)
+	matches, err := filesetFiles(filesetFilesSelector{
+		fileSetType:    persist.FileSetFlushType,
+		contentType:    persist.FileSetIndexContentType,
+		filePathPrefix: filePathPrefix,
+		namespace:      id.Namespace,
+		pattern:        filesetFileForTime(id.BlockStart, volumePattern),
+	})
+	if err != nil {
+		return err
+	}
+
+	var filePaths []string
+	for _, fileset := range matches {
+		if fileset.ID.BlockStart.Equal(id.BlockStart) && fileset.ID.VolumeIndex == id.VolumeIndex {
+			filePaths = append(filePaths, fileset.AbsoluteFilepaths...)
+		}
+	}
+	if len(filePaths) == 0 {
+		return fmt.Errorf("index fileset for blockStart: %d, volume: %d does not exist",
+			id.BlockStart.Unix(), id.VolumeIndex)
+	}
+
+	quarantineDir := NamespaceIndexQuarantineDirPath(filePathPrefix, id.Namespace)
+	if err := os.MkdirAll(quarantineDir, newDirectoryMode); err != nil {
+		return err
+	}
+
+	sort.SliceStable(filePaths, func(i, j int) bool {
+		return strings.HasSuffix(filePaths[i], checkpointFileSuffix+fileSuffix) &&
+			!strings.HasSuffix(filePaths[j], checkpointFileSuffix+fileSuffix)
+	})
+	for _, filePath := range filePaths {
+		quarantinePath := path.Join(quarantineDir, path.Base(filePath))
+		if err := os.Rename(filePath, quarantinePath); err != nil {
+			return fmt.Errorf("failed to quarantine file %s: %v", filePath, err)
+		}
+	}
+
+	return nil
+}
+
 // DataFileSetsBefore returns all the flush data fileset files whose timestamps are earlier than a given time.
 func DataFileSetsBefore(filePathPrefix string, namespace ident.ID, shard uint32, t time.Time) ([]string, error) {
 	matched, err := filesetFiles(filesetFilesSelector{
@@ -1131,6 +1217,12 @@ func NamespaceIndexSnapshotDirPath(prefix string, namespace ident.ID) string {
 	return path.Join(prefix, indexDirName, snapshotDirName, namespace.String())
 }
 
+// NamespaceIndexQuarantineDirPath returns the path to the directory that corrupt
+// index filesets are moved to for a given namespace.
+func NamespaceIndexQuarantineDirPath(prefix string, namespace ident.ID) string {
+	return path.Join(prefix, quarantineDirName, indexDirName, namespace.String())
+}
+
 // SnapshotsDirPath returns the path to the snapshots directory.
 func SnapshotsDirPath(prefix string) string {
 	return path.Join(prefix, snapshotDirName)
@@ -1153,6 +1245,77 @@ func CommitLogsDirPath(prefix string) string {
 	return path.Join(prefix, commitLogsDirName)
 }
 
+// DownsampleCheckpointFilePath returns the path to the checkpoint file that
+// records the progress of rolling up a source namespace into a target namespace.
+func DownsampleCheckpointFilePath(prefix string, source ident.ID, target ident.ID) string {
+	return path.Join(prefix, downsampleDirName, source.String(), target.String()+".json")
+}
+
+// DownsampleStagingDirPath returns the path to the directory that rolled up
+// data filesets are written to before they are moved into the data directory.
+func DownsampleStagingDirPath(prefix string) string {
+	return path.Join(prefix, downsampleDirName, stagingDirName)
+}
+
+// TieredFileCacheDirPath returns the default path to the directory that files
+// downloaded from the object store are cached in.
+func TieredFileCacheDirPath(prefix string) string {
+	return path.Join(prefix, tieredCacheDirName)
+}
+
+// MoveDataFileSet moves a complete data fileset for the given namespace, shard
+// and block start from one file path prefix to another on the same filesystem,
+// replacing any fileset already there. The checkpoint file of the existing
+// fileset is removed first and the new one is moved into place last so that the
+// fileset is never seen as complete while it is made up of old and new files,
+// readers that already have the old files open can keep reading them.
+func MoveDataFileSet(
+	srcFilePathPrefix string,
+	dstFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	newDirectoryMode os.FileMode,
+) error {
+	var (
+		srcShardDir       = ShardDataDirPath(srcFilePathPrefix, namespace, shard)
+		dstShardDir       = ShardDataDirPath(dstFilePathPrefix, namespace, shard)
+		srcCheckpointPath = filesetPathFromTime(srcShardDir, blockStart, checkpointFileSuffix)
+		dstCheckpointPath = filesetPathFromTime(dstShardDir, blockStart, checkpointFileSuffix)
+	)
+	complete, err := CompleteCheckpointFileExists(srcCheckpointPath)
+	if err != nil {
+		return err
+	}
+	if !complete {
+		return fmt.Errorf("fileset for blockStart: %d is not complete", blockStart.Unix())
+	}
+
+	if err := os.MkdirAll(dstShardDir, newDirectoryMode); err != nil {
+		return err
+	}
+	if err := os.Remove(dstCheckpointPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, suffix := range []string{
+		infoFileSuffix,
+		indexFileSuffix,
+		summariesFileSuffix,
+		bloomFilterFileSuffix,
+		dataFileSuffix,
+		digestFileSuffix,
+	} {
+		srcFilePath := filesetPathFromTime(srcShardDir, blockStart, suffix)
+		dstFilePath := filesetPathFromTime(dstShardDir, blockStart, suffix)
+		if err := os.Rename(srcFilePath, dstFilePath); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(srcCheckpointPath, dstCheckpointPath)
+}
+
 // DataFileSetExistsAt determines whether data fileset files exist for the given namespace, shard, and block start.
 func DataFileSetExistsAt(filePathPrefix string, namespace ident.ID, shard uint32, blockStart time.Time) (bool, error) {
 	shardDir := ShardDataDirPath(filePathPrefix, namespace, shard)
@@ -1202,6 +1365,10 @@ func NextSnapshotMetadataFileIndex(opts Options) (int64, error) {
 		return 0, err
 	}
 
+	if len(snapshotMetadataFiles) == 0 {
+		return 0, nil
+	}
+
 	lastSnapshotMetadataFile := snapshotMetadataFiles[len(snapshotMetadataFiles)-1]
 	return lastSnapshotMetadataFile.ID.Index + 1, nil
 }
@@ -410,6 +410,11 @@ func TestShardDirPath(t *testing.T) {
 	require.Equal(t, "foo/bar/data/testNs/12", ShardDataDirPath("foo/bar/", testNs1ID, 12))
 }
 
+func TestDownsampleCheckpointFilePath(t *testing.T) {
+	require.Equal(t, "foo/bar/downsample/testNs/testNs2.json",
+		DownsampleCheckpointFilePath("foo/bar", testNs1ID, testNs2ID))
+}
+
 func TestFilePathFromTime(t *testing.T) {
 	start := time.Unix(1465501321, 123456789)
 	inputs := []struct {
@@ -682,6 +687,11 @@ func TestNextSnapshotMetadataFileIndex(t *testing.T) {
 	require.Empty(t, errorsWithpaths)
 	require.Empty(t, metadataFiles)
 
+	// First index should be zero when there are no files.
+	nextIdx, err := NextSnapshotMetadataFileIndex(opts)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), nextIdx)
+
 	writer := NewSnapshotMetadataWriter(opts)
 	// Write out a bunch of metadata files along with their corresponding checkpoints.
 	for i := 0; i < numMetadataFiles; i++ {
@@ -700,7 +710,7 @@ func TestNextSnapshotMetadataFileIndex(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	nextIdx, err := NextSnapshotMetadataFileIndex(opts)
+	nextIdx, err = NextSnapshotMetadataFileIndex(opts)
 	require.NoError(t, err)
 	// Snapshot metadata file indices are zero-based so if we wrote out
 	// numMetadataFiles, then the last index should be numMetadataFiles-1
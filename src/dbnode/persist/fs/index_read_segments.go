@@ -22,7 +22,9 @@ package fs
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"os"
 
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	m3ninxpersist "github.com/m3db/m3/src/m3ninx/persist"
@@ -32,6 +34,37 @@ var (
 	errFilesystemOptionsNotSpecified = errors.New("filesystem options not specified")
 )
 
+type corruptIndexFileSetError struct {
+	err error
+}
+
+func (e corruptIndexFileSetError) Error() string {
+	return fmt.Sprintf("corrupt index fileset: %v", e.err)
+}
+
+// IsCorruptIndexFileSetError returns whether an error returned when reading
+// index segments was caused by the contents of the index fileset being corrupt
+// rather than a failure to access the files.
+func IsCorruptIndexFileSetError(err error) bool {
+	_, ok := err.(corruptIndexFileSetError)
+	return ok
+}
+
+// isCorruptIndexReaderOpenError returns whether an error opening an index
+// fileset volume with a complete checkpoint file was caused by its digests or
+// info file being missing or invalid rather than a failure to access them.
+func isCorruptIndexReaderOpenError(err error) bool {
+	if err == ErrCheckpointFileNotFound {
+		return false
+	}
+	if pathErr, ok := err.(*os.PathError); ok {
+		// The checkpoint file is written last so every other file of the
+		// volume should exist.
+		return os.IsNotExist(pathErr)
+	}
+	return true
+}
+
 // ReadIndexSegmentsOptions is a set of options used when reading
 // index segments.
 type ReadIndexSegmentsOptions struct {
@@ -42,6 +75,10 @@ type ReadIndexSegmentsOptions struct {
 	// required for reading index segments.
 	FilesystemOptions Options
 
+	// Validate will validate the checksums of the digests file, the info
+	// file and every segment file before any segments are constructed.
+	Validate bool
+
 	// Unexported fields that are hooks used for testing.
 	newReaderFn            newIndexReaderFn
 	newPersistentSegmentFn newPersistentSegmentFn
@@ -73,13 +110,20 @@ func ReadIndexSegments(
 	}
 
 	var (
+		filesets []m3ninxpersist.IndexSegmentFileSet
 		segments []segment.Segment
+		consumed int
 		success  = false
 	)
 
 	// Need to do this to guarantee we release all resources in case of failure.
 	defer func() {
 		if !success {
+			// Any file sets that have not been handed to a segment yet still
+			// own their underlying files and need to be closed directly.
+			for _, fileset := range filesets[consumed:] {
+				closeIndexSegmentFileSet(fileset)
+			}
 			for _, seg := range segments {
 				seg.Close()
 			}
@@ -88,8 +132,12 @@ func ReadIndexSegments(
 	}()
 
 	if _, err := reader.Open(readerOpts); err != nil {
+		if opts.Validate && isCorruptIndexReaderOpenError(err) {
+			return nil, corruptIndexFileSetError{err: err}
+		}
 		return nil, err
 	}
+	filesets = make([]m3ninxpersist.IndexSegmentFileSet, 0, reader.SegmentFileSets())
 	segments = make([]segment.Segment, 0, reader.SegmentFileSets())
 
 	for {
@@ -101,8 +149,28 @@ func ReadIndexSegments(
 			return nil, err
 		}
 
+		filesets = append(filesets, fileset)
+	}
+
+	if opts.Validate {
+		// NB: Digests of the segment files are only calculated as they are
+		// read so validation must happen after all file sets have been read.
+		if err := reader.Validate(); err != nil {
+			return nil, corruptIndexFileSetError{err: err}
+		}
+	}
+
+	for _, fileset := range filesets {
+		// NB: The persistent segment takes ownership of the file set files,
+		// including releasing them if it fails to be constructed.
+		consumed++
 		seg, err := newPersistentSegment(fileset, fsOpts.FSTOptions())
+		if m3ninxpersist.IsCorruptSegmentError(err) {
+			return nil, corruptIndexFileSetError{err: err}
+		}
 		if err != nil {
+			// Failures to access the files, such as failing to mmap them, do
+			// not mean that the volume is corrupt.
 			return nil, err
 		}
 
@@ -114,3 +182,9 @@ func ReadIndexSegments(
 	success = true
 	return segments, nil
 }
+
+func closeIndexSegmentFileSet(fileset m3ninxpersist.IndexSegmentFileSet) {
+	for _, file := range fileset.Files() {
+		file.Close()
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	m3ninxfs "github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestIndexFileSet(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	test indexWriteTestSetup,
+) []testIndexSegment {
+	writer := newTestIndexWriter(t, test.filePathPrefix)
+	err := writer.Open(IndexWriterOpenOptions{
+		Identifier:  test.fileSetID,
+		BlockSize:   test.blockSize,
+		FileSetType: persist.FileSetFlushType,
+		Shards:      shardsSet(1, 2),
+	})
+	require.NoError(t, err)
+
+	testSegments := []testIndexSegment{
+		{
+			segmentType:  idxpersist.IndexSegmentType("fst"),
+			majorVersion: 1,
+			minorVersion: 1,
+			files: []testIndexSegmentFile{
+				{idxpersist.IndexSegmentFileType("first"), randDataFactorOfBuffSize(t, 1.5)},
+				{idxpersist.IndexSegmentFileType("second"), randDataFactorOfBuffSize(t, 2.5)},
+			},
+		},
+	}
+	writeTestIndexSegments(t, ctrl, writer, testSegments)
+	require.NoError(t, writer.Close())
+	return testSegments
+}
+
+func TestReadIndexSegmentsValidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	test := newIndexWriteTestSetup(t)
+	defer test.cleanup()
+
+	writeTestIndexFileSet(t, ctrl, test)
+
+	seg := m3ninxfs.NewMockSegment(ctrl)
+	segments, err := ReadIndexSegments(ReadIndexSegmentsOptions{
+		ReaderOptions: IndexReaderOpenOptions{
+			Identifier:  test.fileSetID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: testDefaultOpts.SetFilePathPrefix(test.filePathPrefix),
+		Validate:          true,
+		newPersistentSegmentFn: func(
+			fileset idxpersist.IndexSegmentFileSet,
+			opts m3ninxfs.Options,
+		) (m3ninxfs.Segment, error) {
+			for _, file := range fileset.Files() {
+				require.NoError(t, file.Close())
+			}
+			return seg, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(segments))
+}
+
+func TestReadIndexSegmentsValidateCorruptSegmentFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	test := newIndexWriteTestSetup(t)
+	defer test.cleanup()
+
+	testSegments := writeTestIndexFileSet(t, ctrl, test)
+
+	// Flip a byte in one of the segment files.
+	corruptFilePath := filesetIndexSegmentFilePathFromTime(
+		NamespaceIndexDataDirPath(test.filePathPrefix, test.fileSetID.Namespace),
+		test.blockStart, test.fileSetID.VolumeIndex, 0,
+		testSegments[0].files[1].segmentFileType)
+	fd, err := os.OpenFile(corruptFilePath, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = fd.WriteAt([]byte{^testSegments[0].files[1].data[0]}, 0)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	_, err = ReadIndexSegments(ReadIndexSegmentsOptions{
+		ReaderOptions: IndexReaderOpenOptions{
+			Identifier:  test.fileSetID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: testDefaultOpts.SetFilePathPrefix(test.filePathPrefix),
+		Validate:          true,
+		newPersistentSegmentFn: func(
+			fileset idxpersist.IndexSegmentFileSet,
+			opts m3ninxfs.Options,
+		) (m3ninxfs.Segment, error) {
+			require.FailNow(t, "segment constructed from corrupt fileset")
+			return nil, nil
+		},
+	})
+	require.Error(t, err)
+	require.True(t, IsCorruptIndexFileSetError(err))
+}
+
+func TestReadIndexSegmentsValidateCorruptInfoFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	test := newIndexWriteTestSetup(t)
+	defer test.cleanup()
+
+	writeTestIndexFileSet(t, ctrl, test)
+
+	// Flip a byte in the info file.
+	infoFilePath := filesetPathFromTimeAndIndex(
+		NamespaceIndexDataDirPath(test.filePathPrefix, test.fileSetID.Namespace),
+		test.blockStart, test.fileSetID.VolumeIndex, infoFileSuffix)
+	data, err := ioutil.ReadFile(infoFilePath)
+	require.NoError(t, err)
+	data[0] = ^data[0]
+	require.NoError(t, ioutil.WriteFile(infoFilePath, data, defaultNewFileMode))
+
+	_, err = ReadIndexSegments(ReadIndexSegmentsOptions{
+		ReaderOptions: IndexReaderOpenOptions{
+			Identifier:  test.fileSetID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: testDefaultOpts.SetFilePathPrefix(test.filePathPrefix),
+		Validate:          true,
+	})
+	require.Error(t, err)
+	require.True(t, IsCorruptIndexFileSetError(err))
+}
+
+func TestReadIndexSegmentsSegmentErrorNotCorrupt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	test := newIndexWriteTestSetup(t)
+	defer test.cleanup()
+
+	writeTestIndexFileSet(t, ctrl, test)
+
+	_, err := ReadIndexSegments(ReadIndexSegmentsOptions{
+		ReaderOptions: IndexReaderOpenOptions{
+			Identifier:  test.fileSetID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: testDefaultOpts.SetFilePathPrefix(test.filePathPrefix),
+		Validate:          true,
+		newPersistentSegmentFn: func(
+			fileset idxpersist.IndexSegmentFileSet,
+			opts m3ninxfs.Options,
+		) (m3ninxfs.Segment, error) {
+			for _, file := range fileset.Files() {
+				require.NoError(t, file.Close())
+			}
+			return nil, errors.New("unable to mmap")
+		},
+	})
+	require.Error(t, err)
+	require.False(t, IsCorruptIndexFileSetError(err))
+}
+
+func TestQuarantineIndexFileSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	test := newIndexWriteTestSetup(t)
+	defer test.cleanup()
+
+	writeTestIndexFileSet(t, ctrl, test)
+
+	filesets, err := IndexFileSetsAt(test.filePathPrefix,
+		test.fileSetID.Namespace, test.blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	filePaths := filesets[0].AbsoluteFilepaths
+
+	err = QuarantineIndexFileSet(test.filePathPrefix, test.fileSetID,
+		testDefaultOpts.NewDirectoryMode())
+	require.NoError(t, err)
+
+	filesets, err = IndexFileSetsAt(test.filePathPrefix,
+		test.fileSetID.Namespace, test.blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(filesets))
+
+	quarantineDir := NamespaceIndexQuarantineDirPath(test.filePathPrefix,
+		test.fileSetID.Namespace)
+	for _, filePath := range filePaths {
+		exists, err := FileExists(filePath)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		exists, err = FileExists(filepath.Join(quarantineDir, filepath.Base(filePath)))
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	// Quarantining a volume that does not exist is an error.
+	err = QuarantineIndexFileSet(test.filePathPrefix, test.fileSetID,
+		testDefaultOpts.NewDirectoryMode())
+	require.Error(t, err)
+}
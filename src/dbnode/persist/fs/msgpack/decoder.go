@@ -31,16 +31,18 @@ import (
 )
 
 var (
-	emptyIndexInfo              schema.IndexInfo
-	emptyIndexSummariesInfo     schema.IndexSummariesInfo
-	emptyIndexBloomFilterInfo   schema.IndexBloomFilterInfo
-	emptyIndexEntry             schema.IndexEntry
-	emptyIndexSummary           schema.IndexSummary
-	emptyIndexSummaryToken      IndexSummaryToken
-	emptyLogInfo                schema.LogInfo
-	emptyLogEntry               schema.LogEntry
-	emptyLogMetadata            schema.LogMetadata
-	emptyLogEntryRemainingToken DecodeLogEntryRemainingToken
+	emptyIndexInfo                 schema.IndexInfo
+	emptyIndexSummariesInfo        schema.IndexSummariesInfo
+	emptyIndexBloomFilterInfo      schema.IndexBloomFilterInfo
+	emptyIndexDataCompressionInfo  schema.IndexDataCompressionInfo
+	emptyIndexDataCompressionFrame schema.IndexDataCompressionFrame
+	emptyIndexEntry                schema.IndexEntry
+	emptyIndexSummary              schema.IndexSummary
+	emptyIndexSummaryToken         IndexSummaryToken
+	emptyLogInfo                   schema.LogInfo
+	emptyLogEntry                  schema.LogEntry
+	emptyLogMetadata               schema.LogMetadata
+	emptyLogEntryRemainingToken    DecodeLogEntryRemainingToken
 )
 
 var errorUnableToDetermineNumFieldsToSkip = errors.New("unable to determine num fields to skip")
@@ -236,6 +238,11 @@ func (dec *Decoder) decodeIndexInfo() schema.IndexInfo {
 		opts.override = true
 		opts.numExpectedMinFields = 8
 		opts.numExpectedCurrFields = 8
+	} else if dec.legacy.decodeLegacyIndexInfoVersion == legacyEncodingIndexVersionV3 {
+		// V3 had 9 fields.
+		opts.override = true
+		opts.numExpectedMinFields = 9
+		opts.numExpectedCurrFields = 9
 	}
 
 	numFieldsToSkip, actual, ok := dec.checkNumFieldsFor(indexInfoType, opts)
@@ -270,10 +277,64 @@ func (dec *Decoder) decodeIndexInfo() schema.IndexInfo {
 	// Decode fields added in V3.
 	indexInfo.SnapshotID, _, _ = dec.decodeBytes()
 
+	// At this point if its a V3 file we've decoded all the available fields.
+	if dec.legacy.decodeLegacyIndexInfoVersion == legacyEncodingIndexVersionV3 || actual < 10 {
+		dec.skip(numFieldsToSkip)
+		return indexInfo
+	}
+
+	// Decode fields added in V4.
+	indexInfo.DataCompression = dec.decodeIndexDataCompressionInfo()
+
 	dec.skip(numFieldsToSkip)
 	return indexInfo
 }
 
+func (dec *Decoder) decodeIndexDataCompressionInfo() schema.IndexDataCompressionInfo {
+	numFieldsToSkip, _, ok := dec.checkNumFieldsFor(indexDataCompressionInfoType, checkNumFieldsOptions{})
+	if !ok {
+		return emptyIndexDataCompressionInfo
+	}
+	var info schema.IndexDataCompressionInfo
+	info.Type = dec.decodeVarint()
+	numFrames := dec.decodeArrayLen()
+	if dec.err != nil {
+		return emptyIndexDataCompressionInfo
+	}
+	if numFrames > 0 {
+		info.Frames = make([]schema.IndexDataCompressionFrame, 0, numFrames)
+	}
+	for i := 0; i < numFrames; i++ {
+		frame := dec.decodeIndexDataCompressionFrame()
+		if dec.err != nil {
+			return emptyIndexDataCompressionInfo
+		}
+		info.Frames = append(info.Frames, frame)
+	}
+	dec.skip(numFieldsToSkip)
+	if dec.err != nil {
+		return emptyIndexDataCompressionInfo
+	}
+	return info
+}
+
+func (dec *Decoder) decodeIndexDataCompressionFrame() schema.IndexDataCompressionFrame {
+	numFieldsToSkip, _, ok := dec.checkNumFieldsFor(indexDataCompressionFrameType, checkNumFieldsOptions{})
+	if !ok {
+		return emptyIndexDataCompressionFrame
+	}
+	var frame schema.IndexDataCompressionFrame
+	frame.Offset = dec.decodeVarint()
+	frame.Size = dec.decodeVarint()
+	frame.CompressedOffset = dec.decodeVarint()
+	frame.CompressedSize = dec.decodeVarint()
+	dec.skip(numFieldsToSkip)
+	if dec.err != nil {
+		return emptyIndexDataCompressionFrame
+	}
+	return frame
+}
+
 func (dec *Decoder) decodeIndexSummariesInfo() schema.IndexSummariesInfo {
 	numFieldsToSkip, _, ok := dec.checkNumFieldsFor(indexSummariesInfoType, checkNumFieldsOptions{})
 	if !ok {
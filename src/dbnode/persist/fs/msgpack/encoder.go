@@ -58,6 +58,7 @@ type legacyEncodingIndexInfoVersion int
 const (
 	// List in reverse order to ensure default value is current version.
 	legacyEncodingIndexVersionCurrent legacyEncodingIndexInfoVersion = iota
+	legacyEncodingIndexVersionV3
 	legacyEncodingIndexVersionV2
 	legacyEncodingIndexVersionV1
 )
@@ -123,8 +124,10 @@ func (enc *Encoder) EncodeIndexInfo(info schema.IndexInfo) error {
 		enc.encodeIndexInfoV1(info)
 	} else if enc.legacy.encodeLegacyIndexInfoVersion == legacyEncodingIndexVersionV2 {
 		enc.encodeIndexInfoV2(info)
-	} else {
+	} else if enc.legacy.encodeLegacyIndexInfoVersion == legacyEncodingIndexVersionV3 {
 		enc.encodeIndexInfoV3(info)
+	} else {
+		enc.encodeIndexInfoV4(info)
 	}
 	return enc.err
 }
@@ -211,7 +214,23 @@ func (enc *Encoder) encodeIndexInfoV2(info schema.IndexInfo) {
 	enc.encodeVarintFn(int64(info.FileType))
 }
 
+// We only keep this method around for the sake of testing
+// backwards-compatbility.
 func (enc *Encoder) encodeIndexInfoV3(info schema.IndexInfo) {
+	// Manually encode num fields for testing purposes.
+	enc.encodeArrayLenFn(9) // V3 had 9 fields.
+	enc.encodeVarintFn(info.BlockStart)
+	enc.encodeVarintFn(info.BlockSize)
+	enc.encodeVarintFn(info.Entries)
+	enc.encodeVarintFn(info.MajorVersion)
+	enc.encodeIndexSummariesInfo(info.Summaries)
+	enc.encodeIndexBloomFilterInfo(info.BloomFilter)
+	enc.encodeVarintFn(info.SnapshotTime)
+	enc.encodeVarintFn(int64(info.FileType))
+	enc.encodeBytesFn(info.SnapshotID)
+}
+
+func (enc *Encoder) encodeIndexInfoV4(info schema.IndexInfo) {
 	enc.encodeNumObjectFieldsForFn(indexInfoType)
 	enc.encodeVarintFn(info.BlockStart)
 	enc.encodeVarintFn(info.BlockSize)
@@ -222,6 +241,7 @@ func (enc *Encoder) encodeIndexInfoV3(info schema.IndexInfo) {
 	enc.encodeVarintFn(info.SnapshotTime)
 	enc.encodeVarintFn(int64(info.FileType))
 	enc.encodeBytesFn(info.SnapshotID)
+	enc.encodeIndexDataCompressionInfo(info.DataCompression)
 }
 
 func (enc *Encoder) encodeIndexSummariesInfo(info schema.IndexSummariesInfo) {
@@ -229,6 +249,23 @@ func (enc *Encoder) encodeIndexSummariesInfo(info schema.IndexSummariesInfo) {
 	enc.encodeVarintFn(info.Summaries)
 }
 
+func (enc *Encoder) encodeIndexDataCompressionInfo(info schema.IndexDataCompressionInfo) {
+	enc.encodeNumObjectFieldsForFn(indexDataCompressionInfoType)
+	enc.encodeVarintFn(info.Type)
+	enc.encodeArrayLenFn(len(info.Frames))
+	for _, frame := range info.Frames {
+		enc.encodeIndexDataCompressionFrame(frame)
+	}
+}
+
+func (enc *Encoder) encodeIndexDataCompressionFrame(frame schema.IndexDataCompressionFrame) {
+	enc.encodeNumObjectFieldsForFn(indexDataCompressionFrameType)
+	enc.encodeVarintFn(frame.Offset)
+	enc.encodeVarintFn(frame.Size)
+	enc.encodeVarintFn(frame.CompressedOffset)
+	enc.encodeVarintFn(frame.CompressedSize)
+}
+
 func (enc *Encoder) encodeIndexBloomFilterInfo(info schema.IndexBloomFilterInfo) {
 	enc.encodeNumObjectFieldsForFn(indexBloomFilterInfoType)
 	enc.encodeVarintFn(info.NumElementsM)
@@ -64,7 +64,9 @@ func testExpectedResultForIndexInfo(t *testing.T, indexInfo schema.IndexInfo) []
 	_, currIndexInfo := numFieldsForType(indexInfoType)
 	_, currSummariesInfo := numFieldsForType(indexSummariesInfoType)
 	_, currIndexBloomFilterInfo := numFieldsForType(indexBloomFilterInfoType)
-	return []interface{}{
+	_, currIndexDataCompressionInfo := numFieldsForType(indexDataCompressionInfoType)
+	_, currIndexDataCompressionFrame := numFieldsForType(indexDataCompressionFrameType)
+	result := []interface{}{
 		int64(indexInfoVersion),
 		currRoot,
 		int64(indexInfoType),
@@ -81,7 +83,20 @@ func testExpectedResultForIndexInfo(t *testing.T, indexInfo schema.IndexInfo) []
 		indexInfo.SnapshotTime,
 		int64(indexInfo.FileType),
 		indexInfo.SnapshotID,
+		currIndexDataCompressionInfo,
+		indexInfo.DataCompression.Type,
+		len(indexInfo.DataCompression.Frames),
+	}
+	for _, frame := range indexInfo.DataCompression.Frames {
+		result = append(result,
+			currIndexDataCompressionFrame,
+			frame.Offset,
+			frame.Size,
+			frame.CompressedOffset,
+			frame.CompressedSize,
+		)
 	}
+	return result
 }
 
 func testExpectedResultForIndexEntry(t *testing.T, indexEntry schema.IndexEntry) []interface{} {
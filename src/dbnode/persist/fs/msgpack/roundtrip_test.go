@@ -46,6 +46,13 @@ var (
 		SnapshotTime: time.Now().UnixNano(),
 		FileType:     persist.FileSetSnapshotType,
 		SnapshotID:   []byte("some_bytes"),
+		DataCompression: schema.IndexDataCompressionInfo{
+			Type: 1,
+			Frames: []schema.IndexDataCompressionFrame{
+				{Offset: 0, Size: 65536, CompressedOffset: 0, CompressedSize: 12345},
+				{Offset: 65536, Size: 1024, CompressedOffset: 12345, CompressedSize: 512},
+			},
+		},
 	}
 
 	testIndexEntry = schema.IndexEntry{
@@ -116,14 +123,17 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV1(t *testing.T) {
 		currSnapshotTime = testIndexInfo.SnapshotTime
 		currFileType     = testIndexInfo.FileType
 		currSnapshotID   = testIndexInfo.SnapshotID
+		currCompression  = testIndexInfo.DataCompression
 	)
 	testIndexInfo.SnapshotTime = 0
 	testIndexInfo.FileType = 0
 	testIndexInfo.SnapshotID = nil
+	testIndexInfo.DataCompression = schema.IndexDataCompressionInfo{}
 	defer func() {
 		testIndexInfo.SnapshotTime = currSnapshotTime
 		testIndexInfo.FileType = currFileType
 		testIndexInfo.SnapshotID = currSnapshotID
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -148,6 +158,7 @@ func TestIndexInfoRoundTripForwardsCompatibilityV2(t *testing.T) {
 		currSnapshotTime = testIndexInfo.SnapshotTime
 		currFileType     = testIndexInfo.FileType
 		currSnapshotID   = testIndexInfo.SnapshotID
+		currCompression  = testIndexInfo.DataCompression
 	)
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -157,10 +168,12 @@ func TestIndexInfoRoundTripForwardsCompatibilityV2(t *testing.T) {
 	testIndexInfo.SnapshotTime = 0
 	testIndexInfo.FileType = 0
 	testIndexInfo.SnapshotID = nil
+	testIndexInfo.DataCompression = schema.IndexDataCompressionInfo{}
 	defer func() {
 		testIndexInfo.SnapshotTime = currSnapshotTime
 		testIndexInfo.FileType = currFileType
 		testIndexInfo.SnapshotID = currSnapshotID
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	dec.Reset(NewDecoderStream(enc.Bytes()))
@@ -185,14 +198,17 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV2(t *testing.T) {
 		currSnapshotTime = testIndexInfo.SnapshotTime
 		currFileType     = testIndexInfo.FileType
 		currSnapshotID   = testIndexInfo.SnapshotID
+		currCompression  = testIndexInfo.DataCompression
 	)
 	testIndexInfo.SnapshotTime = 0
 	testIndexInfo.FileType = 0
 	testIndexInfo.SnapshotID = nil
+	testIndexInfo.DataCompression = schema.IndexDataCompressionInfo{}
 	defer func() {
 		testIndexInfo.SnapshotTime = currSnapshotTime
 		testIndexInfo.FileType = currFileType
 		testIndexInfo.SnapshotID = currSnapshotID
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -213,15 +229,73 @@ func TestIndexInfoRoundTripForwardsCompatibilityV3(t *testing.T) {
 	// Set the default values on the fields that did not exist in V2
 	// and then restore them at the end of the test - This is required
 	// because the old decoder won't read the new fields.
-	currSnapshotID := testIndexInfo.SnapshotID
+	var (
+		currSnapshotID  = testIndexInfo.SnapshotID
+		currCompression = testIndexInfo.DataCompression
+	)
 
 	enc.EncodeIndexInfo(testIndexInfo)
 
 	// Make sure to zero them before we compare, but after we have
 	// encoded the data.
 	testIndexInfo.SnapshotID = nil
+	testIndexInfo.DataCompression = schema.IndexDataCompressionInfo{}
 	defer func() {
 		testIndexInfo.SnapshotID = currSnapshotID
+		testIndexInfo.DataCompression = currCompression
+	}()
+
+	dec.Reset(NewDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeIndexInfo()
+	require.NoError(t, err)
+	require.Equal(t, testIndexInfo, res)
+}
+
+// Make sure the V4 decoding code can handle the V3 file format.
+func TestIndexInfoRoundTripBackwardsCompatibilityV3(t *testing.T) {
+	var (
+		opts = legacyEncodingOptions{encodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV3}
+		enc  = newEncoder(opts)
+		dec  = newDecoder(opts, nil)
+	)
+
+	// Set the default values on the fields that did not exist in V3,
+	// and then restore them at the end of the test - This is required
+	// because the new decoder won't try and read the new fields from
+	// the old file format.
+	currCompression := testIndexInfo.DataCompression
+	testIndexInfo.DataCompression = schema.IndexDataCompressionInfo{}
+	defer func() {
+		testIndexInfo.DataCompression = currCompression
+	}()
+
+	enc.EncodeIndexInfo(testIndexInfo)
+	dec.Reset(NewDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeIndexInfo()
+	require.NoError(t, err)
+	require.Equal(t, testIndexInfo, res)
+}
+
+// Make sure the V3 decoder code can handle the V4 file format.
+func TestIndexInfoRoundTripForwardsCompatibilityV4(t *testing.T) {
+	var (
+		opts = legacyEncodingOptions{decodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV3}
+		enc  = newEncoder(opts)
+		dec  = newDecoder(opts, nil)
+	)
+
+	// Set the default values on the fields that did not exist in V3
+	// and then restore them at the end of the test - This is required
+	// because the old decoder won't read the new fields.
+	currCompression := testIndexInfo.DataCompression
+
+	enc.EncodeIndexInfo(testIndexInfo)
+
+	// Make sure to zero them before we compare, but after we have
+	// encoded the data.
+	testIndexInfo.DataCompression = schema.IndexDataCompressionInfo{}
+	defer func() {
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	dec.Reset(NewDecoderStream(enc.Bytes()))
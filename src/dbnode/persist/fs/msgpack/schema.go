@@ -70,6 +70,8 @@ const (
 	logInfoType
 	logEntryType
 	logMetadataType
+	indexDataCompressionInfoType
+	indexDataCompressionFrameType
 
 	// Total number of object types
 	numObjectTypes = iota
@@ -84,29 +86,33 @@ const (
 	// and an even earlier version only wrote 3 fields, than the minimum number
 	// should be 3 if we intend to continue reading files that were written by
 	// the version that only encoded 3 fields.
-	minNumRootObjectFields           = 2
-	minNumIndexInfoFields            = 6
-	minNumIndexSummariesInfoFields   = 1
-	minNumIndexBloomFilterInfoFields = 2
-	minNumIndexEntryFields           = 5
-	minNumIndexSummaryFields         = 3
-	minNumLogInfoFields              = 3
-	minNumLogEntryFields             = 7
-	minNumLogMetadataFields          = 3
+	minNumRootObjectFields                = 2
+	minNumIndexInfoFields                 = 6
+	minNumIndexSummariesInfoFields        = 1
+	minNumIndexBloomFilterInfoFields      = 2
+	minNumIndexEntryFields                = 5
+	minNumIndexSummaryFields              = 3
+	minNumLogInfoFields                   = 3
+	minNumLogEntryFields                  = 7
+	minNumLogMetadataFields               = 3
+	minNumIndexDataCompressionInfoFields  = 2
+	minNumIndexDataCompressionFrameFields = 4
 
 	// curr number of fields specifies the number of fields that the current
 	// version of the M3DB will encode. This is used to ensure that the
 	// correct number of fields is encoded into the files. These values need
 	// to be incremened whenever we add new fields to an object.
-	currNumRootObjectFields           = 2
-	currNumIndexInfoFields            = 9
-	currNumIndexSummariesInfoFields   = 1
-	currNumIndexBloomFilterInfoFields = 2
-	currNumIndexEntryFields           = 6
-	currNumIndexSummaryFields         = 3
-	currNumLogInfoFields              = 3
-	currNumLogEntryFields             = 7
-	currNumLogMetadataFields          = 3
+	currNumRootObjectFields                = 2
+	currNumIndexInfoFields                 = 10
+	currNumIndexSummariesInfoFields        = 1
+	currNumIndexBloomFilterInfoFields      = 2
+	currNumIndexEntryFields                = 6
+	currNumIndexSummaryFields              = 3
+	currNumLogInfoFields                   = 3
+	currNumLogEntryFields                  = 7
+	currNumLogMetadataFields               = 3
+	currNumIndexDataCompressionInfoFields  = 2
+	currNumIndexDataCompressionFrameFields = 4
 )
 
 var (
@@ -145,6 +151,8 @@ func init() {
 	setMinNumObjectFieldsForType(logInfoType, minNumLogInfoFields)
 	setMinNumObjectFieldsForType(logEntryType, minNumLogEntryFields)
 	setMinNumObjectFieldsForType(logMetadataType, minNumLogMetadataFields)
+	setMinNumObjectFieldsForType(indexDataCompressionInfoType, minNumIndexDataCompressionInfoFields)
+	setMinNumObjectFieldsForType(indexDataCompressionFrameType, minNumIndexDataCompressionFrameFields)
 
 	// Verify all current values are larger than their respective minimum values
 	mustBeGreaterThanOrEqual(currNumRootObjectFields, minNumRootObjectFields)
@@ -156,6 +164,8 @@ func init() {
 	mustBeGreaterThanOrEqual(currNumLogInfoFields, minNumLogInfoFields)
 	mustBeGreaterThanOrEqual(currNumLogEntryFields, minNumLogEntryFields)
 	mustBeGreaterThanOrEqual(currNumLogMetadataFields, minNumLogMetadataFields)
+	mustBeGreaterThanOrEqual(currNumIndexDataCompressionInfoFields, minNumIndexDataCompressionInfoFields)
+	mustBeGreaterThanOrEqual(currNumIndexDataCompressionFrameFields, minNumIndexDataCompressionFrameFields)
 
 	setCurrNumObjectFieldsForType(rootObjectType, currNumRootObjectFields)
 	setCurrNumObjectFieldsForType(indexInfoType, currNumIndexInfoFields)
@@ -166,6 +176,8 @@ func init() {
 	setCurrNumObjectFieldsForType(logInfoType, currNumLogInfoFields)
 	setCurrNumObjectFieldsForType(logEntryType, currNumLogEntryFields)
 	setCurrNumObjectFieldsForType(logMetadataType, currNumLogMetadataFields)
+	setCurrNumObjectFieldsForType(indexDataCompressionInfoType, currNumIndexDataCompressionInfoFields)
+	setCurrNumObjectFieldsForType(indexDataCompressionFrameType, currNumIndexDataCompressionFrameFields)
 
 	// Populate the fixed commit log entry header
 	encoder := NewEncoder()
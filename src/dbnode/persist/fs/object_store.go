@@ -0,0 +1,78 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+type directoryObjectStore struct {
+	dir              string
+	newFileMode      os.FileMode
+	newDirectoryMode os.FileMode
+}
+
+// NewDirectoryObjectStore returns an object store that stores each object as
+// a file beneath a directory, such as a mounted network filesystem, and is a
+// stand-in for a remote object store when testing.
+func NewDirectoryObjectStore(dir string) ObjectStore {
+	return &directoryObjectStore{
+		dir:              dir,
+		newFileMode:      defaultNewFileMode,
+		newDirectoryMode: defaultNewDirectoryMode,
+	}
+}
+
+func (s *directoryObjectStore) Put(key string, r io.Reader) error {
+	filePath := s.filePath(key)
+	if err := os.MkdirAll(filepath.Dir(filePath), s.newDirectoryMode); err != nil {
+		return err
+	}
+	return writeFileAtomically(filePath, r, s.newFileMode)
+}
+
+func (s *directoryObjectStore) Get(key string) (io.ReadCloser, error) {
+	fd, err := os.Open(s.filePath(key))
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fd, nil
+}
+
+func (s *directoryObjectStore) Delete(key string) error {
+	err := os.Remove(s.filePath(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *directoryObjectStore) filePath(key string) string {
+	// Clean the key as an absolute path so that it cannot refer
+	// to a file outside of the directory.
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
@@ -67,6 +68,9 @@ var (
 
 	errTagEncoderPoolNotSet = errors.New("tag encoder pool is not set")
 	errTagDecoderPoolNotSet = errors.New("tag decoder pool is not set")
+
+	errTierDataFileSetsAfterNotPositive = errors.New(
+		"tier data filesets after must be positive when an object store is set")
 )
 
 type options struct {
@@ -88,6 +92,9 @@ type options struct {
 	tagEncoderPool                       serialize.TagEncoderPool
 	tagDecoderPool                       serialize.TagDecoderPool
 	fstOptions                           fst.Options
+	objectStore                          ObjectStore
+	tierDataFileSetsAfter                time.Duration
+	tieredFileCache                      TieredFileCache
 }
 
 // NewOptions creates a new set of fs options
@@ -139,6 +146,9 @@ func (o *options) Validate() error {
 	if o.tagDecoderPool == nil {
 		return errTagDecoderPoolNotSet
 	}
+	if o.objectStore != nil && o.tierDataFileSetsAfter <= 0 {
+		return errTierDataFileSetsAfterNotPositive
+	}
 	return nil
 }
 
@@ -321,3 +331,33 @@ func (o *options) SetFSTOptions(value fst.Options) Options {
 func (o *options) FSTOptions() fst.Options {
 	return o.fstOptions
 }
+
+func (o *options) SetObjectStore(value ObjectStore) Options {
+	opts := *o
+	opts.objectStore = value
+	return &opts
+}
+
+func (o *options) ObjectStore() ObjectStore {
+	return o.objectStore
+}
+
+func (o *options) SetTierDataFileSetsAfter(value time.Duration) Options {
+	opts := *o
+	opts.tierDataFileSetsAfter = value
+	return &opts
+}
+
+func (o *options) TierDataFileSetsAfter() time.Duration {
+	return o.tierDataFileSetsAfter
+}
+
+func (o *options) SetTieredFileCache(value TieredFileCache) Options {
+	opts := *o
+	opts.tieredFileCache = value
+	return &opts
+}
+
+func (o *options) TieredFileCache() TieredFileCache {
+	return o.tieredFileCache
+}
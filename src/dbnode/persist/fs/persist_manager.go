@@ -425,9 +425,11 @@ func (pm *persistManager) PrepareData(opts persist.DataPrepareOptions) (persist.
 
 	blockSize := nsMetadata.Options().RetentionOptions().BlockSize()
 	dataWriterOpts := DataWriterOpenOptions{
-		BlockSize: blockSize,
+		BlockSize:   blockSize,
+		Compression: nsMetadata.Options().DataFileCompression(),
 		Snapshot: DataWriterSnapshotOptions{
 			SnapshotTime: snapshotTime,
+			SnapshotID:   opts.Snapshot.SnapshotID,
 		},
 		FileSetType: opts.FileSetType,
 		Identifier: FileSetFileIdentifier{
@@ -31,6 +31,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/mmap"
@@ -69,12 +70,23 @@ type reader struct {
 	indexDecoderStream      dataFileSetReaderDecoderStream
 	indexEntriesByOffsetAsc []schema.IndexEntry
 
-	dataFd     *os.File
-	dataMmap   []byte
-	dataReader digest.ReaderWithDigest
+	dataFd               *os.File
+	dataMmap             []byte
+	dataReader           digest.ReaderWithDigest
+	dataCompression      compression.Type
+	compressedDataReader *compressedDataReader
 
 	bloomFilterFd *os.File
 
+	// Files of filesets tiered to the object store are opened with the
+	// tiered file opener, and the bloom filter and data files of tiered
+	// filesets are only opened once needed so that metadata reads only
+	// download the index file.
+	openFn              fileOpener
+	tiered              bool
+	bloomFilterFilepath string
+	dataFilepath        string
+
 	entries         int
 	bloomFilterInfo schema.IndexBloomFilterInfo
 	entriesRead     int
@@ -117,6 +129,7 @@ func NewReader(
 		bloomFilterWithDigest:      digest.NewFdWithDigestReader(opts.InfoReaderBufferSize()),
 		indexDecoderStream:         newReaderDecoderStream(),
 		dataReader:                 digest.NewReaderWithDigest(nil),
+		compressedDataReader:       newCompressedDataReader(),
 		decoder:                    msgpack.NewDecoder(opts.DecodingOptions()),
 		digestBuf:                  digest.NewBuffer(),
 		bytesPool:                  bytesPool,
@@ -170,13 +183,28 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 	}
 	r.expectedDigestOfDigest = digest
 
+	r.openFn = os.Open
+	r.tiered = false
+	if opts.FileSetType == persist.FileSetFlushType {
+		// Flushed filesets may have been tiered to the object store.
+		r.openFn = tieredFileOpener(r.opts, r.filePathPrefix)
+		r.tiered, err = isTieredDataFileSet(r.opts, dataFilepath)
+		if err != nil {
+			return err
+		}
+	}
+	r.bloomFilterFilepath = bloomFilterFilepath
+	r.dataFilepath = dataFilepath
+
 	var infoFd, digestFd *os.File
-	err = openFiles(os.Open, map[string]**os.File{
-		infoFilepath:        &infoFd,
-		digestFilepath:      &digestFd,
-		bloomFilterFilepath: &r.bloomFilterFd,
-	})
-	if err != nil {
+	fds := map[string]**os.File{
+		infoFilepath:   &infoFd,
+		digestFilepath: &digestFd,
+	}
+	if !r.tiered {
+		fds[bloomFilterFilepath] = &r.bloomFilterFd
+	}
+	if err := openFiles(os.Open, fds); err != nil {
 		return err
 	}
 
@@ -189,26 +217,23 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		r.digestFdWithDigestContents.Close()
 	}()
 
-	result, err := mmap.Files(os.Open, map[string]mmap.FileDesc{
+	mmapFiles := map[string]mmap.FileDesc{
 		indexFilepath: mmap.FileDesc{
 			File:    &r.indexFd,
 			Bytes:   &r.indexMmap,
 			Options: mmap.Options{Read: true, HugeTLB: r.hugePagesOpts},
 		},
-		dataFilepath: mmap.FileDesc{
+	}
+	if !r.tiered {
+		mmapFiles[dataFilepath] = mmap.FileDesc{
 			File:    &r.dataFd,
 			Bytes:   &r.dataMmap,
 			Options: mmap.Options{Read: true, HugeTLB: r.hugePagesOpts},
-		},
-	})
-	if err != nil {
-		return err
+		}
 	}
-
-	if warning := result.Warning; warning != nil {
-		logger := r.opts.InstrumentOptions().Logger()
-		logger.Warnf("warning while mmapping files in reader: %s",
-			warning.Error())
+	if err := r.mmapFiles(mmapFiles); err != nil {
+		r.closeBloomFilterFd()
+		return err
 	}
 
 	r.indexDecoderStream.Reset(r.indexMmap)
@@ -240,12 +265,58 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 	return nil
 }
 
+func (r *reader) mmapFiles(files map[string]mmap.FileDesc) error {
+	result, err := mmap.Files(mmap.FileOpener(r.openFn), files)
+	if err != nil {
+		return err
+	}
+
+	if warning := result.Warning; warning != nil {
+		logger := r.opts.InstrumentOptions().Logger()
+		logger.Warnf("warning while mmapping files in reader: %s",
+			warning.Error())
+	}
+	return nil
+}
+
+// openTieredData opens the data file of a tiered fileset the first time
+// it is needed, the data file is downloaded if it is not already cached.
+func (r *reader) openTieredData() error {
+	if !r.tiered || r.dataFd != nil {
+		return nil
+	}
+
+	err := r.mmapFiles(map[string]mmap.FileDesc{
+		r.dataFilepath: mmap.FileDesc{
+			File:    &r.dataFd,
+			Bytes:   &r.dataMmap,
+			Options: mmap.Options{Read: true, HugeTLB: r.hugePagesOpts},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	r.dataReader.Reset(bytes.NewReader(r.dataMmap))
+	return nil
+}
+
+func (r *reader) closeBloomFilterFd() error {
+	if r.bloomFilterFd == nil {
+		return nil
+	}
+	err := r.bloomFilterFd.Close()
+	r.bloomFilterFd = nil
+	return err
+}
+
 func (r *reader) Status() DataFileSetReaderStatus {
 	return DataFileSetReaderStatus{
-		Open:       r.open,
-		Namespace:  r.namespace,
-		Shard:      r.shard,
-		BlockStart: r.start,
+		Open:        r.open,
+		Namespace:   r.namespace,
+		Shard:       r.shard,
+		BlockStart:  r.start,
+		Compression: r.dataCompression,
 	}
 }
 
@@ -287,6 +358,17 @@ func (r *reader) readInfo(size int) error {
 	r.entriesRead = 0
 	r.metadataRead = 0
 	r.bloomFilterInfo = info.BloomFilter
+	r.dataCompression = compression.Type(info.DataCompression.Type)
+	if r.dataCompression != compression.None {
+		codec, err := compression.NewCodec(r.dataCompression)
+		if err != nil {
+			return err
+		}
+		// NB: Read the compressed frames through the data reader so that
+		// the digest of the data file is still validated.
+		r.compressedDataReader.Reset(r.dataReader, codec,
+			dataCompressionFrames(info.DataCompression.Frames))
+	}
 	return nil
 }
 
@@ -320,6 +402,10 @@ func (r *reader) Read() (ident.ID, ident.TagIterator, checked.Bytes, uint32, err
 
 	entry := r.indexEntriesByOffsetAsc[r.entriesRead]
 
+	if err := r.openTieredData(); err != nil {
+		return nil, nil, nil, 0, err
+	}
+
 	var data checked.Bytes
 	if r.bytesPool != nil {
 		data = r.bytesPool.Get(int(entry.Size))
@@ -332,7 +418,7 @@ func (r *reader) Read() (ident.ID, ident.TagIterator, checked.Bytes, uint32, err
 		defer data.DecRef()
 	}
 
-	n, err := r.dataReader.Read(data.Bytes())
+	n, err := r.readData(data.Bytes())
 	if err != nil {
 		return nil, nil, nil, 0, err
 	}
@@ -347,6 +433,13 @@ func (r *reader) Read() (ident.ID, ident.TagIterator, checked.Bytes, uint32, err
 	return id, tags, data, uint32(entry.Checksum), nil
 }
 
+func (r *reader) readData(buf []byte) (int, error) {
+	if r.dataCompression != compression.None {
+		return io.ReadFull(r.compressedDataReader, buf)
+	}
+	return r.dataReader.Read(buf)
+}
+
 func (r *reader) ReadMetadata() (ident.ID, ident.TagIterator, int, uint32, error) {
 	if r.metadataRead >= r.entries {
 		return nil, nil, 0, 0, io.EOF
@@ -363,6 +456,15 @@ func (r *reader) ReadMetadata() (ident.ID, ident.TagIterator, int, uint32, error
 }
 
 func (r *reader) ReadBloomFilter() (*ManagedConcurrentBloomFilter, error) {
+	if r.bloomFilterFd == nil {
+		// The bloom filter of a tiered fileset is opened the first time it is
+		// read, it is downloaded if it is not already cached.
+		fd, err := r.openFn(r.bloomFilterFilepath)
+		if err != nil {
+			return nil, err
+		}
+		r.bloomFilterFd = fd
+	}
 	return newManagedConcurrentBloomFilterFromFile(
 		r.bloomFilterFd,
 		r.bloomFilterWithDigest,
@@ -421,6 +523,9 @@ func (r *reader) ValidateMetadata() error {
 // NB(xichen): ValidateData should be called after all data is read because
 // the digest is calculated for the entire data file.
 func (r *reader) ValidateData() error {
+	if err := r.openTieredData(); err != nil {
+		return err
+	}
 	err := r.dataReader.Validate(r.expectedDataDigest)
 	if err != nil {
 		return fmt.Errorf("could not validate data file: %v", err)
@@ -450,10 +555,13 @@ func (r *reader) Close() error {
 	multiErr = multiErr.Add(mmap.Munmap(r.indexMmap))
 	multiErr = multiErr.Add(mmap.Munmap(r.dataMmap))
 	multiErr = multiErr.Add(r.indexFd.Close())
-	multiErr = multiErr.Add(r.dataFd.Close())
-	multiErr = multiErr.Add(r.bloomFilterFd.Close())
+	if r.dataFd != nil {
+		multiErr = multiErr.Add(r.dataFd.Close())
+	}
+	multiErr = multiErr.Add(r.closeBloomFilterFd())
 	r.indexDecoderStream.Reset(nil)
 	r.dataReader.Reset(nil)
+	r.compressedDataReader.Reset(nil, nil, nil)
 	for i := 0; i < len(r.indexEntriesByOffsetAsc); i++ {
 		r.indexEntriesByOffsetAsc[i].ID = nil
 	}
@@ -468,6 +576,7 @@ func (r *reader) Close() error {
 	bloomFilterWithDigest := r.bloomFilterWithDigest
 	indexDecoderStream := r.indexDecoderStream
 	dataReader := r.dataReader
+	compressedDataReader := r.compressedDataReader
 	decoder := r.decoder
 	digestBuf := r.digestBuf
 	bytesPool := r.bytesPool
@@ -486,6 +595,7 @@ func (r *reader) Close() error {
 	r.bloomFilterWithDigest = bloomFilterWithDigest
 	r.indexDecoderStream = indexDecoderStream
 	r.dataReader = dataReader
+	r.compressedDataReader = compressedDataReader
 	r.decoder = decoder
 	r.digestBuf = digestBuf
 	r.bytesPool = bytesPool
@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/bloom"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3x/checked"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
@@ -258,6 +259,61 @@ func TestSimpleReadWrite(t *testing.T) {
 	readTestData(t, r, 0, testWriterStart, entries)
 }
 
+func TestSimpleReadWriteCompressed(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	entries := []testEntry{
+		{"foo", nil, []byte{1, 2, 3}},
+		{"bar", nil, []byte{4, 5, 6}},
+		{"baz", nil, bytes.Repeat([]byte{1, 2}, 40000)},
+		{"cat", nil, bytes.Repeat([]byte{3, 4}, 50000)},
+		{"foo+bar=baz,qux=qaz", map[string]string{
+			"bar": "baz",
+			"qux": "qaz",
+		}, []byte{7, 8, 9}},
+	}
+
+	for _, compressionType := range compression.ValidTypes() {
+		w := newTestWriter(t, filePathPrefix)
+		writerOpts := DataWriterOpenOptions{
+			Identifier: FileSetFileIdentifier{
+				Namespace:  testNs1ID,
+				Shard:      0,
+				BlockStart: testWriterStart,
+			},
+			BlockSize:   testBlockSize,
+			Compression: compressionType,
+		}
+		require.NoError(t, w.Open(writerOpts))
+		for i := range entries {
+			require.NoError(t, w.Write(
+				entries[i].ID(),
+				entries[i].Tags(),
+				bytesRefd(entries[i].data),
+				digest.Checksum(entries[i].data)))
+		}
+		require.NoError(t, w.Close())
+
+		r := newTestReader(t, filePathPrefix)
+		readTestData(t, r, 0, testWriterStart, entries)
+
+		// Verify the status reports the compression and that the digest of
+		// the compressed data file validates once all data is read.
+		require.NoError(t, r.Open(DataReaderOpenOptions{
+			Identifier: writerOpts.Identifier,
+		}))
+		require.Equal(t, compressionType, r.Status().Compression)
+		for i := 0; i < r.Entries(); i++ {
+			_, _, _, _, err := r.Read()
+			require.NoError(t, err)
+		}
+		require.NoError(t, r.Validate())
+		require.NoError(t, r.Close())
+	}
+}
+
 func TestCheckpointFileSizeBytesSize(t *testing.T) {
 	// These values need to match so that the logic for determining whether
 	// a checkpoint file is complete or not remains correct.
@@ -160,6 +160,16 @@ func (r *blockRetriever) CacheShardIndices(shards []uint32) error {
 	return r.seekerMgr.CacheShardIndices(shards)
 }
 
+func (r *blockRetriever) InvalidateBlock(shard uint32, blockStart time.Time) error {
+	r.RLock()
+	defer r.RUnlock()
+
+	if r.status != blockRetrieverOpen {
+		return errBlockRetrieverNotOpen
+	}
+	return r.seekerMgr.Invalidate(shard, blockStart)
+}
+
 func (r *blockRetriever) fetchLoop(seekerMgr DataFileSetSeekerManager) {
 	var (
 		inFlight      []*retrieveRequest
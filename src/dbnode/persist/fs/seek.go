@@ -25,15 +25,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/mmap"
 	"github.com/m3db/m3x/checked"
 	xerrors "github.com/m3db/m3x/errors"
 	"github.com/m3db/m3x/ident"
+	xlog "github.com/m3db/m3x/log"
 	"github.com/m3db/m3x/pool"
 	xtime "github.com/m3db/m3x/time"
 )
@@ -69,6 +72,19 @@ type seeker struct {
 	dataMmap  []byte
 	indexMmap []byte
 
+	// The data file of a fileset tiered to the object store is only mmapped
+	// once first read, it is shared with clones.
+	tieredData *tieredDataFile
+
+	// Data compression read from the indexInfo file, the most recently
+	// decompressed frame is kept around as lookups for series close to
+	// each other are likely to hit the same frame.
+	dataCompression compression.Type
+	dataCodec       compression.Codec
+	dataFrames      dataCompressionFrames
+	dataFrameIdx    int
+	dataFrameBuf    []byte
+
 	unreadBuf []byte
 
 	decoder      *msgpack.Decoder
@@ -149,6 +165,7 @@ func newSeeker(opts seekerOpts) fileSetSeeker {
 		decoder:        msgpack.NewDecoder(opts.decodingOpts),
 		decodingOpts:   opts.decodingOpts,
 		opts:           opts,
+		dataFrameIdx:   -1,
 	}
 }
 
@@ -161,14 +178,26 @@ func (s *seeker) Open(namespace ident.ID, shard uint32, blockStart time.Time) er
 		return errClonesShouldNotBeOpened
 	}
 
-	shardDir := ShardDataDirPath(s.filePathPrefix, namespace, shard)
+	var (
+		shardDir     = ShardDataDirPath(s.filePathPrefix, namespace, shard)
+		dataFilePath = filesetPathFromTime(shardDir, blockStart, dataFileSuffix)
+		openFn       = tieredFileOpener(s.opts.opts, s.filePathPrefix)
+	)
+
+	// NB: The data file of a fileset tiered to the object store is only
+	// downloaded once first read, the smaller files needed to look up series
+	// are downloaded now if they are not already cached.
+	tiered, err := isTieredDataFileSet(s.opts.opts, dataFilePath)
+	if err != nil {
+		return err
+	}
+
 	var infoFd, indexFd, dataFd, digestFd, bloomFilterFd, summariesFd *os.File
 
 	// Open necessary files
-	if err := openFiles(os.Open, map[string]**os.File{
+	if err := openFiles(openFn, map[string]**os.File{
 		filesetPathFromTime(shardDir, blockStart, infoFileSuffix):        &infoFd,
 		filesetPathFromTime(shardDir, blockStart, indexFileSuffix):       &indexFd,
-		filesetPathFromTime(shardDir, blockStart, dataFileSuffix):        &dataFd,
 		filesetPathFromTime(shardDir, blockStart, digestFileSuffix):      &digestFd,
 		filesetPathFromTime(shardDir, blockStart, bloomFilterFileSuffix): &bloomFilterFd,
 		filesetPathFromTime(shardDir, blockStart, summariesFileSuffix):   &summariesFd,
@@ -191,7 +220,9 @@ func (s *seeker) Open(namespace ident.ID, shard uint32, blockStart time.Time) er
 		bloomFilterFdWithDigest.Close()
 		summariesFdWithDigest.Close()
 		digestFdWithDigestContents.Close()
-		dataFd.Close()
+		if dataFd != nil {
+			dataFd.Close()
+		}
 	}()
 
 	infoFdWithDigest.Reset(infoFd)
@@ -207,18 +238,28 @@ func (s *seeker) Open(namespace ident.ID, shard uint32, blockStart time.Time) er
 			Threshold: s.opts.opts.MmapHugeTLBThreshold(),
 		},
 	}
-	mmapResult, err := mmap.Files(os.Open, map[string]mmap.FileDesc{
+	mmapFiles := map[string]mmap.FileDesc{
 		filesetPathFromTime(shardDir, blockStart, indexFileSuffix): mmap.FileDesc{
 			File:    &indexFd,
 			Bytes:   &s.indexMmap,
 			Options: mmapOptions,
 		},
-		filesetPathFromTime(shardDir, blockStart, dataFileSuffix): mmap.FileDesc{
+	}
+	if tiered {
+		s.tieredData = &tieredDataFile{
+			filePath: dataFilePath,
+			pinFn:    tieredFilePinner(s.opts.opts, s.filePathPrefix),
+			opts:     mmapOptions,
+			logger:   s.opts.opts.InstrumentOptions().Logger(),
+		}
+	} else {
+		mmapFiles[dataFilePath] = mmap.FileDesc{
 			File:    &dataFd,
 			Bytes:   &s.dataMmap,
 			Options: mmapOptions,
-		},
-	})
+		}
+	}
+	mmapResult, err := mmap.Files(mmap.FileOpener(openFn), mmapFiles)
 	if err != nil {
 		s.Close()
 		return err
@@ -321,6 +362,15 @@ func (s *seeker) readInfo(size int, infoDigestReader digest.FdWithDigestReader,
 	s.entries = int(info.Entries)
 	s.bloomFilterInfo = info.BloomFilter
 	s.summariesInfo = info.Summaries
+	s.dataCompression = compression.Type(info.DataCompression.Type)
+	s.dataFrames = dataCompressionFrames(info.DataCompression.Frames)
+	s.dataFrameIdx = -1
+	if s.dataCompression != compression.None {
+		s.dataCodec, err = compression.NewCodec(s.dataCompression)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -340,19 +390,9 @@ func (s *seeker) SeekByID(id ident.ID) (checked.Bytes, error) {
 // instead of looking it up on its own. Useful in cases where you've already
 // obtained an entry and don't want to waste resources looking it up again.
 func (s *seeker) SeekByIndexEntry(entry IndexEntry) (checked.Bytes, error) {
-	// Should never happen, but prevent panics if somehow we're provided an index entry
-	// with a negative or too large offset
-	if int(entry.Offset) > len(s.dataMmap)-1 {
-		return nil, errInvalidDataFileOffset
-	}
-
-	// We'll treat "data" similar to a reader interface, I.E after every read we'll
-	// reslice it such that the first byte is the next byte we want to read.
-	data := s.dataMmap[entry.Offset:]
-
-	// Should never happen, but prevents panics in the case of malformed data
-	if len(data) < int(entry.Size) {
-		return nil, errNotEnoughBytes
+	data, err := s.dataAt(entry.Offset, entry.Size)
+	if err != nil {
+		return nil, err
 	}
 
 	// Obtain an appropriately sized buffer
@@ -370,7 +410,7 @@ func (s *seeker) SeekByIndexEntry(entry IndexEntry) (checked.Bytes, error) {
 
 	// Copy the actual data into the underlying buffer
 	underlyingBuf := buffer.Bytes()
-	copy(underlyingBuf, data[:entry.Size])
+	copy(underlyingBuf, data)
 
 	// NB(r): _must_ check the checksum against known checksum as the data
 	// file might not have been verified if we haven't read through the file yet.
@@ -381,6 +421,71 @@ func (s *seeker) SeekByIndexEntry(entry IndexEntry) (checked.Bytes, error) {
 	return buffer, nil
 }
 
+// dataAt returns the uncompressed data of the data file at the offset, the
+// returned slice is only valid until the next call to dataAt.
+func (s *seeker) dataAt(offset int64, size uint32) ([]byte, error) {
+	if s.dataMmap == nil && s.tieredData != nil {
+		data, err := s.tieredData.mmap()
+		if err != nil {
+			return nil, err
+		}
+		s.dataMmap = data
+	}
+
+	if s.dataCompression != compression.None {
+		return s.compressedDataAt(offset, size)
+	}
+
+	// Should never happen, but prevent panics if somehow we're provided an index entry
+	// with a negative or too large offset
+	if int(offset) > len(s.dataMmap)-1 {
+		return nil, errInvalidDataFileOffset
+	}
+
+	// We'll treat "data" similar to a reader interface, I.E after every read we'll
+	// reslice it such that the first byte is the next byte we want to read.
+	data := s.dataMmap[offset:]
+
+	// Should never happen, but prevents panics in the case of malformed data
+	if len(data) < int(size) {
+		return nil, errNotEnoughBytes
+	}
+
+	return data[:size], nil
+}
+
+func (s *seeker) compressedDataAt(offset int64, size uint32) ([]byte, error) {
+	if offset < 0 {
+		return nil, errInvalidDataFileOffset
+	}
+
+	idx, err := s.dataFrames.frameFor(offset, int64(size))
+	if err != nil {
+		return nil, err
+	}
+
+	frame := s.dataFrames[idx]
+	if idx != s.dataFrameIdx {
+		// Should never happen, but prevents panics in the case of malformed data
+		end := frame.CompressedOffset + frame.CompressedSize
+		if frame.CompressedOffset < 0 || end > int64(len(s.dataMmap)) {
+			return nil, errNotEnoughBytes
+		}
+
+		// Invalidate the cached frame before decoding into its buffer.
+		s.dataFrameIdx = -1
+		compressed := s.dataMmap[frame.CompressedOffset:end]
+		s.dataFrameBuf, err = decodeFrame(s.dataCodec, s.dataFrameBuf[:0], frame, compressed)
+		if err != nil {
+			return nil, err
+		}
+		s.dataFrameIdx = idx
+	}
+
+	start := offset - frame.Offset
+	return s.dataFrameBuf[start : start+int64(size)], nil
+}
+
 func (s *seeker) SeekIndexEntry(id ident.ID) (IndexEntry, error) {
 	offset, err := s.indexLookup.getNearestIndexFileOffset(id)
 	// Should never happen, either something is really wrong with the code or
@@ -450,6 +555,11 @@ func (s *seeker) Close() error {
 		multiErr = multiErr.Add(mmap.Munmap(s.indexMmap))
 		s.indexMmap = nil
 	}
+	if s.tieredData != nil {
+		multiErr = multiErr.Add(s.tieredData.close())
+		s.tieredData = nil
+		s.dataMmap = nil
+	}
 	if s.dataMmap != nil {
 		multiErr = multiErr.Add(mmap.Munmap(s.dataMmap))
 		s.dataMmap = nil
@@ -471,11 +581,87 @@ func (s *seeker) ConcurrentClone() (ConcurrentDataFileSetSeeker, error) {
 		decoder:   msgpack.NewDecoder(s.decodingOpts),
 		opts:      s.opts,
 		// Mmaps are read-only so they're concurrency safe
-		dataMmap:  s.dataMmap,
-		indexMmap: s.indexMmap,
+		dataMmap:   s.dataMmap,
+		indexMmap:  s.indexMmap,
+		tieredData: s.tieredData,
+		// Codecs are concurrency safe and frames are read-only, however
+		// each clone needs its own frame buffer
+		dataCompression: s.dataCompression,
+		dataCodec:       s.dataCodec,
+		dataFrames:      s.dataFrames,
+		dataFrameIdx:    -1,
 		// bloomFilter is concurrency safe
 		bloomFilter: s.bloomFilter,
 		indexLookup: indexLookupClone,
 		isClone:     true,
 	}, nil
 }
+
+// tieredDataFile is the data file of a fileset tiered to the object store
+// which is downloaded, if not already cached, and mmapped the first time
+// it is read by a seeker or any of its clones. The cached file is pinned
+// while it is mmapped so that it is not evicted from beneath the mapping.
+type tieredDataFile struct {
+	sync.Mutex
+
+	filePath string
+	pinFn    filePinner
+	opts     mmap.Options
+	logger   xlog.Logger
+	bytes    []byte
+	unpin    func()
+}
+
+func (f *tieredDataFile) mmap() ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.bytes != nil {
+		return f.bytes, nil
+	}
+
+	var (
+		fd    *os.File
+		unpin func()
+	)
+	openFn := func(filePath string) (*os.File, error) {
+		pinned, pinnedUnpin, err := f.pinFn(filePath)
+		unpin = pinnedUnpin
+		return pinned, err
+	}
+	result, err := mmap.Files(openFn, map[string]mmap.FileDesc{
+		f.filePath: mmap.FileDesc{
+			File:    &fd,
+			Bytes:   &f.bytes,
+			Options: f.opts,
+		},
+	})
+	if err != nil {
+		if unpin != nil {
+			unpin()
+		}
+		return nil, err
+	}
+	// The mmap remains valid once the file is closed.
+	fd.Close()
+	f.unpin = unpin
+
+	if warning := result.Warning; warning != nil {
+		f.logger.Warnf("warning while mmaping tiered data file in seeker: %s",
+			warning.Error())
+	}
+	return f.bytes, nil
+}
+
+func (f *tieredDataFile) close() error {
+	f.Lock()
+	defer f.Unlock()
+
+	err := mmap.Munmap(f.bytes)
+	f.bytes = nil
+	if f.unpin != nil {
+		f.unpin()
+		f.unpin = nil
+	}
+	return err
+}
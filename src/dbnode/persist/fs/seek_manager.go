@@ -107,6 +107,9 @@ type seekersByTime struct {
 	shard    uint32
 	accessed bool
 	seekers  map[xtime.UnixNano]seekersAndBloom
+	// invalidated holds the seekers of replaced filesets that are waiting to
+	// be returned before they are closed.
+	invalidated []seekersAndBloom
 }
 
 type seekerManagerPendingClose struct {
@@ -234,6 +237,17 @@ func (m *seekerManager) Return(shard uint32, start time.Time, seeker ConcurrentD
 
 	startNano := xtime.ToUnixNano(start)
 	seekersAndBloom, ok := byTime.seekers[startNano]
+	if ok && returnSeeker(seekersAndBloom, seeker) {
+		return nil
+	}
+
+	// The seeker may have been borrowed before the fileset was invalidated.
+	for _, invalidated := range byTime.invalidated {
+		if returnSeeker(invalidated, seeker) {
+			return nil
+		}
+	}
+
 	// Should never happen - This either means that the caller (DataBlockRetriever) is trying to return seekers
 	// that it never requested, OR its trying to return seekers after the openCloseLoop has already
 	// determined that they were all no longer in use and safe to close. Either way it indicates there is
@@ -242,22 +256,47 @@ func (m *seekerManager) Return(shard uint32, start time.Time, seeker ConcurrentD
 		return errSeekersDontExist
 	}
 
-	found := false
+	// Should never happen with a well behaved caller. Either they are trying to return a seeker
+	// that we're not managing, or they provided the wrong shard/start.
+	return errReturnedUnmanagedSeeker
+}
+
+func returnSeeker(seekersAndBloom seekersAndBloom, seeker ConcurrentDataFileSetSeeker) bool {
 	for i, compareSeeker := range seekersAndBloom.seekers {
 		if seeker == compareSeeker.seeker {
-			found = true
 			compareSeeker.isBorrowed = false
 			seekersAndBloom.seekers[i] = compareSeeker
-			break
+			return true
 		}
 	}
-	// Should never happen with a well behaved caller. Either they are trying to return a seeker
-	// that we're not managing, or they provided the wrong shard/start.
-	if !found {
-		return errReturnedUnmanagedSeeker
-	}
+	return false
+}
 
-	return nil
+func (m *seekerManager) Invalidate(shard uint32, start time.Time) error {
+	byTime := m.seekersByTime(shard)
+
+	byTime.Lock()
+	defer byTime.Unlock()
+
+	startNano := xtime.ToUnixNano(start)
+	for {
+		seekersAndBloom, ok := byTime.seekers[startNano]
+		if !ok {
+			return nil
+		}
+		if seekersAndBloom.wg != nil {
+			// Seekers are being opened and may have read the replaced
+			// fileset, wait for that to complete and invalidate them.
+			byTime.Unlock()
+			seekersAndBloom.wg.Wait()
+			byTime.Lock()
+			continue
+		}
+
+		delete(byTime.seekers, startNano)
+		byTime.invalidated = append(byTime.invalidated, seekersAndBloom)
+		return nil
+	}
 }
 
 // getOrOpenSeekersWithLock checks if the seekers are already open / initialized. If they are, then it
@@ -451,12 +490,17 @@ func (m *seekerManager) Close() error {
 	for _, byTime := range m.seekersByShardIdx {
 		byTime.Lock()
 		for _, seekersByTime := range byTime.seekers {
-			for _, seeker := range seekersByTime.seekers {
-				if seeker.isBorrowed {
-					byTime.Unlock()
-					m.Unlock()
-					return errCantCloseSeekerManagerWhileSeekersAreBorrowed
-				}
+			if !allSeekersAreReturned(seekersByTime) {
+				byTime.Unlock()
+				m.Unlock()
+				return errCantCloseSeekerManagerWhileSeekersAreBorrowed
+			}
+		}
+		for _, seekersByTime := range byTime.invalidated {
+			if !allSeekersAreReturned(seekersByTime) {
+				byTime.Unlock()
+				m.Unlock()
+				return errCantCloseSeekerManagerWhileSeekersAreBorrowed
 			}
 		}
 		byTime.Unlock()
@@ -554,23 +598,34 @@ func (m *seekerManager) openCloseLoop() {
 				blockStartNano := xtime.ToUnixNano(elem.blockStart)
 				byTime.Lock()
 				seekersAndBloom := byTime.seekers[blockStartNano]
-				allSeekersAreReturned := true
-				for _, seeker := range seekersAndBloom.seekers {
-					if seeker.isBorrowed {
-						allSeekersAreReturned = false
-						break
-					}
-				}
 				// Never close seekers unless they've all been returned because
 				// some of them are clones of the original and can't be used once
 				// the parent is closed (because they share underlying resources)
-				if allSeekersAreReturned {
+				if allSeekersAreReturned(seekersAndBloom) {
 					closing = append(closing, seekersAndBloom.seekers...)
 					delete(byTime.seekers, blockStartNano)
 				}
 				byTime.Unlock()
 			}
 		}
+
+		// Close the seekers of replaced filesets once they've all been returned
+		for _, byTime := range m.seekersByShardIdx {
+			byTime.Lock()
+			remaining := byTime.invalidated[:0]
+			for _, seekersAndBloom := range byTime.invalidated {
+				if allSeekersAreReturned(seekersAndBloom) {
+					closing = append(closing, seekersAndBloom.seekers...)
+					continue
+				}
+				remaining = append(remaining, seekersAndBloom)
+			}
+			for i := len(remaining); i < len(byTime.invalidated); i++ {
+				byTime.invalidated[i] = seekersAndBloom{}
+			}
+			byTime.invalidated = remaining
+			byTime.Unlock()
+		}
 		m.RUnlock()
 
 		// Close after releasing lock so any IO is done out of lock
@@ -593,18 +648,13 @@ func (m *seekerManager) openCloseLoop() {
 	for _, byTime := range m.seekersByShardIdx {
 		byTime.Lock()
 		for _, seekersByTime := range byTime.seekers {
-			for _, seeker := range seekersByTime.seekers {
-				// We don't need to check if the seeker is borrowed here because we don't allow the
-				// SeekerManager to be closed if any seekers are still outstanding.
-				err := seeker.seeker.Close()
-				if err != nil {
-					m.logger.
-						WithFields(log.NewField("err", err.Error())).
-						Error("err closing seeker in SeekerManager at end of openCloseLoop")
-				}
-			}
+			m.closeSeekersAtEndOfOpenCloseLoop(seekersByTime)
+		}
+		for _, seekersByTime := range byTime.invalidated {
+			m.closeSeekersAtEndOfOpenCloseLoop(seekersByTime)
 		}
 		byTime.seekers = nil
+		byTime.invalidated = nil
 		byTime.Unlock()
 	}
 	m.seekersByShardIdx = nil
@@ -612,3 +662,25 @@ func (m *seekerManager) openCloseLoop() {
 
 	m.openCloseLoopDoneCh <- struct{}{}
 }
+
+func (m *seekerManager) closeSeekersAtEndOfOpenCloseLoop(seekersAndBloom seekersAndBloom) {
+	for _, seeker := range seekersAndBloom.seekers {
+		// We don't need to check if the seeker is borrowed here because we don't allow the
+		// SeekerManager to be closed if any seekers are still outstanding.
+		err := seeker.seeker.Close()
+		if err != nil {
+			m.logger.
+				WithFields(log.NewField("err", err.Error())).
+				Error("err closing seeker in SeekerManager at end of openCloseLoop")
+		}
+	}
+}
+
+func allSeekersAreReturned(seekersAndBloom seekersAndBloom) bool {
+	for _, seeker := range seekersAndBloom.seekers {
+		if seeker.isBorrowed {
+			return false
+		}
+	}
+	return true
+}
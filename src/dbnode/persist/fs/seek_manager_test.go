@@ -109,6 +109,71 @@ func TestSeekerManagerBorrowOpenSeekersLazy(t *testing.T) {
 	require.NoError(t, m.Close())
 }
 
+// TestSeekerManagerInvalidate tests that invalidated seekers are reopened the
+// next time they're borrowed and closed once they've all been returned.
+func TestSeekerManagerInvalidate(t *testing.T) {
+	defer leaktest.CheckTimeout(t, 1*time.Minute)()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		shard            = uint32(2)
+		fetchConcurrency = NewBlockRetrieverOptions().FetchConcurrency()
+		opened           = 0
+	)
+	m := NewSeekerManager(nil, testDefaultOpts, fetchConcurrency).(*seekerManager)
+	m.openAnyUnopenSeekersFn = func(byTime *seekersByTime) error {
+		return nil
+	}
+	m.newOpenSeekerFn = func(
+		shard uint32,
+		blockStart time.Time,
+	) (DataFileSetSeeker, error) {
+		opened++
+		mock := NewMockDataFileSetSeeker(ctrl)
+		mock.EXPECT().ConcurrentClone().Return(mock, nil).Times(fetchConcurrency - 1)
+		mock.EXPECT().ConcurrentIDBloomFilter().Return(nil)
+		mock.EXPECT().Close().Return(nil).Times(fetchConcurrency)
+		return mock, nil
+	}
+	m.sleepFn = func(_ time.Duration) {
+		time.Sleep(time.Millisecond)
+	}
+
+	metadata := testNs1Metadata(t)
+	require.NoError(t, m.Open(metadata))
+
+	seeker, err := m.Borrow(shard, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 1, opened)
+
+	require.NoError(t, m.Invalidate(shard, time.Time{}))
+
+	// Borrowing again opens the replaced fileset.
+	reopened, err := m.Borrow(shard, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 2, opened)
+	require.False(t, seeker == reopened)
+
+	// Seekers borrowed before invalidating can still be returned and are
+	// closed once returned.
+	require.NoError(t, m.Return(shard, time.Time{}, seeker))
+	byTime := m.seekersByTime(shard)
+	for {
+		byTime.RLock()
+		numInvalidated := len(byTime.invalidated)
+		byTime.RUnlock()
+		if numInvalidated == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	require.NoError(t, m.Return(shard, time.Time{}, reopened))
+	require.NoError(t, m.Close())
+}
+
 // TestSeekerManagerOpenCloseLoop tests the openCloseLoop of the SeekerManager
 // by making sure that it makes the right decisions with regards to cleaning
 // up resources based on their state.
@@ -21,6 +21,8 @@
 package fs
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -28,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
 
@@ -229,6 +232,69 @@ func TestSeek(t *testing.T) {
 
 // TestSeekIDNotExists is similar to TestSeek, but it covers more edge cases
 // around IDs not existing.
+// TestSeekCompressed tests that we can seek IDs spread across multiple
+// compressed frames in any order, using both the seeker and its clone.
+func TestSeekCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	var (
+		ids  []ident.ID
+		data [][]byte
+	)
+	for i := 0; i < 16; i++ {
+		ids = append(ids, ident.StringID(fmt.Sprintf("foo%d", i)))
+		// Large enough that series span several frames.
+		data = append(data, bytes.Repeat([]byte{byte(i), 1, 2}, 8192))
+	}
+
+	for _, compressionType := range compression.ValidTypes() {
+		w := newTestWriter(t, filePathPrefix)
+		writerOpts := DataWriterOpenOptions{
+			BlockSize: testBlockSize,
+			Identifier: FileSetFileIdentifier{
+				Namespace:  testNs1ID,
+				Shard:      0,
+				BlockStart: testWriterStart,
+			},
+			Compression: compressionType,
+		}
+		require.NoError(t, w.Open(writerOpts))
+		for i := range ids {
+			require.NoError(t, w.Write(ids[i], ident.Tags{},
+				bytesRefd(data[i]), digest.Checksum(data[i])))
+		}
+		require.NoError(t, w.Close())
+
+		s := newTestSeeker(filePathPrefix)
+		require.NoError(t, s.Open(testNs1ID, 0, testWriterStart))
+
+		clone, err := s.ConcurrentClone()
+		require.NoError(t, err)
+
+		for _, seeker := range []ConcurrentDataFileSetSeeker{s, clone} {
+			for _, i := range []int{15, 0, 7, 8, 3, 12} {
+				result, err := seeker.SeekByID(ids[i])
+				require.NoError(t, err)
+
+				result.IncRef()
+				require.Equal(t, data[i], result.Bytes(), compressionType.String())
+				result.DecRef()
+			}
+
+			_, err = seeker.SeekByID(ident.StringID("foo"))
+			require.Equal(t, errSeekIDNotFound, err)
+		}
+
+		require.NoError(t, clone.Close())
+		require.NoError(t, s.Close())
+	}
+}
+
 func TestSeekIDNotExists(t *testing.T) {
 	dir, err := ioutil.TempDir("", "testdb")
 	if err != nil {
@@ -0,0 +1,341 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"container/list"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errTieredFileCacheMaxBytesNotPositive = errors.New(
+	"tiered file cache max bytes must be positive")
+
+// TieredFileCacheOptions is the options for a tiered file cache.
+type TieredFileCacheOptions struct {
+	// Directory is the directory that cached files are stored beneath.
+	Directory string
+
+	// MaxBytes is the size of the cached files above which the least
+	// recently opened files are evicted.
+	MaxBytes int64
+
+	// NewFileMode is the file mode of cached files.
+	NewFileMode os.FileMode
+
+	// NewDirectoryMode is the file mode of directories created in the cache.
+	NewDirectoryMode os.FileMode
+}
+
+type tieredFileCache struct {
+	sync.Mutex
+
+	store ObjectStore
+	opts  TieredFileCacheOptions
+
+	// files holds the list elements of the cached files by key, the
+	// list is ordered from most to least recently opened.
+	files map[string]*list.Element
+	lru   *list.List
+	size  int64
+}
+
+type tieredFileCacheEntry struct {
+	key  string
+	size int64
+
+	// pins is the number of callers that have the file mapped, a pinned file
+	// is never evicted and its size is accounted for until it is unpinned.
+	pins int
+	// detached is set once a pinned file is no longer tracked by key, such as
+	// once it is removed or replaced by a download of the same key.
+	detached bool
+	// removedPath is the path a removed pinned file was moved to, it is
+	// deleted once the file is unpinned.
+	removedPath string
+}
+
+// NewTieredFileCache returns a new tiered file cache that downloads files
+// from the object store, files already in the cache directory are kept, in
+// order of their modification time, so the cache is reused across restarts.
+func NewTieredFileCache(
+	store ObjectStore,
+	opts TieredFileCacheOptions,
+) (TieredFileCache, error) {
+	if store == nil {
+		return nil, errObjectStoreNotSet
+	}
+	if opts.MaxBytes <= 0 {
+		return nil, errTieredFileCacheMaxBytesNotPositive
+	}
+	if err := os.MkdirAll(opts.Directory, opts.NewDirectoryMode); err != nil {
+		return nil, err
+	}
+
+	c := &tieredFileCache{
+		store: store,
+		opts:  opts,
+		files: make(map[string]*list.Element),
+		lru:   list.New(),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *tieredFileCache) load() error {
+	type cachedFile struct {
+		entry   *tieredFileCacheEntry
+		modTime time.Time
+	}
+	var files []cachedFile
+	err := filepath.Walk(c.opts.Directory, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), tieredDownloadFilePrefix) {
+			// Left behind by a download that did not complete or by a
+			// removed file that was still pinned
+			return os.Remove(filePath)
+		}
+		key, err := objectStoreKey(c.opts.Directory, filePath)
+		if err != nil {
+			return err
+		}
+		files = append(files, cachedFile{
+			entry:   &tieredFileCacheEntry{key: key, size: info.Size()},
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	for _, f := range files {
+		c.files[f.entry.key] = c.lru.PushBack(f.entry)
+		c.size += f.entry.size
+	}
+	c.evictWithLock()
+	return nil
+}
+
+func (c *tieredFileCache) Open(key string) (*os.File, error) {
+	fd, _, err := c.open(key, false)
+	return fd, err
+}
+
+func (c *tieredFileCache) Pin(key string) (*os.File, func(), error) {
+	fd, entry, err := c.open(key, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	return fd, func() {
+		once.Do(func() { c.unpin(entry) })
+	}, nil
+}
+
+func (c *tieredFileCache) open(key string, pin bool) (*os.File, *tieredFileCacheEntry, error) {
+	filePath := c.filePath(key)
+
+	c.Lock()
+	if elem, ok := c.files[key]; ok {
+		fd, err := os.Open(filePath)
+		if err == nil {
+			entry := elem.Value.(*tieredFileCacheEntry)
+			if pin {
+				entry.pins++
+			}
+			c.lru.MoveToFront(elem)
+			c.Unlock()
+			return fd, entry, nil
+		}
+		if !os.IsNotExist(err) {
+			c.Unlock()
+			return nil, nil, err
+		}
+		// Removed from beneath the cache, download it again.
+		c.detachWithLock(elem)
+	}
+	c.Unlock()
+
+	// NB: Download without holding the lock so that downloads of different
+	// files are not serialized, the downloaded file is opened before it is
+	// moved into place so that it cannot be evicted before it is opened.
+	fd, err := c.download(key, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return nil, nil, err
+	}
+
+	entry := &tieredFileCacheEntry{key: key, size: stat.Size()}
+	if pin {
+		entry.pins++
+	}
+
+	c.Lock()
+	if elem, ok := c.files[key]; ok {
+		// Downloaded concurrently by another caller, the file it downloaded
+		// has been replaced by this download.
+		c.detachWithLock(elem)
+	}
+	c.files[key] = c.lru.PushFront(entry)
+	c.size += entry.size
+	c.evictWithLock()
+	c.Unlock()
+
+	return fd, entry, nil
+}
+
+func (c *tieredFileCache) unpin(entry *tieredFileCacheEntry) {
+	c.Lock()
+	defer c.Unlock()
+
+	entry.pins--
+	if entry.pins > 0 {
+		return
+	}
+	if !entry.detached {
+		// The file may have been kept beyond the cache's max size.
+		c.evictWithLock()
+		return
+	}
+
+	c.size -= entry.size
+	if entry.removedPath != "" {
+		// NB: Files that fail to be removed are only accounted for again
+		// once reloaded on the next restart.
+		os.Remove(entry.removedPath)
+	}
+}
+
+func (c *tieredFileCache) download(key, filePath string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), c.opts.NewDirectoryMode); err != nil {
+		return nil, err
+	}
+
+	r, err := c.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return createFileAtomically(filePath, r, c.opts.NewFileMode)
+}
+
+func (c *tieredFileCache) Remove(key string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	filePath := c.filePath(key)
+	if elem, ok := c.files[key]; ok {
+		entry := elem.Value.(*tieredFileCacheEntry)
+		c.detachWithLock(elem)
+		if entry.pins > 0 {
+			// NB: The file is still mapped, move it aside so that the key can
+			// be downloaded again and delete it once it is unpinned.
+			removedPath, err := c.moveAside(filePath)
+			if err != nil {
+				return err
+			}
+			entry.removedPath = removedPath
+			return nil
+		}
+	}
+	err := os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// moveAside moves the file to a unique path in the same directory which is
+// cleaned up when the cache is next loaded if it is never deleted.
+func (c *tieredFileCache) moveAside(filePath string) (string, error) {
+	fd, err := ioutil.TempFile(filepath.Dir(filePath), tieredDownloadFilePrefix)
+	if err != nil {
+		return "", err
+	}
+	removedPath := fd.Name()
+	fd.Close()
+
+	if err := os.Rename(filePath, removedPath); err != nil {
+		os.Remove(removedPath)
+		return "", err
+	}
+	return removedPath, nil
+}
+
+// evictWithLock removes the least recently opened files until the cache is
+// within its max size, the most recently opened file and pinned files are
+// always kept.
+func (c *tieredFileCache) evictWithLock() {
+	var prev *list.Element
+	for elem := c.lru.Back(); elem != nil && elem != c.lru.Front() &&
+		c.size > c.opts.MaxBytes; elem = prev {
+		prev = elem.Prev()
+		entry := elem.Value.(*tieredFileCacheEntry)
+		if entry.pins > 0 {
+			continue
+		}
+		c.detachWithLock(elem)
+		// NB: Files that fail to be removed are no longer tracked and are
+		// only accounted for again once reloaded on the next restart.
+		os.Remove(c.filePath(entry.key))
+	}
+}
+
+// detachWithLock stops tracking the file by key, the size of a pinned file is
+// accounted for until it is unpinned.
+func (c *tieredFileCache) detachWithLock(elem *list.Element) {
+	entry := elem.Value.(*tieredFileCacheEntry)
+	delete(c.files, entry.key)
+	c.lru.Remove(elem)
+	if entry.pins > 0 {
+		entry.detached = true
+		return
+	}
+	c.size -= entry.size
+}
+
+func (c *tieredFileCache) filePath(key string) string {
+	return filepath.Join(c.opts.Directory, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+)
+
+const tieredDownloadFilePrefix = "tiered-download-"
+
+var (
+	// ErrObjectNotFound is returned by an object store when there is no
+	// object with the requested key.
+	ErrObjectNotFound = errors.New("object not found")
+
+	errObjectStoreNotSet = errors.New("object store is not set")
+)
+
+// tieredDataFileSetSuffixes are the suffixes of the files of a data fileset
+// that are moved to the object store when the fileset is tiered. The info,
+// digest and checkpoint files are small and always kept on local disk so that
+// tiered volumes are still discovered, and validated, from local disk alone.
+var tieredDataFileSetSuffixes = []string{
+	indexFileSuffix,
+	summariesFileSuffix,
+	bloomFilterFileSuffix,
+	dataFileSuffix,
+}
+
+// HasUntieredFiles returns whether any of the files of a flushed data fileset
+// that are tiered to the object store are on local disk.
+func (f FileSetFile) HasUntieredFiles() bool {
+	for _, filePath := range f.AbsoluteFilepaths {
+		for _, suffix := range tieredDataFileSetSuffixes {
+			if strings.HasSuffix(filePath, separator+suffix+fileSuffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TierDataFileSet uploads the files of a complete flushed data fileset to
+// the object store and then removes the local copies. Files that have already
+// been tiered are skipped so tiering a fileset again is a no-op. Tiered files
+// are read through the tiered file cache rather than downloaded back into the
+// data directory so they are never tiered again.
+func TierDataFileSet(
+	opts Options,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+) error {
+	store := opts.ObjectStore()
+	if store == nil {
+		return errObjectStoreNotSet
+	}
+
+	var (
+		filePathPrefix = opts.FilePathPrefix()
+		shardDir       = ShardDataDirPath(filePathPrefix, namespace, shard)
+		checkpointPath = filesetPathFromTime(shardDir, blockStart, checkpointFileSuffix)
+	)
+	complete, err := CompleteCheckpointFileExists(checkpointPath)
+	if err != nil {
+		return err
+	}
+	if !complete {
+		return fmt.Errorf("fileset for blockStart: %d is not complete", blockStart.Unix())
+	}
+
+	uploaded := make([]string, 0, len(tieredDataFileSetSuffixes))
+	for _, suffix := range tieredDataFileSetSuffixes {
+		filePath := filesetPathFromTime(shardDir, blockStart, suffix)
+		exists, err := FileExists(filePath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// Already tiered
+			continue
+		}
+
+		key, err := objectStoreKey(filePathPrefix, filePath)
+		if err != nil {
+			return err
+		}
+		if err := uploadFile(store, key, filePath); err != nil {
+			return err
+		}
+		if cache := opts.TieredFileCache(); cache != nil {
+			// Drop any cached copy of a fileset previously tiered with the
+			// same block start, such as one replaced by a rollup.
+			if err := cache.Remove(key); err != nil {
+				return err
+			}
+		}
+		uploaded = append(uploaded, filePath)
+	}
+
+	// NB: Local copies are only removed once every file has been uploaded so
+	// that failing part way through leaves a readable fileset on local disk.
+	return DeleteFiles(uploaded)
+}
+
+// DeleteTieredDataFiles deletes from the object store, and the tiered file
+// cache, the tiered files of the flushed data filesets that the given local
+// fileset files belong to, each fileset is identified by its info file as it
+// is never tiered.
+func DeleteTieredDataFiles(opts Options, filePaths []string) error {
+	store := opts.ObjectStore()
+	if store == nil {
+		return nil
+	}
+
+	var (
+		cache          = opts.TieredFileCache()
+		filePathPrefix = opts.FilePathPrefix()
+		infoSuffix     = separator + infoFileSuffix + fileSuffix
+		multiErr       = xerrors.NewMultiError()
+	)
+	for _, filePath := range filePaths {
+		if !strings.HasSuffix(filePath, infoSuffix) {
+			continue
+		}
+
+		filePathWithoutSuffix := strings.TrimSuffix(filePath, infoFileSuffix+fileSuffix)
+		for _, suffix := range tieredDataFileSetSuffixes {
+			key, err := objectStoreKey(filePathPrefix, filePathWithoutSuffix+suffix+fileSuffix)
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+			if err := store.Delete(key); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+			if cache == nil {
+				continue
+			}
+			if err := cache.Remove(key); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+		}
+	}
+	return multiErr.FinalError()
+}
+
+// TieredDataFileSetFilePaths returns the paths of the files of a flushed data
+// fileset that are moved to the object store when the fileset is tiered,
+// whether or not the fileset has been tiered.
+func TieredDataFileSetFilePaths(
+	filePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+) []string {
+	var (
+		shardDir  = ShardDataDirPath(filePathPrefix, namespace, shard)
+		filePaths = make([]string, 0, len(tieredDataFileSetSuffixes))
+	)
+	for _, suffix := range tieredDataFileSetSuffixes {
+		filePaths = append(filePaths, filesetPathFromTime(shardDir, blockStart, suffix))
+	}
+	return filePaths
+}
+
+// OpenDataFileSetFile opens a file of a flushed data fileset from local disk,
+// falling back to reading the object it has been tiered to directly from the
+// object store so that it is neither downloaded to local disk nor added to
+// the tiered file cache. A not exist error is returned if the file is neither
+// on local disk nor in the object store.
+func OpenDataFileSetFile(opts Options, filePath string) (io.ReadCloser, error) {
+	fd, err := os.Open(filePath)
+	store := opts.ObjectStore()
+	if err == nil || !os.IsNotExist(err) || store == nil {
+		return fd, err
+	}
+
+	key, keyErr := objectStoreKey(opts.FilePathPrefix(), filePath)
+	if keyErr != nil {
+		return nil, keyErr
+	}
+	r, storeErr := store.Get(key)
+	if storeErr == ErrObjectNotFound {
+		// Neither on local disk nor tiered, report the local file missing.
+		return nil, err
+	}
+	return r, storeErr
+}
+
+// isTieredDataFileSet returns whether the data file of a flushed data fileset
+// has been tiered to the object store and can be read through the tiered
+// file cache.
+func isTieredDataFileSet(opts Options, dataFilePath string) (bool, error) {
+	if opts.TieredFileCache() == nil {
+		return false, nil
+	}
+	exists, err := FileExists(dataFilePath)
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
+
+// tieredFileOpener returns a file opener for the files of flushed data
+// filesets that falls back to opening files missing from local disk through
+// the tiered file cache, in case they have been tiered to the object store.
+func tieredFileOpener(opts Options, filePathPrefix string) fileOpener {
+	cache := opts.TieredFileCache()
+	if cache == nil {
+		return os.Open
+	}
+	return func(filePath string) (*os.File, error) {
+		fd, err := os.Open(filePath)
+		if err == nil || !os.IsNotExist(err) {
+			return fd, err
+		}
+
+		key, keyErr := objectStoreKey(filePathPrefix, filePath)
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		fd, cacheErr := cache.Open(key)
+		if cacheErr == ErrObjectNotFound {
+			// Neither on local disk nor tiered, report the local file missing.
+			return nil, err
+		}
+		return fd, cacheErr
+	}
+}
+
+// tieredFilePinner returns a pinner that opens files as tieredFileOpener does,
+// pinning files opened from the tiered file cache.
+func tieredFilePinner(opts Options, filePathPrefix string) filePinner {
+	cache := opts.TieredFileCache()
+	unpinLocal := func() {}
+	return func(filePath string) (*os.File, func(), error) {
+		fd, err := os.Open(filePath)
+		if cache == nil || err == nil || !os.IsNotExist(err) {
+			return fd, unpinLocal, err
+		}
+
+		key, keyErr := objectStoreKey(filePathPrefix, filePath)
+		if keyErr != nil {
+			return nil, nil, keyErr
+		}
+		fd, unpin, cacheErr := cache.Pin(key)
+		if cacheErr == ErrObjectNotFound {
+			// Neither on local disk nor tiered, report the local file missing.
+			return nil, nil, err
+		}
+		return fd, unpin, cacheErr
+	}
+}
+
+func uploadFile(store ObjectStore, key, filePath string) error {
+	fd, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	return store.Put(key, fd)
+}
+
+// objectStoreKey returns the key of the object a file is tiered to, which is
+// the path of the file relative to the file path prefix.
+func objectStoreKey(filePathPrefix, filePath string) (string, error) {
+	relPath, err := filepath.Rel(filePathPrefix, filePath)
+	if err != nil {
+		return "", err
+	}
+	if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %s is not within %s", filePath, filePathPrefix)
+	}
+	return filepath.ToSlash(relPath), nil
+}
+
+// writeFileAtomically writes the contents of the reader to a temporary file
+// in the same directory and renames it into place so that concurrent readers
+// never observe a partially written file.
+func writeFileAtomically(filePath string, r io.Reader, newFileMode os.FileMode) error {
+	fd, err := createFileAtomically(filePath, r, newFileMode)
+	if err != nil {
+		return err
+	}
+	return fd.Close()
+}
+
+// createFileAtomically is the same as writeFileAtomically but returns the
+// written file opened and positioned at its start.
+func createFileAtomically(filePath string, r io.Reader, newFileMode os.FileMode) (*os.File, error) {
+	fd, err := ioutil.TempFile(filepath.Dir(filePath), tieredDownloadFilePrefix)
+	if err != nil {
+		return nil, err
+	}
+	tmpFilePath := fd.Name()
+
+	if _, err := io.Copy(fd, r); err != nil {
+		fd.Close()
+		os.Remove(tmpFilePath)
+		return nil, err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		os.Remove(tmpFilePath)
+		return nil, err
+	}
+	if err := fd.Chmod(newFileMode); err != nil {
+		fd.Close()
+		os.Remove(tmpFilePath)
+		return nil, err
+	}
+	if err := os.Rename(tmpFilePath, filePath); err != nil {
+		fd.Close()
+		os.Remove(tmpFilePath)
+		return nil, err
+	}
+	if _, err := fd.Seek(0, io.SeekStart); err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return fd, nil
+}
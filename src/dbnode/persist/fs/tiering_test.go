@@ -0,0 +1,287 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTierDataFileSet(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		filePathPrefix = filepath.Join(dir, "data")
+		storeDir       = filepath.Join(dir, "store")
+		cacheDir       = TieredFileCacheDirPath(dir)
+		shard          = uint32(0)
+		store          = NewDirectoryObjectStore(storeDir)
+		entries        = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+			{"bar", nil, []byte{4, 5, 6}},
+		}
+	)
+	cache, err := NewTieredFileCache(store, TieredFileCacheOptions{
+		Directory:        cacheDir,
+		MaxBytes:         1 << 20,
+		NewFileMode:      defaultNewFileMode,
+		NewDirectoryMode: defaultNewDirectoryMode,
+	})
+	require.NoError(t, err)
+	opts := testDefaultOpts.
+		SetFilePathPrefix(filePathPrefix).
+		SetInfoReaderBufferSize(testReaderBufferSize).
+		SetDataReaderBufferSize(testReaderBufferSize).
+		SetObjectStore(store).
+		SetTierDataFileSetsAfter(time.Hour).
+		SetTieredFileCache(cache)
+
+	w := newTestWriter(t, filePathPrefix)
+	writeTestData(t, w, shard, testWriterStart, entries, persist.FileSetFlushType)
+
+	require.NoError(t, TierDataFileSet(opts, testNs1ID, shard, testWriterStart))
+
+	shardDir := ShardDataDirPath(filePathPrefix, testNs1ID, shard)
+	dataFilePath := filesetPathFromTime(shardDir, testWriterStart, dataFileSuffix)
+	indexFilePath := filesetPathFromTime(shardDir, testWriterStart, indexFileSuffix)
+	infoFilePath := filesetPathFromTime(shardDir, testWriterStart, infoFileSuffix)
+	key, err := objectStoreKey(filePathPrefix, dataFilePath)
+	require.NoError(t, err)
+	indexKey, err := objectStoreKey(filePathPrefix, indexFilePath)
+	require.NoError(t, err)
+	tieredFilePath := filepath.Join(storeDir, filepath.FromSlash(key))
+	cachedFilePath := filepath.Join(cacheDir, filepath.FromSlash(key))
+	cachedIndexFilePath := filepath.Join(cacheDir, filepath.FromSlash(indexKey))
+
+	exists, err := FileExists(dataFilePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = FileExists(infoFilePath)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = FileExists(tieredFilePath)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	files, err := DataFiles(filePathPrefix, testNs1ID, shard)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	assert.True(t, files[0].HasCheckpointFile())
+	assert.False(t, files[0].HasUntieredFiles())
+
+	// Reading only the metadata of the fileset only downloads the index
+	r, err := NewReader(testBytesPool, opts)
+	require.NoError(t, err)
+	require.NoError(t, r.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      shard,
+			BlockStart: testWriterStart,
+		},
+	}))
+	for range entries {
+		id, tags, _, _, err := r.ReadMetadata()
+		require.NoError(t, err)
+		id.Finalize()
+		tags.Close()
+	}
+	require.NoError(t, r.ValidateMetadata())
+	require.NoError(t, r.Close())
+
+	exists, err = FileExists(cachedIndexFilePath)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = FileExists(cachedFilePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Reading the data of the fileset downloads the tiered files to the
+	// cache rather than the data directory
+	readTestData(t, r, shard, testWriterStart, entries)
+
+	exists, err = FileExists(cachedFilePath)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	files, err = DataFiles(filePathPrefix, testNs1ID, shard)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	assert.False(t, files[0].HasUntieredFiles())
+
+	// Seeking the fileset reads the tiered data file once seeked
+	s := NewSeeker(filePathPrefix, testReaderBufferSize, testReaderBufferSize,
+		testReaderBufferSize, testBytesPool, false, nil, opts)
+	require.NoError(t, s.Open(testNs1ID, shard, testWriterStart))
+	assert.Equal(t, len(entries), s.Entries())
+	data, err := s.SeekByID(ident.StringID("foo"))
+	require.NoError(t, err)
+	data.IncRef()
+	assert.Equal(t, []byte{1, 2, 3}, data.Bytes())
+	data.DecRef()
+	data.Finalize()
+	require.NoError(t, s.Close())
+
+	require.NoError(t, DeleteTieredDataFiles(opts, files[0].AbsoluteFilepaths))
+	exists, err = FileExists(tieredFilePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = FileExists(cachedFilePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestTieredFileCacheEvictsLeastRecentlyOpened(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		storeDir = filepath.Join(dir, "store")
+		cacheDir = filepath.Join(dir, "cache")
+		store    = NewDirectoryObjectStore(storeDir)
+		opts     = TieredFileCacheOptions{
+			Directory:        cacheDir,
+			MaxBytes:         8,
+			NewFileMode:      defaultNewFileMode,
+			NewDirectoryMode: defaultNewDirectoryMode,
+		}
+	)
+	for _, key := range []string{"a/foo", "a/bar", "a/baz"} {
+		require.NoError(t, store.Put(key, bytes.NewReader([]byte("1234"))))
+	}
+
+	cache, err := NewTieredFileCache(store, opts)
+	require.NoError(t, err)
+
+	cached := func(key string) bool {
+		exists, err := FileExists(filepath.Join(cacheDir, filepath.FromSlash(key)))
+		require.NoError(t, err)
+		return exists
+	}
+	open := func(key string) *os.File {
+		fd, err := cache.Open(key)
+		require.NoError(t, err)
+		return fd
+	}
+
+	require.NoError(t, open("a/foo").Close())
+	require.NoError(t, open("a/bar").Close())
+	require.NoError(t, open("a/foo").Close())
+
+	// Opening a third file evicts the least recently opened file, files
+	// already opened remain readable once evicted
+	fd := open("a/baz")
+	require.NoError(t, cache.Remove("a/baz"))
+	assert.False(t, cached("a/baz"))
+	contents, err := ioutil.ReadAll(fd)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1234"), contents)
+	require.NoError(t, fd.Close())
+
+	require.NoError(t, open("a/baz").Close())
+	assert.True(t, cached("a/foo"))
+	assert.False(t, cached("a/bar"))
+	assert.True(t, cached("a/baz"))
+
+	// The cached files are kept across restarts
+	cache, err = NewTieredFileCache(store, opts)
+	require.NoError(t, err)
+	require.NoError(t, store.Delete("a/foo"))
+	require.NoError(t, open("a/foo").Close())
+
+	_, err = cache.Open("a/qux")
+	assert.Equal(t, ErrObjectNotFound, err)
+}
+
+func TestTieredFileCachePinnedFilesNotEvicted(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		storeDir = filepath.Join(dir, "store")
+		cacheDir = filepath.Join(dir, "cache")
+		store    = NewDirectoryObjectStore(storeDir)
+		opts     = TieredFileCacheOptions{
+			Directory:        cacheDir,
+			MaxBytes:         8,
+			NewFileMode:      defaultNewFileMode,
+			NewDirectoryMode: defaultNewDirectoryMode,
+		}
+	)
+	for _, key := range []string{"a/foo", "a/bar", "a/baz"} {
+		require.NoError(t, store.Put(key, bytes.NewReader([]byte("1234"))))
+	}
+
+	cache, err := NewTieredFileCache(store, opts)
+	require.NoError(t, err)
+
+	cached := func(key string) bool {
+		exists, err := FileExists(filepath.Join(cacheDir, filepath.FromSlash(key)))
+		require.NoError(t, err)
+		return exists
+	}
+	removed := func() []string {
+		matches, err := filepath.Glob(filepath.Join(cacheDir, "a", tieredDownloadFilePrefix+"*"))
+		require.NoError(t, err)
+		return matches
+	}
+	open := func(key string) *os.File {
+		fd, err := cache.Open(key)
+		require.NoError(t, err)
+		return fd
+	}
+
+	fd, unpin, err := cache.Pin("a/foo")
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	// The pinned file is least recently opened but is kept over the next
+	// least recently opened file
+	require.NoError(t, open("a/bar").Close())
+	require.NoError(t, open("a/baz").Close())
+	assert.True(t, cached("a/foo"))
+	assert.False(t, cached("a/bar"))
+	assert.True(t, cached("a/baz"))
+
+	// Removing the pinned file moves it aside until it is unpinned
+	require.NoError(t, cache.Remove("a/foo"))
+	assert.False(t, cached("a/foo"))
+	assert.Len(t, removed(), 1)
+
+	// The removed file is still accounted for until it is unpinned
+	require.NoError(t, open("a/foo").Close())
+	assert.True(t, cached("a/foo"))
+	assert.False(t, cached("a/baz"))
+
+	unpin()
+	unpin()
+	assert.Empty(t, removed())
+	assert.True(t, cached("a/foo"))
+}
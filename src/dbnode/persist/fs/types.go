@@ -27,6 +27,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -59,6 +60,8 @@ type DataWriterOpenOptions struct {
 	FileSetContentType persist.FileSetContentType
 	Identifier         FileSetFileIdentifier
 	BlockSize          time.Duration
+	// Compression is the compression applied to the data file
+	Compression compression.Type
 	// Only used when writing snapshot files
 	Snapshot DataWriterSnapshotOptions
 }
@@ -94,8 +97,9 @@ type DataFileSetReaderStatus struct {
 	Namespace  ident.ID
 	BlockStart time.Time
 
-	Shard uint32
-	Open  bool
+	Shard       uint32
+	Open        bool
+	Compression compression.Type
 }
 
 // DataReaderOpenOptions is options struct for the reader open method.
@@ -232,6 +236,11 @@ type DataFileSetSeekerManager interface {
 	// ConcurrentIDBloomFilter returns a concurrent ID bloom filter for a given
 	// shard and block start time
 	ConcurrentIDBloomFilter(shard uint32, start time.Time) (*ManagedConcurrentBloomFilter, error)
+
+	// Invalidate invalidates the seekers for a given shard and block start time
+	// so that the fileset is reopened the next time it is sought, the seekers
+	// are closed once they have all been returned.
+	Invalidate(shard uint32, start time.Time) error
 }
 
 // DataBlockRetriever provides a block retriever for TSDB file sets
@@ -313,6 +322,42 @@ type IndexFileSetReader interface {
 	Validate() error
 }
 
+// ObjectStore is a store of objects addressed by slash separated keys, such
+// as an S3 compatible object store, that cold fileset files are tiered to.
+type ObjectStore interface {
+	// Put stores the contents of the reader as the object with the given key,
+	// replacing any existing object.
+	Put(key string, r io.Reader) error
+
+	// Get returns a reader of the object with the given key, returning
+	// ErrObjectNotFound if no such object exists.
+	Get(key string) (io.ReadCloser, error)
+
+	// Delete deletes the object with the given key, deleting an object
+	// that does not exist is not an error.
+	Delete(key string) error
+}
+
+// TieredFileCache is a size bounded cache on local disk of the files of
+// flushed data filesets that have been tiered to the object store.
+type TieredFileCache interface {
+	// Open opens the cached copy of the object with the given key, downloading
+	// it from the object store first if it is not cached, returning
+	// ErrObjectNotFound if no such object exists. The least recently opened
+	// files are evicted once the cache is full, files already opened remain
+	// readable after they are evicted.
+	Open(key string) (*os.File, error)
+
+	// Pin opens the cached copy of the object with the given key as Open does
+	// and keeps it from being evicted until the returned unpin function is
+	// called, for files that remain mmapped after they are closed. A pinned
+	// file that is removed is deleted once it is unpinned.
+	Pin(key string) (*os.File, func(), error)
+
+	// Remove removes the cached copy of the object with the given key, if any.
+	Remove(key string) error
+}
+
 // Options represents the options for filesystem persistence
 type Options interface {
 	// Validate will validate the options and return an error if not valid
@@ -427,6 +472,32 @@ type Options interface {
 
 	// FSTOptions returns the fst options
 	FSTOptions() fst.Options
+
+	// SetObjectStore sets the object store that cold data filesets are
+	// tiered to, tiering is disabled if not set.
+	SetObjectStore(value ObjectStore) Options
+
+	// ObjectStore returns the object store that cold data filesets are
+	// tiered to, tiering is disabled if not set.
+	ObjectStore() ObjectStore
+
+	// SetTierDataFileSetsAfter sets how long after the end of their block
+	// flushed data filesets are tiered to the object store.
+	SetTierDataFileSetsAfter(value time.Duration) Options
+
+	// TierDataFileSetsAfter returns how long after the end of their block
+	// flushed data filesets are tiered to the object store.
+	TierDataFileSetsAfter() time.Duration
+
+	// SetTieredFileCache sets the cache that files of tiered data filesets
+	// are downloaded to when read, tiered data filesets cannot be read if
+	// not set.
+	SetTieredFileCache(value TieredFileCache) Options
+
+	// TieredFileCache returns the cache that files of tiered data filesets
+	// are downloaded to when read, tiered data filesets cannot be read if
+	// not set.
+	TieredFileCache() TieredFileCache
 }
 
 // BlockRetrieverOptions represents the options for block retrieval
@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/bloom"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/serialize"
@@ -74,6 +75,7 @@ type writer struct {
 
 	currIdx            int64
 	currOffset         int64
+	compression        writerCompression
 	encoder            *msgpack.Encoder
 	digestBuf          digest.Buffer
 	singleCheckedBytes []checked.Bytes
@@ -81,6 +83,31 @@ type writer struct {
 	err                error
 }
 
+// writerCompression holds the state of the data file compression, when
+// enabled data is buffered into frames that are compressed and written
+// out once they reach the target frame size.
+type writerCompression struct {
+	codec            compression.Codec
+	frameSize        int
+	frameBuf         []byte
+	frameOffset      int64
+	compressedBuf    []byte
+	compressedOffset int64
+	frames           []schema.IndexDataCompressionFrame
+}
+
+func (c *writerCompression) enabled() bool {
+	return c.codec != nil && c.codec.Type() != compression.None
+}
+
+func (c *writerCompression) reset(codec compression.Codec) {
+	c.codec = codec
+	c.frameBuf = c.frameBuf[:0]
+	c.frameOffset = 0
+	c.compressedOffset = 0
+	c.frames = nil
+}
+
 type indexEntry struct {
 	index           int64
 	id              ident.ID
@@ -133,6 +160,9 @@ func NewWriter(opts Options) (DataFileSetWriter, error) {
 		digestBuf:                       digest.NewBuffer(),
 		singleCheckedBytes:              make([]checked.Bytes, 1),
 		tagEncoderPool:                  opts.TagEncoderPool(),
+		compression: writerCompression{
+			frameSize: defaultDataCompressionFrameSize,
+		},
 	}, nil
 }
 
@@ -156,6 +186,12 @@ func (w *writer) Open(opts DataWriterOpenOptions) error {
 	w.currOffset = 0
 	w.err = nil
 
+	codec, err := compression.NewCodec(opts.Compression)
+	if err != nil {
+		return err
+	}
+	w.compression.reset(codec)
+
 	var (
 		shardDir            string
 		infoFilepath        string
@@ -228,6 +264,13 @@ func (w *writer) writeData(data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
+	if w.compression.enabled() {
+		// Offsets always refer to the uncompressed data file, the data is
+		// written out once the frame is flushed.
+		w.compression.frameBuf = append(w.compression.frameBuf, data...)
+		w.currOffset += int64(len(data))
+		return nil
+	}
 	written, err := w.dataFdWithDigest.Write(data)
 	if err != nil {
 		return err
@@ -236,6 +279,41 @@ func (w *writer) writeData(data []byte) error {
 	return nil
 }
 
+func (w *writer) maybeFlushCompressedFrame() error {
+	if len(w.compression.frameBuf) < w.compression.frameSize {
+		return nil
+	}
+	return w.flushCompressedFrame()
+}
+
+func (w *writer) flushCompressedFrame() error {
+	c := &w.compression
+	if !c.enabled() || len(c.frameBuf) == 0 {
+		return nil
+	}
+
+	compressed, err := c.codec.Encode(c.compressedBuf[:0], c.frameBuf)
+	if err != nil {
+		return err
+	}
+	c.compressedBuf = compressed
+
+	if _, err := w.dataFdWithDigest.Write(compressed); err != nil {
+		return err
+	}
+
+	c.frames = append(c.frames, schema.IndexDataCompressionFrame{
+		Offset:           c.frameOffset,
+		Size:             int64(len(c.frameBuf)),
+		CompressedOffset: c.compressedOffset,
+		CompressedSize:   int64(len(compressed)),
+	})
+	c.frameOffset += int64(len(c.frameBuf))
+	c.compressedOffset += int64(len(compressed))
+	c.frameBuf = c.frameBuf[:0]
+	return nil
+}
+
 func (w *writer) Write(
 	id ident.ID,
 	tags ident.Tags,
@@ -297,6 +375,12 @@ func (w *writer) writeAll(
 		}
 	}
 
+	// NB: Only flush a compressed frame once a whole series has been
+	// written so that a series never spans more than a single frame.
+	if err := w.maybeFlushCompressedFrame(); err != nil {
+		return err
+	}
+
 	w.indexEntries = append(w.indexEntries, entry)
 	w.currIdx++
 
@@ -322,6 +406,10 @@ func (w *writer) Close() error {
 }
 
 func (w *writer) close() error {
+	if err := w.flushCompressedFrame(); err != nil {
+		return err
+	}
+
 	if err := w.writeIndexRelatedFiles(); err != nil {
 		return err
 	}
@@ -538,6 +626,12 @@ func (w *writer) writeInfoFileContents(
 			NumHashesK:   int64(bloomFilter.K()),
 		},
 	}
+	if w.compression.enabled() {
+		info.DataCompression = schema.IndexDataCompressionInfo{
+			Type:   int64(w.compression.codec.Type()),
+			Frames: w.compression.frames,
+		}
+	}
 
 	w.encoder.Reset()
 	if err := w.encoder.EncodeIndexInfo(info); err != nil {
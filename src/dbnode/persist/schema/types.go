@@ -40,6 +40,8 @@ type IndexInfo struct {
 	SnapshotTime int64
 	FileType     persist.FileSetType
 	SnapshotID   []byte
+	// DataCompression is only set when the data file is compressed
+	DataCompression IndexDataCompressionInfo
 }
 
 // IndexSummariesInfo stores metadata about the summaries
@@ -53,6 +55,22 @@ type IndexBloomFilterInfo struct {
 	NumHashesK   int64
 }
 
+// IndexDataCompressionInfo stores metadata about the compression of the
+// data file, offsets in index entries always refer to uncompressed offsets
+type IndexDataCompressionInfo struct {
+	Type   int64
+	Frames []IndexDataCompressionFrame
+}
+
+// IndexDataCompressionFrame stores the location of a compressed frame in the
+// data file along with the range of uncompressed data it holds
+type IndexDataCompressionFrame struct {
+	Offset           int64
+	Size             int64
+	CompressedOffset int64
+	CompressedSize   int64
+}
+
 // IndexEntry stores entry-level data indexing
 type IndexEntry struct {
 	Index       int64
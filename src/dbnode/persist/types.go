@@ -122,6 +122,7 @@ type IndexPrepareOptions struct {
 // information specific to read/writing snapshot files.
 type DataPrepareSnapshotOptions struct {
 	SnapshotTime time.Time
+	SnapshotID   []byte
 }
 
 // FileSetType is an enum that indicates what type of files a fileset contains
@@ -0,0 +1,239 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quota
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3x/ident"
+)
+
+type exceededError struct {
+	msg string
+}
+
+func newExceededError(format string, args ...interface{}) error {
+	return exceededError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (e exceededError) Error() string {
+	return e.msg
+}
+
+// IsExceededError returns whether an error was returned because a quota
+// was exceeded.
+func IsExceededError(err error) bool {
+	_, ok := err.(exceededError)
+	return ok
+}
+
+// numCounterShards is the number of shards the window counters are spread
+// across to avoid contending on a single lock, must be a power of two.
+const numCounterShards = 64
+
+// counterShard counts events per key in a fixed one second window, the
+// counters of a previous window are dropped on the first event of a new
+// window so the counters are bounded by the keys seen within a single window.
+type counterShard struct {
+	sync.Mutex
+
+	windowStart time.Time
+	counts      map[string]int64
+}
+
+func (s *counterShard) tryAdd(key string, now time.Time, n, limit int64) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if windowStart := now.Truncate(time.Second); !windowStart.Equal(s.windowStart) {
+		s.windowStart = windowStart
+		if len(s.counts) > 0 {
+			s.counts = make(map[string]int64)
+		}
+	}
+	count := s.counts[key]
+	if count+n > limit {
+		return false
+	}
+	s.counts[key] = count + n
+	return true
+}
+
+type counterShards []*counterShard
+
+func newCounterShards() counterShards {
+	shards := make(counterShards, numCounterShards)
+	for i := range shards {
+		shards[i] = &counterShard{counts: make(map[string]int64)}
+	}
+	return shards
+}
+
+func (s counterShards) shard(key string) *counterShard {
+	// FNV-1a, inlined to avoid allocating a hasher per call.
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return s[h&(numCounterShards-1)]
+}
+
+func (s counterShards) tryAdd(key string, now time.Time, n, limit int64) bool {
+	return s.shard(key).tryAdd(key, now, n, limit)
+}
+
+// enforcerState is the immutable state of the enforcer swapped on every
+// SetLimits so that checking quotas does not need to take a lock.
+type enforcerState struct {
+	limits         Limits
+	tenantTag      []byte
+	hasWriteLimits bool
+}
+
+type enforcer struct {
+	sync.Mutex
+
+	nowFn     clock.NowFn
+	state     atomic.Value
+	newSeries counterShards
+	writes    counterShards
+}
+
+// NewEnforcer returns a new quota enforcer with no quotas.
+func NewEnforcer(nowFn clock.NowFn) Enforcer {
+	e := &enforcer{
+		nowFn:     nowFn,
+		newSeries: newCounterShards(),
+		writes:    newCounterShards(),
+	}
+	e.state.Store(&enforcerState{})
+	e.SetLimits(NewLimits())
+	return e
+}
+
+func (e *enforcer) loadState() *enforcerState {
+	return e.state.Load().(*enforcerState)
+}
+
+func (e *enforcer) SetLimits(value Limits) {
+	e.Lock()
+	defer e.Unlock()
+
+	prev := e.loadState().limits
+	hasWriteLimits := value.DefaultTenant.WritesPerSecond > 0
+	for _, limits := range value.Tenants {
+		hasWriteLimits = hasWriteLimits || limits.WritesPerSecond > 0
+	}
+	e.state.Store(&enforcerState{
+		limits:         value,
+		tenantTag:      []byte(value.TenantTag),
+		hasWriteLimits: hasWriteLimits,
+	})
+
+	// NB: Only reset the counters whose limits changed, resetting every
+	// counter on unrelated runtime options updates would let tenants burst
+	// past their quota.
+	for _, shard := range e.newSeries {
+		shard.Lock()
+		for namespace := range shard.counts {
+			if prev.Namespaces[namespace] != value.Namespaces[namespace] {
+				delete(shard.counts, namespace)
+			}
+		}
+		shard.Unlock()
+	}
+	for _, shard := range e.writes {
+		shard.Lock()
+		for tenant := range shard.counts {
+			if prev.tenant(tenant) != value.tenant(tenant) {
+				delete(shard.counts, tenant)
+			}
+		}
+		shard.Unlock()
+	}
+}
+
+func (e *enforcer) Limits() Limits {
+	return e.loadState().limits
+}
+
+func (e *enforcer) AllowNewSeries(namespace ident.ID) error {
+	limit := e.loadState().limits.Namespaces[namespace.String()].NewSeriesPerSecond
+	if limit <= 0 {
+		return nil
+	}
+	if !e.newSeries.tryAdd(namespace.String(), e.nowFn(), 1, limit) {
+		return newExceededError(
+			"namespace %s exceeded new series quota of %d per second",
+			namespace.String(), limit)
+	}
+	return nil
+}
+
+func (e *enforcer) HasWriteLimits() bool {
+	return e.loadState().hasWriteLimits
+}
+
+func (e *enforcer) Tenant(tags ident.TagIterator) string {
+	tenantTag := e.loadState().tenantTag
+
+	iter := tags.Duplicate()
+	defer iter.Close()
+
+	for iter.Next() {
+		tag := iter.Current()
+		if bytes.Equal(tag.Name.Bytes(), tenantTag) {
+			return tag.Value.String()
+		}
+	}
+	return ""
+}
+
+func (e *enforcer) AllowWrites(tenant string, n int64) error {
+	state := e.loadState()
+	if !state.hasWriteLimits {
+		return nil
+	}
+	limit := state.limits.tenant(tenant).WritesPerSecond
+	if limit <= 0 {
+		return nil
+	}
+	if !e.writes.tryAdd(tenant, e.nowFn(), n, limit) {
+		return newExceededError(
+			"tenant %s exceeded write quota of %d per second", tenant, limit)
+	}
+	return nil
+}
+
+func (e *enforcer) AllowFetchSeries(tenant string, n int64) error {
+	limit := e.loadState().limits.tenant(tenant).FetchSeriesPerQuery
+	if limit > 0 && n > limit {
+		return newExceededError(
+			"tenant %s exceeded fetch quota of %d series per query", tenant, limit)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quota
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEnforcer(limits Limits) (Enforcer, *time.Time) {
+	now := time.Unix(1000, 0)
+	e := NewEnforcer(func() time.Time { return now })
+	e.SetLimits(limits)
+	return e, &now
+}
+
+func TestEnforcerAllowNewSeries(t *testing.T) {
+	limits := NewLimits()
+	limits.Namespaces = map[string]NamespaceLimits{
+		"limited": {NewSeriesPerSecond: 2},
+	}
+	e, now := newTestEnforcer(limits)
+
+	limited := ident.StringID("limited")
+	require.NoError(t, e.AllowNewSeries(limited))
+	require.NoError(t, e.AllowNewSeries(limited))
+	err := e.AllowNewSeries(limited)
+	require.Error(t, err)
+	require.True(t, IsExceededError(err))
+
+	// Namespaces without limits are never rejected.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, e.AllowNewSeries(ident.StringID("unlimited")))
+	}
+
+	// Next window allows new series again.
+	*now = now.Add(time.Second)
+	require.NoError(t, e.AllowNewSeries(limited))
+}
+
+func TestEnforcerAllowWrites(t *testing.T) {
+	limits := NewLimits()
+	limits.Tenants = map[string]TenantLimits{
+		"foo": {WritesPerSecond: 10},
+	}
+	limits.DefaultTenant = TenantLimits{WritesPerSecond: 1}
+	e, now := newTestEnforcer(limits)
+
+	require.NoError(t, e.AllowWrites("foo", 8))
+	err := e.AllowWrites("foo", 3)
+	require.Error(t, err)
+	require.True(t, IsExceededError(err))
+	require.NoError(t, e.AllowWrites("foo", 2))
+
+	require.NoError(t, e.AllowWrites("", 1))
+	require.Error(t, e.AllowWrites("", 1))
+
+	*now = now.Add(time.Second)
+	require.NoError(t, e.AllowWrites("foo", 10))
+}
+
+func TestEnforcerHasWriteLimits(t *testing.T) {
+	e, _ := newTestEnforcer(NewLimits())
+	require.False(t, e.HasWriteLimits())
+
+	limits := NewLimits()
+	limits.Tenants = map[string]TenantLimits{
+		"foo": {FetchSeriesPerQuery: 5},
+	}
+	e.SetLimits(limits)
+	require.False(t, e.HasWriteLimits())
+
+	limits.Tenants = map[string]TenantLimits{
+		"foo": {WritesPerSecond: 5},
+	}
+	e.SetLimits(limits)
+	require.True(t, e.HasWriteLimits())
+
+	limits = NewLimits()
+	limits.DefaultTenant = TenantLimits{WritesPerSecond: 5}
+	e.SetLimits(limits)
+	require.True(t, e.HasWriteLimits())
+}
+
+func TestEnforcerDropsCountersOfPreviousWindows(t *testing.T) {
+	limits := NewLimits()
+	limits.DefaultTenant = TenantLimits{WritesPerSecond: 1}
+	e, now := newTestEnforcer(limits)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, e.AllowWrites(fmt.Sprintf("tenant-%d", i), 1))
+	}
+
+	*now = now.Add(time.Second)
+	require.NoError(t, e.AllowWrites("foo", 1))
+
+	// The counters of the previous window are dropped from a shard as soon as
+	// it is written to in a new window.
+	shard := e.(*enforcer).writes.shard("foo")
+	require.Equal(t, map[string]int64{"foo": 1}, shard.counts)
+}
+
+func TestEnforcerSetLimitsResetsOnlyChangedCounters(t *testing.T) {
+	limits := NewLimits()
+	limits.Namespaces = map[string]NamespaceLimits{
+		"ns": {NewSeriesPerSecond: 1},
+	}
+	limits.Tenants = map[string]TenantLimits{
+		"foo": {WritesPerSecond: 1},
+		"bar": {WritesPerSecond: 1},
+	}
+	e, _ := newTestEnforcer(limits)
+
+	require.NoError(t, e.AllowNewSeries(ident.StringID("ns")))
+	require.NoError(t, e.AllowWrites("foo", 1))
+	require.NoError(t, e.AllowWrites("bar", 1))
+
+	// Setting the same limits does not reset any counters
+	e.SetLimits(limits)
+	require.Error(t, e.AllowNewSeries(ident.StringID("ns")))
+	require.Error(t, e.AllowWrites("foo", 1))
+	require.Error(t, e.AllowWrites("bar", 1))
+
+	// Changing a limit only resets the counter it applies to
+	updated := NewLimits()
+	updated.Namespaces = limits.Namespaces
+	updated.Tenants = map[string]TenantLimits{
+		"foo": {WritesPerSecond: 1},
+		"bar": {WritesPerSecond: 2},
+	}
+	e.SetLimits(updated)
+	require.Error(t, e.AllowNewSeries(ident.StringID("ns")))
+	require.Error(t, e.AllowWrites("foo", 1))
+	require.NoError(t, e.AllowWrites("bar", 2))
+}
+
+func TestEnforcerAllowFetchSeries(t *testing.T) {
+	limits := NewLimits()
+	limits.Tenants = map[string]TenantLimits{
+		"foo": {FetchSeriesPerQuery: 5},
+	}
+	e, _ := newTestEnforcer(limits)
+
+	require.NoError(t, e.AllowFetchSeries("foo", 5))
+	require.NoError(t, e.AllowFetchSeries("foo", 5))
+	err := e.AllowFetchSeries("foo", 6)
+	require.Error(t, err)
+	require.True(t, IsExceededError(err))
+	require.NoError(t, e.AllowFetchSeries("bar", 1000))
+}
+
+func TestEnforcerTenant(t *testing.T) {
+	e, _ := newTestEnforcer(NewLimits())
+
+	tags := ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("host", "a"),
+		ident.StringTag(DefaultTenantTag, "foo"),
+	))
+	require.Equal(t, "foo", e.Tenant(tags))
+	// Tenant must not consume the iterator.
+	require.Equal(t, 2, tags.Remaining())
+
+	noTenant := ident.NewTagsIterator(ident.NewTags(ident.StringTag("host", "a")))
+	require.Equal(t, "", e.Tenant(noTenant))
+
+	limits := NewLimits()
+	limits.TenantTag = "team"
+	e.SetLimits(limits)
+	tags = ident.NewTagsIterator(ident.NewTags(ident.StringTag("team", "bar")))
+	require.Equal(t, "bar", e.Tenant(tags))
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NewLimits returns a new set of limits with no quotas.
+func NewLimits() Limits {
+	return Limits{TenantTag: DefaultTenantTag}
+}
+
+// ParseLimits parses limits from their JSON representation.
+func ParseLimits(value []byte) (Limits, error) {
+	limits := NewLimits()
+	if err := json.Unmarshal(value, &limits); err != nil {
+		return Limits{}, err
+	}
+	if err := limits.Validate(); err != nil {
+		return Limits{}, err
+	}
+	return limits, nil
+}
+
+// Validate validates the limits.
+func (l Limits) Validate() error {
+	if l.TenantTag == "" {
+		return fmt.Errorf("quota tenant tag must be set")
+	}
+	for ns, limits := range l.Namespaces {
+		if limits.NewSeriesPerSecond < 0 {
+			return fmt.Errorf("quota for namespace %s has negative new series limit", ns)
+		}
+	}
+	for tenant, limits := range l.Tenants {
+		if err := limits.validate(); err != nil {
+			return fmt.Errorf("quota for tenant %s is invalid: %v", tenant, err)
+		}
+	}
+	if err := l.DefaultTenant.validate(); err != nil {
+		return fmt.Errorf("quota for default tenant is invalid: %v", err)
+	}
+	return nil
+}
+
+func (l TenantLimits) validate() error {
+	if l.WritesPerSecond < 0 {
+		return fmt.Errorf("negative writes limit: %d", l.WritesPerSecond)
+	}
+	if l.FetchSeriesPerQuery < 0 {
+		return fmt.Errorf("negative fetch series limit: %d", l.FetchSeriesPerQuery)
+	}
+	return nil
+}
+
+func (l Limits) tenant(tenant string) TenantLimits {
+	if limits, ok := l.Tenants[tenant]; ok {
+		return limits
+	}
+	return l.DefaultTenant
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits([]byte(`{
+		"namespaces": {"metrics": {"newSeriesPerSecond": 100}},
+		"tenants": {"foo": {"writesPerSecond": 1000, "fetchSeriesPerQuery": 10}},
+		"defaultTenant": {"writesPerSecond": 10}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, DefaultTenantTag, limits.TenantTag)
+	require.Equal(t, int64(100), limits.Namespaces["metrics"].NewSeriesPerSecond)
+	require.Equal(t, TenantLimits{
+		WritesPerSecond:     1000,
+		FetchSeriesPerQuery: 10,
+	}, limits.tenant("foo"))
+	require.Equal(t, TenantLimits{WritesPerSecond: 10}, limits.tenant("bar"))
+}
+
+func TestParseLimitsInvalid(t *testing.T) {
+	_, err := ParseLimits([]byte(`{"tenants": {"foo": {"writesPerSecond": -1}}}`))
+	require.Error(t, err)
+
+	_, err = ParseLimits([]byte(`{"tenantTag": ""}`))
+	require.Error(t, err)
+
+	_, err = ParseLimits([]byte(`not json`))
+	require.Error(t, err)
+}
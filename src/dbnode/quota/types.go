@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package quota provides per-namespace and per-tenant quotas that protect
+// a node shared by multiple teams from any single one of them.
+package quota
+
+import (
+	"github.com/m3db/m3x/ident"
+)
+
+// DefaultTenantTag is the default tag name used to resolve the tenant of
+// a series.
+const DefaultTenantTag = "tenant"
+
+// Limits is the set of quota limits, a limit of zero means unlimited. Tenant
+// quotas are resolved from series tags, untagged writes have no tenant and
+// are subject to the default tenant quota.
+type Limits struct {
+	// TenantTag is the name of the tag used to resolve the tenant of a series.
+	TenantTag string `json:"tenantTag"`
+
+	// Namespaces contains the limits per namespace.
+	Namespaces map[string]NamespaceLimits `json:"namespaces"`
+
+	// Tenants contains the limits per tenant.
+	Tenants map[string]TenantLimits `json:"tenants"`
+
+	// DefaultTenant contains the limits for tenants without explicit limits,
+	// including series that have no tenant tag and untagged writes.
+	DefaultTenant TenantLimits `json:"defaultTenant"`
+}
+
+// NamespaceLimits is the set of limits applied to a namespace.
+type NamespaceLimits struct {
+	// NewSeriesPerSecond is the max number of new series inserted per second.
+	NewSeriesPerSecond int64 `json:"newSeriesPerSecond"`
+}
+
+// TenantLimits is the set of limits applied to a tenant.
+type TenantLimits struct {
+	// WritesPerSecond is the max number of datapoints written per second.
+	WritesPerSecond int64 `json:"writesPerSecond"`
+
+	// FetchSeriesPerQuery is the max number of series returned by a query.
+	FetchSeriesPerQuery int64 `json:"fetchSeriesPerQuery"`
+}
+
+// Enforcer enforces quota limits, it is safe for concurrent use.
+type Enforcer interface {
+	// SetLimits sets the limits to enforce.
+	SetLimits(value Limits)
+
+	// Limits returns the limits being enforced.
+	Limits() Limits
+
+	// AllowNewSeries returns an error if inserting a new series into the
+	// namespace would exceed its new series quota.
+	AllowNewSeries(namespace ident.ID) error
+
+	// HasWriteLimits returns whether any write quota is configured, callers
+	// can skip resolving the tenant of writes when there is none.
+	HasWriteLimits() bool
+
+	// Tenant returns the tenant of a series resolved from its tags, or an
+	// empty string if the series has no tenant tag.
+	Tenant(tags ident.TagIterator) string
+
+	// AllowWrites returns an error if writing n datapoints for the tenant
+	// would exceed its write quota, the tenant of untagged writes is empty.
+	AllowWrites(tenant string, n int64) error
+
+	// AllowFetchSeries returns an error if returning n series from a single
+	// query for the tenant would exceed its fetch quota.
+	AllowFetchSeries(tenant string, n int64) error
+}
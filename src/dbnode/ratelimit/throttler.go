@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+)
+
+const (
+	bytesPerMegabit = 1024 * 1024 / 8
+
+	// maxThrottlerBurst is the max duration of unused throughput that can
+	// be accumulated and spent at once after a period of inactivity.
+	maxThrottlerBurst = time.Second
+)
+
+// SleepFn is a function that sleeps for a given duration.
+type SleepFn func(time.Duration)
+
+// Throttler throttles the throughput of concurrent callers to the
+// limit specified by a set of rate limit options.
+type Throttler struct {
+	sync.Mutex
+
+	nowFn   clock.NowFn
+	sleepFn SleepFn
+	next    time.Time
+}
+
+// NewThrottler returns a new throttler.
+func NewThrottler(nowFn clock.NowFn, sleepFn SleepFn) *Throttler {
+	return &Throttler{nowFn: nowFn, sleepFn: sleepFn}
+}
+
+// Throttle accounts for bytes transferred and blocks for as long as
+// required to keep the throughput within the limit, it returns the
+// duration slept. The limit check frequency is not used as the
+// throughput is checked on every call.
+func (t *Throttler) Throttle(opts Options, bytes int64) time.Duration {
+	limitMbps := opts.LimitMbps()
+	if !opts.LimitEnabled() || limitMbps <= 0.0 || bytes <= 0 {
+		return 0
+	}
+
+	cost := time.Duration(float64(time.Second) * float64(bytes) / (limitMbps * bytesPerMegabit))
+
+	t.Lock()
+	now := t.nowFn()
+	if earliest := now.Add(-maxThrottlerBurst); t.next.Before(earliest) {
+		t.next = earliest
+	}
+	t.next = t.next.Add(cost)
+	wait := t.next.Sub(now)
+	t.Unlock()
+
+	if wait <= 0 {
+		return 0
+	}
+	t.sleepFn(wait)
+	return wait
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottlerDisabled(t *testing.T) {
+	var slept time.Duration
+	throttler := NewThrottler(time.Now, func(d time.Duration) { slept += d })
+
+	opts := NewOptions().SetLimitEnabled(false).SetLimitMbps(1)
+	require.Equal(t, time.Duration(0), throttler.Throttle(opts, 10*bytesPerMegabit))
+	require.Equal(t, time.Duration(0), slept)
+}
+
+func TestThrottlerThrottles(t *testing.T) {
+	now := time.Unix(1000, 0)
+	nowFn := func() time.Time { return now }
+	sleepFn := func(d time.Duration) { now = now.Add(d) }
+	throttler := NewThrottler(nowFn, sleepFn)
+
+	opts := NewOptions().SetLimitEnabled(true).SetLimitMbps(1)
+
+	// The first second worth of bytes is served from the burst allowance
+	require.Equal(t, time.Duration(0), throttler.Throttle(opts, bytesPerMegabit))
+
+	// Every following second worth of bytes waits a second
+	require.Equal(t, time.Second, throttler.Throttle(opts, bytesPerMegabit))
+	require.Equal(t, time.Second/2, throttler.Throttle(opts, bytesPerMegabit/2))
+
+	// Idle time accumulates at most the burst allowance
+	now = now.Add(time.Minute)
+	require.Equal(t, time.Duration(0), throttler.Throttle(opts, bytesPerMegabit))
+	require.Equal(t, time.Second, throttler.Throttle(opts, bytesPerMegabit))
+}
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/topology"
 )
@@ -81,6 +82,9 @@ type options struct {
 	clientWriteConsistencyLevel          topology.ConsistencyLevel
 	indexDefaultQueryTimeout             time.Duration
 	flushIndexBlockNumSegments           uint
+	quotaLimits                          quota.Limits
+	clientStreamBlocksRateLimitOpts      ratelimit.Options
+	serveBlocksRateLimitOpts             ratelimit.Options
 }
 
 // NewOptions creates a new set of runtime options with defaults
@@ -99,6 +103,9 @@ func NewOptions() Options {
 		clientWriteConsistencyLevel:          DefaultWriteConsistencyLevel,
 		indexDefaultQueryTimeout:             DefaultIndexDefaultQueryTimeout,
 		flushIndexBlockNumSegments:           DefaultFlushIndexBlockNumSegments,
+		quotaLimits:                          quota.NewLimits(),
+		clientStreamBlocksRateLimitOpts:      ratelimit.NewOptions(),
+		serveBlocksRateLimitOpts:             ratelimit.NewOptions(),
 	}
 }
 
@@ -124,7 +131,7 @@ func (o *options) Validate() error {
 
 	// tickMinimumInterval can be zero if user desires
 
-	return nil
+	return o.quotaLimits.Validate()
 }
 
 func (o *options) SetPersistRateLimitOptions(value ratelimit.Options) Options {
@@ -256,3 +263,33 @@ func (o *options) SetFlushIndexBlockNumSegments(value uint) Options {
 func (o *options) FlushIndexBlockNumSegments() uint {
 	return o.flushIndexBlockNumSegments
 }
+
+func (o *options) SetQuotaLimits(value quota.Limits) Options {
+	opts := *o
+	opts.quotaLimits = value
+	return &opts
+}
+
+func (o *options) QuotaLimits() quota.Limits {
+	return o.quotaLimits
+}
+
+func (o *options) SetClientStreamBlocksRateLimitOptions(value ratelimit.Options) Options {
+	opts := *o
+	opts.clientStreamBlocksRateLimitOpts = value
+	return &opts
+}
+
+func (o *options) ClientStreamBlocksRateLimitOptions() ratelimit.Options {
+	return o.clientStreamBlocksRateLimitOpts
+}
+
+func (o *options) SetServeBlocksRateLimitOptions(value ratelimit.Options) Options {
+	opts := *o
+	opts.serveBlocksRateLimitOpts = value
+	return &opts
+}
+
+func (o *options) ServeBlocksRateLimitOptions() ratelimit.Options {
+	return o.serveBlocksRateLimitOpts
+}
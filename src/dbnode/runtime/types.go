@@ -23,6 +23,7 @@ package runtime
 import (
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/topology"
 	xclose "github.com/m3db/m3x/close"
@@ -180,6 +181,30 @@ type Options interface {
 	// greater amount of segments that need to be searched independently but
 	// a higher number reduces the memory pressure when flushing an index block.
 	FlushIndexBlockNumSegments() uint
+
+	// SetQuotaLimits sets the per-namespace and per-tenant quota limits
+	// enforced when writing and querying.
+	SetQuotaLimits(value quota.Limits) Options
+
+	// QuotaLimits returns the per-namespace and per-tenant quota limits
+	// enforced when writing and querying.
+	QuotaLimits() quota.Limits
+
+	// SetClientStreamBlocksRateLimitOptions sets the rate limit options for
+	// streaming blocks from peers when bootstrapping from peers
+	SetClientStreamBlocksRateLimitOptions(value ratelimit.Options) Options
+
+	// ClientStreamBlocksRateLimitOptions returns the rate limit options for
+	// streaming blocks from peers when bootstrapping from peers
+	ClientStreamBlocksRateLimitOptions() ratelimit.Options
+
+	// SetServeBlocksRateLimitOptions sets the rate limit options for serving
+	// blocks to peers that are bootstrapping from this node
+	SetServeBlocksRateLimitOptions(value ratelimit.Options) Options
+
+	// ServeBlocksRateLimitOptions returns the rate limit options for serving
+	// blocks to peers that are bootstrapping from this node
+	ServeBlocksRateLimitOptions() ratelimit.Options
 }
 
 // OptionsManager updates and supplies runtime options.
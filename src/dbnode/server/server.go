@@ -44,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/kvconfig"
+	"github.com/m3db/m3/src/dbnode/network/server/httpjson"
 	hjcluster "github.com/m3db/m3/src/dbnode/network/server/httpjson/cluster"
 	hjnode "github.com/m3db/m3/src/dbnode/network/server/httpjson/node"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
@@ -51,6 +52,7 @@ import (
 	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/retention"
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
@@ -65,6 +67,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/tchannel"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	xdocs "github.com/m3db/m3/src/x/docs"
 	"github.com/m3db/m3/src/x/mmap"
 	"github.com/m3db/m3/src/x/serialize"
@@ -86,6 +89,8 @@ const (
 	serverGracefulCloseTimeout       = 10 * time.Second
 	bgProcessLimitInterval           = 10 * time.Second
 	maxBgProcessLimitMonitorDuration = 5 * time.Minute
+	bootstrapPeersProgressPath       = "/bootstrap/peers/progress"
+	bootstrapProgressPath            = "/bootstrap/progress"
 )
 
 // RunOptions provides options for running the server
@@ -255,6 +260,16 @@ func Run(runOpts RunOptions) {
 			SetTickMinimumInterval(tick.MinimumInterval)
 	}
 
+	if peersCfg := cfg.Bootstrap.Peers; peersCfg != nil {
+		runtimeOpts = runtimeOpts.
+			SetClientStreamBlocksRateLimitOptions(ratelimit.NewOptions().
+				SetLimitEnabled(peersCfg.StreamThroughputLimitMbps > 0).
+				SetLimitMbps(peersCfg.StreamThroughputLimitMbps)).
+			SetServeBlocksRateLimitOptions(ratelimit.NewOptions().
+				SetLimitEnabled(peersCfg.ServeThroughputLimitMbps > 0).
+				SetLimitMbps(peersCfg.ServeThroughputLimitMbps))
+	}
+
 	runtimeOptsMgr := m3dbruntime.NewOptionsManager()
 	if err := runtimeOptsMgr.Update(runtimeOpts); err != nil {
 		logger.Fatalf("could not set initial runtime options: %v", err)
@@ -314,6 +329,39 @@ func Run(runOpts RunOptions) {
 		SetTagEncoderPool(tagEncoderPool).
 		SetTagDecoderPool(tagDecoderPool)
 
+	if tieringCfg := cfg.Filesystem.Tiering; tieringCfg != nil {
+		objectStore := fs.NewDirectoryObjectStore(tieringCfg.Directory)
+		cacheDir := tieringCfg.CacheDirectory
+		if cacheDir == "" {
+			cacheDir = fs.TieredFileCacheDirPath(cfg.Filesystem.FilePathPrefix)
+		}
+		tieredFileCache, err := fs.NewTieredFileCache(objectStore, fs.TieredFileCacheOptions{
+			Directory:        cacheDir,
+			MaxBytes:         tieringCfg.CacheMaxBytesOrDefault(),
+			NewFileMode:      newFileMode,
+			NewDirectoryMode: newDirectoryMode,
+		})
+		if err != nil {
+			logger.Fatalf("could not create tiered file cache: %v", err)
+		}
+		fsopts = fsopts.
+			SetObjectStore(objectStore).
+			SetTierDataFileSetsAfter(tieringCfg.After).
+			SetTieredFileCache(tieredFileCache)
+	}
+
+	if cacheSize := cfg.Index.PostingsListCacheSizeBytes; cacheSize > 0 {
+		postingsListCache := fst.NewPostingsListCache(fst.PostingsListCacheOptions{
+			MaxBytes: cacheSize,
+			InstrumentOptions: opts.InstrumentOptions().
+				SetMetricsScope(scope.SubScope("index")),
+		})
+		fsopts = fsopts.SetFSTOptions(
+			fsopts.FSTOptions().SetPostingsListCache(postingsListCache))
+		opts = opts.SetIndexOptions(opts.IndexOptions().SetFSTSegmentOptions(
+			opts.IndexOptions().FSTSegmentOptions().SetPostingsListCache(postingsListCache)))
+	}
+
 	var commitLogQueueSize int
 	specified := cfg.CommitLog.Queue.Size
 	switch cfg.CommitLog.Queue.CalculationType {
@@ -351,6 +399,14 @@ func Run(runOpts RunOptions) {
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetBacklogQueueChannelSize(commitLogQueueChannelSize).
 		SetBlockSize(cfg.CommitLog.BlockSize))
+	if v := cfg.CommitLog.Compression; v != nil {
+		opts = opts.SetCommitLogOptions(opts.CommitLogOptions().SetCompression(*v))
+	}
+	if v := cfg.CommitLog.GroupCommit; v != nil {
+		opts = opts.SetCommitLogOptions(opts.CommitLogOptions().
+			SetStrategy(commitlog.StrategyGroupCommit).
+			SetGroupCommitMaxDelay(v.MaxDelay))
+	}
 
 	// Set the series cache policy
 	seriesCachePolicy := cfg.Cache.SeriesConfiguration().Policy
@@ -429,6 +485,7 @@ func Run(runOpts RunOptions) {
 	}
 
 	origin := topology.NewHost(hostID, "")
+	streamBlocksProgress := client.NewStreamBlocksProgress(time.Now)
 	m3dbClient, err := cfg.Client.NewAdminClient(
 		client.ConfigurationParameters{
 			InstrumentOptions: iopts.
@@ -443,6 +500,13 @@ func Run(runOpts RunOptions) {
 		},
 		func(opts client.AdminOptions) client.AdminOptions {
 			return opts.SetOrigin(origin)
+		},
+		func(opts client.AdminOptions) client.AdminOptions {
+			opts = opts.SetStreamBlocksProgress(streamBlocksProgress)
+			if peersCfg := cfg.Bootstrap.Peers; peersCfg != nil && peersCfg.StreamConcurrency > 0 {
+				opts = opts.SetFetchSeriesBlocksBatchConcurrency(peersCfg.StreamConcurrency)
+			}
+			return opts
 		})
 	if err != nil {
 		logger.Fatalf("could not create m3db client: %v", err)
@@ -456,6 +520,7 @@ func Run(runOpts RunOptions) {
 	clientAdminOpts := m3dbClient.Options().(client.AdminOptions)
 	kvWatchClientConsistencyLevels(envCfg.KVStore, logger,
 		clientAdminOpts, runtimeOptsMgr)
+	kvWatchQuotaLimits(envCfg.KVStore, logger, runtimeOptsMgr)
 
 	// Set repair options
 	hostBlockMetadataSlicePool := repair.NewHostBlockMetadataSlicePool(
@@ -474,6 +539,11 @@ func Run(runOpts RunOptions) {
 			SetRepairCheckInterval(cfg.Repair.CheckInterval).
 			SetHostBlockMetadataSlicePool(hostBlockMetadataSlicePool))
 
+	// Set downsample options
+	if cfg.Downsample != nil {
+		opts = opts.SetDownsampleRules(cfg.Downsample.DownsampleRules())
+	}
+
 	// Set tchannelthrift options
 	ttopts := tchannelthrift.NewOptions().
 		SetInstrumentOptions(opts.InstrumentOptions()).
@@ -548,8 +618,17 @@ func Run(runOpts RunOptions) {
 	defer tchannelthriftClusterClose()
 	logger.Infof("cluster tchannelthrift: listening on %v", cfg.ClusterListenAddress)
 
+	httpjsonNodeOpts := httpjson.NewServerOptions().
+		SetHandlers(map[string]http.Handler{
+			bootstrapPeersProgressPath: httpjson.NewGetHandler(func(_ *http.Request) (interface{}, error) {
+				return streamBlocksProgress.Shards(), nil
+			}),
+			bootstrapProgressPath: httpjson.NewGetHandler(func(_ *http.Request) (interface{}, error) {
+				return bs.Progress().Status(), nil
+			}),
+		})
 	httpjsonNodeClose, err := hjnode.NewServer(db,
-		cfg.HTTPNodeListenAddress, contextPool, nil, ttopts).ListenAndServe()
+		cfg.HTTPNodeListenAddress, contextPool, httpjsonNodeOpts, ttopts).ListenAndServe()
 	if err != nil {
 		logger.Fatalf("could not open httpjson interface on %s: %v",
 			cfg.HTTPNodeListenAddress, err)
@@ -795,6 +874,27 @@ func kvWatchClientConsistencyLevels(
 		})
 }
 
+func kvWatchQuotaLimits(
+	store kv.Store,
+	logger xlog.Logger,
+	runtimeOptsMgr m3dbruntime.OptionsManager,
+) {
+	kvWatchStringValue(store, logger,
+		kvconfig.QuotaLimitsKey,
+		func(value string) error {
+			limits, err := quota.ParseLimits([]byte(value))
+			if err != nil {
+				return err
+			}
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetQuotaLimits(limits))
+		},
+		func() error {
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetQuotaLimits(quota.NewLimits()))
+		})
+}
+
 func kvWatchStringValue(
 	store kv.Store,
 	logger xlog.Logger,
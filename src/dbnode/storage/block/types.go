@@ -264,6 +264,10 @@ type DatabaseBlockRetriever interface {
 	// to improve times when streaming a block.
 	CacheShardIndices(shards []uint32) error
 
+	// InvalidateBlock invalidates any cached state for the fileset of a given
+	// shard and block start so that it is reread after being replaced on disk.
+	InvalidateBlock(shard uint32, blockStart time.Time) error
+
 	// Stream will stream a block for a given shard, id and start.
 	Stream(
 		ctx context.Context,
@@ -87,7 +87,7 @@ func (b baseBootstrapper) BootstrapData(
 		return result.NewDataBootstrapResult(), nil
 	}
 	step := newBootstrapDataStep(namespace, b.src, b.next, opts)
-	err := b.runBootstrapStep(namespace, shardsTimeRanges, step)
+	err := b.runBootstrapStep(namespace, shardsTimeRanges, step, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +103,7 @@ func (b baseBootstrapper) BootstrapIndex(
 		return result.NewIndexBootstrapResult(), nil
 	}
 	step := newBootstrapIndexStep(namespace, b.src, b.next, opts)
-	err := b.runBootstrapStep(namespace, shardsTimeRanges, step)
+	err := b.runBootstrapStep(namespace, shardsTimeRanges, step, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +114,7 @@ func (b baseBootstrapper) runBootstrapStep(
 	namespace namespace.Metadata,
 	totalRanges result.ShardTimeRanges,
 	step bootstrapStep,
+	opts bootstrap.RunOptions,
 ) error {
 	prepareResult, err := step.prepare(totalRanges)
 	if err != nil {
@@ -155,7 +156,10 @@ func (b baseBootstrapper) runBootstrapStep(
 	nowFn := b.opts.ClockOptions().NowFn()
 	begin := nowFn()
 
+	progress := opts.Progress()
+	progress.SourceStarted(b.name)
 	currStatus, currErr = step.runCurrStep(currRanges)
+	progress.SourceCompleted(b.name, currStatus.fulfilled, currErr)
 
 	logFields = append(logFields, xlog.NewField("took", nowFn().Sub(begin).String()))
 	if currErr != nil {
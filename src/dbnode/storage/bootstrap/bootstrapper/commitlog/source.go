@@ -21,6 +21,7 @@
 package commitlog
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -40,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3x/checked"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -60,6 +62,7 @@ const encoderChanBufSize = 1000
 type newIteratorFn func(opts commitlog.IteratorOpts) (
 	iter commitlog.Iterator, corruptFiles []commitlog.ErrorWithPath, err error)
 type snapshotFilesFn func(filePathPrefix string, namespace ident.ID, shard uint32) (fs.FileSetFilesSlice, error)
+type snapshotMetadataFilesFn func(opts fs.Options) ([]fs.SnapshotMetadata, []fs.SnapshotMetadataErrorWithPaths, error)
 type newReaderFn func(bytesPool pool.CheckedBytesPool, opts fs.Options) (fs.DataFileSetReader, error)
 
 type commitLogSource struct {
@@ -69,9 +72,10 @@ type commitLogSource struct {
 	// Filesystem inspection capture before node was started.
 	inspection fs.Inspection
 
-	newIteratorFn   newIteratorFn
-	snapshotFilesFn snapshotFilesFn
-	newReaderFn     newReaderFn
+	newIteratorFn           newIteratorFn
+	snapshotFilesFn         snapshotFilesFn
+	snapshotMetadataFilesFn snapshotMetadataFilesFn
+	newReaderFn             newReaderFn
 
 	metrics commitLogSourceDataAndIndexMetrics
 }
@@ -98,9 +102,10 @@ func newCommitLogSource(opts Options, inspection fs.Inspection) bootstrap.Source
 
 		inspection: inspection,
 
-		newIteratorFn:   commitlog.NewIterator,
-		snapshotFilesFn: fs.SnapshotFiles,
-		newReaderFn:     fs.NewReader,
+		newIteratorFn:           commitlog.NewIterator,
+		snapshotFilesFn:         fs.SnapshotFiles,
+		snapshotMetadataFilesFn: fs.SortedSnapshotMetadataFiles,
+		newReaderFn:             fs.NewReader,
 
 		metrics: newCommitLogSourceDataAndIndexMetrics(scope),
 	}
@@ -138,6 +143,9 @@ func (s *commitLogSource) AvailableData(
 //        shards (the code treats this case as minimum snapshot time across shards == blockStart).
 //        In that case, we replay all commit log entries whose system timestamps overlap the range
 //        [blockStart.Add(-bufferFuture), blockStart.Add(blockSize).Add(bufferPast)].
+//        In addition, if the most recent snapshot metadata file identifies a snapshot that captured
+//        all of the shard/blockStart combinations being bootstrapped, then skip all commit log files
+//        that precede the commit log file recorded in the snapshot metadata file.
 //    4.  For each shard/blockStart combination, merge all of the M3TSZ encoders that we created from
 //        reading the commit log along with the data available in the corresponding snapshot file.
 //
@@ -196,6 +204,12 @@ func (s *commitLogSource) ReadData(
 		return result.NewDataBootstrapResult(), nil
 	}
 
+	// Index only namespaces hold no series data, their commit log entries
+	// are only read to bootstrap the index.
+	if ns.Options().IndexOnly() {
+		return result.NewDataBootstrapResult(), nil
+	}
+
 	var (
 		// Emit bootstrapping gauge for duration of ReadData
 		doneReadingData        = s.metrics.data.emitBootstrapping()
@@ -767,17 +781,120 @@ func (s *commitLogSource) newReadCommitLogPredBasedOnAvailableSnapshotFiles(
 			block.ToTime().String(), minSnapshotTime.String())
 	}
 
+	// In addition, if the most recent complete snapshot (as marked by its snapshot metadata file)
+	// captured all the data that we're bootstrapping, then none of the commit log files that precede
+	// the commit log file that was rotated to before that snapshot began need to be read at all.
+	capturedBySnapshot, ok := s.commitLogCapturedBySnapshot(
+		ns, shardsTimeRanges, mostRecentCompleteSnapshotByBlockShard)
+	if ok {
+		s.log.Infof(
+			"most recent complete snapshot captured all commit logs before: %s with index: %d",
+			capturedBySnapshot.start.String(), capturedBySnapshot.index)
+	}
+
 	// Now that we have the minimum most recent snapshot time for each block, we can use that data to
 	// decide how much of the commit log we need to read for each block that we're bootstrapping. We'll
 	// construct a new predicate based on the data structure we constructed earlier where the new
 	// predicate will check if there is any overlap between a commit log file and a temporary range
 	// we construct that begins with the minimum snapshot time and ends with the end of that block + bufferPast.
-	return s.newReadCommitLogPred(ns, minimumMostRecentSnapshotTimeByBlock), mostRecentCompleteSnapshotByBlockShard, nil
+	return s.newReadCommitLogPred(ns, minimumMostRecentSnapshotTimeByBlock, capturedBySnapshot, ok),
+		mostRecentCompleteSnapshotByBlockShard, nil
+}
+
+// commitLogPosition identifies a commit log file by its start time and index which
+// together determine the order in which commit log files were written.
+type commitLogPosition struct {
+	start time.Time
+	index int64
+}
+
+func (p commitLogPosition) before(other commitLogPosition) bool {
+	if p.start.Equal(other.start) {
+		return p.index < other.index
+	}
+	return p.start.Before(other.start)
+}
+
+// commitLogCapturedBySnapshot returns the position of the commit log file that was
+// rotated to before the most recent complete snapshot began, and whether all of the
+// shards and blocks that are being bootstrapped are captured by that snapshot (or a
+// more recent one) such that all commit log files preceding it can be skipped.
+func (s *commitLogSource) commitLogCapturedBySnapshot(
+	ns namespace.Metadata,
+	shardsTimeRanges result.ShardTimeRanges,
+	mostRecentCompleteSnapshotByBlockShard map[xtime.UnixNano]map[uint32]fs.FileSetFile,
+) (commitLogPosition, bool) {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	metadatas, _, err := s.snapshotMetadataFilesFn(fsOpts)
+	if err != nil {
+		s.log.Errorf("unable to read snapshot metadata files: %v", err)
+		return commitLogPosition{}, false
+	}
+	if len(metadatas) == 0 {
+		return commitLogPosition{}, false
+	}
+
+	mostRecent := metadatas[len(metadatas)-1]
+	start, index, err := fs.TimeAndIndexFromCommitlogFilename(string(mostRecent.CommitlogIdentifier))
+	if err != nil {
+		s.log.
+			WithFields(xlog.NewField("metadataFilepath", mostRecent.MetadataFilePath)).
+			Errorf("unable to parse commit log identifier of snapshot metadata: %v", err)
+		return commitLogPosition{}, false
+	}
+
+	// Determine when the snapshot began from any of the snapshot files that were
+	// written as part of it.
+	var snapshotTime time.Time
+	for _, mostRecentByShard := range mostRecentCompleteSnapshotByBlockShard {
+		for _, snapshot := range mostRecentByShard {
+			if bytes.Equal(snapshot.CachedSnapshotID, mostRecent.ID.UUID) {
+				snapshotTime = snapshot.CachedSnapshotTime
+			}
+		}
+	}
+	if snapshotTime.IsZero() {
+		return commitLogPosition{}, false
+	}
+
+	var (
+		rOpts        = ns.Options().RetentionOptions()
+		blockSize    = rOpts.BlockSize()
+		bufferFuture = rOpts.BufferFuture()
+	)
+	for blockStart, mostRecentByShard := range mostRecentCompleteSnapshotByBlockShard {
+		blockRange := xtime.Range{Start: blockStart.ToTime(), End: blockStart.ToTime().Add(blockSize)}
+		for shard, snapshot := range mostRecentByShard {
+			if !shardsTimeRanges[shard].Overlaps(blockRange) {
+				continue
+			}
+
+			if len(snapshot.CachedSnapshotID) > 0 && !snapshot.CachedSnapshotTime.Before(snapshotTime) {
+				// Captured by the snapshot or a more recent one.
+				continue
+			}
+
+			if blockStart.ToTime().After(snapshotTime.Add(bufferFuture)) {
+				// No writes could have been received for this block before the
+				// snapshot began.
+				continue
+			}
+
+			s.log.Debugf(
+				"block: %s and shard: %d are not captured by most recent complete snapshot",
+				blockStart.ToTime().String(), shard)
+			return commitLogPosition{}, false
+		}
+	}
+
+	return commitLogPosition{start: start, index: int64(index)}, true
 }
 
 func (s *commitLogSource) newReadCommitLogPred(
 	ns namespace.Metadata,
 	minimumMostRecentSnapshotTimeByBlock map[xtime.UnixNano]time.Time,
+	capturedBySnapshot commitLogPosition,
+	skipCapturedBySnapshot bool,
 ) commitlog.FileFilterPredicate {
 	var (
 		rOpts                            = ns.Options().RetentionOptions()
@@ -829,6 +946,15 @@ func (s *commitLogSource) newReadCommitLogPred(
 			return false
 		}
 
+		position := commitLogPosition{start: f.Start, index: f.Index}
+		if skipCapturedBySnapshot && position.before(capturedBySnapshot) {
+			s.log.
+				Infof(
+					"opting to skip commit log: %s with start: %s and index: %d as it is captured by snapshot",
+					f.FilePath, f.Start.String(), f.Index)
+			return false
+		}
+
 		for _, rangeToCheck := range rangesToCheck {
 			commitLogEntryRange := xtime.Range{
 				Start: f.Start,
@@ -1377,7 +1503,7 @@ func (s *commitLogSource) ReadIndex(
 			val := val.Value()
 			for block := range val.Blocks.AllBlocks() {
 				s.maybeAddToIndex(
-					id, val.Tags, shard, highestShard, block.ToTime(), bootstrapRangesByShard,
+					id, val.Tags, nil, shard, highestShard, block.ToTime(), bootstrapRangesByShard,
 					indexResults, indexOptions, indexBlockSize, resultOptions)
 			}
 		}
@@ -1396,11 +1522,24 @@ func (s *commitLogSource) ReadIndex(
 
 	defer iter.Close()
 
+	indexOnly := ns.Options().IndexOnly()
 	for iter.Next() {
-		series, dp, _, _ := iter.Current()
+		series, dp, _, annotation := iter.Current()
+
+		// Writes to index only namespaces carry the document fields indexed in
+		// addition to the series tags in their annotation.
+		var fields doc.Fields
+		if indexOnly && len(annotation) > 0 {
+			fields, err = convert.DecodeFields(annotation)
+			if err != nil {
+				s.log.Errorf("unable to decode document fields of %s: %v",
+					series.ID.String(), err)
+				fields = nil
+			}
+		}
 
 		s.maybeAddToIndex(
-			series.ID, series.Tags, series.Shard, highestShard, dp.Timestamp, bootstrapRangesByShard,
+			series.ID, series.Tags, fields, series.Shard, highestShard, dp.Timestamp, bootstrapRangesByShard,
 			indexResults, indexOptions, indexBlockSize, resultOptions)
 	}
 
@@ -1481,6 +1620,7 @@ func (s commitLogSource) shouldReturnUnfulfilled(
 func (s commitLogSource) maybeAddToIndex(
 	id ident.ID,
 	tags ident.Tags,
+	fields doc.Fields,
 	shard uint32,
 	highestShard uint32,
 	blockStart time.Time,
@@ -1514,6 +1654,7 @@ func (s commitLogSource) maybeAddToIndex(
 	if err != nil {
 		return err
 	}
+	d.Fields = append(d.Fields, fields...)
 
 	_, err = segment.Insert(d)
 	return err
@@ -47,6 +47,7 @@ import (
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -111,6 +112,36 @@ func TestReadEmpty(t *testing.T) {
 	require.True(t, res.Unfulfilled().IsEmpty())
 }
 
+func TestReadIndexOnlyNamespace(t *testing.T) {
+	opts := testDefaultOpts
+	md, err := namespace.NewMetadata(testNamespaceID, namespace.NewOptions().
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true)).
+		SetIndexOnly(true))
+	require.NoError(t, err)
+	src := newCommitLogSource(opts, fs.Inspection{}).(*commitLogSource)
+
+	blockSize := md.Options().RetentionOptions().BlockSize()
+	start := time.Now().Truncate(blockSize).Add(-blockSize)
+	ranges := xtime.Ranges{}.AddRange(xtime.Range{
+		Start: start,
+		End:   start.Add(blockSize),
+	})
+
+	foo := ts.Series{Namespace: testNamespaceID, Shard: 0, ID: ident.StringID("foo")}
+	values := []testValue{
+		{foo, start.Add(time.Minute), 1.0, xtime.Nanosecond, nil},
+	}
+	src.newIteratorFn = func(_ commitlog.IteratorOpts) (commitlog.Iterator, []commitlog.ErrorWithPath, error) {
+		return newTestCommitLogIterator(values, nil), nil, nil
+	}
+
+	// Index only namespaces have no series data to bootstrap.
+	res, err := src.ReadData(md, result.ShardTimeRanges{0: ranges}, testDefaultRunOpts)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res.ShardResults()))
+	require.True(t, res.Unfulfilled().IsEmpty())
+}
+
 func TestReadErrorOnNewIteratorError(t *testing.T) {
 	opts := testDefaultOpts
 	src := newCommitLogSource(opts, fs.Inspection{}).(*commitLogSource)
@@ -412,6 +443,98 @@ func TestItMergesSnapshotsAndCommitLogs(t *testing.T) {
 		expectedValues, blockSize, res.ShardResults(), opts))
 }
 
+func TestReadCommitLogPredSkipsCommitLogsCapturedBySnapshot(t *testing.T) {
+	var (
+		md           = testNsMetadata(t)
+		blockSize    = md.Options().RetentionOptions().BlockSize()
+		start        = time.Now().Truncate(blockSize).Add(-blockSize)
+		snapshotTime = start.Add(5 * time.Minute)
+		snapshotID   = uuid.NewRandom()
+		ranges       = result.ShardTimeRanges{0: xtime.Ranges{}.AddRange(xtime.Range{
+			Start: start,
+			End:   start.Add(blockSize),
+		})}
+
+		newCommitLogFile = func(start time.Time, index int64) commitlog.File {
+			return commitlog.File{
+				FilePath: fmt.Sprintf(
+					"/var/lib/m3db/commitlogs/commitlog-%d-%d.db", start.UnixNano(), index),
+				Start:    start,
+				Duration: 10 * time.Minute,
+				Index:    index,
+			}
+		}
+		beforeSnapshot = newCommitLogFile(start, 0)
+		rotated        = newCommitLogFile(start, 1)
+		afterSnapshot  = newCommitLogFile(start.Add(10*time.Minute), 0)
+		inspection     = fs.Inspection{SortedCommitLogFiles: []string{
+			beforeSnapshot.FilePath, rotated.FilePath, afterSnapshot.FilePath,
+		}}
+	)
+
+	newSnapshotFiles := func(id []byte) map[uint32]fs.FileSetFilesSlice {
+		return map[uint32]fs.FileSetFilesSlice{
+			0: fs.FileSetFilesSlice{
+				fs.FileSetFile{
+					ID: fs.FileSetFileIdentifier{
+						Namespace:  md.ID(),
+						BlockStart: start,
+						Shard:      0,
+					},
+					AbsoluteFilepaths:  []string{"checkpoint"},
+					CachedSnapshotTime: snapshotTime,
+					CachedSnapshotID:   id,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		snapshotFiles        map[uint32]fs.FileSetFilesSlice
+		expectReadBeforeSnap bool
+	}{
+		{
+			name:                 "captured by snapshot",
+			snapshotFiles:        newSnapshotFiles(snapshotID),
+			expectReadBeforeSnap: false,
+		},
+		{
+			name:                 "snapshot files from other snapshot",
+			snapshotFiles:        newSnapshotFiles(uuid.NewRandom()),
+			expectReadBeforeSnap: true,
+		},
+		{
+			name:                 "no snapshot files",
+			snapshotFiles:        map[uint32]fs.FileSetFilesSlice{},
+			expectReadBeforeSnap: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := newCommitLogSource(testDefaultOpts, inspection).(*commitLogSource)
+			src.snapshotMetadataFilesFn = func(fs.Options) (
+				[]fs.SnapshotMetadata, []fs.SnapshotMetadataErrorWithPaths, error) {
+				return []fs.SnapshotMetadata{
+					{
+						ID:                  fs.SnapshotMetadataIdentifier{Index: 0, UUID: snapshotID},
+						CommitlogIdentifier: []byte(rotated.FilePath),
+					},
+				}, nil, nil
+			}
+
+			pred, _, err := src.newReadCommitLogPredBasedOnAvailableSnapshotFiles(
+				md, ranges, test.snapshotFiles)
+			require.NoError(t, err)
+
+			require.Equal(t, test.expectReadBeforeSnap, pred(beforeSnapshot))
+			require.True(t, pred(rotated))
+			require.True(t, pred(afterSnapshot))
+		})
+	}
+}
+
 type testValue struct {
 	s ts.Series
 	t time.Time
@@ -28,8 +28,10 @@ import (
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 
@@ -231,6 +233,78 @@ func testBootstrapIndex(t *testing.T, bootstrapDataFirst bool) {
 	require.NoError(t, err)
 }
 
+func TestBootstrapIndexOnlyNamespaceDocumentFields(t *testing.T) {
+	var (
+		opts             = testOptions()
+		src              = newCommitLogSource(opts, fs.Inspection{}).(*commitLogSource)
+		indexBlockSize   = 4 * time.Hour
+		namespaceOptions = namespace.NewOptions().
+					SetIndexOnly(true).
+					SetIndexOptions(
+				namespace.NewOptions().
+					IndexOptions().
+					SetBlockSize(indexBlockSize).
+					SetEnabled(true),
+			)
+	)
+	md, err := namespace.NewMetadata(testNamespaceID, namespaceOptions)
+	require.NoError(t, err)
+
+	start := time.Now().Truncate(indexBlockSize)
+	foo := ts.Series{UniqueIndex: 0, Namespace: testNamespaceID, Shard: 0,
+		ID: ident.StringID("foo"), Tags: ident.NewTags(ident.StringTag("host", "a"))}
+	bar := ts.Series{UniqueIndex: 1, Namespace: testNamespaceID, Shard: 0,
+		ID: ident.StringID("bar"), Tags: ident.NewTags(ident.StringTag("host", "b"))}
+
+	fields := convert.EncodeFields(nil, doc.Fields{
+		{Name: []byte("owner"), Value: []byte("infra")},
+	})
+	values := []testValue{
+		{foo, start, 1.0, xtime.Second, fields},
+		// Undecodable annotations still index the series with its tags.
+		{bar, start, 1.0, xtime.Second, []byte{0xff}},
+	}
+	src.newIteratorFn = func(_ commitlog.IteratorOpts) (commitlog.Iterator, []commitlog.ErrorWithPath, error) {
+		return newTestCommitLogIterator(values, nil), nil, nil
+	}
+
+	targetRanges := result.ShardTimeRanges{0: xtime.NewRanges(xtime.Range{
+		Start: start,
+		End:   start.Add(indexBlockSize),
+	})}
+	res, err := src.ReadIndex(md, targetRanges, testDefaultRunOpts)
+	require.NoError(t, err)
+
+	indexResults := res.IndexResults()
+	require.Equal(t, 1, len(indexResults))
+
+	expected := map[string]doc.Fields{
+		"foo": doc.Fields{
+			{Name: []byte("host"), Value: []byte("a")},
+			{Name: []byte("owner"), Value: []byte("infra")},
+		},
+		"bar": doc.Fields{
+			{Name: []byte("host"), Value: []byte("b")},
+		},
+	}
+	for _, seg := range indexResults[xtime.ToUnixNano(start)].Segments() {
+		reader, err := seg.Reader()
+		require.NoError(t, err)
+
+		docs, err := reader.AllDocs()
+		require.NoError(t, err)
+		for docs.Next() {
+			curr := docs.Current()
+			require.Equal(t, expected[string(curr.ID)], curr.Fields)
+			delete(expected, string(curr.ID))
+		}
+		require.NoError(t, docs.Err())
+		require.NoError(t, docs.Close())
+		require.NoError(t, reader.Close())
+	}
+	require.Equal(t, 0, len(expected))
+}
+
 func TestBootstrapIndexEmptyShardTimeRanges(t *testing.T) {
 	var (
 		opts             = testOptions()
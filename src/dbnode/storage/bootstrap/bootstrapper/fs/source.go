@@ -78,8 +78,9 @@ type persistManager struct {
 }
 
 type fileSystemSourceMetrics struct {
-	persistedIndexBlocksRead  tally.Counter
-	persistedIndexBlocksWrite tally.Counter
+	persistedIndexBlocksRead        tally.Counter
+	persistedIndexBlocksWrite       tally.Counter
+	persistedIndexBlocksQuarantined tally.Counter
 }
 
 func newFileSystemSource(opts Options) bootstrap.Source {
@@ -105,8 +106,9 @@ func newFileSystemSource(opts Options) bootstrap.Source {
 			mgr: opts.PersistManager(),
 		},
 		metrics: fileSystemSourceMetrics{
-			persistedIndexBlocksRead:  scope.Counter("persist-index-blocks-read"),
-			persistedIndexBlocksWrite: scope.Counter("persist-index-blocks-write"),
+			persistedIndexBlocksRead:        scope.Counter("persist-index-blocks-read"),
+			persistedIndexBlocksWrite:       scope.Counter("persist-index-blocks-write"),
+			persistedIndexBlocksQuarantined: scope.Counter("persist-index-blocks-quarantined"),
 		},
 	}
 	s.newReaderPoolOpts.alloc = s.newReader
@@ -526,12 +528,17 @@ func (s *fileSystemSource) loadShardReadersDataIntoShardResult(
 				panic(fmt.Errorf("invalid run type: %d", run))
 			}
 
-			numEntries := r.Entries()
+			var (
+				numEntries = r.Entries()
+				bytesRead  int64
+			)
 			for i := 0; err == nil && i < numEntries; i++ {
 				switch run {
 				case bootstrapDataRunType:
-					err = s.readNextEntryAndRecordBlock(r, runResult, start, blockSize, shardResult,
+					var n int
+					n, err = s.readNextEntryAndRecordBlock(r, runResult, start, blockSize, shardResult,
 						shardRetriever, blockPool, seriesCachePolicy)
+					bytesRead += int64(n)
 				case bootstrapIndexRunType:
 					// We can just read the entry and index if performing an index run
 					err = s.readNextEntryAndIndex(r, runResult, indexBlockSegment)
@@ -540,6 +547,7 @@ func (s *fileSystemSource) loadShardReadersDataIntoShardResult(
 					panic(fmt.Errorf("invalid run type: %d", run))
 				}
 			}
+			runOpts.Progress().AddBytesRead(FileSystemBootstrapperName, bytesRead)
 
 			if err == nil {
 				// Validate the read results
@@ -623,7 +631,7 @@ func (s *fileSystemSource) readNextEntryAndRecordBlock(
 	shardRetriever block.DatabaseShardBlockRetriever,
 	blockPool block.DatabaseBlockPool,
 	seriesCachePolicy series.CachePolicy,
-) error {
+) (int, error) {
 	var (
 		seriesBlock = blockPool.Get()
 		id          ident.ID
@@ -638,7 +646,12 @@ func (s *fileSystemSource) readNextEntryAndRecordBlock(
 		err = fmt.Errorf("invalid series cache policy: %s", seriesCachePolicy.String())
 	}
 	if err != nil {
-		return fmt.Errorf("error reading data file: %v", err)
+		return 0, fmt.Errorf("error reading data file: %v", err)
+	}
+
+	var bytesRead int
+	if data != nil {
+		bytesRead = data.Len()
 	}
 
 	var (
@@ -660,7 +673,7 @@ func (s *fileSystemSource) readNextEntryAndRecordBlock(
 	} else {
 		tags, err = convert.TagsFromTagsIter(id, tagsIter, s.idPool)
 		if err != nil {
-			return fmt.Errorf("unable to decode tags: %v", err)
+			return 0, fmt.Errorf("unable to decode tags: %v", err)
 		}
 	}
 	tagsIter.Close()
@@ -670,7 +683,7 @@ func (s *fileSystemSource) readNextEntryAndRecordBlock(
 		seg := ts.NewSegment(data, nil, ts.FinalizeHead)
 		seriesBlock.Reset(blockStart, blockSize, seg)
 	default:
-		return fmt.Errorf("invalid series cache policy: %s", seriesCachePolicy.String())
+		return 0, fmt.Errorf("invalid series cache policy: %s", seriesCachePolicy.String())
 	}
 
 	if exists {
@@ -678,7 +691,7 @@ func (s *fileSystemSource) readNextEntryAndRecordBlock(
 	} else {
 		shardResult.AddBlock(id, tags, seriesBlock)
 	}
-	return nil
+	return bytesRead, nil
 }
 
 func (s *fileSystemSource) readNextEntryAndIndex(
@@ -1097,6 +1110,7 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 				FileSetType: persist.FileSetFlushType,
 			},
 			FilesystemOptions: s.fsopts,
+			Validate:          true,
 		})
 		if err != nil {
 			s.log.WithFields(
@@ -1105,6 +1119,12 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 				xlog.NewField("blockStart", indexBlockStart.String()),
 				xlog.NewField("volumeIndex", infoFile.ID.VolumeIndex),
 			).Error("unable to read segments from index fileset")
+			if fs.IsCorruptIndexFileSetError(err) {
+				// Move the corrupt volume out of the way, the ranges it would
+				// have fulfilled remain unfulfilled and are rebuilt from the
+				// data filesets instead.
+				s.quarantineIndexFileSet(ns, infoFile.ID)
+			}
 			continue
 		}
 
@@ -1128,6 +1148,41 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 	return res, nil
 }
 
+func (s *fileSystemSource) quarantineIndexFileSet(
+	ns namespace.Metadata,
+	id fs.FileSetFileIdentifier,
+) {
+	if ns.Options().IndexOnly() {
+		// NB: Index only namespaces have no data filesets to rebuild the
+		// index block from so the corrupt volume is left in place.
+		s.log.WithFields(
+			xlog.NewField("namespace", ns.ID().String()),
+			xlog.NewField("blockStart", id.BlockStart.String()),
+			xlog.NewField("volumeIndex", id.VolumeIndex),
+		).Error("not quarantining corrupt index fileset of index only namespace")
+		return
+	}
+
+	err := fs.QuarantineIndexFileSet(s.fsopts.FilePathPrefix(), id,
+		s.fsopts.NewDirectoryMode())
+	if err != nil {
+		s.log.WithFields(
+			xlog.NewField("namespace", ns.ID().String()),
+			xlog.NewField("error", err.Error()),
+			xlog.NewField("blockStart", id.BlockStart.String()),
+			xlog.NewField("volumeIndex", id.VolumeIndex),
+		).Error("unable to quarantine corrupt index fileset")
+		return
+	}
+
+	s.metrics.persistedIndexBlocksQuarantined.Inc(1)
+	s.log.WithFields(
+		xlog.NewField("namespace", ns.ID().String()),
+		xlog.NewField("blockStart", id.BlockStart.String()),
+		xlog.NewField("volumeIndex", id.VolumeIndex),
+	).Warn("quarantined corrupt index fileset")
+}
+
 func (s *fileSystemSource) shouldPersist(runOpts bootstrap.RunOptions) bool {
 	persistConfig := runOpts.PersistConfig()
 	return persistConfig.Enabled && persistConfig.FileSetType == persist.FileSetFlushType
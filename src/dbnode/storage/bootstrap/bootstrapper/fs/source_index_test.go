@@ -21,7 +21,9 @@
 package fs
 
 import (
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -443,3 +445,70 @@ func TestBootstrapIndexWithPersistPrefersPersistedIndexBlocks(t *testing.T) {
 	require.Equal(t, int64(1), counters["fs-bootstrapper.persist-index-blocks-read+"].Value())
 	require.Equal(t, int64(0), counters["fs-bootstrapper.persist-index-blocks-write+"].Value())
 }
+
+func TestBootstrapIndexWithPersistQuarantinesCorruptIndexBlocks(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	times := newTestBootstrapIndexTimes(testTimesOptions{
+		numBlocks: 2,
+	})
+
+	// Write data files
+	writeTSDBGoodTaggedSeriesDataFiles(t, dir, testNs1ID, times.start)
+
+	// Now write index block segment from first two data blocks
+	testData := testGoodTaggedSeriesDataBlocks()
+	shards := map[uint32]struct{}{testShard: struct{}{}}
+	writeTSDBPersistedIndexBlock(t, dir, testNsMetadata(t), times.start, shards,
+		append(testData[0], testData[1]...))
+
+	// Corrupt the segment files of the persisted index block
+	filesets, err := fs.IndexFileSetsAt(dir, testNs1ID, times.start)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	for _, filePath := range filesets[0].AbsoluteFilepaths {
+		if !strings.Contains(filePath, "segment") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filePath)
+		require.NoError(t, err)
+		require.True(t, len(data) > 0)
+		data[0] = ^data[0]
+		require.NoError(t, ioutil.WriteFile(filePath, data, 0666))
+	}
+
+	opts := newTestOptionsWithPersistManager(t, dir)
+	scope := tally.NewTestScope("", nil)
+	opts = opts.SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(scope))
+
+	runOpts := testDefaultRunOpts.
+		SetPersistConfig(bootstrap.PersistConfig{Enabled: true})
+
+	src := newFileSystemSource(opts).(*fileSystemSource)
+	res, err := src.ReadIndex(testNsMetadata(t), times.shardTimeRanges,
+		runOpts)
+	require.NoError(t, err)
+
+	// Validate results were rebuilt from the data filesets
+	validateGoodTaggedSeries(t, times.start, res.IndexResults())
+
+	// Check that the corrupt volume was moved to quarantine
+	quarantined, err := ioutil.ReadDir(
+		fs.NamespaceIndexQuarantineDirPath(dir, testNs1ID))
+	require.NoError(t, err)
+	require.Equal(t, len(filesets[0].AbsoluteFilepaths), len(quarantined))
+
+	// Validate that the corrupt block was quarantined and the rebuilt
+	// block was written back out
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(0), counters["fs-bootstrapper.persist-index-blocks-read+"].Value())
+	require.Equal(t, int64(1), counters["fs-bootstrapper.persist-index-blocks-quarantined+"].Value())
+	require.Equal(t, int64(1), counters["fs-bootstrapper.persist-index-blocks-write+"].Value())
+
+	// Check that the rebuilt volume is readable
+	infoFiles := fs.ReadIndexInfoFiles(src.fsopts.FilePathPrefix(), testNs1ID,
+		src.fsopts.InfoReaderBufferSize())
+	require.Equal(t, 1, len(infoFiles))
+	require.NoError(t, infoFiles[0].Err.Error())
+}
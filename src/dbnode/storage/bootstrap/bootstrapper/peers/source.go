@@ -189,7 +189,7 @@ func (s *peersSource) ReadData(
 			defer wg.Done()
 			s.fetchBootstrapBlocksFromPeers(shard, ranges, nsMetadata, session,
 				resultOpts, result, &resultLock, shouldPersist, persistenceQueue,
-				shardRetrieverMgr, blockSize)
+				shardRetrieverMgr, blockSize, opts.Progress())
 		})
 	}
 
@@ -268,6 +268,7 @@ func (s *peersSource) fetchBootstrapBlocksFromPeers(
 	persistenceQueue chan persistenceFlush,
 	shardRetrieverMgr block.DatabaseShardBlockRetrieverManager,
 	blockSize time.Duration,
+	progress bootstrap.Progress,
 ) {
 	it := ranges.Iter()
 	for it.Next() {
@@ -288,6 +289,8 @@ func (s *peersSource) fetchBootstrapBlocksFromPeers(
 				continue
 			}
 
+			progress.AddBytesRead(PeersBootstrapperName, shardResultBytes(shardResult))
+
 			if shouldPersist {
 				persistenceQueue <- persistenceFlush{
 					nsMetadata:        nsMetadata,
@@ -336,6 +339,18 @@ func (s *peersSource) logFetchBootstrapBlocksFromPeersOutcome(
 	}
 }
 
+// shardResultBytes returns the number of bytes of block data in a shard result.
+func shardResultBytes(shardResult result.ShardResult) int64 {
+	var bytes int64
+	for _, entry := range shardResult.AllSeries().Iter() {
+		series := entry.Value()
+		for _, dbBlock := range series.Blocks.AllBlocks() {
+			bytes += int64(dbBlock.Len())
+		}
+	}
+	return bytes
+}
+
 // flush is used to flush peer-bootstrapped shards to disk as they finish so
 // that we're not (necessarily) holding everything in memory at once.
 // flush starts by looping through every block in a timerange for
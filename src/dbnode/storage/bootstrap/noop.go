@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3x/ident"
 )
 
 type noOpBootstrapProcessProvider struct{}
@@ -45,6 +46,10 @@ func (b noOpBootstrapProcessProvider) Provide() (Process, error) {
 	return noOpBootstrapProcess{}, nil
 }
 
+func (b noOpBootstrapProcessProvider) Progress() Progress {
+	return NewNoOpProgress()
+}
+
 type noOpBootstrapProcess struct{}
 
 func (b noOpBootstrapProcess) Run(
@@ -57,3 +62,37 @@ func (b noOpBootstrapProcess) Run(
 		IndexResult: result.NewIndexBootstrapResult(),
 	}, nil
 }
+
+type noOpProgress struct{}
+
+// NewNoOpProgress creates a no-op bootstrap progress tracker.
+func NewNoOpProgress() Progress {
+	return noOpProgress{}
+}
+
+func (p noOpProgress) Status() ProgressStatus {
+	return ProgressStatus{}
+}
+
+func (p noOpProgress) RunStarted(
+	run string,
+	namespace ident.ID,
+	shardsTimeRanges result.ShardTimeRanges,
+) {
+}
+
+func (p noOpProgress) RunCompleted(err error) {
+}
+
+func (p noOpProgress) SourceStarted(source string) {
+}
+
+func (p noOpProgress) SourceCompleted(
+	source string,
+	fulfilled result.ShardTimeRanges,
+	err error,
+) {
+}
+
+func (p noOpProgress) AddBytesRead(source string, bytes int64) {
+}
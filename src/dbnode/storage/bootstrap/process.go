@@ -41,6 +41,7 @@ type bootstrapProcessProvider struct {
 	resultOpts           result.Options
 	log                  xlog.Logger
 	bootstrapperProvider BootstrapperProvider
+	progress             Progress
 }
 
 type bootstrapRunType string
@@ -60,11 +61,13 @@ func NewProcessProvider(
 		return nil, err
 	}
 
+	scope := resultOpts.InstrumentOptions().MetricsScope().SubScope("bootstrap-progress")
 	return &bootstrapProcessProvider{
 		processOpts:          processOpts,
 		resultOpts:           resultOpts,
 		log:                  resultOpts.InstrumentOptions().Logger(),
 		bootstrapperProvider: bootstrapperProvider,
+		progress:             NewProgress(resultOpts.ClockOptions().NowFn(), scope),
 	}, nil
 }
 
@@ -80,6 +83,10 @@ func (b *bootstrapProcessProvider) BootstrapperProvider() BootstrapperProvider {
 	return b.bootstrapperProvider
 }
 
+func (b *bootstrapProcessProvider) Progress() Progress {
+	return b.progress
+}
+
 func (b *bootstrapProcessProvider) Provide() (Process, error) {
 	b.RLock()
 	defer b.RUnlock()
@@ -100,6 +107,7 @@ func (b *bootstrapProcessProvider) Provide() (Process, error) {
 		log:                  b.log,
 		bootstrapper:         bootstrapper,
 		initialTopologyState: initialTopologyState,
+		progress:             b.progress,
 	}, nil
 }
 
@@ -145,6 +153,7 @@ type bootstrapProcess struct {
 	log                  xlog.Logger
 	bootstrapper         Bootstrapper
 	initialTopologyState *topology.StateSnapshot
+	progress             Progress
 }
 
 func (b bootstrapProcess) Run(
@@ -183,8 +192,11 @@ func (b bootstrapProcess) bootstrapData(
 
 		begin := b.nowFn()
 		shardsTimeRanges := b.newShardTimeRanges(target.Range, shards)
+		b.progress.RunStarted(string(bootstrapDataRunType), namespace.ID(),
+			shardsTimeRanges)
 		res, err := b.bootstrapper.BootstrapData(namespace,
 			shardsTimeRanges, target.RunOptions)
+		b.progress.RunCompleted(err)
 
 		b.logBootstrapResult(logFields, err, begin)
 		if err != nil {
@@ -218,8 +230,11 @@ func (b bootstrapProcess) bootstrapIndex(
 
 		begin := b.nowFn()
 		shardsTimeRanges := b.newShardTimeRanges(target.Range, shards)
+		b.progress.RunStarted(string(bootstrapIndexRunType), namespace.ID(),
+			shardsTimeRanges)
 		res, err := b.bootstrapper.BootstrapIndex(namespace,
 			shardsTimeRanges, target.RunOptions)
+		b.progress.RunCompleted(err)
 
 		b.logBootstrapResult(logFields, err, begin)
 		if err != nil {
@@ -365,5 +380,6 @@ func (b bootstrapProcess) newRunOptions() RunOptions {
 		SetCacheSeriesMetadata(
 			b.processOpts.CacheSeriesMetadata(),
 		).
-		SetInitialTopologyState(b.initialTopologyState)
+		SetInitialTopologyState(b.initialTopologyState).
+		SetProgress(b.progress)
 }
@@ -0,0 +1,348 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package bootstrap
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/uber-go/tally"
+)
+
+// ProgressStatus is a point in time view of the progress of bootstrapping.
+type ProgressStatus struct {
+	// Bootstrapping is whether a bootstrap run is currently in progress.
+	Bootstrapping bool `json:"bootstrapping"`
+	// Run is the type of the current or last bootstrap run.
+	Run string `json:"run,omitempty"`
+	// Namespace is the namespace of the current or last bootstrap run.
+	Namespace string `json:"namespace,omitempty"`
+	// From is the start of the target range of the current or last run.
+	From time.Time `json:"from"`
+	// To is the end of the target range of the current or last run.
+	To time.Time `json:"to"`
+	// Started is when the current or last bootstrap run started.
+	Started time.Time `json:"started"`
+	// Took is how long the current or last bootstrap run has taken.
+	Took time.Duration `json:"took"`
+	// LastProgress is when progress was last made by any bootstrapper, a
+	// bootstrap that has not made progress in a long time is likely stuck.
+	LastProgress time.Time `json:"lastProgress"`
+	// Error is the error the last bootstrap run completed with, if any.
+	Error string `json:"error,omitempty"`
+	// Bootstrappers are the bootstrappers currently bootstrapping.
+	Bootstrappers []string `json:"bootstrappers"`
+	// NumShards is the number of shards in the current or last run.
+	NumShards int `json:"numShards"`
+	// Fulfilled are the time ranges fulfilled so far for each shard.
+	Fulfilled []ShardProgress `json:"fulfilled"`
+	// Remaining are the time ranges yet to be fulfilled for each shard.
+	Remaining []ShardProgress `json:"remaining"`
+	// Sources is the progress of each bootstrapper across all runs.
+	Sources []SourceProgress `json:"sources"`
+}
+
+// ShardProgress is the time ranges of a shard in a bootstrap run.
+type ShardProgress struct {
+	Shard  uint32      `json:"shard"`
+	Ranges []TimeRange `json:"ranges"`
+}
+
+// TimeRange is a time range in a bootstrap run.
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SourceProgress is the progress and timing of a bootstrapper across
+// all the bootstrap runs it has taken part in.
+type SourceProgress struct {
+	Source    string        `json:"source"`
+	Running   bool          `json:"running"`
+	Runs      int64         `json:"runs"`
+	Errors    int64         `json:"errors"`
+	Took      time.Duration `json:"took"`
+	BytesRead int64         `json:"bytesRead"`
+}
+
+type progress struct {
+	sync.RWMutex
+
+	nowFn   clock.NowFn
+	scope   tally.Scope
+	metrics progressMetrics
+
+	bootstrapping bool
+	run           string
+	namespace     string
+	window        TimeRange
+	started       time.Time
+	completed     time.Time
+	lastProgress  time.Time
+	err           error
+	numShards     int
+	requested     result.ShardTimeRanges
+	fulfilled     result.ShardTimeRanges
+	sources       map[string]*sourceProgress
+}
+
+type progressMetrics struct {
+	bootstrapping   tally.Gauge
+	shardsRemaining tally.Gauge
+}
+
+type sourceProgress struct {
+	started   time.Time
+	running   int
+	runs      int64
+	errors    int64
+	took      time.Duration
+	bytesRead int64
+	metrics   sourceProgressMetrics
+}
+
+type sourceProgressMetrics struct {
+	runs      tally.Counter
+	errors    tally.Counter
+	took      tally.Timer
+	bytesRead tally.Counter
+}
+
+// NewProgress returns a new tracker of the progress of bootstrapping.
+func NewProgress(nowFn clock.NowFn, scope tally.Scope) Progress {
+	return &progress{
+		nowFn: nowFn,
+		scope: scope,
+		metrics: progressMetrics{
+			bootstrapping:   scope.Gauge("bootstrapping"),
+			shardsRemaining: scope.Gauge("shards-remaining"),
+		},
+		sources: make(map[string]*sourceProgress),
+	}
+}
+
+func (p *progress) RunStarted(
+	run string,
+	namespace ident.ID,
+	shardsTimeRanges result.ShardTimeRanges,
+) {
+	now := p.nowFn()
+	min, max := shardsTimeRanges.MinMax()
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.bootstrapping = true
+	p.run = run
+	p.namespace = namespace.String()
+	p.window = TimeRange{Start: min, End: max}
+	p.started = now
+	p.lastProgress = now
+	p.err = nil
+	p.numShards = len(shardsTimeRanges)
+	p.requested = shardsTimeRanges.Copy()
+	p.fulfilled = result.ShardTimeRanges{}
+
+	p.metrics.bootstrapping.Update(1)
+	p.metrics.shardsRemaining.Update(float64(len(p.requested)))
+}
+
+func (p *progress) RunCompleted(err error) {
+	now := p.nowFn()
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.bootstrapping = false
+	p.completed = now
+	p.lastProgress = now
+	p.err = err
+
+	p.metrics.bootstrapping.Update(0)
+}
+
+func (p *progress) SourceStarted(source string) {
+	now := p.nowFn()
+
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.sourceWithLock(source)
+	if s.running == 0 {
+		s.started = now
+	}
+	s.running++
+	s.runs++
+	s.metrics.runs.Inc(1)
+	p.lastProgress = now
+}
+
+func (p *progress) SourceCompleted(
+	source string,
+	fulfilled result.ShardTimeRanges,
+	err error,
+) {
+	now := p.nowFn()
+
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.sourceWithLock(source)
+	if s.running > 0 {
+		s.running--
+		if s.running == 0 {
+			took := now.Sub(s.started)
+			s.took += took
+			s.metrics.took.Record(took)
+		}
+	}
+	if err != nil {
+		s.errors++
+		s.metrics.errors.Inc(1)
+	}
+	p.lastProgress = now
+
+	if p.fulfilled == nil {
+		return
+	}
+	p.fulfilled.AddRanges(fulfilled.Copy())
+	p.metrics.shardsRemaining.Update(float64(len(p.remainingWithLock())))
+}
+
+func (p *progress) AddBytesRead(source string, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+
+	now := p.nowFn()
+
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.sourceWithLock(source)
+	s.bytesRead += bytes
+	s.metrics.bytesRead.Inc(bytes)
+	p.lastProgress = now
+}
+
+func (p *progress) Status() ProgressStatus {
+	now := p.nowFn()
+
+	p.RLock()
+	defer p.RUnlock()
+
+	status := ProgressStatus{
+		Bootstrapping: p.bootstrapping,
+		Run:           p.run,
+		Namespace:     p.namespace,
+		From:          p.window.Start,
+		To:            p.window.End,
+		Started:       p.started,
+		LastProgress:  p.lastProgress,
+		NumShards:     p.numShards,
+		Bootstrappers: []string{},
+		Fulfilled:     newShardProgress(p.fulfilled),
+		Remaining:     newShardProgress(p.remainingWithLock()),
+		Sources:       make([]SourceProgress, 0, len(p.sources)),
+	}
+	if p.err != nil {
+		status.Error = p.err.Error()
+	}
+	switch {
+	case p.bootstrapping:
+		status.Took = now.Sub(p.started)
+	case !p.started.IsZero():
+		status.Took = p.completed.Sub(p.started)
+	}
+
+	for name, s := range p.sources {
+		source := SourceProgress{
+			Source:    name,
+			Running:   s.running > 0,
+			Runs:      s.runs,
+			Errors:    s.errors,
+			Took:      s.took,
+			BytesRead: s.bytesRead,
+		}
+		if source.Running {
+			// Include the time taken so far by the in flight run
+			source.Took += now.Sub(s.started)
+			status.Bootstrappers = append(status.Bootstrappers, name)
+		}
+		status.Sources = append(status.Sources, source)
+	}
+	sort.Strings(status.Bootstrappers)
+	sort.Slice(status.Sources, func(i, j int) bool {
+		return status.Sources[i].Source < status.Sources[j].Source
+	})
+	return status
+}
+
+func (p *progress) sourceWithLock(source string) *sourceProgress {
+	s, ok := p.sources[source]
+	if ok {
+		return s
+	}
+
+	scope := p.scope.Tagged(map[string]string{"source": source})
+	s = &sourceProgress{
+		metrics: sourceProgressMetrics{
+			runs:      scope.Counter("source-runs"),
+			errors:    scope.Counter("source-errors"),
+			took:      scope.Timer("source-duration"),
+			bytesRead: scope.Counter("source-bytes-read"),
+		},
+	}
+	p.sources[source] = s
+	return s
+}
+
+func (p *progress) remainingWithLock() result.ShardTimeRanges {
+	remaining := p.requested.Copy()
+	remaining.Subtract(p.fulfilled)
+	return remaining
+}
+
+func newShardProgress(shardsTimeRanges result.ShardTimeRanges) []ShardProgress {
+	shards := make([]ShardProgress, 0, len(shardsTimeRanges))
+	for shard, ranges := range shardsTimeRanges {
+		if ranges.IsEmpty() {
+			continue
+		}
+		shardProgress := ShardProgress{Shard: shard}
+		it := ranges.Iter()
+		for it.Next() {
+			value := it.Value()
+			shardProgress.Ranges = append(shardProgress.Ranges,
+				TimeRange{Start: value.Start, End: value.End})
+		}
+		shards = append(shards, shardProgress)
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].Shard < shards[j].Shard
+	})
+	return shards
+}
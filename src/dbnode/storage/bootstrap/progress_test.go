@@ -0,0 +1,119 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package bootstrap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestProgress(t *testing.T) {
+	var (
+		now       = time.Now().Truncate(time.Hour)
+		start     = now.Add(-2 * time.Hour)
+		mid       = now.Add(-time.Hour)
+		scope     = tally.NewTestScope("", nil)
+		progress  = NewProgress(func() time.Time { return now }, scope)
+		requested = result.ShardTimeRanges{
+			0: xtime.NewRanges(xtime.Range{Start: start, End: now}),
+			1: xtime.NewRanges(xtime.Range{Start: start, End: now}),
+		}
+	)
+
+	status := progress.Status()
+	require.False(t, status.Bootstrapping)
+	require.Equal(t, 0, len(status.Remaining))
+
+	progress.RunStarted("bootstrap-data", ident.StringID("testns"), requested)
+	progress.SourceStarted("filesystem")
+	now = now.Add(time.Minute)
+	progress.AddBytesRead("filesystem", 100)
+
+	status = progress.Status()
+	require.True(t, status.Bootstrapping)
+	require.Equal(t, "bootstrap-data", status.Run)
+	require.Equal(t, "testns", status.Namespace)
+	require.Equal(t, start, status.From)
+	require.Equal(t, now.Add(-time.Minute), status.To)
+	require.Equal(t, time.Minute, status.Took)
+	require.Equal(t, now, status.LastProgress)
+	require.Equal(t, 2, status.NumShards)
+	require.Equal(t, []string{"filesystem"}, status.Bootstrappers)
+	require.Equal(t, 0, len(status.Fulfilled))
+	require.Equal(t, 2, len(status.Remaining))
+	require.Equal(t, []SourceProgress{
+		{
+			Source:    "filesystem",
+			Running:   true,
+			Runs:      1,
+			Took:      time.Minute,
+			BytesRead: 100,
+		},
+	}, status.Sources)
+
+	// Filesystem fulfills the first half of each shard and the
+	// commit log the second half of one shard before failing
+	progress.SourceCompleted("filesystem", result.ShardTimeRanges{
+		0: xtime.NewRanges(xtime.Range{Start: start, End: mid}),
+		1: xtime.NewRanges(xtime.Range{Start: start, End: mid}),
+	}, nil)
+	progress.SourceStarted("commitlog")
+	now = now.Add(time.Minute)
+	progress.SourceCompleted("commitlog", result.ShardTimeRanges{
+		0: xtime.NewRanges(xtime.Range{Start: mid, End: now.Add(-2 * time.Minute)}),
+	}, errors.New("an error"))
+
+	status = progress.Status()
+	require.Equal(t, []string{}, status.Bootstrappers)
+	require.Equal(t, 2, len(status.Fulfilled))
+	require.Equal(t, []TimeRange{{Start: start, End: mid}}, status.Fulfilled[1].Ranges)
+	require.Equal(t, []ShardProgress{
+		{Shard: 1, Ranges: []TimeRange{{Start: mid, End: now.Add(-2 * time.Minute)}}},
+	}, status.Remaining)
+	require.Equal(t, []SourceProgress{
+		{Source: "commitlog", Runs: 1, Errors: 1, Took: time.Minute},
+		{Source: "filesystem", Runs: 1, Took: time.Minute, BytesRead: 100},
+	}, status.Sources)
+
+	progress.RunCompleted(errors.New("an error"))
+	now = now.Add(time.Minute)
+
+	status = progress.Status()
+	require.False(t, status.Bootstrapping)
+	require.Equal(t, 2*time.Minute, status.Took)
+	require.Equal(t, "an error", status.Error)
+
+	snapshot := scope.Snapshot()
+	require.Equal(t, float64(0), snapshot.Gauges()["bootstrapping+"].Value())
+	require.Equal(t, float64(1), snapshot.Gauges()["shards-remaining+"].Value())
+	require.Equal(t, int64(100),
+		snapshot.Counters()["source-bytes-read+source=filesystem"].Value())
+	require.Equal(t, int64(1),
+		snapshot.Counters()["source-errors+source=commitlog"].Value())
+}
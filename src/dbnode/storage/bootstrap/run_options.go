@@ -34,6 +34,7 @@ type runOptions struct {
 	persistConfig        PersistConfig
 	cacheSeriesMetadata  bool
 	initialTopologyState *topology.StateSnapshot
+	progress             Progress
 }
 
 // NewRunOptions creates new bootstrap run options
@@ -42,6 +43,7 @@ func NewRunOptions() RunOptions {
 		persistConfig:        defaultPersistConfig,
 		cacheSeriesMetadata:  defaultCacheSeriesMetadata,
 		initialTopologyState: nil,
+		progress:             NewNoOpProgress(),
 	}
 }
 
@@ -74,3 +76,13 @@ func (o *runOptions) SetInitialTopologyState(value *topology.StateSnapshot) RunO
 func (o *runOptions) InitialTopologyState() *topology.StateSnapshot {
 	return o.initialTopologyState
 }
+
+func (o *runOptions) SetProgress(value Progress) RunOptions {
+	opts := *o
+	opts.progress = value
+	return &opts
+}
+
+func (o *runOptions) Progress() Progress {
+	return o.progress
+}
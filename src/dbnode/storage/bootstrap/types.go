@@ -27,6 +27,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 )
 
@@ -43,6 +44,10 @@ type ProcessProvider interface {
 
 	// Provide constructs a bootstrap process.
 	Provide() (Process, error)
+
+	// Progress returns the tracker of the progress of the bootstrap
+	// processes constructed by this provider.
+	Progress() Progress
 }
 
 // Process represents the bootstrap process. Note that a bootstrap process can and will
@@ -104,6 +109,31 @@ type ProcessOptions interface {
 	Validate() error
 }
 
+// Progress tracks the progress of bootstrapping so that it can be
+// observed while a bootstrap is in progress.
+type Progress interface {
+	// Status returns the current progress of bootstrapping.
+	Status() ProgressStatus
+
+	// RunStarted marks the start of a bootstrap run of the given shards
+	// and time ranges for a namespace.
+	RunStarted(run string, namespace ident.ID, shardsTimeRanges result.ShardTimeRanges)
+
+	// RunCompleted marks the completion of the current bootstrap run.
+	RunCompleted(err error)
+
+	// SourceStarted marks a bootstrapper starting to bootstrap as part of
+	// the current run.
+	SourceStarted(source string)
+
+	// SourceCompleted marks a bootstrapper completing, fulfilling the given
+	// shards and time ranges of the current run.
+	SourceCompleted(source string, fulfilled result.ShardTimeRanges, err error)
+
+	// AddBytesRead records bytes read by a bootstrapper.
+	AddBytesRead(source string, bytes int64)
+}
+
 // RunOptions is a set of options for a bootstrap run.
 type RunOptions interface {
 	// SetPersistConfig sets persistence configuration for this bootstrap.
@@ -127,6 +157,12 @@ type RunOptions interface {
 	// InitialTopologyState returns the initial topology as it was measured
 	// before the bootstrap process began.
 	InitialTopologyState() *topology.StateSnapshot
+
+	// SetProgress sets the tracker of the progress of this bootstrap.
+	SetProgress(value Progress) RunOptions
+
+	// Progress returns the tracker of the progress of this bootstrap.
+	Progress() Progress
 }
 
 // BootstrapperProvider constructs a bootstrapper.
@@ -41,6 +41,12 @@ type deleteFilesFn func(files []string) error
 
 type deleteInactiveDirectoriesFn func(parentDirPath string, activeDirNames []string) error
 
+type snapshotMetadataFilesFn func(fs.Options) ([]fs.SnapshotMetadata, []fs.SnapshotMetadataErrorWithPaths, error)
+
+type dataFilesFn func(filePathPrefix string, namespace ident.ID, shard uint32) (fs.FileSetFilesSlice, error)
+
+type tierDataFileSetFn func(opts fs.Options, namespace ident.ID, shard uint32, blockStart time.Time) error
+
 // Narrow interface so as not to expose all the functionality of the commitlog
 // to the cleanup manager.
 type activeCommitlogs interface {
@@ -60,6 +66,9 @@ type cleanupManager struct {
 	commitLogFilesFn            commitLogFilesFn
 	deleteFilesFn               deleteFilesFn
 	deleteInactiveDirectoriesFn deleteInactiveDirectoriesFn
+	snapshotMetadataFilesFn     snapshotMetadataFilesFn
+	dataFilesFn                 dataFilesFn
+	tierDataFileSetFn           tierDataFileSetFn
 	cleanupInProgress           bool
 	metrics                     cleanupManagerMetrics
 }
@@ -68,14 +77,19 @@ type cleanupManagerMetrics struct {
 	status               tally.Gauge
 	corruptCommitlogFile tally.Counter
 	deletedCommitlogFile tally.Counter
+	tieredDataFileSet    tally.Counter
+	tierDataFileSetError tally.Counter
 }
 
 func newCleanupManagerMetrics(scope tally.Scope) cleanupManagerMetrics {
 	clScope := scope.SubScope("commitlog")
+	tieringScope := scope.SubScope("tiering")
 	return cleanupManagerMetrics{
 		status:               scope.Gauge("cleanup"),
 		corruptCommitlogFile: clScope.Counter("corrupt"),
 		deletedCommitlogFile: clScope.Counter("deleted"),
+		tieredDataFileSet:    tieringScope.Counter("tiered"),
+		tierDataFileSetError: tieringScope.Counter("errors"),
 	}
 }
 
@@ -96,6 +110,9 @@ func newCleanupManager(
 		commitLogFilesFn:            commitlog.Files,
 		deleteFilesFn:               fs.DeleteFiles,
 		deleteInactiveDirectoriesFn: fs.DeleteInactiveDirectories,
+		snapshotMetadataFilesFn:     fs.SortedSnapshotMetadataFiles,
+		dataFilesFn:                 fs.DataFiles,
+		tierDataFileSetFn:           fs.TierDataFileSet,
 		metrics:                     newCleanupManagerMetrics(scope),
 	}
 }
@@ -117,6 +134,11 @@ func (m *cleanupManager) Cleanup(t time.Time) error {
 			"encountered errors when cleaning up data files for %v: %v", t, err))
 	}
 
+	if err := m.tierDataFiles(t); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when tiering data files for %v: %v", t, err))
+	}
+
 	if err := m.cleanupExpiredIndexFiles(t); err != nil {
 		multiErr = multiErr.Add(fmt.Errorf(
 			"encountered errors when cleaning up index files for %v: %v", t, err))
@@ -142,6 +164,11 @@ func (m *cleanupManager) Cleanup(t time.Time) error {
 			"encountered errors when deleting inactive namespace files for %v: %v", t, err))
 	}
 
+	if err := m.cleanupSnapshotMetadataFiles(); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when cleaning up snapshot metadata files for %v: %v", t, err))
+	}
+
 	filesToCleanup, err := m.commitLogTimes(t)
 	if err != nil {
 		multiErr = multiErr.Add(fmt.Errorf(
@@ -236,6 +263,55 @@ func (m *cleanupManager) cleanupExpiredDataFiles(t time.Time) error {
 	return multiErr.FinalError()
 }
 
+// tierDataFiles moves the flushed data filesets of blocks that ended longer
+// than the configured age ago to the object store, if one is configured.
+func (m *cleanupManager) tierDataFiles(t time.Time) error {
+	fsOpts := m.opts.CommitLogOptions().FilesystemOptions()
+	if fsOpts.ObjectStore() == nil {
+		return nil
+	}
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	var (
+		multiErr   = xerrors.NewMultiError()
+		tierBefore = t.Add(-fsOpts.TierDataFileSetsAfter())
+	)
+	for _, n := range namespaces {
+		if !n.Options().CleanupEnabled() {
+			continue
+		}
+		blockSize := n.Options().RetentionOptions().BlockSize()
+		for _, shard := range n.GetOwnedShards() {
+			filesets, err := m.dataFilesFn(m.filePathPrefix, n.ID(), shard.ID())
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+			for _, fileset := range filesets {
+				blockEnd := fileset.ID.BlockStart.Add(blockSize)
+				if blockEnd.After(tierBefore) ||
+					!fileset.HasCheckpointFile() ||
+					!fileset.HasUntieredFiles() {
+					continue
+				}
+
+				err := m.tierDataFileSetFn(fsOpts, n.ID(), shard.ID(), fileset.ID.BlockStart)
+				if err != nil {
+					m.metrics.tierDataFileSetError.Inc(1)
+					multiErr = multiErr.Add(err)
+					continue
+				}
+				m.metrics.tieredDataFileSet.Inc(1)
+			}
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (m *cleanupManager) cleanupExpiredIndexFiles(t time.Time) error {
 	namespaces, err := m.database.GetOwnedNamespaces()
 	if err != nil {
@@ -294,6 +370,76 @@ func (m *cleanupManager) cleanupNamespaceSnapshotFiles(earliestToRetain time.Tim
 	return multiErr.FinalError()
 }
 
+// cleanupSnapshotMetadataFiles deletes all of the snapshot metadata files except for
+// the most recent one as it is the only one that is used during bootstrap, as well as
+// any corrupt snapshot metadata files.
+func (m *cleanupManager) cleanupSnapshotMetadataFiles() error {
+	metadatas, errorsWithPaths, err := m.snapshotMetadataFilesFn(
+		m.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		return err
+	}
+
+	filesToDelete := make([]string, 0, 2*len(metadatas)+2*len(errorsWithPaths))
+	for i := 0; i < len(metadatas)-1; i++ {
+		filesToDelete = append(filesToDelete,
+			metadatas[i].CheckpointFilePath, metadatas[i].MetadataFilePath)
+	}
+
+	for _, errorWithPaths := range errorsWithPaths {
+		// The snapshot metadata file can be corrupt in situations where M3DB experiences
+		// sudden shutdown, in which case the checkpoint file will often not exist.
+		m.opts.InstrumentOptions().Logger().Errorf(
+			"encountered corrupt snapshot metadata file during cleanup, marking file for deletion: %s: %v",
+			errorWithPaths.MetadataFilePath, errorWithPaths.Error)
+		if errorWithPaths.CheckpointFilePath != "" {
+			exists, err := fs.FileExists(errorWithPaths.CheckpointFilePath)
+			if err != nil {
+				return err
+			}
+			if exists {
+				filesToDelete = append(filesToDelete, errorWithPaths.CheckpointFilePath)
+			}
+		}
+		filesToDelete = append(filesToDelete, errorWithPaths.MetadataFilePath)
+	}
+
+	return m.deleteFilesFn(filesToDelete)
+}
+
+// commitLogCapturedBySnapshot returns the commit log file recorded in the most recent
+// snapshot metadata file, all of the commit log files that precede it only contain data
+// that was captured by the snapshot.
+func (m *cleanupManager) commitLogCapturedBySnapshot() (commitlog.File, bool, error) {
+	metadatas, _, err := m.snapshotMetadataFilesFn(m.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		return commitlog.File{}, false, err
+	}
+	if len(metadatas) == 0 {
+		return commitlog.File{}, false, nil
+	}
+
+	var (
+		mostRecent = metadatas[len(metadatas)-1]
+		filePath   = string(mostRecent.CommitlogIdentifier)
+	)
+	start, index, err := fs.TimeAndIndexFromCommitlogFilename(filePath)
+	if err != nil {
+		// Not a commitlog file specific issue, but we can still fall back to
+		// determining which commit logs to clean up based on the snapshot files.
+		m.opts.InstrumentOptions().Logger().Errorf(
+			"unable to parse commit log identifier of snapshot metadata file: %s: %v",
+			mostRecent.MetadataFilePath, err)
+		return commitlog.File{}, false, nil
+	}
+
+	return commitlog.File{
+		FilePath: filePath,
+		Start:    start,
+		Index:    int64(index),
+	}, true, nil
+}
+
 // commitLogTimes returns the earliest time before which the commit logs are expired,
 // as well as a list of times we need to clean up commit log files for.
 func (m *cleanupManager) commitLogTimes(t time.Time) ([]commitLogFileWithErrorAndPath, error) {
@@ -326,6 +472,11 @@ func (m *cleanupManager) commitLogTimes(t time.Time) ([]commitLogFileWithErrorAn
 		return nil, err
 	}
 
+	snapshotCommitlog, hasSnapshotCommitlog, err := m.commitLogCapturedBySnapshot()
+	if err != nil {
+		return nil, err
+	}
+
 	shouldCleanupFile := func(f commitlog.File) (bool, error) {
 		if commitlogsContainPath(activeCommitlogs, f.FilePath) {
 			// An active commitlog should never satisfy all of the constraints
@@ -333,6 +484,12 @@ func (m *cleanupManager) commitLogTimes(t time.Time) ([]commitLogFileWithErrorAn
 			return false, nil
 		}
 
+		if hasSnapshotCommitlog && commitlogPrecedes(f, snapshotCommitlog) {
+			// The commit log was rotated away from before the most recent complete
+			// snapshot began so all of its data is captured by the snapshot.
+			return true, nil
+		}
+
 		for _, ns := range namespaces {
 			var (
 				start                      = f.Start
@@ -347,6 +504,13 @@ func (m *cleanupManager) commitLogTimes(t time.Time) ([]commitLogFileWithErrorAn
 				continue
 			}
 
+			if ns.Options().IndexOnly() && ns.NeedsIndexFlush(nsBlocksStart, nsBlocksEnd) {
+				// Index only namespaces hold no series data so neither the data
+				// filesets nor the snapshots capture their documents, until the
+				// index blocks are flushed they only exist in the commit logs.
+				return false, nil
+			}
+
 			if !needsFlush {
 				// Data has been flushed to disk so the commit log file is
 				// safe to clean up.
@@ -466,6 +630,14 @@ func newCommitLogFileWithErrorAndPath(
 	}
 }
 
+// commitlogPrecedes returns whether the commit log file f was written before other.
+func commitlogPrecedes(f, other commitlog.File) bool {
+	if f.Start.Equal(other.Start) {
+		return f.Index < other.Index
+	}
+	return f.Start.Before(other.Start)
+}
+
 func commitlogsContainPath(commitlogs []commitlog.File, path string) bool {
 	for _, f := range commitlogs {
 		if path == f.FilePath {
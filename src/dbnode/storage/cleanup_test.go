@@ -27,6 +27,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
@@ -180,6 +181,66 @@ func TestCleanupDataAndSnapshotFileSetFiles(t *testing.T) {
 	require.NoError(t, mgr.Cleanup(ts))
 }
 
+func TestCleanupManagerTierDataFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ts := timeFor(36000)
+
+	nsOpts := namespace.NewOptions()
+	blockSize := nsOpts.RetentionOptions().BlockSize()
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+	namespaces := []databaseNamespace{ns}
+
+	db := newMockdatabase(ctrl, namespaces...)
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), tally.NoopScope).(*cleanupManager)
+
+	fsOpts := mgr.opts.CommitLogOptions().FilesystemOptions().
+		SetObjectStore(fs.NewDirectoryObjectStore("/var/lib/m3db-tiered")).
+		SetTierDataFileSetsAfter(time.Hour)
+	mgr.opts = mgr.opts.SetCommitLogOptions(
+		mgr.opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	fileset := func(blockStart time.Time, suffixes ...string) fs.FileSetFile {
+		f := fs.FileSetFile{ID: fs.FileSetFileIdentifier{BlockStart: blockStart}}
+		for _, suffix := range suffixes {
+			f.AbsoluteFilepaths = append(f.AbsoluteFilepaths,
+				fmt.Sprintf("fileset-%d-0-%s.db", blockStart.UnixNano(), suffix))
+		}
+		return f
+	}
+	mgr.dataFilesFn = func(_ string, _ ident.ID, _ uint32) (fs.FileSetFilesSlice, error) {
+		return fs.FileSetFilesSlice{
+			// Complete and old enough to tier
+			fileset(ts.Add(-5*blockSize), "info", "data", "checkpoint"),
+			// Incomplete
+			fileset(ts.Add(-4*blockSize), "info", "data"),
+			// Already tiered
+			fileset(ts.Add(-3*blockSize), "info", "checkpoint"),
+			// Too recent to tier
+			fileset(ts.Add(-blockSize), "info", "data", "checkpoint"),
+		}, nil
+	}
+	var tiered []time.Time
+	mgr.tierDataFileSetFn = func(
+		opts fs.Options,
+		namespace ident.ID,
+		shard uint32,
+		blockStart time.Time,
+	) error {
+		tiered = append(tiered, blockStart)
+		return nil
+	}
+
+	require.NoError(t, mgr.tierDataFiles(ts))
+	require.Equal(t, []time.Time{ts.Add(-5 * blockSize)}, tiered)
+}
+
 type deleteInactiveDirectoriesCall struct {
 	parentDirPath  string
 	activeDirNames []string
@@ -431,6 +492,7 @@ func newCleanupManagerCommitLogTimesTest(t *testing.T, ctrl *gomock.Controller)
 	)
 	no := namespace.NewMockOptions(ctrl)
 	no.EXPECT().RetentionOptions().Return(rOpts).AnyTimes()
+	no.EXPECT().IndexOnly().Return(false).AnyTimes()
 
 	ns := NewMockdatabaseNamespace(ctrl)
 	ns.EXPECT().Options().Return(no).AnyTimes()
@@ -457,6 +519,7 @@ func newCleanupManagerCommitLogTimesTestMultiNS(
 	)
 	no := namespace.NewMockOptions(ctrl)
 	no.EXPECT().RetentionOptions().Return(rOpts).AnyTimes()
+	no.EXPECT().IndexOnly().Return(false).AnyTimes()
 
 	ns1 := NewMockdatabaseNamespace(ctrl)
 	ns1.EXPECT().Options().Return(no).AnyTimes()
@@ -764,6 +827,128 @@ func TestCleanupManagerIgnoresActiveCommitLogFiles(t *testing.T) {
 	require.Empty(t, filesToCleanup, path)
 }
 
+func TestCleanupManagerCommitLogTimesCapturedBySnapshotMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		ns, mgr          = newCleanupManagerCommitLogTimesTest(t, ctrl)
+		newCommitLogFile = func(start time.Time, index int64) commitlog.File {
+			return commitlog.File{
+				FilePath: fmt.Sprintf("commitlog-%d-%d.db", start.UnixNano(), index),
+				Start:    start,
+				Duration: commitLogBlockSize,
+				Index:    index,
+			}
+		}
+		beforeSnapshot = newCommitLogFile(time10, 0)
+		rotated        = newCommitLogFile(time10, 1)
+		afterSnapshot  = newCommitLogFile(time20, 0)
+	)
+	mgr.commitLogFilesFn = func(_ commitlog.Options) ([]commitlog.File, []commitlog.ErrorWithPath, error) {
+		return []commitlog.File{beforeSnapshot, rotated, afterSnapshot}, nil, nil
+	}
+	mgr.snapshotMetadataFilesFn = func(fs.Options) ([]fs.SnapshotMetadata, []fs.SnapshotMetadataErrorWithPaths, error) {
+		return []fs.SnapshotMetadata{
+			{CommitlogIdentifier: []byte("commitlog-0-0.db")},
+			{CommitlogIdentifier: []byte(rotated.FilePath)},
+		}, nil, nil
+	}
+
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true).AnyTimes()
+	ns.EXPECT().IsCapturedBySnapshot(
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(false, nil).AnyTimes()
+
+	filesToCleanup, err := mgr.commitLogTimes(currentTime)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesToCleanup))
+	require.True(t, containsCorrupt(filesToCleanup, beforeSnapshot.FilePath))
+}
+
+func TestCleanupManagerCommitLogTimesIndexOnlyPendingIndexFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		rOpts = retention.NewOptions().
+			SetRetentionPeriod(30 * time.Second).
+			SetBufferPast(0 * time.Second).
+			SetBufferFuture(0 * time.Second).
+			SetBlockSize(10 * time.Second)
+		indexOnlyOpts = namespace.NewOptions().
+				SetRetentionOptions(rOpts).
+				SetIndexOnly(true)
+		newCommitLogFile = func(start time.Time) commitlog.File {
+			return commitlog.File{
+				FilePath: fmt.Sprintf("commitlog-%d-0.db", start.UnixNano()),
+				Start:    start,
+				Duration: commitLogBlockSize,
+			}
+		}
+		indexFlushed   = newCommitLogFile(time10)
+		indexUnflushed = newCommitLogFile(time20)
+	)
+
+	ns1, ns2, mgr := newCleanupManagerCommitLogTimesTestMultiNS(t, ctrl)
+	indexOnlyNs := NewMockdatabaseNamespace(ctrl)
+	indexOnlyNs.EXPECT().Options().Return(indexOnlyOpts).AnyTimes()
+	mgr.database = newMockdatabase(ctrl, ns1, ns2, indexOnlyNs)
+
+	mgr.commitLogFilesFn = func(_ commitlog.Options) ([]commitlog.File, []commitlog.ErrorWithPath, error) {
+		return []commitlog.File{indexFlushed, indexUnflushed}, nil, nil
+	}
+	// The index only namespace never lets a snapshot be marked complete while
+	// its index has unflushed blocks so no snapshot metadata exists.
+	mgr.snapshotMetadataFilesFn = func(fs.Options) ([]fs.SnapshotMetadata, []fs.SnapshotMetadataErrorWithPaths, error) {
+		return nil, nil, nil
+	}
+
+	for _, ns := range []*MockdatabaseNamespace{ns1, ns2, indexOnlyNs} {
+		// The data of every namespace is flushed, for the index only namespace
+		// the data filesets hold none of its documents though.
+		ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	}
+	indexOnlyNs.EXPECT().NeedsIndexFlush(time10, time20).Return(false)
+	indexOnlyNs.EXPECT().NeedsIndexFlush(time20, time30).Return(true)
+
+	filesToCleanup, err := mgr.commitLogTimes(currentTime)
+	require.NoError(t, err)
+
+	// The commit log holding documents of the unflushed index block is the
+	// only copy of them, so it must be retained.
+	require.Equal(t, 1, len(filesToCleanup))
+	require.True(t, contains(filesToCleanup, time10))
+}
+
+func TestCleanupManagerCleanupSnapshotMetadataFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, mgr := newCleanupManagerCommitLogTimesTest(t, ctrl)
+	mgr.snapshotMetadataFilesFn = func(fs.Options) ([]fs.SnapshotMetadata, []fs.SnapshotMetadataErrorWithPaths, error) {
+		return []fs.SnapshotMetadata{
+			{MetadataFilePath: "metadata-0", CheckpointFilePath: "checkpoint-0"},
+			{MetadataFilePath: "metadata-1", CheckpointFilePath: "checkpoint-1"},
+			{MetadataFilePath: "metadata-2", CheckpointFilePath: "checkpoint-2"},
+		}, []fs.SnapshotMetadataErrorWithPaths{
+			{Error: errors.New("some_error"), MetadataFilePath: "metadata-3"},
+		}, nil
+	}
+	var deletedFiles []string
+	mgr.deleteFilesFn = func(files []string) error {
+		deletedFiles = append(deletedFiles, files...)
+		return nil
+	}
+
+	require.NoError(t, mgr.cleanupSnapshotMetadataFiles())
+	require.Equal(t, []string{
+		"checkpoint-0", "metadata-0",
+		"checkpoint-1", "metadata-1",
+		"metadata-3",
+	}, deletedFiles)
+}
+
 type fakeActiveLogs struct {
 	activeLogs []commitlog.File
 }
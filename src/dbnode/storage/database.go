@@ -30,13 +30,16 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xcounter"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	xclose "github.com/m3db/m3x/close"
 	"github.com/m3db/m3x/context"
 	xerrors "github.com/m3db/m3x/errors"
 	"github.com/m3db/m3x/ident"
@@ -59,6 +62,9 @@ var (
 	// errDatabaseIsClosed raised when trying to perform an action that requires an open database.
 	errDatabaseIsClosed = errors.New("database is closed")
 
+	// errDatabaseNotBootstrapped raised when trying to snapshot a database that is not bootstrapped.
+	errDatabaseNotBootstrapped = errors.New("database is not bootstrapped")
+
 	// errWriterDoesNotImplementWriteBatch is raised when the provided ts.BatchWriter does not implement
 	// ts.WriteBatch.
 	errWriterDoesNotImplementWriteBatch = errors.New("provided writer does not implement ts.WriteBatch")
@@ -105,6 +111,8 @@ type db struct {
 	errThreshold int64
 
 	writeBatchPool *ts.WriteBatchPool
+
+	runtimeOptsListener xclose.SimpleCloser
 }
 
 type databaseMetrics struct {
@@ -117,6 +125,8 @@ type databaseMetrics struct {
 	unknownNamespaceFetchBlocks         tally.Counter
 	unknownNamespaceFetchBlocksMetadata tally.Counter
 	unknownNamespaceQueryIDs            tally.Counter
+	unknownNamespaceAggregateQuery      tally.Counter
+	unknownNamespaceCardinality         tally.Counter
 	errQueryIDsIndexDisabled            tally.Counter
 	errWriteTaggedIndexDisabled         tally.Counter
 }
@@ -134,6 +144,8 @@ func newDatabaseMetrics(scope tally.Scope) databaseMetrics {
 		unknownNamespaceFetchBlocks:         unknownNamespaceScope.Counter("fetch-blocks"),
 		unknownNamespaceFetchBlocksMetadata: unknownNamespaceScope.Counter("fetch-blocks-metadata"),
 		unknownNamespaceQueryIDs:            unknownNamespaceScope.Counter("query-ids"),
+		unknownNamespaceAggregateQuery:      unknownNamespaceScope.Counter("aggregate-query"),
+		unknownNamespaceCardinality:         unknownNamespaceScope.Counter("cardinality"),
 		errQueryIDsIndexDisabled:            indexDisabledScope.Counter("err-query-ids"),
 		errWriteTaggedIndexDisabled:         indexDisabledScope.Counter("err-write-tagged"),
 	}
@@ -216,9 +228,16 @@ func NewDatabase(
 	}
 	d.mediator = mediator
 
+	// Keep the quota limits enforced up to date with the runtime options
+	d.runtimeOptsListener = opts.RuntimeOptionsManager().RegisterListener(d)
+
 	return d, nil
 }
 
+func (d *db) SetRuntimeOptions(value runtime.Options) {
+	d.opts.QuotaEnforcer().SetLimits(value.QuotaLimits())
+}
+
 func (d *db) UpdateOwnedNamespaces(newNamespaces namespace.Map) error {
 	d.Lock()
 	defer d.Unlock()
@@ -489,6 +508,11 @@ func (d *db) terminateWithLock() error {
 		return err
 	}
 
+	// stop listening for runtime options changes
+	if d.runtimeOptsListener != nil {
+		d.runtimeOptsListener.Close()
+	}
+
 	// Stop the wired list
 	if wiredList := d.opts.DatabaseBlockOptions().WiredList(); wiredList != nil {
 		err := wiredList.Stop()
@@ -736,6 +760,39 @@ func (d *db) QueryIDs(
 	return n.QueryIDs(ctx, query, opts)
 }
 
+func (d *db) AggregateQuery(
+	ctx context.Context,
+	namespace ident.ID,
+	query index.Query,
+	opts index.AggregateQueryOptions,
+) (index.AggregateQueryResults, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceAggregateQuery.Inc(1)
+		return index.AggregateQueryResults{}, err
+	}
+
+	return n.AggregateQuery(ctx, query, opts)
+}
+
+func (d *db) Cardinality(
+	namespace ident.ID,
+	opts cardinality.ReportOptions,
+) (cardinality.Report, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceCardinality.Inc(1)
+		return cardinality.Report{}, err
+	}
+
+	idx, err := n.GetIndex()
+	if err != nil {
+		return cardinality.Report{}, err
+	}
+
+	return idx.Cardinality(opts)
+}
+
 func (d *db) ReadEncoded(
 	ctx context.Context,
 	namespace ident.ID,
@@ -864,6 +921,13 @@ func (d *db) Truncate(namespace ident.ID) (int64, error) {
 	return n.Truncate()
 }
 
+func (d *db) Snapshot() (SnapshotResult, error) {
+	if !d.IsBootstrapped() {
+		return SnapshotResult{}, errDatabaseNotBootstrapped
+	}
+	return d.mediator.Snapshot()
+}
+
 func (d *db) IsOverloaded() bool {
 	return d.errors.Count(d.errWindow) > d.errThreshold
 }
@@ -0,0 +1,770 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/downsample"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3x/checked"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+	xlog "github.com/m3db/m3x/log"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	errDownsampleOperationsInProgress = errors.New("downsample operations already in progress")
+)
+
+type downsampleManagerMetrics struct {
+	blocks tally.Counter
+	series tally.Counter
+	errors tally.Counter
+}
+
+func newDownsampleManagerMetrics(scope tally.Scope) downsampleManagerMetrics {
+	subScope := scope.SubScope("downsample")
+	return downsampleManagerMetrics{
+		blocks: subScope.Counter("blocks"),
+		series: subScope.Counter("series"),
+		errors: subScope.Counter("errors"),
+	}
+}
+
+// downsampleManager rolls up the flushed blocks of source namespaces into
+// lower resolution target namespaces as described by the downsample rules.
+//
+// A target block is rolled up once the target namespace has flushed it and
+// the source namespace has flushed every block it overlaps, the rolled up
+// series are merged with any series already flushed for the target block,
+// with the series already present taking precedence. The merged fileset is
+// written to a staging directory and then moved over the flushed fileset, after
+// which the target shard's seekers are invalidated. The rolled up series are
+// inserted into the target namespace index before the fileset is replaced so
+// that they can be queried by their tags. Progress is recorded per shard in a
+// checkpoint file so that rolling up resumes from the last rolled up block
+// after a restart.
+type downsampleManager struct {
+	sync.RWMutex
+
+	database    database
+	opts        Options
+	fsOpts      fs.Options
+	stagingPM   persist.Manager
+	indexPM     persist.Manager
+	log         xlog.Logger
+	newReaderFn fsNewReaderFn
+
+	inProgress     bool
+	isDownsampling tally.Gauge
+	metrics        downsampleManagerMetrics
+}
+
+func newDownsampleManager(database database, scope tally.Scope) databaseDownsampleManager {
+	opts := database.Options()
+	return &downsampleManager{
+		database:       database,
+		opts:           opts,
+		fsOpts:         opts.CommitLogOptions().FilesystemOptions(),
+		log:            opts.InstrumentOptions().Logger(),
+		newReaderFn:    fs.NewReader,
+		isDownsampling: scope.Gauge("downsample"),
+		metrics:        newDownsampleManagerMetrics(scope),
+	}
+}
+
+func (m *downsampleManager) Downsample(t time.Time) error {
+	rules := m.opts.DownsampleRules()
+	if len(rules) == 0 {
+		return nil
+	}
+
+	m.Lock()
+	if m.inProgress {
+		m.Unlock()
+		return errDownsampleOperationsInProgress
+	}
+	m.inProgress = true
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.inProgress = false
+		m.Unlock()
+	}()
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+	namespacesByID := make(map[string]databaseNamespace, len(namespaces))
+	for _, ns := range namespaces {
+		namespacesByID[ns.ID().String()] = ns
+	}
+
+	if m.stagingPM == nil {
+		stagingFsOpts := m.fsOpts.SetFilePathPrefix(
+			fs.DownsampleStagingDirPath(m.fsOpts.FilePathPrefix()))
+		pm, err := fs.NewPersistManager(stagingFsOpts)
+		if err != nil {
+			return err
+		}
+		m.stagingPM = pm
+	}
+
+	flush, err := m.stagingPM.StartDataPersist()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, rule := range rules {
+		source, ok := namespacesByID[rule.SourceNamespace.String()]
+		if !ok {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"downsample source namespace not owned: %s", rule.SourceNamespace.String()))
+			continue
+		}
+		target, ok := namespacesByID[rule.TargetNamespace.String()]
+		if !ok {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"downsample target namespace not owned: %s", rule.TargetNamespace.String()))
+			continue
+		}
+		if err := m.downsampleRule(t, rule, source, target, flush); err != nil {
+			m.metrics.errors.Inc(1)
+			multiErr = multiErr.Add(fmt.Errorf(
+				"unable to downsample rule %s: %v", rule.String(), err))
+		}
+	}
+
+	multiErr = multiErr.Add(flush.DoneData())
+	return multiErr.FinalError()
+}
+
+func (m *downsampleManager) Report() {
+	m.RLock()
+	inProgress := m.inProgress
+	m.RUnlock()
+
+	if inProgress {
+		m.isDownsampling.Update(1)
+	} else {
+		m.isDownsampling.Update(0)
+	}
+}
+
+func (m *downsampleManager) downsampleRule(
+	t time.Time,
+	rule downsample.Rule,
+	source databaseNamespace,
+	target databaseNamespace,
+	flush persist.DataFlush,
+) error {
+	var (
+		targetRetention = target.Options().RetentionOptions()
+		targetBlockSize = targetRetention.BlockSize()
+		earliest        = retention.FlushTimeStart(targetRetention, t)
+		latest          = retention.FlushTimeEnd(targetRetention, t)
+	)
+	if targetBlockSize%rule.Resolution != 0 {
+		return fmt.Errorf("target namespace block size %s is not a multiple of resolution %s",
+			targetBlockSize.String(), rule.Resolution.String())
+	}
+
+	targetMetadata, err := namespace.NewMetadata(target.ID(), target.Options())
+	if err != nil {
+		return err
+	}
+
+	checkpointFilePath := fs.DownsampleCheckpointFilePath(m.fsOpts.FilePathPrefix(),
+		rule.SourceNamespace, rule.TargetNamespace)
+	checkpoint, err := downsample.ReadCheckpoint(checkpointFilePath)
+	if err != nil {
+		return err
+	}
+
+	sourceShards := make(map[uint32]databaseShard)
+	for _, shard := range source.GetOwnedShards() {
+		sourceShards[shard.ID()] = shard
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, targetShard := range target.GetOwnedShards() {
+		sourceShard, ok := sourceShards[targetShard.ID()]
+		if !ok {
+			continue
+		}
+
+		start := earliest
+		if last, ok := checkpoint.LastBlockStart(targetShard.ID()); ok && !last.Before(start) {
+			start = last.Add(targetBlockSize)
+		}
+
+		// Roll up blocks in order and stop at the first block that is not ready
+		// so that the checkpoint always marks a contiguous range of blocks.
+		for blockStart := start; !blockStart.After(latest); blockStart = blockStart.Add(targetBlockSize) {
+			sourceBlockStarts, ready := m.sourceBlockStartsIfReady(t, source,
+				sourceShard, targetShard, blockStart, targetBlockSize)
+			if !ready {
+				break
+			}
+
+			if len(sourceBlockStarts) > 0 {
+				err := m.downsampleBlock(rule, target, targetMetadata, targetShard,
+					blockStart, targetBlockSize, sourceBlockStarts, flush)
+				if err != nil {
+					multiErr = multiErr.Add(err)
+					break
+				}
+				m.metrics.blocks.Inc(1)
+				m.log.WithFields(
+					xlog.NewField("rule", rule.String()),
+					xlog.NewField("shard", targetShard.ID()),
+					xlog.NewField("blockStart", blockStart.String()),
+				).Debug("rolled up block")
+			}
+
+			checkpoint.SetLastBlockStart(targetShard.ID(), blockStart)
+			err := downsample.WriteCheckpoint(checkpointFilePath, checkpoint,
+				m.fsOpts.NewFileMode(), m.fsOpts.NewDirectoryMode())
+			if err != nil {
+				return multiErr.Add(err).FinalError()
+			}
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+// sourceBlockStartsIfReady returns the start of every source block that
+// overlaps the target block and is still within retention, along with
+// whether the target block is ready to be rolled up.
+func (m *downsampleManager) sourceBlockStartsIfReady(
+	t time.Time,
+	source databaseNamespace,
+	sourceShard databaseShard,
+	targetShard databaseShard,
+	blockStart time.Time,
+	blockSize time.Duration,
+) ([]time.Time, bool) {
+	// The target namespace flushes every block within retention, wait for it
+	// to do so that the rolled up block does not collide with a flush.
+	if targetShard.FlushState(blockStart).Status != fileOpSuccess {
+		return nil, false
+	}
+
+	var (
+		sourceRetention = source.Options().RetentionOptions()
+		sourceBlockSize = sourceRetention.BlockSize()
+		earliest        = retention.FlushTimeStart(sourceRetention, t)
+		latest          = retention.FlushTimeEnd(sourceRetention, t)
+		blockEnd        = blockStart.Add(blockSize)
+		result          []time.Time
+	)
+	for curr := blockStart.Truncate(sourceBlockSize); curr.Before(blockEnd); curr = curr.Add(sourceBlockSize) {
+		if curr.Before(earliest) {
+			// Out of retention for the source namespace.
+			continue
+		}
+		if curr.After(latest) {
+			return nil, false
+		}
+		if sourceShard.FlushState(curr).Status != fileOpSuccess {
+			return nil, false
+		}
+		result = append(result, curr)
+	}
+	return result, true
+}
+
+type downsampleSeries struct {
+	id   ident.ID
+	tags ident.Tags
+
+	// rollup is set for series being rolled up rather than series that were
+	// already present in the target block, sourceBlocks holds the indexes of
+	// the source blocks that hold data for the series.
+	rollup       bool
+	sourceBlocks []int
+}
+
+func (s *downsampleSeries) finalize() {
+	// Tags may reference the bytes of the ID so finalize them first.
+	s.tags.Finalize()
+	s.id.Finalize()
+}
+
+func (m *downsampleManager) downsampleBlock(
+	rule downsample.Rule,
+	target databaseNamespace,
+	targetMetadata namespace.Metadata,
+	targetShard databaseShard,
+	blockStart time.Time,
+	blockSize time.Duration,
+	sourceBlockStarts []time.Time,
+	flush persist.DataFlush,
+) error {
+	var (
+		shard = targetShard.ID()
+		// NB: Only the IDs and tags of the series are held for the whole block
+		// since the writer references them until it is closed, the data of each
+		// series is persisted as soon as it has been read or rolled up.
+		series = make(map[string]*downsampleSeries)
+	)
+	defer func() {
+		for _, s := range series {
+			s.finalize()
+		}
+	}()
+
+	prepared, err := flush.PrepareData(persist.DataPrepareOptions{
+		NamespaceMetadata: targetMetadata,
+		Shard:             shard,
+		BlockStart:        blockStart,
+		DeleteIfExists:    true,
+	})
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	docs, err := m.persistBlock(rule, targetMetadata, shard, blockStart,
+		blockSize, sourceBlockStarts, series, prepared)
+	if err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	if err := prepared.Close(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	if err := multiErr.FinalError(); err != nil {
+		return err
+	}
+
+	// Index the rolled up series before replacing the flushed fileset so that
+	// a failure leaves the block to be rolled up again rather than leaving
+	// rolled up series that are never indexed.
+	if len(docs) > 0 && targetMetadata.Options().IndexOptions().Enabled() {
+		if err := m.indexBlock(target, targetMetadata, shard, blockStart, docs); err != nil {
+			return err
+		}
+	}
+
+	// NB: The flushed fileset may be open by seekers and other readers so it
+	// is replaced by moving the staged fileset over it rather than by
+	// rewriting it in place.
+	err = fs.MoveDataFileSet(fs.DownsampleStagingDirPath(m.fsOpts.FilePathPrefix()),
+		m.fsOpts.FilePathPrefix(), targetMetadata.ID(), shard, blockStart,
+		m.fsOpts.NewDirectoryMode())
+	if err != nil {
+		return err
+	}
+
+	return targetShard.InvalidateBlock(blockStart)
+}
+
+// persistBlock persists the series already flushed for the target block and
+// the series rolled up from the source blocks, returning the documents of the
+// rolled up series.
+func (m *downsampleManager) persistBlock(
+	rule downsample.Rule,
+	targetMetadata namespace.Metadata,
+	shard uint32,
+	blockStart time.Time,
+	blockSize time.Duration,
+	sourceBlockStarts []time.Time,
+	series map[string]*downsampleSeries,
+	prepared persist.PreparedDataPersist,
+) ([]doc.Document, error) {
+	idPool := m.opts.IdentifierPool()
+
+	// Series already flushed for the target block take precedence over
+	// rolled up series and are copied as they are read.
+	exists, err := fs.DataFileSetExistsAt(m.fsOpts.FilePathPrefix(),
+		targetMetadata.ID(), shard, blockStart)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		err := m.readFileSet(targetMetadata.ID(), shard, blockStart, func(
+			id ident.ID,
+			tagsIter ident.TagIterator,
+			data checked.Bytes,
+			checksum uint32,
+		) error {
+			seg := ts.NewSegment(data, nil, ts.FinalizeHead)
+			defer seg.Finalize()
+
+			tags, err := convert.TagsFromTagsIter(id, tagsIter, idPool)
+			tagsIter.Close()
+			if err != nil {
+				id.Finalize()
+				return err
+			}
+			series[id.String()] = &downsampleSeries{id: id, tags: tags}
+			return prepared.Persist(id, tags, seg, checksum)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only read the metadata of the source blocks to find the series to roll
+	// up, their data is read one series at a time below.
+	for i, sourceBlockStart := range sourceBlockStarts {
+		err := m.readFileSetMetadata(rule.SourceNamespace, shard, sourceBlockStart, func(
+			id ident.ID,
+			tagsIter ident.TagIterator,
+		) error {
+			if s, ok := series[id.String()]; ok {
+				id.Finalize()
+				tagsIter.Close()
+				if s.rollup {
+					s.sourceBlocks = append(s.sourceBlocks, i)
+				}
+				return nil
+			}
+
+			tags, err := convert.TagsFromTagsIter(id, tagsIter, idPool)
+			tagsIter.Close()
+			if err != nil {
+				id.Finalize()
+				return err
+			}
+			series[id.String()] = &downsampleSeries{
+				id:           id,
+				tags:         tags,
+				rollup:       true,
+				sourceBlocks: []int{i},
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seekers := make([]fs.DataFileSetSeeker, 0, len(sourceBlockStarts))
+	defer func() {
+		for _, seeker := range seekers {
+			seeker.Close()
+		}
+	}()
+	for _, sourceBlockStart := range sourceBlockStarts {
+		seeker := fs.NewSeeker(m.fsOpts.FilePathPrefix(),
+			m.fsOpts.DataReaderBufferSize(), m.fsOpts.InfoReaderBufferSize(),
+			m.fsOpts.SeekReaderBufferSize(), m.opts.BytesPool(), false, nil, m.fsOpts)
+		if err := seeker.Open(rule.SourceNamespace, shard, sourceBlockStart); err != nil {
+			return nil, err
+		}
+		seekers = append(seekers, seeker)
+	}
+
+	var (
+		docs      []doc.Document
+		blockEnd  = blockStart.Add(blockSize)
+		encoder   = m.opts.EncoderPool().Get()
+		iter      = m.opts.ReaderIteratorPool().Get()
+		segReader = xio.NewSegmentReader(ts.Segment{})
+	)
+	defer func() {
+		encoder.Close()
+		iter.Close()
+	}()
+
+	for _, s := range series {
+		if !s.rollup {
+			continue
+		}
+
+		var (
+			aggregator = downsample.NewAggregator(rule.Resolution, rule.Aggregation)
+			unit       xtime.Unit
+		)
+		encoder.Reset(blockStart, 0)
+		for _, idx := range s.sourceBlocks {
+			data, err := seekers[idx].SeekByID(s.id)
+			if err != nil {
+				return nil, err
+			}
+
+			seg := ts.NewSegment(data, nil, ts.FinalizeHead)
+			segReader.Reset(seg)
+			iter.Reset(segReader)
+			for iter.Next() {
+				dp, dpUnit, _ := iter.Current()
+				if dp.Timestamp.Before(blockStart) || !dp.Timestamp.Before(blockEnd) {
+					continue
+				}
+				unit = dpUnit
+				if result, ok := aggregator.Add(dp); ok {
+					if err := encoder.Encode(result, unit, nil); err != nil {
+						seg.Finalize()
+						return nil, err
+					}
+				}
+			}
+			err = iter.Err()
+			seg.Finalize()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if result, ok := aggregator.Flush(); ok {
+			if err := encoder.Encode(result, unit, nil); err != nil {
+				return nil, err
+			}
+		}
+		if encoder.NumEncoded() == 0 {
+			continue
+		}
+
+		seg := encoder.DiscardReset(blockStart, 0)
+		err := prepared.Persist(s.id, s.tags, seg, digest.SegmentChecksum(seg))
+		seg.Finalize()
+		if err != nil {
+			return nil, err
+		}
+		m.metrics.series.Inc(1)
+
+		d, err := convert.FromMetric(s.id, s.tags)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, d)
+	}
+
+	return docs, nil
+}
+
+// indexBlock inserts the documents of the rolled up series into the target
+// namespace index. If the index block has already been flushed the documents
+// are also persisted as an additional index fileset volume for the shard,
+// otherwise they are flushed along with the rest of the index block since the
+// index flush reads the series of the block from the flushed filesets.
+func (m *downsampleManager) indexBlock(
+	target databaseNamespace,
+	targetMetadata namespace.Metadata,
+	shard uint32,
+	blockStart time.Time,
+	docs []doc.Document,
+) error {
+	indexBlockStart := blockStart.Truncate(
+		targetMetadata.Options().IndexOptions().BlockSize())
+
+	seg, err := mem.NewSegment(0, m.opts.IndexOptions().MemSegmentOptions())
+	if err != nil {
+		return err
+	}
+	if err := seg.InsertBatch(m3ninxindex.NewBatch(docs)); err != nil {
+		seg.Close()
+		return err
+	}
+	if _, err := seg.Seal(); err != nil {
+		seg.Close()
+		return err
+	}
+
+	flushed, err := fs.IndexFileSetsAt(m.fsOpts.FilePathPrefix(),
+		targetMetadata.ID(), indexBlockStart)
+	if err != nil {
+		seg.Close()
+		return err
+	}
+	if len(flushed) == 0 {
+		if err := target.AddIndexSegments(indexBlockStart, []segment.Segment{seg}); err != nil {
+			seg.Close()
+			return err
+		}
+		return nil
+	}
+
+	segments, err := m.persistIndexSegment(targetMetadata, shard, indexBlockStart, seg)
+	seg.Close()
+	if err != nil {
+		return err
+	}
+	if err := target.AddIndexSegments(indexBlockStart, segments); err != nil {
+		for _, s := range segments {
+			s.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *downsampleManager) persistIndexSegment(
+	targetMetadata namespace.Metadata,
+	shard uint32,
+	indexBlockStart time.Time,
+	seg segment.MutableSegment,
+) ([]segment.Segment, error) {
+	if m.indexPM == nil {
+		pm, err := fs.NewPersistManager(m.fsOpts)
+		if err != nil {
+			return nil, err
+		}
+		m.indexPM = pm
+	}
+
+	flush, err := m.indexPM.StartIndexPersist()
+	if err != nil {
+		return nil, err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	prepared, err := flush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: targetMetadata,
+		BlockStart:        indexBlockStart,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            map[uint32]struct{}{shard: {}},
+	})
+	if err != nil {
+		multiErr = multiErr.Add(err)
+		return nil, multiErr.Add(flush.DoneIndex()).FinalError()
+	}
+
+	if err := prepared.Persist(seg); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	segments, err := prepared.Close()
+	if err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	multiErr = multiErr.Add(flush.DoneIndex())
+	if err := multiErr.FinalError(); err != nil {
+		for _, s := range segments {
+			s.Close()
+		}
+		return nil, err
+	}
+	return segments, nil
+}
+
+func (m *downsampleManager) readFileSet(
+	nsID ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	fn func(id ident.ID, tags ident.TagIterator, data checked.Bytes, checksum uint32) error,
+) error {
+	reader, err := m.newReaderFn(m.opts.BytesPool(), m.fsOpts)
+	if err != nil {
+		return err
+	}
+
+	err = reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for {
+		id, tags, data, checksum, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+		if err := fn(id, tags, data, checksum); err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+	}
+
+	if err := reader.Close(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	return multiErr.FinalError()
+}
+
+func (m *downsampleManager) readFileSetMetadata(
+	nsID ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	fn func(id ident.ID, tags ident.TagIterator) error,
+) error {
+	reader, err := m.newReaderFn(m.opts.BytesPool(), m.fsOpts)
+	if err != nil {
+		return err
+	}
+
+	err = reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for {
+		id, tags, _, _, err := reader.ReadMetadata()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+		if err := fn(id, tags); err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+	}
+
+	if err := reader.Close(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	return multiErr.FinalError()
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+)
+
+// Aggregator aggregates time ordered datapoints into buckets of a fixed
+// resolution. Aggregated datapoints are timestamped at the start of their
+// bucket so they always fall within the block of the datapoints that
+// produced them.
+type Aggregator struct {
+	resolution  time.Duration
+	aggregation AggregationType
+
+	bucketStart time.Time
+	count       int64
+	sum         float64
+	min         float64
+	max         float64
+	last        float64
+}
+
+// NewAggregator returns a new aggregator.
+func NewAggregator(
+	resolution time.Duration,
+	aggregation AggregationType,
+) *Aggregator {
+	return &Aggregator{
+		resolution:  resolution,
+		aggregation: aggregation,
+	}
+}
+
+// Add adds a datapoint, the datapoint must not be earlier than any of the
+// previously added datapoints. If the datapoint falls into a new bucket the
+// aggregated datapoint for the previous bucket is returned along with true.
+func (a *Aggregator) Add(dp ts.Datapoint) (ts.Datapoint, bool) {
+	var (
+		bucketStart = dp.Timestamp.Truncate(a.resolution)
+		result      ts.Datapoint
+		ok          bool
+	)
+	if a.count > 0 && !bucketStart.Equal(a.bucketStart) {
+		result, ok = a.Flush()
+	}
+	if a.count == 0 {
+		a.bucketStart = bucketStart
+		a.min = math.Inf(1)
+		a.max = math.Inf(-1)
+	}
+	a.count++
+	a.sum += dp.Value
+	a.min = math.Min(a.min, dp.Value)
+	a.max = math.Max(a.max, dp.Value)
+	a.last = dp.Value
+	return result, ok
+}
+
+// Flush returns the aggregated datapoint for the current bucket along with
+// true if any datapoints have been added since the last flush.
+func (a *Aggregator) Flush() (ts.Datapoint, bool) {
+	if a.count == 0 {
+		return ts.Datapoint{}, false
+	}
+	result := ts.Datapoint{
+		Timestamp: a.bucketStart,
+		Value:     a.value(),
+	}
+	a.Reset()
+	return result, true
+}
+
+// Reset resets the aggregator dropping any datapoints not yet flushed.
+func (a *Aggregator) Reset() {
+	a.bucketStart = time.Time{}
+	a.count = 0
+	a.sum = 0
+	a.min = 0
+	a.max = 0
+	a.last = 0
+}
+
+func (a *Aggregator) value() float64 {
+	switch a.aggregation {
+	case Sum:
+		return a.sum
+	case Min:
+		return a.min
+	case Max:
+		return a.max
+	case Last:
+		return a.last
+	case Count:
+		return float64(a.count)
+	case Mean:
+		return a.sum / float64(a.count)
+	}
+	return math.NaN()
+}
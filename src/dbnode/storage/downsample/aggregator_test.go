@@ -0,0 +1,88 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatorAggregations(t *testing.T) {
+	start := time.Unix(0, 0).Add(10 * time.Hour)
+	dps := []ts.Datapoint{
+		{Timestamp: start, Value: 3},
+		{Timestamp: start.Add(10 * time.Minute), Value: 1},
+		{Timestamp: start.Add(50 * time.Minute), Value: 2},
+		{Timestamp: start.Add(2 * time.Hour), Value: 5},
+	}
+
+	tests := []struct {
+		aggregation AggregationType
+		expected    []float64
+	}{
+		{aggregation: Sum, expected: []float64{6, 5}},
+		{aggregation: Min, expected: []float64{1, 5}},
+		{aggregation: Max, expected: []float64{3, 5}},
+		{aggregation: Last, expected: []float64{2, 5}},
+		{aggregation: Count, expected: []float64{3, 1}},
+		{aggregation: Mean, expected: []float64{2, 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.aggregation.String(), func(t *testing.T) {
+			agg := NewAggregator(time.Hour, test.aggregation)
+
+			var results []ts.Datapoint
+			for _, dp := range dps {
+				if result, ok := agg.Add(dp); ok {
+					results = append(results, result)
+				}
+			}
+			result, ok := agg.Flush()
+			require.True(t, ok)
+			results = append(results, result)
+
+			require.Equal(t, 2, len(results))
+			require.True(t, start.Equal(results[0].Timestamp))
+			require.True(t, start.Add(2*time.Hour).Equal(results[1].Timestamp))
+			for i, expected := range test.expected {
+				require.Equal(t, expected, results[i].Value)
+			}
+
+			_, ok = agg.Flush()
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestAggregatorReset(t *testing.T) {
+	agg := NewAggregator(time.Hour, Sum)
+	_, ok := agg.Add(ts.Datapoint{Timestamp: time.Unix(0, 0), Value: 1})
+	require.False(t, ok)
+
+	agg.Reset()
+	_, ok = agg.Flush()
+	require.False(t, ok)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	xtime "github.com/m3db/m3x/time"
+)
+
+// Checkpoint records, per shard, the start of the last target block that
+// has been rolled up so that rolling up can resume after a restart.
+type Checkpoint struct {
+	shards map[uint32]xtime.UnixNano
+}
+
+type checkpointJSON struct {
+	Shards map[uint32]int64 `json:"shards"`
+}
+
+// NewCheckpoint returns a new empty checkpoint.
+func NewCheckpoint() Checkpoint {
+	return Checkpoint{shards: make(map[uint32]xtime.UnixNano)}
+}
+
+// LastBlockStart returns the start of the last rolled up block for a shard
+// and true, or false if no block has been rolled up for the shard.
+func (c Checkpoint) LastBlockStart(shard uint32) (time.Time, bool) {
+	v, ok := c.shards[shard]
+	if !ok {
+		return time.Time{}, false
+	}
+	return v.ToTime(), true
+}
+
+// SetLastBlockStart sets the start of the last rolled up block for a shard.
+func (c Checkpoint) SetLastBlockStart(shard uint32, blockStart time.Time) {
+	c.shards[shard] = xtime.ToUnixNano(blockStart)
+}
+
+// ReadCheckpoint reads a checkpoint from a file, returning an empty
+// checkpoint if the file does not exist.
+func ReadCheckpoint(filePath string) (Checkpoint, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return NewCheckpoint(), nil
+	}
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	var decoded checkpointJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return Checkpoint{}, err
+	}
+
+	result := NewCheckpoint()
+	for shard, blockStart := range decoded.Shards {
+		result.shards[shard] = xtime.UnixNano(blockStart)
+	}
+	return result, nil
+}
+
+// WriteCheckpoint atomically writes a checkpoint to a file by writing it
+// to a temporary file and renaming it into place.
+func WriteCheckpoint(
+	filePath string,
+	checkpoint Checkpoint,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) error {
+	encoded := checkpointJSON{Shards: make(map[uint32]int64, len(checkpoint.shards))}
+	for shard, blockStart := range checkpoint.shards {
+		encoded.Shards[shard] = int64(blockStart)
+	}
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), newDirectoryMode); err != nil {
+		return err
+	}
+
+	tmpFilePath := filePath + ".tmp"
+	fd, err := os.OpenFile(tmpFilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, newFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, filePath)
+}
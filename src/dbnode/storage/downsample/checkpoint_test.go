@@ -0,0 +1,77 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointReadWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "downsample-checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePath := path.Join(dir, "nested", "checkpoint.json")
+
+	// Reading a checkpoint that does not exist returns an empty checkpoint.
+	checkpoint, err := ReadCheckpoint(filePath)
+	require.NoError(t, err)
+	_, ok := checkpoint.LastBlockStart(1)
+	require.False(t, ok)
+
+	blockStart := time.Unix(0, 0).Add(24 * time.Hour)
+	checkpoint.SetLastBlockStart(1, blockStart)
+	checkpoint.SetLastBlockStart(3, blockStart.Add(24*time.Hour))
+	require.NoError(t, WriteCheckpoint(filePath, checkpoint, 0666, 0755))
+
+	read, err := ReadCheckpoint(filePath)
+	require.NoError(t, err)
+
+	last, ok := read.LastBlockStart(1)
+	require.True(t, ok)
+	require.True(t, blockStart.Equal(last))
+
+	last, ok = read.LastBlockStart(3)
+	require.True(t, ok)
+	require.True(t, blockStart.Add(24*time.Hour).Equal(last))
+
+	_, ok = read.LastBlockStart(2)
+	require.False(t, ok)
+}
+
+func TestCheckpointReadInvalid(t *testing.T) {
+	fd, err := ioutil.TempFile("", "downsample-checkpoint")
+	require.NoError(t, err)
+	defer os.Remove(fd.Name())
+
+	_, err = fd.Write([]byte("not json"))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	_, err = ReadCheckpoint(fd.Name())
+	require.Error(t, err)
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package downsample contains the rules and helpers used to roll up flushed
+// blocks of a namespace into a lower resolution namespace.
+package downsample
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+)
+
+// AggregationType is the aggregation applied to the datapoints that fall
+// within a single resolution bucket.
+type AggregationType int
+
+const (
+	// UnknownAggregationType is an unknown aggregation type.
+	UnknownAggregationType AggregationType = iota
+
+	// Sum is the sum of the datapoints.
+	Sum
+
+	// Min is the minimum of the datapoints.
+	Min
+
+	// Max is the maximum of the datapoints.
+	Max
+
+	// Last is the last datapoint.
+	Last
+
+	// Count is the number of datapoints.
+	Count
+
+	// Mean is the mean of the datapoints.
+	Mean
+)
+
+var validAggregationTypes = []AggregationType{
+	Sum,
+	Min,
+	Max,
+	Last,
+	Count,
+	Mean,
+}
+
+var (
+	errAggregationTypeUnspecified = errors.New("aggregation type not specified")
+	errRuleSourceNamespaceNotSet  = errors.New("downsample rule source namespace not set")
+	errRuleTargetNamespaceNotSet  = errors.New("downsample rule target namespace not set")
+	errRuleSameNamespace          = errors.New("downsample rule source and target namespace must differ")
+	errRuleInvalidResolution      = errors.New("downsample rule resolution must be positive")
+)
+
+// String returns the aggregation type as a string.
+func (t AggregationType) String() string {
+	switch t {
+	case Sum:
+		return "sum"
+	case Min:
+		return "min"
+	case Max:
+		return "max"
+	case Last:
+		return "last"
+	case Count:
+		return "count"
+	case Mean:
+		return "mean"
+	}
+	return "unknown"
+}
+
+// ValidAggregationTypes returns a copy of the valid aggregation types.
+func ValidAggregationTypes() []AggregationType {
+	result := make([]AggregationType, len(validAggregationTypes))
+	copy(result, validAggregationTypes)
+	return result
+}
+
+// Validate returns nil when the aggregation type is valid, otherwise
+// it returns an error.
+func (t AggregationType) Validate() error {
+	for _, valid := range validAggregationTypes {
+		if valid == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid aggregation type: %d", int(t))
+}
+
+// ParseAggregationType parses an aggregation type from a string.
+func ParseAggregationType(str string) (AggregationType, error) {
+	strs := make([]string, 0, len(validAggregationTypes))
+	for _, valid := range validAggregationTypes {
+		if str == valid.String() {
+			return valid, nil
+		}
+		strs = append(strs, "'"+valid.String()+"'")
+	}
+	return UnknownAggregationType, fmt.Errorf(
+		"invalid aggregation type '%s' valid types are: %s",
+		str, strings.Join(strs, ", "))
+}
+
+// UnmarshalYAML unmarshals an aggregation type from a string.
+func (t *AggregationType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		return errAggregationTypeUnspecified
+	}
+	v, err := ParseAggregationType(str)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Rule describes rolling up the flushed blocks of a source namespace into
+// a target namespace at a lower resolution.
+type Rule struct {
+	// SourceNamespace is the namespace flushed blocks are read from.
+	SourceNamespace ident.ID
+
+	// TargetNamespace is the namespace rolled up blocks are written to.
+	TargetNamespace ident.ID
+
+	// Resolution is the size of the buckets datapoints are aggregated into.
+	Resolution time.Duration
+
+	// Aggregation is the aggregation applied to each bucket.
+	Aggregation AggregationType
+}
+
+// Validate validates the rule.
+func (r Rule) Validate() error {
+	if r.SourceNamespace == nil {
+		return errRuleSourceNamespaceNotSet
+	}
+	if r.TargetNamespace == nil {
+		return errRuleTargetNamespaceNotSet
+	}
+	if r.SourceNamespace.Equal(r.TargetNamespace) {
+		return errRuleSameNamespace
+	}
+	if r.Resolution <= 0 {
+		return errRuleInvalidResolution
+	}
+	return r.Aggregation.Validate()
+}
+
+// String returns a description of the rule.
+func (r Rule) String() string {
+	return fmt.Sprintf("%s->%s@%s:%s", r.SourceNamespace.String(),
+		r.TargetNamespace.String(), r.Resolution.String(), r.Aggregation.String())
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestParseAggregationType(t *testing.T) {
+	for _, valid := range ValidAggregationTypes() {
+		parsed, err := ParseAggregationType(valid.String())
+		require.NoError(t, err)
+		require.Equal(t, valid, parsed)
+	}
+
+	_, err := ParseAggregationType("p99")
+	require.Error(t, err)
+}
+
+func TestAggregationTypeUnmarshalYAML(t *testing.T) {
+	var cfg struct {
+		Aggregation AggregationType `yaml:"aggregation"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("aggregation: max\n"), &cfg))
+	require.Equal(t, Max, cfg.Aggregation)
+
+	require.Error(t, yaml.Unmarshal([]byte("aggregation: median\n"), &cfg))
+}
+
+func TestRuleValidate(t *testing.T) {
+	valid := Rule{
+		SourceNamespace: ident.StringID("raw"),
+		TargetNamespace: ident.StringID("agg"),
+		Resolution:      time.Hour,
+		Aggregation:     Sum,
+	}
+	require.NoError(t, valid.Validate())
+
+	rule := valid
+	rule.SourceNamespace = nil
+	require.Error(t, rule.Validate())
+
+	rule = valid
+	rule.TargetNamespace = nil
+	require.Error(t, rule.Validate())
+
+	rule = valid
+	rule.TargetNamespace = ident.StringID("raw")
+	require.Error(t, rule.Validate())
+
+	rule = valid
+	rule.Resolution = 0
+	require.Error(t, rule.Validate())
+
+	rule = valid
+	rule.Aggregation = UnknownAggregationType
+	require.Error(t, rule.Validate())
+}
@@ -0,0 +1,413 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/runtime"
+	"github.com/m3db/m3/src/dbnode/storage/downsample"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/context"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+var (
+	testDownsampleSourceID = ident.StringID("raw")
+	testDownsampleTargetID = ident.StringID("agg")
+)
+
+type testDownsampleSeries struct {
+	id  string
+	dps []ts.Datapoint
+}
+
+func newTestDownsampleOptions(t *testing.T, dir string) Options {
+	opts := testDatabaseOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir).
+		SetRuntimeOptionsManager(runtime.NewNoOpOptionsManager(runtime.NewOptions()))
+	return opts.
+		SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts)).
+		SetDownsampleRules([]downsample.Rule{
+			{
+				SourceNamespace: testDownsampleSourceID,
+				TargetNamespace: testDownsampleTargetID,
+				Resolution:      time.Hour,
+				Aggregation:     downsample.Sum,
+			},
+		})
+}
+
+func writeTestDownsampleFileSet(
+	t *testing.T,
+	opts Options,
+	nsID ident.ID,
+	blockStart time.Time,
+	blockSize time.Duration,
+	series []testDownsampleSeries,
+) {
+	writer, err := fs.NewWriter(opts.CommitLogOptions().FilesystemOptions())
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Open(fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      0,
+			BlockStart: blockStart,
+		},
+		BlockSize:   blockSize,
+		FileSetType: persist.FileSetFlushType,
+	}))
+
+	for _, s := range series {
+		encoder := opts.EncoderPool().Get()
+		encoder.Reset(blockStart, 0)
+		for _, dp := range s.dps {
+			require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		}
+		segment := encoder.Discard()
+		err := writer.WriteAll(ident.StringID(s.id), ident.Tags{},
+			[]checked.Bytes{segment.Head, segment.Tail}, digest.SegmentChecksum(segment))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+}
+
+func readTestDownsampleFileSet(
+	t *testing.T,
+	opts Options,
+	nsID ident.ID,
+	blockStart time.Time,
+) map[string][]ts.Datapoint {
+	reader, err := fs.NewReader(nil, opts.CommitLogOptions().FilesystemOptions())
+	require.NoError(t, err)
+
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      0,
+			BlockStart: blockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+
+	result := make(map[string][]ts.Datapoint)
+	for {
+		id, tags, data, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		tags.Close()
+
+		result[id.String()] = decodeTestDownsampleSegment(t, opts,
+			ts.NewSegment(data, nil, ts.FinalizeNone))
+	}
+	return result
+}
+
+func streamTestDownsampleSeries(
+	t *testing.T,
+	opts Options,
+	retriever fs.DataBlockRetriever,
+	id string,
+	blockStart time.Time,
+) []ts.Datapoint {
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	reader, err := retriever.Stream(ctx, 0, ident.StringID(id), blockStart, nil)
+	require.NoError(t, err)
+	segment, err := reader.Segment()
+	require.NoError(t, err)
+	return decodeTestDownsampleSegment(t, opts, segment)
+}
+
+func decodeTestDownsampleSegment(
+	t *testing.T,
+	opts Options,
+	segment ts.Segment,
+) []ts.Datapoint {
+	iter := opts.ReaderIteratorPool().Get()
+	defer iter.Close()
+
+	iter.Reset(xio.NewSegmentReader(segment))
+	var dps []ts.Datapoint
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		dps = append(dps, dp)
+	}
+	require.NoError(t, iter.Err())
+	return dps
+}
+
+func TestDownsampleManagerDownsample(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "downsample")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts            = newTestDownsampleOptions(t, dir)
+		epoch           = time.Unix(0, 0)
+		now             = epoch.Add(20*24*time.Hour + 12*time.Hour)
+		sourceBlockSize = 2 * time.Hour
+		targetBlockSize = 24 * time.Hour
+		sourceOpts      = namespace.NewOptions().SetRetentionOptions(retention.NewOptions().
+				SetBlockSize(sourceBlockSize).SetRetentionPeriod(48 * time.Hour))
+		targetOpts = namespace.NewOptions().SetRetentionOptions(retention.NewOptions().
+				SetBlockSize(targetBlockSize).SetRetentionPeriod(10 * 24 * time.Hour)).
+				SetIndexOptions(namespace.NewIndexOptions().
+					SetEnabled(true).SetBlockSize(targetBlockSize))
+		targetBlockStart = epoch.Add(19 * 24 * time.Hour)
+		sourceStart      = retention.FlushTimeStart(sourceOpts.RetentionOptions(), now)
+		sourceEnd        = retention.FlushTimeEnd(sourceOpts.RetentionOptions(), now)
+	)
+
+	// Write every source block within retention, only two of them have data.
+	for blockStart := sourceStart; !blockStart.After(sourceEnd); blockStart = blockStart.Add(sourceBlockSize) {
+		var series []testDownsampleSeries
+		if blockStart.Equal(targetBlockStart) {
+			series = []testDownsampleSeries{
+				{
+					id: "foo",
+					dps: []ts.Datapoint{
+						{Timestamp: blockStart, Value: 42},
+					},
+				},
+				{
+					id: "bar",
+					dps: []ts.Datapoint{
+						{Timestamp: blockStart, Value: 1},
+						{Timestamp: blockStart.Add(10 * time.Minute), Value: 2},
+						{Timestamp: blockStart.Add(50 * time.Minute), Value: 3},
+						{Timestamp: blockStart.Add(65 * time.Minute), Value: 4},
+					},
+				},
+			}
+		} else if blockStart.Equal(targetBlockStart.Add(sourceBlockSize)) {
+			series = []testDownsampleSeries{
+				{
+					id: "bar",
+					dps: []ts.Datapoint{
+						{Timestamp: blockStart.Add(10 * time.Minute), Value: 5},
+					},
+				},
+			}
+		}
+		writeTestDownsampleFileSet(t, opts, testDownsampleSourceID,
+			blockStart, sourceBlockSize, series)
+	}
+
+	// The target block has already been flushed with a series that should
+	// take precedence over the rolled up series.
+	writeTestDownsampleFileSet(t, opts, testDownsampleTargetID, targetBlockStart,
+		targetBlockSize, []testDownsampleSeries{
+			{
+				id: "foo",
+				dps: []ts.Datapoint{
+					{Timestamp: targetBlockStart, Value: 100},
+				},
+			},
+		})
+
+	// Open a retriever for the target namespace and read the flushed target
+	// block so that its seekers are open when the block is rolled up.
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	fsOpts = fsOpts.SetClockOptions(fsOpts.ClockOptions().SetNowFn(func() time.Time {
+		return now
+	}))
+	targetMetadata, err := namespace.NewMetadata(testDownsampleTargetID, targetOpts)
+	require.NoError(t, err)
+	retriever := fs.NewBlockRetriever(fs.NewBlockRetrieverOptions(), fsOpts)
+	require.NoError(t, retriever.Open(targetMetadata))
+	defer retriever.Close()
+
+	foo := streamTestDownsampleSeries(t, opts, retriever, "foo", targetBlockStart)
+	require.Equal(t, 1, len(foo))
+	require.Equal(t, 0, len(streamTestDownsampleSeries(t, opts, retriever,
+		"bar", targetBlockStart)))
+
+	flushed := fileOpState{Status: fileOpSuccess}
+	sourceShard := NewMockdatabaseShard(ctrl)
+	sourceShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	sourceShard.EXPECT().FlushState(gomock.Any()).Return(flushed).AnyTimes()
+	targetShard := NewMockdatabaseShard(ctrl)
+	targetShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	targetShard.EXPECT().FlushState(gomock.Any()).Return(flushed).AnyTimes()
+	targetShard.EXPECT().InvalidateBlock(gomock.Any()).DoAndReturn(func(blockStart time.Time) error {
+		require.True(t, targetBlockStart.Equal(blockStart))
+		return retriever.InvalidateBlock(0, blockStart)
+	})
+
+	source := NewMockdatabaseNamespace(ctrl)
+	source.EXPECT().ID().Return(testDownsampleSourceID).AnyTimes()
+	source.EXPECT().Options().Return(sourceOpts).AnyTimes()
+	source.EXPECT().GetOwnedShards().Return([]databaseShard{sourceShard}).AnyTimes()
+	target := NewMockdatabaseNamespace(ctrl)
+	target.EXPECT().ID().Return(testDownsampleTargetID).AnyTimes()
+	target.EXPECT().Options().Return(targetOpts).AnyTimes()
+	target.EXPECT().GetOwnedShards().Return([]databaseShard{targetShard}).AnyTimes()
+
+	// Only the rolled up series are inserted into the target index.
+	target.EXPECT().AddIndexSegments(gomock.Any(), gomock.Any()).DoAndReturn(func(
+		blockStart time.Time,
+		segments []segment.Segment,
+	) error {
+		require.True(t, targetBlockStart.Equal(blockStart))
+		require.Equal(t, 1, len(segments))
+		defer segments[0].Close()
+
+		require.Equal(t, int64(1), segments[0].Size())
+		contains, err := segments[0].ContainsID([]byte("bar"))
+		require.NoError(t, err)
+		require.True(t, contains)
+		return nil
+	})
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{source, target}, nil).AnyTimes()
+
+	mgr := newDownsampleManager(db, tally.NoopScope)
+	require.NoError(t, mgr.Downsample(now))
+
+	// The target index block has not been flushed so the rolled up series
+	// are left to be flushed along with the rest of the index block.
+	indexFileSets, err := fs.IndexFileSetsAt(dir, testDownsampleTargetID, targetBlockStart)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(indexFileSets))
+
+	result := readTestDownsampleFileSet(t, opts, testDownsampleTargetID, targetBlockStart)
+	require.Equal(t, 2, len(result))
+	require.Equal(t, 1, len(result["foo"]))
+	require.Equal(t, float64(100), result["foo"][0].Value)
+
+	bar := result["bar"]
+	require.Equal(t, 3, len(bar))
+	require.True(t, targetBlockStart.Equal(bar[0].Timestamp))
+	require.Equal(t, float64(6), bar[0].Value)
+	require.True(t, targetBlockStart.Add(time.Hour).Equal(bar[1].Timestamp))
+	require.Equal(t, float64(4), bar[1].Value)
+	require.True(t, targetBlockStart.Add(2*time.Hour).Equal(bar[2].Timestamp))
+	require.Equal(t, float64(5), bar[2].Value)
+
+	// The rolled up series are read through the retriever once the replaced
+	// block has been invalidated.
+	require.Equal(t, foo, streamTestDownsampleSeries(t, opts, retriever,
+		"foo", targetBlockStart))
+	require.Equal(t, bar, streamTestDownsampleSeries(t, opts, retriever,
+		"bar", targetBlockStart))
+
+	checkpointFilePath := fs.DownsampleCheckpointFilePath(dir,
+		testDownsampleSourceID, testDownsampleTargetID)
+	checkpoint, err := downsample.ReadCheckpoint(checkpointFilePath)
+	require.NoError(t, err)
+	last, ok := checkpoint.LastBlockStart(0)
+	require.True(t, ok)
+	require.True(t, targetBlockStart.Equal(last))
+
+	// Running again resumes from the checkpoint and leaves the rolled up
+	// block untouched.
+	require.NoError(t, mgr.Downsample(now))
+	require.Equal(t, result, readTestDownsampleFileSet(t, opts,
+		testDownsampleTargetID, targetBlockStart))
+}
+
+func TestDownsampleManagerWaitsForSourceFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "downsample")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts       = newTestDownsampleOptions(t, dir)
+		now        = time.Unix(0, 0).Add(20*24*time.Hour + 12*time.Hour)
+		sourceOpts = namespace.NewOptions().SetRetentionOptions(retention.NewOptions().
+				SetBlockSize(2 * time.Hour).SetRetentionPeriod(48 * time.Hour))
+		targetOpts = namespace.NewOptions().SetRetentionOptions(retention.NewOptions().
+				SetBlockSize(24 * time.Hour).SetRetentionPeriod(10 * 24 * time.Hour))
+	)
+
+	sourceShard := NewMockdatabaseShard(ctrl)
+	sourceShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	sourceShard.EXPECT().FlushState(gomock.Any()).
+		Return(fileOpState{Status: fileOpNotStarted}).AnyTimes()
+	targetShard := NewMockdatabaseShard(ctrl)
+	targetShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	targetShard.EXPECT().FlushState(gomock.Any()).
+		Return(fileOpState{Status: fileOpSuccess}).AnyTimes()
+
+	source := NewMockdatabaseNamespace(ctrl)
+	source.EXPECT().ID().Return(testDownsampleSourceID).AnyTimes()
+	source.EXPECT().Options().Return(sourceOpts).AnyTimes()
+	source.EXPECT().GetOwnedShards().Return([]databaseShard{sourceShard}).AnyTimes()
+	target := NewMockdatabaseNamespace(ctrl)
+	target.EXPECT().ID().Return(testDownsampleTargetID).AnyTimes()
+	target.EXPECT().Options().Return(targetOpts).AnyTimes()
+	target.EXPECT().GetOwnedShards().Return([]databaseShard{targetShard}).AnyTimes()
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{source, target}, nil).AnyTimes()
+
+	mgr := newDownsampleManager(db, tally.NoopScope)
+	require.NoError(t, mgr.Downsample(now))
+
+	// Blocks entirely out of the source retention are checkpointed without
+	// writing anything, the first block overlapping unflushed source blocks
+	// stops progress.
+	checkpoint, err := downsample.ReadCheckpoint(fs.DownsampleCheckpointFilePath(dir,
+		testDownsampleSourceID, testDownsampleTargetID))
+	require.NoError(t, err)
+	last, ok := checkpoint.LastBlockStart(0)
+	require.True(t, ok)
+	require.True(t, time.Unix(0, 0).Add(17*24*time.Hour).Equal(last))
+
+	exists, err := fs.DataFileSetExistsAt(dir, testDownsampleTargetID, 0,
+		time.Unix(0, 0).Add(18*24*time.Hour))
+	require.NoError(t, err)
+	require.False(t, exists)
+}
@@ -26,17 +26,37 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	xerrors "github.com/m3db/m3x/errors"
 
+	"github.com/pborman/uuid"
 	"github.com/uber-go/tally"
 )
 
 var (
 	errFlushOperationsInProgress = errors.New("flush operations already in progress")
+
+	errSnapshotDoesNotCaptureAllData = errors.New(
+		"snapshot would not capture all data, every namespace must have snapshots " +
+			"enabled and every owned shard must be bootstrapped")
 )
 
+type nextSnapshotMetadataFileIndexFn func(opts fs.Options) (int64, error)
+
+// Narrow interface so as not to expose all the functionality of the commitlog
+// to the flush manager.
+type rotatableCommitlog interface {
+	RotateLogs() (commitlog.File, error)
+}
+
+type snapshotMetadataWriter interface {
+	Write(args fs.SnapshotMetadataWriteArgs) error
+}
+
 type flushManagerState int
 
 const (
@@ -52,9 +72,14 @@ const (
 type flushManager struct {
 	sync.RWMutex
 
-	database database
-	opts     Options
-	pm       persist.Manager
+	database  database
+	commitlog rotatableCommitlog
+	opts      Options
+	pm        persist.Manager
+	nowFn     clock.NowFn
+
+	snapshotMetadataWriter          snapshotMetadataWriter
+	nextSnapshotMetadataFileIndexFn nextSnapshotMetadataFileIndexFn
 
 	// state is used to protect the flush manager against concurrent use,
 	// while isFlushing, isSnapshotting, and isIndexFlushing are more
@@ -70,12 +95,17 @@ type flushManager struct {
 	lastSuccessfulSnapshotStartTime time.Time
 }
 
-func newFlushManager(database database, scope tally.Scope) databaseFlushManager {
+func newFlushManager(
+	database database, commitLog rotatableCommitlog, scope tally.Scope) databaseFlushManager {
 	opts := database.Options()
 	return &flushManager{
 		database:                        database,
+		commitlog:                       commitLog,
 		opts:                            opts,
 		pm:                              opts.PersistManager(),
+		nowFn:                           opts.ClockOptions().NowFn(),
+		snapshotMetadataWriter:          fs.NewSnapshotMetadataWriter(opts.CommitLogOptions().FilesystemOptions()),
+		nextSnapshotMetadataFileIndexFn: fs.NextSnapshotMetadataFileIndex,
 		isFlushing:                      scope.Gauge("flush"),
 		isSnapshotting:                  scope.Gauge("snapshot"),
 		isIndexFlushing:                 scope.Gauge("index-flush"),
@@ -138,38 +168,41 @@ func (m *flushManager) Flush(
 	// shard-by-shard basis because the model we're moving towards is that once a snapshot
 	// has completed, then all data that had been received by the dbnode up until the
 	// snapshot "start time" has been persisted durably.
-	shouldSnapshot := tickStart.Sub(m.lastSuccessfulSnapshotStartTime) >= m.opts.MinimumSnapshotInterval()
+	var (
+		shouldSnapshot = tickStart.Sub(m.lastSuccessfulSnapshotStartTime) >= m.opts.MinimumSnapshotInterval()
+		snapshotID     = uuid.NewRandom()
+		snapshotTime   = tickStart
+		// rotatedCommitlog is the commit log file that became active before the
+		// snapshot began, it is only set if the snapshot will capture all of the
+		// data that was written to the commit log files that precede it.
+		rotatedCommitlog *commitlog.File
+	)
 	if shouldSnapshot {
 		m.setState(flushManagerSnapshotInProgress)
-		maxBlocksSnapshottedByNamespace := 0
-		for _, ns := range namespaces {
-			var (
-				snapshotBlockStarts     = m.namespaceSnapshotTimes(ns, tickStart)
-				shardBootstrapTimes, ok = dbBootstrapStateAtTickStart.NamespaceBootstrapStates[ns.ID().String()]
-			)
-
-			if !ok {
-				// Could happen if namespaces are added / removed.
+		if m.snapshotCapturesAllData(namespaces, tickStart, dbBootstrapStateAtTickStart) {
+			// Rotate the commit log before snapshotting so that once the snapshot
+			// completes every write in the preceding commit log files is durably
+			// persisted, which allows the bootstrap process to only replay the
+			// commit log files from the rotated one onwards and the cleanup process
+			// to delete the preceding files.
+			file, err := m.commitlog.RotateLogs()
+			if err != nil {
 				multiErr = multiErr.Add(fmt.Errorf(
-					"tried to flush ns: %s, but did not have shard bootstrap times", ns.ID().String()))
-				continue
-			}
-
-			if len(snapshotBlockStarts) > maxBlocksSnapshottedByNamespace {
-				maxBlocksSnapshottedByNamespace = len(snapshotBlockStarts)
-			}
-			for _, snapshotBlockStart := range snapshotBlockStarts {
-				err := ns.Snapshot(
-					snapshotBlockStart, tickStart, shardBootstrapTimes, flush)
-
-				if err != nil {
-					detailedErr := fmt.Errorf("namespace %s failed to snapshot data: %v",
-						ns.ID().String(), err)
-					multiErr = multiErr.Add(detailedErr)
+					"error rotating commit log before snapshot: %v", err))
+			} else {
+				rotatedCommitlog = &file
+				// Everything received before the rotation will be captured by the
+				// snapshot so we can use the time after the rotation as the snapshot
+				// time, this also ensures that we snapshot any block that could have
+				// received writes between the tick start and the rotation.
+				if now := m.nowFn(); now.After(snapshotTime) {
+					snapshotTime = now
 				}
 			}
 		}
-		m.maxBlocksSnapshottedByNamespace.Update(float64(maxBlocksSnapshottedByNamespace))
+
+		multiErr = multiErr.Add(m.snapshotNamespaces(namespaces, snapshotTime,
+			snapshotID, dbBootstrapStateAtTickStart, flush))
 	}
 
 	// mark data flush finished
@@ -178,6 +211,9 @@ func (m *flushManager) Flush(
 	if shouldSnapshot {
 		if multiErr.NumErrors() == 0 {
 			m.lastSuccessfulSnapshotStartTime = tickStart
+			if rotatedCommitlog != nil {
+				multiErr = multiErr.Add(m.writeSnapshotMetadata(snapshotID, *rotatedCommitlog))
+			}
 		}
 	}
 
@@ -206,6 +242,110 @@ func (m *flushManager) Flush(
 	return multiErr.FinalError()
 }
 
+func (m *flushManager) Snapshot(
+	t time.Time,
+	dbBootstrapState DatabaseBootstrapState,
+) (SnapshotResult, error) {
+	// ensure only a single flush or snapshot is happening at a time
+	m.Lock()
+	if m.state != flushManagerIdle {
+		m.Unlock()
+		return SnapshotResult{}, errFlushOperationsInProgress
+	}
+	m.state = flushManagerNotIdle
+	m.Unlock()
+
+	defer m.setState(flushManagerIdle)
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return SnapshotResult{}, err
+	}
+
+	// NB: A snapshot taken on demand is only useful if it is complete, i.e. it
+	// has snapshot metadata, which is only written for snapshots that capture
+	// all data.
+	if !m.snapshotCapturesAllData(namespaces, t, dbBootstrapState) {
+		return SnapshotResult{}, errSnapshotDoesNotCaptureAllData
+	}
+
+	flush, err := m.pm.StartDataPersist()
+	if err != nil {
+		return SnapshotResult{}, err
+	}
+
+	m.setState(flushManagerSnapshotInProgress)
+	rotatedCommitlog, err := m.commitlog.RotateLogs()
+	if err != nil {
+		flush.DoneData()
+		return SnapshotResult{}, fmt.Errorf(
+			"error rotating commit log before snapshot: %v", err)
+	}
+
+	var (
+		snapshotID   = uuid.NewRandom()
+		snapshotTime = t
+		multiErr     = xerrors.NewMultiError()
+	)
+	if now := m.nowFn(); now.After(snapshotTime) {
+		snapshotTime = now
+	}
+	multiErr = multiErr.Add(m.snapshotNamespaces(namespaces, snapshotTime,
+		snapshotID, dbBootstrapState, flush))
+	multiErr = multiErr.Add(flush.DoneData())
+	if err := multiErr.FinalError(); err != nil {
+		return SnapshotResult{}, err
+	}
+
+	m.lastSuccessfulSnapshotStartTime = t
+	if err := m.writeSnapshotMetadata(snapshotID, rotatedCommitlog); err != nil {
+		return SnapshotResult{}, err
+	}
+	return SnapshotResult{ID: snapshotID, Time: snapshotTime}, nil
+}
+
+func (m *flushManager) snapshotNamespaces(
+	namespaces []databaseNamespace,
+	snapshotTime time.Time,
+	snapshotID uuid.UUID,
+	dbBootstrapState DatabaseBootstrapState,
+	flush persist.DataFlush,
+) error {
+	var (
+		multiErr                        = xerrors.NewMultiError()
+		maxBlocksSnapshottedByNamespace = 0
+	)
+	for _, ns := range namespaces {
+		var (
+			snapshotBlockStarts     = m.namespaceSnapshotTimes(ns, snapshotTime)
+			shardBootstrapTimes, ok = dbBootstrapState.NamespaceBootstrapStates[ns.ID().String()]
+		)
+
+		if !ok {
+			// Could happen if namespaces are added / removed.
+			multiErr = multiErr.Add(fmt.Errorf(
+				"tried to flush ns: %s, but did not have shard bootstrap times", ns.ID().String()))
+			continue
+		}
+
+		if len(snapshotBlockStarts) > maxBlocksSnapshottedByNamespace {
+			maxBlocksSnapshottedByNamespace = len(snapshotBlockStarts)
+		}
+		for _, snapshotBlockStart := range snapshotBlockStarts {
+			err := ns.Snapshot(
+				snapshotBlockStart, snapshotTime, snapshotID, shardBootstrapTimes, flush)
+
+			if err != nil {
+				detailedErr := fmt.Errorf("namespace %s failed to snapshot data: %v",
+					ns.ID().String(), err)
+				multiErr = multiErr.Add(detailedErr)
+			}
+		}
+	}
+	m.maxBlocksSnapshottedByNamespace.Update(float64(maxBlocksSnapshottedByNamespace))
+	return multiErr.FinalError()
+}
+
 func (m *flushManager) Report() {
 	m.RLock()
 	state := m.state
@@ -296,6 +436,81 @@ func (m *flushManager) flushNamespaceWithTimes(
 	return multiErr.FinalError()
 }
 
+// snapshotCapturesAllData returns whether a snapshot started now would capture
+// all of the data held in memory, that is all namespaces have snapshots enabled
+// and all of their owned shards were bootstrapped at tick start. Shards that
+// were not bootstrapped are skipped when snapshotting, so any commit log entries
+// for them must be retained until a later snapshot includes them. Index only
+// namespaces are only captured once all of their index blocks are flushed.
+func (m *flushManager) snapshotCapturesAllData(
+	namespaces []databaseNamespace,
+	t time.Time,
+	dbBootstrapStateAtTickStart DatabaseBootstrapState,
+) bool {
+	if len(namespaces) == 0 {
+		return false
+	}
+
+	for _, ns := range namespaces {
+		if !ns.Options().SnapshotEnabled() {
+			return false
+		}
+
+		// NB: Index only namespaces do not write series data so snapshots
+		// hold none of their documents, until the index blocks are flushed
+		// those documents only exist in the commit logs.
+		if ns.Options().IndexOnly() {
+			ropts := ns.Options().RetentionOptions()
+			start := retention.FlushTimeStart(ropts, t)
+			end := t.Add(ropts.BufferFuture()).Truncate(ropts.BlockSize())
+			if ns.NeedsIndexFlush(start, end) {
+				return false
+			}
+		}
+
+		shardBootstrapStates, ok := dbBootstrapStateAtTickStart.NamespaceBootstrapStates[ns.ID().String()]
+		if !ok {
+			return false
+		}
+
+		for _, shard := range ns.GetOwnedShards() {
+			state, ok := shardBootstrapStates[shard.ID()]
+			if !ok || state != Bootstrapped {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// writeSnapshotMetadata writes out the snapshot metadata file that marks the
+// snapshot as complete, recording the commit log file that was rotated to
+// before the snapshot began so that all commit log files preceding it are
+// known to be captured by the snapshot.
+func (m *flushManager) writeSnapshotMetadata(
+	snapshotID uuid.UUID,
+	rotatedCommitlog commitlog.File,
+) error {
+	index, err := m.nextSnapshotMetadataFileIndexFn(m.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		return fmt.Errorf("error determining next snapshot metadata file index: %v", err)
+	}
+
+	err = m.snapshotMetadataWriter.Write(fs.SnapshotMetadataWriteArgs{
+		ID: fs.SnapshotMetadataIdentifier{
+			Index: index,
+			UUID:  snapshotID,
+		},
+		CommitlogIdentifier: []byte(rotatedCommitlog.FilePath),
+	})
+	if err != nil {
+		return fmt.Errorf("error writing snapshot metadata: %v", err)
+	}
+
+	return nil
+}
+
 func (m *flushManager) LastSuccessfulSnapshotStartTime() (time.Time, bool) {
 	return m.lastSuccessfulSnapshotStartTime, !m.lastSuccessfulSnapshotStartTime.IsZero()
 }
@@ -28,16 +28,51 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3x/ident"
 	xtest "github.com/m3db/m3x/test"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
 )
 
+const testRotatedCommitlogPath = "/var/lib/m3db/commitlogs/commitlog-0-1.db"
+
+type fakeRotatableCommitlog struct {
+	file         commitlog.File
+	numRotations int
+}
+
+func (f *fakeRotatableCommitlog) RotateLogs() (commitlog.File, error) {
+	f.numRotations++
+	return f.file, nil
+}
+
+type fakeSnapshotMetadataWriter struct {
+	written []fs.SnapshotMetadataWriteArgs
+}
+
+func (w *fakeSnapshotMetadataWriter) Write(args fs.SnapshotMetadataWriteArgs) error {
+	w.written = append(w.written, args)
+	return nil
+}
+
+func newTestFlushManager(db database) *flushManager {
+	fm := newFlushManager(db, &fakeRotatableCommitlog{
+		file: commitlog.File{FilePath: testRotatedCommitlogPath, Index: 1},
+	}, tally.NoopScope).(*flushManager)
+	fm.snapshotMetadataWriter = &fakeSnapshotMetadataWriter{}
+	fm.nextSnapshotMetadataFileIndexFn = func(fs.Options) (int64, error) {
+		return 0, nil
+	}
+	return fm
+}
+
 func newMultipleFlushManagerNeedsFlush(t *testing.T, ctrl *gomock.Controller) (
 	*flushManager,
 	*MockdatabaseNamespace,
@@ -47,12 +82,14 @@ func newMultipleFlushManagerNeedsFlush(t *testing.T, ctrl *gomock.Controller) (
 	namespace := NewMockdatabaseNamespace(ctrl)
 	namespace.EXPECT().Options().Return(options).AnyTimes()
 	namespace.EXPECT().ID().Return(defaultTestNs1ID).AnyTimes()
+	namespace.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
 	otherNamespace := NewMockdatabaseNamespace(ctrl)
 	otherNamespace.EXPECT().Options().Return(options).AnyTimes()
 	otherNamespace.EXPECT().ID().Return(ident.StringID("someString")).AnyTimes()
+	otherNamespace.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
 
 	db := newMockdatabase(ctrl, namespace, otherNamespace)
-	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm := newTestFlushManager(db)
 
 	return fm, namespace, otherNamespace
 }
@@ -86,7 +123,7 @@ func TestFlushManagerFlushAlreadyInProgress(t *testing.T) {
 	db.EXPECT().Options().Return(testOpts).AnyTimes()
 	db.EXPECT().GetOwnedNamespaces().Return(nil, nil).AnyTimes()
 
-	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm := newTestFlushManager(db)
 	fm.pm = mockPersistManager
 
 	now := time.Unix(0, 0)
@@ -129,7 +166,7 @@ func TestFlushManagerFlushDoneDataError(t *testing.T) {
 	db.EXPECT().Options().Return(testOpts).AnyTimes()
 	db.EXPECT().GetOwnedNamespaces().Return(nil, nil)
 
-	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm := newTestFlushManager(db)
 	fm.pm = mockPersistManager
 
 	now := time.Unix(0, 0)
@@ -155,7 +192,7 @@ func TestFlushManagerFlushDoneIndexError(t *testing.T) {
 	db.EXPECT().Options().Return(testOpts).AnyTimes()
 	db.EXPECT().GetOwnedNamespaces().Return(nil, nil)
 
-	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm := newTestFlushManager(db)
 	fm.pm = mockPersistManager
 
 	now := time.Unix(0, 0)
@@ -172,7 +209,8 @@ func TestFlushManagerSkipNamespaceIndexingDisabled(t *testing.T) {
 	ns.EXPECT().ID().Return(defaultTestNs1ID).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true).AnyTimes()
 	ns.EXPECT().Flush(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	ns.EXPECT().Snapshot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	ns.EXPECT().Snapshot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
 
 	mockFlusher := persist.NewMockDataFlush(ctrl)
 	mockFlusher.EXPECT().DoneData().Return(nil)
@@ -188,7 +226,7 @@ func TestFlushManagerSkipNamespaceIndexingDisabled(t *testing.T) {
 	db.EXPECT().Options().Return(testOpts).AnyTimes()
 	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
 
-	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm := newTestFlushManager(db)
 	fm.pm = mockPersistManager
 
 	now := time.Unix(0, 0)
@@ -210,7 +248,8 @@ func TestFlushManagerNamespaceIndexingEnabled(t *testing.T) {
 	ns.EXPECT().ID().Return(defaultTestNs1ID).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true).AnyTimes()
 	ns.EXPECT().Flush(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	ns.EXPECT().Snapshot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	ns.EXPECT().Snapshot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
 	ns.EXPECT().FlushIndex(gomock.Any()).Return(nil)
 
 	mockFlusher := persist.NewMockDataFlush(ctrl)
@@ -227,7 +266,7 @@ func TestFlushManagerNamespaceIndexingEnabled(t *testing.T) {
 	db.EXPECT().Options().Return(testOpts).AnyTimes()
 	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
 
-	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm := newTestFlushManager(db)
 	fm.pm = mockPersistManager
 
 	now := time.Unix(0, 0)
@@ -352,6 +391,7 @@ func TestFlushManagerFlushSnapshot(t *testing.T) {
 		fm, ns1, ns2 = newMultipleFlushManagerNeedsFlush(t, ctrl)
 		now          = time.Now()
 	)
+	fm.nowFn = func() time.Time { return now }
 
 	// Haven't snapshotted yet.
 	_, ok := fm.LastSuccessfulSnapshotStartTime()
@@ -376,7 +416,7 @@ func TestFlushManagerFlushSnapshot(t *testing.T) {
 		for i := 0; i < num; i++ {
 			st := start.Add(time.Duration(i) * blockSize)
 			ns.EXPECT().NeedsFlush(st, st).Return(true)
-			ns.EXPECT().Snapshot(st, now, gomock.Any(), gomock.Any())
+			ns.EXPECT().Snapshot(st, now, gomock.Any(), gomock.Any(), gomock.Any())
 		}
 	}
 
@@ -430,6 +470,180 @@ func TestFlushManagerFlushSnapshotHonorsMinimumInterval(t *testing.T) {
 	lastSuccessfulSnapshot, ok := fm.LastSuccessfulSnapshotStartTime()
 	require.True(t, ok)
 	require.Equal(t, now, lastSuccessfulSnapshot)
+
+	// Commit log should have been rotated and the snapshot marked complete.
+	require.Equal(t, 1, fm.commitlog.(*fakeRotatableCommitlog).numRotations)
+	written := fm.snapshotMetadataWriter.(*fakeSnapshotMetadataWriter).written
+	require.Len(t, written, 1)
+	require.Equal(t, []byte(testRotatedCommitlogPath), written[0].CommitlogIdentifier)
+}
+
+func TestFlushManagerFlushSnapshotShardNotBootstrappedSkipsMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		fm, ns1, ns2 = newMultipleFlushManagerNeedsFlush(t, ctrl)
+		now          = time.Now()
+	)
+	fm.nowFn = func() time.Time { return now.Add(time.Minute) }
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	ns3 := NewMockdatabaseNamespace(ctrl)
+	ns3.EXPECT().Options().Return(ns1.Options()).AnyTimes()
+	ns3.EXPECT().ID().Return(ident.StringID("otherString")).AnyTimes()
+	ns3.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+
+	db := newMockdatabase(ctrl, ns1, ns2, ns3)
+	fm.database = db
+
+	for _, ns := range []*MockdatabaseNamespace{ns1, ns2, ns3} {
+		ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	}
+
+	bootstrapStates := DatabaseBootstrapState{
+		NamespaceBootstrapStates: map[string]ShardBootstrapStates{
+			ns1.ID().String(): ShardBootstrapStates{},
+			ns2.ID().String(): ShardBootstrapStates{},
+			ns3.ID().String(): ShardBootstrapStates{0: Bootstrapping},
+		},
+	}
+	require.NoError(t, fm.Flush(now, bootstrapStates))
+
+	// Snapshot is still considered successful, but since it could not capture
+	// the data for the bootstrapping shard the commit log must not be rotated
+	// and the snapshot must not be marked complete.
+	lastSuccessfulSnapshot, ok := fm.LastSuccessfulSnapshotStartTime()
+	require.True(t, ok)
+	require.Equal(t, now, lastSuccessfulSnapshot)
+	require.Equal(t, 0, fm.commitlog.(*fakeRotatableCommitlog).numRotations)
+	require.Empty(t, fm.snapshotMetadataWriter.(*fakeSnapshotMetadataWriter).written)
+}
+
+func TestFlushManagerFlushSnapshotIndexOnlyUnflushedIndexSkipsMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		fm, ns1, ns2 = newMultipleFlushManagerNeedsFlush(t, ctrl)
+		now          = time.Now()
+	)
+	fm.nowFn = func() time.Time { return now.Add(time.Minute) }
+
+	ns3 := NewMockdatabaseNamespace(ctrl)
+	ns3.EXPECT().Options().Return(ns1.Options().SetIndexOnly(true)).AnyTimes()
+	ns3.EXPECT().ID().Return(ident.StringID("indexOnly")).AnyTimes()
+	ns3.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
+	ns3.EXPECT().NeedsIndexFlush(gomock.Any(), gomock.Any()).Return(true)
+
+	db := newMockdatabase(ctrl, ns1, ns2, ns3)
+	fm.database = db
+
+	for _, ns := range []*MockdatabaseNamespace{ns1, ns2, ns3} {
+		ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	}
+
+	bootstrapStates := DatabaseBootstrapState{
+		NamespaceBootstrapStates: map[string]ShardBootstrapStates{
+			ns1.ID().String(): ShardBootstrapStates{},
+			ns2.ID().String(): ShardBootstrapStates{},
+			ns3.ID().String(): ShardBootstrapStates{},
+		},
+	}
+	require.NoError(t, fm.Flush(now, bootstrapStates))
+
+	// The snapshot holds none of the index only namespace's documents, while
+	// its index blocks are unflushed they only exist in the commit logs so the
+	// commit log must not be rotated and the snapshot must not be marked complete.
+	require.Equal(t, 0, fm.commitlog.(*fakeRotatableCommitlog).numRotations)
+	require.Empty(t, fm.snapshotMetadataWriter.(*fakeSnapshotMetadataWriter).written)
+}
+
+func TestFlushManagerSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		fm, ns1, ns2 = newMultipleFlushManagerNeedsFlush(t, ctrl)
+		now          = time.Now()
+		snapshotTime = now.Add(time.Second)
+		snapshotIDs  []uuid.UUID
+	)
+	fm.nowFn = func() time.Time { return snapshotTime }
+
+	for _, ns := range []*MockdatabaseNamespace{ns1, ns2} {
+		var (
+			rOpts       = ns.Options().RetentionOptions()
+			blockSize   = rOpts.BlockSize()
+			start       = retention.FlushTimeStart(rOpts, snapshotTime)
+			snapshotEnd = snapshotTime.Add(rOpts.BufferFuture()).Truncate(blockSize)
+			num         = numIntervals(start, snapshotEnd, blockSize)
+		)
+
+		for i := 0; i < num; i++ {
+			st := start.Add(time.Duration(i) * blockSize)
+			ns.EXPECT().NeedsFlush(st, st).Return(true)
+			ns.EXPECT().
+				Snapshot(st, snapshotTime, gomock.Any(), gomock.Any(), gomock.Any()).
+				Do(func(_, _ time.Time, id uuid.UUID, _ ShardBootstrapStates, _ persist.DataFlush) {
+					snapshotIDs = append(snapshotIDs, id)
+				})
+		}
+	}
+
+	bootstrapStates := DatabaseBootstrapState{
+		NamespaceBootstrapStates: map[string]ShardBootstrapStates{
+			ns1.ID().String(): ShardBootstrapStates{},
+			ns2.ID().String(): ShardBootstrapStates{},
+		},
+	}
+	result, err := fm.Snapshot(now, bootstrapStates)
+	require.NoError(t, err)
+	require.Equal(t, snapshotTime, result.Time)
+
+	// Every fileset and the snapshot metadata share the returned snapshot ID.
+	require.NotEmpty(t, snapshotIDs)
+	for _, id := range snapshotIDs {
+		require.True(t, uuid.Equal(result.ID, id))
+	}
+	require.Equal(t, 1, fm.commitlog.(*fakeRotatableCommitlog).numRotations)
+	written := fm.snapshotMetadataWriter.(*fakeSnapshotMetadataWriter).written
+	require.Len(t, written, 1)
+	require.True(t, uuid.Equal(result.ID, written[0].ID.UUID))
+
+	lastSuccessfulSnapshot, ok := fm.LastSuccessfulSnapshotStartTime()
+	require.True(t, ok)
+	require.Equal(t, now, lastSuccessfulSnapshot)
+}
+
+func TestFlushManagerSnapshotShardNotBootstrapped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		fm, ns1, _ = newMultipleFlushManagerNeedsFlush(t, ctrl)
+		now        = time.Now()
+	)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	ns2 := NewMockdatabaseNamespace(ctrl)
+	ns2.EXPECT().Options().Return(ns1.Options()).AnyTimes()
+	ns2.EXPECT().ID().Return(ident.StringID("otherString")).AnyTimes()
+	ns2.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+	fm.database = newMockdatabase(ctrl, ns1, ns2)
+
+	bootstrapStates := DatabaseBootstrapState{
+		NamespaceBootstrapStates: map[string]ShardBootstrapStates{
+			ns1.ID().String(): ShardBootstrapStates{},
+			ns2.ID().String(): ShardBootstrapStates{0: Bootstrapping},
+		},
+	}
+	_, err := fm.Snapshot(now, bootstrapStates)
+	require.Equal(t, errSnapshotDoesNotCaptureAllData, err)
+	require.Equal(t, 0, fm.commitlog.(*fakeRotatableCommitlog).numRotations)
+	require.Empty(t, fm.snapshotMetadataWriter.(*fakeSnapshotMetadataWriter).written)
 }
 
 type timesInOrder []time.Time
@@ -21,6 +21,7 @@
 package storage
 
 import (
+	"errors"
 	"sync"
 	"time"
 
@@ -28,6 +29,11 @@ import (
 	xlog "github.com/m3db/m3x/log"
 )
 
+var (
+	errFileOpsDisabled   = errors.New("file operations are disabled")
+	errFileOpsInProgress = errors.New("file operations already in progress")
+)
+
 type fileOpStatus int
 
 const (
@@ -59,6 +65,7 @@ const (
 type fileSystemManager struct {
 	databaseFlushManager
 	databaseCleanupManager
+	databaseDownsampleManager
 	sync.RWMutex
 
 	log      xlog.Logger
@@ -75,12 +82,14 @@ func newFileSystemManager(
 ) databaseFileSystemManager {
 	instrumentOpts := opts.InstrumentOptions()
 	scope := instrumentOpts.MetricsScope().SubScope("fs")
-	fm := newFlushManager(database, scope)
+	fm := newFlushManager(database, commitLog, scope)
 	cm := newCleanupManager(database, commitLog, scope)
+	dm := newDownsampleManager(database, scope)
 
 	return &fileSystemManager{
-		databaseFlushManager:   fm,
-		databaseCleanupManager: cm,
+		databaseFlushManager:      fm,
+		databaseCleanupManager:    cm,
+		databaseDownsampleManager: dm,
 		log:      instrumentOpts.Logger(),
 		database: database,
 		opts:     opts,
@@ -134,6 +143,11 @@ func (m *fileSystemManager) Run(
 		if err := m.Flush(t, dbBootstrapStates); err != nil {
 			m.log.Errorf("error when flushing data for time %v: %v", t, err)
 		}
+		// Roll up after flushing so that blocks flushed by this run can be
+		// rolled up without waiting for the next run.
+		if err := m.Downsample(t); err != nil {
+			m.log.Errorf("error when downsampling data for time %v: %v", t, err)
+		}
 		m.Lock()
 		m.status = fileOpNotStarted
 		m.Unlock()
@@ -147,9 +161,35 @@ func (m *fileSystemManager) Run(
 	return true
 }
 
+func (m *fileSystemManager) Snapshot(
+	t time.Time,
+	dbBootstrapState DatabaseBootstrapState,
+) (SnapshotResult, error) {
+	m.Lock()
+	if !m.enabled {
+		m.Unlock()
+		return SnapshotResult{}, errFileOpsDisabled
+	}
+	if m.status == fileOpInProgress {
+		m.Unlock()
+		return SnapshotResult{}, errFileOpsInProgress
+	}
+	m.status = fileOpInProgress
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.status = fileOpNotStarted
+		m.Unlock()
+	}()
+
+	return m.databaseFlushManager.Snapshot(t, dbBootstrapState)
+}
+
 func (m *fileSystemManager) Report() {
 	m.databaseCleanupManager.Report()
 	m.databaseFlushManager.Report()
+	m.databaseDownsampleManager.Report()
 }
 
 func (m *fileSystemManager) shouldRunWithLock() bool {
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -78,17 +79,49 @@ func TestFileSystemManagerRun(t *testing.T) {
 
 	fm := NewMockdatabaseFlushManager(ctrl)
 	cm := NewMockdatabaseCleanupManager(ctrl)
+	dm := NewMockdatabaseDownsampleManager(ctrl)
 	fsm := newFileSystemManager(database, nil, testDatabaseOptions())
 	mgr := fsm.(*fileSystemManager)
 	mgr.databaseFlushManager = fm
 	mgr.databaseCleanupManager = cm
+	mgr.databaseDownsampleManager = dm
 
 	ts := time.Now()
 	gomock.InOrder(
 		cm.EXPECT().Cleanup(ts).Return(errors.New("foo")),
 		fm.EXPECT().Flush(ts, DatabaseBootstrapState{}).Return(errors.New("bar")),
+		dm.EXPECT().Downsample(ts).Return(errors.New("baz")),
 	)
 
 	mgr.Run(ts, DatabaseBootstrapState{}, syncRun, noForce)
 	require.Equal(t, fileOpNotStarted, mgr.status)
 }
+
+func TestFileSystemManagerSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	database := newMockdatabase(ctrl)
+
+	fm := NewMockdatabaseFlushManager(ctrl)
+	fsm := newFileSystemManager(database, nil, testDatabaseOptions())
+	mgr := fsm.(*fileSystemManager)
+	mgr.databaseFlushManager = fm
+
+	ts := time.Now()
+	result := SnapshotResult{ID: uuid.NewRandom(), Time: ts}
+	fm.EXPECT().Snapshot(ts, DatabaseBootstrapState{}).Return(result, nil)
+
+	snapshot, err := mgr.Snapshot(ts, DatabaseBootstrapState{})
+	require.NoError(t, err)
+	require.Equal(t, result, snapshot)
+	require.Equal(t, fileOpNotStarted, mgr.status)
+
+	mgr.status = fileOpInProgress
+	_, err = mgr.Snapshot(ts, DatabaseBootstrapState{})
+	require.Equal(t, errFileOpsInProgress, err)
+
+	mgr.status = fileOpNotStarted
+	mgr.Disable()
+	_, err = mgr.Snapshot(ts, DatabaseBootstrapState{})
+	require.Equal(t, errFileOpsDisabled, err)
+}
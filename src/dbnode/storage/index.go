@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	m3dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -100,6 +101,12 @@ type nsIndex struct {
 	// blocks and other cleanup tasks on index close
 	queriesWg sync.WaitGroup
 
+	// cardinality tracks the cardinality of the series being indexed
+	cardinality cardinality.Tracker
+
+	// closeCh is closed when the index is closed to stop background compaction.
+	closeCh chan struct{}
+
 	metrics nsIndexMetrics
 }
 
@@ -217,6 +224,15 @@ func newNamespaceIndexWithOptions(
 	instrumentOpts = instrumentOpts.SetMetricsScope(scope)
 	indexOpts = indexOpts.SetInstrumentOptions(instrumentOpts)
 
+	retentionOpts := nsMD.Options().RetentionOptions()
+	cardinalityTracker, err := cardinality.NewTracker(indexOpts.CardinalityOptions().
+		SetInstrumentOptions(instrumentOpts).
+		SetBlockSize(nsMD.Options().IndexOptions().BlockSize()).
+		SetRetentionPeriod(retentionOpts.RetentionPeriod()))
+	if err != nil {
+		return nil, err
+	}
+
 	nowFn := indexOpts.ClockOptions().NowFn()
 	idx := &nsIndex{
 		state: nsIndexState{
@@ -242,6 +258,8 @@ func newNamespaceIndexWithOptions(
 		nsMetadata:       nsMD,
 		resultsPool:      indexOpts.ResultsPool(),
 		queryWorkersPool: newIndexOpts.opts.QueryIDsWorkerPool(),
+		cardinality:      cardinalityTracker,
+		closeCh:          make(chan struct{}),
 
 		metrics: newNamespaceIndexMetrics(instrumentOpts),
 	}
@@ -264,9 +282,44 @@ func newNamespaceIndexWithOptions(
 		return nil, err
 	}
 
+	if interval := indexOpts.BackgroundCompactionInterval(); interval > 0 {
+		go idx.backgroundCompactLoop(interval)
+	}
+
 	return idx, nil
 }
 
+// backgroundCompactLoop compacts the in-memory segments of every block of the
+// index from a single goroutine until the index is closed.
+func (i *nsIndex) backgroundCompactLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-i.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		i.state.RLock()
+		blocks := make([]index.Block, 0, len(i.state.blocksByTime))
+		for _, block := range i.state.blocksByTime {
+			blocks = append(blocks, block)
+		}
+		i.state.RUnlock()
+
+		// NB: blocks may be closed whilst being compacted if they slide out of
+		// retention or the index is closed, compacting a closed block is a no-op.
+		for _, block := range blocks {
+			if err := block.Compact(); err != nil {
+				i.logger.Errorf("error compacting index block %v segments: %v",
+					block.StartTime(), err)
+			}
+		}
+	}
+}
+
 func (i *nsIndex) SetRuntimeOptions(value runtime.Options) {
 	i.state.Lock()
 	i.state.runtimeOpts.defaultQueryTimeout = value.IndexDefaultQueryTimeout()
@@ -402,6 +455,12 @@ func (i *nsIndex) writeBatchForBlockStartWithRLock(
 	// NB(r): Capture pending entries so we can emit the latencies
 	pending := batch.PendingEntries()
 
+	// track the cardinality of the series before the block assumes
+	// responsibility for the documents.
+	for _, d := range batch.PendingDocs() {
+		i.cardinality.Add(blockStart, d)
+	}
+
 	// i.e. we have the block and the inserts, perform the writes.
 	result, err := block.WriteBatch(batch)
 
@@ -466,6 +525,23 @@ func (i *nsIndex) Bootstrap(
 	return multiErr.FinalError()
 }
 
+func (i *nsIndex) AddSegments(
+	blockStart time.Time,
+	segments []segment.Segment,
+) error {
+	i.state.RLock()
+	defer i.state.RUnlock()
+	if !i.isOpenWithRLock() {
+		return errDbIndexUnableToWriteClosed
+	}
+
+	block, err := i.ensureBlockPresentWithRLock(blockStart)
+	if err != nil {
+		return err
+	}
+	return block.AddSegments(segments)
+}
+
 func (i *nsIndex) Tick(c context.Cancellable, tickStart time.Time) (namespaceIndexTickResult, error) {
 	var (
 		result                     = namespaceIndexTickResult{}
@@ -473,6 +549,9 @@ func (i *nsIndex) Tick(c context.Cancellable, tickStart time.Time) (namespaceInd
 		lastSealableBlockStart     = retention.FlushTimeEndForBlockSize(i.blockSize, tickStart.Add(-i.bufferPast))
 	)
 
+	// expire cardinality estimates past retention and emit cardinality metrics
+	i.cardinality.Tick(tickStart)
+
 	i.state.Lock()
 	defer func() {
 		i.updateBlockStartsWithLock()
@@ -501,6 +580,8 @@ func (i *nsIndex) Tick(c context.Cancellable, tickStart time.Time) (namespaceInd
 		blockTickResult, tickErr := block.Tick(c, tickStart)
 		multiErr = multiErr.Add(tickErr)
 		result.NumSegments += blockTickResult.NumSegments
+		result.NumMutableSegments += blockTickResult.NumMutableSegments
+		result.NumFSTSegments += blockTickResult.NumFSTSegments
 		result.NumTotalDocs += blockTickResult.NumDocs
 
 		// seal any blocks that are sealable
@@ -555,6 +636,20 @@ func (i *nsIndex) Flush(
 	return nil
 }
 
+func (i *nsIndex) HasUnflushedBlocks(start, end time.Time) bool {
+	i.state.RLock()
+	defer i.state.RUnlock()
+	for _, block := range i.state.blocksByTime {
+		if block.StartTime().After(end) || !block.EndTime().After(start) {
+			continue
+		}
+		if block.NeedsMutableSegmentsEvicted() {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *nsIndex) flushableBlocks(
 	shards []databaseShard,
 ) ([]index.Block, error) {
@@ -638,16 +733,24 @@ func (i *nsIndex) flushBlock(
 		}
 	}()
 
-	for _, shards := range segmentShards {
-		if len(shards) == 0 {
-			// This can happen if fewer shards than num segments we'd like
-			continue
+	if i.nsMetadata.Options().IndexOnly() {
+		// Index only namespaces have no series data to read the documents
+		// from, so flush the documents held by the block itself.
+		if err := i.flushIndexOnlyBlockSegment(preparedPersist, indexBlock); err != nil {
+			return nil, err
 		}
+	} else {
+		for _, shards := range segmentShards {
+			if len(shards) == 0 {
+				// This can happen if fewer shards than num segments we'd like
+				continue
+			}
 
-		// Flush a single block segment
-		err := i.flushBlockSegment(preparedPersist, indexBlock, shards)
-		if err != nil {
-			return nil, err
+			// Flush a single block segment
+			err := i.flushBlockSegment(preparedPersist, indexBlock, shards)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -670,6 +773,10 @@ func (i *nsIndex) flushBlockSegment(
 	}
 	defer seg.Close()
 
+	// NB: the write times of the block's documents are only resolved once
+	// there is a document to insert, and only once for the whole segment.
+	var writeTimes map[string]int64
+
 	ctx := context.NewContext()
 	for _, shard := range shards {
 		var (
@@ -707,7 +814,14 @@ func (i *nsIndex) flushBlockSegment(
 					return err
 				}
 
-				if _, err := seg.Insert(doc); err != nil {
+				if writeTimes == nil {
+					writeTimes, err = indexBlock.EarliestWriteTimes()
+					if err != nil {
+						return err
+					}
+				}
+
+				if err := insertWithWriteTime(seg, writeTimes, doc); err != nil {
 					return err
 				}
 			}
@@ -725,6 +839,42 @@ func (i *nsIndex) flushBlockSegment(
 	return preparedPersist.Persist(seg)
 }
 
+func (i *nsIndex) flushIndexOnlyBlockSegment(
+	preparedPersist persist.PreparedIndexPersist,
+	indexBlock index.Block,
+) error {
+	seg, err := mem.NewSegment(0, i.opts.IndexOptions().MemSegmentOptions())
+	if err != nil {
+		return err
+	}
+	defer seg.Close()
+
+	// NB: the block's segments are the only copy of the documents, merge them
+	// directly so that document fields beyond the series tags are retained.
+	if err := indexBlock.MergeSegments(seg); err != nil {
+		return err
+	}
+
+	if _, err := seg.Seal(); err != nil {
+		return err
+	}
+
+	return preparedPersist.Persist(seg)
+}
+
+// insertWithWriteTime inserts the document into the segment with the earliest
+// time it was written at in the index block so the persisted segment retains
+// the write times used to prune queries.
+func insertWithWriteTime(
+	seg segment.MutableSegment,
+	writeTimes map[string]int64,
+	d doc.Document,
+) error {
+	b := m3ninxindex.NewBatch([]doc.Document{d})
+	b.WriteTimes = []int64{writeTimes[string(d.ID)]}
+	return seg.InsertBatch(b)
+}
+
 func (i *nsIndex) Query(
 	ctx context.Context,
 	query index.Query,
@@ -763,6 +913,10 @@ func (i *nsIndex) Query(
 		return index.QueryResults{}, err
 	}
 
+	if opts.Paginate {
+		return i.queryPage(ctx, query, opts, blocks, start, timeout)
+	}
+
 	var (
 		deadline = start.Add(timeout)
 		wg       sync.WaitGroup
@@ -967,6 +1121,151 @@ func (i *nsIndex) Query(
 	}, nil
 }
 
+// queryPage queries the blocks sequentially, newest first, resuming after the
+// document at the page token's cursor. Blocks are not queried concurrently so
+// that a single cursor is able to describe where the next page begins.
+func (i *nsIndex) queryPage(
+	ctx context.Context,
+	query index.Query,
+	opts index.QueryOptions,
+	blocks []index.Block,
+	start time.Time,
+	timeout time.Duration,
+) (index.QueryResults, error) {
+	cursor, err := opts.PageToken.Cursor()
+	if err != nil {
+		return index.QueryResults{}, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid page token: %v", err))
+	}
+
+	results := i.resultsPool.Get()
+	results.Reset(i.nsMetadata.ID())
+	ctx.RegisterFinalizer(results)
+
+	deadline := start.Add(timeout)
+	for _, block := range blocks {
+		if !cursor.IsZero() && block.StartTime().After(cursor.BlockStart) {
+			// Already returned all results from this block in a previous page.
+			continue
+		}
+
+		if timeout > 0 && !i.nowFn().Before(deadline) {
+			return index.QueryResults{}, fmt.Errorf("index query timed out: %s", timeout.String())
+		}
+
+		next, exhaustive, err := block.QueryPage(query, opts, cursor, results)
+		if err == index.ErrUnableToQueryBlockClosed {
+			// NB: the block slid out of retention, its results are no longer valid.
+			continue
+		}
+		if err != nil {
+			return index.QueryResults{}, err
+		}
+
+		cursor = next
+		if exhaustive {
+			continue
+		}
+
+		token, err := index.NewPageToken(cursor)
+		if err != nil {
+			return index.QueryResults{}, err
+		}
+
+		return index.QueryResults{
+			Exhaustive:    false,
+			Results:       results,
+			NextPageToken: token,
+		}, nil
+	}
+
+	return index.QueryResults{
+		Exhaustive: true,
+		Results:    results,
+	}, nil
+}
+
+func (i *nsIndex) AggregateQuery(
+	ctx context.Context,
+	query index.Query,
+	opts index.AggregateQueryOptions,
+) (index.AggregateQueryResults, error) {
+	// Capture start before needing to acquire lock.
+	start := i.nowFn()
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return index.AggregateQueryResults{}, errDbIndexUnableToQueryClosed
+	}
+
+	// Track this as an inflight query that needs to finish
+	// when the index is closed.
+	i.queriesWg.Add(1)
+	defer i.queriesWg.Done()
+
+	// Enact overrides for query options
+	opts.QueryOptions = i.overriddenOptsForQueryWithRLock(opts.QueryOptions)
+	timeout := i.timeoutForQueryWithRLock(ctx)
+
+	// Retrieve blocks to query, then we can release lock.
+	blocks, err := i.blocksForQueryWithRLock(xtime.NewRanges(xtime.Range{
+		Start: opts.StartInclusive,
+		End:   opts.EndExclusive,
+	}))
+
+	// Can now release the lock and execute the query without holding the lock.
+	i.state.RUnlock()
+
+	if err != nil {
+		return index.AggregateQueryResults{}, err
+	}
+
+	// NB: blocks are aggregated sequentially into a single set of results so
+	// that the limit is applied to distinct tag names and values across blocks
+	// and series indexed in several blocks are only counted once.
+	var (
+		results    = index.NewAggregateResults(opts.Limit)
+		deadline   = start.Add(timeout)
+		exhaustive = true
+	)
+	for _, block := range blocks {
+		if timeout > 0 && !i.nowFn().Before(deadline) {
+			return index.AggregateQueryResults{}, fmt.Errorf("index query timed out: %s", timeout.String())
+		}
+
+		blockExhaustive, err := block.Aggregate(query, opts, results)
+		if err == index.ErrUnableToQueryBlockClosed {
+			// NB: the block slid out of retention, its results are no longer valid.
+			continue
+		}
+		if err != nil {
+			return index.AggregateQueryResults{}, err
+		}
+
+		if !blockExhaustive {
+			exhaustive = false
+			break
+		}
+	}
+
+	return index.AggregateQueryResults{
+		Results:    results,
+		Exhaustive: exhaustive,
+	}, nil
+}
+
+func (i *nsIndex) Cardinality(
+	opts cardinality.ReportOptions,
+) (cardinality.Report, error) {
+	i.state.RLock()
+	defer i.state.RUnlock()
+	if !i.isOpenWithRLock() {
+		return cardinality.Report{}, errDbIndexUnableToQueryClosed
+	}
+	return i.cardinality.Report(opts), nil
+}
+
 func (i *nsIndex) timeoutForQueryWithRLock(
 	ctx context.Context,
 ) time.Duration {
@@ -1144,6 +1443,7 @@ func (i *nsIndex) Close() error {
 	}
 
 	i.state.closed = true
+	close(i.closeCh)
 
 	var multiErr xerrors.MultiError
 	multiErr = multiErr.Add(i.state.insertQueue.Stop())
@@ -0,0 +1,143 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"sort"
+)
+
+type aggregateResults struct {
+	limit  int
+	size   int
+	fields map[string]*aggregateField
+	ids    map[string]struct{}
+}
+
+type aggregateField struct {
+	count  int64
+	values map[string]int64
+}
+
+// NewAggregateResults returns new aggregate results which hold at most limit
+// distinct entries, the number of entries is unbounded if limit is zero.
+func NewAggregateResults(limit int) AggregateResults {
+	return &aggregateResults{
+		limit:  limit,
+		fields: make(map[string]*aggregateField),
+		ids:    make(map[string]struct{}),
+	}
+}
+
+func (r *aggregateResults) Size() int {
+	return r.size
+}
+
+func (r *aggregateResults) full() bool {
+	return r.limit > 0 && r.size >= r.limit
+}
+
+func (r *aggregateResults) AddDocumentID(id []byte) bool {
+	if _, ok := r.ids[string(id)]; ok {
+		return false
+	}
+	r.ids[string(id)] = struct{}{}
+	return true
+}
+
+func (r *aggregateResults) AddField(name []byte, count int64) bool {
+	field, ok := r.fields[string(name)]
+	if !ok {
+		if r.full() {
+			return false
+		}
+		field = &aggregateField{}
+		r.fields[string(name)] = field
+		r.size++
+	}
+	field.count += count
+	return true
+}
+
+func (r *aggregateResults) AddFieldValue(name, value []byte, count int64) bool {
+	field, ok := r.fields[string(name)]
+	if ok {
+		if _, ok := field.values[string(value)]; ok {
+			field.count += count
+			field.values[string(value)] += count
+			return true
+		}
+	}
+
+	if r.full() {
+		return false
+	}
+
+	if !ok {
+		field = &aggregateField{}
+		r.fields[string(name)] = field
+	}
+	if field.values == nil {
+		field.values = make(map[string]int64)
+	}
+	field.count += count
+	field.values[string(value)] = count
+	r.size++
+	return true
+}
+
+func (r *aggregateResults) Fields() []AggregateField {
+	fields := make([]AggregateField, 0, len(r.fields))
+	for name, field := range r.fields {
+		result := AggregateField{
+			Name:  []byte(name),
+			Count: field.count,
+		}
+		if len(field.values) > 0 {
+			result.Values = make([]AggregateValue, 0, len(field.values))
+			for value, count := range field.values {
+				result.Values = append(result.Values, AggregateValue{
+					Value: []byte(value),
+					Count: count,
+				})
+			}
+			sort.Slice(result.Values, func(i, j int) bool {
+				return bytes.Compare(result.Values[i].Value, result.Values[j].Value) < 0
+			})
+		}
+		fields = append(fields, result)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return bytes.Compare(fields[i].Name, fields[j].Name) < 0
+	})
+	return fields
+}
+
+func (r *aggregateResults) Reset() {
+	for name := range r.fields {
+		delete(r.fields, name)
+	}
+	for id := range r.ids {
+		delete(r.ids, id)
+	}
+	r.size = 0
+}
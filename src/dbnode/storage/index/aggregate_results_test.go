@@ -0,0 +1,108 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateResultsAddFieldValue(t *testing.T) {
+	res := NewAggregateResults(0)
+	require.True(t, res.AddFieldValue([]byte("foo"), []byte("qux"), 1))
+	require.True(t, res.AddFieldValue([]byte("foo"), []byte("bar"), 2))
+	require.True(t, res.AddFieldValue([]byte("foo"), []byte("bar"), 3))
+	require.True(t, res.AddFieldValue([]byte("baz"), []byte("bar"), 1))
+	require.Equal(t, 3, res.Size())
+
+	require.Equal(t, []AggregateField{
+		{
+			Name:  []byte("baz"),
+			Count: 1,
+			Values: []AggregateValue{
+				{Value: []byte("bar"), Count: 1},
+			},
+		},
+		{
+			Name:  []byte("foo"),
+			Count: 6,
+			Values: []AggregateValue{
+				{Value: []byte("bar"), Count: 5},
+				{Value: []byte("qux"), Count: 1},
+			},
+		},
+	}, res.Fields())
+}
+
+func TestAggregateResultsAddField(t *testing.T) {
+	res := NewAggregateResults(0)
+	require.True(t, res.AddField([]byte("foo"), 1))
+	require.True(t, res.AddField([]byte("bar"), 2))
+	require.True(t, res.AddField([]byte("foo"), 3))
+	require.Equal(t, 2, res.Size())
+
+	require.Equal(t, []AggregateField{
+		{Name: []byte("bar"), Count: 2},
+		{Name: []byte("foo"), Count: 4},
+	}, res.Fields())
+}
+
+func TestAggregateResultsLimit(t *testing.T) {
+	res := NewAggregateResults(2)
+	require.True(t, res.AddFieldValue([]byte("foo"), []byte("bar"), 1))
+	require.True(t, res.AddFieldValue([]byte("foo"), []byte("baz"), 1))
+	require.False(t, res.AddFieldValue([]byte("foo"), []byte("qux"), 1))
+	require.False(t, res.AddField([]byte("qux"), 1))
+
+	// Existing entries are still counted once the limit is reached.
+	require.True(t, res.AddFieldValue([]byte("foo"), []byte("bar"), 1))
+	require.Equal(t, 2, res.Size())
+
+	fields := res.Fields()
+	require.Len(t, fields, 1)
+	require.Equal(t, int64(3), fields[0].Count)
+	require.Equal(t, []AggregateValue{
+		{Value: []byte("bar"), Count: 2},
+		{Value: []byte("baz"), Count: 1},
+	}, fields[0].Values)
+}
+
+func TestAggregateResultsReset(t *testing.T) {
+	res := NewAggregateResults(1)
+	require.True(t, res.AddField([]byte("foo"), 1))
+	require.False(t, res.AddField([]byte("bar"), 1))
+
+	require.True(t, res.AddDocumentID([]byte("baz")))
+
+	res.Reset()
+	require.Equal(t, 0, res.Size())
+	require.Empty(t, res.Fields())
+	require.True(t, res.AddField([]byte("bar"), 1))
+	require.True(t, res.AddDocumentID([]byte("baz")))
+}
+
+func TestAggregateResultsAddDocumentID(t *testing.T) {
+	res := NewAggregateResults(0)
+	require.True(t, res.AddDocumentID([]byte("foo")))
+	require.True(t, res.AddDocumentID([]byte("bar")))
+	require.False(t, res.AddDocumentID([]byte("foo")))
+}
@@ -21,13 +21,19 @@
 package index
 
 import (
+	"bytes"
+	"container/heap"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index/segments"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
@@ -48,6 +54,7 @@ var (
 	errUnableToWriteBlockClosed     = errors.New("unable to write, index block is closed")
 	errUnableToWriteBlockSealed     = errors.New("unable to write, index block is sealed")
 	errUnableToBootstrapBlockClosed = errors.New("unable to bootstrap, block is closed")
+	errUnableToAddSegmentsClosed    = errors.New("unable to add segments, block is closed")
 	errUnableToTickBlockClosed      = errors.New("unable to tick, block is closed")
 	errBlockAlreadyClosed           = errors.New("unable to close, block already closed")
 
@@ -63,20 +70,29 @@ const (
 	blockStateSealed
 )
 
-type newExecutorFn func() (search.Executor, error)
+type newExecutorFn func(opts executorOptions) (search.Executor, error)
 
 type block struct {
 	sync.RWMutex
 	state               blockState
 	activeSegment       segment.MutableSegment
+	activeSegmentStart  time.Time
+	compactedSegments   []compactedSegment
 	shardRangesSegments []blockShardRangesSegments
 
+	// compacting holds the rotated segments being read by a compaction, the
+	// value is set if the segment was evicted or closed whilst being compacted
+	// in which case the compaction closes it once it is done reading it.
+	compacting map[segment.Segment]bool
+
 	newExecutorFn newExecutorFn
 	startTime     time.Time
 	endTime       time.Time
 	blockSize     time.Duration
 	opts          Options
 	nsMD          namespace.Metadata
+	nowFn         clock.NowFn
+	metrics       blockMetrics
 }
 
 // blockShardsSegments is a collection of segments that has a mapping of what shards
@@ -105,15 +121,21 @@ func NewBlock(
 		return nil, err
 	}
 
+	nowFn := opts.ClockOptions().NowFn()
 	b := &block{
-		state:         blockStateOpen,
-		activeSegment: seg,
+		state:              blockStateOpen,
+		activeSegment:      seg,
+		activeSegmentStart: nowFn(),
 
 		startTime: startTime,
 		endTime:   startTime.Add(blockSize),
 		blockSize: blockSize,
 		opts:      opts,
 		nsMD:      md,
+		nowFn:     nowFn,
+		metrics:   newBlockMetrics(opts.InstrumentOptions().MetricsScope()),
+
+		compacting: make(map[segment.Segment]bool),
 	}
 	b.newExecutorFn = b.executorWithRLock
 
@@ -153,6 +175,7 @@ func (b *block) WriteBatch(inserts *WriteBatch) (WriteBatchResult, error) {
 	err := b.activeSegment.InsertBatch(m3ninxindex.Batch{
 		Docs:                inserts.PendingDocs(),
 		AllowPartialUpdates: true,
+		WriteTimes:          inserts.PendingWriteTimes(),
 	})
 	if err == nil {
 		inserts.MarkUnmarkedEntriesSuccess()
@@ -183,8 +206,8 @@ func (b *block) WriteBatch(inserts *WriteBatch) (WriteBatchResult, error) {
 	}, partialErr
 }
 
-func (b *block) executorWithRLock() (search.Executor, error) {
-	var expectedReaders int
+func (b *block) executorWithRLock(opts executorOptions) (search.Executor, error) {
+	expectedReaders := len(b.compactedSegments)
 	if b.activeSegment != nil {
 		expectedReaders++
 	}
@@ -208,7 +231,16 @@ func (b *block) executorWithRLock() (search.Executor, error) {
 
 	// start with the segment that's being actively written to (if we have one)
 	if b.activeSegment != nil {
-		reader, err := b.activeSegment.Reader()
+		reader, err := segmentReader(b.activeSegment, opts)
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, reader)
+	}
+
+	// then the segments rotated out of the active segment and compacted since
+	for _, compacted := range b.compactedSegments {
+		reader, err := segmentReader(compacted.segment, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -218,7 +250,7 @@ func (b *block) executorWithRLock() (search.Executor, error) {
 	// loop over the segments associated to shard time ranges
 	for _, group := range b.shardRangesSegments {
 		for _, seg := range group.segments {
-			reader, err := seg.Reader()
+			reader, err := segmentReader(seg, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -241,22 +273,126 @@ func (b *block) Query(
 		return false, ErrUnableToQueryBlockClosed
 	}
 
-	exec, err := b.newExecutorFn()
+	var (
+		size           = results.Size()
+		limitedResults = false
+	)
+	done := func() bool {
+		limitedResults = opts.LimitExceeded(size)
+		return limitedResults
+	}
+	err := b.searchWithRLock(query, opts, done, func(d doc.Document) error {
+		var err error
+		_, size, err = results.AddDocument(d)
+		return err
+	})
 	if err != nil {
 		return false, err
 	}
 
+	exhaustive := !limitedResults
+	return exhaustive, nil
+}
+
+// QueryPage adds the documents matching the query to the results in ascending
+// order of their IDs, resuming after the ID of the cursor's document if the
+// cursor is positioned within the block. Paging by ID rather than by the
+// position of documents within the block's segments keeps the cursor valid
+// when segments are rotated or compacted between pages.
+func (b *block) QueryPage(
+	query Query,
+	opts QueryOptions,
+	cursor QueryCursor,
+	results Results,
+) (QueryCursor, bool, error) {
+	b.RLock()
+	defer b.RUnlock()
+	if b.state == blockStateClosed {
+		return cursor, false, ErrUnableToQueryBlockClosed
+	}
+
+	var after []byte
+	if !cursor.IsZero() && cursor.BlockStart.Equal(b.startTime) {
+		after = cursor.DocID
+	}
+
+	var (
+		remaining      = opts.Limit - results.Size()
+		page           docsByIDHeap
+		limitedResults = false
+	)
+	err := b.searchWithRLock(query, opts, nil, func(d doc.Document) error {
+		if after != nil && bytes.Compare(d.ID, after) <= 0 {
+			return nil
+		}
+
+		if opts.Limit <= 0 {
+			_, _, err := results.AddDocument(d)
+			return err
+		}
+
+		if len(page) < remaining {
+			heap.Push(&page, cloneDocument(d))
+			return nil
+		}
+
+		// NB: the page is full, the document either replaces the document with
+		// the largest ID in the page or is left for a later page.
+		limitedResults = true
+		if len(page) > 0 && bytes.Compare(d.ID, page[0].ID) < 0 {
+			page[0] = cloneDocument(d)
+			heap.Fix(&page, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return cursor, false, err
+	}
+
+	sort.Slice(page, func(i, j int) bool {
+		return bytes.Compare(page[i].ID, page[j].ID) < 0
+	})
+	for _, d := range page {
+		if _, _, err := results.AddDocument(d); err != nil {
+			return cursor, false, err
+		}
+	}
+
+	next := cursor
+	if len(page) > 0 {
+		next = QueryCursor{
+			BlockStart: b.startTime,
+			DocID:      page[len(page)-1].ID,
+		}
+	}
+
+	exhaustive := !limitedResults
+	return next, exhaustive, nil
+}
+
+// searchWithRLock calls fn with each document matching the query and accepted
+// by the query options' ID filter, stopping early once done returns true if it
+// is set. The document is only valid for the duration of the call.
+func (b *block) searchWithRLock(
+	query Query,
+	opts QueryOptions,
+	done func() bool,
+	fn func(d doc.Document) error,
+) error {
+	exec, err := b.newExecutorFn(b.executorOptions(query, opts))
+	if err != nil {
+		return err
+	}
+
 	// FOLLOWUP(prateek): push down QueryOptions to restrict results
 	// TODO(jeromefroe): Use the idx query directly once we implement an index in m3ninx
 	// and don't need to use the segments anymore.
 	iter, err := exec.Execute(query.Query.SearchQuery())
 	if err != nil {
 		exec.Close()
-		return false, err
+		return err
 	}
 
-	size := results.Size()
-	limitedResults := false
 	iterCloser := safeCloser{closable: iter}
 	execCloser := safeCloser{closable: exec}
 
@@ -266,32 +402,29 @@ func (b *block) Query(
 	}()
 
 	for iter.Next() {
-		if opts.LimitExceeded(size) {
-			limitedResults = true
+		if done != nil && done() {
 			break
 		}
 
 		d := iter.Current()
-		_, size, err = results.AddDocument(d)
-		if err != nil {
-			return false, err
+		if opts.FilterID != nil && !opts.FilterID(d.ID) {
+			continue
+		}
+
+		if err := fn(d); err != nil {
+			return err
 		}
 	}
 
 	if err := iter.Err(); err != nil {
-		return false, err
+		return err
 	}
 
 	if err := iterCloser.Close(); err != nil {
-		return false, err
-	}
-
-	if err := execCloser.Close(); err != nil {
-		return false, err
+		return err
 	}
 
-	exhaustive := !limitedResults
-	return exhaustive, nil
+	return execCloser.Close()
 }
 
 func (b *block) AddResults(
@@ -371,6 +504,86 @@ func (b *block) AddResults(
 	return multiErr.FinalError()
 }
 
+func (b *block) AddSegments(segments []segment.Segment) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.state == blockStateClosed {
+		return errUnableToAddSegmentsClosed
+	}
+
+	b.shardRangesSegments = append(b.shardRangesSegments, blockShardRangesSegments{
+		segments: segments,
+	})
+	return nil
+}
+
+func (b *block) EarliestWriteTimes() (map[string]int64, error) {
+	b.RLock()
+	defer b.RUnlock()
+	if b.state == blockStateClosed {
+		return nil, ErrUnableToQueryBlockClosed
+	}
+
+	earliest := make(map[string]int64)
+	visit := func(seg segment.Segment) error {
+		// NB: each segment is visited once, the IDs of its documents are the
+		// terms of the reserved ID field.
+		terms, err := seg.Terms(doc.IDReservedFieldName)
+		if err != nil {
+			return err
+		}
+
+		termsCloser := safeCloser{closable: terms}
+		defer termsCloser.Close()
+
+		writeTimes, hasWriteTimes := seg.(segment.WriteTimes)
+		for terms.Next() {
+			id := terms.Current()
+
+			// NB: the write time of the document is unknown if the segment
+			// does not record write times.
+			var writeTime int64
+			if hasWriteTimes {
+				t, ok, err := writeTimes.TermEarliestWriteTime(doc.IDReservedFieldName, id)
+				if err != nil {
+					return err
+				}
+				if ok {
+					writeTime = t
+				}
+			}
+
+			if existing, ok := earliest[string(id)]; !ok || writeTime < existing {
+				earliest[string(id)] = writeTime
+			}
+		}
+
+		if err := terms.Err(); err != nil {
+			return err
+		}
+		return termsCloser.Close()
+	}
+
+	for _, seg := range b.segmentsWithRLock() {
+		if err := visit(seg); err != nil {
+			return nil, err
+		}
+	}
+
+	return earliest, nil
+}
+
+func (b *block) MergeSegments(target segment.MutableSegment) error {
+	b.RLock()
+	defer b.RUnlock()
+	if b.state == blockStateClosed {
+		return ErrUnableToQueryBlockClosed
+	}
+
+	return mem.Merge(target, b.segmentsWithRLock()...)
+}
+
 func (b *block) Tick(c context.Cancellable, tickStart time.Time) (BlockTickResult, error) {
 	b.RLock()
 	defer b.RUnlock()
@@ -382,14 +595,31 @@ func (b *block) Tick(c context.Cancellable, tickStart time.Time) (BlockTickResul
 	// active segment, can be nil incase we've evicted it already.
 	if b.activeSegment != nil {
 		result.NumSegments++
+		result.NumMutableSegments++
 		result.NumDocs += b.activeSegment.Size()
 	}
 
+	// segments rotated out of the active segment.
+	for _, compacted := range b.compactedSegments {
+		result.NumSegments++
+		result.NumDocs += compacted.segment.Size()
+		if compacted.segmentType == segments.MutableType {
+			result.NumMutableSegments++
+		} else {
+			result.NumFSTSegments++
+		}
+	}
+
 	// any other segments
 	for _, group := range b.shardRangesSegments {
 		for _, seg := range group.segments {
 			result.NumSegments++
 			result.NumDocs += seg.Size()
+			if _, ok := seg.(segment.MutableSegment); ok {
+				result.NumMutableSegments++
+			} else {
+				result.NumFSTSegments++
+			}
 		}
 	}
 
@@ -440,6 +670,10 @@ func (b *block) NeedsMutableSegmentsEvicted() bool {
 	defer b.RUnlock()
 	anyMutableSegmentNeedsEviction := b.activeSegment != nil && b.activeSegment.Size() > 0
 
+	// NB: segments rotated out of the active segment (and their compactions)
+	// only hold data which is yet to be flushed, so they are evicted as well.
+	anyMutableSegmentNeedsEviction = anyMutableSegmentNeedsEviction || len(b.compactedSegments) > 0
+
 	// can early terminate if we already know we need to flush.
 	if anyMutableSegmentNeedsEviction {
 		return true
@@ -475,6 +709,14 @@ func (b *block) EvictMutableSegments() (EvictMutableSegmentResults, error) {
 		b.activeSegment = nil
 	}
 
+	// close any segments rotated out of the active segment.
+	for _, compacted := range b.compactedSegments {
+		results.NumMutableSegments++
+		results.NumDocs += compacted.segment.Size()
+		multiErr = multiErr.Add(b.closeCompactedSegmentWithLock(compacted.segment))
+	}
+	b.compactedSegments = nil
+
 	// close any other mutable segments too.
 	for idx := range b.shardRangesSegments {
 		segments := make([]segment.Segment, 0, len(b.shardRangesSegments[idx].segments))
@@ -510,6 +752,12 @@ func (b *block) Close() error {
 		b.activeSegment = nil
 	}
 
+	// close any segments rotated out of the active segment.
+	for _, compacted := range b.compactedSegments {
+		multiErr = multiErr.Add(b.closeCompactedSegmentWithLock(compacted.segment))
+	}
+	b.compactedSegments = nil
+
 	// close any other added segments too.
 	for _, group := range b.shardRangesSegments {
 		for _, seg := range group.segments {
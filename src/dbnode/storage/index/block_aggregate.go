@@ -0,0 +1,171 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/search"
+)
+
+var allQuery = idx.NewAllQuery()
+
+func (b *block) Aggregate(
+	query Query,
+	opts AggregateQueryOptions,
+	results AggregateResults,
+) (bool, error) {
+	b.RLock()
+	defer b.RUnlock()
+	if b.state == blockStateClosed {
+		return false, ErrUnableToQueryBlockClosed
+	}
+
+	// NB: the query is only searched when it restricts the documents
+	// aggregated, otherwise all documents of each segment are aggregated.
+	var searcher search.Searcher
+	if !query.Equal(allQuery) {
+		var err error
+		searcher, err = query.SearchQuery().Searcher()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for _, seg := range b.segmentsWithRLock() {
+		exhaustive, err := b.aggregateSegment(seg, searcher, opts, results)
+		if err != nil {
+			return false, err
+		}
+		if !exhaustive {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (b *block) segmentsWithRLock() []segment.Segment {
+	segments := make([]segment.Segment, 0, 1+len(b.compactedSegments))
+	if b.activeSegment != nil {
+		segments = append(segments, b.activeSegment)
+	}
+	for _, compacted := range b.compactedSegments {
+		segments = append(segments, compacted.segment)
+	}
+	for _, group := range b.shardRangesSegments {
+		segments = append(segments, group.segments...)
+	}
+	return segments
+}
+
+func (b *block) aggregateSegment(
+	seg segment.Segment,
+	searcher search.Searcher,
+	opts AggregateQueryOptions,
+	results AggregateResults,
+) (bool, error) {
+	reader, err := seg.Reader()
+	if err != nil {
+		return false, err
+	}
+
+	readerCloser := safeCloser{closable: reader}
+	defer readerCloser.Close()
+
+	// NB: documents are iterated rather than counting the postings lists of
+	// each field and term so that a series indexed in several segments, or
+	// several blocks, is only counted once.
+	var docs doc.Iterator
+	if searcher == nil {
+		docs, err = reader.AllDocs()
+	} else {
+		var matches postings.List
+		matches, err = searcher.Search(reader)
+		if err != nil {
+			return false, err
+		}
+		if matches.IsEmpty() {
+			return true, readerCloser.Close()
+		}
+		docs, err = reader.Docs(matches)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	docsCloser := safeCloser{closable: docs}
+	defer docsCloser.Close()
+
+	exhaustive := true
+	for exhaustive && docs.Next() {
+		d := docs.Current()
+		if !results.AddDocumentID(d.ID) {
+			// Already aggregated from another segment or block.
+			continue
+		}
+
+		for _, field := range d.Fields {
+			if !aggregateFieldFilterMatches(opts.FieldFilter, field.Name) {
+				continue
+			}
+
+			if opts.Type == AggregateTagNames {
+				exhaustive = results.AddField(field.Name, 1)
+			} else {
+				exhaustive = results.AddFieldValue(field.Name, field.Value, 1)
+			}
+			if !exhaustive {
+				break
+			}
+		}
+	}
+
+	if err := docs.Err(); err != nil {
+		return false, err
+	}
+
+	if err := docsCloser.Close(); err != nil {
+		return false, err
+	}
+
+	if err := readerCloser.Close(); err != nil {
+		return false, err
+	}
+
+	return exhaustive, nil
+}
+
+func aggregateFieldFilterMatches(filter [][]byte, field []byte) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if bytes.Equal(f, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
+	"github.com/m3db/m3/src/dbnode/storage/index/segments"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	xerrors "github.com/m3db/m3x/errors"
+
+	"github.com/uber-go/tally"
+)
+
+// compactedSegment is an in-memory segment which has been rotated out of the
+// active segment of a block, either as is or as the result of a compaction.
+type compactedSegment struct {
+	segment     segment.Segment
+	segmentType segments.Type
+	createdAt   time.Time
+}
+
+type blockMetrics struct {
+	rotatedSegments    tally.Counter
+	compactions        tally.Counter
+	compactionErrors   tally.Counter
+	compactedSegments  tally.Counter
+	compactionLatency  tally.Timer
+	compactionsSkipped tally.Counter
+}
+
+func newBlockMetrics(s tally.Scope) blockMetrics {
+	s = s.SubScope("index-block").SubScope("compaction")
+	return blockMetrics{
+		rotatedSegments:    s.Counter("rotated-segments"),
+		compactions:        s.Counter("compactions"),
+		compactionErrors:   s.Counter("compaction-errors"),
+		compactedSegments:  s.Counter("compacted-segments"),
+		compactionLatency:  s.Timer("compaction-latency"),
+		compactionsSkipped: s.Counter("compactions-skipped"),
+	}
+}
+
+func (b *block) Compact() error {
+	tasks, ok, err := b.planCompaction(b.opts.CompactionPlannerOptions())
+	if err != nil || !ok {
+		return err
+	}
+
+	var multiErr xerrors.MultiError
+	for _, task := range tasks {
+		start := b.nowFn()
+		if err := b.compactTask(task); err != nil {
+			b.metrics.compactionErrors.Inc(1)
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		b.metrics.compactions.Inc(1)
+		b.metrics.compactedSegments.Inc(int64(len(task.Segments)))
+		b.metrics.compactionLatency.Record(b.nowFn().Sub(start))
+	}
+
+	return multiErr.FinalError()
+}
+
+// planCompaction rotates the active segment out if it is compactable and
+// returns the tasks of the plan returned by the compaction planner for the
+// rotated segments. The segments of the returned tasks are marked as being
+// compacted so that they remain open until the compaction is done reading
+// them. It returns false if the block is not open.
+func (b *block) planCompaction(
+	plannerOpts compaction.PlannerOptions,
+) ([]compaction.Task, bool, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.state != blockStateOpen || b.activeSegment == nil {
+		return nil, false, nil
+	}
+
+	now := b.nowFn()
+	active := compaction.Segment{
+		Age:     now.Sub(b.activeSegmentStart),
+		Size:    b.activeSegment.Size(),
+		Type:    segments.MutableType,
+		Segment: b.activeSegment,
+	}
+	if active.Size > 0 && active.Compactable(plannerOpts) {
+		if err := b.rotateActiveSegmentWithLock(now); err != nil {
+			b.opts.InstrumentOptions().Logger().Errorf(
+				"unable to rotate index block %v active segment: %v", b.startTime, err)
+		}
+	}
+
+	candidates := make([]compaction.Segment, 0, len(b.compactedSegments))
+	for _, compacted := range b.compactedSegments {
+		if _, ok := b.compacting[compacted.segment]; ok {
+			// Already being compacted by another compaction.
+			continue
+		}
+		candidates = append(candidates, compaction.Segment{
+			Age:     now.Sub(compacted.createdAt),
+			Size:    compacted.segment.Size(),
+			Type:    compacted.segmentType,
+			Segment: compacted.segment,
+		})
+	}
+
+	plan, err := compaction.NewPlan(candidates, plannerOpts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tasks := make([]compaction.Task, 0, len(plan.Tasks))
+	for _, task := range plan.Tasks {
+		if len(task.Segments) == 1 && task.Segments[0].Type == segments.FSTType {
+			// Nothing to gain from rewriting a single FST segment.
+			continue
+		}
+		for _, seg := range task.Segments {
+			b.compacting[seg.Segment] = false
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, true, nil
+}
+
+func (b *block) rotateActiveSegmentWithLock(now time.Time) error {
+	seg, err := mem.NewSegment(postings.ID(0), b.opts.MemSegmentOptions())
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.activeSegment.Seal(); err != nil {
+		seg.Close()
+		return err
+	}
+
+	b.compactedSegments = append(b.compactedSegments, compactedSegment{
+		segment:     b.activeSegment,
+		segmentType: segments.MutableType,
+		createdAt:   b.activeSegmentStart,
+	})
+	b.activeSegment = seg
+	b.activeSegmentStart = now
+	b.metrics.rotatedSegments.Inc(1)
+	return nil
+}
+
+// compactTask merges the segments of the task into a single FST segment and
+// swaps it in for them. NB: the segments of the task are sealed and were
+// marked as being compacted when the task was planned, so they can be read
+// without holding the block lock.
+func (b *block) compactTask(task compaction.Task) error {
+	srcs := make([]segment.Segment, 0, len(task.Segments))
+	for _, seg := range task.Segments {
+		srcs = append(srcs, seg.Segment)
+	}
+
+	compacted, compactErr := b.compactSegments(srcs)
+
+	b.Lock()
+	defer b.Unlock()
+
+	// NB: the segments may have been evicted or closed whilst compacting, in
+	// which case they are closed now and the result of the compaction is no
+	// longer required.
+	var multiErr xerrors.MultiError
+	evicted := false
+	for _, seg := range srcs {
+		if b.compacting[seg] {
+			evicted = true
+			multiErr = multiErr.Add(seg.Close())
+		}
+		delete(b.compacting, seg)
+	}
+
+	if compactErr != nil {
+		return multiErr.Add(compactErr).FinalError()
+	}
+
+	if b.state == blockStateClosed || evicted {
+		b.metrics.compactionsSkipped.Inc(1)
+		return multiErr.Add(compacted.Close()).FinalError()
+	}
+
+	remaining := make([]compactedSegment, 0, len(b.compactedSegments))
+	var earliest time.Time
+	for _, existing := range b.compactedSegments {
+		if !containsSegment(srcs, existing.segment) {
+			remaining = append(remaining, existing)
+			continue
+		}
+		multiErr = multiErr.Add(existing.segment.Close())
+		if earliest.IsZero() || existing.createdAt.Before(earliest) {
+			earliest = existing.createdAt
+		}
+	}
+
+	b.compactedSegments = append(remaining, compactedSegment{
+		segment:     compacted,
+		segmentType: segments.FSTType,
+		createdAt:   earliest,
+	})
+
+	return multiErr.FinalError()
+}
+
+// closeCompactedSegmentWithLock closes a segment rotated out of the active
+// segment, unless it is being compacted in which case closing it is left to
+// the compaction once it is done reading it.
+func (b *block) closeCompactedSegmentWithLock(seg segment.Segment) error {
+	if _, ok := b.compacting[seg]; ok {
+		b.compacting[seg] = true
+		return nil
+	}
+	return seg.Close()
+}
+
+// compactSegments merges the given segments into a single in-memory FST segment.
+func (b *block) compactSegments(srcs []segment.Segment) (segment.Segment, error) {
+	merged, err := mem.NewSegment(postings.ID(0), b.opts.MemSegmentOptions())
+	if err != nil {
+		return nil, err
+	}
+	defer merged.Close()
+
+	if err := mem.Merge(merged, srcs...); err != nil {
+		return nil, err
+	}
+
+	if _, err := merged.Seal(); err != nil {
+		return nil, err
+	}
+
+	w := fst.NewWriter()
+	if err := w.Reset(merged); err != nil {
+		return nil, err
+	}
+
+	var (
+		docsDataBuffer  bytes.Buffer
+		docsIndexBuffer bytes.Buffer
+		postingsBuffer  bytes.Buffer
+		fstTermsBuffer  bytes.Buffer
+		fstFieldsBuffer bytes.Buffer
+	)
+	if err := w.WriteDocumentsData(&docsDataBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WriteDocumentsIndex(&docsIndexBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WritePostingsOffsets(&postingsBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WriteFSTTerms(&fstTermsBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WriteFSTFields(&fstFieldsBuffer); err != nil {
+		return nil, err
+	}
+
+	return fst.NewSegment(fst.SegmentData{
+		MajorVersion:  w.MajorVersion(),
+		MinorVersion:  w.MinorVersion(),
+		Metadata:      w.Metadata(),
+		DocsData:      docsDataBuffer.Bytes(),
+		DocsIdxData:   docsIndexBuffer.Bytes(),
+		PostingsData:  postingsBuffer.Bytes(),
+		FSTTermsData:  fstTermsBuffer.Bytes(),
+		FSTFieldsData: fstFieldsBuffer.Bytes(),
+	}, b.opts.FSTSegmentOptions())
+}
+
+func containsSegment(segs []segment.Segment, seg segment.Segment) bool {
+	for _, s := range segs {
+		if s == seg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
+	"github.com/m3db/m3/src/dbnode/storage/index/segments"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCompactingBlock(t *testing.T, blockStart time.Time) *block {
+	plannerOpts := compaction.DefaultOptions
+	plannerOpts.MutableSegmentSizeThreshold = 1
+	opts := testOpts.
+		SetBackgroundCompactionInterval(0).
+		SetCompactionPlannerOptions(plannerOpts)
+
+	blk, err := NewBlock(blockStart, newTestNSMetadata(t), opts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+	return b
+}
+
+func writeTestDocs(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	b *block,
+	docs ...doc.Document,
+) {
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: b.blockSize,
+	})
+	for _, d := range docs {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(xtime.ToUnixNano(b.startTime))
+		h.EXPECT().OnIndexSuccess(xtime.ToUnixNano(b.startTime))
+		batch.Append(WriteBatchEntry{
+			Timestamp:     b.startTime.Add(time.Minute),
+			OnIndexSeries: h,
+		}, d)
+	}
+
+	res, err := b.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(docs)), res.NumSuccess)
+}
+
+func requireQueryIDs(t *testing.T, b *block, ids ...string) {
+	q, err := idx.NewRegexpQuery([]byte("bar"), []byte("b.*"))
+	require.NoError(t, err)
+	results := NewResults(testOpts)
+	exhaustive, err := b.Query(Query{q}, QueryOptions{}, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, len(ids), results.Size())
+	for _, id := range ids {
+		_, ok := results.Map().Get(ident.StringID(id))
+		require.True(t, ok)
+	}
+}
+
+func TestBlockCompact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestCompactingBlock(t, time.Now().Truncate(time.Hour))
+	defer b.Close()
+
+	// Nothing to compact while the active segment is empty.
+	require.NoError(t, b.Compact())
+	require.Empty(t, b.compactedSegments)
+
+	writeTestDocs(t, ctrl, b, testDoc1())
+	active := b.activeSegment
+	require.NoError(t, b.Compact())
+
+	// The active segment is rotated out and compacted into a FST segment.
+	require.NotEqual(t, active, b.activeSegment)
+	require.Equal(t, int64(0), b.activeSegment.Size())
+	require.Len(t, b.compactedSegments, 1)
+	require.Equal(t, segments.FSTType, b.compactedSegments[0].segmentType)
+	require.Equal(t, int64(1), b.compactedSegments[0].segment.Size())
+	requireQueryIDs(t, b, string(testDoc1().ID))
+
+	// The next rotated segment is compacted together with the previous one.
+	writeTestDocs(t, ctrl, b, testDoc2())
+	require.NoError(t, b.Compact())
+	require.Len(t, b.compactedSegments, 1)
+	require.Equal(t, segments.FSTType, b.compactedSegments[0].segmentType)
+	require.Equal(t, int64(2), b.compactedSegments[0].segment.Size())
+	requireQueryIDs(t, b, string(testDoc1().ID), string(testDoc2().ID))
+
+	result, err := b.Tick(nil, b.startTime)
+	require.NoError(t, err)
+	require.Equal(t, BlockTickResult{
+		NumSegments:        2,
+		NumMutableSegments: 1,
+		NumFSTSegments:     1,
+		NumDocs:            2,
+	}, result)
+}
+
+func TestBlockCompactSkipsSealedBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestCompactingBlock(t, time.Now().Truncate(time.Hour))
+	defer b.Close()
+
+	writeTestDocs(t, ctrl, b, testDoc1())
+	require.NoError(t, b.Seal())
+
+	active := b.activeSegment
+	require.NoError(t, b.Compact())
+	require.Equal(t, active, b.activeSegment)
+	require.Empty(t, b.compactedSegments)
+}
+
+func TestBlockEvictMutableSegmentsEvictsCompactedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestCompactingBlock(t, time.Now().Truncate(time.Hour))
+	defer b.Close()
+
+	writeTestDocs(t, ctrl, b, testDoc1(), testDoc2())
+	require.NoError(t, b.Compact())
+	require.Len(t, b.compactedSegments, 1)
+
+	require.NoError(t, b.Seal())
+	require.True(t, b.NeedsMutableSegmentsEvicted())
+
+	res, err := b.EvictMutableSegments()
+	require.NoError(t, err)
+	require.Equal(t, EvictMutableSegmentResults{
+		NumMutableSegments: 2,
+		NumDocs:            2,
+	}, res)
+	require.Empty(t, b.compactedSegments)
+	require.False(t, b.NeedsMutableSegmentsEvicted())
+}
+
+func TestBlockCompactDefersClosingEvictedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestCompactingBlock(t, time.Now().Truncate(time.Hour))
+	defer b.Close()
+
+	writeTestDocs(t, ctrl, b, testDoc1())
+	tasks, ok, err := b.planCompaction(b.opts.CompactionPlannerOptions())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, tasks, 1)
+	rotated := tasks[0].Segments[0].Segment
+
+	// Evicting the segments whilst the task is compacting them leaves the
+	// segments being compacted open.
+	require.NoError(t, b.Seal())
+	_, err = b.EvictMutableSegments()
+	require.NoError(t, err)
+	_, err = rotated.ContainsID(testDoc1().ID)
+	require.NoError(t, err)
+
+	// The compaction closes them once done and discards its result.
+	require.NoError(t, b.compactTask(tasks[0]))
+	_, err = rotated.ContainsID(testDoc1().ID)
+	require.Error(t, err)
+	require.Empty(t, b.compactedSegments)
+	require.Empty(t, b.compacting)
+}
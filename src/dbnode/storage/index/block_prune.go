@@ -0,0 +1,169 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"math"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+)
+
+// unboundedWriteTime is the write time cut off used by queries which cannot
+// prune segments or terms.
+const unboundedWriteTime = math.MaxInt64
+
+// executorOptions restrict the segments, and the terms within them, searched
+// by an executor to those which can contain series with data in a query's range.
+type executorOptions struct {
+	// writtenBefore is the time, in unix nanoseconds, at or after which
+	// segments whose documents were all first written at cannot contain
+	// series with data in the query's range.
+	writtenBefore int64
+
+	// pruneTerms is set if the terms within segments are pruned as well as
+	// the segments themselves.
+	pruneTerms bool
+}
+
+// executorOptions returns the options for executing the given query.
+//
+// NB: a series is indexed with the timestamp of the first datapoint written
+// for it in the block, and datapoints are only accepted for timestamps within
+// [now-bufferPast, now+bufferFuture]. Any datapoint written for the series
+// afterwards has a timestamp no earlier than the indexed timestamp less
+// bufferPast and bufferFuture, so series first written at or after the end of
+// the query's range plus both buffers cannot have data in the range.
+func (b *block) executorOptions(query Query, opts QueryOptions) executorOptions {
+	if opts.EndExclusive.IsZero() {
+		return executorOptions{writtenBefore: unboundedWriteTime}
+	}
+
+	if !opts.EndExclusive.Before(b.endTime) {
+		// i.e. every document in the block could have data in the query's range.
+		return executorOptions{writtenBefore: unboundedWriteTime}
+	}
+
+	retOpts := b.nsMD.Options().RetentionOptions()
+	buffer := retOpts.BufferPast() + retOpts.BufferFuture()
+
+	// NB: terms cannot be pruned for queries with negations as the segments
+	// match every document when evaluating them.
+	searchQuery := query.Query.SearchQuery()
+	return executorOptions{
+		writtenBefore: opts.EndExclusive.Add(buffer).UnixNano(),
+		pruneTerms:    searchQuery != nil && !queryHasNegation(searchQuery.ToProto()),
+	}
+}
+
+// segmentReader returns a reader for the given segment restricted by the
+// given options, the reader does not match any documents if the segment's
+// documents were all written at or after the cut off.
+func segmentReader(
+	seg segment.Segment,
+	opts executorOptions,
+) (m3ninxindex.Reader, error) {
+	if opts.writtenBefore == unboundedWriteTime {
+		return seg.Reader()
+	}
+
+	if writeTimes, ok := seg.(segment.WriteTimes); ok &&
+		writeTimes.EarliestWriteTime() >= opts.writtenBefore {
+		// NB: an empty reader is returned rather than skipping the segment
+		// so that the reader indexes used by query cursors remain stable.
+		return emptyReader{}, nil
+	}
+
+	if writtenBefore, ok := seg.(segment.WrittenBeforeSegment); ok && opts.pruneTerms {
+		return writtenBefore.ReaderWrittenBefore(opts.writtenBefore)
+	}
+
+	return seg.Reader()
+}
+
+// queryHasNegation returns whether the given query contains a negation.
+func queryHasNegation(q *querypb.Query) bool {
+	switch q := q.GetQuery().(type) {
+	case *querypb.Query_Negation:
+		return true
+	case *querypb.Query_Conjunction:
+		for _, inner := range q.Conjunction.GetQueries() {
+			if queryHasNegation(inner) {
+				return true
+			}
+		}
+	case *querypb.Query_Disjunction:
+		for _, inner := range q.Disjunction.GetQueries() {
+			if queryHasNegation(inner) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// emptyReader is a reader which does not match any documents.
+type emptyReader struct{}
+
+var _ m3ninxindex.Reader = emptyReader{}
+
+func (r emptyReader) MatchTerm(field, term []byte) (postings.List, error) {
+	return roaring.NewPostingsList(), nil
+}
+
+func (r emptyReader) MatchRegexp(
+	field []byte,
+	c m3ninxindex.CompiledRegex,
+) (postings.List, error) {
+	return roaring.NewPostingsList(), nil
+}
+
+func (r emptyReader) MatchField(field []byte) (postings.List, error) {
+	return roaring.NewPostingsList(), nil
+}
+
+func (r emptyReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	return roaring.NewPostingsList(), nil
+}
+
+func (r emptyReader) MatchAll() (postings.MutableList, error) {
+	return roaring.NewPostingsList(), nil
+}
+
+func (r emptyReader) Doc(id postings.ID) (doc.Document, error) {
+	return doc.Document{}, m3ninxindex.ErrDocNotFound
+}
+
+func (r emptyReader) Docs(pl postings.List) (doc.Iterator, error) {
+	return m3ninxindex.NewIDDocIterator(r, pl.Iterator()), nil
+}
+
+func (r emptyReader) AllDocs() (m3ninxindex.IDDocIterator, error) {
+	return m3ninxindex.NewIDDocIterator(r, postings.NewRangeIterator(0, 0)), nil
+}
+
+func (r emptyReader) Close() error {
+	return nil
+}
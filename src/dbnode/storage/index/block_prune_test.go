@@ -0,0 +1,356 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/index/segments"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+// NB: background compaction is disabled as the tests set the block's
+// segments directly.
+var testPruneOpts = testOpts.SetBackgroundCompactionInterval(0)
+
+func TestBlockQueryPrunesSegmentsByWriteTime(t *testing.T) {
+	testMD := newTestNSMetadata(t)
+	blockStart := time.Now().Truncate(time.Hour)
+
+	blk, err := NewBlock(blockStart, testMD, testPruneOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	early := testPruneDoc("early", "baz")
+	late := testPruneDoc("late", "baz")
+	b.compactedSegments = []compactedSegment{
+		newTestWriteTimesSegment(t, b, []doc.Document{early},
+			[]time.Time{blockStart.Add(time.Minute)}),
+		newTestWriteTimesSegment(t, b, []doc.Document{late},
+			[]time.Time{blockStart.Add(40 * time.Minute)}),
+	}
+
+	// The late segment is skipped as its documents were written after the end
+	// of the range plus the buffer past and buffer future.
+	q := Query{idx.NewTermQuery([]byte("bar"), []byte("baz"))}
+	results := NewResults(testOpts)
+	_, err = b.Query(q, QueryOptions{
+		StartInclusive: blockStart,
+		EndExclusive:   blockStart.Add(10 * time.Minute),
+	}, results)
+	require.NoError(t, err)
+	requireResultIDs(t, results, "early")
+
+	// Both segments are searched once the range covers the late write.
+	results = NewResults(testOpts)
+	_, err = b.Query(q, QueryOptions{
+		StartInclusive: blockStart,
+		EndExclusive:   blockStart.Add(30 * time.Minute),
+	}, results)
+	require.NoError(t, err)
+	requireResultIDs(t, results, "early", "late")
+
+	require.NoError(t, b.Close())
+}
+
+func TestBlockQueryPrunesTermsByWriteTime(t *testing.T) {
+	testMD := newTestNSMetadata(t)
+	blockStart := time.Now().Truncate(time.Hour)
+
+	blk, err := NewBlock(blockStart, testMD, testPruneOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	b.compactedSegments = []compactedSegment{
+		newTestWriteTimesSegment(t, b,
+			[]doc.Document{testPruneDoc("early", "baz"), testPruneDoc("late", "qux")},
+			[]time.Time{blockStart.Add(time.Minute), blockStart.Add(40 * time.Minute)}),
+	}
+	opts := QueryOptions{
+		StartInclusive: blockStart,
+		EndExclusive:   blockStart.Add(10 * time.Minute),
+	}
+
+	results := NewResults(testOpts)
+	_, err = b.Query(Query{idx.NewFieldQuery([]byte("bar"))}, opts, results)
+	require.NoError(t, err)
+	requireResultIDs(t, results, "early")
+
+	// Terms are not pruned for queries with negations.
+	results = NewResults(testOpts)
+	q := idx.NewNegationQuery(idx.NewTermQuery([]byte("bar"), []byte("baz")))
+	_, err = b.Query(Query{q}, opts, results)
+	require.NoError(t, err)
+	requireResultIDs(t, results, "late")
+
+	require.NoError(t, b.Close())
+}
+
+func TestBlockEarliestWriteTimes(t *testing.T) {
+	testMD := newTestNSMetadata(t)
+	blockStart := time.Now().Truncate(time.Hour)
+
+	blk, err := NewBlock(blockStart, testMD, testPruneOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	batch := index.NewBatch([]doc.Document{testPruneDoc("foo", "baz")})
+	batch.WriteTimes = []int64{blockStart.Add(20 * time.Minute).UnixNano()}
+	require.NoError(t, b.activeSegment.InsertBatch(batch))
+
+	b.compactedSegments = []compactedSegment{
+		newTestWriteTimesSegment(t, b, []doc.Document{testPruneDoc("foo", "baz")},
+			[]time.Time{blockStart.Add(10 * time.Minute)}),
+	}
+
+	writeTimes, err := b.EarliestWriteTimes()
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{
+		"foo": blockStart.Add(10 * time.Minute).UnixNano(),
+	}, writeTimes)
+
+	require.NoError(t, b.Close())
+	_, err = b.EarliestWriteTimes()
+	require.Error(t, err)
+}
+
+func TestBlockMergeSegments(t *testing.T) {
+	testMD := newTestNSMetadata(t)
+	blockStart := time.Now().Truncate(time.Hour)
+
+	blk, err := NewBlock(blockStart, testMD, testPruneOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	foo := testPruneDoc("foo", "baz")
+	foo.Fields = append(foo.Fields, doc.Field{
+		Name:  []byte("owner"),
+		Value: []byte("infra"),
+	})
+	batch := index.NewBatch([]doc.Document{foo})
+	batch.WriteTimes = []int64{blockStart.Add(20 * time.Minute).UnixNano()}
+	require.NoError(t, b.activeSegment.InsertBatch(batch))
+
+	b.compactedSegments = []compactedSegment{
+		newTestWriteTimesSegment(t, b, []doc.Document{testPruneDoc("bar", "baz")},
+			[]time.Time{blockStart.Add(10 * time.Minute)}),
+	}
+
+	target, err := mem.NewSegment(0, testPruneOpts.MemSegmentOptions())
+	require.NoError(t, err)
+	require.NoError(t, b.MergeSegments(target))
+
+	writeTimes, ok := target.(segment.WriteTimes)
+	require.True(t, ok)
+	for _, test := range []struct {
+		id        string
+		writeTime time.Time
+	}{
+		{id: "foo", writeTime: blockStart.Add(20 * time.Minute)},
+		{id: "bar", writeTime: blockStart.Add(10 * time.Minute)},
+	} {
+		writeTime, ok, err := writeTimes.TermEarliestWriteTime(doc.IDReservedFieldName, []byte(test.id))
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, test.writeTime.UnixNano(), writeTime)
+	}
+
+	// Document fields beyond the series tags are retained.
+	reader, err := target.Reader()
+	require.NoError(t, err)
+	pl, err := reader.MatchTerm([]byte("owner"), []byte("infra"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	require.NoError(t, reader.Close())
+
+	require.NoError(t, target.Close())
+	require.NoError(t, b.Close())
+	require.Error(t, b.MergeSegments(target))
+}
+
+func TestQueryHasNegation(t *testing.T) {
+	term := idx.NewTermQuery([]byte("bar"), []byte("baz"))
+	tests := []struct {
+		query    idx.Query
+		expected bool
+	}{
+		{query: term, expected: false},
+		{query: idx.NewConjunctionQuery(term, idx.NewFieldQuery([]byte("qux"))), expected: false},
+		{query: idx.NewNegationQuery(term), expected: true},
+		{query: idx.NewConjunctionQuery(term, idx.NewNegationQuery(term)), expected: true},
+		{query: idx.NewDisjunctionQuery(term, idx.NewNegationQuery(term)), expected: true},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, queryHasNegation(test.query.SearchQuery().ToProto()),
+			test.query.String())
+	}
+}
+
+// BenchmarkBlockQueryNarrowRange queries a metric name written throughout a
+// two hour block whose segments each hold the series first written in ten
+// minutes of it, for a range covering the whole block and for a narrow range.
+func BenchmarkBlockQueryNarrowRange(b *testing.B) {
+	const (
+		blockSize         = 2 * time.Hour
+		segmentSpan       = 10 * time.Minute
+		seriesPerSegment  = 1000
+		namesPerSegment   = 100
+		numNamesQueried   = 10
+		narrowQueryLength = 5 * time.Minute
+	)
+
+	ropts := retention.NewOptions().
+		SetBlockSize(blockSize).
+		SetRetentionPeriod(24 * time.Hour)
+	iopts := namespace.NewIndexOptions().
+		SetEnabled(true).
+		SetBlockSize(blockSize)
+	md, err := namespace.NewMetadata(ident.StringID("testNs"),
+		namespace.NewOptions().SetRetentionOptions(ropts).SetIndexOptions(iopts))
+	require.NoError(b, err)
+
+	blockStart := time.Now().Truncate(blockSize)
+	blk, err := NewBlock(blockStart, md, testPruneOpts)
+	require.NoError(b, err)
+	blck := blk.(*block)
+	defer blck.Close()
+
+	for start := blockStart; start.Before(blockStart.Add(blockSize)); start = start.Add(segmentSpan) {
+		var (
+			docs       = make([]doc.Document, 0, seriesPerSegment)
+			writeTimes = make([]time.Time, 0, seriesPerSegment)
+		)
+		for i := 0; i < seriesPerSegment; i++ {
+			name := fmt.Sprintf("metric-%d", (start.Unix()+int64(i))%namesPerSegment)
+			docs = append(docs, doc.Document{
+				ID: []byte(fmt.Sprintf("%s-%d-%d", name, start.Unix(), i)),
+				Fields: []doc.Field{
+					{Name: []byte("__name__"), Value: []byte(name)},
+					{Name: []byte("host"), Value: []byte(fmt.Sprintf("host-%d", i))},
+				},
+			})
+			writeTimes = append(writeTimes, start.Add(time.Duration(i)*segmentSpan/seriesPerSegment))
+		}
+		blck.compactedSegments = append(blck.compactedSegments,
+			newTestWriteTimesSegment(b, blck, docs, writeTimes))
+	}
+
+	queries := make([]Query, 0, numNamesQueried)
+	for i := 0; i < numNamesQueried; i++ {
+		q, err := idx.NewRegexpQuery([]byte("__name__"), []byte(fmt.Sprintf("metric-%d.*", i)))
+		require.NoError(b, err)
+		queries = append(queries, Query{q})
+	}
+
+	for _, bench := range []struct {
+		name string
+		opts QueryOptions
+	}{
+		{
+			name: "block range",
+			opts: QueryOptions{
+				StartInclusive: blockStart,
+				EndExclusive:   blockStart.Add(blockSize),
+			},
+		},
+		{
+			name: "narrow range",
+			opts: QueryOptions{
+				StartInclusive: blockStart,
+				EndExclusive:   blockStart.Add(narrowQueryLength),
+			},
+		},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				results := NewResults(testOpts)
+				_, err := blck.Query(queries[n%len(queries)], bench.opts, results)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func newTestWriteTimesSegment(
+	t require.TestingT,
+	b *block,
+	docs []doc.Document,
+	writeTimes []time.Time,
+) compactedSegment {
+	seg, err := mem.NewSegment(0, b.opts.MemSegmentOptions())
+	require.NoError(t, err)
+
+	batch := index.NewBatch(docs)
+	for _, writeTime := range writeTimes {
+		batch.WriteTimes = append(batch.WriteTimes, writeTime.UnixNano())
+	}
+	require.NoError(t, seg.InsertBatch(batch))
+
+	compacted, err := b.compactSegments([]segment.Segment{seg})
+	require.NoError(t, err)
+	require.NoError(t, seg.Close())
+
+	return compactedSegment{
+		segment:     compacted,
+		segmentType: segments.FSTType,
+		createdAt:   time.Now(),
+	}
+}
+
+func testPruneDoc(id, value string) doc.Document {
+	return doc.Document{
+		ID: []byte(id),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("bar"),
+				Value: []byte(value),
+			},
+		},
+	}
+}
+
+func requireResultIDs(t *testing.T, results Results, ids ...string) {
+	require.Equal(t, len(ids), results.Size())
+	for _, id := range ids {
+		_, ok := results.Map().Get(ident.StringID(id))
+		require.True(t, ok, id)
+	}
+}
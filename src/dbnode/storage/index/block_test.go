@@ -21,6 +21,7 @@
 package index
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
@@ -196,6 +197,10 @@ func TestBlockWriteMockSegment(t *testing.T) {
 		index.Batch{
 			Docs:                []doc.Document{testDoc1(), testDoc1DupeID()},
 			AllowPartialUpdates: true,
+			WriteTimes: []int64{
+				nowNotBlockStartAligned.UnixNano(),
+				nowNotBlockStartAligned.UnixNano(),
+			},
 		},
 	)).Return(nil)
 
@@ -314,6 +319,10 @@ func TestBlockWriteMockSegmentPartialFailure(t *testing.T) {
 		index.Batch{
 			Docs:                []doc.Document{testDoc1(), testDoc1DupeID()},
 			AllowPartialUpdates: true,
+			WriteTimes: []int64{
+				nowNotBlockStartAligned.UnixNano(),
+				nowNotBlockStartAligned.UnixNano(),
+			},
 		},
 	)).Return(berr)
 
@@ -386,6 +395,10 @@ func TestBlockWriteMockSegmentUnexpectedErrorType(t *testing.T) {
 		index.Batch{
 			Docs:                []doc.Document{testDoc1(), testDoc1DupeID()},
 			AllowPartialUpdates: true,
+			WriteTimes: []int64{
+				nowNotBlockStartAligned.UnixNano(),
+				nowNotBlockStartAligned.UnixNano(),
+			},
 		},
 	)).Return(testErr)
 
@@ -442,7 +455,7 @@ func TestBlockQueryExecutorError(t *testing.T) {
 	b, ok := blk.(*block)
 	require.True(t, ok)
 
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		b.RLock() // ensures we call newExecutorFn with RLock, or this would deadlock
 		defer b.RUnlock()
 		return nil, fmt.Errorf("random-err")
@@ -522,7 +535,7 @@ func TestBlockMockQueryExecutorExecError(t *testing.T) {
 
 	// dIter:= doc.NewMockIterator(ctrl)
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 	gomock.InOrder(
@@ -546,7 +559,7 @@ func TestBlockMockQueryExecutorExecIterErr(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -577,7 +590,7 @@ func TestBlockMockQueryExecutorExecLimit(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -618,7 +631,7 @@ func TestBlockMockQueryExecutorExecIterCloseErr(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -648,7 +661,7 @@ func TestBlockMockQueryExecutorExecIterExecCloseErr(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -678,7 +691,7 @@ func TestBlockMockQueryLimit(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -719,7 +732,7 @@ func TestBlockMockQueryLimitExhaustive(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -761,7 +774,7 @@ func TestBlockMockQueryMergeResultsMapLimit(t *testing.T) {
 	require.NoError(t, b.Seal())
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -803,7 +816,7 @@ func TestBlockMockQueryMergeResultsDupeID(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -1469,6 +1482,289 @@ func testSegment(t *testing.T, docs ...doc.Document) segment.Segment {
 	return seg
 }
 
+func newTestAggregateBlock(t *testing.T, ctrl *gomock.Controller) *block {
+	blockSize := time.Hour
+	testMD := newTestNSMetadata(t)
+	blockStart := time.Now().Truncate(blockSize)
+
+	blk, err := NewBlock(blockStart, testMD, testOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: blockSize,
+	})
+	for _, d := range []doc.Document{testDoc1(), testDoc2()} {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(xtime.ToUnixNano(blockStart))
+		h.EXPECT().OnIndexSuccess(xtime.ToUnixNano(blockStart))
+		batch.Append(WriteBatchEntry{
+			Timestamp:     blockStart.Add(time.Minute),
+			OnIndexSeries: h,
+		}, d)
+	}
+
+	res, err := b.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.NumSuccess)
+	return b
+}
+
+func TestBlockE2EAggregateTagNamesAndValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestAggregateBlock(t, ctrl)
+	results := NewAggregateResults(0)
+	exhaustive, err := b.Aggregate(Query{idx.NewAllQuery()},
+		AggregateQueryOptions{Type: AggregateTagNamesAndValues}, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, []AggregateField{
+		{
+			Name:   []byte("bar"),
+			Count:  2,
+			Values: []AggregateValue{{Value: []byte("baz"), Count: 2}},
+		},
+		{
+			Name:   []byte("some"),
+			Count:  1,
+			Values: []AggregateValue{{Value: []byte("more"), Count: 1}},
+		},
+	}, results.Fields())
+}
+
+func TestBlockE2EAggregateCountsSeriesOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Aggregating the same documents twice into the results, as happens for
+	// a series indexed in several blocks, counts each series once.
+	b := newTestAggregateBlock(t, ctrl)
+	results := NewAggregateResults(0)
+	for i := 0; i < 2; i++ {
+		exhaustive, err := b.Aggregate(Query{idx.NewAllQuery()},
+			AggregateQueryOptions{Type: AggregateTagNames}, results)
+		require.NoError(t, err)
+		require.True(t, exhaustive)
+	}
+	require.Equal(t, []AggregateField{
+		{Name: []byte("bar"), Count: 2},
+		{Name: []byte("some"), Count: 1},
+	}, results.Fields())
+}
+
+func TestBlockE2EAggregateTagNamesWithQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestAggregateBlock(t, ctrl)
+	q := idx.NewTermQuery([]byte("some"), []byte("more"))
+	results := NewAggregateResults(0)
+	exhaustive, err := b.Aggregate(Query{q},
+		AggregateQueryOptions{Type: AggregateTagNames}, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, []AggregateField{
+		{Name: []byte("bar"), Count: 1},
+		{Name: []byte("some"), Count: 1},
+	}, results.Fields())
+}
+
+func TestBlockE2EAggregateFieldFilterAndLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := newTestAggregateBlock(t, ctrl)
+	results := NewAggregateResults(0)
+	exhaustive, err := b.Aggregate(Query{idx.NewAllQuery()}, AggregateQueryOptions{
+		FieldFilter: [][]byte{[]byte("some")},
+		Type:        AggregateTagNamesAndValues,
+	}, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, []AggregateField{
+		{
+			Name:   []byte("some"),
+			Count:  1,
+			Values: []AggregateValue{{Value: []byte("more"), Count: 1}},
+		},
+	}, results.Fields())
+
+	results = NewAggregateResults(1)
+	exhaustive, err = b.Aggregate(Query{idx.NewAllQuery()},
+		AggregateQueryOptions{Type: AggregateTagNames}, results)
+	require.NoError(t, err)
+	require.False(t, exhaustive)
+	require.Equal(t, 1, results.Size())
+}
+
+func TestBlockAggregateAfterClose(t *testing.T) {
+	testMD := newTestNSMetadata(t)
+	b, err := NewBlock(time.Now().Truncate(time.Hour), testMD, testOpts)
+	require.NoError(t, err)
+	require.NoError(t, b.Close())
+
+	_, err = b.Aggregate(Query{idx.NewAllQuery()}, AggregateQueryOptions{},
+		NewAggregateResults(0))
+	require.Equal(t, ErrUnableToQueryBlockClosed, err)
+}
+
+func newTestQueryPageBlock(
+	t *testing.T,
+	ctrl *gomock.Controller,
+) (*block, *search.MockExecutor) {
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func(executorOptions) (search.Executor, error) {
+		return exec, nil
+	}
+	return b, exec
+}
+
+func TestBlockQueryPageResumesAfterCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b, exec := newTestQueryPageBlock(t, ctrl)
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().Execute(gomock.Any()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+
+	cursor := QueryCursor{BlockStart: b.startTime, DocID: testDoc1().ID}
+	results := NewResults(testOpts)
+	next, exhaustive, err := b.QueryPage(Query{}, QueryOptions{Limit: 1}, cursor, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, QueryCursor{
+		BlockStart: b.startTime,
+		DocID:      testDoc2().ID,
+	}, next)
+
+	rMap := results.Map()
+	require.Equal(t, 1, rMap.Len())
+	_, ok := rMap.Get(ident.StringID(string(testDoc2().ID)))
+	require.True(t, ok)
+}
+
+func TestBlockQueryFilterID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b, exec := newTestQueryPageBlock(t, ctrl)
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().Execute(gomock.Any()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+
+	opts := QueryOptions{
+		FilterID: func(id []byte) bool {
+			return bytes.Equal(id, testDoc2().ID)
+		},
+	}
+	results := NewResults(testOpts)
+	exhaustive, err := b.Query(Query{}, opts, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+
+	rMap := results.Map()
+	require.Equal(t, 1, rMap.Len())
+	_, ok := rMap.Get(ident.StringID(string(testDoc2().ID)))
+	require.True(t, ok)
+}
+
+func TestBlockQueryPageLimitReturnsSmallestIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b, exec := newTestQueryPageBlock(t, ctrl)
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().Execute(gomock.Any()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+
+	results := NewResults(testOpts)
+	next, exhaustive, err := b.QueryPage(Query{}, QueryOptions{Limit: 1}, QueryCursor{}, results)
+	require.NoError(t, err)
+	require.False(t, exhaustive)
+	require.Equal(t, QueryCursor{
+		BlockStart: b.startTime,
+		DocID:      testDoc1().ID,
+	}, next)
+
+	rMap := results.Map()
+	require.Equal(t, 1, rMap.Len())
+	_, ok := rMap.Get(ident.StringID(string(testDoc1().ID)))
+	require.True(t, ok)
+}
+
+func TestBlockQueryPageCursorDocumentNoLongerPresent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b, exec := newTestQueryPageBlock(t, ctrl)
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().Execute(gomock.Any()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+
+	// The cursor's document is no longer in the block, e.g. it was removed
+	// when a segment was compacted, so the page resumes after its ID without
+	// returning documents from a previous page again.
+	cursor := QueryCursor{BlockStart: b.startTime, DocID: []byte("goo")}
+	results := NewResults(testOpts)
+	next, exhaustive, err := b.QueryPage(Query{}, QueryOptions{}, cursor, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, cursor, next)
+
+	rMap := results.Map()
+	require.Equal(t, 1, rMap.Len())
+	_, ok := rMap.Get(ident.StringID(string(testDoc2().ID)))
+	require.True(t, ok)
+}
+
 func testDoc1() doc.Document {
 	return doc.Document{
 		ID: []byte("foo"),
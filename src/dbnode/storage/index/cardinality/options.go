@@ -0,0 +1,182 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	// MinPrecision is the min precision of the sketches.
+	MinPrecision = 4
+
+	// MaxPrecision is the max precision of the sketches.
+	MaxPrecision = 16
+
+	defaultPrecision        = 10
+	defaultBlockSize        = 2 * time.Hour
+	defaultRetentionPeriod  = 2 * 24 * time.Hour
+	defaultMaxNamesPerBlock = 100000
+	defaultMetricsTopN      = 10
+)
+
+var (
+	defaultMetricNameTag = []byte("__name__")
+
+	errBlockSizeNotPositive        = errors.New("cardinality block size must be positive")
+	errRetentionPeriodNegative     = errors.New("cardinality retention period must not be negative")
+	errMaxNamesPerBlockNotPositive = errors.New("cardinality max names per block must be positive")
+	errMetricsTopNNegative         = errors.New("cardinality metrics top N must not be negative")
+	errConcurrencyNotPositive      = errors.New("cardinality concurrency must be positive")
+)
+
+type options struct {
+	instrumentOpts   instrument.Options
+	precision        uint8
+	metricNameTag    []byte
+	blockSize        time.Duration
+	retentionPeriod  time.Duration
+	maxNamesPerBlock int
+	metricsTopN      int
+	concurrency      int
+}
+
+// NewOptions creates a new set of cardinality tracker options.
+func NewOptions() Options {
+	return &options{
+		instrumentOpts:   instrument.NewOptions(),
+		precision:        defaultPrecision,
+		metricNameTag:    defaultMetricNameTag,
+		blockSize:        defaultBlockSize,
+		retentionPeriod:  defaultRetentionPeriod,
+		maxNamesPerBlock: defaultMaxNamesPerBlock,
+		metricsTopN:      defaultMetricsTopN,
+		concurrency:      runtime.NumCPU(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.precision < MinPrecision || o.precision > MaxPrecision {
+		return fmt.Errorf("cardinality precision %d must be between %d and %d",
+			o.precision, MinPrecision, MaxPrecision)
+	}
+	if o.blockSize <= 0 {
+		return errBlockSizeNotPositive
+	}
+	if o.retentionPeriod < 0 {
+		return errRetentionPeriodNegative
+	}
+	if o.maxNamesPerBlock <= 0 {
+		return errMaxNamesPerBlockNotPositive
+	}
+	if o.metricsTopN < 0 {
+		return errMetricsTopNNegative
+	}
+	if o.concurrency <= 0 {
+		return errConcurrencyNotPositive
+	}
+	return nil
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *options) SetPrecision(value uint8) Options {
+	opts := *o
+	opts.precision = value
+	return &opts
+}
+
+func (o *options) Precision() uint8 {
+	return o.precision
+}
+
+func (o *options) SetMetricNameTag(value []byte) Options {
+	opts := *o
+	opts.metricNameTag = value
+	return &opts
+}
+
+func (o *options) MetricNameTag() []byte {
+	return o.metricNameTag
+}
+
+func (o *options) SetBlockSize(value time.Duration) Options {
+	opts := *o
+	opts.blockSize = value
+	return &opts
+}
+
+func (o *options) BlockSize() time.Duration {
+	return o.blockSize
+}
+
+func (o *options) SetRetentionPeriod(value time.Duration) Options {
+	opts := *o
+	opts.retentionPeriod = value
+	return &opts
+}
+
+func (o *options) RetentionPeriod() time.Duration {
+	return o.retentionPeriod
+}
+
+func (o *options) SetMaxNamesPerBlock(value int) Options {
+	opts := *o
+	opts.maxNamesPerBlock = value
+	return &opts
+}
+
+func (o *options) MaxNamesPerBlock() int {
+	return o.maxNamesPerBlock
+}
+
+func (o *options) SetMetricsTopN(value int) Options {
+	opts := *o
+	opts.metricsTopN = value
+	return &opts
+}
+
+func (o *options) MetricsTopN() int {
+	return o.metricsTopN
+}
+
+func (o *options) SetConcurrency(value int) Options {
+	opts := *o
+	opts.concurrency = value
+	return &opts
+}
+
+func (o *options) Concurrency() int {
+	return o.concurrency
+}
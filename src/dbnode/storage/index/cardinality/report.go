@@ -0,0 +1,81 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultReportLimit is the default max number of names to report.
+	DefaultReportLimit = 10
+
+	startParam = "start"
+	endParam   = "end"
+	limitParam = "limit"
+)
+
+// ParseReportOptions parses report options from URL query values, times
+// may be specified as unix seconds or in RFC3339 format.
+func ParseReportOptions(values url.Values) (ReportOptions, error) {
+	opts := ReportOptions{Limit: DefaultReportLimit}
+
+	var err error
+	if opts.Start, err = parseTimeParam(values, startParam); err != nil {
+		return ReportOptions{}, err
+	}
+	if opts.End, err = parseTimeParam(values, endParam); err != nil {
+		return ReportOptions{}, err
+	}
+	if !opts.Start.IsZero() && !opts.End.IsZero() && !opts.Start.Before(opts.End) {
+		return ReportOptions{}, fmt.Errorf("%s must be before %s", startParam, endParam)
+	}
+
+	if str := values.Get(limitParam); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil || limit <= 0 {
+			return ReportOptions{}, fmt.Errorf("%s must be a positive integer: %s", limitParam, str)
+		}
+		opts.Limit = limit
+	}
+
+	return opts, nil
+}
+
+func parseTimeParam(values url.Values, param string) (time.Time, error) {
+	str := values.Get(param)
+	if str == "" {
+		return time.Time{}, nil
+	}
+	if secs, err := strconv.ParseFloat(str, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*float64(time.Second))), nil
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be unix seconds or RFC3339: %s", param, str)
+	}
+	return t, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReportOptions(t *testing.T) {
+	opts, err := ParseReportOptions(url.Values{})
+	require.NoError(t, err)
+	require.Equal(t, ReportOptions{Limit: DefaultReportLimit}, opts)
+
+	opts, err = ParseReportOptions(url.Values{
+		"start": []string{"1500000000"},
+		"end":   []string{"2017-07-14T03:40:00Z"},
+		"limit": []string{"5"},
+	})
+	require.NoError(t, err)
+	require.True(t, time.Unix(1500000000, 0).Equal(opts.Start))
+	require.True(t, time.Unix(1500003600, 0).Equal(opts.End))
+	require.Equal(t, 5, opts.Limit)
+}
+
+func TestParseReportOptionsInvalid(t *testing.T) {
+	for _, values := range []url.Values{
+		{"start": []string{"yesterday"}},
+		{"end": []string{"tomorrow"}},
+		{"start": []string{"2"}, "end": []string{"1"}},
+		{"limit": []string{"0"}},
+		{"limit": []string{"ten"}},
+	} {
+		_, err := ParseReportOptions(values)
+		require.Error(t, err, values.Encode())
+	}
+}
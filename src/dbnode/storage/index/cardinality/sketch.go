@@ -0,0 +1,158 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/cespare/xxhash"
+)
+
+// Sketch is a HyperLogLog sketch that estimates the number of distinct
+// values added to it. Registers are kept sparsely until enough of them
+// are set for a dense representation to be smaller.
+type Sketch struct {
+	precision uint8
+	sparse    map[uint32]uint8
+	dense     []uint8
+}
+
+// NewSketch returns a new sketch with the given precision, which must be
+// between MinPrecision and MaxPrecision.
+func NewSketch(precision uint8) *Sketch {
+	return &Sketch{
+		precision: precision,
+		sparse:    make(map[uint32]uint8),
+	}
+}
+
+// Add adds a value to the sketch.
+func (s *Sketch) Add(value []byte) {
+	s.AddHash(xxhash.Sum64(value))
+}
+
+// AddHash adds the hash of a value to the sketch.
+func (s *Sketch) AddHash(hash uint64) {
+	idx := uint32(hash >> (64 - s.precision))
+	// Guard bit ensures the rank is bounded when the remaining bits are zero.
+	rest := hash<<s.precision | 1<<(s.precision-1)
+	s.setMax(idx, uint8(bits.LeadingZeros64(rest)+1))
+}
+
+// Merge merges another sketch with the same precision into the sketch.
+func (s *Sketch) Merge(other *Sketch) {
+	if other.dense != nil {
+		for idx, rank := range other.dense {
+			if rank > 0 {
+				s.setMax(uint32(idx), rank)
+			}
+		}
+		return
+	}
+	for idx, rank := range other.sparse {
+		s.setMax(idx, rank)
+	}
+}
+
+// Clone returns a copy of the sketch.
+func (s *Sketch) Clone() *Sketch {
+	clone := &Sketch{precision: s.precision}
+	if s.dense != nil {
+		clone.dense = append([]uint8(nil), s.dense...)
+		return clone
+	}
+	clone.sparse = make(map[uint32]uint8, len(s.sparse))
+	for idx, rank := range s.sparse {
+		clone.sparse[idx] = rank
+	}
+	return clone
+}
+
+// Estimate returns the estimated number of distinct values added.
+func (s *Sketch) Estimate() uint64 {
+	var (
+		m     = s.numRegisters()
+		sum   float64
+		zeros int
+	)
+	if s.dense != nil {
+		for _, rank := range s.dense {
+			if rank == 0 {
+				zeros++
+			}
+			sum += 1.0 / float64(uint64(1)<<rank)
+		}
+	} else {
+		zeros = m - len(s.sparse)
+		sum = float64(zeros)
+		for _, rank := range s.sparse {
+			sum += 1.0 / float64(uint64(1)<<rank)
+		}
+	}
+
+	fm := float64(m)
+	estimate := alpha(m) * fm * fm / sum
+	if estimate <= 2.5*fm && zeros > 0 {
+		// Use linear counting for small cardinalities.
+		estimate = fm * math.Log(fm/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func (s *Sketch) setMax(idx uint32, rank uint8) {
+	if s.dense != nil {
+		if rank > s.dense[idx] {
+			s.dense[idx] = rank
+		}
+		return
+	}
+
+	if rank <= s.sparse[idx] {
+		return
+	}
+	s.sparse[idx] = rank
+
+	// Each sparse entry costs several times more than a dense register.
+	if len(s.sparse) > s.numRegisters()/8 {
+		s.dense = make([]uint8, s.numRegisters())
+		for idx, rank := range s.sparse {
+			s.dense[idx] = rank
+		}
+		s.sparse = nil
+	}
+}
+
+func (s *Sketch) numRegisters() int {
+	return 1 << s.precision
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
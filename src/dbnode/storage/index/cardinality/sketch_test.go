@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireEstimateWithin(t *testing.T, expected int, estimate uint64, relErr float64) {
+	diff := math.Abs(float64(estimate) - float64(expected))
+	require.True(t, diff <= relErr*float64(expected),
+		fmt.Sprintf("expected %d within %.2f, estimated %d", expected, relErr, estimate))
+}
+
+func TestSketchEstimateEmpty(t *testing.T) {
+	require.Equal(t, uint64(0), NewSketch(defaultPrecision).Estimate())
+}
+
+func TestSketchEstimate(t *testing.T) {
+	for _, n := range []int{1, 10, 100, 1000, 10000, 100000} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			s := NewSketch(defaultPrecision)
+			for i := 0; i < n; i++ {
+				s.Add([]byte(fmt.Sprintf("value-%d", i)))
+				// Duplicates should not affect the estimate.
+				s.Add([]byte(fmt.Sprintf("value-%d", i)))
+			}
+			requireEstimateWithin(t, n, s.Estimate(), 0.1)
+		})
+	}
+}
+
+func TestSketchSparseToDense(t *testing.T) {
+	s := NewSketch(MinPrecision + 4)
+	for i := 0; s.dense == nil; i++ {
+		require.True(t, i < 1000)
+		s.Add([]byte(fmt.Sprintf("value-%d", i)))
+	}
+	require.Nil(t, s.sparse)
+	require.Len(t, s.dense, 1<<(MinPrecision+4))
+}
+
+func TestSketchMerge(t *testing.T) {
+	var (
+		a = NewSketch(defaultPrecision)
+		b = NewSketch(defaultPrecision)
+	)
+	for i := 0; i < 5000; i++ {
+		a.Add([]byte(fmt.Sprintf("value-%d", i)))
+	}
+	for i := 2500; i < 7500; i++ {
+		b.Add([]byte(fmt.Sprintf("value-%d", i)))
+	}
+
+	merged := a.Clone()
+	merged.Merge(b)
+	requireEstimateWithin(t, 7500, merged.Estimate(), 0.1)
+
+	// Ensure merging into the clone did not modify the original.
+	requireEstimateWithin(t, 5000, a.Estimate(), 0.1)
+
+	// Ensure merging sparse sketches works as well.
+	sparse := NewSketch(defaultPrecision)
+	sparse.Add([]byte("value-10000"))
+	require.NotNil(t, sparse.sparse)
+	merged.Merge(sparse)
+	requireEstimateWithin(t, 7501, merged.Estimate(), 0.1)
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/cespare/xxhash"
+	"github.com/uber-go/tally"
+)
+
+type tracker struct {
+	blockSize        time.Duration
+	retentionPeriod  time.Duration
+	precision        uint8
+	metricNameTag    []byte
+	maxNamesPerBlock int
+	metricsTopN      int
+
+	// NB: Series are spread across shards by the hash of their ID so that
+	// concurrent adds of different series rarely contend on the same lock,
+	// the estimates of each shard are merged when reporting.
+	shards  []*trackerShard
+	names   trackedNames
+	metrics trackerMetrics
+}
+
+// trackedNames are the names tracked per block by any shard so that the max
+// names per block is enforced across all shards rather than by each shard.
+type trackedNames struct {
+	sync.Mutex
+
+	blocks map[xtime.UnixNano]*blockNames
+}
+
+type blockNames struct {
+	tagNames    map[string]struct{}
+	metricNames map[string]struct{}
+}
+
+type trackerShard struct {
+	sync.RWMutex
+
+	blocks map[xtime.UnixNano]*blockSketches
+}
+
+type blockSketches struct {
+	tagNames    map[string]*Sketch
+	metricNames map[string]*Sketch
+}
+
+type trackerMetrics struct {
+	sync.Mutex
+
+	scope          tally.Scope
+	tagNames       tally.Gauge
+	metricNames    tally.Gauge
+	untrackedNames tally.Counter
+
+	reportedTagNames    map[string]tally.Gauge
+	reportedMetricNames map[string]tally.Gauge
+}
+
+func newTrackerMetrics(scope tally.Scope) trackerMetrics {
+	return trackerMetrics{
+		scope:               scope,
+		tagNames:            scope.Gauge("tag-names"),
+		metricNames:         scope.Gauge("metric-names"),
+		untrackedNames:      scope.Counter("untracked-names"),
+		reportedTagNames:    make(map[string]tally.Gauge),
+		reportedMetricNames: make(map[string]tally.Gauge),
+	}
+}
+
+// NewTracker returns a new cardinality tracker.
+func NewTracker(opts Options) (Tracker, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	shards := make([]*trackerShard, opts.Concurrency())
+	for i := range shards {
+		shards[i] = &trackerShard{
+			blocks: make(map[xtime.UnixNano]*blockSketches),
+		}
+	}
+
+	scope := opts.InstrumentOptions().MetricsScope().SubScope("cardinality")
+	return &tracker{
+		blockSize:        opts.BlockSize(),
+		retentionPeriod:  opts.RetentionPeriod(),
+		precision:        opts.Precision(),
+		metricNameTag:    opts.MetricNameTag(),
+		maxNamesPerBlock: opts.MaxNamesPerBlock(),
+		metricsTopN:      opts.MetricsTopN(),
+		shards:           shards,
+		names: trackedNames{
+			blocks: make(map[xtime.UnixNano]*blockNames),
+		},
+		metrics: newTrackerMetrics(scope),
+	}, nil
+}
+
+func (t *tracker) Add(timestamp time.Time, d doc.Document) {
+	var (
+		blockStart = xtime.ToUnixNano(timestamp.Truncate(t.blockSize))
+		idHash     = xxhash.Sum64(d.ID)
+		shard      = t.shards[idHash%uint64(len(t.shards))]
+	)
+
+	shard.Lock()
+	defer shard.Unlock()
+
+	block, ok := shard.blocks[blockStart]
+	if !ok {
+		block = &blockSketches{
+			tagNames:    make(map[string]*Sketch),
+			metricNames: make(map[string]*Sketch),
+		}
+		shard.blocks[blockStart] = block
+	}
+
+	for _, field := range d.Fields {
+		sketch := t.sketchWithLock(block.tagNames, blockStart, false, field.Name)
+		if sketch != nil {
+			sketch.Add(field.Value)
+		}
+		if !bytes.Equal(field.Name, t.metricNameTag) {
+			continue
+		}
+		sketch = t.sketchWithLock(block.metricNames, blockStart, true, field.Value)
+		if sketch != nil {
+			sketch.AddHash(idHash)
+		}
+	}
+}
+
+func (t *tracker) sketchWithLock(
+	sketches map[string]*Sketch,
+	blockStart xtime.UnixNano,
+	metricName bool,
+	name []byte,
+) *Sketch {
+	if sketch, ok := sketches[string(name)]; ok {
+		return sketch
+	}
+	if !t.names.track(blockStart, metricName, name, t.maxNamesPerBlock) {
+		t.metrics.untrackedNames.Inc(1)
+		return nil
+	}
+	sketch := NewSketch(t.precision)
+	sketches[string(name)] = sketch
+	return sketch
+}
+
+// track returns whether the name is tracked for the block, tracking it if
+// fewer than max names are tracked for the block.
+func (n *trackedNames) track(
+	blockStart xtime.UnixNano,
+	metricName bool,
+	name []byte,
+	max int,
+) bool {
+	n.Lock()
+	defer n.Unlock()
+
+	block, ok := n.blocks[blockStart]
+	if !ok {
+		block = &blockNames{
+			tagNames:    make(map[string]struct{}),
+			metricNames: make(map[string]struct{}),
+		}
+		n.blocks[blockStart] = block
+	}
+
+	names := block.tagNames
+	if metricName {
+		names = block.metricNames
+	}
+	if _, ok := names[string(name)]; ok {
+		return true
+	}
+	if len(names) >= max {
+		return false
+	}
+	names[string(name)] = struct{}{}
+	return true
+}
+
+func (n *trackedNames) removeBefore(blockStart xtime.UnixNano) {
+	n.Lock()
+	for start := range n.blocks {
+		if start < blockStart {
+			delete(n.blocks, start)
+		}
+	}
+	n.Unlock()
+}
+
+func (t *tracker) Report(opts ReportOptions) Report {
+	tagNames, metricNames := t.mergedSketches(opts.Start, opts.End)
+	return Report{
+		TagNames:    topEstimates(tagNames, opts.Limit),
+		MetricNames: topEstimates(metricNames, opts.Limit),
+	}
+}
+
+// mergedSketches returns the sketches of every shard for the blocks that
+// overlap a time range merged by name, a zero start or end is unbounded.
+func (t *tracker) mergedSketches(
+	start, end time.Time,
+) (map[string]*Sketch, map[string]*Sketch) {
+	var (
+		tagNames    = make(map[string]*Sketch)
+		metricNames = make(map[string]*Sketch)
+	)
+	for _, shard := range t.shards {
+		shard.RLock()
+		for blockStart, block := range shard.blocks {
+			blockStartTime := blockStart.ToTime()
+			if !end.IsZero() && !blockStartTime.Before(end) {
+				continue
+			}
+			if !start.IsZero() && !blockStartTime.Add(t.blockSize).After(start) {
+				continue
+			}
+			mergeSketches(tagNames, block.tagNames)
+			mergeSketches(metricNames, block.metricNames)
+		}
+		shard.RUnlock()
+	}
+	return tagNames, metricNames
+}
+
+func (t *tracker) Tick(now time.Time) {
+	var (
+		earliestBlockStart = xtime.ToUnixNano(now.Add(-t.retentionPeriod).Truncate(t.blockSize))
+		currentBlockStart  = now.Truncate(t.blockSize)
+	)
+
+	for _, shard := range t.shards {
+		shard.Lock()
+		for blockStart := range shard.blocks {
+			if blockStart < earliestBlockStart {
+				delete(shard.blocks, blockStart)
+			}
+		}
+		shard.Unlock()
+	}
+	t.names.removeBefore(earliestBlockStart)
+
+	tagNames, metricNames := t.mergedSketches(currentBlockStart,
+		currentBlockStart.Add(t.blockSize))
+
+	m := &t.metrics
+	m.Lock()
+	m.tagNames.Update(float64(len(tagNames)))
+	m.metricNames.Update(float64(len(metricNames)))
+	m.updateTopN(m.reportedTagNames, topEstimates(tagNames, t.metricsTopN),
+		"tag_name", "tag-name-cardinality")
+	m.updateTopN(m.reportedMetricNames, topEstimates(metricNames, t.metricsTopN),
+		"metric_name", "metric-name-cardinality")
+	m.Unlock()
+}
+
+func (m *trackerMetrics) updateTopN(
+	reported map[string]tally.Gauge,
+	estimates []Estimate,
+	tagName string,
+	gaugeName string,
+) {
+	current := make(map[string]struct{}, len(estimates))
+	for _, estimate := range estimates {
+		gauge, ok := reported[estimate.Name]
+		if !ok {
+			gauge = m.scope.Tagged(map[string]string{
+				tagName: estimate.Name,
+			}).Gauge(gaugeName)
+			reported[estimate.Name] = gauge
+		}
+		gauge.Update(float64(estimate.Cardinality))
+		current[estimate.Name] = struct{}{}
+	}
+
+	// Zero out the gauges of names that are no longer in the top N
+	// so they do not keep reporting a stale cardinality.
+	for name, gauge := range reported {
+		if _, ok := current[name]; !ok {
+			gauge.Update(0)
+			delete(reported, name)
+		}
+	}
+}
+
+func mergeSketches(dst, src map[string]*Sketch) {
+	for name, sketch := range src {
+		if existing, ok := dst[name]; ok {
+			existing.Merge(sketch)
+			continue
+		}
+		dst[name] = sketch.Clone()
+	}
+}
+
+func topEstimates(sketches map[string]*Sketch, limit int) []Estimate {
+	estimates := make([]Estimate, 0, len(sketches))
+	for name, sketch := range sketches {
+		estimates = append(estimates, Estimate{
+			Name:        name,
+			Cardinality: sketch.Estimate(),
+		})
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		if estimates[i].Cardinality != estimates[j].Cardinality {
+			return estimates[i].Cardinality > estimates[j].Cardinality
+		}
+		return estimates[i].Name < estimates[j].Name
+	})
+	if limit > 0 && len(estimates) > limit {
+		estimates = estimates[:limit]
+	}
+	return estimates
+}
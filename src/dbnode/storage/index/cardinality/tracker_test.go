@@ -0,0 +1,240 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newTestDocument(id string, fields ...string) doc.Document {
+	d := doc.Document{ID: []byte(id)}
+	for i := 0; i < len(fields); i += 2 {
+		d.Fields = append(d.Fields, doc.Field{
+			Name:  []byte(fields[i]),
+			Value: []byte(fields[i+1]),
+		})
+	}
+	return d
+}
+
+func requireEstimates(t *testing.T, expected, actual []Estimate) {
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		require.Equal(t, expected[i].Name, actual[i].Name)
+		requireEstimateWithin(t, int(expected[i].Cardinality), actual[i].Cardinality, 0.1)
+	}
+}
+
+func newTestTracker(t *testing.T, opts Options) Tracker {
+	tracker, err := NewTracker(opts)
+	require.NoError(t, err)
+	return tracker
+}
+
+func TestNewTrackerInvalidOptions(t *testing.T) {
+	_, err := NewTracker(NewOptions().SetPrecision(MaxPrecision + 1))
+	require.Error(t, err)
+	_, err = NewTracker(NewOptions().SetBlockSize(0))
+	require.Error(t, err)
+	_, err = NewTracker(NewOptions().SetMaxNamesPerBlock(0))
+	require.Error(t, err)
+	_, err = NewTracker(NewOptions().SetConcurrency(0))
+	require.Error(t, err)
+}
+
+func TestTrackerReport(t *testing.T) {
+	var (
+		tracker = newTestTracker(t, NewOptions())
+		start   = time.Now().Truncate(defaultBlockSize)
+	)
+	for i := 0; i < 100; i++ {
+		tracker.Add(start, newTestDocument(fmt.Sprintf("requests-%d", i),
+			"__name__", "requests", "request_id", fmt.Sprintf("%d", i), "env", "prod"))
+	}
+	for i := 0; i < 10; i++ {
+		tracker.Add(start, newTestDocument(fmt.Sprintf("latency-%d", i),
+			"__name__", "latency", "host", fmt.Sprintf("%d", i), "env", "dev"))
+	}
+
+	report := tracker.Report(ReportOptions{Limit: 3})
+	requireEstimates(t, []Estimate{
+		{Name: "request_id", Cardinality: 100},
+		{Name: "host", Cardinality: 10},
+		{Name: "__name__", Cardinality: 2},
+	}, report.TagNames)
+	requireEstimates(t, []Estimate{
+		{Name: "requests", Cardinality: 100},
+		{Name: "latency", Cardinality: 10},
+	}, report.MetricNames)
+}
+
+func TestTrackerReportMergesShards(t *testing.T) {
+	var (
+		tracker = newTestTracker(t, NewOptions().SetConcurrency(8))
+		start   = time.Now().Truncate(defaultBlockSize)
+	)
+	for i := 0; i < 100; i++ {
+		tracker.Add(start, newTestDocument(fmt.Sprintf("requests-%d", i),
+			"__name__", "requests", "env", "prod"))
+	}
+
+	report := tracker.Report(ReportOptions{})
+	requireEstimates(t, []Estimate{
+		{Name: "__name__", Cardinality: 1},
+		{Name: "env", Cardinality: 1},
+	}, report.TagNames)
+	requireEstimates(t, []Estimate{
+		{Name: "requests", Cardinality: 100},
+	}, report.MetricNames)
+}
+
+func TestTrackerReportTimeRange(t *testing.T) {
+	var (
+		tracker = newTestTracker(t, NewOptions())
+		start   = time.Now().Truncate(defaultBlockSize)
+		next    = start.Add(defaultBlockSize)
+	)
+	for i := 0; i < 20; i++ {
+		tracker.Add(start, newTestDocument(fmt.Sprintf("a-%d", i),
+			"__name__", "a", "id", fmt.Sprintf("%d", i)))
+		// Repeat half of the series in the next block.
+		tracker.Add(next, newTestDocument(fmt.Sprintf("a-%d", i+10),
+			"__name__", "a", "id", fmt.Sprintf("%d", i+10)))
+	}
+
+	report := tracker.Report(ReportOptions{Start: next, End: next.Add(time.Minute)})
+	requireEstimates(t, []Estimate{{Name: "a", Cardinality: 20}}, report.MetricNames)
+
+	report = tracker.Report(ReportOptions{End: next})
+	requireEstimates(t, []Estimate{{Name: "a", Cardinality: 20}}, report.MetricNames)
+
+	report = tracker.Report(ReportOptions{Start: start.Add(time.Minute)})
+	requireEstimates(t, []Estimate{{Name: "a", Cardinality: 30}}, report.MetricNames)
+
+	report = tracker.Report(ReportOptions{Start: next.Add(defaultBlockSize)})
+	require.Empty(t, report.TagNames)
+	require.Empty(t, report.MetricNames)
+}
+
+func TestTrackerMaxNamesPerBlock(t *testing.T) {
+	var (
+		scope = tally.NewTestScope("", nil)
+		opts  = NewOptions().
+			SetMaxNamesPerBlock(2).
+			SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(scope))
+		tracker = newTestTracker(t, opts)
+		now     = time.Now()
+	)
+	tracker.Add(now, newTestDocument("a", "__name__", "a", "x", "1", "y", "1"))
+
+	report := tracker.Report(ReportOptions{})
+	requireEstimates(t, []Estimate{
+		{Name: "__name__", Cardinality: 1},
+		{Name: "x", Cardinality: 1},
+	}, report.TagNames)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["cardinality.untracked-names+"].Value())
+}
+
+func TestTrackerMaxNamesPerBlockAcrossShards(t *testing.T) {
+	var (
+		opts = NewOptions().
+			SetMaxNamesPerBlock(2).
+			SetConcurrency(8)
+		tracker = newTestTracker(t, opts)
+		now     = time.Now()
+	)
+	for i := 0; i < 16; i++ {
+		name := fmt.Sprintf("m%d", i)
+		tracker.Add(now, newTestDocument(name, "__name__", name))
+	}
+
+	// The max applies across all shards rather than to each shard.
+	report := tracker.Report(ReportOptions{})
+	require.Equal(t, 2, len(report.MetricNames))
+	require.Equal(t, 1, len(report.TagNames))
+}
+
+func TestTrackerTick(t *testing.T) {
+	var (
+		scope = tally.NewTestScope("", nil)
+		opts  = NewOptions().
+			SetMetricsTopN(1).
+			SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(scope))
+		tracker = newTestTracker(t, opts)
+		now     = time.Now().Truncate(defaultBlockSize)
+		expired = now.Add(-defaultRetentionPeriod - defaultBlockSize)
+	)
+	tracker.Add(expired, newTestDocument("a", "__name__", "a", "x", "1"))
+	tracker.Add(now, newTestDocument("b", "__name__", "b", "y", "1"))
+	tracker.Add(now, newTestDocument("c", "__name__", "b", "y", "2"))
+
+	tracker.Tick(now)
+
+	report := tracker.Report(ReportOptions{})
+	requireEstimates(t, []Estimate{{Name: "b", Cardinality: 2}}, report.MetricNames)
+
+	gauges := scope.Snapshot().Gauges()
+	require.Equal(t, 2.0, gauges["cardinality.tag-names+"].Value())
+	require.Equal(t, 1.0, gauges["cardinality.metric-names+"].Value())
+	require.Equal(t, 2.0, gauges["cardinality.tag-name-cardinality+tag_name=y"].Value())
+	require.Equal(t, 2.0, gauges["cardinality.metric-name-cardinality+metric_name=b"].Value())
+
+	// Once a name drops out of the top N its gauge is zeroed.
+	for i := 0; i < 3; i++ {
+		tracker.Add(now, newTestDocument(fmt.Sprintf("z-%d", i), "__name__", "z", "z", fmt.Sprintf("%d", i)))
+	}
+	tracker.Tick(now)
+
+	gauges = scope.Snapshot().Gauges()
+	require.Equal(t, 0.0, gauges["cardinality.tag-name-cardinality+tag_name=y"].Value())
+	require.Equal(t, 3.0, gauges["cardinality.tag-name-cardinality+tag_name=z"].Value())
+	require.Equal(t, 3.0, gauges["cardinality.metric-name-cardinality+metric_name=z"].Value())
+}
+
+func BenchmarkTrackerAdd(b *testing.B) {
+	tracker, err := NewTracker(NewOptions())
+	require.NoError(b, err)
+
+	docs := make([]doc.Document, 1024)
+	for i := range docs {
+		docs[i] = newTestDocument(fmt.Sprintf("requests-%d", i),
+			"__name__", "requests", "host", fmt.Sprintf("%d", i%64), "env", "prod")
+	}
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			tracker.Add(now, docs[i%len(docs)])
+		}
+	})
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cardinality
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/instrument"
+)
+
+// Tracker tracks estimates of the number of distinct series per metric name
+// and distinct values per tag name for each block of time.
+type Tracker interface {
+	// Add accounts for a series indexed at the given time.
+	Add(timestamp time.Time, d doc.Document)
+
+	// Report returns the tag names and metric names with the highest
+	// estimated cardinality over a time range.
+	Report(opts ReportOptions) Report
+
+	// Tick expires estimates past the retention period and emits metrics
+	// for the highest cardinality names of the current block.
+	Tick(now time.Time)
+}
+
+// ReportOptions are the options for a cardinality report.
+type ReportOptions struct {
+	// Start is the inclusive start of the time range to report on, if
+	// zero the range is not bounded by a start.
+	Start time.Time
+
+	// End is the exclusive end of the time range to report on, if zero
+	// the range is not bounded by an end.
+	End time.Time
+
+	// Limit is the max number of tag names and metric names to report.
+	Limit int
+}
+
+// Report is a report of the names with the highest estimated cardinality.
+type Report struct {
+	TagNames    []Estimate `json:"tagNames"`
+	MetricNames []Estimate `json:"metricNames"`
+}
+
+// Estimate is the estimated cardinality of a name.
+type Estimate struct {
+	Name        string `json:"name"`
+	Cardinality uint64 `json:"cardinality"`
+}
+
+// Options is a set of cardinality tracker options.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetPrecision sets the precision of the sketches used to estimate
+	// cardinality, each sketch uses up to 2^precision bytes and has a
+	// standard error of 1.04/sqrt(2^precision).
+	SetPrecision(value uint8) Options
+
+	// Precision returns the precision of the sketches used to estimate
+	// cardinality.
+	Precision() uint8
+
+	// SetMetricNameTag sets the name of the tag holding the metric name.
+	SetMetricNameTag(value []byte) Options
+
+	// MetricNameTag returns the name of the tag holding the metric name.
+	MetricNameTag() []byte
+
+	// SetBlockSize sets the size of the blocks of time estimates are kept for.
+	SetBlockSize(value time.Duration) Options
+
+	// BlockSize returns the size of the blocks of time estimates are kept for.
+	BlockSize() time.Duration
+
+	// SetRetentionPeriod sets the period estimates are retained for.
+	SetRetentionPeriod(value time.Duration) Options
+
+	// RetentionPeriod returns the period estimates are retained for.
+	RetentionPeriod() time.Duration
+
+	// SetMaxNamesPerBlock sets the max number of tag names and of metric
+	// names tracked per block across all concurrent shards of the tracker,
+	// names seen past the max are not tracked.
+	SetMaxNamesPerBlock(value int) Options
+
+	// MaxNamesPerBlock returns the max number of tag names and of metric
+	// names tracked per block.
+	MaxNamesPerBlock() int
+
+	// SetMetricsTopN sets the number of highest cardinality names to emit
+	// metrics for.
+	SetMetricsTopN(value int) Options
+
+	// MetricsTopN returns the number of highest cardinality names to emit
+	// metrics for.
+	MetricsTopN() int
+
+	// SetConcurrency sets the number of shards series are spread across by
+	// the hash of their ID, each with its own lock, to reduce contention
+	// between concurrent adds.
+	SetConcurrency(value int) Options
+
+	// Concurrency returns the number of shards series are spread across.
+	Concurrency() int
+}
@@ -22,6 +22,7 @@ package convert
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -42,6 +43,9 @@ var (
 
 	errInvalidResultMissingID = errors.New(
 		"corrupt data, unable to extract id")
+
+	errInvalidEncodedFields = errors.New(
+		"invalid encoded document fields")
 )
 
 // ValidateMetric will validate a metric for use in the m3ninx subsytem
@@ -362,3 +366,75 @@ func (t *tagIter) Duplicate() ident.TagIterator {
 	}
 	return &dupe
 }
+
+// EncodeFields appends the encoded document fields to the given bytes, the
+// encoded fields are written as the annotation of writes to index only
+// namespaces to store document fields beyond the tags of the series.
+func EncodeFields(b []byte, fields doc.Fields) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(fields)))
+	b = append(b, buf[:n]...)
+	for _, f := range fields {
+		n = binary.PutUvarint(buf[:], uint64(len(f.Name)))
+		b = append(b, buf[:n]...)
+		b = append(b, f.Name...)
+		n = binary.PutUvarint(buf[:], uint64(len(f.Value)))
+		b = append(b, buf[:n]...)
+		b = append(b, f.Value...)
+	}
+	return b
+}
+
+// DecodeFields decodes document fields encoded by EncodeFields, the returned
+// fields are copied and do not reference the given bytes.
+func DecodeFields(b []byte) (doc.Fields, error) {
+	numFields, b, err := decodeUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	if numFields > uint64(len(b)) {
+		// Each field takes at least two bytes to encode.
+		return nil, errInvalidEncodedFields
+	}
+
+	fields := make(doc.Fields, 0, numFields)
+	for i := uint64(0); i < numFields; i++ {
+		var name, value []byte
+		if name, b, err = decodeBytes(b); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(ReservedFieldNameID, name) {
+			return nil, ErrUsingReservedFieldName
+		}
+		if value, b, err = decodeBytes(b); err != nil {
+			return nil, err
+		}
+		fields = append(fields, doc.Field{
+			Name:  append([]byte(nil), name...),
+			Value: append([]byte(nil), value...),
+		})
+	}
+	if len(b) != 0 {
+		return nil, errInvalidEncodedFields
+	}
+	return fields, nil
+}
+
+func decodeUvarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, errInvalidEncodedFields
+	}
+	return v, b[n:], nil
+}
+
+func decodeBytes(b []byte) ([]byte, []byte, error) {
+	l, b, err := decodeUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if l > uint64(len(b)) {
+		return nil, nil, errInvalidEncodedFields
+	}
+	return b[:l], b[l:], nil
+}
@@ -167,3 +167,43 @@ func TestToMetricInvalidTag(t *testing.T) {
 }
 
 // TODO(prateek): add a test to ensure we're interacting with the Pools as expected
+
+func TestEncodeDecodeFields(t *testing.T) {
+	fields := doc.Fields{
+		{Name: []byte("owner"), Value: []byte("infra")},
+		{Name: []byte("empty"), Value: nil},
+	}
+	b := convert.EncodeFields(nil, fields)
+
+	decoded, err := convert.DecodeFields(b)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(decoded))
+	assert.Equal(t, "owner", string(decoded[0].Name))
+	assert.Equal(t, "infra", string(decoded[0].Value))
+	assert.Equal(t, "empty", string(decoded[1].Name))
+	assert.Equal(t, 0, len(decoded[1].Value))
+
+	// Decoded fields must not reference the encoded bytes.
+	for i := range b {
+		b[i] = 0
+	}
+	assert.Equal(t, "owner", string(decoded[0].Name))
+}
+
+func TestDecodeFieldsInvalid(t *testing.T) {
+	b := convert.EncodeFields(nil, doc.Fields{
+		{Name: []byte("owner"), Value: []byte("infra")},
+	})
+	for i := 0; i < len(b); i++ {
+		_, err := convert.DecodeFields(b[:i])
+		require.Error(t, err)
+	}
+	_, err := convert.DecodeFields(append(b, 0))
+	require.Error(t, err)
+
+	b = convert.EncodeFields(nil, doc.Fields{
+		{Name: convert.ReservedFieldNameID, Value: []byte("foo")},
+	})
+	_, err = convert.DecodeFields(b)
+	require.Equal(t, convert.ErrUsingReservedFieldName, err)
+}
@@ -22,9 +22,13 @@ package index
 
 import (
 	"errors"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -42,6 +46,10 @@ const (
 	documentArrayPoolSize        = 256
 	documentArrayPoolCapacity    = 256
 	documentArrayPoolMaxCapacity = 256 // Do not allow grows, since we know the size
+
+	// defaultBackgroundCompactionInterval is the default interval at which the
+	// open blocks of a namespace index compact their in-memory segments.
+	defaultBackgroundCompactionInterval = 10 * time.Second
 )
 
 var (
@@ -49,17 +57,22 @@ var (
 	errOptionsBytesPoolUnspecified      = errors.New("checkedbytes pool is unset")
 	errOptionsResultsPoolUnspecified    = errors.New("results pool is unset")
 	errIDGenerationDisabled             = errors.New("id generation is disabled")
+	errBackgroundCompactionIntervalNeg  = errors.New("background compaction interval is negative")
 )
 
 type opts struct {
-	insertMode     InsertMode
-	clockOpts      clock.Options
-	instrumentOpts instrument.Options
-	memOpts        mem.Options
-	idPool         ident.Pool
-	bytesPool      pool.CheckedBytesPool
-	resultsPool    ResultsPool
-	docArrayPool   doc.DocumentArrayPool
+	insertMode      InsertMode
+	clockOpts       clock.Options
+	instrumentOpts  instrument.Options
+	memOpts         mem.Options
+	idPool          ident.Pool
+	bytesPool       pool.CheckedBytesPool
+	resultsPool     ResultsPool
+	docArrayPool    doc.DocumentArrayPool
+	cardinalityOpts cardinality.Options
+	fstOpts         fst.Options
+	plannerOpts     compaction.PlannerOptions
+	compactInterval time.Duration
 }
 
 var undefinedUUIDFn = func() ([]byte, error) { return nil, errIDGenerationDisabled }
@@ -84,14 +97,18 @@ func NewOptions() Options {
 	docArrayPool.Init()
 
 	opts := &opts{
-		insertMode:     defaultIndexInsertMode,
-		clockOpts:      clock.NewOptions(),
-		instrumentOpts: instrument.NewOptions(),
-		memOpts:        mem.NewOptions().SetNewUUIDFn(undefinedUUIDFn),
-		bytesPool:      bytesPool,
-		idPool:         idPool,
-		resultsPool:    resultsPool,
-		docArrayPool:   docArrayPool,
+		insertMode:      defaultIndexInsertMode,
+		clockOpts:       clock.NewOptions(),
+		instrumentOpts:  instrument.NewOptions(),
+		memOpts:         mem.NewOptions().SetNewUUIDFn(undefinedUUIDFn),
+		bytesPool:       bytesPool,
+		idPool:          idPool,
+		resultsPool:     resultsPool,
+		docArrayPool:    docArrayPool,
+		cardinalityOpts: cardinality.NewOptions(),
+		fstOpts:         fst.NewOptions(),
+		plannerOpts:     compaction.DefaultOptions,
+		compactInterval: defaultBackgroundCompactionInterval,
 	}
 	resultsPool.Init(func() Results { return NewResults(opts) })
 	return opts
@@ -107,7 +124,10 @@ func (o *opts) Validate() error {
 	if o.resultsPool == nil {
 		return errOptionsResultsPoolUnspecified
 	}
-	return nil
+	if o.compactInterval < 0 {
+		return errBackgroundCompactionIntervalNeg
+	}
+	return o.plannerOpts.Validate()
 }
 
 func (o *opts) SetInsertMode(value InsertMode) Options {
@@ -133,8 +153,10 @@ func (o *opts) ClockOptions() clock.Options {
 func (o *opts) SetInstrumentOptions(value instrument.Options) Options {
 	opts := *o
 	memOpts := opts.MemSegmentOptions().SetInstrumentOptions(value)
+	fstOpts := opts.FSTSegmentOptions().SetInstrumentOptions(value)
 	opts.instrumentOpts = value
 	opts.memOpts = memOpts
+	opts.fstOpts = fstOpts
 	return &opts
 }
 
@@ -191,3 +213,43 @@ func (o *opts) SetDocumentArrayPool(value doc.DocumentArrayPool) Options {
 func (o *opts) DocumentArrayPool() doc.DocumentArrayPool {
 	return o.docArrayPool
 }
+
+func (o *opts) SetCardinalityOptions(value cardinality.Options) Options {
+	opts := *o
+	opts.cardinalityOpts = value
+	return &opts
+}
+
+func (o *opts) CardinalityOptions() cardinality.Options {
+	return o.cardinalityOpts
+}
+
+func (o *opts) SetFSTSegmentOptions(value fst.Options) Options {
+	opts := *o
+	opts.fstOpts = value
+	return &opts
+}
+
+func (o *opts) FSTSegmentOptions() fst.Options {
+	return o.fstOpts
+}
+
+func (o *opts) SetCompactionPlannerOptions(value compaction.PlannerOptions) Options {
+	opts := *o
+	opts.plannerOpts = value
+	return &opts
+}
+
+func (o *opts) CompactionPlannerOptions() compaction.PlannerOptions {
+	return o.plannerOpts
+}
+
+func (o *opts) SetBackgroundCompactionInterval(value time.Duration) Options {
+	opts := *o
+	opts.compactInterval = value
+	return &opts
+}
+
+func (o *opts) BackgroundCompactionInterval() time.Duration {
+	return o.compactInterval
+}
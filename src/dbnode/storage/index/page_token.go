@@ -0,0 +1,113 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/pagetoken"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// PageToken is an opaque token used to resume a paginated query, an empty
+// page token starts the query from the beginning.
+type PageToken []byte
+
+// QueryCursor is the position of a document returned by a paginated query,
+// it identifies the block and the ID of the last document returned from it.
+type QueryCursor struct {
+	BlockStart time.Time
+	DocID      []byte
+}
+
+// IsZero returns whether the cursor is positioned at the start of the index.
+func (c QueryCursor) IsZero() bool {
+	return c.BlockStart.IsZero()
+}
+
+// NewPageToken returns a page token which resumes a query after the document
+// at the cursor.
+func NewPageToken(cursor QueryCursor) (PageToken, error) {
+	if cursor.IsZero() {
+		return PageToken{}, nil
+	}
+	return proto.Marshal(&pagetoken.IndexQueryPageToken{
+		BlockStartUnixNanos: int64(xtime.ToUnixNano(cursor.BlockStart)),
+		DocID:               cursor.DocID,
+	})
+}
+
+// Cursor returns the cursor the page token resumes a query from.
+func (t PageToken) Cursor() (QueryCursor, error) {
+	if len(t) == 0 {
+		return QueryCursor{}, nil
+	}
+
+	var token pagetoken.IndexQueryPageToken
+	if err := proto.Unmarshal(t, &token); err != nil {
+		return QueryCursor{}, err
+	}
+
+	return QueryCursor{
+		BlockStart: xtime.UnixNano(token.BlockStartUnixNanos).ToTime(),
+		DocID:      token.DocID,
+	}, nil
+}
+
+// docsByIDHeap is a max heap of documents ordered by ID, it holds the documents
+// with the smallest IDs seen so far when filling a page.
+type docsByIDHeap []doc.Document
+
+func (h docsByIDHeap) Len() int           { return len(h) }
+func (h docsByIDHeap) Less(i, j int) bool { return bytes.Compare(h[i].ID, h[j].ID) > 0 }
+func (h docsByIDHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *docsByIDHeap) Push(x interface{}) {
+	*h = append(*h, x.(doc.Document))
+}
+
+func (h *docsByIDHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// cloneDocument returns a copy of the document that remains valid once the
+// iterator that returned it has moved on.
+func cloneDocument(d doc.Document) doc.Document {
+	fields := make([]doc.Field, 0, len(d.Fields))
+	for _, f := range d.Fields {
+		fields = append(fields, doc.Field{
+			Name:  append([]byte(nil), f.Name...),
+			Value: append([]byte(nil), f.Value...),
+		})
+	}
+	return doc.Document{
+		ID:     append([]byte(nil), d.ID...),
+		Fields: fields,
+	}
+}
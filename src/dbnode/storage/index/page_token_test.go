@@ -0,0 +1,59 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	cursor := QueryCursor{
+		BlockStart: time.Unix(0, 1540000000000000000),
+		DocID:      []byte("foo"),
+	}
+
+	token, err := NewPageToken(cursor)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	decoded, err := token.Cursor()
+	require.NoError(t, err)
+	require.True(t, cursor.BlockStart.Equal(decoded.BlockStart))
+	require.Equal(t, cursor.DocID, decoded.DocID)
+}
+
+func TestPageTokenEmpty(t *testing.T) {
+	token, err := NewPageToken(QueryCursor{})
+	require.NoError(t, err)
+	require.Empty(t, token)
+
+	cursor, err := PageToken(nil).Cursor()
+	require.NoError(t, err)
+	require.True(t, cursor.IsZero())
+}
+
+func TestPageTokenInvalid(t *testing.T) {
+	_, err := PageToken([]byte{0xff, 0xff}).Cursor()
+	require.Error(t, err)
+}
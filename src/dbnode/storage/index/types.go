@@ -27,8 +27,12 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
@@ -62,6 +66,15 @@ type QueryOptions struct {
 	StartInclusive time.Time
 	EndExclusive   time.Time
 	Limit          int
+
+	// Paginate returns results a page at a time, with the page token of each
+	// page used to resume the query after the last result of the prior page.
+	Paginate  bool
+	PageToken PageToken
+
+	// FilterID restricts the results to the series IDs it returns true for,
+	// if set.
+	FilterID func(id []byte) bool
 }
 
 // LimitExceeded returns whether a given size exceeds the limit
@@ -74,6 +87,81 @@ func (o QueryOptions) LimitExceeded(size int) bool {
 type QueryResults struct {
 	Results    Results
 	Exhaustive bool
+
+	// NextPageToken is set for paginated queries which are not exhaustive and
+	// resumes the query after the last result returned.
+	NextPageToken PageToken
+}
+
+// AggregationType specifies the values returned by an aggregate query.
+type AggregationType uint
+
+const (
+	// AggregateTagNamesAndValues aggregates the distinct tag names and values.
+	AggregateTagNamesAndValues AggregationType = iota
+	// AggregateTagNames aggregates the distinct tag names only.
+	AggregateTagNames
+)
+
+// AggregateQueryOptions enables users to specify constraints on aggregate
+// query execution, the limit applies to the number of distinct tag names, or
+// tag name and value pairs, returned.
+type AggregateQueryOptions struct {
+	QueryOptions
+
+	// FieldFilter restricts the aggregation to the given tag names, all tag
+	// names are aggregated if it is empty.
+	FieldFilter [][]byte
+
+	// Type is the type of aggregation to perform.
+	Type AggregationType
+}
+
+// AggregateQueryResults is the collection of results for an aggregate query.
+type AggregateQueryResults struct {
+	Results    AggregateResults
+	Exhaustive bool
+}
+
+// AggregateResults is a collection of distinct tag names, and optionally tag
+// values, along with the number of documents each was seen in. A series
+// indexed in several blocks is only counted once.
+type AggregateResults interface {
+	// Size returns the number of distinct tag names, or tag name and value
+	// pairs when aggregating tag values.
+	Size() int
+
+	// AddDocumentID records the document as aggregated, it returns false if
+	// the document was already aggregated and its fields must not be added.
+	AddDocumentID(id []byte) bool
+
+	// AddField adds the count of documents containing the tag name, it returns
+	// false if the tag name is new and the results are already at their limit.
+	AddField(name []byte, count int64) bool
+
+	// AddFieldValue adds the count of documents containing the tag name and
+	// value, it returns false if the pair is new and the results are already at
+	// their limit.
+	AddFieldValue(name, value []byte, count int64) bool
+
+	// Fields returns the aggregated tag names, and their values, in order.
+	Fields() []AggregateField
+
+	// Reset resets the results.
+	Reset()
+}
+
+// AggregateField is an aggregated tag name.
+type AggregateField struct {
+	Name   []byte
+	Count  int64
+	Values []AggregateValue
+}
+
+// AggregateValue is an aggregated tag value.
+type AggregateValue struct {
+	Value []byte
+	Count int64
 }
 
 // Results is a collection of results for a query.
@@ -161,12 +249,52 @@ type Block interface {
 		results Results,
 	) (exhaustive bool, err error)
 
+	// QueryPage resolves the given query into known IDs in ascending order,
+	// resuming after the ID of the document at the cursor if the cursor is
+	// within the block, and returns the cursor of the last document added to
+	// the results.
+	QueryPage(
+		query Query,
+		opts QueryOptions,
+		cursor QueryCursor,
+		results Results,
+	) (next QueryCursor, exhaustive bool, err error)
+
+	// Aggregate resolves the given query into the distinct tag names, and
+	// optionally values, of the documents it matches directly from the
+	// segments' fields and terms without retrieving the documents.
+	Aggregate(
+		query Query,
+		opts AggregateQueryOptions,
+		results AggregateResults,
+	) (exhaustive bool, err error)
+
 	// AddResults adds bootstrap results to the block, if c.
 	AddResults(results result.IndexBlock) error
 
+	// AddSegments adds segments holding documents of series persisted outside
+	// of the block's write path, the segments do not fulfill any shard time
+	// ranges so they are only replaced along with the rest of the block's
+	// segments by results that fulfill every shard time range of the block.
+	AddSegments(segments []segment.Segment) error
+
+	// EarliestWriteTimes returns the earliest time, in unix nanoseconds, each
+	// document was written at in any of the block's segments keyed by the
+	// document ID, the write time is zero if it is not known.
+	EarliestWriteTimes() (map[string]int64, error)
+
+	// MergeSegments merges the documents of all of the block's segments into
+	// the target segment, along with the time they were written at.
+	MergeSegments(target segment.MutableSegment) error
+
 	// Tick does internal house keeping operations.
 	Tick(c context.Cancellable, tickStart time.Time) (BlockTickResult, error)
 
+	// Compact rotates the active segment out if it is compactable and then
+	// compacts the in-memory segments rotated out of it into FST segments
+	// following the plan returned by the compaction planner.
+	Compact() error
+
 	// Seal prevents the block from taking any more writes, but, it still permits
 	// addition of segments via Bootstrap().
 	Seal() error
@@ -210,8 +338,10 @@ type WriteBatchResult struct {
 
 // BlockTickResult returns statistics about tick.
 type BlockTickResult struct {
-	NumSegments int64
-	NumDocs     int64
+	NumSegments        int64
+	NumMutableSegments int64
+	NumFSTSegments     int64
+	NumDocs            int64
 }
 
 // WriteBatch is a batch type that allows for building of a slice of documents
@@ -222,8 +352,9 @@ type WriteBatch struct {
 	opts   WriteBatchOptions
 	sortBy writeBatchSortBy
 
-	entries []WriteBatchEntry
-	docs    []doc.Document
+	entries    []WriteBatchEntry
+	docs       []doc.Document
+	writeTimes []int64
 }
 
 type writeBatchSortBy uint
@@ -376,6 +507,16 @@ func (b *WriteBatch) PendingEntries() []WriteBatchEntry {
 	return b.entries[:b.numPending()]
 }
 
+// PendingWriteTimes returns the write times, in unix nanoseconds, of the entries
+// in this batch that are unmarked in the same order as PendingDocs.
+func (b *WriteBatch) PendingWriteTimes() []int64 {
+	b.writeTimes = b.writeTimes[:0]
+	for _, entry := range b.PendingEntries() {
+		b.writeTimes = append(b.writeTimes, entry.Timestamp.UnixNano())
+	}
+	return b.writeTimes
+}
+
 // NumErrs returns the number of errors encountered by the batch.
 func (b *WriteBatch) NumErrs() int {
 	errs := 0
@@ -400,6 +541,7 @@ func (b *WriteBatch) Reset() {
 		b.docs[i] = docZeroed
 	}
 	b.docs = b.docs[:0]
+	b.writeTimes = b.writeTimes[:0]
 }
 
 // SortByUnmarkedAndIndexBlockStart sorts the batch by unmarked first and then
@@ -574,4 +716,33 @@ type Options interface {
 
 	// DocumentArrayPool returns the document array pool.
 	DocumentArrayPool() doc.DocumentArrayPool
+
+	// SetCardinalityOptions sets the cardinality tracking options, the block
+	// size and retention period are set from the namespace of the index.
+	SetCardinalityOptions(value cardinality.Options) Options
+
+	// CardinalityOptions returns the cardinality tracking options.
+	CardinalityOptions() cardinality.Options
+
+	// SetFSTSegmentOptions sets the fst segment options.
+	SetFSTSegmentOptions(value fst.Options) Options
+
+	// FSTSegmentOptions returns the fst segment options.
+	FSTSegmentOptions() fst.Options
+
+	// SetCompactionPlannerOptions sets the compaction planner options used to
+	// pick the in-memory segments of a block to compact together.
+	SetCompactionPlannerOptions(value compaction.PlannerOptions) Options
+
+	// CompactionPlannerOptions returns the compaction planner options.
+	CompactionPlannerOptions() compaction.PlannerOptions
+
+	// SetBackgroundCompactionInterval sets the interval at which the open
+	// blocks of a namespace index are compacted in the background by a single
+	// worker, zero disables background compaction.
+	SetBackgroundCompactionInterval(value time.Duration) Options
+
+	// BackgroundCompactionInterval returns the interval at which the open
+	// blocks of a namespace index are compacted in the background.
+	BackgroundCompactionInterval() time.Duration
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
@@ -198,6 +199,51 @@ func TestNamespaceIndexWrite(t *testing.T) {
 	require.NoError(t, idx.WriteBatch(batch))
 }
 
+func TestNamespaceIndexWriteTracksCardinality(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+	now := time.Now().Truncate(blockSize).Add(2 * time.Minute)
+	nowFn := func() time.Time { return now }
+	opts := testDatabaseOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+
+	mockBlock := index.NewMockBlock(ctrl)
+	mockBlock.EXPECT().StartTime().Return(now.Truncate(blockSize)).AnyTimes()
+	mockBlock.EXPECT().
+		WriteBatch(gomock.Any()).
+		Return(index.WriteBatchResult{}, nil)
+	newBlockFn := func(ts time.Time, md namespace.Metadata, io index.Options) (index.Block, error) {
+		return mockBlock, nil
+	}
+	md := testNamespaceMetadata(blockSize, 4*time.Hour)
+	idx, err := newNamespaceIndexWithNewBlockFn(md, newBlockFn, opts)
+	require.NoError(t, err)
+
+	batch := index.NewWriteBatch(index.WriteBatchOptions{
+		IndexBlockSize: blockSize,
+	})
+	for i := 0; i < 3; i++ {
+		id := ident.StringID(fmt.Sprintf("foo%d", i))
+		tags := ident.NewTags(
+			ident.StringTag("__name__", "foo"),
+			ident.StringTag("request_id", fmt.Sprintf("%d", i)))
+		batch.Append(testWriteBatchEntry(id, tags, now, index.NewMockOnIndexSeries(ctrl)))
+	}
+	require.NoError(t, idx.WriteBatch(batch))
+
+	report, err := idx.Cardinality(cardinality.ReportOptions{Start: now, End: now.Add(time.Minute)})
+	require.NoError(t, err)
+	require.Equal(t, []cardinality.Estimate{
+		{Name: "request_id", Cardinality: 3},
+		{Name: "__name__", Cardinality: 1},
+	}, report.TagNames)
+	require.Equal(t, []cardinality.Estimate{
+		{Name: "foo", Cardinality: 3},
+	}, report.MetricNames)
+}
+
 func TestNamespaceIndexWriteCreatesBlock(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{t})
 	defer ctrl.Finish()
@@ -30,7 +30,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
@@ -147,6 +149,87 @@ func TestNamespaceIndexFlushSuccess(t *testing.T) {
 	require.True(t, persistClosed)
 }
 
+func TestNamespaceIndexFlushIndexOnly(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	test := newTestIndexWithOptions(t, ctrl, namespace.NewOptions().
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true)).
+		SetIndexOnly(true))
+
+	now := time.Now().Truncate(test.indexBlockSize)
+	idx := test.index.(*nsIndex)
+
+	mockBlock := index.NewMockBlock(ctrl)
+	blockTime := now.Add(-2 * test.indexBlockSize)
+	mockBlock.EXPECT().StartTime().Return(blockTime).AnyTimes()
+	mockBlock.EXPECT().EndTime().Return(blockTime.Add(test.indexBlockSize)).AnyTimes()
+	idx.state.blocksByTime[xtime.ToUnixNano(blockTime)] = mockBlock
+
+	mockBlock.EXPECT().IsSealed().Return(true)
+	mockBlock.EXPECT().NeedsMutableSegmentsEvicted().Return(true)
+
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	mockShard.EXPECT().FlushState(blockTime).Return(fileOpState{Status: fileOpSuccess})
+	mockShard.EXPECT().FlushState(blockTime.Add(test.blockSize)).Return(fileOpState{Status: fileOpSuccess})
+	shards := []databaseShard{mockShard}
+
+	// The documents are merged from the block's segments rather than read
+	// from the shard's series, retaining their fields and write times.
+	writeTime := blockTime.Add(time.Minute).UnixNano()
+	fooDoc := doc.Document{
+		ID: []byte("foo"),
+		Fields: doc.Fields{
+			{Name: []byte("host"), Value: []byte("a")},
+			{Name: []byte("owner"), Value: []byte("infra")},
+		},
+	}
+	mockBlock.EXPECT().MergeSegments(gomock.Any()).DoAndReturn(
+		func(target segment.MutableSegment) error {
+			b := m3ninxindex.NewBatch([]doc.Document{fooDoc})
+			b.WriteTimes = []int64{writeTime}
+			return target.InsertBatch(b)
+		})
+
+	mockFlush := persist.NewMockIndexFlush(ctrl)
+
+	persistCalled := false
+	preparedPersist := persist.PreparedIndexPersist{
+		Close: func() ([]segment.Segment, error) {
+			return nil, nil
+		},
+		Persist: func(seg segment.MutableSegment) error {
+			persistCalled = true
+			exists, err := seg.ContainsID([]byte("foo"))
+			require.NoError(t, err)
+			require.True(t, exists)
+
+			writeTimes, ok := seg.(segment.WriteTimes)
+			require.True(t, ok)
+			require.Equal(t, writeTime, writeTimes.EarliestWriteTime())
+
+			reader, err := seg.Reader()
+			require.NoError(t, err)
+			defer reader.Close()
+			docs, err := reader.AllDocs()
+			require.NoError(t, err)
+			require.True(t, docs.Next())
+			require.Equal(t, fooDoc, docs.Current())
+			require.False(t, docs.Next())
+			require.NoError(t, docs.Close())
+			return nil
+		},
+	}
+	mockFlush.EXPECT().PrepareIndex(gomock.Any()).Return(preparedPersist, nil)
+
+	mockBlock.EXPECT().AddResults(gomock.Any()).Return(nil)
+	mockBlock.EXPECT().EvictMutableSegments().Return(index.EvictMutableSegmentResults{}, nil)
+
+	require.NoError(t, idx.Flush(mockFlush, shards))
+	require.True(t, persistCalled)
+}
+
 func TestNamespaceIndexFlushShardStateNotSuccess(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{t})
 	defer ctrl.Finish()
@@ -289,6 +372,14 @@ type testIndex struct {
 }
 
 func newTestIndex(t *testing.T, ctrl *gomock.Controller) testIndex {
+	return newTestIndexWithOptions(t, ctrl, namespace.NewOptions())
+}
+
+func newTestIndexWithOptions(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	nopts namespace.Options,
+) testIndex {
 	blockSize := time.Hour
 	indexBlockSize := 2 * time.Hour
 	retentionPeriod := 24 * time.Hour
@@ -296,9 +387,9 @@ func newTestIndex(t *testing.T, ctrl *gomock.Controller) testIndex {
 		SetBlockSize(blockSize).
 		SetRetentionPeriod(retentionPeriod).
 		SetBufferPast(blockSize / 2)
-	nopts := namespace.NewOptions().
+	nopts = nopts.
 		SetRetentionOptions(ropts).
-		SetIndexOptions(namespace.NewIndexOptions().SetBlockSize(indexBlockSize))
+		SetIndexOptions(nopts.IndexOptions().SetBlockSize(indexBlockSize))
 	md, err := namespace.NewMetadata(ident.StringID("testns"), nopts)
 	require.NoError(t, err)
 	opts := testDatabaseOptions()
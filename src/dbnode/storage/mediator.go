@@ -170,6 +170,12 @@ func (m *mediator) Tick(runType runType, forceType forceType) error {
 	return nil
 }
 
+// Snapshot takes a snapshot of the unflushed data outside of the regular
+// tick, using the current time and bootstrap state of the database.
+func (m *mediator) Snapshot() (SnapshotResult, error) {
+	return m.databaseFileSystemManager.Snapshot(m.nowFn(), m.database.BootstrapState())
+}
+
 func (m *mediator) Report() {
 	m.databaseBootstrapManager.Report()
 	m.databaseRepairer.Report()
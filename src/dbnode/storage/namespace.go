@@ -40,6 +40,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3x/context"
 	xerrors "github.com/m3db/m3x/errors"
 	"github.com/m3db/m3x/ident"
@@ -48,12 +49,14 @@ import (
 	xsync "github.com/m3db/m3x/sync"
 	xtime "github.com/m3db/m3x/time"
 
+	"github.com/pborman/uuid"
 	"github.com/uber-go/tally"
 )
 
 var (
 	errNamespaceAlreadyClosed    = errors.New("namespace already closed")
 	errNamespaceIndexingDisabled = errors.New("namespace indexing is disabled")
+	errNamespaceIndexOnly        = errors.New("namespace is index only and requires tagged writes")
 )
 
 type commitLogWriter interface {
@@ -151,6 +154,7 @@ type databaseNamespaceMetrics struct {
 	fetchBlocks         instrument.MethodMetrics
 	fetchBlocksMetadata instrument.MethodMetrics
 	queryIDs            instrument.MethodMetrics
+	aggregateQuery      instrument.MethodMetrics
 	unfulfilled         tally.Counter
 	bootstrapStart      tally.Counter
 	bootstrapEnd        tally.Counter
@@ -181,11 +185,13 @@ type databaseNamespaceTickMetrics struct {
 }
 
 type databaseNamespaceIndexTickMetrics struct {
-	numBlocks        tally.Gauge
-	numDocs          tally.Gauge
-	numSegments      tally.Gauge
-	numBlocksSealed  tally.Counter
-	numBlocksEvicted tally.Counter
+	numBlocks          tally.Gauge
+	numDocs            tally.Gauge
+	numSegments        tally.Gauge
+	numMutableSegments tally.Gauge
+	numFSTSegments     tally.Gauge
+	numBlocksSealed    tally.Counter
+	numBlocksEvicted   tally.Counter
 }
 
 // databaseNamespaceStatusMetrics are metrics emitted at a fixed interval
@@ -228,6 +234,7 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 		fetchBlocks:         instrument.NewMethodMetrics(scope, "fetchBlocks", samplingRate),
 		fetchBlocksMetadata: instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
 		queryIDs:            instrument.NewMethodMetrics(scope, "queryIDs", samplingRate),
+		aggregateQuery:      instrument.NewMethodMetrics(scope, "aggregateQuery", samplingRate),
 		unfulfilled:         scope.Counter("bootstrap.unfulfilled"),
 		bootstrapStart:      scope.Counter("bootstrap.start"),
 		bootstrapEnd:        scope.Counter("bootstrap.end"),
@@ -249,11 +256,13 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 			mergedOutOfOrderBlocks: tickScope.Counter("merged-out-of-order-blocks"),
 			errors:                 tickScope.Counter("errors"),
 			index: databaseNamespaceIndexTickMetrics{
-				numDocs:          indexTickScope.Gauge("num-docs"),
-				numBlocks:        indexTickScope.Gauge("num-blocks"),
-				numSegments:      indexTickScope.Gauge("num-segments"),
-				numBlocksSealed:  indexTickScope.Counter("num-blocks-sealed"),
-				numBlocksEvicted: indexTickScope.Counter("num-blocks-evicted"),
+				numDocs:            indexTickScope.Gauge("num-docs"),
+				numBlocks:          indexTickScope.Gauge("num-blocks"),
+				numSegments:        indexTickScope.Gauge("num-segments"),
+				numMutableSegments: indexTickScope.Gauge("num-mutable-segments"),
+				numFSTSegments:     indexTickScope.Gauge("num-fst-segments"),
+				numBlocksSealed:    indexTickScope.Counter("num-blocks-sealed"),
+				numBlocksEvicted:   indexTickScope.Counter("num-blocks-evicted"),
 			},
 		},
 		status: databaseNamespaceStatusMetrics{
@@ -542,6 +551,8 @@ func (n *dbNamespace) Tick(c context.Cancellable, tickStart time.Time) error {
 	n.metrics.tick.index.numDocs.Update(float64(indexTickResults.NumTotalDocs))
 	n.metrics.tick.index.numBlocks.Update(float64(indexTickResults.NumBlocks))
 	n.metrics.tick.index.numSegments.Update(float64(indexTickResults.NumSegments))
+	n.metrics.tick.index.numMutableSegments.Update(float64(indexTickResults.NumMutableSegments))
+	n.metrics.tick.index.numFSTSegments.Update(float64(indexTickResults.NumFSTSegments))
 	n.metrics.tick.index.numBlocksEvicted.Inc(indexTickResults.NumBlocksEvicted)
 	n.metrics.tick.index.numBlocksSealed.Inc(indexTickResults.NumBlocksSealed)
 	n.metrics.tick.errors.Inc(int64(r.errors))
@@ -558,6 +569,10 @@ func (n *dbNamespace) Write(
 	annotation []byte,
 ) (ts.Series, error) {
 	callStart := n.nowFn()
+	if n.nopts.IndexOnly() { // untagged writes have no document to index.
+		n.metrics.write.ReportError(n.nowFn().Sub(callStart))
+		return ts.Series{}, errNamespaceIndexOnly
+	}
 	shard, err := n.shardFor(id)
 	if err != nil {
 		n.metrics.write.ReportError(n.nowFn().Sub(callStart))
@@ -607,6 +622,21 @@ func (n *dbNamespace) QueryIDs(
 	return res, err
 }
 
+func (n *dbNamespace) AggregateQuery(
+	ctx context.Context,
+	query index.Query,
+	opts index.AggregateQueryOptions,
+) (index.AggregateQueryResults, error) {
+	callStart := n.nowFn()
+	if n.reverseIndex == nil { // only happens if indexing is enabled.
+		n.metrics.aggregateQuery.ReportError(n.nowFn().Sub(callStart))
+		return index.AggregateQueryResults{}, errNamespaceIndexingDisabled
+	}
+	res, err := n.reverseIndex.AggregateQuery(ctx, query, opts)
+	n.metrics.aggregateQuery.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
+	return res, err
+}
+
 func (n *dbNamespace) ReadEncoded(
 	ctx context.Context,
 	id ident.ID,
@@ -880,6 +910,7 @@ func (n *dbNamespace) FlushIndex(
 func (n *dbNamespace) Snapshot(
 	blockStart,
 	snapshotTime time.Time,
+	snapshotID uuid.UUID,
 	shardBootstrapStatesAtTickStart ShardBootstrapStates,
 	flush persist.DataFlush) error {
 	// NB(rartoul): This value can be used for emitting metrics, but should not be used
@@ -925,7 +956,7 @@ func (n *dbNamespace) Snapshot(
 			continue
 		}
 
-		err := shard.Snapshot(blockStart, snapshotTime, flush)
+		err := shard.Snapshot(blockStart, snapshotTime, snapshotID, flush)
 		if err != nil {
 			detailedErr := fmt.Errorf("shard %d failed to snapshot: %v", shard.ID(), err)
 			multiErr = multiErr.Add(detailedErr)
@@ -938,6 +969,24 @@ func (n *dbNamespace) Snapshot(
 	return res
 }
 
+func (n *dbNamespace) AddIndexSegments(
+	blockStart time.Time,
+	segments []segment.Segment,
+) error {
+	if n.reverseIndex == nil {
+		return errNamespaceIndexingDisabled
+	}
+	return n.reverseIndex.AddSegments(blockStart, segments)
+}
+
+func (n *dbNamespace) NeedsIndexFlush(
+	alignedInclusiveStart time.Time, alignedInclusiveEnd time.Time) bool {
+	if n.reverseIndex == nil {
+		return false
+	}
+	return n.reverseIndex.HasUnflushedBlocks(alignedInclusiveStart, alignedInclusiveEnd)
+}
+
 func (n *dbNamespace) NeedsFlush(
 	alignedInclusiveStart time.Time, alignedInclusiveEnd time.Time) bool {
 	// NB(r): Essentially if all are success, we don't need to flush, if any
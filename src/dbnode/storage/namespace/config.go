@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3x/ident"
 )
@@ -56,6 +57,8 @@ type MetadataConfiguration struct {
 	RepairEnabled     *bool                   `yaml:"repairEnabled"`
 	Retention         retention.Configuration `yaml:"retention" validate:"nonzero"`
 	Index             IndexConfiguration      `yaml:"index"`
+	DataCompression   *compression.Type       `yaml:"dataCompression"`
+	IndexOnly         *bool                   `yaml:"indexOnly"`
 }
 
 // Metadata returns a Metadata corresponding to the receiver struct
@@ -80,6 +83,12 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 	if v := mc.RepairEnabled; v != nil {
 		opts = opts.SetRepairEnabled(*v)
 	}
+	if v := mc.DataCompression; v != nil {
+		opts = opts.SetDataFileCompression(*v)
+	}
+	if v := mc.IndexOnly; v != nil {
+		opts = opts.SetIndexOnly(*v)
+	}
 	return NewMetadata(ident.StringID(mc.ID), opts)
 }
 
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
@@ -97,6 +98,11 @@ func ToMetadata(
 		return nil, err
 	}
 
+	dataCompression, err := compression.ParseType(opts.DataCompression)
+	if err != nil {
+		return nil, err
+	}
+
 	mopts := NewOptions().
 		SetBootstrapEnabled(opts.BootstrapEnabled).
 		SetFlushEnabled(opts.FlushEnabled).
@@ -105,7 +111,9 @@ func ToMetadata(
 		SetWritesToCommitLog(opts.WritesToCommitLog).
 		SetSnapshotEnabled(opts.SnapshotEnabled).
 		SetRetentionOptions(ropts).
-		SetIndexOptions(iopts)
+		SetIndexOptions(iopts).
+		SetDataFileCompression(dataCompression).
+		SetIndexOnly(opts.IndexOnly)
 
 	return NewMetadata(ident.StringID(id), mopts)
 }
@@ -160,5 +168,16 @@ func OptionsToProto(opts Options) *nsproto.NamespaceOptions {
 			Enabled:        iopts.Enabled(),
 			BlockSizeNanos: iopts.BlockSize().Nanoseconds(),
 		},
+		DataCompression: dataCompressionToProto(opts.DataFileCompression()),
+		IndexOnly:       opts.IndexOnly(),
+	}
+}
+
+func dataCompressionToProto(value compression.Type) string {
+	if value == compression.None {
+		// Leave unset so that registries without compressed namespaces
+		// are encoded the same as before data compression was added.
+		return ""
 	}
+	return value.String()
 }
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3x/ident"
@@ -239,3 +240,45 @@ func assertEqualRetentions(t *testing.T, expected nsproto.RetentionOptions, obse
 	require.Equal(t, expected.BlockDataExpiryAfterNotAccessPeriodNanos,
 		observed.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds())
 }
+
+func TestDataCompressionRoundTrip(t *testing.T) {
+	md, err := namespace.NewMetadata(ident.StringID("ns1"),
+		namespace.NewOptions().SetDataFileCompression(compression.Zstd))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Equal(t, "zstd", reg.Namespaces["ns1"].DataCompression)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err = nsMap.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.Equal(t, compression.Zstd, md.Options().DataFileCompression())
+}
+
+func TestFromProtoInvalidDataCompression(t *testing.T) {
+	opts := validNamespaceOpts[0]
+	opts.DataCompression = "lz4"
+	_, err := namespace.ToMetadata("ns1", &opts)
+	require.Error(t, err)
+}
+
+func TestIndexOnlyRoundTrip(t *testing.T) {
+	md, err := namespace.NewMetadata(ident.StringID("ns1"), namespace.NewOptions().
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true)).
+		SetIndexOnly(true))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.True(t, reg.Namespaces["ns1"].IndexOnly)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err = nsMap.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.True(t, md.Options().IndexOnly())
+}
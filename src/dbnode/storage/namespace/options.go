@@ -23,6 +23,7 @@ package namespace
 import (
 	"errors"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 )
 
@@ -44,12 +45,19 @@ const (
 
 	// Namespace requires repair disabled by default.
 	defaultRepairEnabled = false
+
+	// Namespace data files are not compressed by default.
+	defaultDataFileCompression = compression.None
+
+	// Namespace stores time series data by default.
+	defaultIndexOnly = false
 )
 
 var (
 	errIndexBlockSizePositive                       = errors.New("index block size must positive")
 	errIndexBlockSizeTooLarge                       = errors.New("index block size needs to be <= namespace retention period")
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
+	errIndexOnlyRequiresIndexEnabled                = errors.New("index only namespace requires indexing enabled")
 )
 
 type options struct {
@@ -61,6 +69,8 @@ type options struct {
 	repairEnabled     bool
 	retentionOpts     retention.Options
 	indexOpts         IndexOptions
+	dataCompression   compression.Type
+	indexOnly         bool
 }
 
 // NewOptions creates a new namespace options
@@ -74,6 +84,8 @@ func NewOptions() Options {
 		repairEnabled:     defaultRepairEnabled,
 		retentionOpts:     retention.NewOptions(),
 		indexOpts:         NewIndexOptions(),
+		dataCompression:   defaultDataFileCompression,
+		indexOnly:         defaultIndexOnly,
 	}
 }
 
@@ -81,7 +93,13 @@ func (o *options) Validate() error {
 	if err := o.retentionOpts.Validate(); err != nil {
 		return err
 	}
+	if err := o.dataCompression.Validate(); err != nil {
+		return err
+	}
 	if !o.indexOpts.Enabled() {
+		if o.indexOnly {
+			return errIndexOnlyRequiresIndexEnabled
+		}
 		return nil
 	}
 	var (
@@ -109,7 +127,9 @@ func (o *options) Equal(value Options) bool {
 		o.cleanupEnabled == value.CleanupEnabled() &&
 		o.repairEnabled == value.RepairEnabled() &&
 		o.retentionOpts.Equal(value.RetentionOptions()) &&
-		o.indexOpts.Equal(value.IndexOptions())
+		o.indexOpts.Equal(value.IndexOptions()) &&
+		o.dataCompression == value.DataFileCompression() &&
+		o.indexOnly == value.IndexOnly()
 }
 
 func (o *options) SetBootstrapEnabled(value bool) Options {
@@ -191,3 +211,23 @@ func (o *options) SetIndexOptions(value IndexOptions) Options {
 func (o *options) IndexOptions() IndexOptions {
 	return o.indexOpts
 }
+
+func (o *options) SetDataFileCompression(value compression.Type) Options {
+	opts := *o
+	opts.dataCompression = value
+	return &opts
+}
+
+func (o *options) DataFileCompression() compression.Type {
+	return o.dataCompression
+}
+
+func (o *options) SetIndexOnly(value bool) Options {
+	opts := *o
+	opts.indexOnly = value
+	return &opts
+}
+
+func (o *options) IndexOnly() bool {
+	return o.indexOnly
+}
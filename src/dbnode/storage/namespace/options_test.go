@@ -164,3 +164,12 @@ func TestOptionsValidateNoIndexing(t *testing.T) {
 	rOpts.EXPECT().Validate().Return(nil)
 	require.NoError(t, o1.Validate())
 }
+
+func TestOptionsValidateIndexOnlyRequiresIndexing(t *testing.T) {
+	o1 := NewOptions().SetIndexOnly(true)
+	require.Equal(t, errIndexOnlyRequiresIndexEnabled, o1.Validate())
+
+	o2 := o1.SetIndexOptions(NewIndexOptions().SetEnabled(true))
+	require.NoError(t, o2.Validate())
+	require.False(t, o2.Equal(o2.SetIndexOnly(false)))
+}
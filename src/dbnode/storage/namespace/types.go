@@ -84,6 +84,22 @@ type Options interface {
 
 	// IndexOptions returns the IndexOptions.
 	IndexOptions() IndexOptions
+
+	// SetDataFileCompression sets the compression applied to data files.
+	SetDataFileCompression(value compression.Type) Options
+
+	// DataFileCompression returns the compression applied to data files.
+	DataFileCompression() compression.Type
+
+	// SetIndexOnly sets whether the namespace only indexes the documents
+	// written to it, without storing any time series data. Documents hold
+	// the ID and tags of the series written along with any fields encoded in
+	// the write annotation with convert.EncodeFields.
+	SetIndexOnly(value bool) Options
+
+	// IndexOnly returns whether the namespace only indexes the documents
+	// written to it, without storing any time series data.
+	IndexOnly() bool
 }
 
 // IndexOptions controls the indexing options for a namespace.
@@ -47,6 +47,7 @@ import (
 
 	"github.com/fortytw2/leaktest"
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
@@ -170,6 +171,20 @@ func TestNamespaceWriteShardOwned(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNamespaceWriteIndexOnly(t *testing.T) {
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	opts := defaultTestNs1Opts.
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true)).
+		SetIndexOnly(true)
+	ns, closer := newTestNamespaceWithIDOpts(t, defaultTestNs1ID, opts)
+	defer closer()
+
+	_, err := ns.Write(ctx, ident.StringID("foo"), time.Now(), 0.0, xtime.Second, nil)
+	require.Equal(t, errNamespaceIndexOnly, err)
+}
+
 func TestNamespaceReadEncodedShardNotOwned(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
@@ -438,7 +453,7 @@ func TestNamespaceSnapshotNotBootstrapped(t *testing.T) {
 
 	blockSize := ns.Options().RetentionOptions().BlockSize()
 	blockStart := time.Now().Truncate(blockSize)
-	require.Equal(t, errNamespaceNotBootstrapped, ns.Snapshot(blockStart, blockStart, nil, nil))
+	require.Equal(t, errNamespaceNotBootstrapped, ns.Snapshot(blockStart, blockStart, nil, nil, nil))
 }
 
 func TestNamespaceSnapshotShardIsSnapshotting(t *testing.T) {
@@ -541,13 +556,13 @@ func testSnapshotWithShardSnapshotErrs(t *testing.T, shardMethodResults []snapsh
 		shardID := uint32(i)
 		shard.EXPECT().ID().Return(uint32(i)).AnyTimes()
 		if tc.expectSnapshot {
-			shard.EXPECT().Snapshot(blockStart, now, nil).Return(tc.shardSnapshotErr)
+			shard.EXPECT().Snapshot(blockStart, now, gomock.Any(), nil).Return(tc.shardSnapshotErr)
 		}
 		ns.shards[testShardIDs[i].ID()] = shard
 		shardBootstrapStates[shardID] = tc.shardBootstrapStateBeforeTick
 	}
 
-	return ns.Snapshot(blockStart, now, shardBootstrapStates, nil)
+	return ns.Snapshot(blockStart, now, uuid.NewRandom(), shardBootstrapStates, nil)
 }
 
 func TestNamespaceTruncate(t *testing.T) {
@@ -33,10 +33,12 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/retention"
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/downsample"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
@@ -123,6 +125,8 @@ type options struct {
 	repairEnabled                  bool
 	indexOpts                      index.Options
 	repairOpts                     repair.Options
+	downsampleRules                []downsample.Rule
+	quotaEnforcer                  quota.Enforcer
 	newEncoderFn                   encoding.NewEncoderFn
 	newDecoderFn                   encoding.NewDecoderFn
 	bootstrapProcessProvider       bootstrap.ProcessProvider
@@ -178,6 +182,7 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		indexOpts:                index.NewOptions(),
 		repairEnabled:            defaultRepairEnabled,
 		repairOpts:               repair.NewOptions(),
+		quotaEnforcer:            quota.NewEnforcer(time.Now),
 		bootstrapProcessProvider: defaultBootstrapProcessProvider,
 		minSnapshotInterval:      defaultMinSnapshotInterval,
 		poolOpts:                 poolOpts,
@@ -229,6 +234,13 @@ func (o *options) Validate() error {
 		}
 	}
 
+	// validate downsample rules
+	for _, rule := range o.DownsampleRules() {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("unable to validate downsample rule %s, err: %v", rule.String(), err)
+		}
+	}
+
 	// validate indexing options
 	iOpts := o.IndexOptions()
 	if iOpts == nil {
@@ -376,6 +388,26 @@ func (o *options) RepairOptions() repair.Options {
 	return o.repairOpts
 }
 
+func (o *options) SetDownsampleRules(value []downsample.Rule) Options {
+	opts := *o
+	opts.downsampleRules = value
+	return &opts
+}
+
+func (o *options) DownsampleRules() []downsample.Rule {
+	return o.downsampleRules
+}
+
+func (o *options) SetQuotaEnforcer(value quota.Enforcer) Options {
+	opts := *o
+	opts.quotaEnforcer = value
+	return &opts
+}
+
+func (o *options) QuotaEnforcer() quota.Enforcer {
+	return o.quotaEnforcer
+}
+
 func (o *options) SetEncodingM3TSZPooled() Options {
 	opts := *o
 
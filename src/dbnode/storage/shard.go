@@ -55,6 +55,7 @@ import (
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/pborman/uuid"
 	"github.com/uber-go/tally"
 )
 
@@ -189,6 +190,7 @@ type dbShardMetrics struct {
 	insertAsyncWriteErrors        tally.Counter
 	seriesBootstrapBlocksToBuffer tally.Counter
 	seriesBootstrapBlocksMerged   tally.Counter
+	insertQuotaExceeded           tally.Counter
 }
 
 func newDatabaseShardMetrics(scope tally.Scope) dbShardMetrics {
@@ -209,6 +211,7 @@ func newDatabaseShardMetrics(scope tally.Scope) dbShardMetrics {
 		}).Counter("insert-async.errors"),
 		seriesBootstrapBlocksToBuffer: seriesBootstrapScope.Counter("blocks-to-buffer"),
 		seriesBootstrapBlocksMerged:   seriesBootstrapScope.Counter("blocks-merged"),
+		insertQuotaExceeded:           scope.Counter("insert-quota-exceeded"),
 	}
 }
 
@@ -341,6 +344,14 @@ func (s *dbShard) Stream(
 	return s.DatabaseBlockRetriever.Stream(ctx, s.shard, id, blockStart, onRetrieve)
 }
 
+func (s *dbShard) InvalidateBlock(blockStart time.Time) error {
+	if s.DatabaseBlockRetriever == nil {
+		// Blocks are not retrieved from disk.
+		return nil
+	}
+	return s.DatabaseBlockRetriever.InvalidateBlock(s.shard, blockStart)
+}
+
 // IsBlockRetrievable implements series.QueryableBlockRetriever
 func (s *dbShard) IsBlockRetrievable(blockStart time.Time) bool {
 	flushState := s.FlushState(blockStart)
@@ -776,6 +787,22 @@ func (s *dbShard) writeAndIndex(
 	annotation []byte,
 	shouldReverseIndex bool,
 ) (ts.Series, error) {
+	// NB: index only namespaces hold no datapoints, the write only serves
+	// to create or extend the series document in the index block. The
+	// annotation of the write holds the encoded document fields indexed in
+	// addition to the series tags, the value and unit are discarded.
+	var (
+		indexOnly      = s.namespace.Options().IndexOnly()
+		documentFields doc.Fields
+	)
+	if indexOnly && len(annotation) > 0 {
+		var err error
+		documentFields, err = convert.DecodeFields(annotation)
+		if err != nil {
+			return ts.Series{}, xerrors.NewInvalidParamsError(err)
+		}
+	}
+
 	// Prepare write
 	entry, opts, err := s.tryRetrieveWritableSeries(id)
 	if err != nil {
@@ -784,6 +811,15 @@ func (s *dbShard) writeAndIndex(
 
 	writable := entry != nil
 
+	// Enforce the namespace new series quota before inserting a new series
+	if !writable {
+		enforcer := s.opts.QuotaEnforcer()
+		if err := enforcer.AllowNewSeries(s.namespace.ID()); err != nil {
+			s.metrics.insertQuotaExceeded.Inc(1)
+			return ts.Series{}, err
+		}
+	}
+
 	// If no entry and we are not writing new series asynchronously
 	if !writable && !opts.writeNewSeriesAsync {
 		// Avoid double lookup by enqueueing insert immediately
@@ -792,6 +828,7 @@ func (s *dbShard) writeAndIndex(
 			pendingIndex: dbShardPendingIndex{
 				timestamp:  timestamp,
 				enqueuedAt: s.nowFn(),
+				fields:     documentFields,
 			},
 		})
 		if err != nil {
@@ -819,7 +856,9 @@ func (s *dbShard) writeAndIndex(
 	)
 	if writable {
 		// Perform write
-		err = entry.Series.Write(ctx, timestamp, value, unit, annotation)
+		if !indexOnly {
+			err = entry.Series.Write(ctx, timestamp, value, unit, annotation)
+		}
 		// Load series metadata before decrementing the writer count
 		// to ensure this metadata is snapshotted at a consistent state
 		// NB(r): We explicitly do not place the series ID back into a
@@ -832,7 +871,7 @@ func (s *dbShard) writeAndIndex(
 		if err == nil && shouldReverseIndex {
 			if entry.NeedsIndexUpdate(s.reverseIndex.BlockStartForWriteTime(timestamp)) {
 				err = s.insertSeriesForIndexingAsyncBatched(entry, timestamp,
-					opts.writeNewSeriesAsync)
+					documentFields, opts.writeNewSeriesAsync)
 			}
 		}
 		// release the reference we got on entry from `writableSeries`
@@ -843,7 +882,7 @@ func (s *dbShard) writeAndIndex(
 	} else {
 		// This is an asynchronous insert and write
 		result, err := s.insertSeriesAsyncBatched(id, tags, dbShardInsertAsyncOptions{
-			hasPendingWrite: true,
+			hasPendingWrite: !indexOnly,
 			pendingWrite: dbShardPendingWrite{
 				timestamp:  timestamp,
 				value:      value,
@@ -854,6 +893,7 @@ func (s *dbShard) writeAndIndex(
 			pendingIndex: dbShardPendingIndex{
 				timestamp:  timestamp,
 				enqueuedAt: s.nowFn(),
+				fields:     documentFields,
 			},
 		})
 		if err != nil {
@@ -1052,6 +1092,7 @@ type insertAsyncResult struct {
 func (s *dbShard) insertSeriesForIndexingAsyncBatched(
 	entry *lookup.Entry,
 	timestamp time.Time,
+	fields doc.Fields,
 	async bool,
 ) error {
 	indexBlockStart := s.reverseIndex.BlockStartForWriteTime(timestamp)
@@ -1064,6 +1105,7 @@ func (s *dbShard) insertSeriesForIndexingAsyncBatched(
 			pendingIndex: dbShardPendingIndex{
 				timestamp:  timestamp,
 				enqueuedAt: s.nowFn(),
+				fields:     fields,
 			},
 			// indicate we already have inc'd the entry's ref count, so we can correctly
 			// handle the ref counting semantics in `insertSeriesBatch`.
@@ -1298,13 +1340,14 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 
 			var d doc.Document
 			d.ID = id.Bytes() // IDs from shard entries are always set NoFinalize
-			d.Fields = make(doc.Fields, 0, len(tags))
+			d.Fields = make(doc.Fields, 0, len(tags)+len(pendingIndex.fields))
 			for _, tag := range tags {
 				d.Fields = append(d.Fields, doc.Field{
 					Name:  tag.Name.Bytes(),  // Tags from shard entries are always set NoFinalize
 					Value: tag.Value.Bytes(), // Tags from shard entries are always set NoFinalize
 				})
 			}
+			d.Fields = append(d.Fields, pendingIndex.fields...)
 			indexBatch.Append(index.WriteBatchEntry{
 				Timestamp:     pendingIndex.timestamp,
 				OnIndexSeries: entry,
@@ -1783,6 +1826,13 @@ func (s *dbShard) Flush(
 	}
 	s.RUnlock()
 
+	if s.namespace.Options().IndexOnly() {
+		// NB: index only namespaces hold no series data so there is nothing to
+		// write to a data fileset, their documents are flushed with the index
+		// blocks. Mark the flush as done so the index blocks can be flushed.
+		return s.markFlushStateSuccessOrError(blockStart, nil)
+	}
+
 	prepareOpts := persist.DataPrepareOptions{
 		NamespaceMetadata: s.namespace,
 		Shard:             s.ID(),
@@ -1834,6 +1884,7 @@ func (s *dbShard) Flush(
 func (s *dbShard) Snapshot(
 	blockStart time.Time,
 	snapshotTime time.Time,
+	snapshotID uuid.UUID,
 	flush persist.DataFlush,
 ) error {
 	// We don't snapshot data when the shard is still bootstrapping
@@ -1863,6 +1914,7 @@ func (s *dbShard) Snapshot(
 		DeleteIfExists: false,
 		Snapshot: persist.DataPrepareSnapshotOptions{
 			SnapshotTime: snapshotTime,
+			SnapshotID:   snapshotID,
 		},
 	}
 	prepared, err := flush.PrepareData(prepareOpts)
@@ -2026,7 +2078,8 @@ func (s *dbShard) CleanupSnapshots(earliestToRetain time.Time) error {
 }
 
 func (s *dbShard) CleanupExpiredFileSets(earliestToRetain time.Time) error {
-	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	filePathPrefix := fsOpts.FilePathPrefix()
 	multiErr := xerrors.NewMultiError()
 	expired, err := s.filesetBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
 	if err != nil {
@@ -2035,6 +2088,12 @@ func (s *dbShard) CleanupExpiredFileSets(earliestToRetain time.Time) error {
 				filePathPrefix, s.namespace.ID(), s.ID(), err)
 		multiErr = multiErr.Add(detailedErr)
 	}
+	// NB: Delete the tiered copies of expired filesets before the local files
+	// that identify them so that they are never orphaned in the object store.
+	if err := fs.DeleteTieredDataFiles(fsOpts, expired); err != nil {
+		multiErr = multiErr.Add(err)
+		return multiErr.FinalError()
+	}
 	if err := s.deleteFilesFn(expired); err != nil {
 		multiErr = multiErr.Add(err)
 	}
@@ -27,12 +27,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	xclock "github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/context"
+	xerrors "github.com/m3db/m3x/errors"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 
@@ -101,6 +104,90 @@ func TestShardInsertNamespaceIndex(t *testing.T) {
 	require.Equal(t, []byte("value"), indexWrites[0].Fields[0].Value)
 }
 
+func TestShardIndexOnlyNamespaceWriteTagged(t *testing.T) {
+	defer leaktest.CheckTimeout(t, 2*time.Second)()
+	opts := testDatabaseOptions()
+
+	var (
+		lock        sync.Mutex
+		indexWrites []doc.Document
+	)
+
+	now := time.Now()
+	blockSize := namespace.NewIndexOptions().BlockSize()
+	blockStart := xtime.ToUnixNano(now.Truncate(blockSize))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	idx := NewMocknamespaceIndex(ctrl)
+	idx.EXPECT().BlockStartForWriteTime(gomock.Any()).Return(blockStart).AnyTimes()
+	idx.EXPECT().WriteBatch(gomock.Any()).Do(
+		func(batch *index.WriteBatch) {
+			lock.Lock()
+			indexWrites = append(indexWrites, batch.PendingDocs()...)
+			lock.Unlock()
+			for i, e := range batch.PendingEntries() {
+				e.OnIndexSeries.OnIndexSuccess(blockStart)
+				e.OnIndexSeries.OnIndexFinalize(blockStart)
+				batch.PendingEntries()[i].OnIndexSeries = nil
+			}
+		}).Return(nil).AnyTimes()
+
+	nopts := defaultTestNs1Opts.
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true)).
+		SetIndexOnly(true)
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID, nopts)
+	require.NoError(t, err)
+	nsReaderMgr := newNamespaceReaderManager(metadata, tally.NoopScope, opts)
+	seriesOpts := NewSeriesOptionsFromOptions(opts, nopts.RetentionOptions())
+	shard := newDatabaseShard(metadata, 0, nil, nsReaderMgr,
+		&testIncreasingIndex{}, idx, true, opts, seriesOpts).(*dbShard)
+	shard.SetRuntimeOptions(runtime.NewOptions().SetWriteNewSeriesAsync(false))
+	defer shard.Close()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	// The annotation of the write holds document fields beyond the tags.
+	annotation := convert.EncodeFields(nil, doc.Fields{
+		{Name: []byte("owner"), Value: []byte("infra")},
+	})
+	_, err = shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("host", "a"))),
+		now, 1.0, xtime.Second, annotation)
+	require.NoError(t, err)
+
+	// The document is indexed but no datapoints are stored.
+	lock.Lock()
+	require.Len(t, indexWrites, 1)
+	require.Equal(t, []byte("foo"), indexWrites[0].ID)
+	require.Equal(t, doc.Fields{
+		{Name: []byte("host"), Value: []byte("a")},
+		{Name: []byte("owner"), Value: []byte("infra")},
+	}, indexWrites[0].Fields)
+	lock.Unlock()
+
+	blocks, err := shard.ReadEncoded(ctx, ident.StringID("foo"),
+		now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, blocks, 0)
+
+	// Annotations that are not encoded document fields are rejected.
+	_, err = shard.WriteTagged(ctx, ident.StringID("bar"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("host", "b"))),
+		now, 1.0, xtime.Second, []byte{0xff})
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	// Flushing writes no data fileset but marks the block as flushed so that
+	// the index block can be flushed.
+	shard.bootstrapState = Bootstrapped
+	blockStartTime := now.Truncate(nopts.RetentionOptions().BlockSize())
+	flush := persist.NewMockDataFlush(ctrl)
+	require.NoError(t, shard.Flush(blockStartTime, flush))
+	require.Equal(t, fileOpSuccess, shard.FlushState(blockStartTime).Status)
+}
+
 func TestShardAsyncInsertNamespaceIndex(t *testing.T) {
 	defer leaktest.CheckTimeout(t, 2*time.Second)()
 
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/series/lookup"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 
@@ -130,6 +131,9 @@ type dbShardPendingWrite struct {
 type dbShardPendingIndex struct {
 	timestamp  time.Time
 	enqueuedAt time.Time
+	// fields are the document fields indexed in addition to the series tags,
+	// only set for writes to index only namespaces.
+	fields doc.Fields
 }
 
 type dbShardPendingRetrievedBlock struct {
@@ -32,6 +32,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -49,6 +50,7 @@ import (
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
@@ -323,7 +325,7 @@ func TestShardSnapshotShardNotBootstrapped(t *testing.T) {
 	s.bootstrapState = Bootstrapping
 
 	flush := persist.NewMockDataFlush(ctrl)
-	err := s.Snapshot(blockStart, blockStart, flush)
+	err := s.Snapshot(blockStart, blockStart, nil, flush)
 	require.Equal(t, errShardNotBootstrappedToSnapshot, err)
 }
 
@@ -331,7 +333,10 @@ func TestShardSnapshotSeriesSnapshotSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	blockStart := time.Unix(21600, 0)
+	var (
+		blockStart = time.Unix(21600, 0)
+		snapshotID = uuid.NewRandom()
+	)
 
 	s := testDatabaseShard(t, testDatabaseOptions())
 	defer s.Close()
@@ -351,6 +356,7 @@ func TestShardSnapshotSeriesSnapshotSuccess(t *testing.T) {
 		FileSetType:       persist.FileSetSnapshotType,
 		Snapshot: persist.DataPrepareSnapshotOptions{
 			SnapshotTime: blockStart,
+			SnapshotID:   snapshotID,
 		},
 	})
 	flush.EXPECT().PrepareData(prepareOpts).Return(prepared, nil)
@@ -370,7 +376,7 @@ func TestShardSnapshotSeriesSnapshotSuccess(t *testing.T) {
 		s.list.PushBack(lookup.NewEntry(series, 0))
 	}
 
-	err := s.Snapshot(blockStart, blockStart, flush)
+	err := s.Snapshot(blockStart, blockStart, snapshotID, flush)
 
 	require.Equal(t, len(snapshotted), 2)
 	for i := 0; i < 2; i++ {
@@ -1251,3 +1257,39 @@ func TestShardIterateBatchSize(t *testing.T) {
 
 	require.True(t, shardIterateBatchMinSize < iterateBatchSize(2000))
 }
+
+func TestShardWriteNewSeriesQuotaExceeded(t *testing.T) {
+	now := time.Now()
+	enforcer := quota.NewEnforcer(func() time.Time { return now })
+	limits := quota.NewLimits()
+	limits.Namespaces = map[string]quota.NamespaceLimits{
+		defaultTestNs1ID.String(): {NewSeriesPerSecond: 1},
+	}
+	enforcer.SetLimits(limits)
+
+	opts := testDatabaseOptions().SetQuotaEnforcer(enforcer)
+	shard := testDatabaseShard(t, opts)
+	shard.SetRuntimeOptions(runtime.NewOptions().
+		SetWriteNewSeriesAsync(false))
+	defer shard.Close()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	_, err := shard.Write(ctx, ident.StringID("foo"), now, 1.0, xtime.Second, nil)
+	require.NoError(t, err)
+
+	// Writes to existing series are not subject to the new series quota
+	_, err = shard.Write(ctx, ident.StringID("foo"), now.Add(time.Second), 2.0, xtime.Second, nil)
+	require.NoError(t, err)
+
+	_, err = shard.Write(ctx, ident.StringID("bar"), now, 1.0, xtime.Second, nil)
+	require.Error(t, err)
+	require.True(t, quota.IsExceededError(err))
+	require.Equal(t, int64(1), shard.NumSeries())
+
+	// The quota resets with the next window
+	now = now.Add(time.Second)
+	_, err = shard.Write(ctx, ident.StringID("bar"), now, 1.0, xtime.Second, nil)
+	require.NoError(t, err)
+}
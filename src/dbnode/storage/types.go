@@ -29,24 +29,30 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/quota"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/downsample"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xcounter"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
 	xsync "github.com/m3db/m3x/sync"
 	xtime "github.com/m3db/m3x/time"
+
+	"github.com/pborman/uuid"
 )
 
 // PageToken is an opaque paging token.
@@ -139,6 +145,22 @@ type Database interface {
 		opts index.QueryOptions,
 	) (index.QueryResults, error)
 
+	// AggregateQuery resolves the given query into the distinct tag names, and
+	// optionally values, of the series it matches.
+	AggregateQuery(
+		ctx context.Context,
+		namespace ident.ID,
+		query index.Query,
+		opts index.AggregateQueryOptions,
+	) (index.AggregateQueryResults, error)
+
+	// Cardinality returns the tag names and metric names with the
+	// highest estimated cardinality in the index of a namespace.
+	Cardinality(
+		namespace ident.ID,
+		opts cardinality.ReportOptions,
+	) (cardinality.Report, error)
+
 	// ReadEncoded retrieves encoded segments for an ID
 	ReadEncoded(
 		ctx context.Context,
@@ -189,6 +211,10 @@ type Database interface {
 	// Truncate truncates data for the given namespace
 	Truncate(namespace ident.ID) (int64, error)
 
+	// Snapshot takes a snapshot of the unflushed data of every namespace and
+	// returns once the snapshot and its metadata have been written.
+	Snapshot() (SnapshotResult, error)
+
 	// BootstrapState captures and returns a snapshot of the databases' bootstrap state.
 	BootstrapState() DatabaseBootstrapState
 }
@@ -274,6 +300,14 @@ type databaseNamespace interface {
 		opts index.QueryOptions,
 	) (index.QueryResults, error)
 
+	// AggregateQuery resolves the given query into the distinct tag names, and
+	// optionally values, of the series it matches.
+	AggregateQuery(
+		ctx context.Context,
+		query index.Query,
+		opts index.AggregateQueryOptions,
+	) (index.AggregateQueryResults, error)
+
 	// ReadEncoded reads data for given id within [start, end)
 	ReadEncoded(
 		ctx context.Context,
@@ -314,10 +348,20 @@ type databaseNamespace interface {
 		flush persist.IndexFlush,
 	) error
 
+	// AddIndexSegments adds segments holding documents of series flushed
+	// outside of the write path to the index block that starts at blockStart.
+	AddIndexSegments(blockStart time.Time, segments []segment.Segment) error
+
+	// NeedsIndexFlush returns true if the namespace index holds documents
+	// for the period: [start, end] (both inclusive) that have not been
+	// flushed to index filesets yet.
+	NeedsIndexFlush(alignedInclusiveStart time.Time, alignedInclusiveEnd time.Time) bool
+
 	// Snapshot snapshots unflushed in-memory data
 	Snapshot(
 		blockStart,
 		snapshotTime time.Time,
+		snapshotID uuid.UUID,
 		shardBootstrapStatesAtTickStart ShardBootstrapStates,
 		flush persist.DataFlush,
 	) error
@@ -430,11 +474,19 @@ type databaseShard interface {
 	) error
 
 	// Snapshot snapshot's the unflushed series' in this shard.
-	Snapshot(blockStart, snapshotStart time.Time, flush persist.DataFlush) error
+	Snapshot(
+		blockStart, snapshotStart time.Time,
+		snapshotID uuid.UUID,
+		flush persist.DataFlush,
+	) error
 
 	// FlushState returns the flush state for this shard at block start.
 	FlushState(blockStart time.Time) fileOpState
 
+	// InvalidateBlock invalidates the block retriever state for the flushed
+	// fileset at block start after it has been replaced on disk.
+	InvalidateBlock(blockStart time.Time) error
+
 	// SnapshotState returns the snapshot state for this shard.
 	SnapshotState() (isSnapshotting bool, lastSuccessfulSnapshot time.Time)
 
@@ -472,11 +524,33 @@ type namespaceIndex interface {
 		opts index.QueryOptions,
 	) (index.QueryResults, error)
 
+	// AggregateQuery resolves the given query into the distinct tag names, and
+	// optionally values, of the documents it matches.
+	AggregateQuery(
+		ctx context.Context,
+		query index.Query,
+		opts index.AggregateQueryOptions,
+	) (index.AggregateQueryResults, error)
+
+	// Cardinality returns the tag names and metric names with the
+	// highest estimated cardinality in the index.
+	Cardinality(
+		opts cardinality.ReportOptions,
+	) (cardinality.Report, error)
+
 	// Bootstrap bootstraps the index the provided segments.
 	Bootstrap(
 		bootstrapResults result.IndexResults,
 	) error
 
+	// AddSegments adds segments holding documents of series flushed outside
+	// of the write path, such as rolled up series, to the index block that
+	// starts at blockStart.
+	AddSegments(
+		blockStart time.Time,
+		segments []segment.Segment,
+	) error
+
 	// CleanupExpiredFileSets removes expired fileset files. Expiration is calcuated
 	// using the provided `t` as the frame of reference.
 	CleanupExpiredFileSets(t time.Time) error
@@ -492,6 +566,11 @@ type namespaceIndex interface {
 		shards []databaseShard,
 	) error
 
+	// HasUnflushedBlocks returns whether any of the index blocks that overlap
+	// the period [start, end] (both inclusive) hold documents in mutable
+	// segments that have not been flushed yet.
+	HasUnflushedBlocks(start, end time.Time) bool
+
 	// Close will release the index resources and close the index.
 	Close() error
 }
@@ -499,11 +578,13 @@ type namespaceIndex interface {
 // namespaceIndexTickResult are details about the work performed by the namespaceIndex
 // during a Tick().
 type namespaceIndexTickResult struct {
-	NumBlocks        int64
-	NumBlocksSealed  int64
-	NumBlocksEvicted int64
-	NumSegments      int64
-	NumTotalDocs     int64
+	NumBlocks          int64
+	NumBlocksSealed    int64
+	NumBlocksEvicted   int64
+	NumSegments        int64
+	NumMutableSegments int64
+	NumFSTSegments     int64
+	NumTotalDocs       int64
 }
 
 // namespaceIndexInsertQueue is a queue used in-front of the indexing component
@@ -547,6 +628,10 @@ type databaseFlushManager interface {
 	// if any.
 	LastSuccessfulSnapshotStartTime() (time.Time, bool)
 
+	// Snapshot takes a snapshot of the unflushed data of every namespace
+	// outside of a flush, failing if a flush is in progress.
+	Snapshot(t time.Time, dbBootstrapState DatabaseBootstrapState) (SnapshotResult, error)
+
 	// Report reports runtime information
 	Report()
 }
@@ -560,6 +645,16 @@ type databaseCleanupManager interface {
 	Report()
 }
 
+// databaseDownsampleManager manages rolling up flushed blocks of namespaces
+// into lower resolution namespaces.
+type databaseDownsampleManager interface {
+	// Downsample rolls up any flushed blocks that are ready to be rolled up.
+	Downsample(t time.Time) error
+
+	// Report reports runtime information
+	Report()
+}
+
 // databaseFileSystemManager manages the database related filesystem activities.
 type databaseFileSystemManager interface {
 	// Cleanup cleans up data not needed in the persistent storage.
@@ -593,6 +688,10 @@ type databaseFileSystemManager interface {
 	// LastSuccessfulSnapshotStartTime returns the start time of the last successful snapshot,
 	// if any.
 	LastSuccessfulSnapshotStartTime() (time.Time, bool)
+
+	// Snapshot takes a snapshot of the unflushed data of every namespace,
+	// failing if file operations are disabled or in progress.
+	Snapshot(t time.Time, dbBootstrapState DatabaseBootstrapState) (SnapshotResult, error)
 }
 
 // databaseShardRepairer repairs in-memory data for a shard
@@ -668,6 +767,9 @@ type databaseMediator interface {
 	// LastSuccessfulSnapshotStartTime returns the start time of the last successful snapshot,
 	// if any.
 	LastSuccessfulSnapshotStartTime() (time.Time, bool)
+
+	// Snapshot takes a snapshot of the unflushed data of every namespace.
+	Snapshot() (SnapshotResult, error)
 }
 
 // databaseNamespaceWatch watches for namespace updates.
@@ -762,6 +864,22 @@ type Options interface {
 	// RepairOptions returns the repair options.
 	RepairOptions() repair.Options
 
+	// SetDownsampleRules sets the rules used to roll up flushed blocks of
+	// namespaces into lower resolution namespaces.
+	SetDownsampleRules(value []downsample.Rule) Options
+
+	// DownsampleRules returns the rules used to roll up flushed blocks of
+	// namespaces into lower resolution namespaces.
+	DownsampleRules() []downsample.Rule
+
+	// SetQuotaEnforcer sets the enforcer of per-namespace and per-tenant
+	// quotas, its limits are updated from the runtime options.
+	SetQuotaEnforcer(value quota.Enforcer) Options
+
+	// QuotaEnforcer returns the enforcer of per-namespace and per-tenant
+	// quotas, its limits are updated from the runtime options.
+	QuotaEnforcer() quota.Enforcer
+
 	// SetBootstrapProcessProvider sets the bootstrap process provider for the database.
 	SetBootstrapProcessProvider(value bootstrap.ProcessProvider) Options
 
@@ -882,6 +1000,17 @@ type Options interface {
 	WriteBatchPool() *ts.WriteBatchPool
 }
 
+// SnapshotResult describes a snapshot that has been taken on demand.
+type SnapshotResult struct {
+	// ID is the ID of the snapshot, which the snapshot filesets and
+	// snapshot metadata of the snapshot are written with.
+	ID uuid.UUID
+
+	// Time is the time of the snapshot, all data written before the
+	// snapshot time is captured by the snapshot.
+	Time time.Time
+}
+
 // DatabaseBootstrapState stores a snapshot of the bootstrap state for all shards across all
 // namespaces at a given moment in time.
 type DatabaseBootstrapState struct {
@@ -116,8 +116,10 @@ func (x PostingsFormat) String() string {
 func (PostingsFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptorFswriter, []int{2} }
 
 type Metadata struct {
-	PostingsFormat PostingsFormat `protobuf:"varint,1,opt,name=postingsFormat,proto3,enum=fswriter.PostingsFormat" json:"postingsFormat,omitempty"`
-	NumDocs        int64          `protobuf:"varint,2,opt,name=numDocs,proto3" json:"numDocs,omitempty"`
+	PostingsFormat    PostingsFormat `protobuf:"varint,1,opt,name=postingsFormat,proto3,enum=fswriter.PostingsFormat" json:"postingsFormat,omitempty"`
+	NumDocs           int64          `protobuf:"varint,2,opt,name=numDocs,proto3" json:"numDocs,omitempty"`
+	TermWriteTimes    bool           `protobuf:"varint,3,opt,name=termWriteTimes,proto3" json:"termWriteTimes,omitempty"`
+	EarliestWriteTime int64          `protobuf:"varint,4,opt,name=earliestWriteTime,proto3" json:"earliestWriteTime,omitempty"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -139,6 +141,20 @@ func (m *Metadata) GetNumDocs() int64 {
 	return 0
 }
 
+func (m *Metadata) GetTermWriteTimes() bool {
+	if m != nil {
+		return m.TermWriteTimes
+	}
+	return false
+}
+
+func (m *Metadata) GetEarliestWriteTime() int64 {
+	if m != nil {
+		return m.EarliestWriteTime
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Metadata)(nil), "fswriter.Metadata")
 	proto.RegisterEnum("fswriter.SegmentType", SegmentType_name, SegmentType_value)
@@ -170,6 +186,21 @@ func (m *Metadata) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintFswriter(dAtA, i, uint64(m.NumDocs))
 	}
+	if m.TermWriteTimes {
+		dAtA[i] = 0x18
+		i++
+		if m.TermWriteTimes {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.EarliestWriteTime != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintFswriter(dAtA, i, uint64(m.EarliestWriteTime))
+	}
 	return i, nil
 }
 
@@ -191,6 +222,12 @@ func (m *Metadata) Size() (n int) {
 	if m.NumDocs != 0 {
 		n += 1 + sovFswriter(uint64(m.NumDocs))
 	}
+	if m.TermWriteTimes {
+		n += 2
+	}
+	if m.EarliestWriteTime != 0 {
+		n += 1 + sovFswriter(uint64(m.EarliestWriteTime))
+	}
 	return n
 }
 
@@ -274,6 +311,45 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TermWriteTimes", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFswriter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.TermWriteTimes = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EarliestWriteTime", wireType)
+			}
+			m.EarliestWriteTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFswriter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EarliestWriteTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFswriter(dAtA[iNdEx:])
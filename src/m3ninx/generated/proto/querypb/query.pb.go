@@ -33,6 +33,9 @@
 		NegationQuery
 		ConjunctionQuery
 		DisjunctionQuery
+		FieldQuery
+		PrefixQuery
+		AllQuery
 		Query
 */
 package querypb
@@ -150,6 +153,54 @@ func (m *DisjunctionQuery) GetQueries() []*Query {
 	return nil
 }
 
+type FieldQuery struct {
+	Field []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+}
+
+func (m *FieldQuery) Reset()                    { *m = FieldQuery{} }
+func (m *FieldQuery) String() string            { return proto.CompactTextString(m) }
+func (*FieldQuery) ProtoMessage()               {}
+func (*FieldQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
+
+func (m *FieldQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+type PrefixQuery struct {
+	Field  []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Prefix []byte `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (m *PrefixQuery) Reset()                    { *m = PrefixQuery{} }
+func (m *PrefixQuery) String() string            { return proto.CompactTextString(m) }
+func (*PrefixQuery) ProtoMessage()               {}
+func (*PrefixQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{6} }
+
+func (m *PrefixQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *PrefixQuery) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+type AllQuery struct {
+}
+
+func (m *AllQuery) Reset()                    { *m = AllQuery{} }
+func (m *AllQuery) String() string            { return proto.CompactTextString(m) }
+func (*AllQuery) ProtoMessage()               {}
+func (*AllQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{7} }
+
 type Query struct {
 	// Types that are valid to be assigned to Query:
 	//	*Query_Term
@@ -157,13 +208,16 @@ type Query struct {
 	//	*Query_Negation
 	//	*Query_Conjunction
 	//	*Query_Disjunction
+	//	*Query_Field
+	//	*Query_Prefix
+	//	*Query_All
 	Query isQuery_Query `protobuf_oneof:"query"`
 }
 
 func (m *Query) Reset()                    { *m = Query{} }
 func (m *Query) String() string            { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()               {}
-func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
+func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{8} }
 
 type isQuery_Query interface {
 	isQuery_Query()
@@ -186,12 +240,24 @@ type Query_Conjunction struct {
 type Query_Disjunction struct {
 	Disjunction *DisjunctionQuery `protobuf:"bytes,5,opt,name=disjunction,oneof"`
 }
+type Query_Field struct {
+	Field *FieldQuery `protobuf:"bytes,6,opt,name=field,oneof"`
+}
+type Query_Prefix struct {
+	Prefix *PrefixQuery `protobuf:"bytes,7,opt,name=prefix,oneof"`
+}
+type Query_All struct {
+	All *AllQuery `protobuf:"bytes,8,opt,name=all,oneof"`
+}
 
 func (*Query_Term) isQuery_Query()        {}
 func (*Query_Regexp) isQuery_Query()      {}
 func (*Query_Negation) isQuery_Query()    {}
 func (*Query_Conjunction) isQuery_Query() {}
 func (*Query_Disjunction) isQuery_Query() {}
+func (*Query_Field) isQuery_Query()       {}
+func (*Query_Prefix) isQuery_Query()      {}
+func (*Query_All) isQuery_Query()         {}
 
 func (m *Query) GetQuery() isQuery_Query {
 	if m != nil {
@@ -235,6 +301,27 @@ func (m *Query) GetDisjunction() *DisjunctionQuery {
 	return nil
 }
 
+func (m *Query) GetField() *FieldQuery {
+	if x, ok := m.GetQuery().(*Query_Field); ok {
+		return x.Field
+	}
+	return nil
+}
+
+func (m *Query) GetPrefix() *PrefixQuery {
+	if x, ok := m.GetQuery().(*Query_Prefix); ok {
+		return x.Prefix
+	}
+	return nil
+}
+
+func (m *Query) GetAll() *AllQuery {
+	if x, ok := m.GetQuery().(*Query_All); ok {
+		return x.All
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Query_OneofMarshaler, _Query_OneofUnmarshaler, _Query_OneofSizer, []interface{}{
@@ -243,6 +330,9 @@ func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Query_Negation)(nil),
 		(*Query_Conjunction)(nil),
 		(*Query_Disjunction)(nil),
+		(*Query_Field)(nil),
+		(*Query_Prefix)(nil),
+		(*Query_All)(nil),
 	}
 }
 
@@ -275,6 +365,21 @@ func _Query_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Disjunction); err != nil {
 			return err
 		}
+	case *Query_Field:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Field); err != nil {
+			return err
+		}
+	case *Query_Prefix:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Prefix); err != nil {
+			return err
+		}
+	case *Query_All:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.All); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Query.Query has unexpected type %T", x)
@@ -325,6 +430,30 @@ func _Query_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		err := b.DecodeMessage(msg)
 		m.Query = &Query_Disjunction{msg}
 		return true, err
+	case 6: // query.field
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FieldQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_Field{msg}
+		return true, err
+	case 7: // query.prefix
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PrefixQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_Prefix{msg}
+		return true, err
+	case 8: // query.all
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(AllQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_All{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -359,6 +488,21 @@ func _Query_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(5<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Query_Field:
+		s := proto.Size(x.Field)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_Prefix:
+		s := proto.Size(x.Prefix)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_All:
+		s := proto.Size(x.All)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -372,6 +516,9 @@ func init() {
 	proto.RegisterType((*NegationQuery)(nil), "query.NegationQuery")
 	proto.RegisterType((*ConjunctionQuery)(nil), "query.ConjunctionQuery")
 	proto.RegisterType((*DisjunctionQuery)(nil), "query.DisjunctionQuery")
+	proto.RegisterType((*FieldQuery)(nil), "query.FieldQuery")
+	proto.RegisterType((*PrefixQuery)(nil), "query.PrefixQuery")
+	proto.RegisterType((*AllQuery)(nil), "query.AllQuery")
 	proto.RegisterType((*Query)(nil), "query.Query")
 }
 func (m *TermQuery) Marshal() (dAtA []byte, err error) {
@@ -522,6 +669,78 @@ func (m *DisjunctionQuery) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *FieldQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FieldQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	return i, nil
+}
+
+func (m *PrefixQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrefixQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if len(m.Prefix) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Prefix)))
+		i += copy(dAtA[i:], m.Prefix)
+	}
+	return i, nil
+}
+
+func (m *AllQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AllQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
 func (m *Query) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -617,6 +836,48 @@ func (m *Query_Disjunction) MarshalTo(dAtA []byte) (int, error) {
 	}
 	return i, nil
 }
+func (m *Query_Field) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Field != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Field.Size()))
+		n8, err := m.Field.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+func (m *Query_Prefix) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Prefix != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Prefix.Size()))
+		n9, err := m.Prefix.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	return i, nil
+}
+func (m *Query_All) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.All != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.All.Size()))
+		n10, err := m.All.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
 func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -688,6 +949,36 @@ func (m *DisjunctionQuery) Size() (n int) {
 	return n
 }
 
+func (m *FieldQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *PrefixQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *AllQuery) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
 func (m *Query) Size() (n int) {
 	var l int
 	_ = l
@@ -742,6 +1033,33 @@ func (m *Query_Disjunction) Size() (n int) {
 	}
 	return n
 }
+func (m *Query_Field) Size() (n int) {
+	var l int
+	_ = l
+	if m.Field != nil {
+		l = m.Field.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Prefix) Size() (n int) {
+	var l int
+	_ = l
+	if m.Prefix != nil {
+		l = m.Prefix.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_All) Size() (n int) {
+	var l int
+	_ = l
+	if m.All != nil {
+		l = m.All.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
 
 func sovQuery(x uint64) (n int) {
 	for {
@@ -1225,7 +1543,7 @@ func (m *DisjunctionQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Query) Unmarshal(dAtA []byte) error {
+func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1248,17 +1566,17 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Query: wiretype end group for non-group")
+			return fmt.Errorf("proto: FieldQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Query: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FieldQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -1268,57 +1586,300 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &TermQuery{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
 			}
-			m.Query = &Query_Term{v}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowQuery
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if msglen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &RegexpQuery{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Query = &Query_Regexp{v}
-			iNdEx = postIndex
-		case 3:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrefixQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrefixQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrefixQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AllQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AllQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AllQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Query) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Query: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Query: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &TermQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Term{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &RegexpQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Regexp{v}
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Negation", wireType)
 			}
@@ -1414,6 +1975,102 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			m.Query = &Query_Disjunction{v}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FieldQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Field{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &PrefixQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Prefix{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field All", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &AllQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_All{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1545,27 +2202,32 @@ func init() {
 }
 
 var fileDescriptorQuery = []byte{
-	// 343 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xbd, 0x4e, 0xc3, 0x30,
-	0x14, 0x85, 0xe3, 0xb6, 0x69, 0xe1, 0xa6, 0x48, 0x95, 0x55, 0x81, 0x59, 0xa2, 0x2a, 0x03, 0x62,
-	0x40, 0xb5, 0xd4, 0x88, 0x85, 0x4e, 0x14, 0x86, 0x4c, 0x48, 0x44, 0x4c, 0x6c, 0x4d, 0x62, 0x82,
-	0x11, 0x76, 0x8a, 0x9b, 0x4a, 0xe5, 0x2d, 0x18, 0x78, 0x28, 0x46, 0x1e, 0x01, 0x95, 0x17, 0x41,
-	0xb1, 0xdd, 0x5f, 0x24, 0x06, 0xa6, 0xe4, 0xfa, 0x9e, 0x4f, 0x3a, 0xf9, 0x62, 0xb8, 0xcc, 0x79,
-	0xf9, 0x38, 0x4b, 0xfa, 0x69, 0x21, 0xa8, 0x08, 0xb3, 0x84, 0x8a, 0x90, 0x4e, 0x55, 0x4a, 0x45,
-	0x28, 0xb9, 0x9c, 0xd3, 0x9c, 0x49, 0xa6, 0xc6, 0x25, 0xcb, 0xe8, 0x44, 0x15, 0x65, 0x41, 0x5f,
-	0x66, 0x4c, 0xbd, 0x4e, 0x12, 0xf3, 0xec, 0xeb, 0x33, 0xec, 0xea, 0x21, 0x38, 0x87, 0xfd, 0x3b,
-	0xa6, 0xc4, 0x6d, 0x35, 0xe0, 0x2e, 0xb8, 0x0f, 0x9c, 0x3d, 0x67, 0x04, 0xf5, 0xd0, 0x69, 0x3b,
-	0x36, 0x03, 0xc6, 0xd0, 0x28, 0x99, 0x12, 0xa4, 0xa6, 0x0f, 0xf5, 0x7b, 0x30, 0x04, 0x2f, 0x66,
-	0x39, 0x9b, 0x4f, 0xfe, 0x02, 0x0f, 0xa1, 0xa9, 0x74, 0xc8, 0xa2, 0x76, 0x0a, 0x42, 0x38, 0xb8,
-	0x61, 0xf9, 0xb8, 0xe4, 0x85, 0x34, 0x78, 0x00, 0xa6, 0x8d, 0xc6, 0xbd, 0x41, 0xbb, 0x6f, 0x8a,
-	0xea, 0x65, 0x6c, 0x8b, 0x5e, 0x40, 0xe7, 0xaa, 0x90, 0x4f, 0x33, 0x99, 0xae, 0xb9, 0x13, 0x68,
-	0x55, 0x4b, 0xce, 0xa6, 0x04, 0xf5, 0xea, 0xbf, 0xc8, 0xe5, 0xb2, 0x62, 0xaf, 0xf9, 0xf4, 0x7f,
-	0xec, 0x7b, 0x0d, 0xdc, 0x25, 0x61, 0x3c, 0x98, 0x92, 0x1d, 0x1b, 0x5f, 0xd9, 0x8b, 0x1c, 0xe3,
-	0x06, 0x9f, 0x6d, 0x7d, 0xb6, 0x37, 0xc0, 0x36, 0xb9, 0x21, 0x2c, 0x72, 0x96, 0x32, 0xf0, 0x00,
-	0xf6, 0xa4, 0x95, 0x41, 0xea, 0x3a, 0xdf, 0xb5, 0xf9, 0x2d, 0x47, 0x91, 0x13, 0xaf, 0x72, 0x78,
-	0x08, 0x5e, 0xba, 0x76, 0x41, 0x1a, 0x1a, 0x3b, 0xb2, 0xd8, 0xae, 0xa5, 0xc8, 0x89, 0x37, 0xd3,
-	0x15, 0x9c, 0xad, 0x65, 0x10, 0x77, 0x0b, 0xde, 0xd5, 0x54, 0xc1, 0x1b, 0xe9, 0x51, 0xcb, 0xfe,
-	0xa9, 0xd1, 0xf1, 0xc7, 0xc2, 0x47, 0x9f, 0x0b, 0x1f, 0x7d, 0x2d, 0x7c, 0xf4, 0xf6, 0xed, 0x3b,
-	0xf7, 0x2d, 0x7b, 0xcb, 0x92, 0xa6, 0xbe, 0x60, 0xe1, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x87,
-	0xea, 0x95, 0x8a, 0xa5, 0x02, 0x00, 0x00,
+	// 419 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x5d, 0x6b, 0x9b, 0x50,
+	0x18, 0xc7, 0x75, 0xc6, 0x98, 0x3d, 0x66, 0x2c, 0x3b, 0x84, 0xcd, 0xdd, 0x48, 0x70, 0x30, 0x36,
+	0x08, 0x11, 0x22, 0xbb, 0x59, 0xae, 0x92, 0x8d, 0xe2, 0x55, 0x69, 0xa5, 0x57, 0xbd, 0x8b, 0x7a,
+	0x62, 0x2d, 0xbe, 0xf5, 0xc4, 0x40, 0xfa, 0x2d, 0x4a, 0x3f, 0x55, 0x2f, 0xfb, 0x11, 0x4a, 0xfa,
+	0x45, 0xca, 0x79, 0x31, 0x6a, 0x0a, 0x29, 0xf4, 0x2a, 0x79, 0x3c, 0xff, 0x1f, 0x3c, 0xfe, 0xfe,
+	0x1e, 0x98, 0x47, 0x71, 0x79, 0xb5, 0xf1, 0x27, 0x41, 0x9e, 0xda, 0xa9, 0x13, 0xfa, 0x76, 0xea,
+	0xd8, 0x6b, 0x12, 0xd8, 0xa9, 0x93, 0xc5, 0xd9, 0xd6, 0x8e, 0x70, 0x86, 0xc9, 0xb2, 0xc4, 0xa1,
+	0x5d, 0x90, 0xbc, 0xcc, 0xed, 0x9b, 0x0d, 0x26, 0xb7, 0x85, 0xcf, 0x7f, 0x27, 0xec, 0x19, 0x52,
+	0xd9, 0x60, 0xfd, 0x81, 0x8f, 0x17, 0x98, 0xa4, 0xe7, 0x74, 0x40, 0x43, 0x50, 0x57, 0x31, 0x4e,
+	0x42, 0x43, 0x1e, 0xc9, 0xbf, 0xfa, 0x1e, 0x1f, 0x10, 0x82, 0x4e, 0x89, 0x49, 0x6a, 0x7c, 0x60,
+	0x0f, 0xd9, 0x7f, 0x6b, 0x06, 0xba, 0x87, 0x23, 0xbc, 0x2d, 0x8e, 0x81, 0x5f, 0xa1, 0x4b, 0x58,
+	0x48, 0xa0, 0x62, 0xb2, 0x1c, 0xf8, 0x74, 0x8a, 0xa3, 0x65, 0x19, 0xe7, 0x19, 0xc7, 0x2d, 0xe0,
+	0xdb, 0x30, 0x5c, 0x9f, 0xf6, 0x27, 0x7c, 0x51, 0x76, 0xe8, 0x89, 0x45, 0xff, 0xc2, 0xe0, 0x5f,
+	0x9e, 0x5d, 0x6f, 0xb2, 0xa0, 0xe6, 0x7e, 0x82, 0x46, 0x0f, 0x63, 0xbc, 0x36, 0xe4, 0x91, 0xf2,
+	0x8a, 0xac, 0x0e, 0x29, 0xfb, 0x3f, 0x5e, 0xbf, 0x8f, 0xb5, 0x00, 0x4e, 0xe8, 0xdb, 0x1c, 0x79,
+	0x51, 0x6a, 0xe3, 0x8c, 0xe0, 0x55, 0xbc, 0x7d, 0xc3, 0x46, 0xc1, 0x42, 0x95, 0x0d, 0x3e, 0x59,
+	0x00, 0xbd, 0x79, 0x92, 0x30, 0xd2, 0xba, 0x57, 0x40, 0xad, 0xd6, 0xe3, 0xd2, 0xb9, 0x91, 0x81,
+	0xd8, 0x6d, 0x5f, 0x95, 0x2b, 0xf1, 0x22, 0xd0, 0xb8, 0xe5, 0x58, 0x9f, 0x22, 0x91, 0x6c, 0xb4,
+	0xe3, 0x4a, 0x95, 0x79, 0x34, 0x85, 0x5e, 0x26, 0xcc, 0x1b, 0x0a, 0xcb, 0x0f, 0x45, 0xbe, 0x55,
+	0x88, 0x2b, 0x79, 0xfb, 0x1c, 0x9a, 0x81, 0x1e, 0xd4, 0xe2, 0x8d, 0x0e, 0xc3, 0xbe, 0x09, 0xec,
+	0xb0, 0x12, 0x57, 0xf2, 0x9a, 0x69, 0x0a, 0x87, 0xb5, 0x79, 0x43, 0x6d, 0xc1, 0x87, 0x9d, 0x50,
+	0xb8, 0x91, 0x46, 0xbf, 0x2b, 0x8f, 0x5d, 0x86, 0x7d, 0x11, 0x58, 0x5d, 0x87, 0x2b, 0x55, 0x72,
+	0xc7, 0x7b, 0xb9, 0x5a, 0x4b, 0x43, 0xa3, 0x16, 0xaa, 0x81, 0x67, 0xd0, 0x0f, 0x50, 0x96, 0x49,
+	0x62, 0xf4, 0x58, 0xf4, 0xb3, 0x88, 0x56, 0x25, 0xb8, 0x92, 0x47, 0x4f, 0x17, 0x9a, 0xf8, 0x28,
+	0x17, 0xdf, 0x1f, 0x76, 0xa6, 0xfc, 0xb8, 0x33, 0xe5, 0xa7, 0x9d, 0x29, 0xdf, 0x3d, 0x9b, 0xd2,
+	0xa5, 0x26, 0x2e, 0x94, 0xdf, 0x65, 0x77, 0xc9, 0x79, 0x19, 0x00, 0x04, 0x2e, 0x63, 0x96, 0x90,
+	0x03, 0x00, 0x00,
 }
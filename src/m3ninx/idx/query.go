@@ -63,6 +63,29 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewFieldQuery returns a new query for finding documents which contain a field,
+// regardless of its value.
+func NewFieldQuery(field []byte) Query {
+	return Query{
+		query: query.NewFieldQuery(field),
+	}
+}
+
+// NewPrefixQuery returns a new query for finding documents whose value for a field
+// starts with a prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewAllQuery returns a new query for finding all documents.
+func NewAllQuery() Query {
+	return Query{
+		query: query.NewAllQuery(),
+	}
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
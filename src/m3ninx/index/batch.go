@@ -42,6 +42,11 @@ type Batch struct {
 	// If true, on the other hand, then any errors encountered indexing a document will cause
 	// the entire batch to fail and none of the documents in the batch will be indexed.
 	AllowPartialUpdates bool
+
+	// WriteTimes optionally holds the time, in unix nanoseconds, each document
+	// in the batch was written at. Segments which record write times use them
+	// to prune terms which cannot match queries for earlier time ranges.
+	WriteTimes []int64
 }
 
 // BatchOption is an option for a Batch.
@@ -72,6 +72,12 @@ func CompileRegex(r []byte) (CompiledRegex, error) {
 	compiledRegex.PrefixBegin = start
 	compiledRegex.PrefixEnd = end
 
+	// Finally, regexps which are unions of literals, e.g. `(foo|bar)`, `(?i)foo` or `foo.*`
+	// can be resolved with term and prefix lookups rather than evaluating the regexp.
+	if literals, ok := regexpLiterals(vellumRe); ok {
+		compiledRegex.Literals = literals
+	}
+
 	return compiledRegex, nil
 }
 
@@ -0,0 +1,193 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxRegexpLiterals is the maximum number of literals a regexp is expanded to
+// before falling back to evaluating the regexp against every term, e.g. a case
+// insensitive literal expands to every combination of the case of its runes.
+const maxRegexpLiterals = 256
+
+// Matches returns whether the literal matches the given term.
+func (l RegexpLiteral) Matches(term []byte) bool {
+	if !l.Prefix {
+		return bytes.Equal(l.Value, term)
+	}
+	if !bytes.HasPrefix(term, l.Value) {
+		return false
+	}
+	return !l.PrefixExcludesNewline || bytes.IndexByte(term[len(l.Value):], '\n') < 0
+}
+
+// regexpLiterals returns the literals the regexp is equivalent to when matched
+// against an entire term, it returns false if the regexp is not equivalent to
+// a union of at most maxRegexpLiterals literals.
+// NB: assumes the input regexp AST is un-anchored.
+func regexpLiterals(ast *syntax.Regexp) ([]RegexpLiteral, bool) {
+	literals, ok := expandRegexpLiterals(ast)
+	if !ok || len(literals) == 0 {
+		return nil, false
+	}
+	return literals, true
+}
+
+func expandRegexpLiterals(ast *syntax.Regexp) ([]RegexpLiteral, bool) {
+	switch ast.Op {
+	case syntax.OpEmptyMatch:
+		return []RegexpLiteral{{Value: []byte{}}}, true
+	case syntax.OpLiteral:
+		return expandLiteralRunes(ast.Rune, ast.Flags&syntax.FoldCase != 0)
+	case syntax.OpCharClass:
+		return expandCharClass(ast.Rune)
+	case syntax.OpCapture:
+		return expandRegexpLiterals(ast.Sub[0])
+	case syntax.OpStar:
+		if literal, ok := anyCharPrefix(ast); ok {
+			return []RegexpLiteral{literal}, true
+		}
+		return nil, false
+	case syntax.OpQuest:
+		literals, ok := expandRegexpLiterals(ast.Sub[0])
+		if !ok || len(literals)+1 > maxRegexpLiterals {
+			return nil, false
+		}
+		return append(literals, RegexpLiteral{Value: []byte{}}), true
+	case syntax.OpAlternate:
+		var literals []RegexpLiteral
+		for _, sub := range ast.Sub {
+			subLiterals, ok := expandRegexpLiterals(sub)
+			if !ok || len(literals)+len(subLiterals) > maxRegexpLiterals {
+				return nil, false
+			}
+			literals = append(literals, subLiterals...)
+		}
+		return literals, true
+	case syntax.OpConcat:
+		literals := []RegexpLiteral{{Value: []byte{}}}
+		for i, sub := range ast.Sub {
+			subLiterals, ok := expandRegexpLiterals(sub)
+			if !ok || len(literals)*len(subLiterals) > maxRegexpLiterals {
+				return nil, false
+			}
+			last := i == len(ast.Sub)-1
+			next := make([]RegexpLiteral, 0, len(literals)*len(subLiterals))
+			for _, literal := range literals {
+				for _, subLiteral := range subLiterals {
+					if subLiteral.Prefix && !last {
+						// Only a trailing prefix can be expressed as a literal.
+						return nil, false
+					}
+					value := make([]byte, 0, len(literal.Value)+len(subLiteral.Value))
+					value = append(value, literal.Value...)
+					value = append(value, subLiteral.Value...)
+					next = append(next, RegexpLiteral{
+						Value:                 value,
+						Prefix:                subLiteral.Prefix,
+						PrefixExcludesNewline: subLiteral.PrefixExcludesNewline,
+					})
+				}
+			}
+			literals = next
+		}
+		return literals, true
+	}
+	return nil, false
+}
+
+// anyCharPrefix returns a prefix literal matching every term if the regexp is
+// a `.*`, i.e. a repetition of any character.
+func anyCharPrefix(ast *syntax.Regexp) (RegexpLiteral, bool) {
+	if len(ast.Sub) != 1 {
+		return RegexpLiteral{}, false
+	}
+	switch ast.Sub[0].Op {
+	case syntax.OpAnyChar:
+		return RegexpLiteral{Value: []byte{}, Prefix: true}, true
+	case syntax.OpAnyCharNotNL:
+		return RegexpLiteral{Value: []byte{}, Prefix: true, PrefixExcludesNewline: true}, true
+	}
+	return RegexpLiteral{}, false
+}
+
+func expandLiteralRunes(runes []rune, foldCase bool) ([]RegexpLiteral, bool) {
+	values := [][]byte{{}}
+	for _, r := range runes {
+		if r == utf8.RuneError {
+			// NB: the regexp matches invalid UTF-8 against the replacement
+			// character, which a byte comparison of terms would not.
+			return nil, false
+		}
+		variants := []rune{r}
+		if foldCase {
+			// SimpleFold iterates the runes equivalent to r under case folding.
+			for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+				variants = append(variants, f)
+			}
+		}
+		if len(values)*len(variants) > maxRegexpLiterals {
+			return nil, false
+		}
+		next := make([][]byte, 0, len(values)*len(variants))
+		for _, value := range values {
+			for _, variant := range variants {
+				next = append(next, appendRune(value, variant))
+			}
+		}
+		values = next
+	}
+
+	literals := make([]RegexpLiteral, 0, len(values))
+	for _, value := range values {
+		literals = append(literals, RegexpLiteral{Value: value})
+	}
+	return literals, true
+}
+
+func expandCharClass(ranges []rune) ([]RegexpLiteral, bool) {
+	var literals []RegexpLiteral
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo <= utf8.RuneError && utf8.RuneError <= hi {
+			return nil, false
+		}
+		if len(literals)+int(hi-lo)+1 > maxRegexpLiterals {
+			return nil, false
+		}
+		for r := lo; r <= hi; r++ {
+			literals = append(literals, RegexpLiteral{Value: appendRune(nil, r)})
+		}
+	}
+	return literals, true
+}
+
+func appendRune(b []byte, r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	result := make([]byte, 0, len(b)+n)
+	result = append(result, b...)
+	return append(result, buf[:n]...)
+}
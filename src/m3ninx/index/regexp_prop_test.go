@@ -74,6 +74,52 @@ func TestRegexpCompilationProperty(t *testing.T) {
 	}
 }
 
+func TestRegexpLiteralsProperty(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	seed := time.Now().UnixNano()
+	parameters.MinSuccessfulTests = 100000
+	parameters.MaxSize = 10
+	parameters.Rng = rand.New(rand.NewSource(seed))
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("Regexp literals match same strings as regexp", prop.ForAll(
+		func(x *inputCase, other string) (bool, error) {
+			compiled, err := CompileRegex([]byte(x.re))
+			if err != nil {
+				return false, fmt.Errorf("unable to compile re [%v]: %v", x.re, err)
+			}
+			if len(compiled.Literals) == 0 {
+				// i.e. the case insensitive literals expand beyond maxRegexpLiterals.
+				return true, nil
+			}
+
+			for _, input := range []string{x.str, other, strings.ToUpper(x.str)} {
+				regexpMatch := compiled.Simple.MatchString(input)
+				literalsMatch := false
+				for _, literal := range compiled.Literals {
+					if literal.Matches([]byte(input)) {
+						literalsMatch = true
+						break
+					}
+				}
+				if regexpMatch != literalsMatch {
+					return false, fmt.Errorf("don't match %v %v %+v %q",
+						regexpMatch, literalsMatch, x, input)
+				}
+			}
+
+			return true, nil
+		},
+		genLiteralsInputCase(),
+		gen.AnyString(),
+	))
+
+	reporter := gopter.NewFormatedReporter(true, 160, os.Stdout)
+	if !properties.Run(reporter) {
+		t.Errorf("failed with initial seed: %d", seed)
+	}
+}
+
 func compileRegexp(x string, t *testing.T) *regexp.Regexp {
 	ast, err := parseRegexp(x)
 	require.NoError(t, err)
@@ -103,6 +149,23 @@ func genInputCase() gopter.Gen {
 	})
 }
 
+func genLiteralsInputCase() gopter.Gen {
+	return genRegexpLiterals(unicode.ASCII_Hex_Digit).Map(
+		func(reString string, params *gopter.GenParameters) *inputCase {
+			val := gen.RegexMatch(reString)(params)
+			strAny, ok := val.Retrieve()
+			if !ok {
+				return nil
+			}
+			return &inputCase{
+				re:  reString,
+				str: strAny.(string),
+			}
+		}).SuchThat(func(ic *inputCase) bool {
+		return ic != nil
+	})
+}
+
 type inputCase struct {
 	re  string
 	str string
@@ -188,3 +251,58 @@ func genRegexpAnyOperands(l *unicode.RangeTable) gopter.Gen {
 		}
 	})
 }
+
+// genRegexpLiterals generates regexps which are unions of, optionally case
+// insensitive, literals and prefixes.
+func genRegexpLiterals(language *unicode.RangeTable) gopter.Gen {
+	return gopter.CombineGens(
+		gen.SliceOfN(3, genRegexpLiteralsAst(language)),
+		gen.OneConstOf(
+			syntax.OpConcat,
+			syntax.OpAlternate),
+		gen.Bool(),
+	).Map(func(vals []interface{}) string {
+		r := &syntax.Regexp{
+			Op:    vals[1].(syntax.Op),
+			Flags: regexpFlags,
+			Sub:   vals[0].([]*syntax.Regexp),
+		}
+		if vals[2].(bool) {
+			// Terminate with a `.*` so the literals are prefixes.
+			r = &syntax.Regexp{
+				Op:    syntax.OpConcat,
+				Flags: regexpFlags,
+				Sub: []*syntax.Regexp{r, {
+					Op:    syntax.OpStar,
+					Flags: regexpFlags,
+					Sub:   []*syntax.Regexp{{Op: syntax.OpAnyCharNotNL, Flags: regexpFlags}},
+				}},
+			}
+		}
+		return r.Simplify().String()
+	})
+}
+
+func genRegexpLiteralsAst(language *unicode.RangeTable) gopter.Gen {
+	return gopter.CombineGens(
+		genRegexpLiteral(language),
+		gen.OneConstOf(
+			syntax.OpLiteral,
+			syntax.OpCapture,
+			syntax.OpQuest),
+		gen.Bool(),
+	).Map(func(vals []interface{}) *syntax.Regexp {
+		r := vals[0].(*syntax.Regexp)
+		if vals[2].(bool) {
+			r.Flags |= syntax.FoldCase
+		}
+		if op := vals[1].(syntax.Op); op != syntax.OpLiteral {
+			r = &syntax.Regexp{
+				Op:    op,
+				Flags: regexpFlags,
+				Sub:   []*syntax.Regexp{r},
+			}
+		}
+		return r
+	})
+}
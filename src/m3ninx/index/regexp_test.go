@@ -295,6 +295,77 @@ func TestEnsureRegexpAnchored(t *testing.T) {
 	}
 }
 
+func TestRegexpLiterals(t *testing.T) {
+	lit := func(v string) RegexpLiteral {
+		return RegexpLiteral{Value: []byte(v)}
+	}
+	prefix := func(v string) RegexpLiteral {
+		return RegexpLiteral{Value: []byte(v), Prefix: true, PrefixExcludesNewline: true}
+	}
+	testCases := []struct {
+		input    string
+		expected []RegexpLiteral
+	}{
+		{input: "abc", expected: []RegexpLiteral{lit("abc")}},
+		{input: "^abc$", expected: []RegexpLiteral{lit("abc")}},
+		{input: "(abc|def)", expected: []RegexpLiteral{lit("abc"), lit("def")}},
+		{input: "(a|b|c)", expected: []RegexpLiteral{lit("a"), lit("b"), lit("c")}},
+		{input: "ab?", expected: []RegexpLiteral{lit("ab"), lit("a")}},
+		{input: "(?i)ab", expected: []RegexpLiteral{lit("AB"), lit("Ab"), lit("aB"), lit("ab")}},
+		{input: "abc.*", expected: []RegexpLiteral{prefix("abc")}},
+		{input: "(abc|def).*", expected: []RegexpLiteral{prefix("abc"), prefix("def")}},
+		{input: "(abc|de.*)", expected: []RegexpLiteral{lit("abc"), prefix("de")}},
+		{input: "(?s)abc.*", expected: []RegexpLiteral{{Value: []byte("abc"), Prefix: true}}},
+		{input: ".*", expected: []RegexpLiteral{prefix("")}},
+		{input: "abc.*def"},
+		{input: "abc.+"},
+		{input: "a[^b]"},
+		{input: "a*"},
+		{input: `a\b`},
+		{input: "(?i)abcdefghijklmnopqrstuvwxyz"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			ast, err := parseRegexp(tc.input)
+			require.NoError(t, err)
+			ast, err = ensureRegexpUnanchored(ast)
+			require.NoError(t, err)
+
+			literals, ok := regexpLiterals(ast)
+			require.Equal(t, len(tc.expected) > 0, ok)
+			require.Equal(t, tc.expected, literals)
+		})
+	}
+}
+
+func TestRegexpLiteralMatches(t *testing.T) {
+	literal := RegexpLiteral{Value: []byte("abc")}
+	require.True(t, literal.Matches([]byte("abc")))
+	require.False(t, literal.Matches([]byte("abcd")))
+
+	literal.Prefix = true
+	require.True(t, literal.Matches([]byte("abc")))
+	require.True(t, literal.Matches([]byte("abcd\ne")))
+	require.False(t, literal.Matches([]byte("ab")))
+
+	literal.PrefixExcludesNewline = true
+	require.True(t, literal.Matches([]byte("abcd")))
+	require.False(t, literal.Matches([]byte("abcd\ne")))
+}
+
+func TestCompileRegexLiterals(t *testing.T) {
+	compiled, err := CompileRegex([]byte("(foo|bar)"))
+	require.NoError(t, err)
+	require.Equal(t, []RegexpLiteral{
+		{Value: []byte("foo")},
+		{Value: []byte("bar")},
+	}, compiled.Literals)
+
+	compiled, err = CompileRegex([]byte("foo.*bar"))
+	require.NoError(t, err)
+	require.Nil(t, compiled.Literals)
+}
+
 type testCase struct {
 	name           string
 	input          string
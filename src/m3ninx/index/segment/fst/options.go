@@ -57,6 +57,13 @@ type Options interface {
 
 	// PostingsListPool returns the postings list pool.
 	PostingsListPool() postings.Pool
+
+	// SetPostingsListCache sets the postings list cache, a nil cache disables
+	// caching of postings lists.
+	SetPostingsListCache(value *PostingsListCache) Options
+
+	// PostingsListCache returns the postings list cache.
+	PostingsListCache() *PostingsListCache
 }
 
 type opts struct {
@@ -64,6 +71,7 @@ type opts struct {
 	bytesSliceArrPool bytes.SliceArrayPool
 	bytesPool         pool.BytesPool
 	postingsPool      postings.Pool
+	postingsCache     *PostingsListCache
 }
 
 // NewOptions returns new options.
@@ -126,3 +134,13 @@ func (o *opts) SetPostingsListPool(v postings.Pool) Options {
 func (o *opts) PostingsListPool() postings.Pool {
 	return o.postingsPool
 }
+
+func (o *opts) SetPostingsListCache(v *PostingsListCache) Options {
+	opts := *o
+	opts.postingsCache = v
+	return &opts
+}
+
+func (o *opts) PostingsListCache() *PostingsListCache {
+	return o.postingsCache
+}
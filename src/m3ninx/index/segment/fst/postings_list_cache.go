@@ -0,0 +1,301 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fst
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	// postingsListEntryOverheadBytes is the estimated fixed cost of each cache
+	// entry, i.e. the key, list element and postings list headers.
+	postingsListEntryOverheadBytes = 128
+)
+
+// PostingsListCacheOptions is a set of options for a PostingsListCache.
+type PostingsListCacheOptions struct {
+	// MaxBytes is the maximum estimated size of all cached postings lists,
+	// once exceeded the least recently used entries are evicted.
+	MaxBytes int64
+
+	// InstrumentOptions is the instrument options used to emit cache metrics.
+	InstrumentOptions instrument.Options
+}
+
+type patternType int
+
+const (
+	patternTypeTerm patternType = iota
+	patternTypeRegexp
+)
+
+func (t patternType) String() string {
+	switch t {
+	case patternTypeTerm:
+		return "term"
+	case patternTypeRegexp:
+		return "regexp"
+	}
+	return "unknown"
+}
+
+type postingsListCacheKey struct {
+	segmentID   uint64
+	field       string
+	pattern     string
+	patternType patternType
+}
+
+type postingsListCacheEntry struct {
+	key   postingsListCacheKey
+	pl    postings.List
+	bytes int64
+}
+
+// PostingsListCache is a LRU cache of the postings lists retrieved by fst
+// segments, keyed by segment, field, pattern and pattern type. The cache is
+// safe to share between segments, entries for a segment are purged when the
+// segment is closed.
+type PostingsListCache struct {
+	sync.Mutex
+
+	maxBytes int64
+	bytes    int64
+	lru      *list.List
+	entries  map[postingsListCacheKey]*list.Element
+	metrics  postingsListCacheMetrics
+}
+
+// NewPostingsListCache returns a new PostingsListCache.
+func NewPostingsListCache(opts PostingsListCacheOptions) *PostingsListCache {
+	iopts := opts.InstrumentOptions
+	if iopts == nil {
+		iopts = instrument.NewOptions()
+	}
+	return &PostingsListCache{
+		maxBytes: opts.MaxBytes,
+		lru:      list.New(),
+		entries:  make(map[postingsListCacheKey]*list.Element),
+		metrics: newPostingsListCacheMetrics(
+			iopts.MetricsScope().SubScope("postings-list-cache")),
+	}
+}
+
+// GetTerm returns the cached postings list for the term query, if any.
+func (c *PostingsListCache) GetTerm(
+	segmentID uint64,
+	field, term []byte,
+) (postings.List, bool) {
+	return c.get(newPostingsListCacheKey(segmentID, field, term, patternTypeTerm))
+}
+
+// PutTerm caches the postings list for the term query.
+func (c *PostingsListCache) PutTerm(
+	segmentID uint64,
+	field, term []byte,
+	pl postings.List,
+) {
+	c.put(newPostingsListCacheKey(segmentID, field, term, patternTypeTerm), pl)
+}
+
+// GetRegexp returns the cached postings list for the regexp query, if any.
+func (c *PostingsListCache) GetRegexp(
+	segmentID uint64,
+	field []byte,
+	pattern string,
+) (postings.List, bool) {
+	return c.get(postingsListCacheKey{
+		segmentID:   segmentID,
+		field:       string(field),
+		pattern:     pattern,
+		patternType: patternTypeRegexp,
+	})
+}
+
+// PutRegexp caches the postings list for the regexp query.
+func (c *PostingsListCache) PutRegexp(
+	segmentID uint64,
+	field []byte,
+	pattern string,
+	pl postings.List,
+) {
+	c.put(postingsListCacheKey{
+		segmentID:   segmentID,
+		field:       string(field),
+		pattern:     pattern,
+		patternType: patternTypeRegexp,
+	}, pl)
+}
+
+// PurgeSegment removes all cached postings lists for the segment.
+func (c *PostingsListCache) PurgeSegment(segmentID uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	// NB: segments are closed rarely relative to queries so a scan is preferred
+	// over maintaining a secondary index keyed by segment.
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*postingsListCacheEntry)
+		if entry.key.segmentID == segmentID {
+			c.removeWithLock(elem)
+			c.metrics.purges.Inc(1)
+		}
+		elem = next
+	}
+	c.updateGaugesWithLock()
+}
+
+// Len returns the number of cached postings lists.
+func (c *PostingsListCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.lru.Len()
+}
+
+// Bytes returns the estimated size of the cached postings lists.
+func (c *PostingsListCache) Bytes() int64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.bytes
+}
+
+func (c *PostingsListCache) get(key postingsListCacheKey) (postings.List, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.metrics.misses(key.patternType).Inc(1)
+		return nil, false
+	}
+
+	c.metrics.hits(key.patternType).Inc(1)
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*postingsListCacheEntry).pl, true
+}
+
+func (c *PostingsListCache) put(key postingsListCacheKey, pl postings.List) {
+	bytes := estimatePostingsListBytes(key, pl)
+	if bytes > c.maxBytes {
+		// NB: never cache entries that would evict the entire cache.
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeWithLock(elem)
+	}
+
+	c.entries[key] = c.lru.PushFront(&postingsListCacheEntry{
+		key:   key,
+		pl:    pl,
+		bytes: bytes,
+	})
+	c.bytes += bytes
+
+	for c.bytes > c.maxBytes {
+		c.removeWithLock(c.lru.Back())
+		c.metrics.evictions.Inc(1)
+	}
+	c.updateGaugesWithLock()
+}
+
+func (c *PostingsListCache) removeWithLock(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*postingsListCacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.bytes
+}
+
+func (c *PostingsListCache) updateGaugesWithLock() {
+	c.metrics.entries.Update(float64(c.lru.Len()))
+	c.metrics.bytes.Update(float64(c.bytes))
+}
+
+func newPostingsListCacheKey(
+	segmentID uint64,
+	field, pattern []byte,
+	patternType patternType,
+) postingsListCacheKey {
+	return postingsListCacheKey{
+		segmentID:   segmentID,
+		field:       string(field),
+		pattern:     string(pattern),
+		patternType: patternType,
+	}
+}
+
+// estimatePostingsListBytes estimates the memory used by a cache entry, roaring
+// array containers use two bytes per postings ID and bitmap containers are
+// denser still so this is an upper bound for the postings list itself.
+func estimatePostingsListBytes(key postingsListCacheKey, pl postings.List) int64 {
+	return postingsListEntryOverheadBytes +
+		int64(len(key.field)+len(key.pattern)) +
+		2*int64(pl.Len())
+}
+
+type postingsListCacheMetrics struct {
+	termHits     tally.Counter
+	termMisses   tally.Counter
+	regexpHits   tally.Counter
+	regexpMisses tally.Counter
+	evictions    tally.Counter
+	purges       tally.Counter
+	entries      tally.Gauge
+	bytes        tally.Gauge
+}
+
+func newPostingsListCacheMetrics(scope tally.Scope) postingsListCacheMetrics {
+	termScope := scope.Tagged(map[string]string{"type": patternTypeTerm.String()})
+	regexpScope := scope.Tagged(map[string]string{"type": patternTypeRegexp.String()})
+	return postingsListCacheMetrics{
+		termHits:     termScope.Counter("hits"),
+		termMisses:   termScope.Counter("misses"),
+		regexpHits:   regexpScope.Counter("hits"),
+		regexpMisses: regexpScope.Counter("misses"),
+		evictions:    scope.Counter("evictions"),
+		purges:       scope.Counter("purges"),
+		entries:      scope.Gauge("entries"),
+		bytes:        scope.Gauge("bytes"),
+	}
+}
+
+func (m postingsListCacheMetrics) hits(t patternType) tally.Counter {
+	if t == patternTypeRegexp {
+		return m.regexpHits
+	}
+	return m.termHits
+}
+
+func (m postingsListCacheMetrics) misses(t patternType) tally.Counter {
+	if t == patternTypeRegexp {
+		return m.regexpMisses
+	}
+	return m.termMisses
+}
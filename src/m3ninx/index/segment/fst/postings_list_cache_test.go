@@ -0,0 +1,144 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fst
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newTestPostingsList(t *testing.T, ids ...postings.ID) postings.List {
+	pl := roaring.NewPostingsList()
+	for _, id := range ids {
+		require.NoError(t, pl.Insert(id))
+	}
+	return pl
+}
+
+func newTestPostingsListCache(maxBytes int64) (*PostingsListCache, tally.TestScope) {
+	scope := tally.NewTestScope("", nil)
+	cache := NewPostingsListCache(PostingsListCacheOptions{
+		MaxBytes:          maxBytes,
+		InstrumentOptions: instrument.NewOptions().SetMetricsScope(scope),
+	})
+	return cache, scope
+}
+
+func TestPostingsListCacheGetPut(t *testing.T) {
+	cache, scope := newTestPostingsListCache(1 << 20)
+
+	_, ok := cache.GetTerm(1, []byte("foo"), []byte("bar"))
+	require.False(t, ok)
+
+	termPl := newTestPostingsList(t, 1, 2, 3)
+	cache.PutTerm(1, []byte("foo"), []byte("bar"), termPl)
+	regexpPl := newTestPostingsList(t, 4)
+	cache.PutRegexp(1, []byte("foo"), "^bar$", regexpPl)
+	require.Equal(t, 2, cache.Len())
+
+	pl, ok := cache.GetTerm(1, []byte("foo"), []byte("bar"))
+	require.True(t, ok)
+	require.True(t, termPl.Equal(pl))
+
+	// Term and regexp entries with the same pattern must not collide.
+	pl, ok = cache.GetRegexp(1, []byte("foo"), "bar")
+	require.False(t, ok)
+	pl, ok = cache.GetRegexp(1, []byte("foo"), "^bar$")
+	require.True(t, ok)
+	require.True(t, regexpPl.Equal(pl))
+
+	// Entries are keyed by segment.
+	_, ok = cache.GetTerm(2, []byte("foo"), []byte("bar"))
+	require.False(t, ok)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["postings-list-cache.hits+type=term"].Value())
+	require.Equal(t, int64(2), counters["postings-list-cache.misses+type=term"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.hits+type=regexp"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.misses+type=regexp"].Value())
+}
+
+func TestPostingsListCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var (
+		pl        = newTestPostingsList(t, 1, 2, 3)
+		entrySize = estimatePostingsListBytes(postingsListCacheKey{
+			field:   "foo",
+			pattern: "a",
+		}, pl)
+	)
+	cache, scope := newTestPostingsListCache(2 * entrySize)
+
+	cache.PutTerm(1, []byte("foo"), []byte("a"), pl)
+	cache.PutTerm(1, []byte("foo"), []byte("b"), pl)
+	require.Equal(t, 2*entrySize, cache.Bytes())
+
+	// Touch "a" so that "b" is the least recently used entry.
+	_, ok := cache.GetTerm(1, []byte("foo"), []byte("a"))
+	require.True(t, ok)
+
+	cache.PutTerm(1, []byte("foo"), []byte("c"), pl)
+	require.Equal(t, 2, cache.Len())
+	require.Equal(t, 2*entrySize, cache.Bytes())
+
+	_, ok = cache.GetTerm(1, []byte("foo"), []byte("a"))
+	require.True(t, ok)
+	_, ok = cache.GetTerm(1, []byte("foo"), []byte("b"))
+	require.False(t, ok)
+	_, ok = cache.GetTerm(1, []byte("foo"), []byte("c"))
+	require.True(t, ok)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["postings-list-cache.evictions+"].Value())
+}
+
+func TestPostingsListCacheSkipsEntriesLargerThanCache(t *testing.T) {
+	cache, _ := newTestPostingsListCache(1)
+	cache.PutTerm(1, []byte("foo"), []byte("bar"), newTestPostingsList(t, 1))
+	require.Equal(t, 0, cache.Len())
+	require.Equal(t, int64(0), cache.Bytes())
+}
+
+func TestPostingsListCachePurgeSegment(t *testing.T) {
+	cache, scope := newTestPostingsListCache(1 << 20)
+
+	pl := newTestPostingsList(t, 1)
+	cache.PutTerm(1, []byte("foo"), []byte("bar"), pl)
+	cache.PutRegexp(1, []byte("foo"), "^b.*$", pl)
+	cache.PutTerm(2, []byte("foo"), []byte("bar"), pl)
+
+	cache.PurgeSegment(1)
+	require.Equal(t, 1, cache.Len())
+	_, ok := cache.GetTerm(1, []byte("foo"), []byte("bar"))
+	require.False(t, ok)
+	_, ok = cache.GetTerm(2, []byte("foo"), []byte("bar"))
+	require.True(t, ok)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(2), counters["postings-list-cache.purges+"].Value())
+	gauges := scope.Snapshot().Gauges()
+	require.Equal(t, float64(1), gauges["postings-list-cache.entries+"].Value())
+}
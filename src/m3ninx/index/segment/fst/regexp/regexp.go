@@ -68,7 +68,8 @@ func LiteralPrefix(s *syntax.Regexp) string {
 		s = s.Sub[0]
 	}
 
-	if s.Op == syntax.OpLiteral {
+	// NB: a case insensitive literal matches terms which do not begin with its runes.
+	if s.Op == syntax.OpLiteral && s.Flags&syntax.FoldCase == 0 {
 		return string(s.Rune)
 	}
 
@@ -48,6 +48,8 @@ func TestLiteralPrefix(t *testing.T) {
 		{`^hello`, ""},
 		{`^`, ""},
 		{`$`, ""},
+		{`(?i)hello`, ""},
+		{`(?i)hello.*`, ""},
 	}
 
 	for i, test := range tests {
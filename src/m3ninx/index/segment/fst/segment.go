@@ -24,7 +24,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sync"
+	"sync/atomic"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/generated/proto/fswriter"
@@ -41,6 +43,14 @@ import (
 	"github.com/couchbase/vellum"
 )
 
+// lastSegmentID is used to assign each segment a unique ID, segment IDs are
+// used to key cached postings lists.
+var lastSegmentID uint64
+
+// unboundedWriteTime is used by readers which do not prune terms by the time
+// their documents were written at.
+const unboundedWriteTime = math.MaxInt64
+
 var (
 	errReaderClosed            = errors.New("segment is closed")
 	errReaderNilRegexp         = errors.New("nil regexp provided")
@@ -50,6 +60,7 @@ var (
 	errPostingsDataUnset       = errors.New("postings data bytes are not set")
 	errFSTTermsDataUnset       = errors.New("fst terms data bytes are not set")
 	errFSTFieldsDataUnset      = errors.New("fst fields data bytes are not set")
+	errPostingsWriteTimeUnset  = errors.New("postings write time is not set")
 )
 
 // SegmentData represent the collection of required parameters to construct a Segment.
@@ -128,20 +139,24 @@ func NewSegment(data SegmentData, opts Options) (Segment, error) {
 	docsDataReader := docs.NewDataReader(data.DocsData)
 
 	return &fsSegment{
+		id:              atomic.AddUint64(&lastSegmentID, 1),
 		fieldsFST:       fieldsFST,
 		docsDataReader:  docsDataReader,
 		docsIndexReader: docsIndexReader,
 
-		data:           data,
-		opts:           opts,
-		numDocs:        metadata.NumDocs,
-		startInclusive: startInclusive,
-		endExclusive:   endExclusive,
+		data:              data,
+		opts:              opts,
+		numDocs:           metadata.NumDocs,
+		termWriteTimes:    metadata.TermWriteTimes,
+		earliestWriteTime: metadata.EarliestWriteTime,
+		startInclusive:    startInclusive,
+		endExclusive:      endExclusive,
 	}, nil
 }
 
 type fsSegment struct {
 	sync.RWMutex
+	id              uint64
 	closed          bool
 	fieldsFST       *vellum.FST
 	docsDataReader  *docs.DataReader
@@ -149,11 +164,18 @@ type fsSegment struct {
 	data            SegmentData
 	opts            Options
 
-	numDocs        int64
-	startInclusive postings.ID
-	endExclusive   postings.ID
+	numDocs           int64
+	termWriteTimes    bool
+	earliestWriteTime int64
+	startInclusive    postings.ID
+	endExclusive      postings.ID
 }
 
+var (
+	_ sgmt.WriteTimes           = &fsSegment{}
+	_ sgmt.WrittenBeforeSegment = &fsSegment{}
+)
+
 func (r *fsSegment) Size() int64 {
 	r.RLock()
 	defer r.RUnlock()
@@ -197,10 +219,67 @@ func (r *fsSegment) Reader() (index.Reader, error) {
 		return nil, errReaderClosed
 	}
 	return &fsSegmentReader{
-		fsSegment: r,
+		fsSegment:     r,
+		writtenBefore: unboundedWriteTime,
 	}, nil
 }
 
+func (r *fsSegment) ReaderWrittenBefore(t int64) (index.Reader, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return &fsSegmentReader{
+		fsSegment:     r,
+		writtenBefore: t,
+	}, nil
+}
+
+func (r *fsSegment) EarliestWriteTime() int64 {
+	if !r.termWriteTimes {
+		// i.e. the segment was written without write times so they are unknown.
+		return 0
+	}
+	return r.earliestWriteTime
+}
+
+func (r *fsSegment) TermEarliestWriteTime(field, term []byte) (int64, bool, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return 0, false, errReaderClosed
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !exists {
+		return 0, false, nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	postingsOffset, exists, err := termsFST.Get(term)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !exists {
+		return 0, false, nil
+	}
+
+	_, writeTime, err := r.retrievePostingsPayloadWithRLock(postingsOffset)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return writeTime, true, fstCloser.Close()
+}
+
 func (r *fsSegment) Close() error {
 	r.Lock()
 	defer r.Unlock()
@@ -208,6 +287,9 @@ func (r *fsSegment) Close() error {
 		return errReaderClosed
 	}
 	r.closed = true
+	if cache := r.opts.PostingsListCache(); cache != nil {
+		cache.PurgeSegment(r.id)
+	}
 	var multiErr xerrors.MultiError
 	multiErr = multiErr.Add(r.fieldsFST.Close())
 	if r.data.Closer != nil {
@@ -254,12 +336,26 @@ func (r *fsSegment) Terms(field []byte) (sgmt.TermsIterator, error) {
 }
 
 func (r *fsSegment) MatchTerm(field []byte, term []byte) (postings.List, error) {
+	return r.matchTerm(field, term, unboundedWriteTime)
+}
+
+func (r *fsSegment) matchTerm(
+	field, term []byte,
+	writtenBefore int64,
+) (postings.List, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
 		return nil, errReaderClosed
 	}
 
+	cache := r.opts.PostingsListCache()
+	if cache != nil {
+		if pl, ok := cache.GetTerm(r.id, field, term); ok {
+			return pl, nil
+		}
+	}
+
 	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
 	if err != nil {
 		return nil, err
@@ -283,7 +379,7 @@ func (r *fsSegment) MatchTerm(field []byte, term []byte) (postings.List, error)
 		return r.opts.PostingsListPool().Get(), nil
 	}
 
-	pl, err := r.retrievePostingsListWithRLock(postingsOffset)
+	pl, matched, err := r.retrievePostingsListWithRLock(postingsOffset, writtenBefore)
 	if err != nil {
 		return nil, err
 	}
@@ -292,10 +388,28 @@ func (r *fsSegment) MatchTerm(field []byte, term []byte) (postings.List, error)
 		return nil, err
 	}
 
+	if !matched {
+		// i.e. the term's documents were all written after the cut off.
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	// NB: only postings lists which have not been pruned are cached.
+	if cache != nil && writtenBefore == unboundedWriteTime {
+		cache.PutTerm(r.id, field, term, pl)
+	}
+
 	return pl, nil
 }
 
 func (r *fsSegment) MatchRegexp(field []byte, compiled index.CompiledRegex) (postings.List, error) {
+	return r.matchRegexp(field, compiled, unboundedWriteTime)
+}
+
+func (r *fsSegment) matchRegexp(
+	field []byte,
+	compiled index.CompiledRegex,
+	writtenBefore int64,
+) (postings.List, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
@@ -307,6 +421,43 @@ func (r *fsSegment) MatchRegexp(field []byte, compiled index.CompiledRegex) (pos
 		return nil, errReaderNilRegexp
 	}
 
+	// NB: the anchored regexp used by the simple segment is used to key the
+	// cache as it is equivalent to the FST regexp.
+	cache := r.opts.PostingsListCache()
+	if compiled.Simple == nil {
+		cache = nil
+	}
+	if cache != nil {
+		if pl, ok := cache.GetRegexp(r.id, field, compiled.Simple.String()); ok {
+			return pl, nil
+		}
+	}
+
+	var (
+		pl  postings.List
+		err error
+	)
+	if len(compiled.Literals) > 0 {
+		pl, err = r.matchLiteralsWithRLock(field, compiled.Literals, writtenBefore)
+	} else {
+		pl, err = r.matchRegexpWithRLock(field, compiled, writtenBefore)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil && writtenBefore == unboundedWriteTime {
+		cache.PutRegexp(r.id, field, compiled.Simple.String(), pl)
+	}
+
+	return pl, nil
+}
+
+func (r *fsSegment) matchRegexpWithRLock(
+	field []byte,
+	compiled index.CompiledRegex,
+	writtenBefore int64,
+) (postings.List, error) {
 	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
 	if err != nil {
 		return nil, err
@@ -319,7 +470,7 @@ func (r *fsSegment) MatchRegexp(field []byte, compiled index.CompiledRegex) (pos
 
 	var (
 		fstCloser     = x.NewSafeCloser(termsFST)
-		iter, iterErr = termsFST.Search(re, compiled.PrefixBegin, compiled.PrefixEnd)
+		iter, iterErr = termsFST.Search(compiled.FST, compiled.PrefixBegin, compiled.PrefixEnd)
 		iterCloser    = x.NewSafeCloser(iter)
 		// NB(prateek): way quicker to union the PLs together at the end, rathen than one at a time.
 		pls []postings.List // TODO: pool this slice allocation
@@ -339,11 +490,13 @@ func (r *fsSegment) MatchRegexp(field []byte, compiled index.CompiledRegex) (pos
 		}
 
 		_, postingsOffset := iter.Current()
-		nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
+		nextPl, matched, err := r.retrievePostingsListWithRLock(postingsOffset, writtenBefore)
 		if err != nil {
 			return nil, err
 		}
-		pls = append(pls, nextPl)
+		if matched {
+			pls = append(pls, nextPl)
+		}
 		iterErr = iter.Next()
 	}
 
@@ -363,6 +516,180 @@ func (r *fsSegment) MatchRegexp(field []byte, compiled index.CompiledRegex) (pos
 	return pl, nil
 }
 
+// matchLiteralsWithRLock returns the union of the postings lists of all terms in
+// the given field which are matched by any of the literals.
+func (r *fsSegment) matchLiteralsWithRLock(
+	field []byte,
+	literals []index.RegexpLiteral,
+	writtenBefore int64,
+) (postings.List, error) {
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	var (
+		fstCloser = x.NewSafeCloser(termsFST)
+		pls       = make([]postings.List, 0, len(literals))
+	)
+	defer fstCloser.Close()
+
+	for _, literal := range literals {
+		if literal.Prefix {
+			pls, err = r.appendRangePostingsListsWithRLock(pls, termsFST,
+				literal.Value, prefixEnd(literal.Value), literal.Matches, writtenBefore)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		postingsOffset, exists, err := termsFST.Get(literal.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		pl, matched, err := r.retrievePostingsListWithRLock(postingsOffset, writtenBefore)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			pls = append(pls, pl)
+		}
+	}
+
+	pl, err := roaring.Union(pls)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+func (r *fsSegment) MatchField(field []byte) (postings.List, error) {
+	return r.matchField(field, unboundedWriteTime)
+}
+
+func (r *fsSegment) matchField(field []byte, writtenBefore int64) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	// NB: a nil start and end key iterates over every term in the field.
+	return r.matchRangeWithRLock(field, nil, nil, writtenBefore)
+}
+
+func (r *fsSegment) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	return r.matchPrefix(field, prefix, unboundedWriteTime)
+}
+
+func (r *fsSegment) matchPrefix(field, prefix []byte, writtenBefore int64) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	return r.matchRangeWithRLock(field, prefix, prefixEnd(prefix), writtenBefore)
+}
+
+// matchRangeWithRLock returns the union of the postings lists of all terms in the
+// given field which fall in the range [startInclusive, endExclusive).
+func (r *fsSegment) matchRangeWithRLock(
+	field, startInclusive, endExclusive []byte,
+	writtenBefore int64,
+) (postings.List, error) {
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	pls, err := r.appendRangePostingsListsWithRLock(nil, termsFST,
+		startInclusive, endExclusive, nil, writtenBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	pl, err := roaring.Union(pls)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+// appendRangePostingsListsWithRLock appends the postings lists of all terms in the
+// range [startInclusive, endExclusive) which are matched by matchFn, all terms in
+// the range are matched if matchFn is nil. Terms whose documents were all written
+// at or after writtenBefore are skipped.
+func (r *fsSegment) appendRangePostingsListsWithRLock(
+	pls []postings.List,
+	termsFST *vellum.FST,
+	startInclusive, endExclusive []byte,
+	matchFn func(term []byte) bool,
+	writtenBefore int64,
+) ([]postings.List, error) {
+	var (
+		iter, iterErr = termsFST.Iterator(startInclusive, endExclusive)
+		iterCloser    = x.NewSafeCloser(iter)
+	)
+	defer iterCloser.Close()
+
+	for {
+		if iterErr == vellum.ErrIteratorDone {
+			break
+		}
+
+		if iterErr != nil {
+			return nil, iterErr
+		}
+
+		term, postingsOffset := iter.Current()
+		if matchFn == nil || matchFn(term) {
+			nextPl, matched, err := r.retrievePostingsListWithRLock(postingsOffset, writtenBefore)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				pls = append(pls, nextPl)
+			}
+		}
+		iterErr = iter.Next()
+	}
+
+	if err := iterCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pls, nil
+}
+
 func (r *fsSegment) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -414,13 +741,57 @@ func (r *fsSegment) AllDocs() (index.IDDocIterator, error) {
 	return index.NewIDDocIterator(r, pi), nil
 }
 
-func (r *fsSegment) retrievePostingsListWithRLock(postingsOffset uint64) (postings.List, error) {
-	postingsBytes, err := r.retrieveBytesWithRLock(r.data.PostingsData, postingsOffset)
+// retrievePostingsListWithRLock returns the postings list at the given offset, and
+// whether the earliest write time of its documents is before writtenBefore. The
+// postings list is not unmarshalled if it is not.
+func (r *fsSegment) retrievePostingsListWithRLock(
+	postingsOffset uint64,
+	writtenBefore int64,
+) (postings.List, bool, error) {
+	postingsBytes, writeTime, err := r.retrievePostingsPayloadWithRLock(postingsOffset)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if writtenBefore != unboundedWriteTime && writeTime >= writtenBefore {
+		return nil, false, nil
+	}
+
+	pl, err := pilosa.Unmarshal(postingsBytes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return pl, true, nil
+}
+
+// retrievePostingsPayloadWithRLock returns the serialized postings list at the given
+// offset and the earliest write time of its documents, which is zero if the segment
+// was written without write times.
+func (r *fsSegment) retrievePostingsPayloadWithRLock(postingsOffset uint64) ([]byte, int64, error) {
+	const sizeofUint64 = 8
+	payload, err := r.retrieveBytesWithRLock(r.data.PostingsData, postingsOffset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to retrieve postings data: %v", err)
+	}
+
+	if !r.termWriteTimes {
+		return payload, 0, nil
+	}
+
+	// The earliest write time is stored in the last 8 bytes of the payload.
+	writeTimeStart := len(payload) - sizeofUint64
+	if writeTimeStart < 0 {
+		return nil, 0, errPostingsWriteTimeUnset
+	}
+
+	d := encoding.NewDecoder(payload[writeTimeStart:])
+	writeTime, err := d.Uint64()
 	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve postings data: %v", err)
+		return nil, 0, fmt.Errorf("error while decoding postings write time: %v", err)
 	}
 
-	return pilosa.Unmarshal(postingsBytes)
+	return payload[:writeTimeStart], int64(writeTime), nil
 }
 
 func (r *fsSegment) retrieveTermsFSTWithRLock(field []byte) (*vellum.FST, bool, error) {
@@ -499,7 +870,8 @@ type fsSegmentReader struct {
 	sync.RWMutex
 	closed bool
 
-	fsSegment *fsSegment
+	fsSegment     *fsSegment
+	writtenBefore int64
 }
 
 var _ index.Reader = &fsSegmentReader{}
@@ -510,7 +882,7 @@ func (sr *fsSegmentReader) MatchTerm(field []byte, term []byte) (postings.List,
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.MatchTerm(field, term)
+	return sr.fsSegment.matchTerm(field, term, sr.writtenBefore)
 }
 
 func (sr *fsSegmentReader) MatchRegexp(field []byte, compiled index.CompiledRegex) (postings.List, error) {
@@ -519,7 +891,25 @@ func (sr *fsSegmentReader) MatchRegexp(field []byte, compiled index.CompiledRege
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.MatchRegexp(field, compiled)
+	return sr.fsSegment.matchRegexp(field, compiled, sr.writtenBefore)
+}
+
+func (sr *fsSegmentReader) MatchField(field []byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchField(field, sr.writtenBefore)
+}
+
+func (sr *fsSegmentReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchPrefix(field, prefix, sr.writtenBefore)
 }
 
 func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
@@ -567,3 +957,17 @@ func (sr *fsSegmentReader) Close() error {
 	sr.closed = true
 	return nil
 }
+
+// prefixEnd returns the smallest key which is greater than every key starting
+// with the given prefix, or nil if there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			end := make([]byte, i+1)
+			copy(end, prefix)
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
@@ -34,7 +34,7 @@ const (
 	MajorVersion = 1
 
 	// MinorVersion is the current MinorVersion.
-	MinorVersion = 0
+	MinorVersion = 1
 )
 
 // Segment represents a FST segment.
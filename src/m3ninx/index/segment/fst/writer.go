@@ -56,6 +56,8 @@ type writer struct {
 	docDataWriter   *docs.DataWriter
 	docIndexWriter  *docs.IndexWriter
 
+	writeTimes          sgmt.WriteTimes
+	postingsBuf         []byte
 	metadata            []byte
 	docsDataFileWritten bool
 	postingsFileWritten bool
@@ -82,6 +84,7 @@ func NewWriter() Writer {
 func (w *writer) clear() {
 	w.seg = nil
 	w.segReader = nil
+	w.writeTimes = nil
 
 	w.fstWriter = newFSTWriter()
 	w.intEncoder.Reset()
@@ -108,6 +111,15 @@ func (w *writer) Reset(s sgmt.MutableSegment) error {
 	numDocs := s.Size()
 	metadata := defaultV1Metadata()
 	metadata.NumDocs = numDocs
+
+	// NB: segments which record write times have the earliest write time of
+	// each term's documents appended to the term's postings list.
+	writeTimes, ok := s.(sgmt.WriteTimes)
+	if ok {
+		metadata.TermWriteTimes = true
+		metadata.EarliestWriteTime = writeTimes.EarliestWriteTime()
+	}
+
 	metadataBytes, err := metadata.Marshal()
 	if err != nil {
 		return err
@@ -121,6 +133,7 @@ func (w *writer) Reset(s sgmt.MutableSegment) error {
 	w.metadata = metadataBytes
 	w.seg = s
 	w.segReader = reader
+	w.writeTimes = writeTimes
 	return nil
 }
 
@@ -214,7 +227,12 @@ func (w *writer) WritePostingsOffsets(iow io.Writer) error {
 				return err
 			}
 
-			n, err := w.writePayloadAndSizeAndMagicNumber(iow, postingsBytes)
+			payload, err := w.postingsPayload(f, t, postingsBytes)
+			if err != nil {
+				return err
+			}
+
+			n, err := w.writePayloadAndSizeAndMagicNumber(iow, payload)
 			if err != nil {
 				return err
 			}
@@ -374,6 +392,26 @@ func (w *writer) WriteFSTFields(iow io.Writer) error {
 	return err
 }
 
+// postingsPayload returns the payload written for the postings list of the given
+// term, i.e. the serialized postings list followed by the earliest write time of
+// the term's documents if the segment records write times.
+func (w *writer) postingsPayload(field, term, postingsBytes []byte) ([]byte, error) {
+	if w.writeTimes == nil {
+		return postingsBytes, nil
+	}
+
+	writeTime, _, err := w.writeTimes.TermEarliestWriteTime(field, term)
+	if err != nil {
+		return nil, err
+	}
+
+	w.intEncoder.Reset()
+	w.intEncoder.PutUint64(uint64(writeTime))
+	w.postingsBuf = append(w.postingsBuf[:0], postingsBytes...)
+	w.postingsBuf = append(w.postingsBuf, w.intEncoder.Bytes()...)
+	return w.postingsBuf, nil
+}
+
 // given a payload []byte, and io.Writer; this method writes the following data out to the writer
 // | payload - len(payload) bytes | 8 bytes for uint64 (size of payload) | 8 bytes for `magicNumber` |
 func (w *writer) writePayloadAndSizeAndMagicNumber(iow io.Writer, payload []byte) (uint64, error) {
@@ -256,6 +256,41 @@ func TestPostingsListEqualForMatchTerm(t *testing.T) {
 	}
 }
 
+func TestPostingsListCachedForMatchTermAndRegexp(t *testing.T) {
+	cache := NewPostingsListCache(PostingsListCacheOptions{MaxBytes: 1 << 20})
+	memSeg := newTestMemSegment(t)
+	for _, d := range fewTestDocuments {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+	fstSeg := newFSTSegment(t, memSeg, testOptions.SetPostingsListCache(cache))
+	reader, err := fstSeg.Reader()
+	require.NoError(t, err)
+
+	memReader, err := memSeg.Reader()
+	require.NoError(t, err)
+	field, term := []byte("fruit"), []byte("apple")
+	expected, err := memReader.MatchTerm(field, term)
+	require.NoError(t, err)
+	compiled, err := index.CompileRegex([]byte("app.*"))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		pl, err := reader.MatchTerm(field, term)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(pl))
+
+		pl, err = reader.MatchRegexp(field, compiled)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(pl))
+	}
+	require.Equal(t, 2, cache.Len())
+
+	require.NoError(t, reader.Close())
+	require.NoError(t, fstSeg.Close())
+	require.Equal(t, 0, cache.Len())
+}
+
 func TestPostingsListContainsID(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -299,6 +334,132 @@ func TestPostingsListRegexAll(t *testing.T) {
 	}
 }
 
+func TestPostingsListEqualForMatchRegexpLiterals(t *testing.T) {
+	patterns := []string{
+		"apple",
+		"(apple|banana|kiwi)",
+		"(?i)APPLE",
+		"(?i)(red|YELLOW)",
+		"pine.*",
+		"(?i)PINE.*",
+		"(red|yel.*)",
+		"[a-c]pple",
+		"node_.*",
+		".*",
+	}
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			fieldsIter, err := memSeg.Fields()
+			require.NoError(t, err)
+			fields := toSlice(t, fieldsIter)
+			for _, pattern := range patterns {
+				compiled, err := index.CompileRegex([]byte(pattern))
+				require.NoError(t, err)
+				require.NotEmpty(t, compiled.Literals, pattern)
+
+				// Resolve the same regexp without the literals fast path.
+				withoutLiterals := compiled
+				withoutLiterals.Literals = nil
+
+				for _, f := range fields {
+					memPl, err := memReader.MatchRegexp(f, compiled)
+					require.NoError(t, err)
+					fstPl, err := fstReader.MatchRegexp(f, compiled)
+					require.NoError(t, err)
+					memRegexpPl, err := memReader.MatchRegexp(f, withoutLiterals)
+					require.NoError(t, err)
+					fstRegexpPl, err := fstReader.MatchRegexp(f, withoutLiterals)
+					require.NoError(t, err)
+
+					msg := fmt.Sprintf("%s:%s", string(f), pattern)
+					require.True(t, memPl.Equal(fstPl), msg)
+					require.True(t, memPl.Equal(memRegexpPl), msg)
+					require.True(t, fstPl.Equal(fstRegexpPl), msg)
+				}
+			}
+		})
+	}
+}
+
+func TestPostingsListEqualForMatchField(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			fieldsIter, err := memSeg.Fields()
+			require.NoError(t, err)
+			fields := toSlice(t, fieldsIter)
+			for _, f := range append(fields, []byte("unknown")) {
+				memPl, err := memReader.MatchField(f)
+				require.NoError(t, err)
+				fstPl, err := fstReader.MatchField(f)
+				require.NoError(t, err)
+				require.True(t, memPl.Equal(fstPl),
+					fmt.Sprintf("%s - [%v] != [%v]", string(f), pprintIter(memPl), pprintIter(fstPl)))
+			}
+		})
+	}
+}
+
+func TestPostingsListEqualForMatchPrefix(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			fieldsIter, err := memSeg.Fields()
+			require.NoError(t, err)
+			fields := toSlice(t, fieldsIter)
+			for _, f := range fields {
+				termsIter, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				terms := toSlice(t, termsIter)
+
+				for _, term := range terms {
+					for i := 0; i <= len(term); i++ {
+						prefix := term[:i]
+						memPl, err := memReader.MatchPrefix(f, prefix)
+						require.NoError(t, err)
+						fstPl, err := fstReader.MatchPrefix(f, prefix)
+						require.NoError(t, err)
+						require.True(t, memPl.Equal(fstPl),
+							fmt.Sprintf("%s:%s - [%v] != [%v]", string(f), string(prefix), pprintIter(memPl), pprintIter(fstPl)))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := []struct {
+		prefix   []byte
+		expected []byte
+	}{
+		{prefix: nil, expected: nil},
+		{prefix: []byte("abc"), expected: []byte("abd")},
+		{prefix: []byte{'a', 0xff}, expected: []byte("b")},
+		{prefix: []byte{0xff, 0xff}, expected: nil},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, prefixEnd(test.prefix))
+	}
+}
+
 func TestSegmentDocs(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -378,6 +539,116 @@ func TestPostingsListReaderLifecycle(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSegmentWriteTimes(t *testing.T) {
+	memSeg := newTestMemSegmentWithWriteTimes(t, fewTestDocuments, []int64{30, 20, 10})
+	fstSeg := newFSTSegment(t, memSeg, testOptions)
+
+	writeTimes, ok := fstSeg.(sgmt.WriteTimes)
+	require.True(t, ok)
+	require.Equal(t, int64(10), writeTimes.EarliestWriteTime())
+
+	tests := []struct {
+		field, term string
+		expected    int64
+		found       bool
+	}{
+		{field: "fruit", term: "banana", expected: 30, found: true},
+		{field: "fruit", term: "apple", expected: 20, found: true},
+		{field: "color", term: "yellow", expected: 10, found: true},
+		{field: "fruit", term: "cherry"},
+		{field: "shape", term: "round"},
+	}
+	for _, test := range tests {
+		writeTime, found, err := writeTimes.TermEarliestWriteTime([]byte(test.field), []byte(test.term))
+		require.NoError(t, err)
+		require.Equal(t, test.found, found, test.term)
+		require.Equal(t, test.expected, writeTime, test.term)
+	}
+
+	require.NoError(t, fstSeg.Close())
+}
+
+func TestReaderWrittenBefore(t *testing.T) {
+	cache := NewPostingsListCache(PostingsListCacheOptions{MaxBytes: 1 << 20})
+	memSeg := newTestMemSegmentWithWriteTimes(t, fewTestDocuments, []int64{30, 20, 10})
+	fstSeg := newFSTSegment(t, memSeg, testOptions.SetPostingsListCache(cache))
+
+	writtenBefore, ok := fstSeg.(sgmt.WrittenBeforeSegment)
+	require.True(t, ok)
+	reader, err := writtenBefore.ReaderWrittenBefore(20)
+	require.NoError(t, err)
+
+	// Only the terms of the document written at 10 are matched, however their
+	// postings lists are not pruned.
+	assertPostingsList := func(expected []postings.ID, pl postings.List) {
+		require.Equal(t, len(expected), pl.Len())
+		for _, id := range expected {
+			require.True(t, pl.Contains(id))
+		}
+	}
+
+	pl, err := reader.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	assertPostingsList(nil, pl)
+
+	pl, err = reader.MatchTerm([]byte("color"), []byte("yellow"))
+	require.NoError(t, err)
+	assertPostingsList([]postings.ID{0, 2}, pl)
+
+	pl, err = reader.MatchField([]byte("fruit"))
+	require.NoError(t, err)
+	assertPostingsList([]postings.ID{2}, pl)
+
+	pl, err = reader.MatchPrefix([]byte("fruit"), []byte("b"))
+	require.NoError(t, err)
+	assertPostingsList(nil, pl)
+
+	for _, re := range []string{"app.*", "b.*n.*", ".*apple"} {
+		compiled, err := index.CompileRegex([]byte(re))
+		require.NoError(t, err)
+		pl, err = reader.MatchRegexp([]byte("fruit"), compiled)
+		require.NoError(t, err)
+		if re == ".*apple" {
+			assertPostingsList([]postings.ID{2}, pl)
+		} else {
+			assertPostingsList(nil, pl)
+		}
+	}
+
+	// NB: MatchAll is not pruned.
+	all, err := reader.MatchAll()
+	require.NoError(t, err)
+	assertPostingsList([]postings.ID{0, 1, 2}, all)
+
+	// Pruned postings lists are not cached.
+	require.Equal(t, 0, cache.Len())
+	require.NoError(t, reader.Close())
+
+	reader, err = fstSeg.Reader()
+	require.NoError(t, err)
+	pl, err = reader.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	assertPostingsList([]postings.ID{1}, pl)
+	require.Equal(t, 1, cache.Len())
+
+	require.NoError(t, reader.Close())
+	require.NoError(t, fstSeg.Close())
+}
+
+func newTestMemSegmentWithWriteTimes(
+	t *testing.T,
+	docs []doc.Document,
+	writeTimes []int64,
+) sgmt.MutableSegment {
+	s := newTestMemSegment(t)
+	batchDocs := make([]doc.Document, len(docs))
+	copy(batchDocs, docs)
+	b := index.NewBatch(batchDocs)
+	b.WriteTimes = writeTimes
+	require.NoError(t, s.InsertBatch(b))
+	return s
+}
+
 func newTestSegments(t *testing.T, docs []doc.Document) (memSeg sgmt.MutableSegment, fstSeg sgmt.Segment) {
 	s := newTestMemSegment(t)
 	for _, d := range docs {
@@ -21,9 +21,11 @@
 package mem
 
 import (
+	"bytes"
 	"regexp"
 	"sync"
 
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
 
@@ -100,14 +102,86 @@ func (m *concurrentPostingsMap) Get(key []byte) (postings.List, bool) {
 // GetRegex returns the union of the postings lists whose keys match the
 // provided regexp.
 func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool) {
+	// TODO: Evaluate if performing a prefix match would speed up the common case.
+	return m.getMatching(re.Match)
+}
+
+// GetLiterals returns the union of the postings lists whose keys are matched by
+// any of the provided regexp literals.
+func (m *concurrentPostingsMap) GetLiterals(
+	literals []index.RegexpLiteral,
+) (postings.List, bool) {
+	var (
+		pl       postings.MutableList
+		prefixes []index.RegexpLiteral
+	)
+
+	m.RLock()
+	for _, literal := range literals {
+		if literal.Prefix {
+			prefixes = append(prefixes, literal)
+			continue
+		}
+		p, ok := m.postingsMap.Get(literal.Value)
+		if !ok {
+			continue
+		}
+		if pl == nil {
+			pl = p.Clone()
+		} else {
+			pl.Union(p)
+		}
+	}
+	m.RUnlock()
+
+	if len(prefixes) > 0 {
+		prefixPl, ok := m.getMatching(func(key []byte) bool {
+			for _, prefix := range prefixes {
+				if prefix.Matches(key) {
+					return true
+				}
+			}
+			return false
+		})
+		if ok {
+			if pl == nil {
+				return prefixPl, true
+			}
+			pl.Union(prefixPl)
+		}
+	}
+
+	if pl == nil {
+		return nil, false
+	}
+	return pl, true
+}
+
+// GetPrefix returns the union of the postings lists whose keys start with the
+// provided prefix.
+func (m *concurrentPostingsMap) GetPrefix(prefix []byte) (postings.List, bool) {
+	return m.getMatching(func(key []byte) bool {
+		return bytes.HasPrefix(key, prefix)
+	})
+}
+
+// GetAll returns the union of all the postings lists in the map.
+func (m *concurrentPostingsMap) GetAll() (postings.List, bool) {
+	return m.getMatching(func([]byte) bool {
+		return true
+	})
+}
+
+func (m *concurrentPostingsMap) getMatching(
+	matchFn func(key []byte) bool,
+) (postings.List, bool) {
 	var pl postings.MutableList
 
 	m.RLock()
 	for _, mapEntry := range m.postingsMap.Iter() {
 		// TODO: Evaluate lock contention caused by holding on to the read lock while
 		// evaluating this predicate.
-		// TODO: Evaluate if performing a prefix match would speed up the common case.
-		if re.Match(mapEntry.Key()) {
+		if matchFn(mapEntry.Key()) {
 			if pl == nil {
 				pl = mapEntry.Value().Clone()
 			} else {
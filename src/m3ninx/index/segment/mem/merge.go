@@ -23,13 +23,14 @@ package mem
 import (
 	"io"
 
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/x"
 )
 
 // Merge merges the segments `srcs` into `target`.
-func Merge(target sgmt.MutableSegment, srcs ...sgmt.MutableSegment) error {
+func Merge(target sgmt.MutableSegment, srcs ...sgmt.Segment) error {
 	safeClosers := []io.Closer{}
 	defer func() {
 		for _, c := range safeClosers {
@@ -58,7 +59,26 @@ func Merge(target sgmt.MutableSegment, srcs ...sgmt.MutableSegment) error {
 		// iterate over all known docs
 		for dIter.Next() {
 			d := dIter.Current()
-			_, err := target.Insert(d)
+
+			// only the first copy of a document is inserted, skip the lookup
+			// of its write time for any later copies
+			exists, err := target.ContainsID(d.ID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			b := index.NewBatch([]doc.Document{d})
+			t, ok, err := earliestWriteTime(srcs, d.ID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				b.WriteTimes = []int64{t}
+			}
+			err = target.InsertBatch(b)
 			if err == nil || err == index.ErrDuplicateID {
 				continue
 			}
@@ -79,3 +99,36 @@ func Merge(target sgmt.MutableSegment, srcs ...sgmt.MutableSegment) error {
 	// all good
 	return nil
 }
+
+// earliestWriteTime returns the earliest write time of the document with the
+// given ID across all of the srcs, as the document is only inserted once. The
+// write time is not known if a src which does not record write times contains
+// the document.
+func earliestWriteTime(srcs []sgmt.Segment, id []byte) (int64, bool, error) {
+	var (
+		earliest int64
+		found    bool
+	)
+	for _, src := range srcs {
+		writeTimes, ok := src.(sgmt.WriteTimes)
+		if !ok {
+			contains, err := src.ContainsID(id)
+			if err != nil {
+				return 0, false, err
+			}
+			if contains {
+				return 0, false, nil
+			}
+			continue
+		}
+
+		t, ok, err := writeTimes.TermEarliestWriteTime(doc.IDReservedFieldName, id)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok && (!found || t < earliest) {
+			earliest, found = t, true
+		}
+	}
+	return earliest, found, nil
+}
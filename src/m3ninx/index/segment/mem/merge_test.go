@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
+	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 
 	"github.com/stretchr/testify/require"
@@ -103,6 +104,75 @@ func TestMemSegmentMerge(t *testing.T) {
 	require.NoError(t, reader.Close())
 }
 
+func TestMemSegmentMergeWriteTimes(t *testing.T) {
+	d := doc.Document{
+		ID: []byte("abc"),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("banana"),
+			},
+		},
+	}
+
+	opts := NewOptions()
+	m1, err := NewSegment(postings.ID(0), opts)
+	require.NoError(t, err)
+	b := index.NewBatch([]doc.Document{d})
+	b.WriteTimes = []int64{42}
+	require.NoError(t, m1.InsertBatch(b))
+
+	m2, err := NewSegment(postings.ID(0), opts)
+	require.NoError(t, err)
+	require.NoError(t, Merge(m2, m1))
+
+	writeTimes, ok := m2.(sgmt.WriteTimes)
+	require.True(t, ok)
+	require.Equal(t, int64(42), writeTimes.EarliestWriteTime())
+
+	earliest, ok, err := writeTimes.TermEarliestWriteTime([]byte("fruit"), []byte("banana"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(42), earliest)
+}
+
+func TestMemSegmentMergeDuplicateIDsKeepEarliestWriteTime(t *testing.T) {
+	d := doc.Document{
+		ID: []byte("abc"),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("banana"),
+			},
+		},
+	}
+
+	opts := NewOptions()
+	m1, err := NewSegment(postings.ID(0), opts)
+	require.NoError(t, err)
+	b := index.NewBatch([]doc.Document{d})
+	b.WriteTimes = []int64{42}
+	require.NoError(t, m1.InsertBatch(b))
+
+	m2, err := NewSegment(postings.ID(0), opts)
+	require.NoError(t, err)
+	b = index.NewBatch([]doc.Document{d})
+	b.WriteTimes = []int64{24}
+	require.NoError(t, m2.InsertBatch(b))
+
+	m3, err := NewSegment(postings.ID(0), opts)
+	require.NoError(t, err)
+	require.NoError(t, Merge(m3, m1, m2))
+	require.Equal(t, int64(1), m3.Size())
+
+	writeTimes, ok := m3.(sgmt.WriteTimes)
+	require.True(t, ok)
+	earliest, ok, err := writeTimes.TermEarliestWriteTime(doc.IDReservedFieldName, d.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(24), earliest)
+}
+
 func assertReaderHasDoc(t *testing.T, r index.Reader, d doc.Document) {
 	iter, err := r.AllDocs()
 	require.NoError(t, err)
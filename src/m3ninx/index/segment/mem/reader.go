@@ -88,9 +88,37 @@ func (r *reader) MatchRegexp(field []byte, compiled index.CompiledRegex) (postin
 		return nil, errReaderNilRegex
 	}
 
+	if len(compiled.Literals) > 0 {
+		return r.segment.matchLiterals(field, compiled.Literals)
+	}
+
 	return r.segment.matchRegexp(field, compileRE)
 }
 
+func (r *reader) MatchField(field []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// NB: as with MatchTerm, IDs greater than the reader's limit are only filtered
+	// out when the documents are retrieved through a call to Docs.
+	return r.segment.matchField(field)
+}
+
+func (r *reader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// NB: as with MatchTerm, IDs greater than the reader's limit are only filtered
+	// out when the documents are retrieved through a call to Docs.
+	return r.segment.matchPrefix(field, prefix)
+}
+
 func (r *reader) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -22,8 +22,10 @@ package mem
 
 import (
 	"errors"
+	"math"
 	re "regexp"
 	"sync"
+	"sync/atomic"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
@@ -39,6 +41,11 @@ var (
 
 // nolint: maligned
 type segment struct {
+	// earliestWriteTime is the earliest write time of the documents in the
+	// segment. It must be accessed atomically, and is the first field in the
+	// struct to ensure it is 64-bit aligned.
+	earliestWriteTime int64
+
 	offset    int
 	plPool    postings.Pool
 	newUUIDFn util.NewUUIDFn
@@ -49,10 +56,13 @@ type segment struct {
 		sealed bool
 	}
 
-	// Mapping of postings ID to document.
+	// Mapping of postings ID to document, and to the time the document was
+	// written at. Write times are accessed atomically as readers may load the
+	// write time of a document while it is being stored.
 	docs struct {
 		sync.RWMutex
-		data []doc.Document
+		data       []doc.Document
+		writeTimes []int64
 	}
 
 	// Mapping of term to postings list.
@@ -75,9 +85,12 @@ func NewSegment(offset postings.ID, opts Options) (sgmt.MutableSegment, error) {
 		newUUIDFn: opts.NewUUIDFn(),
 		termsDict: newTermsDict(opts),
 		readerID:  postings.NewAtomicID(offset),
+
+		earliestWriteTime: math.MaxInt64,
 	}
 
 	s.docs.data = make([]doc.Document, opts.InitialCapacity())
+	s.docs.writeTimes = make([]int64, opts.InitialCapacity())
 
 	s.writer.idSet = newIDsMap(256)
 	s.writer.nextID = offset
@@ -129,7 +142,7 @@ func (s *segment) Insert(d doc.Document) ([]byte, error) {
 		// Update the document in case we generated a UUID for it.
 		d = b.Docs[0]
 
-		s.insertDocWithLocks(d)
+		s.insertDocWithLocks(d, 0)
 		s.readerID.Inc()
 
 		s.writer.Unlock()
@@ -155,14 +168,18 @@ func (s *segment) InsertBatch(b index.Batch) error {
 		}
 
 		numInserts := uint32(0)
-		for _, d := range b.Docs {
+		for i, d := range b.Docs {
 			// NB(prateek): we override a document to have no ID when
 			// it doesn't need to be inserted.
 			if !d.HasID() {
 				continue
 			}
+			var writeTime int64
+			if i < len(b.WriteTimes) {
+				writeTime = b.WriteTimes[i]
+			}
 			numInserts++
-			s.insertDocWithLocks(d)
+			s.insertDocWithLocks(d, writeTime)
 		}
 		s.readerID.Add(numInserts)
 
@@ -239,11 +256,19 @@ func (s *segment) prepareDocsWithLocks(b index.Batch) error {
 
 // insertDocWithLocks inserts a document into the index. It must be called with the
 // state and writer locks.
-func (s *segment) insertDocWithLocks(d doc.Document) {
+func (s *segment) insertDocWithLocks(d doc.Document, writeTime int64) {
 	nextID := s.writer.nextID
+	// NB: the document, and its write time, are stored before its postings are
+	// indexed so that readers matching the postings see its write time.
+	s.storeDocWithStateLock(nextID, d, writeTime)
 	s.indexDocWithStateLock(nextID, d)
-	s.storeDocWithStateLock(nextID, d)
 	s.writer.nextID++
+
+	// NB: the earliest write time is only updated by writers which hold the
+	// writer lock so a load followed by a store cannot race.
+	if writeTime < atomic.LoadInt64(&s.earliestWriteTime) {
+		atomic.StoreInt64(&s.earliestWriteTime, writeTime)
+	}
 }
 
 // indexDocWithStateLock indexes the fields of a document in the segment's terms
@@ -261,7 +286,7 @@ func (s *segment) indexDocWithStateLock(id postings.ID, d doc.Document) error {
 
 // storeDocWithStateLock stores a documents into the segment's mapping of postings
 // IDs to documents. It must be called with the segment's state lock.
-func (s *segment) storeDocWithStateLock(id postings.ID, d doc.Document) {
+func (s *segment) storeDocWithStateLock(id postings.ID, d doc.Document, writeTime int64) {
 	idx := int(id) - s.offset
 
 	// Can return early if we have sufficient capacity.
@@ -273,6 +298,7 @@ func (s *segment) storeDocWithStateLock(id postings.ID, d doc.Document) {
 			// we're guaranteed to never have conflicts with docID (it's monotonically increasing),
 			// and have checked `i.docs.data` is large enough.
 			s.docs.data[idx] = d
+			atomic.StoreInt64(&s.docs.writeTimes[idx], writeTime)
 			s.docs.RUnlock()
 			return
 		}
@@ -287,6 +313,7 @@ func (s *segment) storeDocWithStateLock(id postings.ID, d doc.Document) {
 		// The slice has already been expanded since we released the lock.
 		if size > idx {
 			s.docs.data[idx] = d
+			atomic.StoreInt64(&s.docs.writeTimes[idx], writeTime)
 			s.docs.Unlock()
 			return
 		}
@@ -295,6 +322,11 @@ func (s *segment) storeDocWithStateLock(id postings.ID, d doc.Document) {
 		copy(data, s.docs.data)
 		s.docs.data = data
 		s.docs.data[idx] = d
+
+		writeTimes := make([]int64, len(data))
+		copy(writeTimes, s.docs.writeTimes)
+		s.docs.writeTimes = writeTimes
+		s.docs.writeTimes[idx] = writeTime
 		s.docs.Unlock()
 	}
 }
@@ -333,6 +365,39 @@ func (s *segment) matchRegexp(field []byte, compiled *re.Regexp) (postings.List,
 	return s.termsDict.MatchRegexp(field, compiled), nil
 }
 
+func (s *segment) matchLiterals(
+	field []byte,
+	literals []index.RegexpLiteral,
+) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchLiterals(field, literals), nil
+}
+
+func (s *segment) matchField(field []byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchField(field), nil
+}
+
+func (s *segment) matchPrefix(field, prefix []byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchPrefix(field, prefix), nil
+}
+
 func (s *segment) getDoc(id postings.ID) (doc.Document, error) {
 	s.state.RLock()
 	defer s.state.RUnlock()
@@ -353,6 +418,50 @@ func (s *segment) getDoc(id postings.ID) (doc.Document, error) {
 	return d, nil
 }
 
+func (s *segment) EarliestWriteTime() int64 {
+	return atomic.LoadInt64(&s.earliestWriteTime)
+}
+
+func (s *segment) TermEarliestWriteTime(field, term []byte) (int64, bool, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return 0, false, sgmt.ErrClosed
+	}
+
+	pl := s.termsDict.MatchTerm(field, term)
+	if pl.IsEmpty() {
+		return 0, false, nil
+	}
+
+	iter := pl.Iterator()
+	earliest := int64(math.MaxInt64)
+	s.docs.RLock()
+	for iter.Next() {
+		idx := int(iter.Current()) - s.offset
+		if idx >= len(s.docs.writeTimes) {
+			// NB: should never happen as write times are stored before the
+			// postings of a document are indexed, conservatively treat its
+			// write time as unknown.
+			earliest = 0
+			continue
+		}
+		if t := atomic.LoadInt64(&s.docs.writeTimes[idx]); t < earliest {
+			earliest = t
+		}
+	}
+	s.docs.RUnlock()
+
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return 0, false, err
+	}
+	if err := iter.Close(); err != nil {
+		return 0, false, err
+	}
+	return earliest, true, nil
+}
+
 func (s *segment) Close() error {
 	s.state.Lock()
 	defer s.state.Unlock()
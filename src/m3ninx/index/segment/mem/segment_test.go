@@ -21,12 +21,16 @@
 package mem
 
 import (
+	"math"
 	re "regexp"
+	"sync"
 	"testing"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
+	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -724,6 +728,106 @@ func TestSegmentIsSealed(t *testing.T) {
 	require.False(t, segment.IsSealed())
 }
 
+func TestSegmentWriteTimes(t *testing.T) {
+	segment, err := NewSegment(0, testOptions)
+	require.NoError(t, err)
+
+	writeTimes, ok := segment.(sgmt.WriteTimes)
+	require.True(t, ok)
+	require.Equal(t, int64(math.MaxInt64), writeTimes.EarliestWriteTime())
+
+	docs := make([]doc.Document, len(testDocuments))
+	copy(docs, testDocuments)
+	b := index.NewBatch(docs)
+	b.WriteTimes = []int64{30, 20, 10}
+	require.NoError(t, segment.InsertBatch(b))
+	require.Equal(t, int64(10), writeTimes.EarliestWriteTime())
+
+	earliest, ok, err := writeTimes.TermEarliestWriteTime([]byte("color"), []byte("yellow"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(10), earliest)
+
+	earliest, ok, err = writeTimes.TermEarliestWriteTime([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(20), earliest)
+
+	_, ok, err = writeTimes.TermEarliestWriteTime([]byte("fruit"), []byte("cherry"))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Documents inserted without a write time have an unknown write time.
+	_, err = segment.Insert(doc.Document{
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("apple"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), writeTimes.EarliestWriteTime())
+
+	earliest, ok, err = writeTimes.TermEarliestWriteTime([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(0), earliest)
+}
+
+func TestSegmentWriteTimesConcurrentInsertAndRead(t *testing.T) {
+	segment, err := NewSegment(0, testOptions)
+	require.NoError(t, err)
+
+	writeTimes, ok := segment.(sgmt.WriteTimes)
+	require.True(t, ok)
+
+	var (
+		numDocs = 1000
+		wg      sync.WaitGroup
+		doneCh  = make(chan struct{})
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-doneCh:
+				return
+			default:
+			}
+
+			// Write times are stored before postings are indexed so a
+			// matched document always has its write time.
+			earliest, ok, err := writeTimes.TermEarliestWriteTime(
+				[]byte("fruit"), []byte("apple"))
+			assert.NoError(t, err)
+			if ok {
+				assert.Equal(t, int64(42), earliest)
+			}
+		}
+	}()
+
+	for i := 0; i < numDocs; i++ {
+		b := index.NewBatch([]doc.Document{
+			doc.Document{
+				Fields: []doc.Field{
+					doc.Field{
+						Name:  []byte("fruit"),
+						Value: []byte("apple"),
+					},
+				},
+			},
+		})
+		b.WriteTimes = []int64{42}
+		require.NoError(t, segment.InsertBatch(b))
+	}
+
+	close(doneCh)
+	wg.Wait()
+	require.Equal(t, int64(numDocs), segment.Size())
+}
+
 func TestSegmentFields(t *testing.T) {
 	segment, err := NewSegment(0, testOptions)
 	require.NoError(t, err)
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
@@ -116,6 +117,51 @@ func (d *termsDict) MatchRegexp(
 	return pl
 }
 
+func (d *termsDict) MatchLiterals(
+	field []byte,
+	literals []index.RegexpLiteral,
+) postings.List {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	pl, ok := postingsMap.GetLiterals(literals)
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	return pl
+}
+
+func (d *termsDict) MatchField(field []byte) postings.List {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	pl, ok := postingsMap.GetAll()
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	return pl
+}
+
+func (d *termsDict) MatchPrefix(field, prefix []byte) postings.List {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	pl, ok := postingsMap.GetPrefix(prefix)
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	return pl
+}
+
 func (d *termsDict) getOrAddName(name []byte) *concurrentPostingsMap {
 	// Cheap read lock to see if it already exists.
 	d.fields.RLock()
@@ -24,6 +24,7 @@ import (
 	re "regexp"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
@@ -45,6 +46,18 @@ type termsDictionary interface {
 	// given egular expression.
 	MatchRegexp(field []byte, compiled *re.Regexp) postings.List
 
+	// MatchLiterals returns the postings list corresponding to documents whose value
+	// for the given field is matched by any of the given regexp literals.
+	MatchLiterals(field []byte, literals []index.RegexpLiteral) postings.List
+
+	// MatchField returns the postings list corresponding to documents which contain
+	// the given field.
+	MatchField(field []byte) postings.List
+
+	// MatchPrefix returns the postings list corresponding to documents whose value
+	// for the given field starts with the given prefix.
+	MatchPrefix(field, prefix []byte) postings.List
+
 	// Fields returns the known fields.
 	Fields() sgmt.FieldsIterator
 
@@ -65,6 +78,17 @@ type ReadableSegment interface {
 	// matchRegexp returns the postings list of documents which match the given regular expression.
 	matchRegexp(field []byte, compiled *re.Regexp) (postings.List, error)
 
+	// matchLiterals returns the postings list of documents whose value for the given
+	// field is matched by any of the given regexp literals.
+	matchLiterals(field []byte, literals []index.RegexpLiteral) (postings.List, error)
+
+	// matchField returns the postings list of documents which contain the given field.
+	matchField(field []byte) (postings.List, error)
+
+	// matchPrefix returns the postings list of documents whose value for the given
+	// field starts with the given prefix.
+	matchPrefix(field, prefix []byte) (postings.List, error)
+
 	// getDoc returns the document associated with the given ID.
 	getDoc(id postings.ID) (doc.Document, error)
 }
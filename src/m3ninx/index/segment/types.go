@@ -54,6 +54,29 @@ type Segment interface {
 	Close() error
 }
 
+// WriteTimes is implemented by segments which record the time, in unix
+// nanoseconds, each of their documents was first written at. A write time of
+// zero means the time the document was written at is not known.
+type WriteTimes interface {
+	// EarliestWriteTime returns the earliest write time of the documents in
+	// the segment, or math.MaxInt64 if the segment contains no documents.
+	EarliestWriteTime() int64
+
+	// TermEarliestWriteTime returns the earliest write time of the documents
+	// containing the given term, and whether the segment contains the term.
+	TermEarliestWriteTime(field, term []byte) (int64, bool, error)
+}
+
+// WrittenBeforeSegment is implemented by segments which can prune the terms
+// they match using the time their documents were first written at.
+type WrittenBeforeSegment interface {
+	// ReaderWrittenBefore returns a point-in-time accessor to search the segment
+	// which does not match terms whose documents were all written at or after
+	// the given time, in unix nanoseconds. NB: MatchAll is not pruned so queries
+	// containing negations must not be executed using the reader.
+	ReaderWrittenBefore(t int64) (index.Reader, error)
+}
+
 // OrderedBytesIterator iterates over a collection of []bytes in lexicographical order.
 type OrderedBytesIterator interface {
 	// Next returns a bool indicating if there are any more elements.
@@ -69,6 +69,14 @@ type Readable interface {
 	// regular expression.
 	MatchRegexp(field []byte, c CompiledRegex) (postings.List, error)
 
+	// MatchField returns a postings list over all documents which contain the given
+	// field, regardless of its value.
+	MatchField(field []byte) (postings.List, error)
+
+	// MatchPrefix returns a postings list over all documents whose value for the
+	// given field starts with the given prefix.
+	MatchPrefix(field, prefix []byte) (postings.List, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.MutableList, error)
 
@@ -87,6 +95,23 @@ type CompiledRegex struct {
 	FST         *vregex.Regexp
 	PrefixBegin []byte
 	PrefixEnd   []byte
+
+	// Literals is set when the regexp is equivalent to a union of literal terms
+	// and term prefixes, in which case segments resolve the regexp with term and
+	// prefix lookups rather than evaluating it against every term.
+	Literals []RegexpLiteral
+}
+
+// RegexpLiteral is a literal term, or term prefix, matched by a regexp.
+type RegexpLiteral struct {
+	// Value is the literal term, or the prefix of the terms matched.
+	Value []byte
+	// Prefix is true if terms beginning with Value are matched, rather
+	// than only the term equal to Value.
+	Prefix bool
+	// PrefixExcludesNewline is true if terms which contain a newline after
+	// the prefix are not matched, i.e. the prefix is followed by `.*`.
+	PrefixExcludesNewline bool
 }
 
 // DocRetriever returns the document associated with a postings ID. It returns
@@ -28,6 +28,22 @@ import (
 	"github.com/m3db/m3/src/m3ninx/x"
 )
 
+type corruptSegmentError struct {
+	err error
+}
+
+func (e corruptSegmentError) Error() string {
+	return e.err.Error()
+}
+
+// IsCorruptSegmentError returns whether an error returned by NewSegment was
+// caused by the contents of the fileset being invalid rather than a failure
+// to access the files.
+func IsCorruptSegmentError(err error) bool {
+	_, ok := err.(corruptSegmentError)
+	return ok
+}
+
 // NewSegment returns a new fst.Segment backed by the provided fileset.
 // NB: this method takes ownership of the provided fileset files, in case of both errors,
 // and success. i.e. users are not expected to call Close on any of the provided fileset.Files()
@@ -42,7 +58,7 @@ func NewSegment(fileset IndexSegmentFileSet, opts fst.Options) (fst.Segment, err
 	}()
 
 	if t := fileset.SegmentType(); t != FSTIndexSegmentType {
-		return nil, fmt.Errorf("unknown segment type: %s", t)
+		return nil, corruptSegmentError{err: fmt.Errorf("unknown segment type: %s", t)}
 	}
 
 	sd, err := filesetToSegmentData(fileset)
@@ -53,7 +69,7 @@ func NewSegment(fileset IndexSegmentFileSet, opts fst.Options) (fst.Segment, err
 
 	segment, err := fst.NewSegment(sd, opts)
 	if err != nil {
-		return nil, err
+		return nil, corruptSegmentError{err: err}
 	}
 
 	// indicate we don't need to close files in the defer above.
@@ -102,7 +118,9 @@ func filesetToSegmentData(fileset IndexSegmentFileSet) (fst.SegmentData, error)
 				return sd, err
 			}
 		default:
-			return sd, fmt.Errorf("unknown fileType: %s provided", fileType)
+			return sd, corruptSegmentError{
+				err: fmt.Errorf("unknown fileType: %s provided", fileType),
+			}
 		}
 	}
 
@@ -40,6 +40,7 @@ func TestReaderValidateType(t *testing.T) {
 	fset.EXPECT().Files().Return(nil).AnyTimes()
 	_, err := NewSegment(fset, nil)
 	require.Error(t, err)
+	require.True(t, IsCorruptSegmentError(err))
 }
 
 func TestReaderValidateErrorCloses(t *testing.T) {
@@ -88,6 +89,7 @@ func TestReaderValidateByteAccess(t *testing.T) {
 
 	_, err := NewSegment(fset, nil)
 	require.Error(t, err)
+	require.False(t, IsCorruptSegmentError(err))
 }
 
 func TestReaderValidateDoesNotCloseAllOnBadByteAccess(t *testing.T) {
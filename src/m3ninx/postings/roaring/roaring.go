@@ -23,6 +23,7 @@ package roaring
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/x"
@@ -57,6 +58,72 @@ func Union(inputs []postings.List) (postings.MutableList, error) {
 	return NewPostingsListFromBitmap(unionedBitmap), nil
 }
 
+// Intersect retrieves a new postings list which is the intersection of the provided
+// lists less the union of the negations, the operations are performed natively on the
+// roaring bitmaps rather than through the postings list iterators.
+func Intersect(inputs []postings.List, negations []postings.List) (postings.MutableList, error) {
+	if len(inputs) == 0 {
+		return NewPostingsList(), nil
+	}
+
+	bitmaps, err := bitmapsFromPostingsLists(inputs, errIntersectRoaringOnly)
+	if err != nil {
+		return nil, err
+	}
+	negationBitmaps, err := bitmapsFromPostingsLists(negations, errDifferenceRoaringOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	// NB: intersecting in order of increasing size keeps the intermediate bitmaps small.
+	sort.Slice(bitmaps, func(i, j int) bool {
+		return bitmaps[i].Count() < bitmaps[j].Count()
+	})
+
+	intersectedBitmap := bitmaps[0].Clone()
+	for _, bitmap := range bitmaps[1:] {
+		if intersectedBitmap.Count() == 0 {
+			break
+		}
+		intersectedBitmap = intersectedBitmap.Intersect(bitmap)
+	}
+
+	if len(negationBitmaps) > 0 && intersectedBitmap.Count() > 0 {
+		// Take a single difference with the union of the negations.
+		negatedBitmap := roaring.NewBitmap()
+		negatedBitmap.UnionInPlace(negationBitmaps...)
+		intersectedBitmap = intersectedBitmap.Difference(negatedBitmap)
+	}
+
+	return NewPostingsListFromBitmap(intersectedBitmap), nil
+}
+
+// IsPostingsList returns whether every one of the postings lists is a
+// roaring bitmap postings list.
+func IsPostingsList(pls ...postings.List) bool {
+	for _, pl := range pls {
+		if _, ok := pl.(*postingsList); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func bitmapsFromPostingsLists(
+	pls []postings.List,
+	errNotRoaring error,
+) ([]*roaring.Bitmap, error) {
+	bitmaps := make([]*roaring.Bitmap, 0, len(pls))
+	for _, in := range pls {
+		pl, ok := in.(*postingsList)
+		if !ok {
+			return nil, errNotRoaring
+		}
+		bitmaps = append(bitmaps, pl.bitmap)
+	}
+	return bitmaps, nil
+}
+
 // BitmapFromPostingsList returns a bitmap from a postings list if it
 // is a roaring bitmap postings list.
 func BitmapFromPostingsList(pl postings.List) (*roaring.Bitmap, bool) {
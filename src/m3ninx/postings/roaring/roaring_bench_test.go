@@ -23,6 +23,8 @@ package roaring
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/m3ninx/postings"
+
 	"github.com/RoaringBitmap/roaring"
 )
 
@@ -61,3 +63,84 @@ func BenchmarkCachedObject(b *testing.B) {
 		}
 	}
 }
+
+func newBenchmarkPostingsLists(numPostingsLists, numTotalElements int) []postings.List {
+	bitmaps := newSampledPostingsListsPilosa(numPostingsLists, numTotalElements)
+	pls := make([]postings.List, 0, len(bitmaps))
+	for _, bitmap := range bitmaps {
+		pls = append(pls, NewPostingsListFromBitmap(bitmap))
+	}
+	return pls
+}
+
+func BenchmarkPostingsListUnionLargeIterative(b *testing.B) {
+	pls := newBenchmarkPostingsLists(1000, 1000000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pl := pls[0].Clone()
+		for _, other := range pls[1:] {
+			if err := pl.Union(other); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkPostingsListUnionLargeNative(b *testing.B) {
+	pls := newBenchmarkPostingsLists(1000, 1000000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Union(pls); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func newOverlappingPostingsLists(b *testing.B, numPostingsLists, numTotalElements int) []postings.List {
+	pls := make([]postings.List, 0, numPostingsLists)
+	for j := 0; j < numPostingsLists; j++ {
+		pl := NewPostingsList()
+		for i := 0; i < numTotalElements; i++ {
+			// Each postings list excludes a different subset of the elements.
+			if i%(j+2) != 0 {
+				if err := pl.Insert(postings.ID(i)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		pls = append(pls, pl)
+	}
+	return pls
+}
+
+func BenchmarkPostingsListIntersectIterative(b *testing.B) {
+	pls := newOverlappingPostingsLists(b, 10, 1000000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pl := pls[0].Clone()
+		for _, other := range pls[1:5] {
+			if err := pl.Intersect(other); err != nil {
+				b.Fatal(err)
+			}
+		}
+		for _, other := range pls[5:] {
+			if err := pl.Difference(other); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkPostingsListIntersectNative(b *testing.B) {
+	pls := newOverlappingPostingsLists(b, 10, 1000000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Intersect(pls[:5], pls[5:]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
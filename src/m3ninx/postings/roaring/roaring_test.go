@@ -141,6 +141,56 @@ func TestRoaringPostingsListUnion(t *testing.T) {
 	require.Equal(t, 2, c.Len())
 }
 
+func TestRoaringIntersect(t *testing.T) {
+	a := NewPostingsList()
+	require.NoError(t, a.AddRange(0, 10))
+	b := NewPostingsList()
+	require.NoError(t, b.AddRange(5, 15))
+	c := NewPostingsList()
+	require.NoError(t, c.AddRange(3, 8))
+	negation := NewPostingsList()
+	require.NoError(t, negation.Insert(6))
+
+	pl, err := Intersect([]postings.List{a, b, c}, []postings.List{negation})
+	require.NoError(t, err)
+
+	expected := NewPostingsList()
+	require.NoError(t, expected.Insert(5))
+	require.NoError(t, expected.Insert(7))
+	require.True(t, expected.Equal(pl))
+
+	// Ensure the inputs are not mutated.
+	require.Equal(t, 10, a.Len())
+	require.Equal(t, 10, b.Len())
+	require.Equal(t, 5, c.Len())
+}
+
+func TestRoaringIntersectEmpty(t *testing.T) {
+	pl, err := Intersect(nil, nil)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+
+	a := NewPostingsList()
+	require.NoError(t, a.AddRange(0, 10))
+	pl, err = Intersect([]postings.List{a, NewPostingsList()}, nil)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
+func TestRoaringIntersectNonRoaring(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	other := postings.NewMockList(mockCtrl)
+	_, err := Intersect([]postings.List{NewPostingsList(), other}, nil)
+	require.Error(t, err)
+	_, err = Intersect([]postings.List{NewPostingsList()}, []postings.List{other})
+	require.Error(t, err)
+
+	require.True(t, IsPostingsList(NewPostingsList(), NewPostingsList()))
+	require.False(t, IsPostingsList(NewPostingsList(), other))
+}
+
 func TestRoaringPostingsListAddRange(t *testing.T) {
 	d := NewPostingsList()
 	require.NoError(t, d.Insert(1))
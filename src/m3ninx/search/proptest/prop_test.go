@@ -25,13 +25,16 @@ package proptest
 import (
 	"math/rand"
 	"os"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/m3ninx/search/executor"
+	"github.com/m3db/m3/src/m3ninx/search/query"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
@@ -140,3 +143,59 @@ func TestFSTSimpleSegmentsQueryTheSame(t *testing.T) {
 		t.Errorf("failed with initial seed: %d", seed)
 	}
 }
+
+func TestPrefixQueryMatchesEquivalentRegexp(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	seed := time.Now().UnixNano()
+	parameters.MinSuccessfulTests = 100
+	parameters.MaxSize = 20
+	parameters.Rng = rand.New(rand.NewSource(seed))
+	properties := gopter.NewProperties(parameters)
+
+	simpleSeg := newTestMemSegment(t, lotsTestDocuments)
+	fstSeg := fst.ToTestSegment(t, simpleSeg, fstOptions)
+
+	properties.Property("Prefix queries match the same documents as the equivalent regexp", prop.ForAll(
+		func(fp fieldPrefix) (bool, error) {
+			re := append([]byte(regexp.QuoteMeta(string(fp.prefix))), []byte(".*")...)
+			reQuery, err := query.NewRegexpQuery(fp.field, re)
+			if err != nil {
+				return false, err
+			}
+
+			rFst, err := fstSeg.Reader()
+			require.NoError(t, err)
+			eOrg := executor.NewExecutor([]index.Reader{rFst})
+			dOrg, err := eOrg.Execute(reQuery)
+			if err != nil {
+				return false, err
+			}
+			matchedDocs, err := collectDocs(dOrg)
+			require.NoError(t, err)
+			docMatcher, err := newDocumentIteratorMatcher(matchedDocs...)
+			require.NoError(t, err)
+
+			for _, seg := range []segment.Segment{simpleSeg, fstSeg} {
+				r, err := seg.Reader()
+				require.NoError(t, err)
+				e := executor.NewExecutor([]index.Reader{r})
+				d, err := e.Execute(query.NewPrefixQuery(fp.field, fp.prefix))
+				if err != nil {
+					return false, err
+				}
+
+				if err := docMatcher.Matches(d); err != nil {
+					return false, err
+				}
+			}
+
+			return true, nil
+		},
+		genFieldPrefix(lotsTestDocuments),
+	))
+
+	reporter := gopter.NewFormatedReporter(true, 160, os.Stdout)
+	if !properties.Run(reporter) {
+		t.Errorf("failed with initial seed: %d", seed)
+	}
+}
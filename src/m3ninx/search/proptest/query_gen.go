@@ -102,10 +102,63 @@ func genRegexpQuery(docs []doc.Document) gopter.Gen {
 	}
 }
 
+func genFieldQuery(docs []doc.Document) gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		field := genField(docs, genParams)
+		q := query.NewFieldQuery(field.Name)
+		return gopter.NewGenResult(q, gopter.NoShrinker)
+	}
+}
+
+func genPrefixQuery(docs []doc.Document) gopter.Gen {
+	return genFieldPrefix(docs).
+		Map(func(fp fieldPrefix) search.Query {
+			return query.NewPrefixQuery(fp.field, fp.prefix)
+		})
+}
+
+func genAllQuery() gopter.Gen {
+	return gen.Const(query.NewAllQuery())
+}
+
+// fieldPrefix is a field name and a prefix of one of its values.
+type fieldPrefix struct {
+	field  []byte
+	prefix []byte
+}
+
+func genFieldPrefix(docs []doc.Document) gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		field := genField(docs, genParams)
+		idx := genParams.NextUint64() % uint64(len(field.Value)+1)
+		fp := fieldPrefix{
+			field:  field.Name,
+			prefix: append([]byte(nil), field.Value[:idx]...),
+		}
+		return gopter.NewGenResult(fp, gopter.NoShrinker)
+	}
+}
+
+func genField(docs []doc.Document, genParams *gopter.GenParameters) doc.Field {
+	docIDRes, ok := gen.IntRange(0, len(docs)-1)(genParams).Retrieve()
+	if !ok {
+		panic("unable to generate document") // should never happen
+	}
+	doc := docs[docIDRes.(int)]
+
+	fieldRes, ok := gen.IntRange(0, len(doc.Fields)-1)(genParams).Retrieve()
+	if !ok {
+		panic("unable to generate document field") // should never happen
+	}
+	return doc.Fields[fieldRes.(int)]
+}
+
 func genNegationQuery(docs []doc.Document) gopter.Gen {
 	return gen.OneGenOf(
 		genTermQuery(docs),
 		genRegexpQuery(docs),
+		genFieldQuery(docs),
+		genPrefixQuery(docs),
 	).
 		Map(func(q search.Query) search.Query {
 			return query.NewNegationQuery(q)
@@ -117,6 +170,8 @@ func genConjuctionQuery(docs []doc.Document) gopter.Gen {
 		gen.OneGenOf(
 			genTermQuery(docs),
 			genRegexpQuery(docs),
+			genFieldQuery(docs),
+			genPrefixQuery(docs),
 			genNegationQuery(docs)),
 		reflect.TypeOf((*search.Query)(nil)).Elem()).
 		Map(func(qs []search.Query) search.Query {
@@ -129,6 +184,8 @@ func genDisjunctionQuery(docs []doc.Document) gopter.Gen {
 		gen.OneGenOf(
 			genTermQuery(docs),
 			genRegexpQuery(docs),
+			genFieldQuery(docs),
+			genPrefixQuery(docs),
 			genNegationQuery(docs)),
 		reflect.TypeOf((*search.Query)(nil)).Elem()).
 		Map(func(qs []search.Query) search.Query {
@@ -140,6 +197,9 @@ func genQuery(docs []doc.Document) gopter.Gen {
 	return gen.OneGenOf(
 		genTermQuery(docs),
 		genRegexpQuery(docs),
+		genFieldQuery(docs),
+		genPrefixQuery(docs),
+		genAllQuery(),
 		genNegationQuery(docs),
 		genConjuctionQuery(docs),
 		genDisjunctionQuery(docs))
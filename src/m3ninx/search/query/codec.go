@@ -86,6 +86,15 @@ func unmarshal(q *querypb.Query) (search.Query, error) {
 		}
 		return NewDisjunctionQuery(qs), nil
 
+	case *querypb.Query_Field:
+		return NewFieldQuery(q.Field.Field), nil
+
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_All:
+		return NewAllQuery(), nil
+
 	}
 
 	return nil, fmt.Errorf("unknown query: %v", q)
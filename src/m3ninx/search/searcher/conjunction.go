@@ -23,6 +23,7 @@ package searcher
 import (
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/m3ninx/search"
 )
 
@@ -45,35 +46,49 @@ func NewConjunctionSearcher(searchers, negations search.Searchers) (search.Searc
 }
 
 func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
-	var pl postings.MutableList
+	pls := make([]postings.List, 0, len(s.searchers))
 	for _, sr := range s.searchers {
 		curr, err := sr.Search(r)
 		if err != nil {
 			return nil, err
 		}
 
-		// TODO: Sort the iterators so that we take the intersection in order of increasing size.
-		if pl == nil {
-			pl = curr.Clone()
-		} else {
-			if err := pl.Intersect(curr); err != nil {
-				return nil, err
-			}
-		}
-
-		// We can break early if the interescted postings list is ever empty.
-		if pl.IsEmpty() {
-			break
+		// We can return early if any postings list is empty as the intersection is empty.
+		if curr.IsEmpty() {
+			return curr, nil
 		}
+		pls = append(pls, curr)
 	}
 
+	negations := make([]postings.List, 0, len(s.negations))
 	for _, sr := range s.negations {
 		curr, err := sr.Search(r)
 		if err != nil {
 			return nil, err
 		}
+		negations = append(negations, curr)
+	}
+
+	// NB: roaring bitmaps are intersected and differenced natively, which is
+	// considerably faster than combining the postings lists one at a time.
+	if roaring.IsPostingsList(pls...) && roaring.IsPostingsList(negations...) {
+		return roaring.Intersect(pls, negations)
+	}
+
+	// TODO: Sort the postings lists so that we take the intersection in order of increasing size.
+	pl := pls[0].Clone()
+	for _, curr := range pls[1:] {
+		if err := pl.Intersect(curr); err != nil {
+			return nil, err
+		}
+
+		// We can break early if the interescted postings list is ever empty.
+		if pl.IsEmpty() {
+			return pl, nil
+		}
+	}
 
-		// TODO: Sort the iterators so that we take the set differences in order of decreasing size.
+	for _, curr := range negations {
 		if err := pl.Difference(curr); err != nil {
 			return nil, err
 		}
@@ -102,6 +102,33 @@ func TestConjunctionSearcher(t *testing.T) {
 	require.True(t, pl.Equal(expected))
 }
 
+func TestConjunctionSearcherEmptyPostingsList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	reader := index.NewMockReader(mockCtrl)
+
+	firstPL := roaring.NewPostingsList()
+	firstSearcher := search.NewMockSearcher(mockCtrl)
+	firstSearcher.EXPECT().Search(reader).Return(firstPL, nil)
+
+	// The remaining searchers are not searched once the intersection is known to be empty.
+	secondSearcher := search.NewMockSearcher(mockCtrl)
+	thirdSearcher := search.NewMockSearcher(mockCtrl)
+
+	var (
+		searchers = []search.Searcher{firstSearcher, secondSearcher}
+		negations = []search.Searcher{thirdSearcher}
+	)
+
+	s, err := NewConjunctionSearcher(searchers, negations)
+	require.NoError(t, err)
+
+	pl, err := s.Search(reader)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
 func TestConjunctionSearcherError(t *testing.T) {
 	tests := []struct {
 		name      string
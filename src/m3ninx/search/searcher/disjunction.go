@@ -23,6 +23,7 @@ package searcher
 import (
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/m3ninx/search"
 )
 
@@ -43,18 +44,26 @@ func NewDisjunctionSearcher(searchers search.Searchers) (search.Searcher, error)
 }
 
 func (s *disjunctionSearcher) Search(r index.Reader) (postings.List, error) {
-	var pl postings.MutableList
+	pls := make([]postings.List, 0, len(s.searchers))
 	for _, sr := range s.searchers {
 		curr, err := sr.Search(r)
 		if err != nil {
 			return nil, err
 		}
+		pls = append(pls, curr)
+	}
+
+	// NB: roaring bitmaps are unioned natively in a single pass, which is
+	// considerably faster than unioning the postings lists one at a time.
+	if roaring.IsPostingsList(pls...) {
+		return roaring.Union(pls)
+	}
 
-		// TODO: Sort the iterators so that we take the union in order of decreasing size.
-		if pl == nil {
-			pl = curr.Clone()
-		} else {
-			pl.Union(curr)
+	// TODO: Sort the postings lists so that we take the union in order of decreasing size.
+	pl := pls[0].Clone()
+	for _, curr := range pls[1:] {
+		if err := pl.Union(curr); err != nil {
+			return nil, err
 		}
 	}
 	return pl, nil
@@ -49,6 +49,8 @@ func (s *negationSearcher) Search(r index.Reader) (postings.List, error) {
 		return nil, err
 	}
 
-	pl.Difference(sPl)
+	if err := pl.Difference(sPl); err != nil {
+		return nil, err
+	}
 	return pl, nil
 }
@@ -0,0 +1,63 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// CardinalityURL is the url to report the tag names and metric names
+	// with the highest cardinality
+	CardinalityURL = "/cardinality"
+
+	// CardinalityHTTPMethod is the HTTP method used with this resource.
+	CardinalityHTTPMethod = http.MethodGet
+)
+
+// CardinalityHandler represents a handler for the cardinality endpoint
+type CardinalityHandler struct {
+	tracker cardinality.Tracker
+}
+
+// NewCardinalityHandler returns a new instance of handler
+func NewCardinalityHandler(tracker cardinality.Tracker) http.Handler {
+	return &CardinalityHandler{tracker: tracker}
+}
+
+func (h *CardinalityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	opts, err := cardinality.ParseReportOptions(r.URL.Query())
+	if err != nil {
+		logger.Error("unable to parse request", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, h.tracker.Report(opts), logger)
+}
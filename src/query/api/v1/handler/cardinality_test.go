@@ -0,0 +1,74 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityHandler(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	tracker, err := cardinality.NewTracker(cardinality.NewOptions())
+	require.NoError(t, err)
+
+	store := storage.NewCardinalityTrackingStorage(mock.NewMockStorage(), tracker)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		tags := models.NewTags(2, models.NewTagOptions()).
+			SetName([]byte("requests")).
+			AddTag(models.Tag{Name: []byte("request_id"), Value: []byte(fmt.Sprintf("%d", i))})
+		require.NoError(t, store.Write(context.TODO(), &storage.WriteQuery{
+			Tags:       tags,
+			Datapoints: ts.Datapoints{{Timestamp: now, Value: 1}},
+		}))
+	}
+
+	req := httptest.NewRequest(CardinalityHTTPMethod, CardinalityURL+"?limit=1", nil)
+	recorder := httptest.NewRecorder()
+	NewCardinalityHandler(tracker).ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var report cardinality.Report
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	require.Equal(t, []cardinality.Estimate{{Name: "request_id", Cardinality: 3}}, report.TagNames)
+	require.Equal(t, []cardinality.Estimate{{Name: "requests", Cardinality: 3}}, report.MetricNames)
+
+	req = httptest.NewRequest(CardinalityHTTPMethod, CardinalityURL+"?limit=none", nil)
+	recorder = httptest.NewRecorder()
+	NewCardinalityHandler(tracker).ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
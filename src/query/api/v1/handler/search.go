@@ -107,6 +107,7 @@ func (h *SearchHandler) parseURLParams(r *http.Request) *storage.FetchOptions {
 	}
 
 	fetchOptions := newFetchOptions(limit)
+	fetchOptions.Namespace = r.URL.Query().Get("namespace")
 	return &fetchOptions
 }
 
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/database"
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
@@ -67,6 +68,7 @@ type Handler struct {
 	handler       http.Handler
 	storage       storage.Storage
 	downsampler   downsample.Downsampler
+	cardinality   cardinality.Tracker
 	engine        *executor.Engine
 	clusters      m3.Clusters
 	clusterClient clusterclient.Client
@@ -87,6 +89,7 @@ func NewHandler(
 	storage storage.Storage,
 	tagOptions models.TagOptions,
 	downsampler downsample.Downsampler,
+	cardinalityTracker cardinality.Tracker,
 	engine *executor.Engine,
 	m3dbClusters m3.Clusters,
 	clusterClient clusterclient.Client,
@@ -109,6 +112,7 @@ func NewHandler(
 		handler:       withMiddleware,
 		storage:       storage,
 		downsampler:   downsampler,
+		cardinality:   cardinalityTracker,
 		engine:        engine,
 		clusters:      m3dbClusters,
 		clusterClient: clusterClient,
@@ -178,6 +182,13 @@ func (h *Handler) RegisterRoutes() error {
 		logged(remote.NewPromSeriesMatchHandler(h.storage, h.tagOptions)).ServeHTTP,
 	).Methods(remote.PromSeriesMatchHTTPMethod)
 
+	// Cardinality endpoint
+	if h.cardinality != nil {
+		h.router.HandleFunc(handler.CardinalityURL,
+			logged(handler.NewCardinalityHandler(h.cardinality)).ServeHTTP,
+		).Methods(handler.CardinalityHTTPMethod)
+	}
+
 	// Debug endpoints
 	h.router.HandleFunc(validator.PromDebugURL,
 		logged(validator.NewPromDebugHandler(nativePromReadHandler, h.scope)).ServeHTTP,
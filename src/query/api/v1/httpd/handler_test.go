@@ -48,7 +48,7 @@ func makeTagOptions() models.TagOptions {
 }
 
 func setupHandler(store storage.Storage) (*Handler, error) {
-	return NewHandler(store, makeTagOptions(), nil, executor.NewEngine(store, tally.NewTestScope("test", nil)), nil, nil,
+	return NewHandler(store, makeTagOptions(), nil, nil, executor.NewEngine(store, tally.NewTestScope("test", nil)), nil, nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 }
 
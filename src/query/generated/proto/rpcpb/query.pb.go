@@ -142,6 +142,7 @@ type FetchRequest struct {
 	// Types that are valid to be assigned to Matchers:
 	//	*FetchRequest_TagMatchers
 	Matchers isFetchRequest_Matchers `protobuf_oneof:"matchers"`
+	Export   bool                    `protobuf:"varint,4,opt,name=export,proto3" json:"export,omitempty"`
 }
 
 func (m *FetchRequest) Reset()                    { *m = FetchRequest{} }
@@ -189,6 +190,13 @@ func (m *FetchRequest) GetTagMatchers() *TagMatchers {
 	return nil
 }
 
+func (m *FetchRequest) GetExport() bool {
+	if m != nil {
+		return m.Export
+	}
+	return false
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*FetchRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _FetchRequest_OneofMarshaler, _FetchRequest_OneofUnmarshaler, _FetchRequest_OneofSizer, []interface{}{
@@ -1526,6 +1534,16 @@ func (m *FetchRequest) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += nn1
 	}
+	if m.Export {
+		dAtA[i] = 0x20
+		i++
+		if m.Export {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -2436,6 +2454,9 @@ func (m *FetchRequest) Size() (n int) {
 	if m.Matchers != nil {
 		n += m.Matchers.Size()
 	}
+	if m.Export {
+		n += 2
+	}
 	return n
 }
 
@@ -2958,6 +2979,26 @@ func (m *FetchRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Matchers = &FetchRequest_TagMatchers{v}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Export", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Export = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -5588,73 +5629,72 @@ func init() {
 }
 
 var fileDescriptorQuery = []byte{
-	// 1075 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xf6, 0xf9, 0x6c, 0xc7, 0x1e, 0xbf, 0xd4, 0x4c, 0x02, 0x75, 0xa3, 0x28, 0x44, 0x07, 0x94,
-	0x10, 0x20, 0x0e, 0x76, 0x04, 0x14, 0x89, 0x97, 0x84, 0xba, 0x09, 0x52, 0x93, 0x34, 0xeb, 0x2b,
-	0x8a, 0x50, 0x3f, 0xb0, 0xbe, 0xdb, 0x3a, 0xa7, 0xfa, 0x7c, 0xd7, 0xbb, 0x35, 0xaa, 0xfb, 0x17,
-	0xf8, 0x82, 0xf8, 0x2d, 0xf0, 0x1f, 0xf8, 0xc8, 0x4f, 0x40, 0xe1, 0x8f, 0xa0, 0xdd, 0xdb, 0x7b,
-	0xb3, 0x5d, 0xde, 0xbe, 0xdd, 0xcc, 0x3c, 0x33, 0x3b, 0x33, 0xfb, 0xf8, 0xf1, 0xc2, 0x17, 0x63,
-	0x87, 0x5f, 0xcf, 0x46, 0xfb, 0x96, 0xe7, 0x76, 0xdd, 0xbe, 0x3d, 0xea, 0xba, 0xfd, 0x6e, 0x18,
-	0x58, 0xdd, 0xe7, 0x33, 0x16, 0xcc, 0xbb, 0x63, 0x36, 0x65, 0x01, 0xe5, 0xcc, 0xee, 0xfa, 0x81,
-	0xc7, 0xbd, 0x6e, 0xe0, 0x5b, 0xfe, 0x28, 0x8a, 0xed, 0x4b, 0x0f, 0xea, 0x81, 0x6f, 0x19, 0x2f,
-	0xa0, 0xf1, 0x80, 0x71, 0xeb, 0x9a, 0xb0, 0xe7, 0x33, 0x16, 0x72, 0xdc, 0x80, 0x72, 0xc8, 0x69,
-	0xc0, 0x3b, 0xda, 0x8e, 0xb6, 0xab, 0x93, 0xc8, 0xc0, 0x36, 0xe8, 0x6c, 0x6a, 0x77, 0x8a, 0xd2,
-	0x27, 0x3e, 0xf1, 0x10, 0xea, 0x9c, 0x8e, 0xcf, 0x28, 0xb7, 0xae, 0x59, 0x10, 0x76, 0xf4, 0x1d,
-	0x6d, 0xb7, 0xde, 0x6b, 0xef, 0x07, 0xbe, 0xb5, 0x6f, 0xa6, 0xfe, 0xd3, 0x02, 0xc9, 0xc2, 0x8e,
-	0x01, 0xaa, 0xae, 0xfa, 0x36, 0xbe, 0x82, 0x7a, 0x06, 0x89, 0x1f, 0xe5, 0x0b, 0x6a, 0x3b, 0xfa,
-	0x6e, 0xbd, 0x77, 0x6b, 0xa1, 0x60, 0xae, 0x9a, 0xf1, 0x04, 0x20, 0x0d, 0x21, 0x42, 0x69, 0x4a,
-	0x5d, 0x26, 0x1b, 0x6f, 0x10, 0xf9, 0x2d, 0xa6, 0xf9, 0x81, 0x4e, 0x66, 0x4c, 0x76, 0xde, 0x20,
-	0x91, 0x81, 0x6f, 0x43, 0x89, 0xcf, 0x7d, 0x26, 0x9b, 0x6e, 0xa9, 0xa6, 0x55, 0x15, 0x73, 0xee,
-	0x33, 0x22, 0xa3, 0xc6, 0x21, 0x34, 0xd5, 0x66, 0x42, 0xdf, 0x9b, 0x86, 0x0c, 0xdf, 0x82, 0x4a,
-	0xc8, 0x02, 0x87, 0xc5, 0xcd, 0xd5, 0x65, 0xe2, 0x50, 0xba, 0x88, 0x0a, 0x19, 0xbf, 0x68, 0x50,
-	0x89, 0x5c, 0xf8, 0x2e, 0x94, 0x5c, 0xc6, 0xa9, 0x6c, 0xa8, 0xde, 0x5b, 0xcf, 0xa0, 0xcf, 0x18,
-	0xa7, 0x36, 0xe5, 0x94, 0x48, 0x00, 0x7e, 0x0e, 0x0d, 0x9b, 0x59, 0x9e, 0xeb, 0x07, 0x2c, 0x0c,
-	0x59, 0xb4, 0xe6, 0x7a, 0xef, 0xb6, 0x4c, 0xb8, 0x9f, 0x09, 0x44, 0xc9, 0xa7, 0x05, 0x92, 0x83,
-	0xe3, 0x3d, 0x80, 0x4c, 0xb2, 0x9e, 0x49, 0x3e, 0xeb, 0x7f, 0xbd, 0x9c, 0x9c, 0x01, 0x1f, 0xaf,
-	0xa9, 0xfd, 0x18, 0x57, 0xd0, 0xca, 0xb7, 0x86, 0x2d, 0x28, 0x3a, 0xb6, 0x5a, 0x66, 0xd1, 0xb1,
-	0x71, 0x0b, 0x6a, 0x92, 0x0b, 0xa6, 0xe3, 0x32, 0x45, 0x84, 0xd4, 0x81, 0x1d, 0x58, 0x63, 0x53,
-	0x5b, 0xc6, 0x74, 0x19, 0x8b, 0x4d, 0x63, 0x04, 0xb8, 0x3c, 0x03, 0xee, 0x03, 0x88, 0x53, 0x7c,
-	0xcf, 0x99, 0xf2, 0x78, 0x9f, 0xad, 0x68, 0xe0, 0xd8, 0x4d, 0x32, 0x08, 0xdc, 0x82, 0x12, 0xa7,
-	0xe3, 0xb0, 0x53, 0x94, 0xc8, 0x6a, 0x4c, 0x0b, 0x22, 0xbd, 0xc6, 0x97, 0x50, 0x4b, 0xd2, 0x44,
-	0xa3, 0xdc, 0x71, 0x59, 0xc8, 0xa9, 0xeb, 0x2b, 0x16, 0xa7, 0x8e, 0x3c, 0x23, 0x34, 0xc5, 0x08,
-	0xa3, 0x0b, 0xba, 0x49, 0xc7, 0xff, 0x9e, 0x42, 0xc6, 0x0b, 0xc0, 0xe5, 0xe5, 0xe2, 0x5d, 0x68,
-	0xa5, 0x93, 0x9a, 0xa2, 0xdf, 0xa8, 0xd2, 0x82, 0x17, 0x3f, 0x83, 0x6a, 0xc0, 0xfc, 0x89, 0x63,
-	0xd1, 0x78, 0xa2, 0xed, 0xa5, 0xfb, 0xfa, 0x56, 0x9c, 0x13, 0x92, 0x08, 0x46, 0x12, 0xbc, 0x71,
-	0x0a, 0x77, 0x5e, 0x09, 0xc3, 0xf7, 0xa1, 0x1a, 0xb2, 0xb1, 0xcb, 0xd2, 0xa5, 0xde, 0x52, 0x85,
-	0x87, 0xca, 0x4d, 0x12, 0x80, 0xf1, 0x3d, 0x40, 0xea, 0xc7, 0xbb, 0x50, 0x71, 0x59, 0x30, 0x66,
-	0xb6, 0xe2, 0x6b, 0x2b, 0x9f, 0x48, 0x54, 0x14, 0xf7, 0xa0, 0x3a, 0x9b, 0x2a, 0x64, 0x31, 0x73,
-	0x6f, 0x29, 0x32, 0x89, 0x1b, 0x1e, 0xd4, 0x12, 0xb7, 0x58, 0xee, 0x35, 0xa3, 0x31, 0xa5, 0xe4,
-	0xb7, 0xf0, 0x71, 0xea, 0x4c, 0xd4, 0x6e, 0xe5, 0x77, 0x9e, 0x68, 0xfa, 0x22, 0xd1, 0xb6, 0xa0,
-	0x36, 0x9a, 0x78, 0xd6, 0xb3, 0xa1, 0xf3, 0x92, 0x75, 0x4a, 0x51, 0x34, 0x71, 0x18, 0x97, 0xd0,
-	0x1c, 0x32, 0x1a, 0xa4, 0x72, 0x76, 0xb8, 0xa8, 0x2a, 0xff, 0x59, 0xa6, 0x4e, 0xa0, 0x79, 0xd6,
-	0x37, 0xe9, 0xf8, 0x51, 0xe0, 0xf9, 0x2c, 0xe0, 0xf3, 0xa5, 0x1f, 0xc6, 0xf2, 0xa5, 0x17, 0x57,
-	0x5d, 0xba, 0x31, 0x80, 0x5b, 0xd9, 0x42, 0x82, 0x2f, 0x3d, 0x00, 0x3f, 0xb1, 0xd4, 0x85, 0xa1,
-	0xda, 0x66, 0xe6, 0x48, 0x92, 0x41, 0x19, 0x9f, 0x48, 0xd9, 0x4c, 0xba, 0x69, 0x83, 0xfe, 0x8c,
-	0xcd, 0x55, 0x3b, 0xe2, 0x13, 0xdf, 0x80, 0x8a, 0xe4, 0x68, 0xdc, 0x87, 0xb2, 0x8c, 0x23, 0x68,
-	0xe6, 0x4f, 0x3f, 0x58, 0x71, 0x7a, 0xb2, 0x9a, 0x95, 0x67, 0xff, 0xa8, 0x09, 0x99, 0x88, 0xf6,
-	0xab, 0x44, 0xf1, 0xd3, 0x05, 0xed, 0x8a, 0x36, 0x8c, 0x0b, 0x65, 0x56, 0xc9, 0xd6, 0xc7, 0x39,
-	0xd9, 0x8a, 0x34, 0x6f, 0x63, 0x69, 0xf8, 0xbf, 0xd1, 0x2c, 0x0f, 0x36, 0xc5, 0xef, 0x60, 0xc2,
-	0x38, 0x13, 0x0b, 0x56, 0x57, 0x7e, 0xe1, 0x73, 0xc7, 0x9b, 0x86, 0xf8, 0x9e, 0x12, 0x79, 0x4d,
-	0x8a, 0xfc, 0xeb, 0xb2, 0x70, 0x16, 0x9e, 0x2a, 0xbd, 0xb8, 0xc1, 0xa7, 0xce, 0x84, 0xb3, 0xe0,
-	0x9c, 0xba, 0xcc, 0x8c, 0x65, 0xa6, 0x41, 0x16, 0xbc, 0xc6, 0xcf, 0x1a, 0xac, 0xaf, 0x38, 0xf1,
-	0xff, 0x91, 0x0c, 0xef, 0xc1, 0x9a, 0x17, 0xf5, 0xaa, 0x86, 0x7f, 0x73, 0xa9, 0xc7, 0xfc, 0x48,
-	0x24, 0xc6, 0xe7, 0xf8, 0xb9, 0x03, 0x55, 0x93, 0x8e, 0x45, 0x8f, 0xa1, 0xd0, 0x2a, 0xa1, 0x59,
-	0xd1, 0x65, 0x36, 0x48, 0x64, 0x18, 0x87, 0x12, 0x21, 0x85, 0xe2, 0x1f, 0xe8, 0xa2, 0x67, 0xe8,
-	0xd2, 0x83, 0x5a, 0x9c, 0x15, 0xe2, 0x3b, 0x09, 0x28, 0xa2, 0x49, 0x33, 0x1e, 0x4e, 0xc6, 0x93,
-	0x9c, 0x97, 0xb0, 0x91, 0x6f, 0x5f, 0x91, 0x64, 0x0f, 0xd6, 0x6c, 0xf6, 0x94, 0xce, 0x26, 0x3c,
-	0x27, 0x2e, 0x49, 0xfd, 0xd3, 0x02, 0x89, 0x01, 0xf8, 0x21, 0xd4, 0x64, 0xdb, 0x17, 0xd3, 0xc9,
-	0x5c, 0x2d, 0x26, 0x39, 0x4d, 0x4e, 0x79, 0x5a, 0x20, 0x29, 0x22, 0x61, 0xc3, 0xde, 0x13, 0xa8,
-	0x67, 0xfe, 0xc3, 0xb1, 0x06, 0xe5, 0xc1, 0xe5, 0xe3, 0xa3, 0x87, 0xed, 0x02, 0x36, 0xa0, 0x7a,
-	0x7e, 0x61, 0x46, 0x96, 0x86, 0x00, 0x15, 0x32, 0x38, 0x19, 0x5c, 0x3d, 0x6a, 0x17, 0xb1, 0x09,
-	0xb5, 0xf3, 0x0b, 0x53, 0x99, 0xba, 0x08, 0x0d, 0xae, 0xbe, 0x19, 0x9a, 0xc3, 0x76, 0x49, 0x85,
-	0x94, 0x59, 0xde, 0xfb, 0x00, 0xda, 0x8b, 0xe4, 0xc1, 0x3a, 0xac, 0xdd, 0x1f, 0x3c, 0x38, 0x7a,
-	0xfc, 0xd0, 0x6c, 0x17, 0x84, 0x61, 0x1e, 0x9d, 0x9c, 0x1f, 0x9d, 0x0d, 0xda, 0x5a, 0xef, 0x57,
-	0x0d, 0xca, 0x97, 0xe2, 0xa5, 0x85, 0x07, 0x50, 0x96, 0x8f, 0x08, 0x7c, 0x4d, 0xce, 0x90, 0x7d,
-	0x6a, 0x6d, 0x62, 0xd6, 0x15, 0x6d, 0xea, 0x40, 0xc3, 0xbe, 0x78, 0x3f, 0x88, 0x9f, 0x18, 0xa2,
-	0x7a, 0x31, 0x64, 0xf4, 0x6c, 0x73, 0x3d, 0xe7, 0x4b, 0x92, 0x06, 0xd0, 0xc8, 0xb6, 0x87, 0x9d,
-	0x57, 0x51, 0x69, 0xf3, 0xce, 0x8a, 0x48, 0x5c, 0xe6, 0xf8, 0xf6, 0x6f, 0x37, 0xdb, 0xda, 0xef,
-	0x37, 0xdb, 0xda, 0x1f, 0x37, 0xdb, 0xda, 0x4f, 0x7f, 0x6e, 0x17, 0xbe, 0x2b, 0xcb, 0x67, 0xe3,
-	0xa8, 0x22, 0x5f, 0x8c, 0xfd, 0xbf, 0x02, 0x00, 0x00, 0xff, 0xff, 0xe6, 0x41, 0x85, 0x94, 0x73,
-	0x0a, 0x00, 0x00,
+	// 1071 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9d, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
+	0x10, 0x35, 0x45, 0x5d, 0x47, 0x17, 0xab, 0x63, 0xb7, 0x51, 0x8c, 0xc2, 0x35, 0xd8, 0xbb, 0xdb,
+	0x4a, 0xa9, 0x64, 0xf4, 0x06, 0xf4, 0x22, 0x37, 0x8a, 0x53, 0x20, 0xb2, 0x63, 0x4a, 0x29, 0x8c,
+	0x22, 0x0f, 0xa5, 0xa4, 0x8d, 0x4c, 0x44, 0x12, 0x59, 0x92, 0x2a, 0xa2, 0xfc, 0x42, 0x5f, 0x82,
+	0x7e, 0x4b, 0xfb, 0x0f, 0x7d, 0xec, 0x27, 0x14, 0xed, 0x8f, 0x74, 0x76, 0xb9, 0x24, 0x97, 0x92,
+	0x92, 0x06, 0x7d, 0xb0, 0xb1, 0x33, 0x73, 0xe6, 0xba, 0xc3, 0xa3, 0x85, 0xaf, 0xa6, 0x76, 0x70,
+	0xbd, 0x1c, 0x35, 0xc7, 0xce, 0xbc, 0x35, 0xef, 0x4c, 0x46, 0xf4, 0xaf, 0xe5, 0x7b, 0xe3, 0xd6,
+	0x4f, 0x4b, 0xe6, 0xad, 0x5a, 0x53, 0xb6, 0x60, 0x9e, 0x15, 0xb0, 0x49, 0xcb, 0xf5, 0x9c, 0xc0,
+	0x69, 0x79, 0xee, 0xd8, 0x1d, 0x85, 0xb6, 0xa6, 0xd0, 0xa0, 0x4e, 0x2a, 0xe3, 0x99, 0x06, 0x95,
+	0x3b, 0x2c, 0x18, 0x5f, 0x9b, 0x8c, 0x6c, 0x7e, 0x80, 0xfb, 0x90, 0xf3, 0x03, 0xcb, 0x0b, 0x1a,
+	0xda, 0x91, 0xf6, 0x9e, 0x6e, 0x86, 0x02, 0xd6, 0x41, 0x67, 0x8b, 0x49, 0x23, 0x23, 0x74, 0xfc,
+	0x88, 0x27, 0x50, 0x0e, 0xac, 0x69, 0xdf, 0x22, 0x57, 0xe6, 0xf9, 0x0d, 0x9d, 0x2c, 0xe5, 0x76,
+	0xbd, 0x49, 0x31, 0x9b, 0xc3, 0x44, 0x7f, 0x77, 0xc7, 0x54, 0x61, 0xf8, 0x1a, 0xe4, 0xd9, 0x13,
+	0xd7, 0xa1, 0xf0, 0x59, 0x72, 0x28, 0x9a, 0x52, 0x3a, 0x05, 0x28, 0xce, 0x25, 0xc6, 0xf8, 0x06,
+	0xca, 0x4a, 0x04, 0xfc, 0x38, 0x9d, 0x48, 0x3b, 0xd2, 0x29, 0xd1, 0xee, 0x5a, 0xa2, 0x54, 0x16,
+	0xe3, 0x21, 0x40, 0x62, 0x42, 0x84, 0xec, 0xc2, 0x9a, 0x33, 0xd1, 0x50, 0xc5, 0x14, 0x67, 0xde,
+	0xe5, 0xcf, 0xd6, 0x6c, 0xc9, 0x44, 0x47, 0x15, 0x33, 0x14, 0xf0, 0x2d, 0xc8, 0x06, 0x2b, 0x97,
+	0x89, 0x66, 0x6a, 0xb2, 0x19, 0x19, 0x65, 0x48, 0x7a, 0x53, 0x58, 0x8d, 0x13, 0xa8, 0xca, 0x89,
+	0xf9, 0xae, 0xb3, 0xf0, 0x19, 0xbe, 0x09, 0x79, 0x9f, 0x79, 0x36, 0x8b, 0x8a, 0x2b, 0x0b, 0xc7,
+	0x81, 0x50, 0x99, 0xd2, 0x64, 0xfc, 0xa6, 0x41, 0x3e, 0x54, 0xe1, 0xbb, 0x90, 0x9d, 0xb3, 0xc0,
+	0x12, 0x05, 0x95, 0xdb, 0x7b, 0x0a, 0xba, 0x4f, 0xea, 0x89, 0x15, 0x58, 0xa6, 0x00, 0xe0, 0x97,
+	0x50, 0x99, 0x30, 0xba, 0x5e, 0xd7, 0x63, 0xbe, 0xcf, 0xc2, 0xf1, 0x97, 0xdb, 0x37, 0x84, 0xc3,
+	0x6d, 0xc5, 0x10, 0x3a, 0xd3, 0xac, 0x53, 0x70, 0xfc, 0x1c, 0x40, 0x71, 0xd6, 0x15, 0xe7, 0x7e,
+	0xe7, 0xdb, 0x4d, 0x67, 0x05, 0x7c, 0x5a, 0x90, 0xf3, 0x31, 0xae, 0xa0, 0x96, 0x2e, 0x0d, 0x6b,
+	0x90, 0xb1, 0x27, 0x72, 0x98, 0x74, 0xc2, 0xd7, 0xa1, 0x24, 0x76, 0x64, 0x68, 0xcf, 0x99, 0x5c,
+	0x90, 0x44, 0x81, 0x0d, 0x28, 0xd0, 0xb6, 0x08, 0x9b, 0x2e, 0x6c, 0x91, 0x68, 0x8c, 0x00, 0x37,
+	0x7b, 0xc0, 0x26, 0x00, 0xcf, 0xe2, 0x3a, 0xf6, 0x22, 0x88, 0xe6, 0x59, 0x0b, 0x1b, 0x8e, 0xd4,
+	0xa6, 0x82, 0xa0, 0xec, 0x59, 0xba, 0x79, 0x9f, 0x12, 0x73, 0x64, 0x31, 0x5a, 0x0b, 0x53, 0x68,
+	0x8d, 0xaf, 0xa1, 0x14, 0xbb, 0xf1, 0x42, 0x03, 0x4a, 0x4c, 0xb5, 0xcd, 0x5d, 0xb9, 0xdd, 0x89,
+	0x22, 0xbd, 0x11, 0x9a, 0xdc, 0x08, 0xa3, 0x05, 0x3a, 0x45, 0x7b, 0xf9, 0x15, 0x32, 0x9e, 0x00,
+	0x6e, 0x0e, 0x17, 0xdf, 0x81, 0x5a, 0xd2, 0xe9, 0x90, 0xd7, 0x1b, 0x46, 0x5a, 0xd3, 0xe2, 0x17,
+	0x50, 0xf4, 0x98, 0x3b, 0xb3, 0xc7, 0x56, 0xd4, 0xd1, 0xe1, 0xc6, 0x7d, 0x7d, 0xcf, 0xf3, 0xf8,
+	0x66, 0x08, 0x33, 0x63, 0xbc, 0x71, 0x17, 0x6e, 0x3e, 0x17, 0x86, 0x1f, 0x40, 0xd1, 0x67, 0xd3,
+	0x39, 0x4b, 0x86, 0xba, 0x2b, 0x03, 0x0f, 0xa4, 0xda, 0x8c, 0x01, 0xc6, 0x8f, 0x00, 0x89, 0x9e,
+	0x6a, 0xcf, 0xcf, 0x99, 0x37, 0x65, 0x13, 0xb9, 0xaf, 0xb5, 0xb4, 0xa3, 0x29, 0xad, 0x78, 0x0c,
+	0xc5, 0xe5, 0x42, 0x22, 0x33, 0xca, 0xbd, 0x25, 0xc8, 0xd8, 0x6e, 0x38, 0x50, 0x8a, 0xd5, 0x7c,
+	0xb8, 0xd7, 0xcc, 0x8a, 0x56, 0x4a, 0x9c, 0xb9, 0x2e, 0xb0, 0xec, 0x99, 0x9c, 0xad, 0x38, 0xa7,
+	0x17, 0x4d, 0x5f, 0x5f, 0x34, 0xb2, 0x8e, 0x66, 0xce, 0xf8, 0xf1, 0xc0, 0x7e, 0xca, 0x04, 0xb9,
+	0x90, 0x35, 0x56, 0x18, 0x97, 0x50, 0x1d, 0x30, 0xcb, 0x4b, 0x68, 0xee, 0x64, 0x9d, 0x55, 0x5e,
+	0x86, 0xbe, 0x52, 0x34, 0x75, 0x06, 0xd5, 0x7e, 0x87, 0xb0, 0xf7, 0x3d, 0xc7, 0x65, 0x5e, 0xb0,
+	0xda, 0xf8, 0x30, 0x36, 0x2f, 0x3d, 0xb3, 0xed, 0xd2, 0x8d, 0x1e, 0xec, 0xaa, 0x81, 0xf8, 0xbe,
+	0xb4, 0x01, 0xdc, 0x58, 0x92, 0x17, 0x86, 0x72, 0x9a, 0x4a, 0x4a, 0x53, 0x41, 0x19, 0x9f, 0x0a,
+	0xda, 0x8c, 0xab, 0x21, 0xc6, 0x7e, 0xcc, 0x56, 0xb2, 0x1c, 0x7e, 0xe4, 0xdc, 0x2b, 0x76, 0x34,
+	0xaa, 0x43, 0x4a, 0x46, 0x17, 0xaa, 0xe9, 0xec, 0xb7, 0xb6, 0x64, 0x8f, 0x47, 0xb3, 0x35, 0xf7,
+	0x2f, 0x1a, 0xa7, 0x89, 0x70, 0xbe, 0x92, 0x14, 0x3f, 0x5b, 0xe3, 0xae, 0x70, 0xc2, 0xb8, 0x16,
+	0x66, 0x1b, 0x6d, 0x7d, 0x92, 0xa2, 0xad, 0x90, 0xf3, 0xf6, 0x37, 0x9a, 0x7f, 0x01, 0x67, 0x39,
+	0x70, 0xc0, 0xbf, 0x83, 0x19, 0x0b, 0x18, 0x1f, 0xb0, 0xbc, 0xf2, 0x0b, 0x37, 0xb0, 0xa9, 0x32,
+	0x7c, 0x5f, 0x92, 0xbc, 0x26, 0x48, 0xfe, 0x55, 0x11, 0x58, 0x85, 0x27, 0x4c, 0xcf, 0x6f, 0xf0,
+	0x91, 0x3d, 0x0b, 0x98, 0x77, 0x4e, 0x1f, 0xfc, 0x30, 0xa2, 0x19, 0xba, 0xc1, 0xb4, 0xd6, 0xf8,
+	0x55, 0x83, 0xbd, 0x2d, 0x19, 0xff, 0xdf, 0x92, 0x11, 0x6d, 0x17, 0x9c, 0xb0, 0x56, 0xd9, 0xfc,
+	0x1b, 0x1b, 0x35, 0xa6, 0x5b, 0x32, 0x23, 0x7c, 0x6a, 0x3f, 0x8f, 0xa0, 0x48, 0x50, 0x5e, 0xa3,
+	0xcf, 0xb9, 0x8a, 0x73, 0x56, 0x78, 0x99, 0xc4, 0x55, 0x42, 0xa0, 0x1f, 0x32, 0x8e, 0x10, 0x44,
+	0xf1, 0x1f, 0xeb, 0xa2, 0x2b, 0xeb, 0xd2, 0x86, 0x52, 0xe4, 0xe5, 0xe3, 0xdb, 0x31, 0x28, 0x5c,
+	0x93, 0x6a, 0xd4, 0x9c, 0xb0, 0xc7, 0x3e, 0x4f, 0x61, 0x3f, 0x5d, 0xbe, 0x5c, 0x92, 0x63, 0x28,
+	0x4c, 0xd8, 0x23, 0x6b, 0x39, 0x0b, 0x52, 0xe4, 0x12, 0xc7, 0xa7, 0xd1, 0x44, 0x00, 0xfc, 0x08,
+	0x4a, 0xa2, 0xec, 0x8b, 0xc5, 0x6c, 0x25, 0x07, 0x13, 0x67, 0x13, 0x5d, 0x12, 0x38, 0x41, 0xc4,
+	0xdb, 0x70, 0xfc, 0x10, 0xca, 0xca, 0x6f, 0x38, 0x96, 0x20, 0xd7, 0xbb, 0x7c, 0xd0, 0xbd, 0x57,
+	0xdf, 0xc1, 0x0a, 0x14, 0xcf, 0x2f, 0x86, 0xa1, 0xa4, 0x21, 0x40, 0xde, 0xec, 0x9d, 0xf5, 0xae,
+	0xee, 0xd7, 0x33, 0x58, 0x85, 0x12, 0x59, 0xa4, 0xa8, 0x73, 0x53, 0xef, 0xea, 0xbb, 0xc1, 0x70,
+	0x50, 0xcf, 0x4a, 0x93, 0x14, 0x73, 0xc7, 0x1f, 0x42, 0x7d, 0x7d, 0x79, 0xb0, 0x0c, 0x85, 0xdb,
+	0xbd, 0x3b, 0xdd, 0x07, 0xf7, 0x86, 0x94, 0x84, 0x84, 0x61, 0xf7, 0xec, 0xbc, 0xdb, 0xef, 0xd5,
+	0xb5, 0xf6, 0xef, 0x1a, 0xe4, 0x2e, 0xf9, 0x13, 0x8c, 0xbe, 0xb1, 0x9c, 0x78, 0x44, 0xe0, 0x2b,
+	0xa2, 0x07, 0xf5, 0x09, 0x76, 0x80, 0xaa, 0x2a, 0x9c, 0xd4, 0x2d, 0x0d, 0x3b, 0xfc, 0xfd, 0xc0,
+	0x3f, 0x31, 0x44, 0xf9, 0x62, 0x50, 0xf8, 0xec, 0x60, 0x2f, 0xa5, 0x8b, 0x9d, 0x7a, 0x50, 0x51,
+	0xcb, 0xc3, 0xc6, 0xf3, 0x56, 0xe9, 0xe0, 0xe6, 0x16, 0x4b, 0x14, 0xe6, 0xf4, 0xc6, 0x1f, 0x7f,
+	0x1f, 0x6a, 0x7f, 0xd2, 0xdf, 0x5f, 0xf4, 0xf7, 0xec, 0x9f, 0xc3, 0x9d, 0x1f, 0x72, 0xe2, 0x3d,
+	0x39, 0xca, 0x8b, 0xa7, 0x64, 0xe7, 0x5f, 0xd7, 0x0b, 0x62, 0x8e, 0x8c, 0x0a, 0x00, 0x00,
 }
@@ -36,6 +36,7 @@ import (
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/httpd"
@@ -66,6 +67,10 @@ import (
 	"google.golang.org/grpc"
 )
 
+const (
+	cardinalityTickInterval = time.Minute
+)
+
 var (
 	defaultLocalConfiguration = &config.LocalConfiguration{
 		Namespace: "default",
@@ -202,10 +207,24 @@ func Run(runOpts RunOptions) {
 		defer cleanup()
 	}
 
+	// Track the cardinality of the series written through the coordinator
+	cardinalityTracker, err := cardinality.NewTracker(cardinality.NewOptions().
+		SetMetricNameTag(tagOptions.MetricName()).
+		SetInstrumentOptions(instrumentOptions))
+	if err != nil {
+		logger.Fatal("could not create cardinality tracker", zap.Error(err))
+	}
+	backendStorage = storage.NewCardinalityTrackingStorage(backendStorage, cardinalityTracker)
+
+	cardinalityDoneCh := make(chan struct{})
+	defer close(cardinalityDoneCh)
+	go tickCardinalityTracker(cardinalityTracker, cardinalityDoneCh)
+
 	engine := executor.NewEngine(backendStorage, scope.SubScope("engine"))
 
-	handler, err := httpd.NewHandler(backendStorage, tagOptions, downsampler, engine,
-		m3dbClusters, clusterClient, cfg, runOpts.DBConfig, scope)
+	handler, err := httpd.NewHandler(backendStorage, tagOptions, downsampler,
+		cardinalityTracker, engine, m3dbClusters, clusterClient, cfg,
+		runOpts.DBConfig, scope)
 	if err != nil {
 		logger.Fatal("unable to set up handlers", zap.Error(err))
 	}
@@ -643,3 +662,17 @@ func startGrpcServer(
 	<-waitForStart
 	return server, startErr
 }
+
+func tickCardinalityTracker(tracker cardinality.Tracker, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(cardinalityTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-doneCh:
+			return
+		case now := <-ticker.C:
+			tracker.Tick(now)
+		}
+	}
+}
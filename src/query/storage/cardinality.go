@@ -0,0 +1,88 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/query/models"
+)
+
+type cardinalityTrackingStorage struct {
+	Storage
+
+	tracker    cardinality.Tracker
+	bufferPool sync.Pool
+}
+
+// trackingBuffer is reused to build the document of a series so tracking
+// writes does not allocate, the tracker does not retain the document.
+type trackingBuffer struct {
+	id     []byte
+	fields []doc.Field
+}
+
+// NewCardinalityTrackingStorage returns a storage that tracks the
+// cardinality of the series written through it.
+func NewCardinalityTrackingStorage(
+	store Storage,
+	tracker cardinality.Tracker,
+) Storage {
+	return &cardinalityTrackingStorage{
+		Storage: store,
+		tracker: tracker,
+		bufferPool: sync.Pool{
+			New: func() interface{} {
+				return &trackingBuffer{}
+			},
+		},
+	}
+}
+
+func (s *cardinalityTrackingStorage) Write(
+	ctx context.Context,
+	query *WriteQuery,
+) error {
+	if query != nil && len(query.Datapoints) > 0 {
+		buf := s.bufferPool.Get().(*trackingBuffer)
+		s.tracker.Add(query.Datapoints[0].Timestamp, buf.document(query.Tags))
+		s.bufferPool.Put(buf)
+	}
+	return s.Storage.Write(ctx, query)
+}
+
+func (b *trackingBuffer) document(tags models.Tags) doc.Document {
+	b.fields = b.fields[:0]
+	for _, tag := range tags.Tags {
+		b.fields = append(b.fields, doc.Field{
+			Name:  tag.Name,
+			Value: tag.Value,
+		})
+	}
+	b.id = tags.IDMarshalTo(b.id[:0])
+	return doc.Document{
+		ID:     b.id,
+		Fields: b.fields,
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/cardinality"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/require"
+)
+
+type noopWriteStorage struct {
+	Storage
+}
+
+func (s noopWriteStorage) Write(_ context.Context, _ *WriteQuery) error {
+	return nil
+}
+
+func TestCardinalityTrackingStorageWrite(t *testing.T) {
+	tracker, err := cardinality.NewTracker(cardinality.NewOptions())
+	require.NoError(t, err)
+	store := NewCardinalityTrackingStorage(noopWriteStorage{}, tracker)
+
+	for _, query := range newTestWriteQueries(10) {
+		require.NoError(t, store.Write(context.Background(), query))
+	}
+
+	report := tracker.Report(cardinality.ReportOptions{})
+	require.Equal(t, 1, len(report.MetricNames))
+	require.Equal(t, "requests", report.MetricNames[0].Name)
+	require.InDelta(t, 10, float64(report.MetricNames[0].Cardinality), 1)
+}
+
+func BenchmarkCardinalityTrackingStorageWrite(b *testing.B) {
+	tracker, err := cardinality.NewTracker(cardinality.NewOptions())
+	require.NoError(b, err)
+
+	queries := newTestWriteQueries(1024)
+	for _, bench := range []struct {
+		name  string
+		store Storage
+	}{
+		{name: "tracking=off", store: noopWriteStorage{}},
+		{name: "tracking=on", store: NewCardinalityTrackingStorage(noopWriteStorage{}, tracker)},
+	} {
+		store := bench.store
+		b.Run(bench.name, func(b *testing.B) {
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if err := store.Write(ctx, queries[i%len(queries)]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func newTestWriteQueries(n int) []*WriteQuery {
+	var (
+		now     = time.Now()
+		queries = make([]*WriteQuery, 0, n)
+	)
+	for i := 0; i < n; i++ {
+		tags := models.NewTags(3, models.NewTagOptions()).
+			AddTag(models.Tag{Name: []byte("__name__"), Value: []byte("requests")}).
+			AddTag(models.Tag{Name: []byte("host"), Value: []byte(fmt.Sprintf("host-%d", i%64))}).
+			AddTag(models.Tag{Name: []byte("request_id"), Value: []byte(fmt.Sprintf("%d", i))})
+		queries = append(queries, &WriteQuery{
+			Tags:       tags,
+			Datapoints: ts.Datapoints{{Timestamp: now, Value: float64(i)}},
+		})
+	}
+	return queries
+}
@@ -29,6 +29,8 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/errors"
 	"github.com/m3db/m3/src/query/models"
@@ -43,6 +45,9 @@ var (
 	errNoNamespacesConfigured = goerrors.New("no namespaces configured")
 )
 
+// streamBatchSize is the number of series passed to a stream fn at a time.
+const streamBatchSize = 128
+
 type queryFanoutType uint
 
 const (
@@ -201,6 +206,89 @@ func (s *m3storage) FetchCompressed(
 	return iters, result.Close, nil
 }
 
+func (s *m3storage) FetchCompressedStream(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+	fn FetchCompressedStreamFn,
+) error {
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	m3query, err := storage.FetchQueryToM3Query(query)
+	if err != nil {
+		return err
+	}
+
+	fanout, namespaces, err := s.resolveClusterNamespacesForQuery(query.Start, query.End)
+	if err != nil {
+		return err
+	}
+
+	if fanout != namespaceCoversAllQueryRange || len(namespaces) != 1 {
+		// NB: results from multiple namespaces need to be merged before they
+		// can be returned so they cannot be streamed.
+		iters, cleanup, err := s.FetchCompressed(ctx, query, options)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		return fn(iters)
+	}
+
+	var (
+		namespace = namespaces[0]
+		opts      = storage.FetchOptionsToM3Options(options, query)
+	)
+	stream, err := namespace.Session().FetchTaggedStream(namespace.NamespaceID(), m3query, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	batch := make([]encoding.SeriesIterator, 0, streamBatchSize)
+	flush := func() error {
+		iters := encoding.NewSeriesIterators(batch, nil)
+		err := fn(iters)
+		iters.Close()
+		batch = batch[:0]
+		return err
+	}
+
+	for stream.Next() {
+		batch = append(batch, stream.Current())
+		if len(batch) < streamBatchSize {
+			continue
+		}
+
+		// Check if the query was interrupted.
+		select {
+		case <-ctx.Done():
+			encoding.NewSeriesIterators(batch, nil).Close()
+			return ctx.Err()
+		default:
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		encoding.NewSeriesIterators(batch, nil).Close()
+		return err
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return flush()
+}
+
 func (s *m3storage) FetchTags(
 	ctx context.Context,
 	query *storage.FetchQuery,
@@ -228,11 +316,72 @@ func (s *m3storage) FetchTags(
 }
 
 func (s *m3storage) CompleteTags(
-	_ context.Context,
-	_ *storage.CompleteTagsQuery,
-	_ *storage.FetchOptions,
+	ctx context.Context,
+	query *storage.CompleteTagsQuery,
+	options *storage.FetchOptions,
 ) (*storage.CompleteTagsResult, error) {
-	return nil, errors.ErrNotImplemented
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	m3query := index.Query{Query: idx.NewAllQuery()}
+	if len(query.TagMatchers) > 0 {
+		var err error
+		m3query, err = storage.FetchQueryToM3Query(&storage.FetchQuery{
+			TagMatchers: query.TagMatchers,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// NB: tags are completed from the unaggregated namespace across its whole
+	// retention, every series written is indexed there.
+	var (
+		now       = s.nowFn()
+		namespace = s.clusters.UnaggregatedClusterNamespace()
+		retention = namespace.Options().Attributes().Retention
+		opts      = index.AggregateQueryOptions{
+			QueryOptions: index.QueryOptions{
+				StartInclusive: now.Add(-1 * retention),
+				EndExclusive:   now,
+			},
+			FieldFilter: query.FilterNameTags,
+			Type:        index.AggregateTagNamesAndValues,
+		}
+	)
+	if options != nil {
+		opts.Limit = options.Limit
+	}
+	if query.CompleteNameOnly {
+		opts.Type = index.AggregateTagNames
+	}
+
+	aggregated, err := namespace.Session().Aggregate(namespace.NamespaceID(), m3query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := aggregated.Results.Fields()
+	result := &storage.CompleteTagsResult{
+		CompleteNameOnly: query.CompleteNameOnly,
+		CompletedTags:    make([]storage.CompletedTag, 0, len(fields)),
+	}
+	for _, field := range fields {
+		tag := storage.CompletedTag{Name: field.Name}
+		if !query.CompleteNameOnly {
+			tag.Values = make([][]byte, 0, len(field.Values))
+			for _, value := range field.Values {
+				tag.Values = append(tag.Values, value.Value)
+			}
+		}
+		result.CompletedTags = append(result.CompletedTags, tag)
+	}
+
+	return result, nil
 }
 
 func (s *m3storage) SearchCompressed(
@@ -263,6 +412,17 @@ func (s *m3storage) SearchCompressed(
 		return nil, noop, errNoNamespacesConfigured
 	}
 
+	if options != nil && options.Namespace != "" {
+		// NB: namespaces which are not cluster namespaces, such as index only
+		// namespaces, are searched through the unaggregated namespace's session.
+		session := s.clusters.UnaggregatedClusterNamespace().Session()
+		iter, _, err := session.FetchTaggedIDs(ident.StringID(options.Namespace),
+			m3query, m3opts)
+		result.Add(iter, err)
+		tagResult, err := result.FinalResult()
+		return tagResult, result.Close, err
+	}
+
 	wg.Add(len(namespaces))
 	for _, namespace := range namespaces {
 		namespace := namespace // Capture var
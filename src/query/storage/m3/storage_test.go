@@ -30,6 +30,8 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/test/seriesiter"
@@ -271,6 +273,71 @@ func TestLocalRead(t *testing.T) {
 	assert.Equal(t, []byte("name"), results.SeriesList[0].Tags.Opts.MetricName())
 }
 
+func TestLocalFetchCompressedStream(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	var iters []encoding.SeriesIterator
+	for i := 0; i < streamBatchSize+1; i++ {
+		iter := encoding.NewMockSeriesIterator(ctrl)
+		iter.EXPECT().Close()
+		iters = append(iters, iter)
+	}
+
+	stream := client.NewMockSeriesIteratorStream(ctrl)
+	for _, iter := range iters {
+		stream.EXPECT().Next().Return(true)
+		stream.EXPECT().Current().Return(iter)
+	}
+	stream.EXPECT().Next().Return(false)
+	stream.EXPECT().Err().Return(nil)
+	stream.EXPECT().Close()
+
+	session := sessions.unaggregated1MonthRetention
+	session.EXPECT().FetchTaggedStream(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(stream, nil)
+
+	var batches [][]encoding.SeriesIterator
+	err := store.(Querier).FetchCompressedStream(context.TODO(), newFetchReq(),
+		&storage.FetchOptions{Limit: 1000}, func(batch encoding.SeriesIterators) error {
+			batches = append(batches, append([]encoding.SeriesIterator(nil), batch.Iters()...))
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(batches))
+	assert.Equal(t, iters[:streamBatchSize], batches[0])
+	assert.Equal(t, iters[streamBatchSize:], batches[1])
+}
+
+func TestLocalFetchCompressedStreamError(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	iter.EXPECT().Close()
+
+	streamErr := fmt.Errorf("an error")
+	stream := client.NewMockSeriesIteratorStream(ctrl)
+	stream.EXPECT().Next().Return(true)
+	stream.EXPECT().Current().Return(iter)
+	stream.EXPECT().Next().Return(false)
+	stream.EXPECT().Err().Return(streamErr)
+	stream.EXPECT().Close()
+
+	session := sessions.unaggregated1MonthRetention
+	session.EXPECT().FetchTaggedStream(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(stream, nil)
+
+	err := store.(Querier).FetchCompressedStream(context.TODO(), newFetchReq(),
+		&storage.FetchOptions{Limit: 1000}, func(batch encoding.SeriesIterators) error {
+			require.Fail(t, "no batches expected")
+			return nil
+		})
+	assert.Equal(t, streamErr, err)
+}
+
 func TestLocalReadExceedsRetention(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -552,6 +619,123 @@ func TestLocalSearchSuccess(t *testing.T) {
 	}
 }
 
+func TestLocalSearchNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	iter := client.NewMockTaggedIDsIterator(ctrl)
+	gomock.InOrder(
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(
+			ident.StringID("hosts"),
+			ident.StringID("foo"),
+			ident.NewTagsIterator(ident.NewTags(ident.StringTag("qux", "qaz"))),
+		),
+		iter.EXPECT().Next().Return(false),
+		iter.EXPECT().Err().Return(nil),
+		iter.EXPECT().Finalize(),
+	)
+
+	// Only the requested namespace is searched.
+	session := sessions.unaggregated1MonthRetention
+	session.EXPECT().FetchTaggedIDs(ident.NewIDMatcher("hosts"), gomock.Any(), gomock.Any()).
+		Return(iter, true, nil)
+	session.EXPECT().IteratorPools().Return(nil, nil).AnyTimes()
+
+	searchReq := newFetchReq()
+	result, err := store.FetchTags(context.TODO(), searchReq,
+		&storage.FetchOptions{Limit: 100, Namespace: "hosts"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Metrics))
+	assert.Equal(t, "foo", result.Metrics[0].ID)
+}
+
+func TestLocalCompleteTagsSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	aggregated := index.NewAggregateResults(0)
+	aggregated.AddFieldValue([]byte("foo"), []byte("bar"), 2)
+	aggregated.AddFieldValue([]byte("foo"), []byte("baz"), 1)
+	aggregated.AddFieldValue([]byte("qux"), []byte("quz"), 1)
+
+	sessions.unaggregated1MonthRetention.EXPECT().
+		Aggregate(ident.NewIDMatcher("metrics_unaggregated"), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ ident.ID,
+			_ index.Query,
+			opts index.AggregateQueryOptions,
+		) (index.AggregateQueryResults, error) {
+			assert.Equal(t, index.AggregateTagNamesAndValues, opts.Type)
+			assert.Equal(t, [][]byte{[]byte("foo"), []byte("qux")}, opts.FieldFilter)
+			assert.Equal(t, 100, opts.Limit)
+			return index.AggregateQueryResults{
+				Results:    aggregated,
+				Exhaustive: true,
+			}, nil
+		})
+
+	query := &storage.CompleteTagsQuery{
+		FilterNameTags: [][]byte{[]byte("foo"), []byte("qux")},
+		TagMatchers:    newFetchReq().TagMatchers,
+	}
+	result, err := store.CompleteTags(context.TODO(), query, &storage.FetchOptions{Limit: 100})
+	require.NoError(t, err)
+
+	assert.False(t, result.CompleteNameOnly)
+	assert.Equal(t, []storage.CompletedTag{
+		{Name: []byte("foo"), Values: [][]byte{[]byte("bar"), []byte("baz")}},
+		{Name: []byte("qux"), Values: [][]byte{[]byte("quz")}},
+	}, result.CompletedTags)
+}
+
+func TestLocalCompleteTagsNameOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	aggregated := index.NewAggregateResults(0)
+	aggregated.AddField([]byte("foo"), 3)
+
+	sessions.unaggregated1MonthRetention.EXPECT().
+		Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ ident.ID,
+			q index.Query,
+			opts index.AggregateQueryOptions,
+		) (index.AggregateQueryResults, error) {
+			assert.True(t, q.Equal(idx.NewAllQuery()))
+			assert.Equal(t, index.AggregateTagNames, opts.Type)
+			return index.AggregateQueryResults{
+				Results:    aggregated,
+				Exhaustive: true,
+			}, nil
+		})
+
+	query := &storage.CompleteTagsQuery{CompleteNameOnly: true}
+	result, err := store.CompleteTags(context.TODO(), query, storage.NewFetchOptions())
+	require.NoError(t, err)
+
+	assert.True(t, result.CompleteNameOnly)
+	assert.Equal(t, []storage.CompletedTag{{Name: []byte("foo")}}, result.CompletedTags)
+}
+
+func TestLocalCompleteTagsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	sessions.unaggregated1MonthRetention.EXPECT().
+		Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(index.AggregateQueryResults{}, fmt.Errorf("an error"))
+
+	query := &storage.CompleteTagsQuery{TagMatchers: newFetchReq().TagMatchers}
+	_, err := store.CompleteTags(context.TODO(), query, storage.NewFetchOptions())
+	assert.Error(t, err)
+}
+
 func newTestIteratorPools(ctrl *gomock.Controller) encoding.IteratorPools {
 	pools := encoding.NewMockIteratorPools(ctrl)
 
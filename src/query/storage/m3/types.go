@@ -50,6 +50,15 @@ type Querier interface {
 		query *genericstorage.FetchQuery,
 		options *genericstorage.FetchOptions,
 	) (encoding.SeriesIterators, Cleanup, error)
+	// FetchCompressedStream fetches timeseries data based on a query, calling
+	// fn with batches of series as they are received rather than buffering all
+	// results when the query can be served by a single namespace
+	FetchCompressedStream(
+		ctx context.Context,
+		query *genericstorage.FetchQuery,
+		options *genericstorage.FetchOptions,
+		fn FetchCompressedStreamFn,
+	) error
 	// SearchCompressed fetches matching tags based on a query
 	SearchCompressed(
 		ctx context.Context,
@@ -58,6 +67,10 @@ type Querier interface {
 	) ([]MultiTagResult, Cleanup, error)
 }
 
+// FetchCompressedStreamFn is called with each batch of series streamed by a
+// fetch, the series iterators are closed once it returns
+type FetchCompressedStreamFn func(iters encoding.SeriesIterators) error
+
 // MultiFetchResult is a deduping accumalator for series iterators
 // that allows merging using a given strategy
 type MultiFetchResult interface {
@@ -83,6 +83,12 @@ type FetchOptions struct {
 	// Limit is the maximum number of series to return.
 	Limit     int
 	UseLegacy bool
+	// Namespace, if set, restricts searches to the given namespace rather
+	// than the configured cluster namespaces, e.g. an index only namespace.
+	Namespace string
+	// Export streams the results of a remote fetch as they are received
+	// rather than buffering them, for exports too large to buffer.
+	Export bool
 }
 
 // NewFetchOptions creates a new fetch options.
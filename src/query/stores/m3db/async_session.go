@@ -114,6 +114,34 @@ func (s *AsyncSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterato
 	return s.session.WriteTagged(namespace, id, tags, t, value, unit, annotation)
 }
 
+// WriteTaggedAsync writes a value to the database for an ID and given tags
+// without waiting for the result
+func (s *AsyncSession) WriteTaggedAsync(namespace, id ident.ID, tags ident.TagIterator, t time.Time, value float64, unit xtime.Unit, annotation []byte, fn client.WriteCompletionFn) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		fn(s.err)
+		return
+	}
+
+	s.session.WriteTaggedAsync(namespace, id, tags, t, value, unit, annotation, fn)
+}
+
+// WriteBatch writes values to the database for a batch of IDs without
+// waiting for the results
+func (s *AsyncSession) WriteBatch(writes []client.WriteBatchElement, fn client.WriteBatchCompletionFn) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		for i := range writes {
+			fn(i, s.err)
+		}
+		return
+	}
+
+	s.session.WriteBatch(writes, fn)
+}
+
 // Fetch fetches values from the database for an ID
 func (s *AsyncSession) Fetch(namespace, id ident.ID, startInclusive, endExclusive time.Time) (encoding.SeriesIterator, error) {
 	s.RLock()
@@ -158,6 +186,52 @@ func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts in
 	return s.session.FetchTaggedIDs(namespace, q, opts)
 }
 
+// FetchTaggedPage resolves the provided query to known IDs, and fetches the data for them a page at a time
+func (s *AsyncSession) FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions, pageToken []byte) (encoding.SeriesIterators, []byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	return s.session.FetchTaggedPage(namespace, q, opts, pageToken)
+}
+
+// FetchTaggedIDsPage resolves the provided query to known IDs a page at a time.
+func (s *AsyncSession) FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions, pageToken []byte) (client.TaggedIDsIterator, []byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	return s.session.FetchTaggedIDsPage(namespace, q, opts, pageToken)
+}
+
+// FetchTaggedStream resolves the provided query to known IDs, and streams the
+// data for them a batch of shards at a time.
+func (s *AsyncSession) FetchTaggedStream(namespace ident.ID, q index.Query, opts index.QueryOptions) (client.SeriesIteratorStream, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.session.FetchTaggedStream(namespace, q, opts)
+}
+
+// Aggregate resolves the provided query to the distinct tag names, and
+// optionally tag values, of the series that match it.
+func (s *AsyncSession) Aggregate(namespace ident.ID, q index.Query, opts index.AggregateQueryOptions) (index.AggregateQueryResults, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return index.AggregateQueryResults{}, s.err
+	}
+
+	return s.session.Aggregate(namespace, q, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing
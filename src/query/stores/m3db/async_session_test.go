@@ -75,6 +75,18 @@ func TestAsyncSessionError(t *testing.T) {
 	err = asyncSession.WriteTagged(nil, nil, nil, time.Now(), 0, xtime.Second, nil)
 	assert.EqualError(t, err, expectedErrStr)
 
+	asyncSession.WriteTaggedAsync(nil, nil, nil, time.Now(), 0, xtime.Second, nil, func(err error) {
+		assert.EqualError(t, err, expectedErrStr)
+	})
+
+	var numErrs int
+	asyncSession.WriteBatch(make([]client.WriteBatchElement, 2), func(idx int, err error) {
+		assert.Equal(t, numErrs, idx)
+		assert.EqualError(t, err, expectedErrStr)
+		numErrs++
+	})
+	assert.Equal(t, 2, numErrs)
+
 	seriesIterator, err := asyncSession.Fetch(nil, nil, time.Now(), time.Now())
 	assert.Nil(t, seriesIterator)
 	assert.EqualError(t, err, expectedErrStr)
@@ -106,6 +118,10 @@ func TestAsyncSessionUninitialized(t *testing.T) {
 	_, _, err = asyncSession.FetchTaggedIDs(namespace, index.Query{}, index.QueryOptions{})
 	assert.Equal(t, err, errSessionUninitialized)
 
+	stream, err := asyncSession.FetchTaggedStream(namespace, index.Query{}, index.QueryOptions{})
+	assert.Nil(t, stream)
+	assert.Equal(t, err, errSessionUninitialized)
+
 	id, err := asyncSession.ShardID(nil)
 	assert.Equal(t, uint32(0), id)
 	assert.Equal(t, err, errSessionUninitialized)
@@ -138,6 +154,12 @@ func TestAsyncSessionInitialized(t *testing.T) {
 	err = asyncSession.WriteTagged(nil, nil, nil, time.Now(), 0, xtime.Second, nil)
 	assert.NoError(t, err)
 
+	mockSession.EXPECT().WriteTaggedAsync(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+	asyncSession.WriteTaggedAsync(nil, nil, nil, time.Now(), 0, xtime.Second, nil, func(err error) {})
+
+	mockSession.EXPECT().WriteBatch(gomock.Any(), gomock.Any())
+	asyncSession.WriteBatch(nil, func(idx int, err error) {})
+
 	mockSession.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
 	_, err = asyncSession.Fetch(nil, nil, time.Now(), time.Now())
 	assert.NoError(t, err)
@@ -154,6 +176,10 @@ func TestAsyncSessionInitialized(t *testing.T) {
 	_, _, err = asyncSession.FetchTaggedIDs(namespace, index.Query{}, index.QueryOptions{})
 	assert.NoError(t, err)
 
+	mockSession.EXPECT().FetchTaggedStream(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	_, err = asyncSession.FetchTaggedStream(namespace, index.Query{}, index.QueryOptions{})
+	assert.NoError(t, err)
+
 	mockSession.EXPECT().ShardID(gomock.Any()).Return(uint32(0), nil)
 	_, err = asyncSession.ShardID(nil)
 	assert.NoError(t, err)
@@ -124,6 +124,9 @@ func (c *grpcClient) fetchRaw(
 	if err != nil {
 		return nil, err
 	}
+	if options != nil {
+		request.Export = options.Export
+	}
 
 	// Send the id from the client to the remote server so that provides logging
 	// TODO: replace id propagation with opentracing
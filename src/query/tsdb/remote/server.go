@@ -21,6 +21,7 @@
 package remote
 
 import (
+	"context"
 	"net"
 	"sync"
 	"time"
@@ -99,6 +100,16 @@ func (s *grpcServer) Fetch(
 		return err
 	}
 
+	pools, err := s.waitForPools()
+	if err != nil {
+		logger.Error("unable to get pools", zap.Error(err))
+		return err
+	}
+
+	if message.GetExport() {
+		return s.fetchStream(ctx, storeQuery, pools, stream)
+	}
+
 	result, cleanup, err := s.storage.FetchCompressed(
 		ctx,
 		storeQuery,
@@ -110,12 +121,6 @@ func (s *grpcServer) Fetch(
 		return err
 	}
 
-	pools, err := s.waitForPools()
-	if err != nil {
-		logger.Error("unable to get pools", zap.Error(err))
-		return err
-	}
-
 	response, err := encodeToCompressedFetchResult(result, pools)
 	if err != nil {
 		logger.Error("unable to compress query", zap.Error(err))
@@ -130,6 +135,42 @@ func (s *grpcServer) Fetch(
 	return err
 }
 
+// fetchStream sends series to an export as they are received so that large
+// exports are not buffered in their entirety, streamed fetches are paginated
+// rather than retried as a whole.
+func (s *grpcServer) fetchStream(
+	ctx context.Context,
+	storeQuery *storage.FetchQuery,
+	pools encoding.IteratorPools,
+	stream rpc.Query_FetchServer,
+) error {
+	logger := logging.WithContext(ctx)
+	err := s.storage.FetchCompressedStream(
+		ctx,
+		storeQuery,
+		storage.NewFetchOptions(),
+		func(iters encoding.SeriesIterators) error {
+			response, err := encodeToCompressedFetchResult(iters, pools)
+			if err != nil {
+				logger.Error("unable to compress query", zap.Error(err))
+				return err
+			}
+
+			err = stream.Send(response)
+			if err != nil {
+				logger.Error("unable to send fetch result", zap.Error(err))
+			}
+
+			return err
+		},
+	)
+	if err != nil {
+		logger.Error("unable to fetch local query", zap.Error(err))
+	}
+
+	return err
+}
+
 func (s *grpcServer) Search(
 	message *rpc.SearchRequest,
 	stream rpc.Query_SearchServer,